@@ -23,6 +23,9 @@ import (
 type Agent struct {
 	comm client.Communicator
 	opts Options
+	// localOutputDir is set when the agent runs a task locally, in which
+	// case commands that use remote storage use this directory instead.
+	localOutputDir string
 }
 
 // Options contains startup options for the Agent.
@@ -35,11 +38,18 @@ type Options struct {
 	HeartbeatInterval  time.Duration
 	AgentSleepInterval time.Duration
 	Cleanup            bool
+	// SpotInterruptionURL is the instance metadata endpoint that gives
+	// notice that the cloud provider will reclaim the host's spot instance.
+	// If it is set, the agent polls it and reports the notice to the API
+	// server.
+	SpotInterruptionURL      string
+	SpotInterruptionInterval time.Duration
 }
 
 type taskContext struct {
 	currentCommand command.Command
 	logger         client.LoggerProducer
+	redactor       *logRedactor
 	statsCollector *StatsCollector
 	task           client.TaskData
 	taskGroup      string
@@ -48,6 +58,7 @@ type taskContext struct {
 	taskDirectory  string
 	timeout        time.Duration
 	timedOut       bool
+	resources      *resourceMonitor
 	sync.RWMutex
 }
 
@@ -74,6 +85,9 @@ func (a *Agent) Start(ctx context.Context) error {
 	if a.opts.Cleanup {
 		tryCleanupDirectory(a.opts.WorkingDirectory)
 	}
+	if a.opts.SpotInterruptionURL != "" {
+		go a.startSpotInterruptionWatch(ctx)
+	}
 	return errors.Wrap(a.loop(ctx), "error in agent loop, exiting")
 }
 
@@ -189,7 +203,8 @@ func nextTaskHasDifferentTaskGroupOrBuild(nextTask *apimodels.NextTaskResponse,
 }
 
 func (a *Agent) resetLogging(ctx context.Context, tc *taskContext) error {
-	tc.logger = a.comm.GetLoggerProducer(ctx, tc.task)
+	tc.redactor = newLogRedactor()
+	tc.logger = newRedactingLoggerProducer(a.comm.GetLoggerProducer(ctx, tc.task), tc.redactor)
 
 	sender, err := GetSender(ctx, a.opts.LogPrefix, tc.task.ID)
 	if err != nil {
@@ -298,6 +313,13 @@ func (a *Agent) runTaskTimeoutCommands(ctx context.Context, tc *taskContext) {
 
 // finishTask sends the returned EndTaskResponse and error
 func (a *Agent) finishTask(ctx context.Context, tc *taskContext, status string) (*apimodels.EndTaskResponse, error) {
+	// the task's cgroup is removed along with its processes when they are
+	// killed, but processes are left running if cleanup is disabled or the
+	// task group shares them, so the cgroup must not outlive the task
+	defer func() {
+		grip.Warning(a.releaseResourceLimits(tc))
+	}()
+
 	detail := a.endTaskResponse(tc, status)
 	switch detail.Status {
 	case evergreen.TaskSucceeded:
@@ -318,6 +340,11 @@ func (a *Agent) finishTask(ctx context.Context, tc *taskContext, status string)
 	if err := tc.logger.Close(); err != nil {
 		grip.Errorf("Error closing logger: %v", err)
 	}
+	detail.Redactions = tc.redactor.redactions()
+	if taskConfig := tc.getTaskConfig(); taskConfig != nil {
+		detail.Cache = taskConfig.GetCacheStats()
+	}
+	detail.ResourceUsage = tc.getResourceMonitor().commandUsage()
 	grip.Infof("Sending final status as: %v", detail.Status)
 	resp, err := a.comm.EndTask(ctx, detail, tc.task)
 	grip.Infof("Sent final status as: %v", detail.Status)
@@ -333,6 +360,7 @@ func (a *Agent) endTaskResponse(tc *taskContext, status string) *apimodels.TaskE
 		Description: tc.getCurrentCommand().DisplayName(),
 		Type:        tc.getCurrentCommand().Type(),
 		TimedOut:    tc.hadTimedOut(),
+		OOMKilled:   status == evergreen.TaskFailed && tc.getResourceMonitor().hadOOMKill(),
 		Status:      status,
 	}
 }
@@ -395,6 +423,9 @@ func (a *Agent) killProcs(tc *taskContext, ignoreTaskGroupCheck bool) {
 				msg := fmt.Sprintf("Error cleaning up spawned processes (agent-exit): %v", err)
 				grip.Critical(msg)
 			}
+			if err := a.removeResourceLimits(tc); err != nil {
+				grip.Warning(err)
+			}
 		}
 		grip.Infof("processes cleaned up for task %s", tc.task.ID)
 	}
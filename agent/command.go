@@ -22,7 +22,7 @@ func (a *Agent) runCommands(ctx context.Context, tc *taskContext, commands []mod
 			return errors.New("runCommands canceled")
 		}
 
-		cmds, err = command.Render(commandInfo, tc.taskConfig.Project.Functions)
+		cmds, err = a.renderCommands(commandInfo, tc.taskConfig.Project.Functions)
 		if err != nil {
 			tc.logger.Task().Errorf("Couldn't parse plugin command '%v': %v", commandInfo.Command, err)
 			if isTaskCommands {
@@ -76,6 +76,7 @@ func (a *Agent) runCommands(ctx context.Context, tc *taskContext, commands []mod
 			}
 
 			start := time.Now()
+			stopSampling := tc.getResourceMonitor().startCommand(fullCommandName)
 			// We have seen cases where calling exec.*Cmd.Wait() waits for too long if
 			// the process has called subprocesses. It will wait until a subprocess
 			// finishes, instead of returning immediately when the context is canceled.
@@ -92,13 +93,21 @@ func (a *Agent) runCommands(ctx context.Context, tc *taskContext, commands []mod
 			}()
 			select {
 			case err = <-cmdChan:
+				usage := stopSampling()
 				if err != nil {
 					tc.logger.Task().Errorf("Command failed: %v", err)
+					if usage.OOMKills > 0 {
+						tc.logger.Task().Errorf("Command had %d process(es) killed for exceeding the task's memory limit.", usage.OOMKills)
+					}
 					if isTaskCommands {
+						if usage.OOMKills > 0 {
+							tc.getResourceMonitor().setOOMKilled()
+						}
 						return errors.Wrap(err, "command failed")
 					}
 				}
 			case <-ctx.Done():
+				stopSampling()
 				tc.logger.Task().Errorf("Command canceled: %v", err)
 				return errors.Wrap(err, "command canceled")
 			}
@@ -135,6 +144,13 @@ func (a *Agent) runTaskCommands(ctx context.Context, tc *taskContext) error {
 	return nil
 }
 
+func (a *Agent) renderCommands(commandInfo model.PluginCommandConf, fns map[string]*model.YAMLCommandSet) ([]command.Command, error) {
+	if a.localOutputDir != "" {
+		return command.RenderLocal(commandInfo, fns)
+	}
+	return command.Render(commandInfo, fns)
+}
+
 func (a *Agent) getCommandName(commandInfo model.PluginCommandConf, cmd command.Command) string {
 	commandName := cmd.Name()
 	if commandInfo.Function != "" {
@@ -32,6 +32,11 @@ const (
 	// "timeout" command sets should be shut down.
 	defaultCallbackCmdTimeout = 15 * time.Minute
 
+	// defaultSpotInterruptionInterval is the interval at which the agent
+	// polls for notice that the host's spot instance will be reclaimed. EC2
+	// gives two minutes of notice.
+	defaultSpotInterruptionInterval = 5 * time.Second
+
 	// maxHeartbeats is the number of failed heartbeats after which an agent
 	// reports an error
 	maxHeartbeats = 10
@@ -0,0 +1,165 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/command"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/pkg/errors"
+)
+
+// LocalOptions describe a task to run locally with RunLocalTask.
+type LocalOptions struct {
+	// Project is the project configuration YAML.
+	Project []byte
+	// ProjectIdentifier is the identifier used for the project expansion.
+	ProjectIdentifier string
+	Variant           string
+	Task              string
+	// WorkingDirectory is the directory the task's commands run in.
+	WorkingDirectory string
+	// OutputDirectory is where data the task would send to the API server
+	// or to remote storage is written.
+	OutputDirectory string
+	// Expansions are added to the task's expansions, overriding the
+	// defaults and the variant's expansions.
+	Expansions map[string]string
+}
+
+// RunLocalTask runs a project task on the local machine, the same way the
+// agent runs it on a host: the task group's or project's setup commands run
+// first, then the task's commands, then the teardown commands. Commands that
+// would contact the API server or remote storage write to the output
+// directory instead. It returns the status of the task.
+func RunLocalTask(ctx context.Context, opts LocalOptions) (string, error) {
+	a := &Agent{
+		comm:           client.NewLocalCommunicator(opts.OutputDirectory),
+		localOutputDir: opts.OutputDirectory,
+	}
+	tc, err := a.newLocalTaskContext(ctx, opts)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	defer tc.logger.Close()
+
+	factory, ok := command.GetCommandFactory("setup.initial")
+	if !ok {
+		return "", errors.New("problem during configuring initial state")
+	}
+	tc.setCurrentCommand(factory())
+
+	a.runPreTaskCommands(ctx, tc)
+	status := evergreen.TaskSucceeded
+	if err = a.runTaskCommands(ctx, tc); err != nil {
+		status = evergreen.TaskFailed
+	}
+	a.runPostTaskCommands(ctx, tc)
+
+	if tc.taskGroup != "" {
+		taskGroup, err := model.GetTaskGroup(tc.taskGroup, tc.taskConfig)
+		if err != nil {
+			return status, errors.Wrap(err, "error fetching task group for post-group commands")
+		}
+		if taskGroup.TeardownGroup != nil {
+			tc.logger.Task().Info("Running post-group commands.")
+			groupCtx, cancel := a.withCallbackTimeout(ctx, tc)
+			defer cancel()
+			err = a.runCommands(groupCtx, tc, taskGroup.TeardownGroup.List(), false)
+			tc.logger.Task().ErrorWhenf(err != nil, "Error running post-group command: %v", err)
+		}
+	}
+
+	tc.logger.Task().Infof("Task completed - %s.", status)
+	return status, nil
+}
+
+func (a *Agent) newLocalTaskContext(ctx context.Context, opts LocalOptions) (*taskContext, error) {
+	if opts.Variant == "" || opts.Task == "" {
+		return nil, errors.New("a variant and a task are required")
+	}
+
+	identifier := opts.ProjectIdentifier
+	if identifier == "" {
+		identifier = "local"
+	}
+	project := &model.Project{}
+	if err := model.LoadProjectInto(opts.Project, identifier, project); err != nil {
+		return nil, errors.Wrap(err, "problem loading project")
+	}
+
+	bv := project.FindBuildVariant(opts.Variant)
+	if bv == nil {
+		return nil, errors.Errorf("build variant '%s' is not in the project", opts.Variant)
+	}
+	taskGroup, found := "", false
+	for _, unit := range bv.Tasks {
+		if unit.IsGroup {
+			tg := project.FindTaskGroup(unit.Name)
+			if tg == nil {
+				continue
+			}
+			for _, name := range tg.Tasks {
+				if name == opts.Task {
+					taskGroup, found = tg.Name, true
+				}
+			}
+		} else if unit.Name == opts.Task {
+			found = true
+		}
+		if found {
+			break
+		}
+	}
+	if !found {
+		return nil, errors.Errorf("task '%s' is not in build variant '%s'", opts.Task, opts.Variant)
+	}
+
+	workDir, err := filepath.Abs(opts.WorkingDirectory)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem resolving working directory '%s'", opts.WorkingDirectory)
+	}
+	if err = os.MkdirAll(workDir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "problem creating working directory '%s'", workDir)
+	}
+
+	t := &task.Task{
+		Id:           fmt.Sprintf("local_%s_%s", opts.Variant, opts.Task),
+		DisplayName:  opts.Task,
+		BuildVariant: opts.Variant,
+		Project:      identifier,
+		Version:      "local",
+		TaskGroup:    taskGroup,
+	}
+	d := &distro.Distro{Id: "localhost", WorkDir: workDir}
+	v := &version.Version{
+		Id:         "local",
+		Identifier: identifier,
+		Config:     string(opts.Project),
+		Requester:  evergreen.RepotrackerVersionRequester,
+	}
+	ref := &model.ProjectRef{Identifier: identifier}
+
+	conf, err := model.NewTaskConfig(d, v, project, t, ref, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem creating task configuration")
+	}
+	conf.Expansions.Update(opts.Expansions)
+
+	td := client.TaskData{ID: t.Id}
+	return &taskContext{
+		logger:        a.comm.GetLoggerProducer(ctx, td),
+		task:          td,
+		taskGroup:     taskGroup,
+		runGroupSetup: true,
+		taskConfig:    conf,
+		taskDirectory: workDir,
+	}, nil
+}
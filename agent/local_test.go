@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const localTestProject = `
+functions:
+  write:
+    command: shell.exec
+    params:
+      script: echo -n "${greeting} from ${task_name}" > ${file}
+
+task_groups:
+- name: group
+  setup_group:
+  - command: shell.exec
+    params:
+      script: echo -n setup > setup_group.txt
+  teardown_group:
+  - command: shell.exec
+    params:
+      script: echo -n teardown > teardown_group.txt
+  tasks:
+  - grouped
+
+tasks:
+- name: upload
+  commands:
+  - func: write
+    vars:
+      file: hello.txt
+  - command: s3.put
+    params:
+      aws_key: key
+      aws_secret: secret
+      bucket: bucket
+      local_file: hello.txt
+      remote_file: ${task_name}/hello.txt
+      content_type: text/plain
+      permissions: private
+  - command: s3.get
+    params:
+      aws_key: key
+      aws_secret: secret
+      bucket: bucket
+      remote_file: ${task_name}/hello.txt
+      local_file: copy.txt
+- name: grouped
+  commands:
+  - command: shell.exec
+    params:
+      script: exit 1
+- name: other
+
+buildvariants:
+- name: linux
+  expansions:
+    greeting: hi
+  tasks:
+  - name: upload
+  - name: group
+`
+
+func TestRunLocalTask(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir, err := ioutil.TempDir("", "run-local-task")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	opts := LocalOptions{
+		Project:          []byte(localTestProject),
+		Variant:          "linux",
+		Task:             "upload",
+		WorkingDirectory: filepath.Join(dir, "work"),
+		OutputDirectory:  filepath.Join(dir, "output"),
+		Expansions:       map[string]string{"greeting": "hello"},
+	}
+
+	status, err := RunLocalTask(ctx, opts)
+	require.NoError(err)
+	assert.Equal(evergreen.TaskSucceeded, status)
+	out, err := ioutil.ReadFile(filepath.Join(dir, "work", "copy.txt"))
+	require.NoError(err)
+	assert.Equal("hello from upload", string(out))
+	assertFileExists(t, filepath.Join(dir, "output", "s3", "bucket", "upload", "hello.txt"))
+	assertFileExists(t, filepath.Join(dir, "output", "artifacts.json"))
+
+	opts.Task = "grouped"
+	status, err = RunLocalTask(ctx, opts)
+	require.NoError(err)
+	assert.Equal(evergreen.TaskFailed, status)
+	assertFileExists(t, filepath.Join(dir, "work", "setup_group.txt"))
+	assertFileExists(t, filepath.Join(dir, "work", "teardown_group.txt"))
+
+	opts.Task = "other"
+	_, err = RunLocalTask(ctx, opts)
+	assert.Error(err)
+	opts.Variant = "windows"
+	_, err = RunLocalTask(ctx, opts)
+	assert.Error(err)
+}
+
+func assertFileExists(t *testing.T, path string) {
+	exists, err := util.FileExists(path)
+	assert.NoError(t, err)
+	assert.True(t, exists, "expected '%s' to exist", path)
+}
@@ -0,0 +1,203 @@
+package agent
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/level"
+	"github.com/mongodb/grip/logging"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/send"
+	"github.com/pkg/errors"
+)
+
+// minRedactedLength is the length below which private values are not
+// redacted, since replacing every occurrence of a very short string would
+// make logs unreadable without protecting anything.
+const minRedactedLength = 3
+
+type redaction struct {
+	value       string
+	placeholder string
+}
+
+// logRedactor replaces the values of private expansions, and their common
+// encodings, in log messages, and counts how many it has replaced.
+type logRedactor struct {
+	mu           sync.RWMutex
+	replacements []redaction
+	count        int
+}
+
+func newLogRedactor() *logRedactor { return &logRedactor{} }
+
+// setPrivateVars sets the values to redact to those of the private
+// variables.
+func (r *logRedactor) setPrivateVars(vars map[string]string, private map[string]bool) {
+	if r == nil {
+		return
+	}
+
+	seen := map[string]bool{}
+	redactions := []redaction{}
+	for name := range private {
+		value := vars[name]
+		if len(value) < minRedactedLength {
+			continue
+		}
+
+		placeholder := fmt.Sprintf("<REDACTED:%s>", name)
+		candidates := []string{
+			value,
+			base64.StdEncoding.EncodeToString([]byte(value)),
+			base64.RawStdEncoding.EncodeToString([]byte(value)),
+			base64.URLEncoding.EncodeToString([]byte(value)),
+			base64.RawURLEncoding.EncodeToString([]byte(value)),
+			url.QueryEscape(value),
+			url.PathEscape(value),
+		}
+		// Writers split output on newlines before it reaches the sender,
+		// so each line of a multi-line value, such as a key file, must be
+		// redacted on its own as well.
+		if strings.Contains(value, "\n") {
+			for _, line := range strings.Split(value, "\n") {
+				if line = strings.TrimSpace(line); len(line) >= minRedactedLength {
+					candidates = append(candidates, line)
+				}
+			}
+		}
+		for _, encoded := range candidates {
+			if seen[encoded] {
+				continue
+			}
+			seen[encoded] = true
+			redactions = append(redactions, redaction{value: encoded, placeholder: placeholder})
+		}
+	}
+	// Replace longer values first so that a value that contains another
+	// is redacted as a whole.
+	sort.SliceStable(redactions, func(i, j int) bool {
+		return len(redactions[i].value) > len(redactions[j].value)
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.replacements = redactions
+}
+
+// redact returns the string with private values replaced.
+func (r *logRedactor) redact(s string) string {
+	r.mu.RLock()
+	redactions := r.replacements
+	r.mu.RUnlock()
+
+	replaced := 0
+	for _, red := range redactions {
+		if n := strings.Count(s, red.value); n > 0 {
+			s = strings.Replace(s, red.value, red.placeholder, -1)
+			replaced += n
+		}
+	}
+
+	if replaced > 0 {
+		r.mu.Lock()
+		r.count += replaced
+		r.mu.Unlock()
+	}
+	return s
+}
+
+// redactions returns the number of values replaced so far.
+func (r *logRedactor) redactions() int {
+	if r == nil {
+		return 0
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.count
+}
+
+// redactingSender wraps a sender, redacting messages before sending them.
+type redactingSender struct {
+	redactor *logRedactor
+	send.Sender
+}
+
+func newRedactingSender(sender send.Sender, r *logRedactor) send.Sender {
+	return &redactingSender{redactor: r, Sender: sender}
+}
+
+func (s *redactingSender) Send(m message.Composer) {
+	if !s.Level().ShouldLog(m) {
+		return
+	}
+
+	original := m.String()
+	if redacted := s.redactor.redact(original); redacted != original {
+		m = message.NewDefaultMessage(m.Priority(), redacted)
+	}
+	s.Sender.Send(m)
+}
+
+// redactingLoggerProducer wraps each channel of a LoggerProducer so that
+// private values never reach the logs.
+type redactingLoggerProducer struct {
+	producer  client.LoggerProducer
+	execution grip.Journaler
+	task      grip.Journaler
+	system    grip.Journaler
+	mu        sync.Mutex
+	writers   []io.WriteCloser
+}
+
+func newRedactingLoggerProducer(producer client.LoggerProducer, r *logRedactor) client.LoggerProducer {
+	return &redactingLoggerProducer{
+		producer:  producer,
+		execution: logging.MakeGrip(newRedactingSender(producer.Execution().GetSender(), r)),
+		task:      logging.MakeGrip(newRedactingSender(producer.Task().GetSender(), r)),
+		system:    logging.MakeGrip(newRedactingSender(producer.System().GetSender(), r)),
+	}
+}
+
+func (l *redactingLoggerProducer) Execution() grip.Journaler { return l.execution }
+func (l *redactingLoggerProducer) Task() grip.Journaler      { return l.task }
+func (l *redactingLoggerProducer) System() grip.Journaler    { return l.system }
+
+func (l *redactingLoggerProducer) TaskWriter(p level.Priority) io.WriteCloser {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	w := send.MakeWriterSender(l.task.GetSender(), p)
+	l.writers = append(l.writers, w)
+	return w
+}
+
+func (l *redactingLoggerProducer) SystemWriter(p level.Priority) io.WriteCloser {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	w := send.MakeWriterSender(l.system.GetSender(), p)
+	l.writers = append(l.writers, w)
+	return w
+}
+
+func (l *redactingLoggerProducer) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	catcher := grip.NewBasicCatcher()
+	for _, w := range l.writers {
+		catcher.Add(w.Close())
+	}
+	catcher.Add(l.producer.Close())
+
+	return errors.Wrap(catcher.Resolve(), "problem closing redacting log producer")
+}
@@ -0,0 +1,108 @@
+package agent
+
+import (
+	"encoding/base64"
+	"net/url"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/mongodb/grip/level"
+	"github.com/mongodb/grip/send"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogRedactor(t *testing.T) {
+	assert := assert.New(t)
+
+	r := newLogRedactor()
+	assert.Equal("password hunter2", r.redact("password hunter2"))
+
+	r.setPrivateVars(map[string]string{
+		"password": "hunter2",
+		"token":    "a b/c",
+		"short":    "ab",
+		"public":   "visible",
+	}, map[string]bool{
+		"password": true,
+		"token":    true,
+		"short":    true,
+	})
+
+	assert.Equal("password <REDACTED:password>", r.redact("password hunter2"))
+	assert.Equal(1, r.redactions())
+
+	encoded := base64.StdEncoding.EncodeToString([]byte("hunter2"))
+	assert.Equal("auth <REDACTED:password> <REDACTED:password>", r.redact("auth "+encoded+" hunter2"))
+	assert.Equal(3, r.redactions())
+
+	assert.Equal("?t=<REDACTED:token>", r.redact("?t="+url.QueryEscape("a b/c")))
+	assert.Equal("/<REDACTED:token>", r.redact("/"+url.PathEscape("a b/c")))
+	assert.Equal(5, r.redactions())
+
+	assert.Equal("ab visible", r.redact("ab visible"), "short and public values should not be redacted")
+	assert.Equal(5, r.redactions())
+
+	var nilRedactor *logRedactor
+	nilRedactor.setPrivateVars(map[string]string{"a": "hunter2"}, map[string]bool{"a": true})
+	assert.Zero(nilRedactor.redactions())
+}
+
+func TestRedactingLoggerProducer(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sender := send.MakeInternalLogger()
+	r := newLogRedactor()
+	logger := newRedactingLoggerProducer(client.NewSingleChannelLogHarness("test", sender), r)
+
+	logger.Task().Info("before hunter2")
+	r.setPrivateVars(map[string]string{"password": "hunter2"}, map[string]bool{"password": true})
+	logger.Execution().Info("after hunter2")
+
+	w := logger.TaskWriter(level.Info)
+	_, err := w.Write([]byte("echo hunter2\n"))
+	require.NoError(err)
+	require.NoError(w.Close())
+
+	msgs := []string{}
+	for sender.HasMessage() {
+		msgs = append(msgs, sender.GetMessage().Rendered)
+	}
+	require.Len(msgs, 3)
+	assert.Contains(msgs[0], "before hunter2")
+	assert.Contains(msgs[1], "after <REDACTED:password>")
+	assert.Contains(msgs[2], "echo <REDACTED:password>")
+	assert.Equal(2, r.redactions())
+
+	assert.NoError(logger.Close())
+}
+
+func TestRedactingLoggerProducerMultiLineSecret(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	key := "-----BEGIN KEY-----\nMIIEpAIBAAKCAQEA\r\n  b3BlbnNzaC1rZXk  \nab\n-----END KEY-----\n"
+
+	sender := send.MakeInternalLogger()
+	r := newLogRedactor()
+	r.setPrivateVars(map[string]string{"key": key}, map[string]bool{"key": true})
+	logger := newRedactingLoggerProducer(client.NewSingleChannelLogHarness("test", sender), r)
+
+	w := logger.TaskWriter(level.Info)
+	_, err := w.Write([]byte("cat id_rsa\n" + key))
+	require.NoError(err)
+	require.NoError(w.Close())
+
+	output := ""
+	for sender.HasMessage() {
+		output += sender.GetMessage().Rendered + "\n"
+	}
+	assert.Contains(output, "cat id_rsa")
+	assert.Contains(output, "<REDACTED:key>")
+	for _, line := range []string{"BEGIN KEY", "MIIEpAIBAAKCAQEA", "b3BlbnNzaC1rZXk", "END KEY"} {
+		assert.NotContains(output, line)
+	}
+
+	assert.NoError(logger.Close())
+}
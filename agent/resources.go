@@ -0,0 +1,160 @@
+package agent
+
+import (
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/subprocess"
+	"github.com/pkg/errors"
+)
+
+// resourceSampleInterval is how often the agent samples the memory and
+// processes used by a command that runs in the task's cgroup.
+const resourceSampleInterval = time.Second
+
+// resourceMonitor records the resources used by each command of a task whose
+// processes run in a cgroup.
+type resourceMonitor struct {
+	key       string
+	usage     []apimodels.CommandResourceUsage
+	oomKilled bool
+	mu        sync.Mutex
+}
+
+// setupResourceLimits creates a cgroup for the task's processes if the distro
+// or the task set resource limits. Tasks whose limits cannot be enforced,
+// such as tasks on hosts without cgroups, run without them.
+func (a *Agent) setupResourceLimits(tc *taskContext) {
+	limits := tc.getTaskConfig().GetResourceLimits()
+	if limits.IsZero() {
+		return
+	}
+
+	err := subprocess.CreateCgroup(tc.task.ID, subprocess.CgroupLimits{
+		MemoryBytes: int64(limits.MemoryMB) * 1024 * 1024,
+		CPUs:        limits.CPUs,
+		Processes:   limits.Processes,
+	})
+	if err != nil {
+		tc.logger.Execution().Warningf("Not enforcing resource limits: %v", err)
+		return
+	}
+
+	tc.logger.Execution().Infof("Enforcing resource limits (memory: %d MB, cpus: %g, processes: %d).",
+		limits.MemoryMB, limits.CPUs, limits.Processes)
+	tc.setResourceMonitor(&resourceMonitor{key: tc.task.ID})
+}
+
+// startCommand samples the resources used by the task's cgroup while a
+// command runs. The returned function stops sampling, records the command's
+// usage, and returns it.
+func (m *resourceMonitor) startCommand(command string) func() apimodels.CommandResourceUsage {
+	noop := func() apimodels.CommandResourceUsage { return apimodels.CommandResourceUsage{} }
+	if m == nil {
+		return noop
+	}
+	start, err := subprocess.GetCgroupUsage(m.key)
+	if err != nil {
+		// the cgroup is removed when the task's processes are killed, so
+		// commands that run afterward, such as post-task commands, are
+		// not sampled
+		return noop
+	}
+
+	usage := apimodels.CommandResourceUsage{Command: command}
+	sample := func() subprocess.CgroupUsage {
+		current, err := subprocess.GetCgroupUsage(m.key)
+		if err != nil {
+			return start
+		}
+		if current.MemoryBytes > usage.PeakMemoryBytes {
+			usage.PeakMemoryBytes = current.MemoryBytes
+		}
+		if current.Processes > usage.PeakProcesses {
+			usage.PeakProcesses = current.Processes
+		}
+		return current
+	}
+
+	done := make(chan struct{})
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(resourceSampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				sample()
+			}
+		}
+	}()
+
+	return func() apimodels.CommandResourceUsage {
+		close(done)
+		wg.Wait()
+
+		end := sample()
+		usage.CPUSeconds = (end.CPUTime - start.CPUTime).Seconds()
+		usage.OOMKills = end.OOMKills - start.OOMKills
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.usage = append(m.usage, usage)
+		return usage
+	}
+}
+
+// setOOMKilled records that the task failed because of a process killed for
+// exceeding the task's memory limit.
+func (m *resourceMonitor) setOOMKilled() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.oomKilled = true
+}
+
+func (m *resourceMonitor) hadOOMKill() bool {
+	if m == nil {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.oomKilled
+}
+
+// commandUsage returns the usage of each command that ran in the task's
+// cgroup.
+func (m *resourceMonitor) commandUsage() []apimodels.CommandResourceUsage {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]apimodels.CommandResourceUsage{}, m.usage...)
+}
+
+// removeResourceLimits kills any processes left in the task's cgroup and
+// removes it.
+func (a *Agent) removeResourceLimits(tc *taskContext) error {
+	if tc.getResourceMonitor() == nil {
+		return nil
+	}
+	return errors.Wrap(subprocess.RemoveCgroup(tc.task.ID), "problem removing task's cgroup")
+}
+
+// releaseResourceLimits removes the task's cgroup without killing the
+// processes left in it, which keep running without limits. It is a noop if
+// the task's processes were already killed and its cgroup removed.
+func (a *Agent) releaseResourceLimits(tc *taskContext) error {
+	if tc.getResourceMonitor() == nil {
+		return nil
+	}
+	return errors.Wrap(subprocess.ReleaseCgroup(tc.task.ID), "problem releasing task's cgroup")
+}
@@ -0,0 +1,27 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceMonitor(t *testing.T) {
+	assert := assert.New(t)
+
+	// tasks without resource limits have no monitor
+	var monitor *resourceMonitor
+	assert.Equal(apimodels.CommandResourceUsage{}, monitor.startCommand("'shell.exec'")())
+	monitor.setOOMKilled()
+	assert.False(monitor.hadOOMKill())
+	assert.Nil(monitor.commandUsage())
+
+	// commands that run after the task's cgroup is removed are not sampled
+	monitor = &resourceMonitor{key: "no-cgroup"}
+	assert.Equal(apimodels.CommandResourceUsage{}, monitor.startCommand("'shell.exec'")())
+	assert.Empty(monitor.commandUsage())
+
+	monitor.setOOMKilled()
+	assert.True(monitor.hadOOMKill())
+}
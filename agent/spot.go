@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/recovery"
+	"github.com/pkg/errors"
+)
+
+// startSpotInterruptionWatch polls the instance metadata for notice that the
+// cloud provider will reclaim the host's spot instance, and reports the notice
+// to the API server, which stops dispatching tasks to the host and resets its
+// running task.
+func (a *Agent) startSpotInterruptionWatch(ctx context.Context) {
+	defer recovery.LogStackTraceAndContinue("spot interruption watcher")
+	interval := defaultSpotInterruptionInterval
+	if a.opts.SpotInterruptionInterval != 0 {
+		interval = a.opts.SpotInterruptionInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			grip.Info("Spot interruption watcher canceled")
+			return
+		case <-ticker.C:
+			notice, err := getSpotInterruptionNotice(ctx, a.opts.SpotInterruptionURL)
+			if err != nil {
+				grip.Debug(message.WrapError(err, message.Fields{
+					"message": "problem checking for spot interruption notice",
+					"url":     a.opts.SpotInterruptionURL,
+				}))
+				continue
+			}
+			if notice == nil {
+				continue
+			}
+
+			grip.Warning(message.Fields{
+				"message": "spot instance will be reclaimed",
+				"action":  notice.Action,
+				"time":    notice.Time,
+			})
+			// keep trying until the API server has the notice
+			if err = a.comm.ReportSpotInterruption(ctx, *notice); err != nil {
+				grip.Error(message.WrapError(err, "problem reporting spot interruption"))
+				continue
+			}
+			return
+		}
+	}
+}
+
+// getSpotInterruptionNotice returns the spot interruption notice from the
+// instance metadata, or nil if there is none.
+func getSpotInterruptionNotice(ctx context.Context, url string) (*apimodels.SpotInterruptionNotice, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem building request")
+	}
+	req = req.WithContext(ctx)
+
+	client := util.GetHTTPClient()
+	defer util.PutHTTPClient(client)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem requesting spot interruption notice")
+	}
+	defer resp.Body.Close()
+
+	// the endpoint is not found until there is a notice
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status %d requesting spot interruption notice", resp.StatusCode)
+	}
+
+	notice := &apimodels.SpotInterruptionNotice{}
+	if err = util.ReadJSONInto(resp.Body, notice); err != nil {
+		return nil, errors.Wrap(err, "problem reading spot interruption notice")
+	}
+	return notice, nil
+}
@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSpotInterruptionNotice(t *testing.T) {
+	assert := assert.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	status := http.StatusNotFound
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			_, _ = w.Write([]byte(`{"action": "terminate", "time": "2019-01-07T08:45:00Z"}`))
+		}
+	}))
+	defer server.Close()
+
+	notice, err := getSpotInterruptionNotice(ctx, server.URL)
+	assert.NoError(err)
+	assert.Nil(notice)
+
+	status = http.StatusOK
+	notice, err = getSpotInterruptionNotice(ctx, server.URL)
+	assert.NoError(err)
+	if assert.NotNil(notice) {
+		assert.Equal("terminate", notice.Action)
+		assert.True(time.Date(2019, time.January, 7, 8, 45, 0, 0, time.UTC).Equal(notice.Time))
+	}
+
+	status = http.StatusInternalServerError
+	notice, err = getSpotInterruptionNotice(ctx, server.URL)
+	assert.Error(err)
+	assert.Nil(notice)
+}
+
+func TestStartSpotInterruptionWatch(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// the notice appears on the third poll
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&polls, 1) < 3 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"action": "stop", "time": "2019-01-07T08:45:00Z"}`))
+	}))
+	defer server.Close()
+
+	comm := client.NewMock("url")
+	a := &Agent{
+		opts: Options{
+			SpotInterruptionURL:      server.URL,
+			SpotInterruptionInterval: time.Millisecond,
+		},
+		comm: comm,
+	}
+
+	// the watch returns once it reports the notice
+	a.startSpotInterruptionWatch(ctx)
+	require.NoError(ctx.Err())
+	require.Len(comm.SpotInterruptions, 1)
+	assert.Equal("stop", comm.SpotInterruptions[0].Action)
+	assert.Equal(int32(3), atomic.LoadInt32(&polls))
+}
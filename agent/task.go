@@ -92,6 +92,7 @@ func (a *Agent) startTask(ctx context.Context, tc *taskContext, complete chan<-
 	}
 	taskConfig.Expansions.Update(expVars.Vars)
 	taskConfig.Redacted = expVars.PrivateVars
+	tc.redactor.setPrivateVars(expVars.Vars, expVars.PrivateVars)
 	tc.setTaskConfig(taskConfig)
 
 	// set up the system stats collector
@@ -128,6 +129,7 @@ func (a *Agent) startTask(ctx context.Context, tc *taskContext, complete chan<-
 	}
 
 	a.killProcs(tc, false)
+	a.setupResourceLimits(tc)
 	a.runPreTaskCommands(innerCtx, tc)
 
 	if err = a.runTaskCommands(innerCtx, tc); err != nil {
@@ -305,3 +307,15 @@ func (tc *taskContext) getTaskConfig() *model.TaskConfig {
 	defer tc.RUnlock()
 	return tc.taskConfig
 }
+
+func (tc *taskContext) setResourceMonitor(monitor *resourceMonitor) {
+	tc.Lock()
+	defer tc.Unlock()
+	tc.resources = monitor
+}
+
+func (tc *taskContext) getResourceMonitor() *resourceMonitor {
+	tc.RLock()
+	defer tc.RUnlock()
+	return tc.resources
+}
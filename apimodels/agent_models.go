@@ -2,6 +2,7 @@ package apimodels
 
 import (
 	"errors"
+	"time"
 
 	"github.com/mongodb/grip"
 )
@@ -33,6 +34,35 @@ type TaskEndDetail struct {
 	Type        string `bson:"type,omitempty" json:"type,omitempty"`
 	Description string `bson:"desc,omitempty" json:"desc,omitempty"`
 	TimedOut    bool   `bson:"timed_out,omitempty" json:"timed_out,omitempty"`
+	// Redactions is the number of private values the agent redacted from
+	// the task's logs.
+	Redactions int `bson:"redactions,omitempty" json:"redactions,omitempty"`
+	// Cache summarizes the task's use of the build cache, if it used it.
+	Cache *CacheStats `bson:"cache,omitempty" json:"cache,omitempty"`
+	// OOMKilled is true if the task failed because one of its processes
+	// was killed for exceeding the task's memory limit.
+	OOMKilled bool `bson:"oom_killed,omitempty" json:"oom_killed,omitempty"`
+	// ResourceUsage is the peak resource usage of each command that ran
+	// while the task had resource limits.
+	ResourceUsage []CommandResourceUsage `bson:"resource_usage,omitempty" json:"resource_usage,omitempty"`
+}
+
+// CacheStats counts the caches a task restored and saved.
+type CacheStats struct {
+	Hits          int   `bson:"hits" json:"hits"`
+	Misses        int   `bson:"misses" json:"misses"`
+	Saves         int   `bson:"saves" json:"saves"`
+	BytesRestored int64 `bson:"bytes_restored" json:"bytes_restored"`
+	BytesSaved    int64 `bson:"bytes_saved" json:"bytes_saved"`
+}
+
+// CommandResourceUsage is the resources used by the processes of a command.
+type CommandResourceUsage struct {
+	Command         string  `bson:"command" json:"command"`
+	PeakMemoryBytes int64   `bson:"peak_memory_bytes" json:"peak_memory_bytes"`
+	CPUSeconds      float64 `bson:"cpu_seconds" json:"cpu_seconds"`
+	PeakProcesses   int     `bson:"peak_processes" json:"peak_processes"`
+	OOMKills        int     `bson:"oom_kills,omitempty" json:"oom_kills,omitempty"`
 }
 
 type TaskEndDetails struct {
@@ -68,6 +98,16 @@ type NextTaskResponse struct {
 	NewAgent bool `json:"new_agent,omitempty"`
 }
 
+// SpotInterruptionNotice is sent by the agent when the cloud provider gives
+// notice that it will reclaim the host's spot instance. Its fields match the
+// EC2 instance metadata's instance-action document.
+type SpotInterruptionNotice struct {
+	// Action is what will happen to the instance, e.g. "terminate" or "stop".
+	Action string `json:"action"`
+	// Time is when the action will happen.
+	Time time.Time `json:"time"`
+}
+
 // EndTaskResponse is what is returned when the task ends
 type EndTaskResponse struct {
 	ShouldExit bool `json:"should_exit,omitempty"`
@@ -6,6 +6,7 @@ package apimodels
 type S3CopyRequest struct {
 	AwsKey              string `json:"aws_key"`
 	AwsSecret           string `json:"aws_secret"`
+	Provider            string `json:"provider,omitempty"`
 	S3SourceBucket      string `json:"s3_source_bucket"`
 	S3SourcePath        string `json:"s3_source_path"`
 	S3DestinationBucket string `json:"s3_destination_bucket"`
@@ -10,10 +10,28 @@ import (
 	"github.com/pkg/errors"
 )
 
-//LoadUserManager is used to check the configuration for authentication and create a UserManager depending on what type of authentication (Crowd or Naive) is used.
+//LoadUserManager is used to check the configuration for authentication and create a UserManager depending on what type of authentication (Crowd, Naive, GitHub or OIDC) is used.
+// OIDC may be combined with Naive, in which case the Naive users are accepted as well.
 func LoadUserManager(authConfig evergreen.AuthConfig) (gimlet.UserManager, error) {
 	var manager gimlet.UserManager
 	var err error
+	if authConfig.OIDC != nil {
+		if authConfig.Crowd != nil || authConfig.Github != nil {
+			return nil, errors.New("Cannot combine OIDC authentication with forms other than Naive")
+		}
+		oidc, err := NewOIDCUserManager(authConfig.OIDC)
+		if err != nil {
+			return nil, err
+		}
+		if authConfig.Naive == nil {
+			return oidc, nil
+		}
+		naive, err := NewNaiveUserManager(authConfig.Naive)
+		if err != nil {
+			return nil, err
+		}
+		return NewChainUserManager(oidc, naive), nil
+	}
 	if authConfig.Crowd != nil {
 		manager, err = NewCrowdUserManager(authConfig.Crowd)
 		if err != nil {
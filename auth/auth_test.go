@@ -48,6 +48,26 @@ func TestLoadUserManager(t *testing.T) {
 			So(um, ShouldNotBeNil)
 		})
 
+		Convey("a UserManager should be able to be created if the AuthConfig types are OIDC and Naive", func() {
+			o := evergreen.OIDCAuthConfig{Issuer: "https://idp.example.com", ClientId: "client", ClientSecret: "secret"}
+			a := evergreen.AuthConfig{
+				Naive: &n,
+				OIDC:  &o}
+			um, err := LoadUserManager(a)
+			So(err, ShouldBeNil)
+			So(um, ShouldHaveSameTypeAs, &ChainUserManager{})
+
+			a.Naive = nil
+			um, err = LoadUserManager(a)
+			So(err, ShouldBeNil)
+			So(um, ShouldHaveSameTypeAs, &OIDCUserManager{})
+
+			a.Crowd = &c
+			um, err = LoadUserManager(a)
+			So(err, ShouldNotBeNil)
+			So(um, ShouldBeNil)
+		})
+
 		Convey("a UserManager should be able to be created if one AuthConfig type is Naive", func() {
 			a := evergreen.AuthConfig{
 				Crowd:  nil,
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+// ChainUserManager implements the UserManager by consulting several user
+// managers in order. Tokens are accepted if any manager accepts them, while
+// the login flow and user persistence belong to the primary manager. This
+// allows, for example, service accounts configured for the Naive manager to
+// authenticate alongside users of a redirecting identity provider.
+type ChainUserManager struct {
+	primary gimlet.UserManager
+	others  []gimlet.UserManager
+}
+
+// NewChainUserManager returns a ChainUserManager that tries the primary
+// manager first, followed by the others.
+func NewChainUserManager(primary gimlet.UserManager, others ...gimlet.UserManager) *ChainUserManager {
+	return &ChainUserManager{primary: primary, others: others}
+}
+
+func (c *ChainUserManager) managers() []gimlet.UserManager {
+	return append([]gimlet.UserManager{c.primary}, c.others...)
+}
+
+// GetUserByToken returns the user from the first manager that accepts the
+// token.
+func (c *ChainUserManager) GetUserByToken(ctx context.Context, token string) (gimlet.User, error) {
+	catcher := grip.NewSimpleCatcher()
+	for _, m := range c.managers() {
+		u, err := m.GetUserByToken(ctx, token)
+		if err == nil && u != nil {
+			return u, nil
+		}
+		catcher.Add(err)
+	}
+
+	return nil, errors.Wrap(catcher.Resolve(), "No valid user found")
+}
+
+// CreateUserToken returns a token from the first manager that accepts the
+// username and password.
+func (c *ChainUserManager) CreateUserToken(username, password string) (string, error) {
+	catcher := grip.NewSimpleCatcher()
+	for _, m := range c.managers() {
+		token, err := m.CreateUserToken(username, password)
+		if err == nil {
+			return token, nil
+		}
+		catcher.Add(err)
+	}
+
+	return "", errors.Wrap(catcher.Resolve(), "No valid user for the given username and password")
+}
+
+func (c *ChainUserManager) GetLoginHandler(url string) http.HandlerFunc {
+	return c.primary.GetLoginHandler(url)
+}
+func (c *ChainUserManager) GetLoginCallbackHandler() http.HandlerFunc {
+	return c.primary.GetLoginCallbackHandler()
+}
+func (c *ChainUserManager) IsRedirect() bool { return c.primary.IsRedirect() }
+func (c *ChainUserManager) GetUserByID(id string) (gimlet.User, error) {
+	return c.primary.GetUserByID(id)
+}
+func (c *ChainUserManager) GetOrCreateUser(u gimlet.User) (gimlet.User, error) {
+	return c.primary.GetOrCreateUser(u)
+}
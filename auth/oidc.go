@@ -0,0 +1,570 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+const (
+	oidcRequestTimeout = 10 * time.Second
+	// oidcClockSkew is the leeway given to token expiration times.
+	oidcClockSkew = time.Minute
+	// oidcKeyRefreshInterval limits how often unknown key ids cause the
+	// provider's keys to be fetched again.
+	oidcKeyRefreshInterval = time.Minute
+	// oidcStateTTL is how long a user has to authenticate with the provider
+	// after being redirected to it.
+	oidcStateTTL = 10 * time.Minute
+	// oidcRoleSource marks the role assignments granted to users through
+	// their groups.
+	oidcRoleSource = "oidc"
+)
+
+// OIDCUserManager implements the UserManager with an OpenID Connect identity
+// provider using the authorization code flow.
+// The login handler redirects the user to the provider's authorization
+// endpoint with a state string that records where to send the user afterward,
+// signed with the Salt field so that the callback can check that it was not
+// forged, and a nonce derived from the state. After the user authenticates,
+// the provider redirects the user to the callback handler with a code, which
+// the application exchanges for an ID token. The ID token is checked against
+// the provider's published keys and the nonce, and is then stored in the
+// session cookie.
+// Whenever GetUserByToken is called, the token's signature, issuer, audience
+// and expiration are verified and the user is built from its claims. If the
+// configuration names groups, the user must belong to one of them. When a
+// user from the provider is stored, the roles the configuration grants to
+// their groups are assigned to them, and those it no longer grants are
+// revoked.
+type OIDCUserManager struct {
+	conf   *evergreen.OIDCAuthConfig
+	salt   string
+	client *http.Client
+
+	mu          sync.Mutex
+	provider    *oidcProviderInfo
+	keys        map[string]*rsa.PublicKey
+	keysFetched time.Time
+	callbackURL string
+}
+
+type oidcProviderInfo struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// oidcUser is a user built from the claims of an ID token, whose roles are
+// their groups. It is distinct from other users so that only users that came
+// from the provider have their role assignments synced with their groups.
+type oidcUser struct {
+	simpleUser
+}
+
+// NewOIDCUserManager initializes an OIDCUserManager. The provider's
+// configuration is discovered the first time it is needed.
+func NewOIDCUserManager(conf *evergreen.OIDCAuthConfig) (*OIDCUserManager, error) {
+	if conf.Issuer == "" {
+		return nil, errors.New("no issuer for config given")
+	}
+	if conf.ClientId == "" {
+		return nil, errors.New("no client id for config given")
+	}
+	if conf.ClientSecret == "" {
+		return nil, errors.New("no client secret for config given")
+	}
+
+	return &OIDCUserManager{
+		conf:   conf,
+		salt:   util.RandomString(),
+		client: &http.Client{Timeout: oidcRequestTimeout},
+	}, nil
+}
+
+// GetUserByToken verifies an ID token and returns the user it describes.
+func (m *OIDCUserManager) GetUserByToken(ctx context.Context, token string) (gimlet.User, error) {
+	claims, err := m.verifyIDToken(ctx, token)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid ID token")
+	}
+
+	return m.userFromClaims(claims)
+}
+
+// CreateUserToken is not implemented in OIDCUserManager
+func (*OIDCUserManager) CreateUserToken(string, string) (string, error) {
+	return "", errors.New("OIDCUserManager does not create tokens via username/password")
+}
+
+// GetLoginHandler returns the function that starts the authorization code
+// flow by redirecting the user to the provider.
+func (m *OIDCUserManager) GetLoginHandler(callbackUri string) http.HandlerFunc {
+	m.mu.Lock()
+	m.callbackURL = fmt.Sprintf("%s/login/redirect/callback", callbackUri)
+	m.mu.Unlock()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), oidcRequestTimeout)
+		defer cancel()
+
+		conf, err := m.oauthConfig(ctx)
+		if err != nil {
+			grip.Error(message.WrapError(err, message.Fields{
+				"message": "problem discovering OIDC provider",
+				"issuer":  m.conf.Issuer,
+			}))
+			http.Error(w, "identity provider unavailable", http.StatusBadGateway)
+			return
+		}
+
+		state := m.makeState(r.FormValue("redirect"), time.Now())
+		http.Redirect(w, r, conf.AuthCodeURL(state, oauth2.SetAuthURLParam("nonce", oidcNonce(state))), http.StatusFound)
+	}
+}
+
+// GetLoginCallbackHandler returns the function that is called when the
+// provider redirects the user back to Evergreen.
+func (m *OIDCUserManager) GetLoginCallbackHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if errCode := r.FormValue("error"); errCode != "" {
+			grip.Error(message.Fields{
+				"message":     "OIDC provider returned an error",
+				"error":       errCode,
+				"description": r.FormValue("error_description"),
+			})
+			http.Error(w, "authentication failed", http.StatusUnauthorized)
+			return
+		}
+		code := r.FormValue("code")
+		if code == "" {
+			grip.Error("Error getting code from OIDC provider for authentication")
+			http.Error(w, "authentication failed", http.StatusBadRequest)
+			return
+		}
+		state := r.FormValue("state")
+		redirect, err := m.checkState(state, time.Now())
+		if err != nil {
+			grip.Error(message.WrapError(err, message.Fields{
+				"message": "invalid state when authenticating with OIDC provider",
+			}))
+			http.Redirect(w, r, "/login", http.StatusFound)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), oidcRequestTimeout)
+		defer cancel()
+
+		idToken, err := m.exchange(ctx, code)
+		if err != nil {
+			grip.Error(message.WrapError(err, message.Fields{
+				"message": "problem exchanging code with OIDC provider",
+				"issuer":  m.conf.Issuer,
+			}))
+			http.Error(w, "authentication failed", http.StatusUnauthorized)
+			return
+		}
+		claims, err := m.verifyIDToken(ctx, idToken)
+		if err == nil && claims["nonce"] != oidcNonce(state) {
+			err = errors.New("nonce does not match")
+		}
+		if err != nil {
+			grip.Error(message.WrapError(err, message.Fields{
+				"message": "OIDC provider returned an invalid ID token",
+				"issuer":  m.conf.Issuer,
+			}))
+			http.Error(w, "authentication failed", http.StatusUnauthorized)
+			return
+		}
+		if _, err = m.userFromClaims(claims); err != nil {
+			grip.Info(message.WrapError(err, message.Fields{
+				"message": "user is not authorized",
+				"issuer":  m.conf.Issuer,
+			}))
+			http.Error(w, "user is not authorized", http.StatusForbidden)
+			return
+		}
+
+		setLoginToken(idToken, w)
+		http.Redirect(w, r, redirect, http.StatusFound)
+	}
+}
+
+func (*OIDCUserManager) IsRedirect() bool                           { return true }
+func (*OIDCUserManager) GetUserByID(id string) (gimlet.User, error) { return getUserByID(id) }
+
+// GetOrCreateUser stores the user, syncing the role assignments of users
+// from the provider with the roles granted to their groups.
+func (m *OIDCUserManager) GetOrCreateUser(u gimlet.User) (gimlet.User, error) {
+	dbUser, err := model.GetOrCreateUser(u.Username(), u.DisplayName(), u.Email())
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := u.(*oidcUser); !ok {
+		return dbUser, nil
+	}
+
+	if err = role.SyncAssignments(dbUser.Id, oidcRoleSource, m.groupAssignments(u.Roles())); err != nil {
+		return nil, errors.Wrapf(err, "problem syncing roles of user '%s'", dbUser.Id)
+	}
+
+	return dbUser, nil
+}
+
+// groupAssignments returns the role assignments the configuration grants to
+// members of the groups.
+func (m *OIDCUserManager) groupAssignments(groups []string) []role.Assignment {
+	assignments := []role.Assignment{}
+	for _, groupRole := range m.conf.GroupRoles {
+		if util.StringSliceContains(groups, groupRole.Group) {
+			assignments = append(assignments, role.Assignment{Role: groupRole.Role, Project: groupRole.Project})
+		}
+	}
+	return assignments
+}
+
+// userFromClaims builds a user from the claims of a verified ID token.
+func (m *OIDCUserManager) userFromClaims(claims map[string]interface{}) (*oidcUser, error) {
+	username, _ := claims[m.conf.UsernameClaim].(string)
+	if username == "" {
+		return nil, errors.Errorf("ID token has no '%s' claim", m.conf.UsernameClaim)
+	}
+	u := &oidcUser{simpleUser{UserId: username}}
+	u.Name, _ = claims[m.conf.DisplayNameClaim].(string)
+	u.EmailAddress, _ = claims[m.conf.EmailClaim].(string)
+
+	if m.conf.GroupsClaim != "" {
+		switch groups := claims[m.conf.GroupsClaim].(type) {
+		case string:
+			u.SiteRoles = []string{groups}
+		case []interface{}:
+			for _, group := range groups {
+				if name, ok := group.(string); ok {
+					u.SiteRoles = append(u.SiteRoles, name)
+				}
+			}
+		}
+	}
+
+	if len(m.conf.Groups) == 0 {
+		return u, nil
+	}
+	for _, group := range u.SiteRoles {
+		if util.StringSliceContains(m.conf.Groups, group) {
+			return u, nil
+		}
+	}
+	return nil, errors.Errorf("user '%s' is not a member of an authorized group", username)
+}
+
+// makeState returns a state string that encodes the time and the page to
+// return to after logging in, signed with the manager's salt.
+func (m *OIDCUserManager) makeState(redirect string, now time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d|%s", now.Unix(), redirect)))
+	return fmt.Sprintf("%s.%s", payload, m.sign(payload))
+}
+
+// checkState verifies a state string made by makeState and returns the page
+// to return to. Only paths on this site are honored.
+func (m *OIDCUserManager) checkState(state string, now time.Time) (string, error) {
+	parts := strings.SplitN(state, ".", 2)
+	if len(parts) != 2 || !hmac.Equal([]byte(m.sign(parts[0])), []byte(parts[1])) {
+		return "", errors.New("state signature does not match")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", errors.Wrap(err, "malformed state")
+	}
+	fields := strings.SplitN(string(payload), "|", 2)
+	if len(fields) != 2 {
+		return "", errors.New("malformed state")
+	}
+	var issued int64
+	if _, err = fmt.Sscanf(fields[0], "%d", &issued); err != nil {
+		return "", errors.Wrap(err, "malformed state")
+	}
+	if now.Sub(time.Unix(issued, 0)) > oidcStateTTL {
+		return "", errors.New("state has expired")
+	}
+
+	redirect := fields[1]
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") {
+		redirect = "/"
+	}
+	return redirect, nil
+}
+
+func (m *OIDCUserManager) sign(payload string) string {
+	mac := hmac.New(sha256.New, []byte(m.salt))
+	_, _ = mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func oidcNonce(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return hex.EncodeToString(sum[:])
+}
+
+func (m *OIDCUserManager) oauthConfig(ctx context.Context) (*oauth2.Config, error) {
+	provider, err := m.discover(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	scopes := m.conf.Scopes
+	if !util.StringSliceContains(scopes, "openid") {
+		scopes = append([]string{"openid"}, scopes...)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return &oauth2.Config{
+		ClientID:     m.conf.ClientId,
+		ClientSecret: m.conf.ClientSecret,
+		RedirectURL:  m.callbackURL,
+		Scopes:       scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  provider.AuthorizationEndpoint,
+			TokenURL: provider.TokenEndpoint,
+		},
+	}, nil
+}
+
+// exchange trades an authorization code for an ID token.
+func (m *OIDCUserManager) exchange(ctx context.Context, code string) (string, error) {
+	conf, err := m.oauthConfig(ctx)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	token, err := conf.Exchange(context.WithValue(ctx, oauth2.HTTPClient, m.client), code)
+	if err != nil {
+		return "", errors.Wrap(err, "problem exchanging code for token")
+	}
+	idToken, _ := token.Extra("id_token").(string)
+	if idToken == "" {
+		return "", errors.New("token response has no ID token")
+	}
+
+	return idToken, nil
+}
+
+// discover fetches and caches the provider's configuration.
+func (m *OIDCUserManager) discover(ctx context.Context) (*oidcProviderInfo, error) {
+	m.mu.Lock()
+	provider := m.provider
+	m.mu.Unlock()
+	if provider != nil {
+		return provider, nil
+	}
+
+	provider = &oidcProviderInfo{}
+	discoveryURL := strings.TrimSuffix(m.conf.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := m.getJSON(ctx, discoveryURL, provider); err != nil {
+		return nil, errors.Wrap(err, "problem fetching provider configuration")
+	}
+	if provider.Issuer != m.conf.Issuer {
+		return nil, errors.Errorf("provider reports issuer '%s', expected '%s'", provider.Issuer, m.conf.Issuer)
+	}
+	if provider.AuthorizationEndpoint == "" || provider.TokenEndpoint == "" || provider.JWKSURI == "" {
+		return nil, errors.New("provider configuration is missing endpoints")
+	}
+
+	m.mu.Lock()
+	m.provider = provider
+	m.mu.Unlock()
+	return provider, nil
+}
+
+// publicKey returns the provider's key with the given id, fetching the
+// provider's keys if the id is unknown.
+func (m *OIDCUserManager) publicKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	m.mu.Lock()
+	key, ok := m.keys[kid]
+	canRefresh := time.Since(m.keysFetched) > oidcKeyRefreshInterval
+	m.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+	if !canRefresh {
+		return nil, errors.Errorf("unknown signing key '%s'", kid)
+	}
+
+	provider, err := m.discover(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	set := struct {
+		Keys []oidcJSONWebKey `json:"keys"`
+	}{}
+	if err = m.getJSON(ctx, provider.JWKSURI, &set); err != nil {
+		return nil, errors.Wrap(err, "problem fetching provider keys")
+	}
+	keys := map[string]*rsa.PublicKey{}
+	for _, jwk := range set.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		pub, err := jwk.rsaKey()
+		if err != nil {
+			grip.Warning(message.WrapError(err, message.Fields{
+				"message": "skipping malformed provider key",
+				"kid":     jwk.Kid,
+			}))
+			continue
+		}
+		keys[jwk.Kid] = pub
+	}
+
+	m.mu.Lock()
+	m.keys = keys
+	m.keysFetched = time.Now()
+	m.mu.Unlock()
+
+	if key, ok = keys[kid]; !ok {
+		return nil, errors.Errorf("unknown signing key '%s'", kid)
+	}
+	return key, nil
+}
+
+type oidcJSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+func (k oidcJSONWebKey) rsaKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, errors.Wrap(err, "malformed modulus")
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, errors.Wrap(err, "malformed exponent")
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+		return nil, errors.New("exponent is too large")
+	}
+
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
+
+// verifyIDToken checks an ID token's signature and standard claims and
+// returns its claims. Only RS256 signatures are supported.
+func (m *OIDCUserManager) verifyIDToken(ctx context.Context, token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	header := struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}{}
+	if err := decodeTokenSegment(parts[0], &header); err != nil {
+		return nil, errors.Wrap(err, "malformed token header")
+	}
+	if header.Alg != "RS256" {
+		return nil, errors.Errorf("unsupported signing algorithm '%s'", header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Wrap(err, "malformed token signature")
+	}
+	key, err := m.publicKey(ctx, header.Kid)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, errors.New("token signature is invalid")
+	}
+
+	claims := map[string]interface{}{}
+	if err = decodeTokenSegment(parts[1], &claims); err != nil {
+		return nil, errors.Wrap(err, "malformed token claims")
+	}
+	if iss, _ := claims["iss"].(string); iss != m.conf.Issuer {
+		return nil, errors.Errorf("token was issued by '%s'", iss)
+	}
+	if !audienceContains(claims["aud"], m.conf.ClientId) {
+		return nil, errors.New("token was not issued for this client")
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return nil, errors.New("token has no expiration")
+	}
+	if time.Now().Add(-oidcClockSkew).After(time.Unix(int64(exp), 0)) {
+		return nil, errors.New("token has expired")
+	}
+
+	return claims, nil
+}
+
+func decodeTokenSegment(segment string, out interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(json.Unmarshal(data, out))
+}
+
+func audienceContains(aud interface{}, clientId string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == clientId
+	case []interface{}:
+		for _, a := range v {
+			if a == clientId {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (m *OIDCUserManager) getJSON(ctx context.Context, target string, out interface{}) error {
+	if _, err := url.Parse(target); err != nil {
+		return errors.Wrapf(err, "invalid url '%s'", target)
+	}
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	resp, err := m.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("got status %d from '%s'", resp.StatusCode, target)
+	}
+
+	return errors.WithStack(util.ReadJSONInto(resp.Body, out))
+}
@@ -0,0 +1,315 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/stretchr/testify/suite"
+)
+
+// oidcTestProvider is a stand-in OpenID Connect provider that issues ID
+// tokens signed with a generated key.
+type oidcTestProvider struct {
+	server  *httptest.Server
+	key     *rsa.PrivateKey
+	kid     string
+	codes   map[string]string
+	jwksHit int
+}
+
+func newOIDCTestProvider() (*oidcTestProvider, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	p := &oidcTestProvider{key: key, kid: "key-1", codes: map[string]string{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.server.URL,
+			"authorization_endpoint": p.server.URL + "/authorize",
+			"token_endpoint":         p.server.URL + "/token",
+			"jwks_uri":               p.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		p.jwksHit++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": p.kid,
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		token, ok := p.codes[r.FormValue("code")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"id_token":     token,
+		})
+	})
+	p.server = httptest.NewServer(mux)
+
+	return p, nil
+}
+
+func (p *oidcTestProvider) sign(key *rsa.PrivateKey, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": p.kid})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	sig, _ := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func (p *oidcTestProvider) token(overrides map[string]interface{}) string {
+	claims := map[string]interface{}{
+		"iss":                p.server.URL,
+		"aud":                "client",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"sub":                "1234",
+		"preferred_username": "octocat",
+		"name":               "Mona Octocat",
+		"email":              "octocat@example.com",
+		"groups":             []string{"engineering", "oncall"},
+	}
+	for k, v := range overrides {
+		if v == nil {
+			delete(claims, k)
+			continue
+		}
+		claims[k] = v
+	}
+	return p.sign(p.key, claims)
+}
+
+type OIDCSuite struct {
+	provider *oidcTestProvider
+	conf     *evergreen.OIDCAuthConfig
+	um       *OIDCUserManager
+	ctx      context.Context
+	suite.Suite
+}
+
+func TestOIDCSuite(t *testing.T) {
+	suite.Run(t, new(OIDCSuite))
+}
+
+func (s *OIDCSuite) SetupSuite() {
+	var err error
+	s.provider, err = newOIDCTestProvider()
+	s.Require().NoError(err)
+	s.ctx = context.Background()
+}
+
+func (s *OIDCSuite) TearDownSuite() {
+	s.provider.server.Close()
+}
+
+func (s *OIDCSuite) SetupTest() {
+	s.conf = &evergreen.OIDCAuthConfig{
+		Issuer:       s.provider.server.URL,
+		ClientId:     "client",
+		ClientSecret: "secret",
+		GroupsClaim:  "groups",
+	}
+	authConf := evergreen.AuthConfig{OIDC: s.conf}
+	s.Require().NoError(authConf.ValidateAndDefault())
+
+	var err error
+	s.um, err = NewOIDCUserManager(s.conf)
+	s.Require().NoError(err)
+}
+
+func (s *OIDCSuite) TestNewOIDCUserManagerRequiresSettings() {
+	_, err := NewOIDCUserManager(&evergreen.OIDCAuthConfig{ClientId: "client", ClientSecret: "secret"})
+	s.Error(err)
+	_, err = NewOIDCUserManager(&evergreen.OIDCAuthConfig{Issuer: "https://idp", ClientSecret: "secret"})
+	s.Error(err)
+	_, err = NewOIDCUserManager(&evergreen.OIDCAuthConfig{Issuer: "https://idp", ClientId: "client"})
+	s.Error(err)
+}
+
+func (s *OIDCSuite) TestGetUserByToken() {
+	u, err := s.um.GetUserByToken(s.ctx, s.provider.token(nil))
+	s.Require().NoError(err)
+	s.Equal("octocat", u.Username())
+	s.Equal("Mona Octocat", u.DisplayName())
+	s.Equal("octocat@example.com", u.Email())
+	s.Equal([]string{"engineering", "oncall"}, u.Roles())
+}
+
+func (s *OIDCSuite) TestGetUserByTokenRejectsInvalidTokens() {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+
+	for name, token := range map[string]string{
+		"wrong key":        s.provider.sign(otherKey, map[string]interface{}{"iss": s.provider.server.URL, "aud": "client", "exp": time.Now().Add(time.Hour).Unix(), "preferred_username": "octocat"}),
+		"wrong issuer":     s.provider.token(map[string]interface{}{"iss": "https://elsewhere"}),
+		"wrong audience":   s.provider.token(map[string]interface{}{"aud": []string{"other"}}),
+		"expired":          s.provider.token(map[string]interface{}{"exp": time.Now().Add(-time.Hour).Unix()}),
+		"no expiration":    s.provider.token(map[string]interface{}{"exp": nil}),
+		"no username":      s.provider.token(map[string]interface{}{"preferred_username": nil}),
+		"malformed":        "not.a.token",
+		"too few segments": "abc",
+	} {
+		_, err = s.um.GetUserByToken(s.ctx, token)
+		s.Error(err, name)
+	}
+}
+
+func (s *OIDCSuite) TestAudienceList() {
+	u, err := s.um.GetUserByToken(s.ctx, s.provider.token(map[string]interface{}{"aud": []string{"other", "client"}}))
+	s.Require().NoError(err)
+	s.Equal("octocat", u.Username())
+}
+
+func (s *OIDCSuite) TestAuthorizedGroups() {
+	s.conf.Groups = []string{"oncall"}
+	_, err := s.um.GetUserByToken(s.ctx, s.provider.token(nil))
+	s.NoError(err)
+
+	s.conf.Groups = []string{"admins"}
+	_, err = s.um.GetUserByToken(s.ctx, s.provider.token(nil))
+	s.Error(err)
+}
+
+func (s *OIDCSuite) TestGroupAssignments() {
+	s.conf.GroupRoles = []evergreen.OIDCGroupRole{
+		{Group: "oncall", Role: "admin"},
+		{Group: "engineering", Role: "project_admin", Project: "mci"},
+		{Group: "admins", Role: "admin"},
+	}
+
+	u, err := s.um.GetUserByToken(s.ctx, s.provider.token(nil))
+	s.Require().NoError(err)
+	assignments := s.um.groupAssignments(u.Roles())
+	s.Require().Len(assignments, 2)
+	s.Equal("admin", assignments[0].Role)
+	s.Empty(assignments[0].Project)
+	s.Equal("project_admin", assignments[1].Role)
+	s.Equal("mci", assignments[1].Project)
+
+	s.Empty(s.um.groupAssignments(nil))
+}
+
+func (s *OIDCSuite) TestKeysAreCached() {
+	hits := s.provider.jwksHit
+	for i := 0; i < 3; i++ {
+		_, err := s.um.GetUserByToken(s.ctx, s.provider.token(nil))
+		s.Require().NoError(err)
+	}
+	s.Equal(hits+1, s.provider.jwksHit)
+}
+
+func (s *OIDCSuite) TestState() {
+	now := time.Now()
+	state := s.um.makeState("/task/abc", now)
+	redirect, err := s.um.checkState(state, now)
+	s.NoError(err)
+	s.Equal("/task/abc", redirect)
+
+	redirect, err = s.um.checkState(s.um.makeState("https://evil.example.com", now), now)
+	s.NoError(err)
+	s.Equal("/", redirect)
+
+	_, err = s.um.checkState(state, now.Add(2*oidcStateTTL))
+	s.Error(err)
+	_, err = s.um.checkState(state+"0", now)
+	s.Error(err)
+}
+
+func (s *OIDCSuite) TestLoginFlow() {
+	login := s.um.GetLoginHandler("https://evergreen.example.com")
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/login/redirect?redirect=%2Fwaterfall", nil)
+	login(w, r)
+	s.Require().Equal(http.StatusFound, w.Code)
+
+	location, err := url.Parse(w.Header().Get("Location"))
+	s.Require().NoError(err)
+	s.Equal(s.provider.server.URL+"/authorize", location.Scheme+"://"+location.Host+location.Path)
+	query := location.Query()
+	s.Equal("client", query.Get("client_id"))
+	s.Equal("https://evergreen.example.com/login/redirect/callback", query.Get("redirect_uri"))
+	s.Contains(strings.Split(query.Get("scope"), " "), "openid")
+	state := query.Get("state")
+	s.Require().NotEmpty(state)
+	s.Equal(oidcNonce(state), query.Get("nonce"))
+
+	idToken := s.provider.token(map[string]interface{}{"nonce": query.Get("nonce")})
+	s.provider.codes["good"] = idToken
+	callback := s.um.GetLoginCallbackHandler()
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/login/redirect/callback?code=good&state="+url.QueryEscape(state), nil)
+	callback(w, r)
+	s.Require().Equal(http.StatusFound, w.Code)
+	s.Equal("/waterfall", w.Header().Get("Location"))
+	cookies := w.Result().Cookies()
+	s.Require().Len(cookies, 1)
+	s.Equal(evergreen.AuthTokenCookie, cookies[0].Name)
+	s.Equal(idToken, cookies[0].Value)
+
+	// a token minted for a different login attempt is rejected
+	s.provider.codes["replayed"] = s.provider.token(map[string]interface{}{"nonce": "other"})
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/login/redirect/callback?code=replayed&state="+url.QueryEscape(state), nil)
+	callback(w, r)
+	s.Equal(http.StatusUnauthorized, w.Code)
+
+	// a forged state sends the user back to the login page
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/login/redirect/callback?code=good&state=forged.state", nil)
+	callback(w, r)
+	s.Equal(http.StatusFound, w.Code)
+	s.Equal("/login", w.Header().Get("Location"))
+}
+
+func (s *OIDCSuite) TestChainWithNaive() {
+	naive, err := NewNaiveUserManager(&evergreen.NaiveAuthConfig{
+		Users: []*evergreen.AuthUser{{Username: "service", Password: "pw", Email: "service@example.com"}},
+	})
+	s.Require().NoError(err)
+	um := NewChainUserManager(s.um, naive)
+
+	s.True(um.IsRedirect())
+
+	u, err := um.GetUserByToken(s.ctx, s.provider.token(nil))
+	s.Require().NoError(err)
+	s.Equal("octocat", u.Username())
+
+	token, err := um.CreateUserToken("service", "pw")
+	s.Require().NoError(err)
+	u, err = um.GetUserByToken(s.ctx, token)
+	s.Require().NoError(err)
+	s.Equal("service", u.Username())
+
+	_, err = um.CreateUserToken("service", "wrong")
+	s.Error(err)
+	_, err = um.GetUserByToken(s.ctx, "garbage")
+	s.Error(err)
+}
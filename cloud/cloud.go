@@ -99,6 +99,10 @@ func GetManager(ctx context.Context, providerName string, settings *evergreen.Se
 		provider = &dockerManager{}
 	case evergreen.ProviderNameDockerMock:
 		provider = &dockerManager{client: &dockerClientMock{}}
+	case evergreen.ProviderNameKubernetes:
+		provider = &kubernetesManager{}
+	case evergreen.ProviderNameK8sMock:
+		provider = &kubernetesManager{client: &kubernetesClientMock{}}
 	case evergreen.ProviderNameOpenstack:
 		provider = &openStackManager{}
 	case evergreen.ProviderNameGce:
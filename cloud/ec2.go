@@ -122,6 +122,18 @@ const (
 	EC2ErrorSpotRequestNotFound = "InvalidSpotInstanceRequestID.NotFound"
 )
 
+const (
+	// EC2SpotInterruptionURL is the instance metadata endpoint at which EC2
+	// gives notice that it will reclaim a spot instance.
+	EC2SpotInterruptionURL = "http://169.254.169.254/latest/meta-data/spot/instance-action"
+
+	// spotInterruptionThreshold is the number of spot interruptions of a
+	// distro's hosts within spotInterruptionWindow after which the auto
+	// provider spawns on-demand hosts for the distro.
+	spotInterruptionThreshold = 3
+	spotInterruptionWindow    = 6 * time.Hour
+)
+
 // EC2ManagerOptions are used to construct a new ec2Manager.
 type EC2ManagerOptions struct {
 	// client is the client library for communicating with AWS.
@@ -234,6 +234,10 @@ func (m *ec2Manager) getProvider(ctx context.Context, h *host.Host, ec2settings
 		return onDemandProvider, nil
 	}
 	if m.provider == autoProvider {
+		if recentlyInterrupted(h.Distro.Id) {
+			h.Distro.Provider = evergreen.ProviderNameEc2OnDemand
+			return onDemandProvider, nil
+		}
 		r, err := getRegion(h)
 		if err != nil {
 			return 0, errors.Wrap(err, "problem getting region for host")
@@ -265,6 +269,31 @@ func (m *ec2Manager) getProvider(ctx context.Context, h *host.Host, ec2settings
 	return 0, errors.Errorf("provider is %d, expected %d, %d, or %d", m.provider, onDemandProvider, spotProvider, autoProvider)
 }
 
+// recentlyInterrupted returns true if the distro's spot instances have been
+// reclaimed so often that its hosts should be on-demand instances, even if
+// spot instances are cheaper.
+func recentlyInterrupted(distroID string) bool {
+	interruptions, err := host.CountSpotInterruptions(distroID, time.Now().Add(-spotInterruptionWindow))
+	if err != nil {
+		grip.Warning(message.WrapError(err, message.Fields{
+			"message": "problem counting spot interruptions, not avoiding spot instances",
+			"distro":  distroID,
+		}))
+		return false
+	}
+	if interruptions < spotInterruptionThreshold {
+		return false
+	}
+
+	grip.Info(message.Fields{
+		"message":       "spot instances of distro were recently interrupted, spawning on-demand instance",
+		"distro":        distroID,
+		"interruptions": interruptions,
+		"window":        spotInterruptionWindow.String(),
+	})
+	return true
+}
+
 func (m *ec2Manager) getSubnetForAZ(ctx context.Context, azName, vpcName string) (string, error) {
 	vpcs, err := m.client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
 		Filters: []*ec2.Filter{
@@ -0,0 +1,318 @@
+package cloud
+
+import (
+	"context"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/mitchellh/mapstructure"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// kubernetesManager implements the Manager and ContainerManager interfaces
+// by running each host as a pod in a Kubernetes cluster.
+type kubernetesManager struct {
+	client           kubernetesClient
+	apiURL           string
+	defaultNamespace string
+}
+
+// kubernetesSettings specifies the settings used to configure a pod.
+type kubernetesSettings struct {
+	// Image is the container image the pod runs. It must contain the
+	// Evergreen binary at AgentPath.
+	Image string `mapstructure:"image" json:"image" bson:"image"`
+	// AgentPath is the location of the Evergreen binary in the image.
+	AgentPath string `mapstructure:"agent_path" json:"agent_path" bson:"agent_path"`
+	// Namespace overrides the namespace of the container pool and the
+	// admin settings.
+	Namespace string `mapstructure:"namespace" json:"namespace" bson:"namespace"`
+	// CPU and Memory are resource limits in Kubernetes quantity notation,
+	// e.g. "500m" or "2Gi".
+	CPU            string            `mapstructure:"cpu" json:"cpu" bson:"cpu"`
+	Memory         string            `mapstructure:"memory" json:"memory" bson:"memory"`
+	NodeSelector   map[string]string `mapstructure:"node_selector" json:"node_selector" bson:"node_selector"`
+	ServiceAccount string            `mapstructure:"service_account" json:"service_account" bson:"service_account"`
+}
+
+// Validate checks that the settings from the distro are sane.
+func (settings *kubernetesSettings) Validate() error {
+	if settings.Image == "" {
+		return errors.New("Image must not be blank")
+	}
+
+	return nil
+}
+
+// GetSettings returns an empty ProviderSettings struct.
+func (*kubernetesManager) GetSettings() ProviderSettings {
+	return &kubernetesSettings{}
+}
+
+// Configure populates a kubernetesManager by reading relevant settings from
+// the config object.
+func (m *kubernetesManager) Configure(ctx context.Context, s *evergreen.Settings) error {
+	config := s.Providers.Kubernetes
+
+	if m.client == nil {
+		m.client = &kubernetesClientImpl{}
+	}
+
+	if err := m.client.Init(config); err != nil {
+		return errors.Wrap(err, "Failed to initialize client connection")
+	}
+
+	m.apiURL = s.ApiUrl
+	m.defaultNamespace = config.Namespace
+
+	return nil
+}
+
+// getSettings decodes and validates the provider settings of the host's distro.
+func (m *kubernetesManager) getSettings(h *host.Host) (*kubernetesSettings, error) {
+	settings := &kubernetesSettings{}
+	if h.Distro.ProviderSettings != nil {
+		if err := mapstructure.Decode(h.Distro.ProviderSettings, settings); err != nil {
+			return nil, errors.Wrapf(err, "Error decoding params for distro '%s'", h.Distro.Id)
+		}
+	}
+	if settings.AgentPath == "" {
+		settings.AgentPath = "/evergreen"
+	}
+
+	if err := settings.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "Invalid Kubernetes settings for host '%s'", h.Id)
+	}
+
+	return settings, nil
+}
+
+// namespace returns the namespace of the host's pod, preferring the distro's
+// settings, then the host's container pool, then the admin settings.
+func (m *kubernetesManager) namespace(h *host.Host, settings *kubernetesSettings) (string, error) {
+	switch {
+	case settings.Namespace != "":
+		return settings.Namespace, nil
+	case h.ContainerPoolSettings != nil && h.ContainerPoolSettings.Namespace != "":
+		return h.ContainerPoolSettings.Namespace, nil
+	case m.defaultNamespace != "":
+		return m.defaultNamespace, nil
+	default:
+		return "", errors.Errorf("no Kubernetes namespace configured for host '%s'", h.Id)
+	}
+}
+
+func (m *kubernetesManager) podNamespace(h *host.Host) (string, error) {
+	settings, err := m.getSettings(h)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return m.namespace(h, settings)
+}
+
+// SpawnHost creates a pod that runs the agent for the host.
+func (m *kubernetesManager) SpawnHost(ctx context.Context, h *host.Host) (*host.Host, error) {
+	if h.Distro.Provider != evergreen.ProviderNameKubernetes {
+		return nil, errors.Errorf("Can't spawn instance of %s for distro %s: provider is %s",
+			evergreen.ProviderNameKubernetes, h.Distro.Id, h.Distro.Provider)
+	}
+
+	settings, err := m.getSettings(h)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	namespace, err := m.namespace(h, settings)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if h.Secret == "" {
+		if err = h.CreateSecret(); err != nil {
+			return nil, errors.Wrapf(err, "creating secret for %s", h.Id)
+		}
+	}
+
+	grip.Info(message.Fields{
+		"message":   "decoded Kubernetes pod settings",
+		"host":      h.Id,
+		"namespace": namespace,
+		"image":     settings.Image,
+	})
+
+	if err = m.client.CreateSecret(ctx, makeK8sSecret(h, namespace)); err != nil {
+		err = errors.Wrapf(err, "Failed to create secret for host '%s'", h.Id)
+		grip.Error(err)
+		return nil, err
+	}
+
+	if err = m.client.CreatePod(ctx, makeK8sPod(h, settings, namespace, m.apiURL)); err != nil {
+		err = errors.Wrapf(err, "Failed to create pod for host '%s'", h.Id)
+		grip.Error(err)
+		grip.Error(message.WrapError(m.client.DeleteSecret(ctx, namespace, h.Id), message.Fields{
+			"message":   "problem deleting secret of pod that was not created",
+			"host":      h.Id,
+			"namespace": namespace,
+		}))
+		return nil, err
+	}
+
+	if err = h.SetAgentRevision(evergreen.BuildRevision); err != nil {
+		return nil, errors.Wrapf(err, "error setting agent revision on host %s", h.Id)
+	}
+
+	// The pod starts the agent itself, so there is nothing left to provision.
+	if err = h.MarkAsProvisioned(); err != nil {
+		return nil, errors.Wrapf(err, "error marking host %s as provisioned", h.Id)
+	}
+
+	grip.Info(message.Fields{
+		"message":   "created Kubernetes pod",
+		"host":      h.Id,
+		"namespace": namespace,
+	})
+	event.LogHostStarted(h.Id)
+
+	return h, nil
+}
+
+// GetInstanceStatus returns a universal status code representing the phase
+// of the host's pod.
+func (m *kubernetesManager) GetInstanceStatus(ctx context.Context, h *host.Host) (CloudStatus, error) {
+	namespace, err := m.podNamespace(h)
+	if err != nil {
+		return StatusUnknown, errors.WithStack(err)
+	}
+
+	pod, err := m.client.GetPod(ctx, namespace, h.Id)
+	if err != nil {
+		return StatusUnknown, errors.Wrapf(err, "Failed to get pod information for host '%s'", h.Id)
+	}
+
+	return k8sPhaseToEvgStatus(pod.Status.Phase), nil
+}
+
+// GetDNSName returns the IP address of the host's pod.
+func (m *kubernetesManager) GetDNSName(ctx context.Context, h *host.Host) (string, error) {
+	namespace, err := m.podNamespace(h)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	pod, err := m.client.GetPod(ctx, namespace, h.Id)
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to get pod information for host '%s'", h.Id)
+	}
+
+	return pod.Status.PodIP, nil
+}
+
+// TerminateInstance deletes the host's pod.
+func (m *kubernetesManager) TerminateInstance(ctx context.Context, h *host.Host, user string) error {
+	if h.Status == evergreen.HostTerminated {
+		err := errors.Errorf("Can not terminate %s - already marked as terminated!", h.Id)
+		grip.Error(err)
+		return err
+	}
+
+	namespace, err := m.podNamespace(h)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := m.client.DeletePod(ctx, namespace, h.Id); err != nil {
+		return errors.Wrap(err, "API call to delete pod failed")
+	}
+
+	// the pod is gone, so a secret left behind is only logged
+	grip.Error(message.WrapError(m.client.DeleteSecret(ctx, namespace, h.Id), message.Fields{
+		"message":   "problem deleting secret of terminated pod",
+		"host":      h.Id,
+		"namespace": namespace,
+	}))
+
+	grip.Info(message.Fields{
+		"message":   "terminated Kubernetes pod",
+		"host":      h.Id,
+		"namespace": namespace,
+	})
+
+	return h.Terminate(user)
+}
+
+// IsUp returns true if the host's pod is running.
+func (m *kubernetesManager) IsUp(ctx context.Context, h *host.Host) (bool, error) {
+	cloudStatus, err := m.GetInstanceStatus(ctx, h)
+	if err != nil {
+		return false, err
+	}
+	return cloudStatus == StatusRunning, nil
+}
+
+// OnUp does nothing.
+func (m *kubernetesManager) OnUp(context.Context, *host.Host) error {
+	return nil
+}
+
+// GetSSHOptions returns an array of default SSH options for connecting to a
+// pod.
+func (m *kubernetesManager) GetSSHOptions(h *host.Host, keyPath string) ([]string, error) {
+	if keyPath == "" {
+		return []string{}, errors.New("No key specified for Kubernetes host")
+	}
+
+	opts := []string{"-i", keyPath}
+	for _, opt := range h.Distro.SSHOptions {
+		opts = append(opts, "-o", opt)
+	}
+	return opts, nil
+}
+
+// TimeTilNextPayment returns the amount of time until the next payment is due
+// for the host. For Kubernetes this is not relevant.
+func (m *kubernetesManager) TimeTilNextPayment(_ *host.Host) time.Duration {
+	return time.Duration(0)
+}
+
+// GetContainers returns the IDs of the hosts running as pods in the
+// namespace of the given host's container pool.
+func (m *kubernetesManager) GetContainers(ctx context.Context, h *host.Host) ([]string, error) {
+	pool := h.ContainerPoolSettings
+	if pool == nil || !pool.UsesNamespace() {
+		return nil, errors.Errorf("host '%s' does not belong to a Kubernetes container pool", h.Id)
+	}
+
+	pods, err := m.client.ListPods(ctx, pool.Namespace, map[string]string{k8sContainerPoolLabel: pool.Id})
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing pods")
+	}
+
+	ids := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		ids = append(ids, pod.Metadata.Labels[k8sHostIDLabel])
+	}
+
+	return ids, nil
+}
+
+// RemoveOldestImage does nothing, since the cluster's nodes garbage collect
+// their own images.
+func (m *kubernetesManager) RemoveOldestImage(context.Context, *host.Host) error {
+	return nil
+}
+
+// CalculateImageSpaceUsage returns zero, since images are stored on the
+// cluster's nodes rather than on Evergreen hosts.
+func (m *kubernetesManager) CalculateImageSpaceUsage(context.Context, *host.Host) (int64, error) {
+	return 0, nil
+}
+
+// BuildContainerImage does nothing, since the cluster pulls pod images
+// itself.
+func (m *kubernetesManager) BuildContainerImage(context.Context, *host.Host, string) error {
+	return nil
+}
@@ -0,0 +1,155 @@
+package cloud
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/pkg/errors"
+)
+
+const k8sRequestTimeout = time.Minute
+
+// The kubernetesClient interface wraps interaction with the pods and secrets
+// APIs of a Kubernetes cluster.
+type kubernetesClient interface {
+	Init(evergreen.KubernetesConfig) error
+	CreatePod(context.Context, *k8sPod) error
+	GetPod(context.Context, string, string) (*k8sPod, error)
+	DeletePod(context.Context, string, string) error
+	ListPods(context.Context, string, map[string]string) ([]k8sPod, error)
+	CreateSecret(context.Context, *k8sSecret) error
+	DeleteSecret(context.Context, string, string) error
+}
+
+type kubernetesClientImpl struct {
+	apiServer  string
+	token      string
+	httpClient *http.Client
+}
+
+// Init validates the cluster configuration and creates the HTTP client used
+// to reach the API server.
+func (c *kubernetesClientImpl) Init(config evergreen.KubernetesConfig) error {
+	if config.APIServer == "" {
+		return errors.New("Kubernetes API server must not be blank")
+	}
+	c.apiServer = strings.TrimSuffix(config.APIServer, "/")
+	c.token = config.Token
+	c.httpClient = &http.Client{
+		Timeout: k8sRequestTimeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: config.Insecure},
+		},
+	}
+
+	return nil
+}
+
+func (c *kubernetesClientImpl) podsPath(namespace string) string {
+	return fmt.Sprintf("%s/api/v1/namespaces/%s/pods", c.apiServer, url.PathEscape(namespace))
+}
+
+func (c *kubernetesClientImpl) secretsPath(namespace string) string {
+	return fmt.Sprintf("%s/api/v1/namespaces/%s/secrets", c.apiServer, url.PathEscape(namespace))
+}
+
+func (c *kubernetesClientImpl) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return errors.Wrap(err, "problem marshalling request body")
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, path, body)
+	if err != nil {
+		return errors.Wrapf(err, "problem building request for '%s'", path)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "Kubernetes API call %s '%s' failed", method, path)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return errors.Errorf("Kubernetes API call %s '%s' returned status %d: %s",
+			method, path, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return errors.Wrap(json.NewDecoder(resp.Body).Decode(out), "problem reading response body")
+}
+
+// CreatePod creates the pod in the namespace named in its metadata.
+func (c *kubernetesClientImpl) CreatePod(ctx context.Context, pod *k8sPod) error {
+	return errors.WithStack(c.do(ctx, http.MethodPost, c.podsPath(pod.Metadata.Namespace), pod, nil))
+}
+
+// GetPod returns the pod with the given name in the namespace.
+func (c *kubernetesClientImpl) GetPod(ctx context.Context, namespace, name string) (*k8sPod, error) {
+	pod := &k8sPod{}
+	if err := c.do(ctx, http.MethodGet, c.podsPath(namespace)+"/"+url.PathEscape(name), nil, pod); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return pod, nil
+}
+
+// DeletePod removes the pod with the given name from the namespace.
+func (c *kubernetesClientImpl) DeletePod(ctx context.Context, namespace, name string) error {
+	return errors.WithStack(c.do(ctx, http.MethodDelete, c.podsPath(namespace)+"/"+url.PathEscape(name), nil, nil))
+}
+
+// ListPods returns the pods in the namespace that have all of the given labels.
+func (c *kubernetesClientImpl) ListPods(ctx context.Context, namespace string, labels map[string]string) ([]k8sPod, error) {
+	path := c.podsPath(namespace)
+	if len(labels) > 0 {
+		selectors := make([]string, 0, len(labels))
+		for k, v := range labels {
+			selectors = append(selectors, fmt.Sprintf("%s=%s", k, v))
+		}
+		sort.Strings(selectors)
+		path += "?labelSelector=" + url.QueryEscape(strings.Join(selectors, ","))
+	}
+
+	list := &k8sPodList{}
+	if err := c.do(ctx, http.MethodGet, path, nil, list); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return list.Items, nil
+}
+
+// CreateSecret creates the secret in the namespace named in its metadata.
+func (c *kubernetesClientImpl) CreateSecret(ctx context.Context, secret *k8sSecret) error {
+	return errors.WithStack(c.do(ctx, http.MethodPost, c.secretsPath(secret.Metadata.Namespace), secret, nil))
+}
+
+// DeleteSecret removes the secret with the given name from the namespace.
+func (c *kubernetesClientImpl) DeleteSecret(ctx context.Context, namespace, name string) error {
+	return errors.WithStack(c.do(ctx, http.MethodDelete, c.secretsPath(namespace)+"/"+url.PathEscape(name), nil, nil))
+}
@@ -0,0 +1,167 @@
+package cloud
+
+import (
+	"context"
+	"sync"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/pkg/errors"
+)
+
+// kubernetesClientMock is a fake clientset that keeps pods and secrets in
+// memory.
+type kubernetesClientMock struct {
+	// API call options
+	failInit   bool
+	failCreate bool
+	failGet    bool
+	failDelete bool
+	failList   bool
+
+	failCreateSecret bool
+	failDeleteSecret bool
+
+	// phase assigned to newly created pods
+	podPhase string
+	podIP    string
+
+	mu      sync.Mutex
+	pods    map[string]k8sPod
+	secrets map[string]k8sSecret
+}
+
+func k8sPodKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+func (c *kubernetesClientMock) Init(evergreen.KubernetesConfig) error {
+	if c.failInit {
+		return errors.New("failed to initialize client")
+	}
+	return nil
+}
+
+func (c *kubernetesClientMock) CreatePod(_ context.Context, pod *k8sPod) error {
+	if c.failCreate {
+		return errors.New("failed to create pod")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.pods == nil {
+		c.pods = map[string]k8sPod{}
+	}
+	key := k8sPodKey(pod.Metadata.Namespace, pod.Metadata.Name)
+	if _, ok := c.pods[key]; ok {
+		return errors.Errorf("pod '%s' already exists", key)
+	}
+
+	created := *pod
+	created.Status = k8sPodStatus{
+		Phase: c.podPhase,
+		PodIP: c.podIP,
+	}
+	if created.Status.Phase == "" {
+		created.Status.Phase = k8sPodPhaseRunning
+	}
+	c.pods[key] = created
+
+	return nil
+}
+
+func (c *kubernetesClientMock) GetPod(_ context.Context, namespace, name string) (*k8sPod, error) {
+	if c.failGet {
+		return nil, errors.New("failed to get pod")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pod, ok := c.pods[k8sPodKey(namespace, name)]
+	if !ok {
+		return nil, errors.Errorf("pod '%s' not found", k8sPodKey(namespace, name))
+	}
+	return &pod, nil
+}
+
+func (c *kubernetesClientMock) DeletePod(_ context.Context, namespace, name string) error {
+	if c.failDelete {
+		return errors.New("failed to delete pod")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := k8sPodKey(namespace, name)
+	if _, ok := c.pods[key]; !ok {
+		return errors.Errorf("pod '%s' not found", key)
+	}
+	delete(c.pods, key)
+
+	return nil
+}
+
+func (c *kubernetesClientMock) ListPods(_ context.Context, namespace string, labels map[string]string) ([]k8sPod, error) {
+	if c.failList {
+		return nil, errors.New("failed to list pods")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pods := []k8sPod{}
+	for _, pod := range c.pods {
+		if pod.Metadata.Namespace != namespace {
+			continue
+		}
+		matches := true
+		for k, v := range labels {
+			if pod.Metadata.Labels[k] != v {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+func (c *kubernetesClientMock) CreateSecret(_ context.Context, secret *k8sSecret) error {
+	if c.failCreateSecret {
+		return errors.New("failed to create secret")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.secrets == nil {
+		c.secrets = map[string]k8sSecret{}
+	}
+	key := k8sPodKey(secret.Metadata.Namespace, secret.Metadata.Name)
+	if _, ok := c.secrets[key]; ok {
+		return errors.Errorf("secret '%s' already exists", key)
+	}
+	c.secrets[key] = *secret
+
+	return nil
+}
+
+func (c *kubernetesClientMock) DeleteSecret(_ context.Context, namespace, name string) error {
+	if c.failDeleteSecret {
+		return errors.New("failed to delete secret")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := k8sPodKey(namespace, name)
+	if _, ok := c.secrets[key]; !ok {
+		return errors.Errorf("secret '%s' not found", key)
+	}
+	delete(c.secrets, key)
+
+	return nil
+}
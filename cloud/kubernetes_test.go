@@ -0,0 +1,281 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type KubernetesSuite struct {
+	client  *kubernetesClientMock
+	manager *kubernetesManager
+	distro  distro.Distro
+	pool    *evergreen.ContainerPool
+	suite.Suite
+}
+
+func TestKubernetesSuite(t *testing.T) {
+	suite.Run(t, new(KubernetesSuite))
+}
+
+func (s *KubernetesSuite) SetupSuite() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+}
+
+func (s *KubernetesSuite) SetupTest() {
+	s.NoError(db.Clear(host.Collection))
+	s.client = &kubernetesClientMock{podIP: "10.0.0.1"}
+	s.manager = &kubernetesManager{client: s.client}
+	s.distro = distro.Distro{
+		Id:       "d",
+		Provider: evergreen.ProviderNameKubernetes,
+		ProviderSettings: &map[string]interface{}{
+			"image":  "evergreen/agent:latest",
+			"cpu":    "500m",
+			"memory": "1Gi",
+		},
+		WorkDir: "/data/mci",
+	}
+	s.pool = &evergreen.ContainerPool{
+		Id:            "pool",
+		Namespace:     "builds",
+		MaxContainers: 10,
+	}
+}
+
+func (s *KubernetesSuite) TearDownTest() {
+	s.NoError(db.Clear(host.Collection))
+}
+
+func (s *KubernetesSuite) newHost() *host.Host {
+	h := NewIntent(s.distro, "pod-1", evergreen.ProviderNameKubernetes, HostOptions{
+		UserName:              evergreen.User,
+		ContainerPoolSettings: s.pool,
+	})
+	s.Require().NoError(h.Insert())
+	return h
+}
+
+func (s *KubernetesSuite) TestValidateSettings() {
+	s.NoError((&kubernetesSettings{Image: "image"}).Validate())
+	s.EqualError((&kubernetesSettings{}).Validate(), "Image must not be blank")
+}
+
+func (s *KubernetesSuite) TestConfigure() {
+	settings := &evergreen.Settings{
+		ApiUrl: "https://evergreen.example.com",
+		Providers: evergreen.CloudProviders{
+			Kubernetes: evergreen.KubernetesConfig{Namespace: "default-ns"},
+		},
+	}
+	s.NoError(s.manager.Configure(context.Background(), settings))
+	s.Equal("https://evergreen.example.com", s.manager.apiURL)
+	s.Equal("default-ns", s.manager.defaultNamespace)
+
+	s.client.failInit = true
+	s.Error(s.manager.Configure(context.Background(), settings))
+
+	s.Error((&kubernetesManager{}).Configure(context.Background(), &evergreen.Settings{}))
+}
+
+func (s *KubernetesSuite) TestNamespace() {
+	h := &host.Host{Id: "h"}
+	settings := &kubernetesSettings{Image: "image"}
+
+	_, err := s.manager.namespace(h, settings)
+	s.Error(err)
+
+	s.manager.defaultNamespace = "default-ns"
+	ns, err := s.manager.namespace(h, settings)
+	s.NoError(err)
+	s.Equal("default-ns", ns)
+
+	h.ContainerPoolSettings = s.pool
+	ns, err = s.manager.namespace(h, settings)
+	s.NoError(err)
+	s.Equal("builds", ns)
+
+	settings.Namespace = "distro-ns"
+	ns, err = s.manager.namespace(h, settings)
+	s.NoError(err)
+	s.Equal("distro-ns", ns)
+}
+
+func (s *KubernetesSuite) TestSpawnHost() {
+	h := s.newHost()
+
+	spawned, err := s.manager.SpawnHost(context.Background(), h)
+	s.Require().NoError(err)
+	s.NotEmpty(spawned.Secret)
+
+	pod, ok := s.client.pods[k8sPodKey("builds", h.Id)]
+	s.Require().True(ok)
+	s.Equal("pool", pod.Metadata.Labels[k8sContainerPoolLabel])
+	s.Require().Len(pod.Spec.Containers, 1)
+	s.Equal("evergreen/agent:latest", pod.Spec.Containers[0].Image)
+	s.Equal("500m", pod.Spec.Containers[0].Resources.Limits["cpu"])
+	s.Equal("1Gi", pod.Spec.Containers[0].Resources.Limits["memory"])
+	s.Contains(pod.Spec.Containers[0].Command, "--host_id="+h.Id)
+
+	// the host's secret is only passed through a Kubernetes secret
+	for _, arg := range pod.Spec.Containers[0].Command {
+		s.NotContains(arg, spawned.Secret)
+	}
+	secret, ok := s.client.secrets[k8sPodKey("builds", h.Id)]
+	s.Require().True(ok)
+	s.Equal(spawned.Secret, secret.StringData[k8sHostSecretKey])
+	s.Require().Len(pod.Spec.Containers[0].Env, 1)
+	env := pod.Spec.Containers[0].Env[0]
+	s.Equal(evergreen.HostSecretEnv, env.Name)
+	s.Empty(env.Value)
+	s.Require().NotNil(env.ValueFrom)
+	s.Equal(&k8sSecretKeySelector{Name: h.Id, Key: k8sHostSecretKey}, env.ValueFrom.SecretKeyRef)
+
+	dbHost, err := host.FindOne(host.ById(h.Id))
+	s.NoError(err)
+	s.True(dbHost.Provisioned)
+}
+
+func (s *KubernetesSuite) TestSpawnHostFailures() {
+	h := s.newHost()
+	h.Distro.Provider = evergreen.ProviderNameDocker
+	_, err := s.manager.SpawnHost(context.Background(), h)
+	s.Error(err)
+
+	h = s.newHost()
+	s.client.failCreateSecret = true
+	_, err = s.manager.SpawnHost(context.Background(), h)
+	s.Error(err)
+	s.Empty(s.client.pods)
+
+	// the secret of a pod that could not be created is removed
+	s.client.failCreateSecret = false
+	s.client.failCreate = true
+	_, err = s.manager.SpawnHost(context.Background(), h)
+	s.Error(err)
+	s.Empty(s.client.secrets)
+}
+
+func (s *KubernetesSuite) TestInstanceStatusAndDNSName() {
+	h := s.newHost()
+	_, err := s.manager.GetInstanceStatus(context.Background(), h)
+	s.Error(err)
+
+	_, err = s.manager.SpawnHost(context.Background(), h)
+	s.Require().NoError(err)
+
+	status, err := s.manager.GetInstanceStatus(context.Background(), h)
+	s.NoError(err)
+	s.Equal(StatusRunning, status)
+
+	up, err := s.manager.IsUp(context.Background(), h)
+	s.NoError(err)
+	s.True(up)
+
+	name, err := s.manager.GetDNSName(context.Background(), h)
+	s.NoError(err)
+	s.Equal("10.0.0.1", name)
+}
+
+func (s *KubernetesSuite) TestTerminateInstance() {
+	h := s.newHost()
+	_, err := s.manager.SpawnHost(context.Background(), h)
+	s.Require().NoError(err)
+
+	s.NoError(s.manager.TerminateInstance(context.Background(), h, evergreen.User))
+	s.Empty(s.client.pods)
+	s.Empty(s.client.secrets)
+
+	dbHost, err := host.FindOne(host.ById(h.Id))
+	s.NoError(err)
+	s.Equal(evergreen.HostTerminated, dbHost.Status)
+
+	s.Error(s.manager.TerminateInstance(context.Background(), dbHost, evergreen.User))
+}
+
+func (s *KubernetesSuite) TestGetContainers() {
+	h := s.newHost()
+	_, err := s.manager.SpawnHost(context.Background(), h)
+	s.Require().NoError(err)
+
+	ids, err := s.manager.GetContainers(context.Background(), h)
+	s.NoError(err)
+	s.Equal([]string{h.Id}, ids)
+
+	_, err = s.manager.GetContainers(context.Background(), &host.Host{Id: "parent"})
+	s.Error(err)
+
+	s.client.failList = true
+	_, err = s.manager.GetContainers(context.Background(), h)
+	s.Error(err)
+}
+
+func TestKubernetesClientImpl(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var lastReq *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastReq = r
+		switch {
+		case r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/namespaces/ns/pods/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/namespaces/ns/pods/p1":
+			assert.NoError(json.NewEncoder(w).Encode(k8sPod{
+				Metadata: k8sObjectMeta{Name: "p1", Namespace: "ns"},
+				Status:   k8sPodStatus{Phase: k8sPodPhasePending},
+			}))
+		case r.Method == http.MethodGet:
+			assert.NoError(json.NewEncoder(w).Encode(k8sPodList{
+				Items: []k8sPod{{Metadata: k8sObjectMeta{Name: "p1"}}},
+			}))
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	client := &kubernetesClientImpl{}
+	assert.Error(client.Init(evergreen.KubernetesConfig{}))
+	require.NoError(client.Init(evergreen.KubernetesConfig{APIServer: server.URL + "/", Token: "token"}))
+
+	ctx := context.Background()
+	assert.NoError(client.CreatePod(ctx, &k8sPod{Metadata: k8sObjectMeta{Name: "p1", Namespace: "ns"}}))
+	assert.Equal("/api/v1/namespaces/ns/pods", lastReq.URL.Path)
+	assert.Equal("Bearer token", lastReq.Header.Get("Authorization"))
+
+	pod, err := client.GetPod(ctx, "ns", "p1")
+	require.NoError(err)
+	assert.Equal(StatusInitializing, k8sPhaseToEvgStatus(pod.Status.Phase))
+
+	_, err = client.GetPod(ctx, "ns", "missing")
+	assert.Error(err)
+
+	pods, err := client.ListPods(ctx, "ns", map[string]string{"b": "2", "a": "1"})
+	require.NoError(err)
+	assert.Len(pods, 1)
+	assert.Equal("a=1,b=2", lastReq.URL.Query().Get("labelSelector"))
+
+	assert.NoError(client.DeletePod(ctx, "ns", "p1"))
+	assert.Equal(http.MethodDelete, lastReq.Method)
+
+	assert.NoError(client.CreateSecret(ctx, &k8sSecret{Metadata: k8sObjectMeta{Name: "p1", Namespace: "ns"}}))
+	assert.Equal("/api/v1/namespaces/ns/secrets", lastReq.URL.Path)
+
+	assert.NoError(client.DeleteSecret(ctx, "ns", "p1"))
+	assert.Equal(http.MethodDelete, lastReq.Method)
+	assert.Equal("/api/v1/namespaces/ns/secrets/p1", lastReq.URL.Path)
+}
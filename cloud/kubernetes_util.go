@@ -0,0 +1,189 @@
+package cloud
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/host"
+)
+
+const (
+	k8sHostIDLabel        = "evergreen-host-id"
+	k8sContainerPoolLabel = "evergreen-container-pool"
+	k8sAgentContainerName = "evergreen-agent"
+	k8sHostSecretKey      = "host_secret"
+
+	k8sPodPhasePending   = "Pending"
+	k8sPodPhaseRunning   = "Running"
+	k8sPodPhaseSucceeded = "Succeeded"
+	k8sPodPhaseFailed    = "Failed"
+)
+
+// k8sPod is the subset of the Kubernetes v1 Pod resource that Evergreen
+// reads and writes.
+type k8sPod struct {
+	APIVersion string        `json:"apiVersion,omitempty"`
+	Kind       string        `json:"kind,omitempty"`
+	Metadata   k8sObjectMeta `json:"metadata"`
+	Spec       k8sPodSpec    `json:"spec"`
+	Status     k8sPodStatus  `json:"status,omitempty"`
+}
+
+type k8sObjectMeta struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+type k8sPodSpec struct {
+	Containers         []k8sContainer    `json:"containers"`
+	RestartPolicy      string            `json:"restartPolicy,omitempty"`
+	NodeSelector       map[string]string `json:"nodeSelector,omitempty"`
+	ServiceAccountName string            `json:"serviceAccountName,omitempty"`
+}
+
+type k8sContainer struct {
+	Name      string                  `json:"name"`
+	Image     string                  `json:"image"`
+	Command   []string                `json:"command,omitempty"`
+	Env       []k8sEnvVar             `json:"env,omitempty"`
+	Resources k8sResourceRequirements `json:"resources,omitempty"`
+}
+
+type k8sEnvVar struct {
+	Name      string           `json:"name"`
+	Value     string           `json:"value,omitempty"`
+	ValueFrom *k8sEnvVarSource `json:"valueFrom,omitempty"`
+}
+
+type k8sEnvVarSource struct {
+	SecretKeyRef *k8sSecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+type k8sSecretKeySelector struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+}
+
+type k8sResourceRequirements struct {
+	Limits   map[string]string `json:"limits,omitempty"`
+	Requests map[string]string `json:"requests,omitempty"`
+}
+
+type k8sPodStatus struct {
+	Phase  string `json:"phase,omitempty"`
+	PodIP  string `json:"podIP,omitempty"`
+	HostIP string `json:"hostIP,omitempty"`
+}
+
+type k8sPodList struct {
+	Items []k8sPod `json:"items"`
+}
+
+// k8sSecret is the subset of the Kubernetes v1 Secret resource that
+// Evergreen writes.
+type k8sSecret struct {
+	APIVersion string            `json:"apiVersion,omitempty"`
+	Kind       string            `json:"kind,omitempty"`
+	Metadata   k8sObjectMeta     `json:"metadata"`
+	Type       string            `json:"type,omitempty"`
+	StringData map[string]string `json:"stringData,omitempty"`
+}
+
+// k8sPhaseToEvgStatus converts a pod phase to an Evergreen cloud provider status.
+func k8sPhaseToEvgStatus(phase string) CloudStatus {
+	switch phase {
+	case k8sPodPhasePending:
+		return StatusInitializing
+	case k8sPodPhaseRunning:
+		return StatusRunning
+	case k8sPodPhaseSucceeded:
+		return StatusTerminated
+	case k8sPodPhaseFailed:
+		return StatusFailed
+	default:
+		return StatusUnknown
+	}
+}
+
+// k8sHostLabels returns the labels of the resources created for the host.
+func k8sHostLabels(h *host.Host) map[string]string {
+	labels := map[string]string{
+		k8sHostIDLabel: h.Id,
+	}
+	if h.ContainerPoolSettings != nil {
+		labels[k8sContainerPoolLabel] = h.ContainerPoolSettings.Id
+	}
+	return labels
+}
+
+// makeK8sSecret builds the secret that holds the host's secret, so that it
+// is not visible in the pod's spec.
+func makeK8sSecret(h *host.Host, namespace string) *k8sSecret {
+	return &k8sSecret{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Metadata: k8sObjectMeta{
+			Name:      h.Id,
+			Namespace: namespace,
+			Labels:    k8sHostLabels(h),
+		},
+		Type:       "Opaque",
+		StringData: map[string]string{k8sHostSecretKey: h.Secret},
+	}
+}
+
+// makeK8sPod builds the pod that runs the agent for the given container host.
+// The agent reads the host's secret from the secret made by makeK8sSecret.
+func makeK8sPod(h *host.Host, settings *kubernetesSettings, namespace, apiURL string) *k8sPod {
+	resources := k8sResourceRequirements{}
+	if settings.CPU != "" || settings.Memory != "" {
+		resources.Limits = map[string]string{}
+		if settings.CPU != "" {
+			resources.Limits["cpu"] = settings.CPU
+		}
+		if settings.Memory != "" {
+			resources.Limits["memory"] = settings.Memory
+		}
+	}
+
+	return &k8sPod{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Metadata: k8sObjectMeta{
+			Name:      h.Id,
+			Namespace: namespace,
+			Labels:    k8sHostLabels(h),
+		},
+		Spec: k8sPodSpec{
+			Containers: []k8sContainer{
+				{
+					Name:  k8sAgentContainerName,
+					Image: settings.Image,
+					Command: []string{
+						settings.AgentPath,
+						"agent",
+						fmt.Sprintf("--api_server=%s", apiURL),
+						fmt.Sprintf("--host_id=%s", h.Id),
+						fmt.Sprintf("--log_prefix=%s", filepath.Join(h.Distro.WorkDir, "agent")),
+						fmt.Sprintf("--working_directory=%s", h.Distro.WorkDir),
+						"--cleanup",
+					},
+					Env: []k8sEnvVar{
+						{
+							Name: evergreen.HostSecretEnv,
+							ValueFrom: &k8sEnvVarSource{
+								SecretKeyRef: &k8sSecretKeySelector{Name: h.Id, Key: k8sHostSecretKey},
+							},
+						},
+					},
+					Resources: resources,
+				},
+			},
+			RestartPolicy:      "Never",
+			NodeSelector:       settings.NodeSelector,
+			ServiceAccountName: settings.ServiceAccount,
+		},
+	}
+}
@@ -0,0 +1,455 @@
+package command
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/goamz/goamz/s3"
+	"github.com/mitchellh/mapstructure"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	cacheDirectory     = "cache"
+	cacheFileExtension = ".tar.gz"
+
+	// Caches saved by patches are kept apart from the caches saved by
+	// mainline builds, so that a patch cannot replace a cache that
+	// mainline builds restore.
+	cacheScopeMainline = "mainline"
+	cacheScopePatch    = "patch"
+)
+
+// cacheRemotePath returns the path in the bucket of the project's cache
+// with the key in the scope, or, without the extension, the prefix of the
+// paths of the caches whose keys start with it.
+func cacheRemotePath(project, scope, key string) string {
+	return path.Join(cacheDirectory, project, scope, key)
+}
+
+// cacheSaveScope returns the scope that tasks with the requester save
+// caches to.
+func cacheSaveScope(requester string) string {
+	if evergreen.IsPatchRequester(requester) {
+		return cacheScopePatch
+	}
+	return cacheScopeMainline
+}
+
+// cacheRestoreScopes returns the scopes that tasks with the requester
+// restore caches from, in order. Patches prefer the caches of patches, but
+// can also restore the caches of mainline builds.
+func cacheRestoreScopes(requester string) []string {
+	if evergreen.IsPatchRequester(requester) {
+		return []string{cacheScopePatch, cacheScopeMainline}
+	}
+	return []string{cacheScopeMainline}
+}
+
+// validateCacheKey checks that the key, once cleaned, names a path within
+// the project's caches, so that a task cannot read or write the caches of
+// other projects.
+func validateCacheKey(key string) error {
+	if key == "" {
+		return errors.New("key cannot be blank")
+	}
+	clean := path.Clean(key)
+	if clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return errors.Errorf("key '%s' is not within the project's caches", key)
+	}
+	return nil
+}
+
+// cacheSave archives paths in the working directory and uploads the archive
+// to a bucket under a key, so that later tasks can restore them with
+// cache.restore.
+type cacheSave struct {
+	// Key identifies the cache within the project. It usually contains
+	// expansions, e.g. "deps-${build_variant}-${revision}".
+	Key string `mapstructure:"key" plugin:"expand"`
+
+	// Paths are the files to cache, relative to the working directory, as
+	// patterns like the include patterns of archive.targz_pack, e.g.
+	// "node_modules/**". A directory includes all of the files under it.
+	Paths []string `mapstructure:"paths" plugin:"expand"`
+
+	// ExcludeFiles is a list of file name patterns to leave out of the
+	// cache, e.g. "*.log".
+	ExcludeFiles []string `mapstructure:"exclude_files" plugin:"expand"`
+
+	// Overwrite replaces an existing cache with the same key. By default,
+	// caches are never modified once saved. Patches save their caches
+	// apart from mainline builds, so they never replace mainline caches.
+	Overwrite bool `mapstructure:"overwrite"`
+
+	// Bucket, Provider, AwsKey, AwsSecret and Permissions describe where
+	// to store the cache, as for s3.put. Permissions default to private.
+	Bucket      string `mapstructure:"bucket" plugin:"expand"`
+	Provider    string `mapstructure:"provider" plugin:"expand"`
+	AwsKey      string `mapstructure:"aws_key" plugin:"expand"`
+	AwsSecret   string `mapstructure:"aws_secret" plugin:"expand"`
+	Permissions string `mapstructure:"permissions"`
+
+	// providerOverride, if set, replaces Provider, so that local runs
+	// can keep caches in the local storage directory.
+	providerOverride string
+
+	base
+}
+
+func cacheSaveFactory() Command   { return &cacheSave{} }
+func (c *cacheSave) Name() string { return "cache.save" }
+
+func (c *cacheSave) ParseParams(params map[string]interface{}) error {
+	if err := mapstructure.Decode(params, c); err != nil {
+		return errors.Wrapf(err, "error decoding %s params", c.Name())
+	}
+	if c.Permissions == "" {
+		c.Permissions = string(s3.Private)
+	}
+
+	return errors.Wrapf(c.validate(), "error validating %s params", c.Name())
+}
+
+func (c *cacheSave) validate() error {
+	catcher := grip.NewSimpleCatcher()
+	catcher.Add(validateCacheKey(c.Key))
+	if len(c.Paths) == 0 {
+		catcher.Add(errors.New("paths cannot be empty"))
+	}
+	catcher.Add(validateCacheBucket(c.Bucket, c.Provider, c.provider(), c.AwsKey, c.AwsSecret))
+	if c.provider() != evergreen.BucketProviderFilesystem && !validS3Permissions(c.Permissions) {
+		catcher.Add(errors.Errorf("permissions '%s' are not valid", c.Permissions))
+	}
+
+	return catcher.Resolve()
+}
+
+func (c *cacheSave) provider() string {
+	if c.providerOverride != "" {
+		return c.providerOverride
+	}
+	return c.Provider
+}
+
+func (c *cacheSave) Execute(ctx context.Context,
+	comm client.Communicator, logger client.LoggerProducer, conf *model.TaskConfig) error {
+
+	if err := util.ExpandValues(c, conf.Expansions); err != nil {
+		return errors.Wrap(err, "error expanding params")
+	}
+	if err := c.validate(); err != nil {
+		return errors.Wrap(err, "expanded params are not valid")
+	}
+
+	td := client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret}
+	bucket, err := newBucket(ctx, comm, td, thirdparty.BucketOptions{
+		Provider:    c.provider(),
+		Name:        c.Bucket,
+		Key:         c.AwsKey,
+		Secret:      c.AwsSecret,
+		Permissions: c.Permissions,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	scope := cacheSaveScope(conf.Task.Requester)
+	remotePath := cacheRemotePath(conf.Task.Project, scope, c.Key) + cacheFileExtension
+	if !c.Overwrite {
+		var items []thirdparty.BucketItem
+		items, err = bucket.List(ctx, remotePath)
+		if err != nil {
+			return errors.Wrapf(err, "problem checking for an existing cache '%s'", c.Key)
+		}
+		for _, item := range items {
+			if item.Path == remotePath {
+				logger.Task().Info(message.Fields{
+					"message": "cache already exists, not saving it again",
+					"key":     c.Key,
+					"scope":   scope,
+					"size":    item.Size,
+				})
+				return nil
+			}
+		}
+	}
+
+	archive, err := ioutil.TempFile("", "evergreen-cache")
+	if err != nil {
+		return errors.Wrap(err, "problem creating cache archive")
+	}
+	archivePath := archive.Name()
+	defer os.Remove(archivePath)
+	if err = archive.Close(); err != nil {
+		return errors.Wrap(err, "problem creating cache archive")
+	}
+
+	numFiles, err := c.makeArchive(ctx, logger, archivePath, conf.WorkDir)
+	if err != nil {
+		return errors.Wrapf(err, "problem archiving cache '%s'", c.Key)
+	}
+	if numFiles == 0 {
+		logger.Task().Warning(message.Fields{
+			"message": "no files matched the cache paths, not saving the cache",
+			"key":     c.Key,
+			"paths":   c.Paths,
+		})
+		return nil
+	}
+
+	info, err := os.Stat(archivePath)
+	if err != nil {
+		return errors.Wrap(err, "problem finding the size of the cache archive")
+	}
+
+	_, err = util.Retry(func() (bool, error) {
+		if ctx.Err() != nil {
+			return false, errors.New("cache save operation canceled")
+		}
+		if err := bucket.Upload(ctx, archivePath, remotePath, "application/gzip"); err != nil {
+			logger.Execution().Error(errors.Wrap(err, "problem uploading cache, retrying"))
+			return true, err
+		}
+		return false, nil
+	}, maxS3OpAttempts, s3OpSleep)
+	if err != nil {
+		return errors.Wrapf(err, "problem uploading cache '%s'", c.Key)
+	}
+
+	conf.RecordCacheSave(info.Size())
+	logger.Task().Info(message.Fields{
+		"message": "saved cache",
+		"key":     c.Key,
+		"scope":   scope,
+		"files":   numFiles,
+		"size":    info.Size(),
+		"link":    bucket.Link(remotePath),
+	})
+
+	return nil
+}
+
+// makeArchive writes the files matching the cache's paths to a tarball at
+// target, returning the number of files in it.
+func (c *cacheSave) makeArchive(ctx context.Context, logger client.LoggerProducer, target, workDir string) (int, error) {
+	includes := make([]string, 0, len(c.Paths))
+	for _, include := range c.Paths {
+		if info, err := os.Stat(filepath.Join(workDir, include)); err == nil && info.IsDir() {
+			include = filepath.Join(include, "**")
+		}
+		includes = append(includes, include)
+	}
+
+	f, gz, tarWriter, err := util.TarGzWriter(target)
+	if err != nil {
+		return 0, errors.Wrapf(err, "error opening archive file %s", target)
+	}
+
+	// list the archived files in the system log, since caches often
+	// contain too many files to list in the task log
+	numFiles, err := util.BuildArchive(ctx, tarWriter, workDir, includes, c.ExcludeFiles, logger.System())
+
+	catcher := grip.NewSimpleCatcher()
+	catcher.Add(err)
+	catcher.Add(tarWriter.Close())
+	catcher.Add(gz.Close())
+	catcher.Add(f.Close())
+	return numFiles, errors.WithStack(catcher.Resolve())
+}
+
+// cacheRestore downloads and extracts a cache saved by cache.save.
+type cacheRestore struct {
+	// Keys are tried in order until one matches a cache. A key matches
+	// the cache saved with that key, or otherwise the most recently saved
+	// cache whose key starts with it, e.g. "deps-${build_variant}-"
+	// matches the latest dependencies cache of the build variant.
+	Keys []string `mapstructure:"keys" plugin:"expand"`
+
+	// Dir is the directory to extract the cache to, relative to the
+	// working directory. It defaults to the working directory.
+	Dir string `mapstructure:"dir" plugin:"expand"`
+
+	// Bucket, Provider, AwsKey and AwsSecret describe where caches are
+	// stored, as for s3.get.
+	Bucket    string `mapstructure:"bucket" plugin:"expand"`
+	Provider  string `mapstructure:"provider" plugin:"expand"`
+	AwsKey    string `mapstructure:"aws_key" plugin:"expand"`
+	AwsSecret string `mapstructure:"aws_secret" plugin:"expand"`
+
+	// providerOverride, if set, replaces Provider, so that local runs
+	// can restore caches from the local storage directory.
+	providerOverride string
+
+	base
+}
+
+func cacheRestoreFactory() Command   { return &cacheRestore{} }
+func (c *cacheRestore) Name() string { return "cache.restore" }
+
+func (c *cacheRestore) ParseParams(params map[string]interface{}) error {
+	if err := mapstructure.Decode(params, c); err != nil {
+		return errors.Wrapf(err, "error decoding %s params", c.Name())
+	}
+
+	return errors.Wrapf(c.validate(), "error validating %s params", c.Name())
+}
+
+func (c *cacheRestore) validate() error {
+	catcher := grip.NewSimpleCatcher()
+	if len(c.Keys) == 0 {
+		catcher.Add(errors.New("keys cannot be empty"))
+	}
+	for _, key := range c.Keys {
+		catcher.Add(validateCacheKey(key))
+	}
+	catcher.Add(validateCacheBucket(c.Bucket, c.Provider, c.provider(), c.AwsKey, c.AwsSecret))
+
+	return catcher.Resolve()
+}
+
+func (c *cacheRestore) provider() string {
+	if c.providerOverride != "" {
+		return c.providerOverride
+	}
+	return c.Provider
+}
+
+func (c *cacheRestore) Execute(ctx context.Context,
+	comm client.Communicator, logger client.LoggerProducer, conf *model.TaskConfig) error {
+
+	if err := util.ExpandValues(c, conf.Expansions); err != nil {
+		return errors.Wrap(err, "error expanding params")
+	}
+	if err := c.validate(); err != nil {
+		return errors.Wrap(err, "expanded params are not valid")
+	}
+	if !filepath.IsAbs(c.Dir) {
+		c.Dir = filepath.Join(conf.WorkDir, c.Dir)
+	}
+
+	td := client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret}
+	bucket, err := newBucket(ctx, comm, td, thirdparty.BucketOptions{
+		Provider: c.provider(),
+		Name:     c.Bucket,
+		Key:      c.AwsKey,
+		Secret:   c.AwsSecret,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, key := range c.Keys {
+		var match *thirdparty.BucketItem
+		var scope string
+		for _, scope = range cacheRestoreScopes(conf.Task.Requester) {
+			match, err = findCache(ctx, bucket, conf.Task.Project, scope, key)
+			if err != nil {
+				return errors.Wrapf(err, "problem finding cache for key '%s'", key)
+			}
+			if match != nil {
+				break
+			}
+		}
+		if match == nil {
+			continue
+		}
+
+		matchedKey := strings.TrimSuffix(strings.TrimPrefix(match.Path,
+			cacheRemotePath(conf.Task.Project, scope, "")+"/"), cacheFileExtension)
+		logger.Task().Info(message.Fields{
+			"message":     "cache hit",
+			"key":         key,
+			"matched_key": matchedKey,
+			"scope":       scope,
+			"exact":       matchedKey == key,
+			"size":        match.Size,
+		})
+
+		_, err = util.Retry(func() (bool, error) {
+			if ctx.Err() != nil {
+				return false, errors.New("cache restore operation canceled")
+			}
+			if err := c.extract(ctx, bucket, match.Path); err != nil {
+				logger.Execution().Error(errors.Wrap(err, "problem restoring cache, retrying"))
+				return true, err
+			}
+			return false, nil
+		}, maxS3OpAttempts, s3OpSleep)
+		if err != nil {
+			return errors.Wrapf(err, "problem restoring cache '%s'", matchedKey)
+		}
+
+		conf.RecordCacheRestore(true, match.Size)
+		return nil
+	}
+
+	conf.RecordCacheRestore(false, 0)
+	logger.Task().Info(message.Fields{
+		"message": "cache miss",
+		"keys":    c.Keys,
+	})
+	return nil
+}
+
+func (c *cacheRestore) extract(ctx context.Context, bucket thirdparty.Bucket, remotePath string) error {
+	reader, err := bucket.Get(ctx, remotePath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer reader.Close()
+
+	if err = os.MkdirAll(c.Dir, 0755); err != nil {
+		return errors.Wrapf(err, "problem creating directory %s", c.Dir)
+	}
+	return errors.WithStack(util.ExtractTarball(ctx, reader, c.Dir, []string{}))
+}
+
+// findCache returns the project's cache in the scope with the key if it
+// exists, or otherwise the most recently saved cache in the scope whose key
+// starts with it, or nil if there is no such cache.
+func findCache(ctx context.Context, bucket thirdparty.Bucket, project, scope, key string) (*thirdparty.BucketItem, error) {
+	exact := cacheRemotePath(project, scope, key) + cacheFileExtension
+	items, err := bucket.List(ctx, cacheRemotePath(project, scope, key))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var latest *thirdparty.BucketItem
+	for i := range items {
+		item := items[i]
+		if !strings.HasSuffix(item.Path, cacheFileExtension) {
+			continue
+		}
+		if item.Path == exact {
+			return &item, nil
+		}
+		if latest == nil || item.LastModified.After(latest.LastModified) {
+			latest = &item
+		}
+	}
+	return latest, nil
+}
+
+// validateCacheBucket checks the parameters cache commands use to find the
+// bucket of caches.
+func validateCacheBucket(bucket, provider, effectiveProvider, key, secret string) error {
+	catcher := grip.NewSimpleCatcher()
+	catcher.Add(validateBucketProvider(provider))
+	catcher.Add(validateBucketCredentials(effectiveProvider, key, secret))
+	if err := validateS3BucketName(bucket); err != nil {
+		catcher.Add(errors.Wrapf(err, "%s is an invalid bucket name", bucket))
+	}
+	return catcher.Resolve()
+}
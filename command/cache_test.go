@@ -0,0 +1,247 @@
+package command
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheParseParams(t *testing.T) {
+	assert := assert.New(t)
+
+	save := &cacheSave{}
+	assert.Error(save.ParseParams(map[string]interface{}{
+		"paths":      []string{"deps"},
+		"bucket":     "caches",
+		"aws_key":    "key",
+		"aws_secret": "secret",
+	}), "key is required")
+	save = &cacheSave{}
+	assert.Error(save.ParseParams(map[string]interface{}{
+		"key":    "deps",
+		"paths":  []string{"deps"},
+		"bucket": "caches",
+	}), "s3 caches need credentials")
+
+	save = &cacheSave{}
+	assert.NoError(save.ParseParams(map[string]interface{}{
+		"key":        "deps-${revision}",
+		"paths":      []string{"deps"},
+		"bucket":     "caches",
+		"aws_key":    "key",
+		"aws_secret": "secret",
+	}))
+	assert.Equal("private", save.Permissions)
+
+	for _, key := range []string{"..", "../other/deps", "deps/../../other/deps", "."} {
+		save = &cacheSave{}
+		assert.Error(save.ParseParams(map[string]interface{}{
+			"key":      key,
+			"paths":    []string{"deps"},
+			"bucket":   "caches",
+			"provider": evergreen.BucketProviderFilesystem,
+		}), key)
+	}
+
+	restore := &cacheRestore{}
+	assert.Error(restore.ParseParams(map[string]interface{}{
+		"bucket":   "caches",
+		"provider": evergreen.BucketProviderFilesystem,
+	}), "keys are required")
+	restore = &cacheRestore{}
+	assert.Error(restore.ParseParams(map[string]interface{}{
+		"keys":     []string{"deps"},
+		"bucket":   "caches",
+		"provider": "floppy",
+	}))
+	restore = &cacheRestore{}
+	assert.Error(restore.ParseParams(map[string]interface{}{
+		"keys":     []string{"deps-a", "../other/deps-"},
+		"bucket":   "caches",
+		"provider": evergreen.BucketProviderFilesystem,
+	}), "keys must stay within the project's caches")
+	restore = &cacheRestore{}
+	assert.NoError(restore.ParseParams(map[string]interface{}{
+		"keys":     []string{"deps-a", "deps-"},
+		"bucket":   "caches",
+		"provider": evergreen.BucketProviderFilesystem,
+	}))
+}
+
+func TestCacheSaveAndRestore(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir, err := ioutil.TempDir("", "cache-commands")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	workDir := filepath.Join(dir, "work")
+	require.NoError(os.MkdirAll(filepath.Join(workDir, "deps", "lib"), 0755))
+	require.NoError(ioutil.WriteFile(filepath.Join(workDir, "deps", "lib", "a.txt"), []byte("a"), 0644))
+	require.NoError(ioutil.WriteFile(filepath.Join(workDir, "deps", "b.log"), []byte("b"), 0644))
+
+	comm := client.NewMock("http://localhost.com")
+	comm.BucketsConfig.Filesystem.Root = filepath.Join(dir, "store")
+	conf := &model.TaskConfig{
+		Expansions: util.NewExpansions(map[string]string{"revision": "abc"}),
+		Task:       &task.Task{Id: "task", Project: "project"},
+		WorkDir:    workDir,
+	}
+	logger := comm.GetLoggerProducer(ctx, client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret})
+
+	save := &cacheSave{}
+	require.NoError(save.ParseParams(map[string]interface{}{
+		"key":           "deps-${revision}",
+		"paths":         []string{"deps"},
+		"exclude_files": []string{"*.log"},
+		"bucket":        "caches",
+		"provider":      evergreen.BucketProviderFilesystem,
+	}))
+	require.NoError(save.Execute(ctx, comm, logger, conf))
+	_, err = os.Stat(filepath.Join(dir, "store", "caches", "cache", "project", cacheScopeMainline, "deps-abc.tar.gz"))
+	require.NoError(err)
+
+	stats := conf.GetCacheStats()
+	require.NotNil(stats)
+	assert.Equal(1, stats.Saves)
+	assert.True(stats.BytesSaved > 0)
+
+	// caches are not saved again unless overwrite is set
+	require.NoError(save.Execute(ctx, comm, logger, conf))
+	assert.Equal(1, conf.GetCacheStats().Saves)
+
+	restore := &cacheRestore{}
+	require.NoError(restore.ParseParams(map[string]interface{}{
+		"keys":     []string{"deps-${revision}"},
+		"dir":      "restored",
+		"bucket":   "caches",
+		"provider": evergreen.BucketProviderFilesystem,
+	}))
+	require.NoError(restore.Execute(ctx, comm, logger, conf))
+	data, err := ioutil.ReadFile(filepath.Join(workDir, "restored", "deps", "lib", "a.txt"))
+	require.NoError(err)
+	assert.Equal("a", string(data))
+	_, err = os.Stat(filepath.Join(workDir, "restored", "deps", "b.log"))
+	assert.True(os.IsNotExist(err), "excluded files should not be cached")
+
+	// a key that matches no cache falls back to the next key's prefix
+	restore = &cacheRestore{}
+	require.NoError(restore.ParseParams(map[string]interface{}{
+		"keys":     []string{"deps-def", "deps-"},
+		"dir":      "prefixed",
+		"bucket":   "caches",
+		"provider": evergreen.BucketProviderFilesystem,
+	}))
+	require.NoError(restore.Execute(ctx, comm, logger, conf))
+	_, err = os.Stat(filepath.Join(workDir, "prefixed", "deps", "lib", "a.txt"))
+	assert.NoError(err)
+
+	// keys that expand to paths outside the project's caches are rejected
+	conf.Expansions.Put("other", "../other/deps-abc")
+	restore = &cacheRestore{}
+	require.NoError(restore.ParseParams(map[string]interface{}{
+		"keys":     []string{"${other}"},
+		"bucket":   "caches",
+		"provider": evergreen.BucketProviderFilesystem,
+	}))
+	assert.Error(restore.Execute(ctx, comm, logger, conf))
+
+	// misses are not errors
+	restore = &cacheRestore{}
+	require.NoError(restore.ParseParams(map[string]interface{}{
+		"keys":     []string{"tools-"},
+		"bucket":   "caches",
+		"provider": evergreen.BucketProviderFilesystem,
+	}))
+	require.NoError(restore.Execute(ctx, comm, logger, conf))
+
+	stats = conf.GetCacheStats()
+	assert.Equal(2, stats.Hits)
+	assert.Equal(1, stats.Misses)
+	assert.Equal(2*stats.BytesSaved, stats.BytesRestored)
+}
+
+func TestCachePatchesDoNotReplaceMainlineCaches(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir, err := ioutil.TempDir("", "cache-commands")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	workDir := filepath.Join(dir, "work")
+	require.NoError(os.MkdirAll(filepath.Join(workDir, "deps"), 0755))
+	depsFile := filepath.Join(workDir, "deps", "a.txt")
+
+	comm := client.NewMock("http://localhost.com")
+	comm.BucketsConfig.Filesystem.Root = filepath.Join(dir, "store")
+	conf := &model.TaskConfig{
+		Expansions: util.NewExpansions(map[string]string{}),
+		Task:       &task.Task{Id: "task", Project: "project", Requester: evergreen.RepotrackerVersionRequester},
+		WorkDir:    workDir,
+	}
+	logger := comm.GetLoggerProducer(ctx, client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret})
+
+	save := func(content string) {
+		require.NoError(ioutil.WriteFile(depsFile, []byte(content), 0644))
+		cmd := &cacheSave{}
+		require.NoError(cmd.ParseParams(map[string]interface{}{
+			"key":       "deps",
+			"paths":     []string{"deps"},
+			"overwrite": true,
+			"bucket":    "caches",
+			"provider":  evergreen.BucketProviderFilesystem,
+		}))
+		require.NoError(cmd.Execute(ctx, comm, logger, conf))
+	}
+	restore := func(key string) string {
+		require.NoError(os.RemoveAll(filepath.Join(workDir, "restored")))
+		cmd := &cacheRestore{}
+		require.NoError(cmd.ParseParams(map[string]interface{}{
+			"keys":     []string{key},
+			"dir":      "restored",
+			"bucket":   "caches",
+			"provider": evergreen.BucketProviderFilesystem,
+		}))
+		require.NoError(cmd.Execute(ctx, comm, logger, conf))
+		data, err := ioutil.ReadFile(filepath.Join(workDir, "restored", "deps", "a.txt"))
+		if os.IsNotExist(err) {
+			return ""
+		}
+		require.NoError(err)
+		return string(data)
+	}
+
+	save("mainline")
+
+	// patches restore mainline caches until they save their own
+	conf.Task.Requester = evergreen.PatchVersionRequester
+	assert.Equal("mainline", restore("deps"))
+
+	save("patch")
+	assert.Equal("patch", restore("deps"))
+	_, err = os.Stat(filepath.Join(dir, "store", "caches", "cache", "project", cacheScopePatch, "deps.tar.gz"))
+	assert.NoError(err)
+
+	// mainline builds never restore the caches of patches
+	conf.Task.Requester = evergreen.RepotrackerVersionRequester
+	assert.Equal("mainline", restore("deps"))
+
+	require.NoError(os.Remove(filepath.Join(dir, "store", "caches", "cache", "project", cacheScopeMainline, "deps.tar.gz")))
+	assert.Equal("", restore("deps"))
+}
@@ -0,0 +1,27 @@
+package command
+
+import (
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+)
+
+// RenderLocal renders commands like Render, but replaces the commands that
+// read and write remote storage with commands that use filesystem buckets,
+// which the local communicator keeps in its local storage directory, so that
+// tasks can run without credentials or network access. Commands that talk to
+// the API server are handled by the communicator the commands run with.
+func RenderLocal(c model.PluginCommandConf, fns map[string]*model.YAMLCommandSet) ([]Command, error) {
+	registry := newCommandRegistry()
+	evgRegistry.mu.RLock()
+	for name, factory := range evgRegistry.cmds {
+		registry.cmds[name] = factory
+	}
+	evgRegistry.mu.RUnlock()
+
+	registry.cmds["s3.put"] = func() Command { return &s3put{providerOverride: evergreen.BucketProviderFilesystem} }
+	registry.cmds["s3.get"] = func() Command { return &s3get{providerOverride: evergreen.BucketProviderFilesystem} }
+	registry.cmds["cache.save"] = func() Command { return &cacheSave{providerOverride: evergreen.BucketProviderFilesystem} }
+	registry.cmds["cache.restore"] = func() Command { return &cacheRestore{providerOverride: evergreen.BucketProviderFilesystem} }
+
+	return registry.renderCommands(c, fns)
+}
@@ -26,6 +26,12 @@ func init() {
 		"attach.results":                attachResultsFactory,
 		"attach.xunit_results":          xunitResultsFactory,
 		"attach.artifacts":              attachArtifactsFactory,
+		"attach.cucumber_results":       cucumberResultsFactory,
+		"attach.mocha_results":          mochaResultsFactory,
+		"attach.pytest_results":         pytestResultsFactory,
+		"attach.tap_results":            tapResultsFactory,
+		"cache.restore":                 cacheRestoreFactory,
+		"cache.save":                    cacheSaveFactory,
 		evergreen.CreateHostCommandName: createHostFactory,
 		"host.list":                     listHostFactory,
 		"expansions.fetch_vars":         fetchVarsFactory,
@@ -0,0 +1,115 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/pkg/errors"
+)
+
+type cucumberFeature struct {
+	URI      string            `json:"uri"`
+	Name     string            `json:"name"`
+	Elements []cucumberElement `json:"elements"`
+}
+
+type cucumberElement struct {
+	Name    string         `json:"name"`
+	Type    string         `json:"type"`
+	Keyword string         `json:"keyword"`
+	Line    int            `json:"line"`
+	Steps   []cucumberStep `json:"steps"`
+}
+
+type cucumberStep struct {
+	Keyword string `json:"keyword"`
+	Name    string `json:"name"`
+	Result  struct {
+		Status string `json:"status"`
+		// Duration is in nanoseconds.
+		Duration     int64  `json:"duration"`
+		ErrorMessage string `json:"error_message"`
+	} `json:"result"`
+}
+
+// parseCucumberResults reads the results of a Cucumber JSON report. Each
+// scenario is a test, which fails if any of its steps, including the steps
+// of the feature's background, failed, passes if all of them passed, and is
+// otherwise skipped, e.g. when a step is pending or undefined.
+func parseCucumberResults(reader io.Reader) ([]reportTestResult, error) {
+	features := []cucumberFeature{}
+	if err := json.NewDecoder(reader).Decode(&features); err != nil {
+		return nil, errors.Wrap(err, "problem decoding Cucumber report")
+	}
+
+	results := []reportTestResult{}
+	for _, feature := range features {
+		featureName := feature.Name
+		if featureName == "" {
+			featureName = feature.URI
+		}
+
+		for i, element := range feature.Elements {
+			if element.Type == "background" {
+				continue
+			}
+
+			steps := element.Steps
+			// reports repeat the background before each scenario instead
+			// of including its steps in the scenario
+			if i > 0 && feature.Elements[i-1].Type == "background" {
+				steps = append(append([]cucumberStep{}, feature.Elements[i-1].Steps...), steps...)
+			}
+
+			name := element.Name
+			if name == "" {
+				name = fmt.Sprintf("line %d", element.Line)
+			}
+			results = append(results, newCucumberResult(featureName+": "+name, steps))
+		}
+	}
+
+	return results, nil
+}
+
+func newCucumberResult(name string, steps []cucumberStep) reportTestResult {
+	result := reportTestResult{Name: name}
+
+	passed, failed := len(steps) > 0, false
+	for _, step := range steps {
+		result.Duration += time.Duration(step.Result.Duration)
+		status := step.Result.Status
+		switch status {
+		case "passed":
+		case "failed", "ambiguous":
+			failed = true
+		default:
+			passed = false
+		}
+
+		result.Output = append(result.Output,
+			fmt.Sprintf("%s%s [%s]", step.Keyword, step.Name, status))
+		if msg := strings.TrimRight(step.Result.ErrorMessage, "\n"); msg != "" {
+			result.Output = append(result.Output, strings.Split(msg, "\n")...)
+		}
+	}
+
+	switch {
+	case failed:
+		result.Status = evergreen.TestFailedStatus
+	case passed:
+		result.Status = evergreen.TestSucceededStatus
+	default:
+		result.Status = evergreen.TestSkippedStatus
+	}
+	if result.Status == evergreen.TestSucceededStatus {
+		// only keep the steps of scenarios that did not pass
+		result.Output = nil
+	}
+
+	return result
+}
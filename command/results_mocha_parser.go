@@ -0,0 +1,76 @@
+package command
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/pkg/errors"
+)
+
+// mochaReport is the report of Mocha's JSON reporter.
+type mochaReport struct {
+	Tests   []mochaTest `json:"tests"`
+	Pending []mochaTest `json:"pending"`
+}
+
+type mochaTest struct {
+	Title     string `json:"title"`
+	FullTitle string `json:"fullTitle"`
+	File      string `json:"file"`
+	// Duration is in milliseconds.
+	Duration float64 `json:"duration"`
+	// Pending is only set by newer versions of Mocha; older versions only
+	// list pending tests in the report's pending tests.
+	Pending bool `json:"pending"`
+	Err     struct {
+		Message string `json:"message"`
+		Stack   string `json:"stack"`
+	} `json:"err"`
+}
+
+// parseMochaResults reads the results of a Mocha JSON report, as written by
+// mocha --reporter json.
+func parseMochaResults(reader io.Reader) ([]reportTestResult, error) {
+	report := mochaReport{}
+	if err := json.NewDecoder(reader).Decode(&report); err != nil {
+		return nil, errors.Wrap(err, "problem decoding Mocha report")
+	}
+
+	pending := map[string]bool{}
+	for _, test := range report.Pending {
+		pending[test.FullTitle] = true
+	}
+
+	results := make([]reportTestResult, 0, len(report.Tests))
+	for _, test := range report.Tests {
+		result := reportTestResult{
+			Name:     test.FullTitle,
+			Duration: time.Duration(test.Duration * float64(time.Millisecond)),
+		}
+		if result.Name == "" {
+			result.Name = test.Title
+		}
+
+		switch {
+		case test.Pending || pending[test.FullTitle]:
+			result.Status = evergreen.TestSkippedStatus
+		case test.Err.Message != "" || test.Err.Stack != "":
+			result.Status = evergreen.TestFailedStatus
+			// the stack of an error starts with its message
+			output := test.Err.Stack
+			if !strings.Contains(output, test.Err.Message) {
+				output = test.Err.Message + "\n" + output
+			}
+			result.Output = strings.Split(strings.TrimRight(output, "\n"), "\n")
+		default:
+			result.Status = evergreen.TestSucceededStatus
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
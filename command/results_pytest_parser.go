@@ -0,0 +1,99 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/pkg/errors"
+)
+
+// pytestReport is the report of the pytest-json-report plugin.
+type pytestReport struct {
+	Tests []pytestTest `json:"tests"`
+}
+
+type pytestTest struct {
+	NodeID   string           `json:"nodeid"`
+	Outcome  string           `json:"outcome"`
+	Setup    *pytestTestStage `json:"setup"`
+	Call     *pytestTestStage `json:"call"`
+	Teardown *pytestTestStage `json:"teardown"`
+}
+
+type pytestTestStage struct {
+	Duration float64 `json:"duration"`
+	Outcome  string  `json:"outcome"`
+	Stdout   string  `json:"stdout"`
+	Stderr   string  `json:"stderr"`
+	// Longrepr is the traceback of a failure, or the reason for a skip.
+	Longrepr string `json:"longrepr"`
+}
+
+// parsePytestResults reads the results of a pytest JSON report, as
+// written by the pytest-json-report plugin with --json-report.
+func parsePytestResults(reader io.Reader) ([]reportTestResult, error) {
+	report := pytestReport{}
+	if err := json.NewDecoder(reader).Decode(&report); err != nil {
+		return nil, errors.Wrap(err, "problem decoding pytest report")
+	}
+
+	results := make([]reportTestResult, 0, len(report.Tests))
+	for _, test := range report.Tests {
+		result := reportTestResult{Name: test.NodeID}
+
+		switch test.Outcome {
+		case "passed", "xpassed":
+			result.Status = evergreen.TestSucceededStatus
+		case "skipped", "xfailed":
+			result.Status = evergreen.TestSkippedStatus
+		case "failed", "error":
+			result.Status = evergreen.TestFailedStatus
+		default:
+			return nil, errors.Errorf("test '%s' has unknown outcome '%s'", test.NodeID, test.Outcome)
+		}
+
+		for _, stage := range []struct {
+			name  string
+			stage *pytestTestStage
+		}{
+			{name: "setup", stage: test.Setup},
+			{name: "call", stage: test.Call},
+			{name: "teardown", stage: test.Teardown},
+		} {
+			if stage.stage == nil {
+				continue
+			}
+			result.Duration += time.Duration(stage.stage.Duration * float64(time.Second))
+			result.Output = append(result.Output, stage.stage.output(stage.name)...)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// output returns the lines of the stage's captured output and failure.
+func (s *pytestTestStage) output(name string) []string {
+	lines := []string{}
+	for _, section := range []struct {
+		title string
+		text  string
+	}{
+		{title: "stdout", text: s.Stdout},
+		{title: "stderr", text: s.Stderr},
+		{title: s.Outcome, text: s.Longrepr},
+	} {
+		text := strings.TrimRight(section.text, "\n")
+		if text == "" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s %s:", name, section.title))
+		lines = append(lines, strings.Split(text, "\n")...)
+	}
+	return lines
+}
@@ -0,0 +1,207 @@
+package command
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mitchellh/mapstructure"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+// reportTestResult is a test result read from a test report.
+type reportTestResult struct {
+	Name     string
+	Status   string
+	Duration time.Duration
+	// Output is the test's log, if the report includes one.
+	Output []string
+}
+
+// reportParser reads the test results from a test report.
+type reportParser func(io.Reader) ([]reportTestResult, error)
+
+// reportResults reads in test report files of a format other than xunit
+// and converts them to a format MCI can use. Each supported format is a
+// separate command.
+type reportResults struct {
+	// File describes the relative path of the file to be sent. Supports globbing.
+	// Note that this can also be described via expansions.
+	File  string   `mapstructure:"file" plugin:"expand"`
+	Files []string `mapstructure:"files" plugin:"expand"`
+
+	name   string
+	format string
+	parse  reportParser
+	base
+}
+
+func tapResultsFactory() Command {
+	return &reportResults{name: "attach.tap_results", format: "TAP", parse: parseTAPResults}
+}
+
+func pytestResultsFactory() Command {
+	return &reportResults{name: "attach.pytest_results", format: "pytest JSON", parse: parsePytestResults}
+}
+
+func cucumberResultsFactory() Command {
+	return &reportResults{name: "attach.cucumber_results", format: "Cucumber JSON", parse: parseCucumberResults}
+}
+
+func mochaResultsFactory() Command {
+	return &reportResults{name: "attach.mocha_results", format: "Mocha JSON", parse: parseMochaResults}
+}
+
+func (c *reportResults) Name() string { return c.name }
+
+// ParseParams reads and validates the command parameters. This is required
+// to satisfy the 'Command' interface
+func (c *reportResults) ParseParams(params map[string]interface{}) error {
+	if err := mapstructure.Decode(params, c); err != nil {
+		return errors.Wrapf(err, "error decoding '%s' params", c.Name())
+	}
+
+	if c.File == "" && len(c.Files) == 0 {
+		return errors.New("must specify at least one file")
+	}
+
+	return nil
+}
+
+// Expand the parameter appropriately
+func (c *reportResults) expandParams(conf *model.TaskConfig) error {
+	if c.File != "" {
+		c.Files = append(c.Files, c.File)
+	}
+
+	catcher := grip.NewBasicCatcher()
+
+	var err error
+	for idx, f := range c.Files {
+		c.Files[idx], err = conf.Expansions.ExpandString(f)
+		catcher.Add(err)
+	}
+
+	return errors.Wrapf(catcher.Resolve(), "problem expanding paths")
+}
+
+// Execute carries out the command - this is required to satisfy the
+// 'Command' interface
+func (c *reportResults) Execute(ctx context.Context,
+	comm client.Communicator, logger client.LoggerProducer, conf *model.TaskConfig) error {
+
+	if err := c.expandParams(conf); err != nil {
+		return err
+	}
+
+	errChan := make(chan error)
+	go func() {
+		errChan <- c.parseAndUploadResults(ctx, conf, logger, comm)
+	}()
+
+	select {
+	case err := <-errChan:
+		return errors.WithStack(err)
+	case <-ctx.Done():
+		logger.Execution().Infof("Received signal to terminate execution of %s command", c.Name())
+		return nil
+	}
+}
+
+func (c *reportResults) parseAndUploadResults(ctx context.Context, conf *model.TaskConfig,
+	logger client.LoggerProducer, comm client.Communicator) error {
+
+	reportFilePaths, err := getFilePaths(conf.WorkDir, c.Files)
+	if err != nil {
+		return err
+	}
+
+	tests := []task.TestResult{}
+	logs := []*model.TestLog{}
+	logIdxToTestIdx := []int{}
+	for _, reportFileLoc := range reportFilePaths {
+		if ctx.Err() != nil {
+			return errors.New("operation canceled")
+		}
+
+		results, err := c.parseFile(reportFileLoc)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		for _, result := range results {
+			test, log := result.toModelTestResultAndLog(conf.Task)
+			if log != nil {
+				logs = append(logs, log)
+				logIdxToTestIdx = append(logIdxToTestIdx, len(tests))
+			}
+			tests = append(tests, test)
+		}
+	}
+
+	td := client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret}
+
+	for i, log := range logs {
+		if ctx.Err() != nil {
+			return errors.New("operation canceled")
+		}
+
+		logId, err := sendJSONLogs(ctx, logger, comm, td, log)
+		if err != nil {
+			logger.Task().Warningf("problem uploading logs for %s", log.Name)
+			continue
+		}
+		tests[logIdxToTestIdx[i]].LogId = logId
+		tests[logIdxToTestIdx[i]].LineNum = 1
+	}
+
+	return sendJSONResults(ctx, conf, logger, comm, &task.LocalTestResults{Results: tests})
+}
+
+func (c *reportResults) parseFile(path string) ([]reportTestResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't open %s file", c.format)
+	}
+	defer file.Close()
+
+	results, err := c.parse(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing %s file %s", c.format, path)
+	}
+	return results, nil
+}
+
+// toModelTestResultAndLog converts a report's test result into an
+// mci task.TestResult and, if the report includes the test's output, a
+// model.TestLog.
+func (r reportTestResult) toModelTestResultAndLog(t *task.Task) (task.TestResult, *model.TestLog) {
+	res := task.TestResult{
+		// replace spaces, dashes, etc. with underscores
+		TestFile:  util.CleanForPath(r.Name),
+		Status:    r.Status,
+		StartTime: float64(time.Now().Unix()),
+	}
+	res.EndTime = res.StartTime + r.Duration.Seconds()
+
+	if len(r.Output) == 0 {
+		return res, nil
+	}
+
+	log := &model.TestLog{
+		Name:          res.TestFile,
+		Task:          t.Id,
+		TaskExecution: t.Execution,
+		Lines:         r.Output,
+	}
+	// update the URL of the result to the expected log URL
+	res.URL = log.URL()
+
+	return res, log
+}
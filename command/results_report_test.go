@@ -0,0 +1,163 @@
+package command
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseReportFile(t *testing.T, parse reportParser, name string) []reportTestResult {
+	file, err := os.Open(filepath.Join(testutil.GetDirectoryOfFile(), "testdata", "reports", name))
+	require.NoError(t, err)
+	defer file.Close()
+
+	results, err := parse(file)
+	require.NoError(t, err)
+	return results
+}
+
+func TestParseTAPResults(t *testing.T) {
+	assert := assert.New(t)
+
+	results := parseReportFile(t, parseTAPResults, "results.tap")
+	require.Len(t, results, 6)
+
+	assert.Equal("parses the config", results[0].Name)
+	assert.Equal(evergreen.TestSucceededStatus, results[0].Status)
+	assert.Empty(results[0].Output)
+
+	assert.Equal("writes the file", results[1].Name)
+	assert.Equal(evergreen.TestFailedStatus, results[1].Status)
+	assert.Equal(1500*time.Millisecond, results[1].Duration)
+	assert.Contains(strings.Join(results[1].Output, "\n"), "expected 1 to equal 2")
+
+	assert.Equal("retries", results[2].Name)
+	assert.Equal(evergreen.TestSkippedStatus, results[2].Status)
+	assert.Equal([]string{"SKIP: no network"}, results[2].Output)
+
+	assert.Equal(evergreen.TestSkippedStatus, results[3].Status, "failing TODO tests are not failures")
+	assert.Equal("test 5", results[4].Name)
+
+	assert.Equal("nested", results[5].Name, "subtests should be left to their parent test")
+	assert.Equal(evergreen.TestSucceededStatus, results[5].Status)
+
+	results, err := parseTAPResults(strings.NewReader("1..2\nok 1 - first\nBail out! database is down\n"))
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(evergreen.TestFailedStatus, results[1].Status)
+}
+
+func TestParsePytestResults(t *testing.T) {
+	assert := assert.New(t)
+
+	results := parseReportFile(t, parsePytestResults, "pytest.json")
+	require.Len(t, results, 3)
+
+	assert.Equal("tests/test_config.py::test_parse", results[0].Name)
+	assert.Equal(evergreen.TestSucceededStatus, results[0].Status)
+	assert.Equal(252*time.Millisecond, results[0].Duration)
+	assert.Equal([]string{"call stdout:", "parsed"}, results[0].Output)
+
+	assert.Equal(evergreen.TestFailedStatus, results[1].Status)
+	assert.Contains(results[1].Output, "call failed:")
+	assert.Contains(results[1].Output, "E       assert 1 == 2")
+
+	assert.Equal(evergreen.TestSkippedStatus, results[2].Status)
+	assert.Contains(strings.Join(results[2].Output, "\n"), "Skipped: no network")
+
+	_, err := parsePytestResults(strings.NewReader(`{"tests": [{"nodeid": "a", "outcome": "exploded"}]}`))
+	assert.Error(err)
+}
+
+func TestParseCucumberResults(t *testing.T) {
+	assert := assert.New(t)
+
+	results := parseReportFile(t, parseCucumberResults, "cucumber.json")
+	require.Len(t, results, 3)
+
+	assert.Equal("Login: valid password", results[0].Name)
+	assert.Equal(evergreen.TestSucceededStatus, results[0].Status)
+	assert.Equal(6*time.Millisecond, results[0].Duration, "background steps count toward the scenario")
+	assert.Empty(results[0].Output)
+
+	assert.Equal("Login: invalid password", results[1].Name)
+	assert.Equal(evergreen.TestFailedStatus, results[1].Status)
+	assert.Equal([]string{
+		"Given a user [passed]",
+		"When they log in with a bad password [passed]",
+		"Then they see an error [failed]",
+		"expected an error",
+		"but got the dashboard",
+	}, results[1].Output)
+
+	assert.Equal(evergreen.TestSkippedStatus, results[2].Status, "scenarios with undefined steps are skipped")
+}
+
+func TestParseMochaResults(t *testing.T) {
+	assert := assert.New(t)
+
+	results := parseReportFile(t, parseMochaResults, "mocha.json")
+	require.Len(t, results, 3)
+
+	assert.Equal("config parses", results[0].Name)
+	assert.Equal(evergreen.TestSucceededStatus, results[0].Status)
+	assert.Equal(12*time.Millisecond, results[0].Duration)
+
+	assert.Equal(evergreen.TestFailedStatus, results[1].Status)
+	assert.Equal([]string{
+		"AssertionError: expected 1 to equal 2",
+		"    at Context.<anonymous> (test/config.js:10:5)",
+	}, results[1].Output)
+
+	assert.Equal(evergreen.TestSkippedStatus, results[2].Status)
+}
+
+func TestReportResultsCommand(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	comm := client.NewMock("http://localhost.com")
+	conf := &model.TaskConfig{
+		Expansions: util.NewExpansions(map[string]string{"format": "json"}),
+		Task:       &task.Task{Id: "task"},
+		WorkDir:    filepath.Join(testutil.GetDirectoryOfFile(), "testdata"),
+	}
+	logger := comm.GetLoggerProducer(ctx, client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret})
+
+	cmd := mochaResultsFactory()
+	assert.Equal("attach.mocha_results", cmd.Name())
+	assert.Error(cmd.ParseParams(map[string]interface{}{}))
+	require.NoError(cmd.ParseParams(map[string]interface{}{"files": []string{"reports/moch*.${format}"}}))
+	require.NoError(cmd.Execute(ctx, comm, logger, conf))
+
+	require.NotNil(comm.LocalTestResults)
+	require.Len(comm.LocalTestResults.Results, 3)
+	failed := comm.LocalTestResults.Results[1]
+	assert.Equal(evergreen.TestFailedStatus, failed.Status)
+	assert.Equal("config_writes", failed.TestFile)
+	assert.Equal("/test_log/task/0/config_writes", failed.URL)
+	assert.NotEmpty(failed.LogId, "the output of failed tests should be sent as a log")
+	assert.Empty(comm.LocalTestResults.Results[0].LogId)
+	require.Len(comm.TestLogs, 1)
+	assert.Equal("config_writes", comm.TestLogs[0].Name)
+
+	for _, factory := range []CommandFactory{tapResultsFactory, pytestResultsFactory, cucumberResultsFactory} {
+		cmd = factory()
+		_, ok := GetCommandFactory(cmd.Name())
+		assert.True(ok, "%s should be registered", cmd.Name())
+	}
+}
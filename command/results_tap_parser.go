@@ -0,0 +1,102 @@
+package command
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/pkg/errors"
+)
+
+var (
+	// tapTestLine matches TAP test lines, e.g. "not ok 3 - reads the file # TODO".
+	tapTestLine = regexp.MustCompile(`^(not )?ok\b\s*(\d+)?\s*(?:-\s*)?([^#]*?)\s*(?:#\s*(\w+)\b\s*(.*))?$`)
+	// tapDuration matches the duration in a TAP 13 YAML block, which
+	// reporters write in milliseconds.
+	tapDuration = regexp.MustCompile(`^\s*duration_ms:\s*([0-9.]+)\s*$`)
+)
+
+// parseTAPResults reads the results of a Test Anything Protocol stream.
+// Output after a test, such as a TAP 13 YAML block or comments, is the
+// test's log. Nested subtests are left to their parent test.
+func parseTAPResults(reader io.Reader) ([]reportTestResult, error) {
+	results := []reportTestResult{}
+	var current *reportTestResult
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "Bail out!") {
+			results = append(results, reportTestResult{
+				Name:   "Bail out!",
+				Status: evergreen.TestFailedStatus,
+				Output: []string{trimmed},
+			})
+			current = nil
+			continue
+		}
+
+		// top level test lines start at the beginning of the line, while
+		// subtests are indented
+		if match := tapTestLine.FindStringSubmatch(line); match != nil {
+			results = append(results, newTAPResult(match, len(results)+1))
+			current = &results[len(results)-1]
+			continue
+		}
+
+		if current == nil || trimmed == "" || trimmed == "---" || trimmed == "..." {
+			continue
+		}
+		if match := tapDuration.FindStringSubmatch(line); match != nil {
+			if ms, err := strconv.ParseFloat(match[1], 64); err == nil {
+				current.Duration = time.Duration(ms * float64(time.Millisecond))
+			}
+		}
+		current.Output = append(current.Output, strings.TrimRight(line, " \t"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "problem reading TAP stream")
+	}
+
+	return results, nil
+}
+
+func newTAPResult(match []string, number int) reportTestResult {
+	result := reportTestResult{Name: strings.TrimSpace(match[3])}
+	if match[2] != "" {
+		if n, err := strconv.Atoi(match[2]); err == nil {
+			number = n
+		}
+	}
+	if result.Name == "" {
+		result.Name = fmt.Sprintf("test %d", number)
+	}
+
+	directive := strings.ToUpper(match[4])
+	switch {
+	case strings.HasPrefix(directive, "SKIP"):
+		// "ok 1 # skip" and "not ok 1 # skip" are both skipped tests
+		result.Status = evergreen.TestSkippedStatus
+	case directive == "TODO":
+		// failing TODO tests are expected to fail, so they are not
+		// failures, and passing ones have not been marked done yet
+		result.Status = evergreen.TestSkippedStatus
+	case match[1] != "":
+		result.Status = evergreen.TestFailedStatus
+	default:
+		result.Status = evergreen.TestSucceededStatus
+	}
+	if directive != "" && match[5] != "" {
+		result.Output = append(result.Output, fmt.Sprintf("%s: %s", directive, match[5]))
+	}
+
+	return result
+}
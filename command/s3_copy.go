@@ -8,6 +8,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/artifact"
 	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/thirdparty"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
@@ -21,6 +22,10 @@ type s3copy struct {
 	AwsKey    string `mapstructure:"aws_key" plugin:"expand" json:"aws_key"`
 	AwsSecret string `mapstructure:"aws_secret" plugin:"expand" json:"aws_secret"`
 
+	// Provider is the storage backend holding the source and destination
+	// buckets: "s3" (the default), "gcs" or "filesystem".
+	Provider string `mapstructure:"provider" plugin:"expand" json:"provider"`
+
 	// An array of file copy configurations
 	S3CopyFiles []*s3CopyFile `mapstructure:"s3_copy_files" plugin:"expand"`
 	base
@@ -80,11 +85,11 @@ func (c *s3copy) ParseParams(params map[string]interface{}) error {
 // validateParams is a helper function that ensures all
 // the fields necessary for carrying out an S3 copy operation are present
 func (c *s3copy) validateParams() error {
-	if c.AwsKey == "" {
-		return errors.New("s3 AWS key cannot be blank")
+	if err := validateBucketProvider(c.Provider); err != nil {
+		return errors.WithStack(err)
 	}
-	if c.AwsSecret == "" {
-		return errors.New("s3 AWS secret cannot be blank")
+	if err := validateBucketCredentials(c.Provider, c.AwsKey, c.AwsSecret); err != nil {
+		return errors.WithStack(err)
 	}
 	for _, s3CopyFile := range c.S3CopyFiles {
 		if s3CopyFile.Source.Bucket == "" {
@@ -125,6 +130,10 @@ func (c *s3copy) Execute(ctx context.Context,
 	if err := util.ExpandValues(c, conf.Expansions); err != nil {
 		return errors.WithStack(err)
 	}
+	// buckets can only be checked once their expansions are known
+	if err := c.validateParams(); err != nil {
+		return errors.Wrap(err, "expanded params are not valid")
+	}
 
 	errChan := make(chan error)
 	go func() {
@@ -166,6 +175,7 @@ func (c *s3copy) s3Copy(ctx context.Context,
 		s3CopyReq := apimodels.S3CopyRequest{
 			AwsKey:              c.AwsKey,
 			AwsSecret:           c.AwsSecret,
+			Provider:            c.Provider,
 			S3SourceBucket:      s3CopyFile.Source.Bucket,
 			S3SourcePath:        s3CopyFile.Source.Path,
 			S3DestinationBucket: s3CopyFile.Destination.Bucket,
@@ -199,8 +209,14 @@ func (c *s3copy) s3Copy(ctx context.Context,
 func (c *s3copy) attachFiles(ctx context.Context, comm client.Communicator,
 	logger client.LoggerProducer, td client.TaskData, request apimodels.S3CopyRequest) error {
 
-	remotePath := filepath.ToSlash(request.S3DestinationPath)
-	fileLink := s3baseURL + request.S3DestinationBucket + "/" + remotePath
+	bucket, err := newBucket(ctx, comm, td, thirdparty.BucketOptions{
+		Provider: request.Provider,
+		Name:     request.S3DestinationBucket,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	fileLink := bucket.Link(filepath.ToSlash(request.S3DestinationPath))
 
 	displayName := request.S3DisplayName
 
@@ -5,12 +5,18 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
 	modelutil "github.com/evergreen-ci/evergreen/model/testutil"
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/rest/client"
 	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/evergreen/util"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestS3CopyPluginExecution(t *testing.T) {
@@ -56,3 +62,27 @@ func TestS3CopyPluginExecution(t *testing.T) {
 		})
 	})
 }
+
+func TestS3CopyRejectsExpandedBucketNames(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	comm := client.NewMock("http://localhost.com")
+	conf := &model.TaskConfig{
+		Expansions: util.NewExpansions(map[string]string{"bucket": "../../etc"}),
+		Task:       &task.Task{Id: "task"},
+	}
+	logger := comm.GetLoggerProducer(ctx, client.TaskData{ID: conf.Task.Id})
+
+	cmd := &s3copy{}
+	require.NoError(cmd.ParseParams(map[string]interface{}{
+		"provider": evergreen.BucketProviderFilesystem,
+		"s3_copy_files": []map[string]interface{}{{
+			"source":      map[string]string{"bucket": "${bucket}", "path": "passwd"},
+			"destination": map[string]string{"bucket": "artifacts", "path": "passwd"},
+		}},
+	}))
+	assert.Error(cmd.Execute(ctx, comm, logger, conf))
+}
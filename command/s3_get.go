@@ -11,7 +11,6 @@ import (
 	"github.com/evergreen-ci/evergreen/rest/client"
 	"github.com/evergreen-ci/evergreen/thirdparty"
 	"github.com/evergreen-ci/evergreen/util"
-	"github.com/goamz/goamz/aws"
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
 )
@@ -24,6 +23,10 @@ type s3get struct {
 	AwsKey    string `mapstructure:"aws_key" plugin:"expand"`
 	AwsSecret string `mapstructure:"aws_secret" plugin:"expand"`
 
+	// Provider is the storage backend holding the bucket: "s3" (the
+	// default), "gcs" or "filesystem".
+	Provider string `mapstructure:"provider" plugin:"expand"`
+
 	// RemoteFile is the filepath of the file to get, within its bucket
 	RemoteFile string `mapstructure:"remote_file" plugin:"expand"`
 
@@ -41,6 +44,10 @@ type s3get struct {
 	LocalFile string `mapstructure:"local_file" plugin:"expand"`
 	ExtractTo string `mapstructure:"extract_to" plugin:"expand"`
 
+	// providerOverride, if set, replaces Provider, so that local runs
+	// can read files from the local storage directory.
+	providerOverride string
+
 	base
 }
 
@@ -64,11 +71,11 @@ func (c *s3get) ParseParams(params map[string]interface{}) error {
 // Validate that all necessary params are set, and that only one of
 // local_file and extract_to is specified.
 func (c *s3get) validateParams() error {
-	if c.AwsKey == "" {
-		return errors.New("aws_key cannot be blank")
+	if err := validateBucketProvider(c.Provider); err != nil {
+		return errors.WithStack(err)
 	}
-	if c.AwsSecret == "" {
-		return errors.New("aws_secret cannot be blank")
+	if err := validateBucketCredentials(c.provider(), c.AwsKey, c.AwsSecret); err != nil {
+		return errors.WithStack(err)
 	}
 	if c.RemoteFile == "" {
 		return errors.New("remote_file cannot be blank")
@@ -91,6 +98,14 @@ func (c *s3get) validateParams() error {
 	return nil
 }
 
+// provider returns the storage backend to get files from.
+func (c *s3get) provider() string {
+	if c.providerOverride != "" {
+		return c.providerOverride
+	}
+	return c.Provider
+}
+
 func (c *s3get) shouldRunForVariant(buildVariantName string) bool {
 	//No buildvariant filter, so run always
 	if len(c.BuildVariants) == 0 {
@@ -150,9 +165,20 @@ func (c *s3get) Execute(ctx context.Context,
 		}
 	}
 
+	td := client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret}
+	bucket, err := newBucket(ctx, comm, td, thirdparty.BucketOptions{
+		Provider: c.provider(),
+		Name:     c.Bucket,
+		Key:      c.AwsKey,
+		Secret:   c.AwsSecret,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
 	errChan := make(chan error)
 	go func() {
-		errChan <- errors.WithStack(c.getWithRetry(ctx, logger))
+		errChan <- errors.WithStack(c.getWithRetry(ctx, logger, bucket))
 	}()
 
 	select {
@@ -166,26 +192,26 @@ func (c *s3get) Execute(ctx context.Context,
 }
 
 // Wrapper around the Get() function to retry it
-func (c *s3get) getWithRetry(ctx context.Context, logger client.LoggerProducer) error {
+func (c *s3get) getWithRetry(ctx context.Context, logger client.LoggerProducer, bucket thirdparty.Bucket) error {
 	backoffCounter := getS3OpBackoff()
 	timer := time.NewTimer(0)
 	defer timer.Stop()
 
 	for i := 1; i <= maxS3OpAttempts; i++ {
-		logger.Task().Infof("fetching %s from s3 bucket %s (attempt %d of %d)",
-			c.RemoteFile, c.Bucket, i, maxS3OpAttempts)
+		logger.Task().Infof("fetching %s from %s bucket %s (attempt %d of %d)",
+			c.RemoteFile, bucket.Name(), c.Bucket, i, maxS3OpAttempts)
 
 		select {
 		case <-ctx.Done():
 			return errors.New("s3 get operation aborted")
 		case <-timer.C:
-			err := errors.WithStack(c.get(ctx))
+			err := errors.WithStack(c.get(ctx, bucket))
 			if err == nil {
 				return nil
 			}
 
-			logger.Execution().Errorf("problem getting %s from s3 bucket, retrying. [%v]",
-				c.RemoteFile, err)
+			logger.Execution().Errorf("problem getting %s from %s bucket, retrying. [%v]",
+				c.RemoteFile, bucket.Name(), err)
 			timer.Reset(backoffCounter.Duration())
 		}
 	}
@@ -193,22 +219,10 @@ func (c *s3get) getWithRetry(ctx context.Context, logger client.LoggerProducer)
 	return errors.Errorf("S3 get failed after %d attempts", maxS3OpAttempts)
 }
 
-// Fetch the specified resource from s3.
-func (c *s3get) get(ctx context.Context) error {
-	// get the appropriate session and bucket
-	auth := &aws.Auth{
-		AccessKey: c.AwsKey,
-		SecretKey: c.AwsSecret,
-	}
-
-	client := util.GetHTTPClient()
-	defer util.PutHTTPClient(client)
-
-	session := thirdparty.NewS3Session(auth, aws.USEast, client)
-	bucket := session.Bucket(c.Bucket)
-
+// Fetch the specified resource from the bucket.
+func (c *s3get) get(ctx context.Context, bucket thirdparty.Bucket) error {
 	// get a reader for the bucket
-	reader, err := bucket.GetReader(c.RemoteFile)
+	reader, err := bucket.Get(ctx, c.RemoteFile)
 	if err != nil {
 		return errors.Wrapf(err, "error getting bucket reader for file %v", c.RemoteFile)
 	}
@@ -3,18 +3,17 @@ package command
 import (
 	"context"
 	"fmt"
-	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/artifact"
 	"github.com/evergreen-ci/evergreen/rest/client"
 	"github.com/evergreen-ci/evergreen/thirdparty"
 	"github.com/evergreen-ci/evergreen/util"
-	"github.com/goamz/goamz/aws"
 	"github.com/mitchellh/mapstructure"
 	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
@@ -28,6 +27,10 @@ type s3put struct {
 	AwsKey    string `mapstructure:"aws_key" plugin:"expand"`
 	AwsSecret string `mapstructure:"aws_secret" plugin:"expand"`
 
+	// Provider is the storage backend holding the bucket: "s3" (the
+	// default), "gcs" or "filesystem".
+	Provider string `mapstructure:"provider" plugin:"expand"`
+
 	// LocalFile is the local filepath to the file the user
 	// wishes to store in s3
 	LocalFile string `mapstructure:"local_file" plugin:"expand"`
@@ -78,6 +81,10 @@ type s3put struct {
 	workDir     string
 	skipMissing bool
 
+	// providerOverride, if set, replaces Provider, so that local runs
+	// can store files in the local storage directory.
+	providerOverride string
+
 	taskdata client.TaskData
 	base
 }
@@ -106,12 +113,8 @@ func (s3pc *s3put) validate() error {
 	catcher := grip.NewSimpleCatcher()
 
 	// make sure the command params are valid
-	if s3pc.AwsKey == "" {
-		catcher.Add(errors.New("aws_key cannot be blank"))
-	}
-	if s3pc.AwsSecret == "" {
-		catcher.Add(errors.New("aws_secret cannot be blank"))
-	}
+	catcher.Add(validateBucketProvider(s3pc.Provider))
+	catcher.Add(validateBucketCredentials(s3pc.provider(), s3pc.AwsKey, s3pc.AwsSecret))
 	if s3pc.LocalFile == "" && !s3pc.isMulti() {
 		catcher.Add(errors.New("local_file and local_files_include_filter cannot both be blank"))
 	}
@@ -140,14 +143,22 @@ func (s3pc *s3put) validate() error {
 		catcher.Add(errors.Wrapf(err, "%v is an invalid bucket name", s3pc.Bucket))
 	}
 
-	// make sure the s3 permissions are valid
-	if !validS3Permissions(s3pc.Permissions) {
+	// make sure the s3 permissions are valid; filesystem buckets ignore them
+	if s3pc.provider() != evergreen.BucketProviderFilesystem && !validS3Permissions(s3pc.Permissions) {
 		catcher.Add(errors.Errorf("permissions '%v' are not valid", s3pc.Permissions))
 	}
 
 	return catcher.Resolve()
 }
 
+// provider returns the storage backend to put files in.
+func (s3pc *s3put) provider() string {
+	if s3pc.providerOverride != "" {
+		return s3pc.providerOverride
+	}
+	return s3pc.Provider
+}
+
 // Apply the expansions from the relevant task config to all appropriate
 // fields of the s3put.
 func (s3pc *s3put) expandParams(conf *model.TaskConfig) error {
@@ -208,17 +219,28 @@ func (s3pc *s3put) Execute(ctx context.Context,
 		return nil
 	}
 
+	bucket, err := newBucket(ctx, comm, s3pc.taskdata, thirdparty.BucketOptions{
+		Provider:    s3pc.provider(),
+		Name:        s3pc.Bucket,
+		Key:         s3pc.AwsKey,
+		Secret:      s3pc.AwsSecret,
+		Permissions: s3pc.Permissions,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
 	if s3pc.isMulti() {
-		logger.Task().Infof("Putting files matching filter %v into path %v in s3 bucket %v",
-			s3pc.LocalFilesIncludeFilter, s3pc.RemoteFile, s3pc.Bucket)
+		logger.Task().Infof("Putting files matching filter %v into path %v in %s bucket %v",
+			s3pc.LocalFilesIncludeFilter, s3pc.RemoteFile, bucket.Name(), s3pc.Bucket)
 	} else {
-		logger.Task().Infof("Putting %s into %s/%s/%s",
-			s3pc.LocalFile, s3baseURL, s3pc.Bucket, s3pc.RemoteFile)
+		logger.Task().Infof("Putting %s into %s",
+			s3pc.LocalFile, bucket.Link(s3pc.RemoteFile))
 	}
 
 	errChan := make(chan error)
 	go func() {
-		errChan <- errors.WithStack(s3pc.putWithRetry(ctx, comm, logger))
+		errChan <- errors.WithStack(s3pc.putWithRetry(ctx, comm, logger, bucket))
 	}()
 
 	select {
@@ -232,14 +254,9 @@ func (s3pc *s3put) Execute(ctx context.Context,
 }
 
 // Wrapper around the Put() function to retry it.
-func (s3pc *s3put) putWithRetry(ctx context.Context, comm client.Communicator, logger client.LoggerProducer) error {
+func (s3pc *s3put) putWithRetry(ctx context.Context, comm client.Communicator, logger client.LoggerProducer, bucket thirdparty.Bucket) error {
 	backoffCounter := getS3OpBackoff()
 
-	auth := &aws.Auth{
-		AccessKey: s3pc.AwsKey,
-		SecretKey: s3pc.AwsSecret,
-	}
-
 	var (
 		err           error
 		uploadedFiles []string
@@ -251,8 +268,8 @@ func (s3pc *s3put) putWithRetry(ctx context.Context, comm client.Communicator, l
 
 retryLoop:
 	for i := 1; i <= maxS3OpAttempts; i++ {
-		logger.Task().Infof("performing s3 put to %s of %s [%d of %d]",
-			s3pc.Bucket, s3pc.RemoteFile,
+		logger.Task().Infof("performing %s put to %s of %s [%d of %d]",
+			bucket.Name(), s3pc.Bucket, s3pc.RemoteFile,
 			i, maxS3OpAttempts)
 
 		select {
@@ -284,14 +301,8 @@ retryLoop:
 					remoteName = fmt.Sprintf("%s%s", s3pc.RemoteFile, fname)
 				}
 
-				s3URL := url.URL{
-					Scheme: "s3",
-					Host:   s3pc.Bucket,
-					Path:   remoteName,
-				}
-
 				fpath = filepath.Join(s3pc.workDir, fpath)
-				err = thirdparty.PutS3File(auth, fpath, s3URL.String(), s3pc.ContentType, s3pc.Permissions)
+				err = bucket.Upload(ctx, fpath, remoteName, s3pc.ContentType)
 				if err != nil {
 					// retry errors other than "file doesn't exist", which we handle differently based on what
 					// kind of upload it is
@@ -326,7 +337,7 @@ retryLoop:
 		return nil
 	}
 
-	err = errors.WithStack(s3pc.attachFiles(ctx, comm, logger, uploadedFiles, s3pc.RemoteFile))
+	err = errors.WithStack(s3pc.attachFiles(ctx, comm, logger, bucket, uploadedFiles, s3pc.RemoteFile))
 	if err != nil {
 		return err
 	}
@@ -340,7 +351,7 @@ retryLoop:
 
 // attachTaskFiles is responsible for sending the
 // specified file to the API Server. Does not support multiple file putting.
-func (s3pc *s3put) attachFiles(ctx context.Context, comm client.Communicator, logger client.LoggerProducer, localFiles []string, remoteFile string) error {
+func (s3pc *s3put) attachFiles(ctx context.Context, comm client.Communicator, logger client.LoggerProducer, bucket thirdparty.Bucket, localFiles []string, remoteFile string) error {
 	files := []*artifact.File{}
 
 	for _, fn := range localFiles {
@@ -349,7 +360,7 @@ func (s3pc *s3put) attachFiles(ctx context.Context, comm client.Communicator, lo
 			remoteFileName = fmt.Sprintf("%s%s", remoteFile, filepath.Base(fn))
 		}
 
-		fileLink := s3baseURL + s3pc.Bucket + "/" + remoteFileName
+		fileLink := bucket.Link(remoteFileName)
 
 		displayName := s3pc.ResourceDisplayName
 		if s3pc.isMulti() || displayName == "" {
@@ -1,12 +1,21 @@
 package command
 
 import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/client"
 	"github.com/evergreen-ci/evergreen/util"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestS3PutValidateParams(t *testing.T) {
@@ -293,3 +302,65 @@ func TestExpandS3PutParams(t *testing.T) {
 
 	})
 }
+
+func TestS3PutAndGetWithFilesystemProvider(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir, err := ioutil.TempDir("", "s3-put-filesystem")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "artifact.txt"), []byte("artifact"), 0644))
+
+	comm := client.NewMock("http://localhost.com")
+	comm.BucketsConfig.Filesystem.Root = filepath.Join(dir, "store")
+	conf := &model.TaskConfig{
+		Expansions:   &util.Expansions{},
+		Task:         &task.Task{Id: "task"},
+		BuildVariant: &model.BuildVariant{},
+		Project:      &model.Project{},
+		WorkDir:      dir,
+	}
+	logger := comm.GetLoggerProducer(ctx, client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret})
+
+	put := &s3put{}
+	assert.Error(put.ParseParams(map[string]interface{}{
+		"provider":     "floppy",
+		"local_file":   "artifact.txt",
+		"remote_file":  "builds/artifact.txt",
+		"bucket":       "artifacts",
+		"content_type": "text/plain",
+	}), "unknown providers should be rejected")
+
+	put = &s3put{}
+	require.NoError(put.ParseParams(map[string]interface{}{
+		"provider":     evergreen.BucketProviderFilesystem,
+		"local_file":   "artifact.txt",
+		"remote_file":  "builds/artifact.txt",
+		"bucket":       "artifacts",
+		"content_type": "text/plain",
+	}), "filesystem buckets need neither credentials nor permissions")
+	require.NoError(put.Execute(ctx, comm, logger, conf))
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "store", "artifacts", "builds", "artifact.txt"))
+	require.NoError(err)
+	assert.Equal("artifact", string(data))
+	require.Len(comm.AttachedFiles[conf.Task.Id], 1)
+	assert.Equal("file://"+filepath.ToSlash(filepath.Join(dir, "store", "artifacts", "builds", "artifact.txt")),
+		comm.AttachedFiles[conf.Task.Id][0].Link)
+
+	get := &s3get{}
+	require.NoError(get.ParseParams(map[string]interface{}{
+		"provider":    evergreen.BucketProviderFilesystem,
+		"remote_file": "builds/artifact.txt",
+		"bucket":      "artifacts",
+		"local_file":  "fetched.txt",
+	}))
+	require.NoError(get.Execute(ctx, comm, logger, conf))
+
+	data, err = ioutil.ReadFile(filepath.Join(dir, "fetched.txt"))
+	require.NoError(err)
+	assert.Equal("artifact", string(data))
+}
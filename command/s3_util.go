@@ -1,21 +1,25 @@
 package command
 
 import (
-	"errors"
+	"context"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/thirdparty"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/goamz/goamz/s3"
 	"github.com/jpillora/backoff"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
 )
 
 const (
 	maxS3OpAttempts   = 10
 	s3OpSleep         = 2 * time.Second
 	s3OpRetryMaxSleep = 20 * time.Second
-	s3baseURL         = "https://s3.amazonaws.com/"
 )
 
 var (
@@ -65,6 +69,55 @@ func validS3Permissions(perm string) bool {
 	return util.StringSliceContains(perms, perm)
 }
 
+// validateBucketProvider checks that the provider is empty, which means s3,
+// or one of the supported storage backends.
+func validateBucketProvider(provider string) error {
+	if provider == "" || util.IsExpandable(provider) {
+		return nil
+	}
+	if !util.StringSliceContains(evergreen.BucketProviders, provider) {
+		return errors.Errorf("provider '%s' is not one of %s", provider,
+			strings.Join(evergreen.BucketProviders, ", "))
+	}
+	return nil
+}
+
+// validateBucketCredentials checks that the credentials the provider needs
+// are set: s3 needs a key and a secret, gcs uses the secret as its access
+// token, and filesystem buckets need neither.
+func validateBucketCredentials(provider, key, secret string) error {
+	catcher := grip.NewSimpleCatcher()
+	switch provider {
+	case "", evergreen.BucketProviderS3:
+		if key == "" {
+			catcher.Add(errors.New("aws_key cannot be blank"))
+		}
+		if secret == "" {
+			catcher.Add(errors.New("aws_secret cannot be blank"))
+		}
+	case evergreen.BucketProviderGCS:
+		if secret == "" {
+			catcher.Add(errors.New("aws_secret cannot be blank"))
+		}
+	}
+	return catcher.Resolve()
+}
+
+// newBucket returns the bucket described by opts, fetching the storage
+// backend settings from the API server for providers other than s3.
+func newBucket(ctx context.Context, comm client.Communicator, td client.TaskData, opts thirdparty.BucketOptions) (thirdparty.Bucket, error) {
+	conf := &evergreen.BucketsConfig{}
+	if opts.Provider != "" && opts.Provider != evergreen.BucketProviderS3 {
+		var err error
+		conf, err = comm.GetBucketsConfig(ctx, td)
+		if err != nil {
+			return nil, errors.Wrap(err, "problem getting bucket settings")
+		}
+	}
+
+	return thirdparty.NewBucket(opts, conf)
+}
+
 func getS3OpBackoff() *backoff.Backoff {
 	return &backoff.Backoff{
 		Min:    s3OpSleep,
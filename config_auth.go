@@ -39,11 +39,41 @@ type GithubAuthConfig struct {
 	Organization string   `bson:"organization" json:"organization" yaml:"organization"`
 }
 
+// OIDCAuthConfig holds settings for authenticating users with an OpenID
+// Connect identity provider. The claims fields name the ID token claims that
+// hold each user attribute; GroupsClaim may be empty if the provider does not
+// report groups. If Groups is not empty, only members of one of those groups
+// may log in. GroupRoles grants roles to the members of groups each time
+// they log in.
+type OIDCAuthConfig struct {
+	Issuer           string          `bson:"issuer" json:"issuer" yaml:"issuer"`
+	ClientId         string          `bson:"client_id" json:"client_id" yaml:"client_id"`
+	ClientSecret     string          `bson:"client_secret" json:"client_secret" yaml:"client_secret"`
+	Scopes           []string        `bson:"scopes" json:"scopes" yaml:"scopes"`
+	UsernameClaim    string          `bson:"username_claim" json:"username_claim" yaml:"username_claim"`
+	DisplayNameClaim string          `bson:"display_name_claim" json:"display_name_claim" yaml:"display_name_claim"`
+	EmailClaim       string          `bson:"email_claim" json:"email_claim" yaml:"email_claim"`
+	GroupsClaim      string          `bson:"groups_claim" json:"groups_claim" yaml:"groups_claim"`
+	Groups           []string        `bson:"groups" json:"groups" yaml:"groups"`
+	GroupRoles       []OIDCGroupRole `bson:"group_roles" json:"group_roles" yaml:"group_roles"`
+}
+
+// OIDCGroupRole grants a role to the members of a group on a project, or
+// globally if the project is empty.
+type OIDCGroupRole struct {
+	Group   string `bson:"group" json:"group" yaml:"group"`
+	Role    string `bson:"role" json:"role" yaml:"role"`
+	Project string `bson:"project" json:"project" yaml:"project"`
+}
+
 // AuthConfig has a pointer to either a CrowConfig or a NaiveAuthConfig.
+// The only forms of authentication that may be combined are OIDC and Naive,
+// in which case the Naive users are intended for service accounts.
 type AuthConfig struct {
 	Crowd  *CrowdConfig      `bson:"crowd" json:"crowd" yaml:"crowd"`
 	Naive  *NaiveAuthConfig  `bson:"naive" json:"naive" yaml:"naive"`
 	Github *GithubAuthConfig `bson:"github" json:"github" yaml:"github"`
+	OIDC   *OIDCAuthConfig   `bson:"oidc" json:"oidc" yaml:"oidc"`
 }
 
 func (c *AuthConfig) SectionId() string { return "auth" }
@@ -63,6 +93,7 @@ func (c *AuthConfig) Set() error {
 			"crowd":  c.Crowd,
 			"naive":  c.Naive,
 			"github": c.Github,
+			"oidc":   c.OIDC,
 		},
 	})
 	return errors.Wrapf(err, "error updating section %s", c.SectionId())
@@ -70,7 +101,7 @@ func (c *AuthConfig) Set() error {
 
 func (c *AuthConfig) ValidateAndDefault() error {
 	catcher := grip.NewSimpleCatcher()
-	if c.Crowd == nil && c.Naive == nil && c.Github == nil {
+	if c.Crowd == nil && c.Naive == nil && c.Github == nil && c.OIDC == nil {
 		catcher.Add(errors.New("You must specify one form of authentication"))
 	}
 	if c.Naive != nil {
@@ -87,5 +118,36 @@ func (c *AuthConfig) ValidateAndDefault() error {
 			catcher.Add(errors.New("Must specify either a set of users or an organization for Github Authentication"))
 		}
 	}
+	if c.OIDC != nil {
+		if c.Crowd != nil || c.Github != nil {
+			catcher.Add(errors.New("OIDC authentication can only be combined with Naive authentication"))
+		}
+		if c.OIDC.Issuer == "" {
+			catcher.Add(errors.New("Must specify an issuer for OIDC authentication"))
+		}
+		if c.OIDC.ClientId == "" || c.OIDC.ClientSecret == "" {
+			catcher.Add(errors.New("Must specify a client id and secret for OIDC authentication"))
+		}
+		if len(c.OIDC.Scopes) == 0 {
+			c.OIDC.Scopes = []string{"openid", "profile", "email"}
+		}
+		if c.OIDC.UsernameClaim == "" {
+			c.OIDC.UsernameClaim = "preferred_username"
+		}
+		if c.OIDC.DisplayNameClaim == "" {
+			c.OIDC.DisplayNameClaim = "name"
+		}
+		if c.OIDC.EmailClaim == "" {
+			c.OIDC.EmailClaim = "email"
+		}
+		if len(c.OIDC.GroupRoles) != 0 && c.OIDC.GroupsClaim == "" {
+			catcher.Add(errors.New("Must specify a groups claim to grant roles to OIDC groups"))
+		}
+		for _, groupRole := range c.OIDC.GroupRoles {
+			if groupRole.Group == "" || groupRole.Role == "" {
+				catcher.Add(errors.New("Must specify a group and a role for each OIDC group role"))
+			}
+		}
+	}
 	return catcher.Resolve()
 }
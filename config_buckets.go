@@ -0,0 +1,84 @@
+package evergreen
+
+import (
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// BucketProviderS3 stores artifacts in AWS S3.
+	BucketProviderS3 = "s3"
+	// BucketProviderFilesystem stores artifacts in a directory, which is
+	// typically a network file system shared by the hosts.
+	BucketProviderFilesystem = "filesystem"
+	// BucketProviderGCS stores artifacts in an object store that speaks the
+	// Google Cloud Storage JSON API.
+	BucketProviderGCS = "gcs"
+
+	defaultGCSEndpoint = "https://storage.googleapis.com"
+)
+
+// BucketProviders are the storage backends artifact commands can use.
+var BucketProviders = []string{BucketProviderS3, BucketProviderFilesystem, BucketProviderGCS}
+
+// BucketsConfig holds settings for the storage backends of the artifact
+// commands other than S3.
+type BucketsConfig struct {
+	Filesystem FilesystemBucketConfig `bson:"filesystem" json:"filesystem" yaml:"filesystem"`
+	GCS        GCSBucketConfig        `bson:"gcs" json:"gcs" yaml:"gcs"`
+}
+
+// FilesystemBucketConfig configures buckets that are directories under a
+// root directory.
+type FilesystemBucketConfig struct {
+	// Root is the directory holding a subdirectory for each bucket.
+	Root string `bson:"root" json:"root" yaml:"root"`
+	// LinkBaseURL is the URL of a file server serving the root directory,
+	// which links to artifacts are made relative to. If it is empty, links
+	// are file URLs.
+	LinkBaseURL string `bson:"link_base_url" json:"link_base_url" yaml:"link_base_url"`
+}
+
+// GCSBucketConfig configures buckets in an object store that speaks the
+// Google Cloud Storage JSON API.
+type GCSBucketConfig struct {
+	// Endpoint is the base URL of the API. It defaults to Google's.
+	Endpoint string `bson:"endpoint" json:"endpoint" yaml:"endpoint"`
+	// LinkBaseURL is the URL links to artifacts are made relative to. It
+	// defaults to the endpoint.
+	LinkBaseURL string `bson:"link_base_url" json:"link_base_url" yaml:"link_base_url"`
+}
+
+func (c *BucketsConfig) SectionId() string { return "buckets" }
+
+func (c *BucketsConfig) Get() error {
+	err := db.FindOneQ(ConfigCollection, db.Query(byId(c.SectionId())), c)
+	if err != nil && err.Error() == errNotFound {
+		*c = BucketsConfig{}
+		return nil
+	}
+	return errors.Wrapf(err, "error retrieving section %s", c.SectionId())
+}
+
+func (c *BucketsConfig) Set() error {
+	_, err := db.Upsert(ConfigCollection, byId(c.SectionId()), bson.M{
+		"$set": bson.M{
+			"filesystem": c.Filesystem,
+			"gcs":        c.GCS,
+		},
+	})
+	return errors.Wrapf(err, "error updating section %s", c.SectionId())
+}
+
+func (c *BucketsConfig) ValidateAndDefault() error {
+	c.Filesystem.LinkBaseURL = strings.TrimRight(c.Filesystem.LinkBaseURL, "/")
+	if c.GCS.Endpoint == "" {
+		c.GCS.Endpoint = defaultGCSEndpoint
+	}
+	c.GCS.Endpoint = strings.TrimRight(c.GCS.Endpoint, "/")
+	c.GCS.LinkBaseURL = strings.TrimRight(c.GCS.LinkBaseURL, "/")
+	return nil
+}
@@ -8,11 +8,12 @@ import (
 
 // CloudProviders stores configuration settings for the supported cloud host providers.
 type CloudProviders struct {
-	AWS       AWSConfig       `bson:"aws" json:"aws" yaml:"aws"`
-	Docker    DockerConfig    `bson:"docker" json:"docker" yaml:"docker"`
-	GCE       GCEConfig       `bson:"gce" json:"gce" yaml:"gce"`
-	OpenStack OpenStackConfig `bson:"openstack" json:"openstack" yaml:"openstack"`
-	VSphere   VSphereConfig   `bson:"vsphere" json:"vsphere" yaml:"vsphere"`
+	AWS        AWSConfig        `bson:"aws" json:"aws" yaml:"aws"`
+	Docker     DockerConfig     `bson:"docker" json:"docker" yaml:"docker"`
+	GCE        GCEConfig        `bson:"gce" json:"gce" yaml:"gce"`
+	Kubernetes KubernetesConfig `bson:"kubernetes" json:"kubernetes" yaml:"kubernetes"`
+	OpenStack  OpenStackConfig  `bson:"openstack" json:"openstack" yaml:"openstack"`
+	VSphere    VSphereConfig    `bson:"vsphere" json:"vsphere" yaml:"vsphere"`
 }
 
 func (c *CloudProviders) SectionId() string { return "providers" }
@@ -29,11 +30,12 @@ func (c *CloudProviders) Get() error {
 func (c *CloudProviders) Set() error {
 	_, err := db.Upsert(ConfigCollection, byId(c.SectionId()), bson.M{
 		"$set": bson.M{
-			"aws":       c.AWS,
-			"docker":    c.Docker,
-			"gce":       c.GCE,
-			"openstack": c.OpenStack,
-			"vsphere":   c.VSphere,
+			"aws":        c.AWS,
+			"docker":     c.Docker,
+			"gce":        c.GCE,
+			"kubernetes": c.Kubernetes,
+			"openstack":  c.OpenStack,
+			"vsphere":    c.VSphere,
 		},
 	})
 	return errors.Wrapf(err, "error updating section %s", c.SectionId())
@@ -52,6 +54,19 @@ type DockerConfig struct {
 	APIVersion string `bson:"api_version" json:"api_version" yaml:"api_version"`
 }
 
+// KubernetesConfig stores the connection info for a Kubernetes cluster.
+type KubernetesConfig struct {
+	// APIServer is the base URL of the cluster's API server.
+	APIServer string `bson:"api_server" json:"api_server" yaml:"api_server"`
+	// Token is the bearer token of the service account used to manage pods.
+	Token string `bson:"token" json:"token" yaml:"token"`
+	// Namespace is the namespace pods are created in when neither the distro
+	// nor the container pool specifies one.
+	Namespace string `bson:"namespace" json:"namespace" yaml:"namespace"`
+	// Insecure skips verification of the API server's certificate.
+	Insecure bool `bson:"insecure" json:"insecure" yaml:"insecure"`
+}
+
 // OpenStackConfig stores auth info for Linaro using Identity V3. All fields required.
 //
 // The config is NOT compatible with Identity V2.
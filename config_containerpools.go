@@ -16,6 +16,15 @@ type ContainerPool struct {
 	MaxContainers int `bson:"max_containers" json:"max_containers" yaml:"max_containers"`
 	// Port number to start at for SSH connections
 	Port uint16 `bson:"port" json:"port" yaml:"port"`
+	// Kubernetes namespace that hosts containers, used instead of a parent
+	// host distro
+	Namespace string `bson:"namespace,omitempty" json:"namespace,omitempty" yaml:"namespace,omitempty"`
+}
+
+// UsesNamespace returns true if the pool's containers are scheduled as
+// Kubernetes pods rather than on Docker parent hosts.
+func (p *ContainerPool) UsesNamespace() bool {
+	return p.Namespace != ""
 }
 
 type ContainerPoolsConfig struct {
@@ -59,6 +68,9 @@ func (c *ContainerPoolsConfig) ValidateAndDefault() error {
 		if pool.MaxContainers <= 0 {
 			return errors.Errorf("container pool field max_containers must be positive integer")
 		}
+		if pool.Namespace != "" && pool.Distro != "" {
+			return errors.Errorf("container pool '%s' cannot specify both a namespace and a parent distro", pool.Id)
+		}
 	}
 	return nil
 }
@@ -35,6 +35,7 @@ func resetRegistry() error {
 		&AmboyConfig{},
 		&APIConfig{},
 		&AuthConfig{},
+		&BucketsConfig{},
 		&CloudProviders{},
 		&ContainerPoolsConfig{},
 		&HostInitConfig{},
@@ -43,6 +44,7 @@ func resetRegistry() error {
 		&NotifyConfig{},
 		&RepoTrackerConfig{},
 		&SchedulerConfig{},
+		&SecretsConfig{},
 		&ServiceFlags{},
 		&SlackConfig{},
 		&UIConfig{},
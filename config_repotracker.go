@@ -11,6 +11,13 @@ type RepoTrackerConfig struct {
 	NumNewRepoRevisionsToFetch int `bson:"revs_to_fetch" json:"revs_to_fetch" yaml:"numnewreporevisionstofetch"`
 	MaxRepoRevisionsToSearch   int `bson:"max_revs_to_search" json:"max_revs_to_search" yaml:"maxreporevisionstosearch"`
 	MaxConcurrentRequests      int `bson:"max_con_requests" json:"max_con_requests" yaml:"maxconcurrentrequests"`
+	// MirrorDirectory is where local mirrors of git projects' repositories
+	// are kept.
+	MirrorDirectory string `bson:"mirror_dir" json:"mirror_dir" yaml:"mirrordirectory"`
+	// GitlabURL is the GitLab instance that the GitLab credentials are for.
+	// Projects on other instances are polled without credentials. It
+	// defaults to gitlab.com.
+	GitlabURL string `bson:"gitlab_url" json:"gitlab_url" yaml:"gitlaburl"`
 }
 
 func (c *RepoTrackerConfig) SectionId() string { return "repotracker" }
@@ -30,6 +37,8 @@ func (c *RepoTrackerConfig) Set() error {
 			"revs_to_fetch":      c.NumNewRepoRevisionsToFetch,
 			"max_revs_to_search": c.MaxRepoRevisionsToSearch,
 			"max_con_requests":   c.MaxConcurrentRequests,
+			"mirror_dir":         c.MirrorDirectory,
+			"gitlab_url":         c.GitlabURL,
 		},
 	})
 	return errors.Wrapf(err, "error updating section %s", c.SectionId())
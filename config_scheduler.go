@@ -7,10 +7,24 @@ import (
 	"gopkg.in/mgo.v2/bson"
 )
 
+const (
+	// TaskPrioritizerComparator orders a distro's queue with the
+	// comparator-based prioritizer.
+	TaskPrioritizerComparator = "comparator"
+	// TaskPrioritizerFairShare orders a distro's queue so that projects
+	// share the distro's hosts.
+	TaskPrioritizerFairShare = "fairshare"
+)
+
+// ValidTaskPrioritizers are the task prioritizers that the scheduler and
+// distros can select.
+var ValidTaskPrioritizers = []string{TaskPrioritizerComparator, TaskPrioritizerFairShare}
+
 // SchedulerConfig holds relevant settings for the scheduler process.
 type SchedulerConfig struct {
 	TaskFinder       string  `bson:"task_finder" json:"task_finder" yaml:"task_finder"`
 	HostAllocator    string  `bson:"host_allocator" json:"host_allocator" yaml:"host_allocator"`
+	TaskPrioritizer  string  `bson:"task_prioritizer" json:"task_prioritizer" yaml:"task_prioritizer"`
 	FreeHostFraction float64 `bson:"free_host_fraction" json:"free_host_fraction" yaml:"free_host_fraction"`
 }
 
@@ -30,6 +44,7 @@ func (c *SchedulerConfig) Set() error {
 		"$set": bson.M{
 			"task_finder":        c.TaskFinder,
 			"host_allocator":     c.HostAllocator,
+			"task_prioritizer":   c.TaskPrioritizer,
 			"free_host_fraction": c.FreeHostFraction,
 		},
 	})
@@ -37,6 +52,15 @@ func (c *SchedulerConfig) Set() error {
 }
 
 func (c *SchedulerConfig) ValidateAndDefault() error {
+	if c.TaskPrioritizer == "" {
+		c.TaskPrioritizer = TaskPrioritizerComparator
+	}
+
+	if !util.StringSliceContains(ValidTaskPrioritizers, c.TaskPrioritizer) {
+		return errors.Errorf("supported prioritizers are %s; %s is not supported",
+			ValidTaskPrioritizers, c.TaskPrioritizer)
+	}
+
 	finders := []string{"legacy", "alternate", "parallel", "pipeline"}
 
 	if c.TaskFinder == "" {
@@ -50,7 +74,7 @@ func (c *SchedulerConfig) ValidateAndDefault() error {
 			finders, c.TaskFinder)
 	}
 
-	allocators := []string{"duration", "deficit", "utilization"}
+	allocators := []string{"duration", "deficit", "utilization", "predictive"}
 	if c.HostAllocator == "" {
 		c.HostAllocator = allocators[0]
 		return nil
@@ -0,0 +1,152 @@
+package evergreen
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// SecretBackendVault resolves project variables from a Vault-compatible
+	// key/value secret store.
+	SecretBackendVault = "vault"
+
+	// ProjectVarsEncryptionKeyEnv is the environment variable that holds
+	// the project variables encryption key when the configuration file does
+	// not.
+	ProjectVarsEncryptionKeyEnv = "EVERGREEN_PROJECT_VARS_ENCRYPTION_KEY"
+
+	defaultVaultMount         = "secret"
+	defaultVaultProjectPrefix = "evergreen/projects"
+	defaultVaultTimeoutSecs   = 10
+)
+
+// SecretsConfig holds settings for protecting project variables.
+type SecretsConfig struct {
+	// EncryptionKey is a base64 encoded AES key of 16, 24, or 32 bytes. When
+	// it is set, project variables are encrypted before they are stored.
+	// Since it protects data in the database, it is only read from the
+	// configuration file or the environment, and never stored or returned
+	// by the API.
+	EncryptionKey string `bson:"-" json:"-" yaml:"encryption_key"`
+	// Backend is the external secret store that project variables can
+	// reference. It is empty if there is none.
+	Backend string      `bson:"backend" json:"backend" yaml:"backend"`
+	Vault   VaultConfig `bson:"vault" json:"vault" yaml:"vault"`
+}
+
+// VaultConfig holds settings for reaching a Vault-compatible secret store
+// with a version 2 key/value secrets engine. Projects can only reference
+// secrets under ProjectPrefix/<project id>, and the token's policy should
+// only allow reading "<mount>/data/<project prefix>/*".
+type VaultConfig struct {
+	URL           string `bson:"url" json:"url" yaml:"url"`
+	Token         string `bson:"token" json:"token" yaml:"token"`
+	Mount         string `bson:"mount" json:"mount" yaml:"mount"`
+	ProjectPrefix string `bson:"project_prefix" json:"project_prefix" yaml:"project_prefix"`
+	TimeoutSecs   int    `bson:"timeout_secs" json:"timeout_secs" yaml:"timeout_secs"`
+}
+
+// ProjectSecretPath returns the path within the secrets engine that a
+// project's secret references are relative to.
+func (c VaultConfig) ProjectSecretPath(projectID string) string {
+	prefix := strings.Trim(c.ProjectPrefix, "/")
+	if prefix == "" {
+		prefix = defaultVaultProjectPrefix
+	}
+	return prefix + "/" + projectID
+}
+
+func (c *SecretsConfig) SectionId() string { return "secrets" }
+
+func (c *SecretsConfig) Get() error {
+	err := db.FindOneQ(ConfigCollection, db.Query(byId(c.SectionId())), c)
+	if err != nil && err.Error() == errNotFound {
+		*c = SecretsConfig{}
+		return nil
+	}
+	return errors.Wrapf(err, "error retrieving section %s", c.SectionId())
+}
+
+func (c *SecretsConfig) Set() error {
+	_, err := db.Upsert(ConfigCollection, byId(c.SectionId()), bson.M{
+		"$set": bson.M{
+			"backend": c.Backend,
+			"vault":   c.Vault,
+		},
+		// remove any key saved before keys were kept out of the database
+		"$unset": bson.M{"encryption_key": 1},
+	})
+	return errors.Wrapf(err, "error updating section %s", c.SectionId())
+}
+
+func (c *SecretsConfig) ValidateAndDefault() error {
+	catcher := grip.NewSimpleCatcher()
+	if _, err := c.GetEncryptionKey(); err != nil {
+		catcher.Add(err)
+	}
+
+	switch c.Backend {
+	case "":
+	case SecretBackendVault:
+		if c.Vault.URL == "" {
+			catcher.Add(errors.New("vault secret backend must have a URL"))
+		}
+		if c.Vault.Mount == "" {
+			c.Vault.Mount = defaultVaultMount
+		}
+		if c.Vault.ProjectPrefix == "" {
+			c.Vault.ProjectPrefix = defaultVaultProjectPrefix
+		}
+		for _, segment := range strings.Split(strings.Trim(c.Vault.ProjectPrefix, "/"), "/") {
+			if segment == "" || segment == "." || segment == ".." {
+				catcher.Add(errors.Errorf("invalid vault project prefix '%s'", c.Vault.ProjectPrefix))
+				break
+			}
+		}
+		if c.Vault.TimeoutSecs <= 0 {
+			c.Vault.TimeoutSecs = defaultVaultTimeoutSecs
+		}
+	default:
+		catcher.Add(errors.Errorf("invalid secret backend '%s'", c.Backend))
+	}
+
+	return catcher.Resolve()
+}
+
+// GetEncryptionKey returns the decoded key to encrypt project variables
+// with, taken from the configuration file or otherwise the environment, or
+// nil if they are not encrypted.
+func (c *SecretsConfig) GetEncryptionKey() ([]byte, error) {
+	encoded := c.EncryptionKey
+	if encoded == "" {
+		encoded = os.Getenv(ProjectVarsEncryptionKeyEnv)
+	}
+	if encoded == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "encryption key is not valid base64")
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		return key, nil
+	default:
+		return nil, errors.Errorf("encryption key must be 16, 24, or 32 bytes, not %d", len(key))
+	}
+}
+
+// ProjectVarAdditionalData returns the data that the encryption of a project
+// variable is bound to, so that its ciphertext cannot be moved to another
+// project or variable.
+func ProjectVarAdditionalData(projectID, name string) []byte {
+	return []byte(fmt.Sprintf("%d:%s:%s", len(projectID), projectID, name))
+}
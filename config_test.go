@@ -1,6 +1,7 @@
 package evergreen
 
 import (
+	"os"
 	"path/filepath"
 	"reflect"
 	"testing"
@@ -256,6 +257,16 @@ func (s *AdminSuite) TestAuthConfig() {
 			Users:        []string{"ghuser"},
 			Organization: "ghorg",
 		},
+		OIDC: &OIDCAuthConfig{
+			Issuer:        "https://idp.example.com",
+			ClientId:      "oidcclient",
+			ClientSecret:  "oidcsecret",
+			Scopes:        []string{"openid"},
+			UsernameClaim: "preferred_username",
+			GroupsClaim:   "groups",
+			Groups:        []string{"engineering"},
+			GroupRoles:    []OIDCGroupRole{{Group: "engineering", Role: "project_admin", Project: "mci"}},
+		},
 	}
 
 	err := config.Set()
@@ -266,6 +277,27 @@ func (s *AdminSuite) TestAuthConfig() {
 	s.Equal(config, settings.AuthConfig)
 }
 
+func TestOIDCGroupRolesValidateAndDefault(t *testing.T) {
+	assert := assert.New(t)
+
+	oidc := OIDCAuthConfig{
+		Issuer:       "https://idp.example.com",
+		ClientId:     "client",
+		ClientSecret: "secret",
+		GroupsClaim:  "groups",
+		GroupRoles:   []OIDCGroupRole{{Group: "engineering", Role: "admin"}},
+	}
+	config := AuthConfig{OIDC: &oidc}
+	assert.NoError(config.ValidateAndDefault())
+
+	oidc.GroupRoles = []OIDCGroupRole{{Group: "engineering"}}
+	assert.Error(config.ValidateAndDefault())
+
+	oidc.GroupRoles = []OIDCGroupRole{{Group: "engineering", Role: "admin"}}
+	oidc.GroupsClaim = ""
+	assert.Error(config.ValidateAndDefault())
+}
+
 func (s *AdminSuite) TestHostinitConfig() {
 	config := HostInitConfig{
 		SSHTimeoutSeconds: 10,
@@ -310,6 +342,11 @@ func (s *AdminSuite) TestProvidersConfig() {
 			PrivateKeyID: "gce_key_id",
 			TokenURI:     "gce_token",
 		},
+		Kubernetes: KubernetesConfig{
+			APIServer: "https://kubernetes.example.com",
+			Token:     "k8s_token",
+			Namespace: "evergreen",
+		},
 		OpenStack: OpenStackConfig{
 			IdentityEndpoint: "endpoint",
 			Username:         "username",
@@ -362,6 +399,109 @@ func (s *AdminSuite) TestSchedulerConfig() {
 	s.Equal(config, settings.Scheduler)
 }
 
+func (s *AdminSuite) TestBucketsConfig() {
+	config := BucketsConfig{
+		Filesystem: FilesystemBucketConfig{
+			Root:        "/srv/artifacts",
+			LinkBaseURL: "https://artifacts.example.com",
+		},
+		GCS: GCSBucketConfig{
+			Endpoint: "http://localhost:4443",
+		},
+	}
+
+	err := config.Set()
+	s.NoError(err)
+	settings, err := GetConfig()
+	s.NoError(err)
+	s.NotNil(settings)
+	s.Equal(config, settings.Buckets)
+}
+
+func TestBucketsConfigValidateAndDefault(t *testing.T) {
+	assert := assert.New(t)
+
+	config := BucketsConfig{}
+	assert.NoError(config.ValidateAndDefault())
+	assert.Equal(defaultGCSEndpoint, config.GCS.Endpoint)
+
+	config = BucketsConfig{
+		Filesystem: FilesystemBucketConfig{LinkBaseURL: "https://artifacts.example.com/"},
+		GCS:        GCSBucketConfig{Endpoint: "http://localhost:4443/"},
+	}
+	assert.NoError(config.ValidateAndDefault())
+	assert.Equal("https://artifacts.example.com", config.Filesystem.LinkBaseURL)
+	assert.Equal("http://localhost:4443", config.GCS.Endpoint)
+}
+
+func (s *AdminSuite) TestSecretsConfig() {
+	config := SecretsConfig{
+		EncryptionKey: "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=",
+		Backend:       SecretBackendVault,
+		Vault: VaultConfig{
+			URL:           "http://localhost:8200",
+			Token:         "token",
+			Mount:         "secret",
+			ProjectPrefix: "evergreen/projects",
+			TimeoutSecs:   10,
+		},
+	}
+
+	err := config.Set()
+	s.NoError(err)
+	settings, err := GetConfig()
+	s.NoError(err)
+	s.NotNil(settings)
+	s.Empty(settings.Secrets.EncryptionKey, "the encryption key should never be stored")
+	config.EncryptionKey = ""
+	s.Equal(config, settings.Secrets)
+}
+
+func TestSecretsConfigValidateAndDefault(t *testing.T) {
+	assert := assert.New(t)
+
+	config := SecretsConfig{}
+	assert.NoError(config.ValidateAndDefault())
+	key, err := config.GetEncryptionKey()
+	assert.NoError(err)
+	assert.Nil(key)
+
+	config = SecretsConfig{
+		EncryptionKey: "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=",
+		Backend:       SecretBackendVault,
+		Vault:         VaultConfig{URL: "http://localhost:8200"},
+	}
+	assert.NoError(config.ValidateAndDefault())
+	assert.Equal(defaultVaultMount, config.Vault.Mount)
+	assert.Equal(defaultVaultTimeoutSecs, config.Vault.TimeoutSecs)
+	assert.Equal(defaultVaultProjectPrefix, config.Vault.ProjectPrefix)
+	assert.Equal("evergreen/projects/mci", config.Vault.ProjectSecretPath("mci"))
+	key, err = config.GetEncryptionKey()
+	assert.NoError(err)
+	assert.Len(key, 32)
+
+	// the key can come from the environment instead of the configuration
+	// file
+	assert.NoError(os.Setenv(ProjectVarsEncryptionKeyEnv, "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="))
+	config = SecretsConfig{}
+	key, err = config.GetEncryptionKey()
+	assert.NoError(os.Unsetenv(ProjectVarsEncryptionKeyEnv))
+	assert.NoError(err)
+	assert.Len(key, 32)
+
+	config = SecretsConfig{EncryptionKey: "c2hvcnQ=", Backend: "keychain"}
+	assert.Error(config.ValidateAndDefault())
+
+	config = SecretsConfig{Backend: SecretBackendVault}
+	assert.Error(config.ValidateAndDefault())
+
+	config = SecretsConfig{
+		Backend: SecretBackendVault,
+		Vault:   VaultConfig{URL: "http://localhost:8200", ProjectPrefix: "evergreen/../other"},
+	}
+	assert.Error(config.ValidateAndDefault())
+}
+
 func (s *AdminSuite) TestSlackConfig() {
 	config := SlackConfig{
 		Options: &send.SlackOptions{
@@ -514,6 +654,11 @@ func (s *AdminSuite) TestContainerPoolsConfig() {
 	err := invalidConfig.ValidateAndDefault()
 	s.EqualError(err, "container pool field max_containers must be positive integer")
 
+	invalidConfig.Pools[0].MaxContainers = 5
+	invalidConfig.Pools[0].Namespace = "ns"
+	err = invalidConfig.ValidateAndDefault()
+	s.EqualError(err, "container pool 'test-pool-1' cannot specify both a namespace and a parent distro")
+
 	validConfig := ContainerPoolsConfig{
 		Pools: []ContainerPool{
 			ContainerPool{
@@ -526,8 +671,14 @@ func (s *AdminSuite) TestContainerPoolsConfig() {
 				Id:            "test-pool-2",
 				MaxContainers: 1,
 			},
+			ContainerPool{
+				Namespace:     "ns",
+				Id:            "test-pool-3",
+				MaxContainers: 10,
+			},
 		},
 	}
+	s.NoError(validConfig.ValidateAndDefault())
 
 	err = validConfig.Set()
 	s.NoError(err)
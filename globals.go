@@ -111,6 +111,9 @@ const (
 
 	EvergreenHome = "EVGHOME"
 	MongodbUrl    = "MONGO_URL"
+	// HostSecretEnv is the environment variable from which the agent reads
+	// its host's secret if it is not passed as a flag.
+	HostSecretEnv = "EVERGREEN_HOST_SECRET"
 
 	// Special logging output targets
 	LocalLoggingOverride          = "LOCAL"
@@ -119,6 +122,7 @@ const (
 	DefaultTaskActivator   = ""
 	StepbackTaskActivator  = "stepback"
 	APIServerTaskActivator = "apiserver"
+	AutoRetryTaskActivator = "auto-retry"
 
 	RestRoutePrefix = "rest"
 	APIRoutePrefix  = "api"
@@ -177,6 +181,8 @@ const (
 	ProviderNameEc2Spot     = "ec2-spot"
 	ProviderNameDocker      = "docker"
 	ProviderNameDockerMock  = "docker-mock"
+	ProviderNameKubernetes  = "kubernetes"
+	ProviderNameK8sMock     = "kubernetes-mock"
 	ProviderNameGce         = "gce"
 	ProviderNameStatic      = "static"
 	ProviderNameOpenstack   = "openstack"
@@ -196,6 +202,7 @@ var (
 		ProviderNameEc2Spot,
 		ProviderNameEc2Auto,
 		ProviderNameGce,
+		ProviderNameKubernetes,
 		ProviderNameOpenstack,
 		ProviderNameVsphere,
 		ProviderNameMock,
@@ -215,6 +222,10 @@ const (
 	PatchVersionRequester       = "patch_request"
 	GithubPRRequester           = "github_pull_request"
 	RepotrackerVersionRequester = "gitter_request"
+	MergeTestRequester          = "merge_test"
+	ScheduledVersionRequester   = "scheduled_request"
+	TriggerRequester            = "trigger_request"
+	ManualVersionRequester      = "manual_request"
 )
 
 const (
@@ -269,6 +280,7 @@ var (
 	PatchRequesters = []string{
 		PatchVersionRequester,
 		GithubPRRequester,
+		MergeTestRequester,
 	}
 
 	// UphostStatus is a list of all host statuses that are considered "up."
@@ -316,5 +328,5 @@ func IsSystemActivator(caller string) bool {
 }
 
 func IsPatchRequester(requester string) bool {
-	return requester == PatchVersionRequester || requester == GithubPRRequester
+	return requester == PatchVersionRequester || requester == GithubPRRequester || requester == MergeTestRequester
 }
@@ -46,6 +46,7 @@ func buildApp() *cli.App {
 		operations.Agent(),
 		operations.Admin(),
 		operations.Host(),
+		operations.Distro(),
 
 		// Top-level commands.
 		operations.Keys(),
@@ -56,6 +57,12 @@ func buildApp() *cli.App {
 		operations.TestHistory(),
 		operations.LastGreen(),
 		operations.Subscriptions(),
+		operations.FlakyTests(),
+		operations.Project(),
+		operations.CommitQueue(),
+		operations.Task(),
+		operations.RunTask(),
+		operations.ManualVersion(),
 
 		// Patch creation and management commands (top-level)
 		operations.Patch(),
@@ -82,6 +82,11 @@ func (opts Options) Application(env anser.Environment, evgEnv evergreen.Environm
 		return nil, err
 	}
 
+	varsKey, err := evgEnv.Settings().Secrets.GetEncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+
 	generatorFactories := map[string]migrationGeneratorFactory{
 		// Early Migrations, disabled because the generator queries are not properly indexed.
 		//
@@ -100,6 +105,7 @@ func (opts Options) Application(env anser.Environment, evgEnv evergreen.Environm
 		migrationDistroSecurityGroups:               distroSecurityGroupsGenerator,
 		migrationLegacyNotificationsToSubscriptions: legacyNotificationsToSubscriptionsGenerator,
 		migrationSubscriptionBSONObjectIDToString:   makeBSONObjectIDToStringGenerator("subscriptions"),
+		migrationProjectVarsEncrypt:                 projectVarsEncryptGenerator(varsKey),
 	}
 	catcher := grip.NewBasicCatcher()
 
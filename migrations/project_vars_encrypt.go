@@ -0,0 +1,95 @@
+package migrations
+
+import (
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/anser"
+	"github.com/mongodb/anser/db"
+	"github.com/mongodb/anser/model"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const migrationProjectVarsEncrypt = "project-vars-encrypt"
+
+// projectVarsEncryptGenerator encrypts the variables of project vars
+// documents that were stored before an encryption key was configured. It
+// does nothing if there is no key.
+func projectVarsEncryptGenerator(key []byte) migrationGeneratorFactory {
+	return func(env anser.Environment, args migrationGeneratorFactoryOptions) (anser.Generator, error) {
+		const (
+			projectVarsCollection = "project_vars"
+			migrationName         = "project_vars_encrypt"
+			varsKey               = "vars"
+			encryptedVarsKey      = "encrypted_vars"
+		)
+
+		if key == nil {
+			return nil, nil
+		}
+
+		if err := env.RegisterManualMigrationOperation(migrationName, makeProjectVarsEncryptMigration(args.db, key)); err != nil {
+			return nil, err
+		}
+
+		opts := model.GeneratorOptions{
+			NS: model.Namespace{
+				DB:         args.db,
+				Collection: projectVarsCollection,
+			},
+			Limit: args.limit,
+			Query: bson.M{
+				varsKey:          bson.M{"$exists": true, "$ne": bson.M{}},
+				encryptedVarsKey: bson.M{"$exists": false},
+			},
+			JobID: args.id,
+		}
+
+		return anser.NewManualMigrationGenerator(env, opts, migrationName), nil
+	}
+}
+
+func makeProjectVarsEncryptMigration(database string, key []byte) db.MigrationOperation {
+	const (
+		projectVarsCollection = "project_vars"
+
+		idKey            = "_id"
+		varsKey          = "vars"
+		encryptedVarsKey = "encrypted_vars"
+	)
+
+	return func(session db.Session, rawD bson.RawD) error {
+		defer session.Close()
+
+		id := ""
+		vars := map[string]string{}
+		for _, raw := range rawD {
+			switch raw.Name {
+			case idKey:
+				if err := raw.Value.Unmarshal(&id); err != nil {
+					return errors.Wrap(err, "error unmarshaling id")
+				}
+			case varsKey:
+				if err := raw.Value.Unmarshal(&vars); err != nil {
+					return errors.Wrap(err, "error unmarshaling vars")
+				}
+			}
+		}
+
+		encrypted := make(map[string]string, len(vars))
+		for name, value := range vars {
+			ciphertext, err := util.EncryptString(key, value, evergreen.ProjectVarAdditionalData(id, name))
+			if err != nil {
+				return errors.Wrapf(err, "problem encrypting variable '%s' of project '%s'", name, id)
+			}
+			encrypted[name] = ciphertext
+		}
+
+		return session.DB(database).C(projectVarsCollection).UpdateId(id, bson.M{
+			"$set": bson.M{
+				varsKey:          bson.M{},
+				encryptedVarsKey: encrypted,
+			},
+		})
+	}
+}
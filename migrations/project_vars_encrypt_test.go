@@ -0,0 +1,147 @@
+package migrations
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/mock"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/anser"
+	anserdb "github.com/mongodb/anser/db"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type projectVarsEncryptSuite struct {
+	env      *mock.Environment
+	database string
+	session  anserdb.Session
+	cancel   func()
+	key      []byte
+
+	suite.Suite
+}
+
+func TestProjectVarsEncryptMigration(t *testing.T) {
+	require := require.New(t)
+
+	mgoSession, database, err := db.GetGlobalSessionFactory().GetSession()
+	require.NoError(err)
+	defer mgoSession.Close()
+
+	session := anserdb.WrapSession(mgoSession.Copy())
+	defer session.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := &projectVarsEncryptSuite{
+		env:      &mock.Environment{},
+		session:  session,
+		database: database.Name,
+		cancel:   cancel,
+		key:      []byte("0123456789abcdef0123456789abcdef"),
+	}
+
+	require.NoError(s.env.Configure(ctx, filepath.Join(evergreen.FindEvergreenHome(), testutil.TestDir, testutil.TestSettings), nil))
+	require.NoError(s.env.LocalQueue().Start(ctx))
+
+	anser.ResetEnvironment()
+	require.NoError(anser.GetEnvironment().Setup(s.env.LocalQueue(), s.session))
+	anser.GetEnvironment().RegisterCloser(func() error { cancel(); return nil })
+
+	suite.Run(t, s)
+}
+
+func (s *projectVarsEncryptSuite) SetupTest() {
+	s.NoError(db.ClearCollections(model.ProjectVarsCollection))
+
+	ciphertext, err := util.EncryptString(s.key, "already", evergreen.ProjectVarAdditionalData("encrypted", "c"))
+	s.Require().NoError(err)
+
+	for _, vars := range []bson.M{
+		{
+			"_id": "mci",
+			"vars": bson.M{
+				"a": "1",
+				"b": "2",
+			},
+			"private_vars": bson.M{
+				"b": true,
+			},
+		},
+		{
+			"_id":  "empty",
+			"vars": bson.M{},
+		},
+		{
+			"_id":  "encrypted",
+			"vars": bson.M{},
+			"encrypted_vars": bson.M{
+				"c": ciphertext,
+			},
+		},
+	} {
+		s.NoError(db.Insert(model.ProjectVarsCollection, vars))
+	}
+}
+
+func (s *projectVarsEncryptSuite) TestNoKey() {
+	gen, err := projectVarsEncryptGenerator(nil)(anser.GetEnvironment(), migrationGeneratorFactoryOptions{
+		db:    s.database,
+		limit: 50,
+		id:    "migration-project-vars-encrypt-no-key",
+	})
+	s.NoError(err)
+	s.Nil(gen)
+}
+
+func (s *projectVarsEncryptSuite) TestMigration() {
+	gen, err := projectVarsEncryptGenerator(s.key)(anser.GetEnvironment(), migrationGeneratorFactoryOptions{
+		db:    s.database,
+		limit: 50,
+		id:    "migration-project-vars-encrypt",
+	})
+	s.Require().NoError(err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	gen.Run(ctx)
+	s.NoError(gen.Error())
+
+	for j := range gen.Jobs() {
+		j.Run(ctx)
+		s.NoError(j.Error())
+	}
+
+	out := struct {
+		Vars          map[string]string `bson:"vars"`
+		EncryptedVars map[string]string `bson:"encrypted_vars"`
+		PrivateVars   map[string]bool   `bson:"private_vars"`
+	}{}
+	s.NoError(db.FindOneQ(model.ProjectVarsCollection, db.Query(bson.M{"_id": "mci"}), &out))
+	s.Empty(out.Vars)
+	s.True(out.PrivateVars["b"])
+	s.Require().Len(out.EncryptedVars, 2)
+	for name, expected := range map[string]string{"a": "1", "b": "2"} {
+		value, err := util.DecryptString(s.key, out.EncryptedVars[name], evergreen.ProjectVarAdditionalData("mci", name))
+		s.NoError(err)
+		s.Equal(expected, value)
+	}
+
+	out.EncryptedVars = nil
+	s.NoError(db.FindOneQ(model.ProjectVarsCollection, db.Query(bson.M{"_id": "encrypted"}), &out))
+	s.Require().Len(out.EncryptedVars, 1)
+	value, err := util.DecryptString(s.key, out.EncryptedVars["c"], evergreen.ProjectVarAdditionalData("encrypted", "c"))
+	s.NoError(err)
+	s.Equal("already", value)
+}
+
+func (s *projectVarsEncryptSuite) TearDownSuite() {
+	s.cancel()
+}
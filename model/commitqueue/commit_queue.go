@@ -0,0 +1,145 @@
+package commitqueue
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
+)
+
+// CommitQueueItem is a GitHub pull request waiting in a project's commit
+// queue.
+type CommitQueueItem struct {
+	// Issue is the number of the pull request.
+	Issue string `bson:"issue"`
+	// PatchId is the id of the patch testing the item, once the item has
+	// reached the front of the queue.
+	PatchId     string    `bson:"patch_id,omitempty"`
+	EnqueueTime time.Time `bson:"enqueue_time"`
+	Enqueuer    string    `bson:"enqueuer,omitempty"`
+}
+
+// CommitQueue is the ordered list of pull requests waiting to be tested and
+// merged into a project's branch. Only the item at the front of the queue is
+// tested at a time, so every merge is tested against the branch tip that it
+// is merged into.
+type CommitQueue struct {
+	ProjectID string            `bson:"_id"`
+	Queue     []CommitQueueItem `bson:"queue"`
+}
+
+// Enqueue adds the item to the back of the queue and returns its position.
+// Enqueueing an item that is already in the queue returns its current
+// position.
+func (q *CommitQueue) Enqueue(item CommitQueueItem) (int, error) {
+	if item.Issue == "" {
+		return -1, errors.New("cannot enqueue an item without an issue")
+	}
+	if position := q.FindItem(item.Issue); position >= 0 {
+		return position, nil
+	}
+	if item.EnqueueTime.IsZero() {
+		item.EnqueueTime = time.Now()
+	}
+	// match the precision of the database so the queue can be compared
+	// against its stored copy
+	item.EnqueueTime = item.EnqueueTime.Round(time.Millisecond)
+
+	if err := add(q.ProjectID, item); err != nil {
+		return -1, errors.Wrapf(err, "can't add '%s' to queue '%s'", item.Issue, q.ProjectID)
+	}
+	q.Queue = append(q.Queue, item)
+
+	return len(q.Queue) - 1, nil
+}
+
+// Next returns the item at the front of the queue, or nil if the queue is
+// empty.
+func (q *CommitQueue) Next() *CommitQueueItem {
+	if len(q.Queue) == 0 {
+		return nil
+	}
+
+	return &q.Queue[0]
+}
+
+// FindItem returns the position of the item in the queue, or -1 if it is not
+// in the queue.
+func (q *CommitQueue) FindItem(issue string) int {
+	for i, item := range q.Queue {
+		if item.Issue == issue {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Remove removes the item from the queue. It returns false if the item was
+// not in the queue.
+func (q *CommitQueue) Remove(issue string) (bool, error) {
+	position := q.FindItem(issue)
+	if position < 0 {
+		return false, nil
+	}
+
+	if err := remove(q.ProjectID, issue); err != nil {
+		return false, errors.Wrapf(err, "can't remove item '%s' from queue '%s'", issue, q.ProjectID)
+	}
+	q.Queue = append(q.Queue[:position], q.Queue[position+1:]...)
+
+	return true, nil
+}
+
+// Move moves the item to the given position in the queue. The item at the
+// front of the queue cannot be moved, and no item can be moved in front of it,
+// once it is being tested.
+func (q *CommitQueue) Move(issue string, position int) error {
+	current := q.FindItem(issue)
+	if current < 0 {
+		return errors.Errorf("item '%s' is not in queue '%s'", issue, q.ProjectID)
+	}
+	if position < 0 || position >= len(q.Queue) {
+		return errors.Errorf("position %d is out of range for queue '%s' of length %d", position, q.ProjectID, len(q.Queue))
+	}
+	if q.Queue[0].PatchId != "" && (current == 0 || position == 0) {
+		return errors.Errorf("item '%s' is already being tested", q.Queue[0].Issue)
+	}
+	if current == position {
+		return nil
+	}
+
+	item := q.Queue[current]
+	reordered := make([]CommitQueueItem, 0, len(q.Queue))
+	reordered = append(reordered, q.Queue[:current]...)
+	reordered = append(reordered, q.Queue[current+1:]...)
+	reordered = append(reordered[:position], append([]CommitQueueItem{item}, reordered[position:]...)...)
+
+	if err := replaceQueue(q.ProjectID, q.Queue, reordered); err != nil {
+		return errors.Wrapf(err, "can't move item '%s' in queue '%s'", issue, q.ProjectID)
+	}
+	q.Queue = reordered
+
+	return nil
+}
+
+// SetPatchId records the patch testing the item. It returns false if the
+// item already has a patch, for instance because another job started testing
+// it first.
+func (q *CommitQueue) SetPatchId(issue, patchID string) (bool, error) {
+	position := q.FindItem(issue)
+	if position < 0 {
+		return false, errors.Errorf("item '%s' is not in queue '%s'", issue, q.ProjectID)
+	}
+
+	err := setPatchId(q.ProjectID, issue, patchID)
+	if err == mgo.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "can't set patch for item '%s' in queue '%s'", issue, q.ProjectID)
+	}
+	q.Queue[position].PatchId = patchID
+
+	return true, nil
+}
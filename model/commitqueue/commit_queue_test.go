@@ -0,0 +1,126 @@
+package commitqueue
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/suite"
+)
+
+type CommitQueueSuite struct {
+	suite.Suite
+	q *CommitQueue
+}
+
+func TestCommitQueueSuite(t *testing.T) {
+	suite.Run(t, new(CommitQueueSuite))
+}
+
+func (s *CommitQueueSuite) SetupSuite() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+}
+
+func (s *CommitQueueSuite) SetupTest() {
+	s.Require().NoError(db.ClearCollections(Collection))
+	s.q = &CommitQueue{ProjectID: "mci"}
+	s.Require().NoError(InsertQueue(s.q))
+}
+
+func (s *CommitQueueSuite) enqueue(issues ...string) {
+	for _, issue := range issues {
+		_, err := s.q.Enqueue(CommitQueueItem{Issue: issue, Enqueuer: "me"})
+		s.Require().NoError(err)
+	}
+}
+
+func (s *CommitQueueSuite) issues() []string {
+	dbQueue, err := FindOneId("mci")
+	s.Require().NoError(err)
+	s.Require().NotNil(dbQueue)
+	issues := []string{}
+	for _, item := range dbQueue.Queue {
+		issues = append(issues, item.Issue)
+	}
+	return issues
+}
+
+func (s *CommitQueueSuite) TestEnqueue() {
+	position, err := s.q.Enqueue(CommitQueueItem{Issue: "1"})
+	s.NoError(err)
+	s.Equal(0, position)
+
+	position, err = s.q.Enqueue(CommitQueueItem{Issue: "2"})
+	s.NoError(err)
+	s.Equal(1, position)
+
+	position, err = s.q.Enqueue(CommitQueueItem{Issue: "1"})
+	s.NoError(err)
+	s.Equal(0, position)
+
+	_, err = s.q.Enqueue(CommitQueueItem{})
+	s.Error(err)
+
+	s.Equal([]string{"1", "2"}, s.issues())
+	s.Equal("1", s.q.Next().Issue)
+}
+
+func (s *CommitQueueSuite) TestRemove() {
+	s.enqueue("1", "2", "3")
+
+	found, err := s.q.Remove("2")
+	s.NoError(err)
+	s.True(found)
+
+	found, err = s.q.Remove("2")
+	s.NoError(err)
+	s.False(found)
+
+	s.Equal([]string{"1", "3"}, s.issues())
+}
+
+func (s *CommitQueueSuite) TestMove() {
+	s.enqueue("1", "2", "3", "4")
+
+	s.NoError(s.q.Move("4", 1))
+	s.Equal([]string{"1", "4", "2", "3"}, s.issues())
+
+	s.NoError(s.q.Move("1", 3))
+	s.Equal([]string{"4", "2", "3", "1"}, s.issues())
+
+	s.Error(s.q.Move("5", 0))
+	s.Error(s.q.Move("4", 4))
+
+	claimed, err := s.q.SetPatchId("4", "patch")
+	s.NoError(err)
+	s.True(claimed)
+	s.Error(s.q.Move("2", 0))
+	s.Error(s.q.Move("4", 2))
+	s.NoError(s.q.Move("1", 1))
+	s.Equal([]string{"4", "1", "2", "3"}, s.issues())
+
+	// a stale copy of the queue cannot overwrite a newer one
+	stale := &CommitQueue{ProjectID: "mci", Queue: append([]CommitQueueItem{}, s.q.Queue...)}
+	s.enqueue("5")
+	s.Error(stale.Move("3", 1))
+}
+
+func (s *CommitQueueSuite) TestSetPatchId() {
+	s.enqueue("1")
+	stale := &CommitQueue{ProjectID: "mci", Queue: append([]CommitQueueItem{}, s.q.Queue...)}
+
+	claimed, err := s.q.SetPatchId("1", "patch")
+	s.NoError(err)
+	s.True(claimed)
+	_, err = s.q.SetPatchId("2", "patch")
+	s.Error(err)
+
+	// a job that read the queue before the patch was set can't replace it
+	claimed, err = stale.SetPatchId("1", "other")
+	s.NoError(err)
+	s.False(claimed)
+
+	dbQueue, err := FindOneId("mci")
+	s.NoError(err)
+	s.Equal("patch", dbQueue.Next().PatchId)
+}
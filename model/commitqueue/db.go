@@ -0,0 +1,101 @@
+package commitqueue
+
+import (
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// Collection is the name of the commit queue collection in the database.
+	Collection = "commit_queue"
+)
+
+var (
+	// bson fields for the CommitQueue struct
+	IdKey    = bsonutil.MustHaveTag(CommitQueue{}, "ProjectID")
+	QueueKey = bsonutil.MustHaveTag(CommitQueue{}, "Queue")
+
+	// bson fields for the CommitQueueItem struct
+	IssueKey       = bsonutil.MustHaveTag(CommitQueueItem{}, "Issue")
+	PatchIdKey     = bsonutil.MustHaveTag(CommitQueueItem{}, "PatchId")
+	EnqueueTimeKey = bsonutil.MustHaveTag(CommitQueueItem{}, "EnqueueTime")
+	EnqueuerKey    = bsonutil.MustHaveTag(CommitQueueItem{}, "Enqueuer")
+)
+
+// ById returns a query for the commit queue of the project.
+func ById(projectID string) db.Q {
+	return db.Query(bson.M{IdKey: projectID})
+}
+
+// FindOne returns the commit queue matching the query, or nil if there is
+// none.
+func FindOne(query db.Q) (*CommitQueue, error) {
+	queue := &CommitQueue{}
+	err := db.FindOneQ(Collection, query, queue)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "problem finding commit queue")
+	}
+
+	return queue, nil
+}
+
+// FindOneId returns the commit queue of the project, or nil if the project
+// has none.
+func FindOneId(projectID string) (*CommitQueue, error) {
+	return FindOne(ById(projectID))
+}
+
+// InsertQueue inserts a commit queue into the database.
+func InsertQueue(q *CommitQueue) error {
+	return db.Insert(Collection, q)
+}
+
+func add(projectID string, item CommitQueueItem) error {
+	return db.Update(
+		Collection,
+		bson.M{IdKey: projectID},
+		bson.M{"$push": bson.M{QueueKey: item}},
+	)
+}
+
+func remove(projectID, issue string) error {
+	return db.Update(
+		Collection,
+		bson.M{IdKey: projectID},
+		bson.M{"$pull": bson.M{QueueKey: bson.M{IssueKey: issue}}},
+	)
+}
+
+// replaceQueue overwrites the queue, provided it has not changed since it
+// was read.
+func replaceQueue(projectID string, old, queue []CommitQueueItem) error {
+	return db.Update(
+		Collection,
+		bson.M{IdKey: projectID, QueueKey: old},
+		bson.M{"$set": bson.M{QueueKey: queue}},
+	)
+}
+
+// setPatchId records the patch testing the item, provided the item does not
+// already have a patch. It returns mgo.ErrNotFound if it does.
+func setPatchId(projectID, issue, patchID string) error {
+	return db.Update(
+		Collection,
+		bson.M{
+			IdKey: projectID,
+			QueueKey: bson.M{"$elemMatch": bson.M{
+				IssueKey:   issue,
+				PatchIdKey: bson.M{"$in": []interface{}{nil, ""}},
+			}},
+		},
+		bson.M{"$set": bson.M{
+			bsonutil.GetDottedKeyName(QueueKey, "$", PatchIdKey): patchID,
+		}},
+	)
+}
@@ -23,6 +23,8 @@ var (
 	ExpansionsKey       = bsonutil.MustHaveTag(Distro{}, "Expansions")
 	DisabledKey         = bsonutil.MustHaveTag(Distro{}, "Disabled")
 	ContainerPoolKey    = bsonutil.MustHaveTag(Distro{}, "ContainerPool")
+	TaskPrioritizerKey  = bsonutil.MustHaveTag(Distro{}, "TaskPrioritizer")
+	ResourceLimitsKey   = bsonutil.MustHaveTag(Distro{}, "ResourceLimits")
 )
 
 const Collection = "distro"
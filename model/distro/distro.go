@@ -34,6 +34,22 @@ type Distro struct {
 	Disabled     bool        `bson:"disabled,omitempty" json:"disabled,omitempty" mapstructure:"disabled,omitempty"`
 
 	ContainerPool string `bson:"container_pool,omitempty" json:"container_pool,omitempty" mapstructure:"container_pool,omitempty"`
+
+	// TaskPrioritizer overrides the scheduler's default task prioritizer for
+	// this distro's queue.
+	TaskPrioritizer string `bson:"task_prioritizer,omitempty" json:"task_prioritizer,omitempty" mapstructure:"task_prioritizer,omitempty"`
+
+	// ResourceLimits are the default limits on the resources a task may
+	// use on the distro's hosts.
+	ResourceLimits *ResourceLimits `bson:"resource_limits,omitempty" json:"resource_limits,omitempty" mapstructure:"resource_limits,omitempty"`
+}
+
+// ResourceLimits are the limits on the resources used by a task's processes,
+// which the agent enforces on Linux hosts. Zero values are unlimited.
+type ResourceLimits struct {
+	MemoryMB  int     `bson:"memory_mb,omitempty" json:"memory_mb,omitempty" mapstructure:"memory_mb,omitempty" yaml:"memory_mb,omitempty"`
+	CPUs      float64 `bson:"cpus,omitempty" json:"cpus,omitempty" mapstructure:"cpus,omitempty" yaml:"cpus,omitempty"`
+	Processes int     `bson:"processes,omitempty" json:"processes,omitempty" mapstructure:"processes,omitempty" yaml:"processes,omitempty"`
 }
 
 type DistroGroup []Distro
@@ -75,6 +91,12 @@ func (d *Distro) GenerateName() string {
 		}
 	}
 
+	if d.Provider == evergreen.ProviderNameKubernetes {
+		// Pod names must be lowercase DNS subdomain names
+		r, _ := regexp.Compile("[^a-z0-9.-]+")
+		name = r.ReplaceAllString(strings.ToLower(name), "-")
+	}
+
 	return name
 }
 
@@ -143,3 +165,39 @@ func (distros DistroGroup) GetDistroIds() []string {
 	}
 	return ids
 }
+
+// IsZero returns true if none of the limits are set.
+func (l ResourceLimits) IsZero() bool {
+	return l.MemoryMB == 0 && l.CPUs == 0 && l.Processes == 0
+}
+
+// Restrict returns the limits tightened by the given limits. A limit that is
+// set in both is the smaller of the two, and the given limits can only set
+// limits that are unset here, so they can never loosen a limit.
+func (l ResourceLimits) Restrict(other ResourceLimits) ResourceLimits {
+	if other.MemoryMB != 0 && (l.MemoryMB == 0 || other.MemoryMB < l.MemoryMB) {
+		l.MemoryMB = other.MemoryMB
+	}
+	if other.CPUs != 0 && (l.CPUs == 0 || other.CPUs < l.CPUs) {
+		l.CPUs = other.CPUs
+	}
+	if other.Processes != 0 && (l.Processes == 0 || other.Processes < l.Processes) {
+		l.Processes = other.Processes
+	}
+	return l
+}
+
+// Validate returns an error if any of the limits are negative.
+func (l ResourceLimits) Validate() error {
+	catcher := grip.NewSimpleCatcher()
+	if l.MemoryMB < 0 {
+		catcher.Add(errors.New("memory_mb cannot be negative"))
+	}
+	if l.CPUs < 0 {
+		catcher.Add(errors.New("cpus cannot be negative"))
+	}
+	if l.Processes < 0 {
+		catcher.Add(errors.New("processes cannot be negative"))
+	}
+	return catcher.Resolve()
+}
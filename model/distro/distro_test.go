@@ -31,6 +31,15 @@ func TestGenerateName(t *testing.T) {
 	assert.True(match)
 }
 
+func TestGenerateKubernetesName(t *testing.T) {
+	assert := assert.New(t)
+
+	d := Distro{Id: "Ubuntu_1604 Large", Provider: evergreen.ProviderNameKubernetes}
+	match, err := regexp.MatchString("^[a-z0-9.-]+$", d.GenerateName())
+	assert.NoError(err)
+	assert.True(match)
+}
+
 func TestGenerateGceName(t *testing.T) {
 	assert := assert.New(t)
 
@@ -153,3 +162,21 @@ func TestGetDistroIds(t *testing.T) {
 	ids := hosts.GetDistroIds()
 	assert.Equal([]string{"d1", "d2", "d3"}, ids)
 }
+
+func TestResourceLimits(t *testing.T) {
+	assert := assert.New(t)
+
+	limits := ResourceLimits{MemoryMB: 1024, CPUs: 2}
+	assert.False(limits.IsZero())
+	assert.True(ResourceLimits{}.IsZero())
+
+	restricted := limits.Restrict(ResourceLimits{CPUs: 0.5, Processes: 100})
+	assert.Equal(ResourceLimits{MemoryMB: 1024, CPUs: 0.5, Processes: 100}, restricted)
+	assert.Equal(limits, limits.Restrict(ResourceLimits{}))
+	assert.Equal(limits, limits.Restrict(ResourceLimits{MemoryMB: 8192, CPUs: 4}), "limits the distro sets should not be raised")
+	assert.Equal(ResourceLimits{MemoryMB: 512, CPUs: 2}, limits.Restrict(ResourceLimits{MemoryMB: 512, CPUs: 4}))
+
+	assert.NoError(limits.Validate())
+	assert.Error(ResourceLimits{MemoryMB: -1}.Validate())
+	assert.Error(ResourceLimits{Processes: -1}.Validate())
+}
@@ -0,0 +1,92 @@
+package distro
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Revision is a distro as it was after one of its changes. Revisions are
+// numbered from 1 in the order that the changes happened.
+type Revision struct {
+	Number    int
+	EventType string
+	User      string
+	Timestamp time.Time
+	Distro    Distro
+}
+
+// FieldChange is a difference between two versions of a distro. The field
+// is the path to the JSON field, with the keys of nested objects, such as
+// provider settings, separated by dots.
+type FieldChange struct {
+	Field string      `json:"field" yaml:"field"`
+	Old   interface{} `json:"old,omitempty" yaml:"old,omitempty"`
+	New   interface{} `json:"new,omitempty" yaml:"new,omitempty"`
+}
+
+// Diff returns the fields that differ between two versions of a distro,
+// sorted by field.
+func Diff(oldDistro, newDistro Distro) ([]FieldChange, error) {
+	oldFields, err := toFieldMap(oldDistro)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem reading distro '%s'", oldDistro.Id)
+	}
+	newFields, err := toFieldMap(newDistro)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem reading distro '%s'", newDistro.Id)
+	}
+
+	changes := diffFields("", oldFields, newFields)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes, nil
+}
+
+func toFieldMap(d Distro) (map[string]interface{}, error) {
+	out, err := json.Marshal(d)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	fields := map[string]interface{}{}
+	if err = json.Unmarshal(out, &fields); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return fields, nil
+}
+
+func diffFields(prefix string, oldFields, newFields map[string]interface{}) []FieldChange {
+	keys := map[string]bool{}
+	for k := range oldFields {
+		keys[k] = true
+	}
+	for k := range newFields {
+		keys[k] = true
+	}
+
+	changes := []FieldChange{}
+	for k := range keys {
+		oldVal, newVal := oldFields[k], newFields[k]
+		if reflect.DeepEqual(oldVal, newVal) {
+			continue
+		}
+
+		field := prefix + k
+		oldMap, oldIsMap := oldVal.(map[string]interface{})
+		newMap, newIsMap := newVal.(map[string]interface{})
+		if (oldIsMap || oldVal == nil) && (newIsMap || newVal == nil) {
+			changes = append(changes, diffFields(field+".", oldMap, newMap)...)
+			continue
+		}
+
+		changes = append(changes, FieldChange{
+			Field: field,
+			Old:   oldVal,
+			New:   newVal,
+		})
+	}
+
+	return changes
+}
@@ -0,0 +1,56 @@
+package distro
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	oldDistro := Distro{
+		Id:       "d",
+		Arch:     "linux_amd64",
+		Provider: evergreen.ProviderNameEc2OnDemand,
+		ProviderSettings: &map[string]interface{}{
+			"ami":           "ami-1",
+			"instance_type": "m3.large",
+		},
+		PoolSize: 10,
+	}
+
+	changes, err := Diff(oldDistro, oldDistro)
+	require.NoError(err)
+	assert.Empty(changes)
+
+	newDistro := oldDistro
+	newDistro.ProviderSettings = &map[string]interface{}{
+		"ami":           "ami-2",
+		"instance_type": "m3.large",
+		"region":        "us-east-1",
+	}
+	newDistro.PoolSize = 20
+	newDistro.Setup = "echo hi"
+
+	changes, err = Diff(oldDistro, newDistro)
+	require.NoError(err)
+	assert.Equal([]FieldChange{
+		{Field: "pool_size", Old: float64(10), New: float64(20)},
+		{Field: "settings.ami", Old: "ami-1", New: "ami-2"},
+		{Field: "settings.region", New: "us-east-1"},
+		{Field: "setup", New: "echo hi"},
+	}, changes)
+
+	// every field of a new distro is a change
+	changes, err = Diff(Distro{}, oldDistro)
+	require.NoError(err)
+	fields := []string{}
+	for _, c := range changes {
+		fields = append(fields, c.Field)
+	}
+	assert.Equal([]string{"_id", "arch", "pool_size", "provider", "settings.ami", "settings.instance_type"}, fields)
+}
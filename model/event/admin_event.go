@@ -20,14 +20,39 @@ func init() {
 const (
 	ResourceTypeAdmin     = "ADMIN"
 	EventTypeValueChanged = "CONFIG_VALUE_CHANGED"
+	EventTypeRoleChanged  = "ROLE_CHANGED"
+
+	// RolesSection is the section of admin events that record changes to
+	// roles and role assignments.
+	RolesSection = "roles"
+)
+
+// Actions recorded by role change events.
+const (
+	RoleActionUpdate   = "update"
+	RoleActionRemove   = "remove"
+	RoleActionAssign   = "assign"
+	RoleActionUnassign = "unassign"
 )
 
 // AdminEventData holds all potential data properties of a logged admin event
 type AdminEventData struct {
-	GUID    string           `bson:"guid" json:"guid"`
-	User    string           `bson:"user" json:"user"`
-	Section string           `bson:"section" json:"section"`
-	Changes ConfigDataChange `bson:"changes" json:"changes"`
+	GUID       string           `bson:"guid" json:"guid"`
+	User       string           `bson:"user" json:"user"`
+	Section    string           `bson:"section" json:"section"`
+	Changes    ConfigDataChange `bson:"changes" json:"changes"`
+	RoleChange *RoleDataChange  `bson:"role_change,omitempty" json:"role_change,omitempty"`
+}
+
+// RoleDataChange describes a change to a role, in which case Before and
+// After hold the role's permissions, or to the roles assigned to a user.
+type RoleDataChange struct {
+	Action   string   `bson:"action" json:"action"`
+	Role     string   `bson:"role" json:"role"`
+	Assignee string   `bson:"assignee,omitempty" json:"assignee,omitempty"`
+	Project  string   `bson:"project,omitempty" json:"project,omitempty"`
+	Before   []string `bson:"before,omitempty" json:"before,omitempty"`
+	After    []string `bson:"after,omitempty" json:"after,omitempty"`
 }
 
 type ConfigDataChange struct {
@@ -41,10 +66,11 @@ type rawConfigDataChange struct {
 }
 
 type rawAdminEventData struct {
-	GUID    string              `bson:"guid"`
-	User    string              `bson:"user"`
-	Section string              `bson:"section"`
-	Changes rawConfigDataChange `bson:"changes"`
+	GUID       string              `bson:"guid"`
+	User       string              `bson:"user"`
+	Section    string              `bson:"section"`
+	Changes    rawConfigDataChange `bson:"changes"`
+	RoleChange *RoleDataChange     `bson:"role_change,omitempty"`
 }
 
 func LogAdminEvent(section string, before, after evergreen.ConfigSection, user string) error {
@@ -82,6 +108,33 @@ func LogAdminEvent(section string, before, after evergreen.ConfigSection, user s
 	return nil
 }
 
+// LogRoleEvent records a change to a role or role assignment made by the
+// given user.
+func LogRoleEvent(change RoleDataChange, user string) error {
+	event := EventLogEntry{
+		Timestamp: time.Now(),
+		EventType: EventTypeRoleChanged,
+		Data: AdminEventData{
+			User:       user,
+			Section:    RolesSection,
+			RoleChange: &change,
+			GUID:       util.RandomString(),
+		},
+		ResourceType: ResourceTypeAdmin,
+	}
+
+	logger := NewDBEventLogger(AllLogCollection)
+	if err := logger.LogEvent(&event); err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"resource_type": ResourceTypeAdmin,
+			"message":       "error logging event",
+			"source":        "event-log-fail",
+		}))
+		return errors.Wrap(err, "Error logging role event")
+	}
+	return nil
+}
+
 func stripInteriorSections(config *evergreen.Settings) *evergreen.Settings {
 	copy := &evergreen.Settings{}
 	*copy = *config
@@ -133,9 +186,13 @@ func FindAdmin(query db.Q) ([]EventLogEntry, error) {
 
 func convertRaw(in rawAdminEventData) (*AdminEventData, error) {
 	out := AdminEventData{
-		Section: in.Section,
-		User:    in.User,
-		GUID:    in.GUID,
+		Section:    in.Section,
+		User:       in.User,
+		GUID:       in.GUID,
+		RoleChange: in.RoleChange,
+	}
+	if in.RoleChange != nil {
+		return &out, nil
 	}
 
 	// get the correct implementation of the interface from the registry
@@ -174,6 +231,9 @@ func RevertConfig(guid string, user string) error {
 	}
 	evt := events[0]
 	data := evt.Data.(*AdminEventData)
+	if data.RoleChange != nil {
+		return errors.Errorf("event %s records a role change, which cannot be reverted", guid)
+	}
 	current := evergreen.ConfigRegistry.GetSection(data.Section)
 	if current == nil {
 		return errors.Errorf("unable to find section %s", data.Section)
@@ -41,6 +41,7 @@ const (
 	EventHostTeardown              = "HOST_TEARDOWN"
 	EventHostTerminatedExternally  = "HOST_TERMINATED_EXTERNALLY"
 	EventHostExpirationWarningSent = "HOST_EXPIRATION_WARNING_SENT"
+	EventHostSpotInterrupted       = "HOST_SPOT_INTERRUPTED"
 )
 
 // implements EventData
@@ -140,6 +141,12 @@ func LogHostRunningTaskCleared(hostId string, taskId string) {
 		HostEventData{TaskId: taskId})
 }
 
+// LogHostSpotInterrupted is used when the agent reports that the cloud
+// provider will reclaim the host's spot instance.
+func LogHostSpotInterrupted(hostId string, taskId string, action string) {
+	LogHostEvent(hostId, EventHostSpotInterrupted, HostEventData{TaskId: taskId, Logs: action})
+}
+
 func LogHostTaskPidSet(hostId string, taskPid string) {
 	LogHostEvent(hostId, EventHostTaskPidSet, HostEventData{TaskPid: taskPid})
 }
@@ -25,10 +25,27 @@ type TaskQueueInfo struct {
 	ExpectedDuration time.Duration `bson:"ex_d" json:"expected_duration,"`
 }
 
+// HostForecast records the demand that the predictive host allocator
+// forecast for a distro from the tasks that historically arrived at the same
+// time of the week, and the hosts it decided to spawn.
+type HostForecast struct {
+	// Lookahead is how far ahead of the scheduler run the forecast looks.
+	Lookahead         time.Duration `bson:"l" json:"lookahead"`
+	PredictedTasks    float64       `bson:"p_t" json:"predicted_tasks"`
+	PredictedDuration time.Duration `bson:"p_d" json:"predicted_duration"`
+	// PredictedHosts is the number of hosts needed to run the predicted
+	// tasks within the lookahead.
+	PredictedHosts int `bson:"p_h" json:"predicted_hosts"`
+	// ReactiveHosts is the number of hosts that the queue alone called for.
+	ReactiveHosts int `bson:"r_h" json:"reactive_hosts"`
+	NewHosts      int `bson:"n_h" json:"new_hosts"`
+}
+
 // implements EventData
 type SchedulerEventData struct {
 	TaskQueueInfo TaskQueueInfo `bson:"tq_info" json:"task_queue_info"`
 	DistroId      string        `bson:"d_id" json:"distro_id"`
+	HostForecast  *HostForecast `bson:"forecast,omitempty" json:"host_forecast,omitempty"`
 }
 
 // LogSchedulerEvent takes care of logging the statistics about the scheduler at a given time.
@@ -23,6 +23,7 @@ const (
 	TaskStarted                 = "TASK_STARTED"
 	TaskFinished                = "TASK_FINISHED"
 	TaskRestarted               = "TASK_RESTARTED"
+	TaskAutoRestarted           = "TASK_AUTO_RESTARTED"
 	TaskActivated               = "TASK_ACTIVATED"
 	TaskDeactivated             = "TASK_DEACTIVATED"
 	TaskAbortRequest            = "TASK_ABORT_REQUEST"
@@ -39,6 +40,7 @@ type TaskEventData struct {
 	UserId    string `bson:"u_id,omitempty" json:"user_id,omitempty"`
 	Status    string `bson:"s,omitempty" json:"status,omitempty"`
 	JiraIssue string `bson:"jira,omitempty" json:"jira,omitempty"`
+	Reason    string `bson:"reason,omitempty" json:"reason,omitempty"`
 
 	Timestamp time.Time `bson:"ts,omitempty" json:"timestamp,omitempty"`
 	Priority  int64     `bson:"pri,omitempty" json:"priority,omitempty"`
@@ -127,6 +129,12 @@ func LogTaskRestarted(taskId string, execution int, userId string) {
 	logTaskEvent(taskId, TaskRestarted, TaskEventData{Execution: execution, UserId: userId})
 }
 
+// LogTaskAutoRestarted records that a task was restarted by its retry policy
+// after a failure of the given type.
+func LogTaskAutoRestarted(taskId string, execution int, failureType string) {
+	logTaskEvent(taskId, TaskAutoRestarted, TaskEventData{Execution: execution, Reason: failureType})
+}
+
 func LogTaskActivated(taskId string, execution int, userId string) {
 	logTaskEvent(taskId, TaskActivated, TaskEventData{Execution: execution, UserId: userId})
 }
@@ -0,0 +1,193 @@
+package flaky
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// Collection is the name of the flaky tests collection in the database.
+	Collection = "flaky_tests"
+)
+
+// FlakyTest records how often a test in a project's task has flipped between
+// passing and failing across executions of the same task, and whether the
+// project has quarantined it.
+type FlakyTest struct {
+	Id       string `bson:"_id" json:"id"`
+	Project  string `bson:"project" json:"project"`
+	TaskName string `bson:"task_name" json:"task_name"`
+	TestFile string `bson:"test_file" json:"test_file"`
+
+	// Score is the fraction of restarted tasks in the detection window in
+	// which the test both passed and failed.
+	Score          float64   `bson:"score" json:"score"`
+	NumFlips       int       `bson:"num_flips" json:"num_flips"`
+	NumRuns        int       `bson:"num_runs" json:"num_runs"`
+	LastFlipTaskId string    `bson:"last_flip_task_id,omitempty" json:"last_flip_task_id,omitempty"`
+	LastUpdated    time.Time `bson:"last_updated" json:"last_updated"`
+
+	Quarantined   bool      `bson:"quarantined" json:"quarantined"`
+	QuarantinedBy string    `bson:"quarantined_by,omitempty" json:"quarantined_by,omitempty"`
+	QuarantinedAt time.Time `bson:"quarantined_at,omitempty" json:"quarantined_at,omitempty"`
+}
+
+var (
+	IdKey             = bsonutil.MustHaveTag(FlakyTest{}, "Id")
+	ProjectKey        = bsonutil.MustHaveTag(FlakyTest{}, "Project")
+	TaskNameKey       = bsonutil.MustHaveTag(FlakyTest{}, "TaskName")
+	TestFileKey       = bsonutil.MustHaveTag(FlakyTest{}, "TestFile")
+	ScoreKey          = bsonutil.MustHaveTag(FlakyTest{}, "Score")
+	NumFlipsKey       = bsonutil.MustHaveTag(FlakyTest{}, "NumFlips")
+	NumRunsKey        = bsonutil.MustHaveTag(FlakyTest{}, "NumRuns")
+	LastFlipTaskIdKey = bsonutil.MustHaveTag(FlakyTest{}, "LastFlipTaskId")
+	LastUpdatedKey    = bsonutil.MustHaveTag(FlakyTest{}, "LastUpdated")
+	QuarantinedKey    = bsonutil.MustHaveTag(FlakyTest{}, "Quarantined")
+	QuarantinedByKey  = bsonutil.MustHaveTag(FlakyTest{}, "QuarantinedBy")
+	QuarantinedAtKey  = bsonutil.MustHaveTag(FlakyTest{}, "QuarantinedAt")
+)
+
+// MakeId returns the id of the flaky test document for a test in a task of
+// a project.
+func MakeId(project, taskName, testFile string) string {
+	return fmt.Sprintf("%s|%s|%s", project, taskName, testFile)
+}
+
+// ById returns a query for the flaky test with the given id.
+func ById(id string) db.Q {
+	return db.Query(bson.M{IdKey: id})
+}
+
+// ByProject returns a query for the tests of a project that are either flaky
+// or quarantined, most flaky first.
+func ByProject(project string) db.Q {
+	return db.Query(bson.M{
+		ProjectKey: project,
+		"$or": []bson.M{
+			{ScoreKey: bson.M{"$gt": 0}},
+			{QuarantinedKey: true},
+		},
+	}).Sort([]string{"-" + ScoreKey, TaskNameKey, TestFileKey})
+}
+
+// QuarantinedByTask returns a query for the quarantined tests of a task in a
+// project.
+func QuarantinedByTask(project, taskName string) db.Q {
+	return db.Query(bson.M{
+		ProjectKey:     project,
+		TaskNameKey:    taskName,
+		QuarantinedKey: true,
+	})
+}
+
+// FindOne returns the flaky test matching the query, or nil if there is none.
+func FindOne(query db.Q) (*FlakyTest, error) {
+	test := &FlakyTest{}
+	err := db.FindOneQ(Collection, query, test)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+	return test, err
+}
+
+// Find returns all flaky tests matching the query.
+func Find(query db.Q) ([]FlakyTest, error) {
+	tests := []FlakyTest{}
+	err := db.FindAllQ(Collection, query, &tests)
+	return tests, err
+}
+
+// FindQuarantinedTests returns the set of quarantined test files for a task
+// in a project.
+func FindQuarantinedTests(project, taskName string) (map[string]bool, error) {
+	tests, err := Find(QuarantinedByTask(project, taskName).WithFields(TestFileKey))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding quarantined tests for task '%s' in project '%s'", taskName, project)
+	}
+
+	quarantined := make(map[string]bool, len(tests))
+	for _, test := range tests {
+		quarantined[test.TestFile] = true
+	}
+	return quarantined, nil
+}
+
+// UpsertScore records the latest flakiness score for the test, leaving its
+// quarantine state untouched.
+func (t *FlakyTest) UpsertScore() error {
+	t.Id = MakeId(t.Project, t.TaskName, t.TestFile)
+	_, err := db.Upsert(
+		Collection,
+		bson.M{IdKey: t.Id},
+		bson.M{
+			"$set": bson.M{
+				ProjectKey:        t.Project,
+				TaskNameKey:       t.TaskName,
+				TestFileKey:       t.TestFile,
+				ScoreKey:          t.Score,
+				NumFlipsKey:       t.NumFlips,
+				NumRunsKey:        t.NumRuns,
+				LastFlipTaskIdKey: t.LastFlipTaskId,
+				LastUpdatedKey:    t.LastUpdated,
+			},
+		},
+	)
+	return errors.Wrapf(err, "error updating score for flaky test '%s'", t.Id)
+}
+
+// ResetStaleScores zeroes the scores of the project's tests that were not
+// updated since the given time, so that tests which stopped flipping drop off
+// the flaky list. Quarantined tests stay listed until they are released.
+func ResetStaleScores(project string, updatedBefore time.Time) error {
+	_, err := db.UpdateAll(
+		Collection,
+		bson.M{
+			ProjectKey:     project,
+			LastUpdatedKey: bson.M{"$lt": updatedBefore},
+			ScoreKey:       bson.M{"$gt": 0},
+		},
+		bson.M{
+			"$set": bson.M{
+				ScoreKey:    0,
+				NumFlipsKey: 0,
+				NumRunsKey:  0,
+			},
+		},
+	)
+	return errors.Wrapf(err, "error resetting stale flaky test scores for project '%s'", project)
+}
+
+// SetQuarantined quarantines or releases a test in a task of a project. Tests
+// that have not been detected as flaky can be quarantined as well.
+func SetQuarantined(project, taskName, testFile string, quarantined bool, user string) error {
+	if project == "" || taskName == "" || testFile == "" {
+		return errors.New("project, task name, and test file must all be specified")
+	}
+
+	set := bson.M{
+		ProjectKey:     project,
+		TaskNameKey:    taskName,
+		TestFileKey:    testFile,
+		QuarantinedKey: quarantined,
+	}
+	update := bson.M{"$set": set}
+	if quarantined {
+		set[QuarantinedByKey] = user
+		set[QuarantinedAtKey] = time.Now()
+	} else {
+		update["$unset"] = bson.M{
+			QuarantinedByKey: 1,
+			QuarantinedAtKey: 1,
+		}
+	}
+
+	id := MakeId(project, taskName, testFile)
+	_, err := db.Upsert(Collection, bson.M{IdKey: id}, update)
+	return errors.Wrapf(err, "error setting quarantine state for test '%s'", id)
+}
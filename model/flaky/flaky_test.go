@@ -0,0 +1,135 @@
+package flaky
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestScoreTests(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+
+	tasks := []task.Task{
+		{Id: "t1", DisplayName: "unit"},
+		{Id: "t2", DisplayName: "unit"},
+		{Id: "t3", DisplayName: "lint"},
+	}
+	results := []testresult.TestResult{
+		// flips in t1, stable in t2
+		{TaskID: "t1", Execution: 0, TestFile: "a", Status: evergreen.TestFailedStatus},
+		{TaskID: "t1", Execution: 1, TestFile: "a", Status: evergreen.TestSucceededStatus},
+		{TaskID: "t2", Execution: 0, TestFile: "a", Status: evergreen.TestSucceededStatus},
+		{TaskID: "t2", Execution: 1, TestFile: "a", Status: evergreen.TestSucceededStatus},
+		// consistently failing
+		{TaskID: "t1", Execution: 0, TestFile: "b", Status: evergreen.TestFailedStatus},
+		{TaskID: "t1", Execution: 1, TestFile: "b", Status: evergreen.TestFailedStatus},
+		// only ran once
+		{TaskID: "t2", Execution: 1, TestFile: "c", Status: evergreen.TestFailedStatus},
+		// flips in every run
+		{TaskID: "t3", Execution: 0, TestFile: "d", Status: evergreen.TestSucceededStatus},
+		{TaskID: "t3", Execution: 1, TestFile: "d", Status: "skip"},
+		{TaskID: "t3", Execution: 2, TestFile: "d", Status: evergreen.TestFailedStatus},
+		// result for a task that was not restarted
+		{TaskID: "t4", Execution: 0, TestFile: "e", Status: evergreen.TestFailedStatus},
+	}
+
+	scores := ScoreTests("proj", tasks, results, now)
+	assert.Len(scores, 2)
+
+	assert.Equal(MakeId("proj", "lint", "d"), scores[0].Id)
+	assert.Equal(1.0, scores[0].Score)
+	assert.Equal(1, scores[0].NumFlips)
+	assert.Equal(1, scores[0].NumRuns)
+	assert.Equal("t3", scores[0].LastFlipTaskId)
+
+	assert.Equal(MakeId("proj", "unit", "a"), scores[1].Id)
+	assert.Equal(0.5, scores[1].Score)
+	assert.Equal(1, scores[1].NumFlips)
+	assert.Equal(2, scores[1].NumRuns)
+	assert.Equal("t1", scores[1].LastFlipTaskId)
+	assert.Equal(now, scores[1].LastUpdated)
+
+	assert.Empty(ScoreTests("proj", nil, results, now))
+}
+
+type flakySuite struct {
+	suite.Suite
+}
+
+func TestFlakySuite(t *testing.T) {
+	suite.Run(t, new(flakySuite))
+}
+
+func (s *flakySuite) SetupSuite() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+}
+
+func (s *flakySuite) SetupTest() {
+	s.NoError(db.ClearCollections(Collection, task.Collection, testresult.Collection))
+}
+
+func (s *flakySuite) TestUpdateProjectScores() {
+	for _, t := range []task.Task{
+		{Id: "t1", DisplayName: "unit", Project: "proj", Execution: 1, Status: evergreen.TaskSucceeded, FinishTime: time.Now()},
+		{Id: "t2", DisplayName: "unit", Project: "other", Execution: 1, Status: evergreen.TaskSucceeded, FinishTime: time.Now()},
+	} {
+		s.Require().NoError(t.Insert())
+	}
+	s.Require().NoError(testresult.InsertMany([]testresult.TestResult{
+		{TaskID: "t1", Execution: 0, TestFile: "a", Status: evergreen.TestFailedStatus},
+		{TaskID: "t1", Execution: 1, TestFile: "a", Status: evergreen.TestSucceededStatus},
+		{TaskID: "t2", Execution: 0, TestFile: "a", Status: evergreen.TestFailedStatus},
+		{TaskID: "t2", Execution: 1, TestFile: "a", Status: evergreen.TestSucceededStatus},
+	}))
+
+	s.Require().NoError(SetQuarantined("proj", "unit", "a", true, "me"))
+	s.Require().NoError(UpdateProjectScores("proj", DetectionWindow))
+
+	tests, err := Find(ByProject("proj"))
+	s.Require().NoError(err)
+	s.Require().Len(tests, 1)
+	s.Equal(1.0, tests[0].Score)
+	s.True(tests[0].Quarantined)
+	s.Equal("me", tests[0].QuarantinedBy)
+
+	// scores of tests that stop flipping are reset, but quarantined tests
+	// remain listed
+	s.Require().NoError(db.ClearCollections(testresult.Collection))
+	s.Require().NoError(UpdateProjectScores("proj", DetectionWindow))
+	tests, err = Find(ByProject("proj"))
+	s.Require().NoError(err)
+	s.Require().Len(tests, 1)
+	s.Zero(tests[0].Score)
+
+	s.Require().NoError(SetQuarantined("proj", "unit", "a", false, "me"))
+	tests, err = Find(ByProject("proj"))
+	s.NoError(err)
+	s.Empty(tests)
+}
+
+func (s *flakySuite) TestFindQuarantinedTests() {
+	s.Error(SetQuarantined("proj", "", "a", true, "me"))
+	s.NoError(SetQuarantined("proj", "unit", "a", true, "me"))
+	s.NoError(SetQuarantined("proj", "unit", "b", true, "me"))
+	s.NoError(SetQuarantined("proj", "unit", "b", false, "me"))
+	s.NoError(SetQuarantined("proj", "lint", "c", true, "me"))
+
+	quarantined, err := FindQuarantinedTests("proj", "unit")
+	s.NoError(err)
+	s.Equal(map[string]bool{"a": true}, quarantined)
+
+	test, err := FindOne(ById(MakeId("proj", "unit", "b")))
+	s.NoError(err)
+	require.NotNil(s.T(), test)
+	s.False(test.Quarantined)
+	s.Empty(test.QuarantinedBy)
+}
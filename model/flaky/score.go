@@ -0,0 +1,157 @@
+package flaky
+
+import (
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// DetectionWindow is how far back the detector looks for restarted tasks.
+const DetectionWindow = 7 * 24 * time.Hour
+
+type testKey struct {
+	taskName string
+	testFile string
+}
+
+type testRun struct {
+	taskId  string
+	results map[int]string
+}
+
+// ScoreTests computes flakiness scores from the test results of restarted
+// tasks. A test flips in a task when it passed in one execution of the task
+// and failed in another; since every execution of a task runs the same
+// revision, a flip cannot be explained by a code change. The score of a test
+// is the number of tasks in which it flipped divided by the number of tasks
+// in which it ran in at least two executions. Only tests that flipped at
+// least once are returned, most flaky first.
+func ScoreTests(project string, tasks []task.Task, results []testresult.TestResult, now time.Time) []FlakyTest {
+	taskNames := make(map[string]string, len(tasks))
+	for _, t := range tasks {
+		taskNames[t.Id] = t.DisplayName
+	}
+
+	runs := map[testKey]map[string]*testRun{}
+	for _, result := range results {
+		taskName, ok := taskNames[result.TaskID]
+		if !ok {
+			continue
+		}
+		if result.Status != evergreen.TestSucceededStatus && result.Status != evergreen.TestFailedStatus {
+			continue
+		}
+
+		key := testKey{taskName: taskName, testFile: result.TestFile}
+		if runs[key] == nil {
+			runs[key] = map[string]*testRun{}
+		}
+		run, ok := runs[key][result.TaskID]
+		if !ok {
+			run = &testRun{taskId: result.TaskID, results: map[int]string{}}
+			runs[key][result.TaskID] = run
+		}
+		// a test reported more than once in an execution counts as failed
+		// if any of its reports failed
+		if run.results[result.Execution] != evergreen.TestFailedStatus {
+			run.results[result.Execution] = result.Status
+		}
+	}
+
+	scores := []FlakyTest{}
+	for key, byTask := range runs {
+		test := FlakyTest{
+			Project:     project,
+			TaskName:    key.taskName,
+			TestFile:    key.testFile,
+			LastUpdated: now,
+		}
+
+		taskIds := make([]string, 0, len(byTask))
+		for id := range byTask {
+			taskIds = append(taskIds, id)
+		}
+		sort.Strings(taskIds)
+
+		for _, id := range taskIds {
+			run := byTask[id]
+			if len(run.results) < 2 {
+				continue
+			}
+			test.NumRuns++
+
+			passed, failed := false, false
+			for _, status := range run.results {
+				passed = passed || status == evergreen.TestSucceededStatus
+				failed = failed || status == evergreen.TestFailedStatus
+			}
+			if passed && failed {
+				test.NumFlips++
+				test.LastFlipTaskId = run.taskId
+			}
+		}
+
+		if test.NumFlips == 0 {
+			continue
+		}
+		test.Score = float64(test.NumFlips) / float64(test.NumRuns)
+		test.Id = MakeId(project, key.taskName, key.testFile)
+		scores = append(scores, test)
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return scores[i].Id < scores[j].Id
+	})
+
+	return scores
+}
+
+// UpdateProjectScores rescores the tests of a project from the test results
+// of the tasks restarted within the window, and persists the scores.
+func UpdateProjectScores(project string, window time.Duration) error {
+	now := time.Now()
+
+	tasks, err := task.Find(task.ByRestartedInProject(project, now.Add(-window)).
+		WithFields(task.IdKey, task.DisplayNameKey))
+	if err != nil {
+		return errors.Wrapf(err, "error finding restarted tasks for project '%s'", project)
+	}
+
+	var results []testresult.TestResult
+	if len(tasks) > 0 {
+		ids := make([]string, 0, len(tasks))
+		for _, t := range tasks {
+			ids = append(ids, t.Id)
+		}
+		results, err = testresult.Find(testresult.ByTaskIDs(ids).
+			WithFields(testresult.TaskIDKey, testresult.ExecutionKey, testresult.TestFileKey, testresult.StatusKey))
+		if err != nil {
+			return errors.Wrapf(err, "error finding test results for project '%s'", project)
+		}
+	}
+
+	scores := ScoreTests(project, tasks, results, now)
+	catcher := grip.NewBasicCatcher()
+	for i := range scores {
+		catcher.Add(scores[i].UpsertScore())
+	}
+	catcher.Add(ResetStaleScores(project, now))
+
+	grip.Info(message.Fields{
+		"message":         "updated flaky test scores",
+		"project":         project,
+		"restarted_tasks": len(tasks),
+		"flaky_tests":     len(scores),
+	})
+
+	return catcher.Resolve()
+}
@@ -57,6 +57,7 @@ var (
 	VolumeSizeKey                = bsonutil.MustHaveTag(Host{}, "VolumeTotalSize")
 	NotificationsKey             = bsonutil.MustHaveTag(Host{}, "Notifications")
 	LastCommunicationTimeKey     = bsonutil.MustHaveTag(Host{}, "LastCommunicationTime")
+	SpotInterruptionTimeKey      = bsonutil.MustHaveTag(Host{}, "SpotInterruptionTime")
 	UserHostKey                  = bsonutil.MustHaveTag(Host{}, "UserHost")
 	ZoneKey                      = bsonutil.MustHaveTag(Host{}, "Zone")
 	ProjectKey                   = bsonutil.MustHaveTag(Host{}, "Project")
@@ -142,6 +143,16 @@ func CountRunningHosts(distroID string) (int, error) {
 	return num, errors.Wrap(err, "problem finding running hosts")
 }
 
+// CountSpotInterruptions returns the number of the distro's hosts whose spot
+// instances were reported as interrupted since the given time.
+func CountSpotInterruptions(distroID string, since time.Time) (int, error) {
+	num, err := Count(db.Query(bson.M{
+		bsonutil.GetDottedKeyName(DistroKey, distro.IdKey): distroID,
+		SpotInterruptionTimeKey:                            bson.M{"$gte": since},
+	}))
+	return num, errors.Wrap(err, "problem counting spot interruptions")
+}
+
 func AllRunningHosts(distroID string) ([]Host, error) {
 	allHosts, err := Find(db.Query(runningHostsQuery(distroID)))
 	if err != nil {
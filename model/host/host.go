@@ -71,6 +71,10 @@ type Host struct {
 	LastTaskCompletedTime time.Time `bson:"last_task_completed_time" json:"last_task_completed_time"`
 	LastCommunicationTime time.Time `bson:"last_communication" json:"last_communication"`
 
+	// SpotInterruptionTime is when the agent reported that the cloud
+	// provider will reclaim the host's spot instance.
+	SpotInterruptionTime time.Time `bson:"spot_interruption_time,omitempty" json:"spot_interruption_time,omitempty"`
+
 	Status    string `bson:"status" json:"status"`
 	StartedBy string `bson:"started_by" json:"started_by"`
 	// UserHost is alwayas false, and will be removed
@@ -486,6 +490,27 @@ func (h *Host) SetAgentRevision(agentRevision string) error {
 	return nil
 }
 
+// SetSpotInterrupted records that the host's spot instance will be
+// reclaimed, returning false if it was already recorded.
+func (h *Host) SetSpotInterrupted(interruptionTime time.Time) (bool, error) {
+	err := UpdateOne(
+		bson.M{
+			IdKey:                   h.Id,
+			SpotInterruptionTimeKey: bson.M{"$exists": false},
+		},
+		bson.M{
+			"$set": bson.M{SpotInterruptionTimeKey: interruptionTime},
+		})
+	if db.ResultsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	h.SpotInterruptionTime = interruptionTime
+	return true, nil
+}
+
 // IsWaitingForAgent provides a local predicate for the logic in the
 // "NeedsNewAgent" query.
 func (h *Host) IsWaitingForAgent() bool {
@@ -1061,6 +1086,16 @@ func FindAllRunningParentsByContainerPool(poolId string) ([]Host, error) {
 	return Find(query)
 }
 
+// CountContainersByContainerPool returns the number of up containers in the container pool specified by the given ID
+func CountContainersByContainerPool(poolId string) (int, error) {
+	hostContainerPoolId := bsonutil.GetDottedKeyName(ContainerPoolSettingsKey, evergreen.ContainerPoolIdKey)
+	return db.Count(Collection, bson.M{
+		HasContainersKey:    bson.M{"$ne": true},
+		StatusKey:           bson.M{"$in": evergreen.UphostStatus},
+		hostContainerPoolId: poolId,
+	})
+}
+
 // CountUphostParents returns the number of initializing parent host intent documents
 func CountUphostParentsByContainerPool(poolId string) (int, error) {
 	hostContainerPoolId := bsonutil.GetDottedKeyName(ContainerPoolSettingsKey, evergreen.ContainerPoolIdKey)
@@ -22,6 +22,10 @@ const (
 
 	// GithubAlias is a special alias to specify default variants and tasks for GitHub pull requests.
 	GithubAlias = "__github"
+
+	// CommitQueueAlias is a special alias to specify the variants and tasks
+	// that must pass before the commit queue merges a pull request.
+	CommitQueueAlias = "__commit_queue"
 )
 
 // githubIntent represents an intent to create a patch build as a result of a
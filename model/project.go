@@ -49,6 +49,8 @@ type Project struct {
 	TaskGroups      []TaskGroup                `yaml:"task_groups,omitempty" bson:"task_groups"`
 	Tasks           []ProjectTask              `yaml:"tasks,omitempty" bson:"tasks"`
 	ExecTimeoutSecs int                        `yaml:"exec_timeout_secs,omitempty" bson:"exec_timeout_secs"`
+	Retry           *RetryPolicy               `yaml:"retry,omitempty" bson:"retry,omitempty"`
+	Parameters      []ParameterDefinition      `yaml:"parameters,omitempty" bson:"parameters,omitempty"`
 
 	// Flag that indicates a project as requiring user authentication
 	Private bool `yaml:"private,omitempty" bson:"private"`
@@ -331,6 +333,14 @@ type ProjectTask struct {
 	//   3. false = overriding the project setting with false
 	Patchable *bool `yaml:"patchable,omitempty" bson:"patchable,omitempty"`
 	Stepback  *bool `yaml:"stepback,omitempty" bson:"stepback,omitempty"`
+
+	// Retry overrides the retry policy of the project.
+	Retry *RetryPolicy `yaml:"retry,omitempty" bson:"retry,omitempty"`
+
+	// ResourceLimits restrict the resource limits of the distro the task
+	// runs on. They can lower the distro's limits or set limits the distro
+	// leaves unset, but never raise them.
+	ResourceLimits *distro.ResourceLimits `yaml:"resource_limits,omitempty" bson:"resource_limits,omitempty"`
 }
 
 // TaskIdTable is a map of [variant, task display name]->[task id].
@@ -597,6 +607,10 @@ func populateExpansions(d *distro.Distro, v *version.Version, bv *BuildVariant,
 		expansions.Put(e.Key, e.Value)
 	}
 	expansions.Update(bv.Expansions)
+
+	for _, param := range v.Parameters {
+		expansions.Put(param.Key, param.Value)
+	}
 	return expansions
 }
 
@@ -1057,6 +1071,65 @@ func (p *Project) BuildProjectTVPairs(patchDoc *patch.Patch, alias string) {
 	patchDoc.SyncVariantsTasks(tasks.TVPairsToVariantTasks())
 }
 
+// BuildScheduleTVPairs returns the tasks and display tasks that a version
+// schedule runs, including their dependencies.
+func (p *Project) BuildScheduleTVPairs(schedule *VersionSchedule) (TaskVariantPairs, error) {
+	return p.BuildVersionTVPairs(schedule.Variants, schedule.Tasks, schedule.Alias)
+}
+
+// BuildVersionTVPairs returns the tasks and display tasks, including their
+// dependencies, for a version that runs either the named tasks on the named
+// variants, where an empty list or "*" means all of them, or the variants and
+// tasks matched by a patch alias.
+func (p *Project) BuildVersionTVPairs(variants, tasks []string, alias string) (TaskVariantPairs, error) {
+	if len(variants) == 0 || util.StringSliceContains(variants, "*") {
+		variants = []string{}
+		for _, bv := range p.BuildVariants {
+			if bv.Disabled {
+				continue
+			}
+			variants = append(variants, bv.Name)
+		}
+	}
+
+	if len(tasks) == 0 || util.StringSliceContains(tasks, "*") {
+		tasks = []string{}
+		for _, t := range p.Tasks {
+			tasks = append(tasks, t.Name)
+		}
+	}
+
+	pairs := []TVPair{}
+	if alias != "" {
+		variants, tasks = []string{}, []string{}
+		aliasPairs, displayTaskPairs, err := p.BuildProjectTVPairsWithAlias(alias)
+		if err != nil {
+			return TaskVariantPairs{}, errors.Wrapf(err, "problem getting task/variant pairs for alias '%s'", alias)
+		}
+		pairs = append(pairs, aliasPairs...)
+		for _, pair := range displayTaskPairs {
+			if !util.StringSliceContains(variants, pair.Variant) {
+				variants = append(variants, pair.Variant)
+			}
+			if !util.StringSliceContains(tasks, pair.TaskName) {
+				tasks = append(tasks, pair.TaskName)
+			}
+		}
+	} else {
+		for _, v := range variants {
+			for _, t := range tasks {
+				if p.FindTaskForVariant(t, v) != nil {
+					pairs = append(pairs, TVPair{v, t})
+				}
+			}
+		}
+	}
+
+	tvPairs := extractDisplayTasks(pairs, tasks, variants, p)
+	tvPairs.ExecTasks = IncludePatchDependencies(p, tvPairs.ExecTasks)
+	return tvPairs, nil
+}
+
 // TasksThatCallCommand returns a map of tasks that call a given command.
 func (p *Project) TasksThatCallCommand(find string) map[string]int {
 	// get all functions that call `generate.tasks`
@@ -1181,9 +1254,10 @@ func (p *Project) BuildProjectTVPairsWithAlias(alias string) ([]TVPair, []TVPair
 func FetchVersionsAndAssociatedBuilds(project *Project, skip int, numVersions int) ([]version.Version, map[string][]build.Build, error) {
 
 	// fetch the versions from the db
-	versionsFromDB, err := version.Find(version.ByProjectId(project.Identifier).
+	versionsFromDB, err := version.Find(version.ByProjectIdForWaterfall(project.Identifier).
 		WithFields(
 			version.RevisionKey,
+			version.RequesterKey,
 			version.ErrorsKey,
 			version.WarningsKey,
 			version.IgnoredKey,
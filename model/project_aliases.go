@@ -1,6 +1,10 @@
 package model
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/mongodb/anser/bsonutil"
 	"github.com/pkg/errors"
@@ -106,6 +110,41 @@ func (p *ProjectAlias) Upsert() error {
 	return nil
 }
 
+// ValidateProjectAliases checks that each alias has a name, a valid variant
+// regex, and either a valid task regex or tags, and returns a description of
+// each problem.
+func ValidateProjectAliases(aliases []ProjectAlias) []string {
+	errs := []string{}
+	for i, pd := range aliases {
+		if strings.TrimSpace(pd.Alias) == "" {
+			errs = append(errs, fmt.Sprintf("alias name #%d can't be empty string", i+1))
+		}
+		if strings.TrimSpace(pd.Variant) == "" {
+			errs = append(errs, fmt.Sprintf("variant regex #%d can't be empty string", i+1))
+		}
+		if strings.TrimSpace(pd.Task) == "" && len(pd.Tags) == 0 {
+			errs = append(errs, fmt.Sprintf("must specify either task regex or tags on line #%d ", i+1))
+		}
+
+		if _, err := regexp.Compile(pd.Variant); err != nil {
+			errs = append(errs, fmt.Sprintf("variant regex #%d is invalid", i+1))
+		}
+		if _, err := regexp.Compile(pd.Task); err != nil {
+			errs = append(errs, fmt.Sprintf("task regex #%d is invalid", i+1))
+		}
+	}
+	return errs
+}
+
+// RemoveProjectAliasesForProject removes all of a project's aliases.
+func RemoveProjectAliasesForProject(projectID string) error {
+	err := db.RemoveAll(ProjectAliasCollection, bson.M{projectIDKey: projectID})
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove project aliases for project '%s'", projectID)
+	}
+	return nil
+}
+
 // RemoveProjectAlias removes a project alias with the given document ID from the
 // database.
 func RemoveProjectAlias(id string) error {
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"sort"
+
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+// ParameterDefinition declares a parameter that users can set when they
+// start a manual version of a project. The values of a version's parameters
+// become expansions for all of its tasks.
+type ParameterDefinition struct {
+	Name        string `yaml:"name" bson:"name"`
+	Default     string `yaml:"default,omitempty" bson:"default,omitempty"`
+	Description string `yaml:"description,omitempty" bson:"description,omitempty"`
+	// AllowedValues, if set, are the only values the parameter may take.
+	AllowedValues []string `yaml:"allowed_values,omitempty" bson:"allowed_values,omitempty"`
+}
+
+// Validate checks that the parameter definition has a name and that its
+// default is one of its allowed values.
+func (d *ParameterDefinition) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	if d.Name == "" {
+		catcher.Add(errors.New("parameter must have a name"))
+	}
+	if d.Default != "" && len(d.AllowedValues) > 0 && !util.StringSliceContains(d.AllowedValues, d.Default) {
+		catcher.Add(errors.Errorf("default '%s' for parameter '%s' is not an allowed value", d.Default, d.Name))
+	}
+	return catcher.Resolve()
+}
+
+// GetParameterDefinition returns the definition of the parameter with the
+// given name, or nil if the project declares no such parameter.
+func (p *Project) GetParameterDefinition(name string) *ParameterDefinition {
+	for i := range p.Parameters {
+		if p.Parameters[i].Name == name {
+			return &p.Parameters[i]
+		}
+	}
+	return nil
+}
+
+// ValidateParameters checks user supplied parameter values against the
+// parameters the project declares, and returns the version parameters
+// sorted by name, with the defaults of the parameters not supplied filled
+// in. Every value must be for a declared parameter and, if the parameter
+// has allowed values, must be one of them.
+func (p *Project) ValidateParameters(values map[string]string) ([]version.Parameter, error) {
+	catcher := grip.NewBasicCatcher()
+	for name, value := range values {
+		def := p.GetParameterDefinition(name)
+		if def == nil {
+			catcher.Add(errors.Errorf("project '%s' has no parameter '%s'", p.Identifier, name))
+			continue
+		}
+		if len(def.AllowedValues) > 0 && !util.StringSliceContains(def.AllowedValues, value) {
+			catcher.Add(errors.Errorf("'%s' is not an allowed value for parameter '%s' (allowed values: %v)",
+				value, name, def.AllowedValues))
+		}
+	}
+	if catcher.HasErrors() {
+		return nil, catcher.Resolve()
+	}
+
+	params := []version.Parameter{}
+	for _, def := range p.Parameters {
+		value, ok := values[def.Name]
+		if !ok {
+			value = def.Default
+		}
+		params = append(params, version.Parameter{Key: def.Name, Value: value})
+	}
+	sort.Slice(params, func(i, j int) bool { return params[i].Key < params[j].Key })
+
+	return params, nil
+}
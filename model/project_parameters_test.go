@@ -0,0 +1,69 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParameterDefinitionValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError((&ParameterDefinition{Name: "release"}).Validate())
+	assert.NoError((&ParameterDefinition{Name: "channel", Default: "beta", AllowedValues: []string{"beta", "stable"}}).Validate())
+	assert.Error((&ParameterDefinition{Default: "beta"}).Validate())
+	assert.Error((&ParameterDefinition{Name: "channel", Default: "nightly", AllowedValues: []string{"beta", "stable"}}).Validate())
+}
+
+func TestValidateParameters(t *testing.T) {
+	assert := assert.New(t)
+
+	p := &Project{
+		Identifier: "proj",
+		Parameters: []ParameterDefinition{
+			{Name: "release"},
+			{Name: "channel", Default: "beta", AllowedValues: []string{"beta", "stable"}},
+		},
+	}
+
+	params, err := p.ValidateParameters(nil)
+	assert.NoError(err)
+	assert.Equal([]version.Parameter{{Key: "channel", Value: "beta"}, {Key: "release", Value: ""}}, params)
+
+	params, err = p.ValidateParameters(map[string]string{"release": "4.2.0", "channel": "stable"})
+	assert.NoError(err)
+	assert.Equal([]version.Parameter{{Key: "channel", Value: "stable"}, {Key: "release", Value: "4.2.0"}}, params)
+
+	_, err = p.ValidateParameters(map[string]string{"channel": "nightly"})
+	assert.Error(err)
+
+	_, err = p.ValidateParameters(map[string]string{"unknown": "value"})
+	assert.Error(err)
+}
+
+func TestParseProjectParameters(t *testing.T) {
+	assert := assert.New(t)
+
+	yml := `
+parameters:
+- name: channel
+  default: beta
+  description: the release channel
+  allowed_values: [beta, stable]
+tasks:
+- name: release
+buildvariants:
+- name: ubuntu
+  run_on: [ubuntu1604]
+  tasks: [release]
+`
+	p := &Project{}
+	assert.NoError(LoadProjectInto([]byte(yml), "proj", p))
+	assert.Equal([]ParameterDefinition{{
+		Name:          "channel",
+		Default:       "beta",
+		Description:   "the release channel",
+		AllowedValues: []string{"beta", "stable"},
+	}}, p.Parameters)
+}
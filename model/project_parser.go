@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 
+	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
@@ -59,6 +60,8 @@ type parserProject struct {
 	TaskGroups      []parserTaskGroup          `yaml:"task_groups,omitempty"`
 	Tasks           []parserTask               `yaml:"tasks,omitempty"`
 	ExecTimeoutSecs int                        `yaml:"exec_timeout_secs,omitempty"`
+	Retry           *RetryPolicy               `yaml:"retry,omitempty"`
+	Parameters      []ParameterDefinition      `yaml:"parameters,omitempty"`
 
 	// Matrix code
 	Axes []matrixAxis `yaml:"axes,omitempty"`
@@ -88,15 +91,17 @@ func (ptg *parserTaskGroup) tags() []string { return ptg.Tags }
 
 // parserTask represents an intermediary state of task definitions.
 type parserTask struct {
-	Name            string              `yaml:"name,omitempty"`
-	Priority        int64               `yaml:"priority,omitempty"`
-	ExecTimeoutSecs int                 `yaml:"exec_timeout_secs,omitempty"`
-	DependsOn       parserDependencies  `yaml:"depends_on,omitempty"`
-	Requires        taskSelectors       `yaml:"requires,omitempty"`
-	Commands        []PluginCommandConf `yaml:"commands,omitempty"`
-	Tags            parserStringSlice   `yaml:"tags,omitempty"`
-	Patchable       *bool               `yaml:"patchable,omitempty"`
-	Stepback        *bool               `yaml:"stepback,omitempty"`
+	Name            string                 `yaml:"name,omitempty"`
+	Priority        int64                  `yaml:"priority,omitempty"`
+	ExecTimeoutSecs int                    `yaml:"exec_timeout_secs,omitempty"`
+	DependsOn       parserDependencies     `yaml:"depends_on,omitempty"`
+	Requires        taskSelectors          `yaml:"requires,omitempty"`
+	Commands        []PluginCommandConf    `yaml:"commands,omitempty"`
+	Tags            parserStringSlice      `yaml:"tags,omitempty"`
+	Patchable       *bool                  `yaml:"patchable,omitempty"`
+	Stepback        *bool                  `yaml:"stepback,omitempty"`
+	Retry           *RetryPolicy           `yaml:"retry,omitempty"`
+	ResourceLimits  *distro.ResourceLimits `yaml:"resource_limits,omitempty"`
 }
 
 type displayTask struct {
@@ -463,6 +468,8 @@ func translateProject(pp *parserProject) (*Project, []error) {
 		Modules:         pp.Modules,
 		Functions:       pp.Functions,
 		ExecTimeoutSecs: pp.ExecTimeoutSecs,
+		Retry:           pp.Retry,
+		Parameters:      pp.Parameters,
 	}
 	tse := NewParserTaskSelectorEvaluator(pp.Tasks)
 	tgse := newTaskGroupSelectorEvaluator(pp.TaskGroups)
@@ -509,6 +516,8 @@ func evaluateTaskUnits(tse *taskSelectorEvaluator, tgse *tagSelectorEvaluator, v
 			Tags:            pt.Tags,
 			Patchable:       pt.Patchable,
 			Stepback:        pt.Stepback,
+			Retry:           pt.Retry,
+			ResourceLimits:  pt.ResourceLimits,
 		}
 		t.DependsOn, errs = evaluateDependsOn(tse.tagEval, tgse, vse, pt.DependsOn)
 		evalErrs = append(evalErrs, errs...)
@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"math"
 	"net/url"
+	"regexp"
+	"strings"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
@@ -30,12 +33,28 @@ type ProjectRef struct {
 	LocalConfig        string `bson:"local_config" json:"local_config" yaml:"local_config"`
 	DeactivatePrevious bool   `bson:"deactivate_previous" json:"deactivate_previous" yaml:"deactivate_previous"`
 
+	// RepoURL is the URL of the GitLab instance hosting the repository for
+	// gitlab projects, and the remote to clone for git projects.
+	RepoURL string `bson:"repo_url" json:"repo_url" yaml:"repo_url"`
+
 	// TracksPushEvents, if true indicates that Repotracker is triggered by
 	// Github PushEvents for this project, instead of the Repotracker runner
 	TracksPushEvents bool `bson:"tracks_push_events" json:"tracks_push_events" yaml:"tracks_push_events"`
 
 	PRTestingEnabled bool `bson:"pr_testing_enabled" json:"pr_testing_enabled" yaml:"pr_testing_enabled"`
 
+	// CommitQueue configures the queue that tests pull requests against the
+	// branch tip before merging them
+	CommitQueue CommitQueueParams `bson:"commit_queue" json:"commit_queue" yaml:"commit_queue"`
+
+	// Schedules create versions from the tip of the branch on a cron
+	// schedule, independent of commits
+	Schedules []VersionSchedule `bson:"schedules,omitempty" json:"schedules,omitempty" yaml:"schedules,omitempty"`
+
+	// Triggers create versions in this project when versions, builds, or
+	// tasks in other projects finish
+	Triggers []TriggerDefinition `bson:"triggers,omitempty" json:"triggers,omitempty" yaml:"triggers,omitempty"`
+
 	//Tracked determines whether or not the project is discoverable in the UI
 	Tracked          bool `bson:"tracked" json:"tracked"`
 	PatchingDisabled bool `bson:"patching_disabled" json:"patching_disabled"`
@@ -51,6 +70,252 @@ type ProjectRef struct {
 	RepotrackerError *RepositoryErrorDetails `bson:"repotracker_error" json:"repotracker_error"`
 }
 
+// CommitQueueParams configures the commit queue of a project.
+type CommitQueueParams struct {
+	Enabled bool `bson:"enabled" json:"enabled" yaml:"enabled"`
+	// MergeMethod is the GitHub merge method used to merge pull requests:
+	// one of "squash", "merge", or "rebase"
+	MergeMethod string `bson:"merge_method" json:"merge_method" yaml:"merge_method"`
+}
+
+// ValidMergeMethods are the GitHub merge methods a commit queue can use.
+var ValidMergeMethods = []string{"squash", "merge", "rebase"}
+
+// ValidRepoURLPrefixes are the schemes a project's repo url can use. The
+// url is passed to git, so other transports, such as local paths and
+// "ext::" remotes, are not allowed.
+var ValidRepoURLPrefixes = []string{"https://", "ssh://", "git@"}
+
+// ValidateRepoURL checks that a repo url, if there is one, is an https or
+// ssh url that git cannot mistake for an option.
+func ValidateRepoURL(repoURL string) error {
+	if repoURL == "" {
+		return nil
+	}
+	if strings.HasPrefix(repoURL, "-") {
+		return errors.Errorf("invalid repo url '%s'", repoURL)
+	}
+	for _, prefix := range ValidRepoURLPrefixes {
+		if strings.HasPrefix(repoURL, prefix) && len(repoURL) > len(prefix) {
+			return nil
+		}
+	}
+	return errors.Errorf("repo url '%s' must start with one of: %s", repoURL, strings.Join(ValidRepoURLPrefixes, ", "))
+}
+
+// VersionSchedule configures versions that are created on a cron schedule at
+// the tip of a project's branch. A schedule runs either the named tasks on
+// the named variants, where an empty list or "*" means all of them, or the
+// variants and tasks matched by a patch alias. Cron specifications are
+// evaluated in UTC.
+type VersionSchedule struct {
+	ID       string   `bson:"id" json:"id" yaml:"id"`
+	Cron     string   `bson:"cron" json:"cron" yaml:"cron"`
+	Variants []string `bson:"variants,omitempty" json:"variants,omitempty" yaml:"variants,omitempty"`
+	Tasks    []string `bson:"tasks,omitempty" json:"tasks,omitempty" yaml:"tasks,omitempty"`
+	Alias    string   `bson:"alias,omitempty" json:"alias,omitempty" yaml:"alias,omitempty"`
+	Message  string   `bson:"message,omitempty" json:"message,omitempty" yaml:"message,omitempty"`
+	Disabled bool     `bson:"disabled,omitempty" json:"disabled,omitempty" yaml:"disabled,omitempty"`
+}
+
+// Validate checks that the schedule has an id and a valid cron specification,
+// and does not name both an alias and variants or tasks.
+func (s *VersionSchedule) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	if strings.TrimSpace(s.ID) == "" {
+		catcher.Add(errors.New("schedule must have an id"))
+	}
+	if _, err := util.ParseCron(s.Cron); err != nil {
+		catcher.Add(errors.Wrapf(err, "schedule '%s' has an invalid cron specification", s.ID))
+	}
+	if s.Alias != "" && (len(s.Variants) != 0 || len(s.Tasks) != 0) {
+		catcher.Add(errors.Errorf("schedule '%s' cannot specify both an alias and variants or tasks", s.ID))
+	}
+	return catcher.Resolve()
+}
+
+// ValidateSchedules validates each of the project's version schedules, and
+// checks that their ids are unique.
+func (projectRef *ProjectRef) ValidateSchedules() error {
+	catcher := grip.NewBasicCatcher()
+	ids := map[string]bool{}
+	for i := range projectRef.Schedules {
+		catcher.Add(projectRef.Schedules[i].Validate())
+		if ids[projectRef.Schedules[i].ID] {
+			catcher.Add(errors.Errorf("duplicate schedule id '%s'", projectRef.Schedules[i].ID))
+		}
+		ids[projectRef.Schedules[i].ID] = true
+	}
+	return catcher.Resolve()
+}
+
+// GetSchedule returns the project's version schedule with the given id, or
+// nil if there is none.
+func (projectRef *ProjectRef) GetSchedule(id string) *VersionSchedule {
+	for i := range projectRef.Schedules {
+		if projectRef.Schedules[i].ID == id {
+			return &projectRef.Schedules[i]
+		}
+	}
+	return nil
+}
+
+const (
+	ProjectTriggerLevelVersion = "version"
+	ProjectTriggerLevelBuild   = "build"
+	ProjectTriggerLevelTask    = "task"
+)
+
+// ValidProjectTriggerLevels are the kinds of upstream object whose outcome a
+// project trigger can act on.
+var ValidProjectTriggerLevels = []string{
+	ProjectTriggerLevelVersion,
+	ProjectTriggerLevelBuild,
+	ProjectTriggerLevelTask,
+}
+
+// TriggerDefinition configures a downstream trigger: when a version, build,
+// or task in the upstream project finishes with the given status, a version
+// is created in the project that defines the trigger. Builds and tasks may be
+// narrowed with regular expressions on their variant and task names. The
+// downstream version runs the tasks of the patch alias, or all tasks if there
+// is no alias, with the configuration in ConfigFile if it is set, and
+// otherwise with the configuration at the tip of the downstream project.
+type TriggerDefinition struct {
+	ID                string `bson:"id" json:"id" yaml:"id"`
+	Project           string `bson:"project" json:"project" yaml:"project"`
+	Level             string `bson:"level" json:"level" yaml:"level"`
+	Status            string `bson:"status,omitempty" json:"status,omitempty" yaml:"status,omitempty"`
+	BuildVariantRegex string `bson:"variant_regex,omitempty" json:"variant_regex,omitempty" yaml:"variant_regex,omitempty"`
+	TaskRegex         string `bson:"task_regex,omitempty" json:"task_regex,omitempty" yaml:"task_regex,omitempty"`
+	ConfigFile        string `bson:"config_file,omitempty" json:"config_file,omitempty" yaml:"config_file,omitempty"`
+	Alias             string `bson:"alias,omitempty" json:"alias,omitempty" yaml:"alias,omitempty"`
+}
+
+// Validate checks that the trigger names an upstream project and a valid
+// level, and that its regular expressions compile. A trigger without a status
+// fires on success.
+func (t *TriggerDefinition) Validate(downstreamProject string) error {
+	catcher := grip.NewBasicCatcher()
+	if strings.TrimSpace(t.ID) == "" {
+		catcher.Add(errors.New("trigger must have an id"))
+	}
+	if t.Project == "" {
+		catcher.Add(errors.Errorf("trigger '%s' must specify an upstream project", t.ID))
+	} else if t.Project == downstreamProject {
+		catcher.Add(errors.Errorf("trigger '%s' cannot trigger on its own project", t.ID))
+	}
+	if !util.StringSliceContains(ValidProjectTriggerLevels, t.Level) {
+		catcher.Add(errors.Errorf("trigger '%s' has invalid level '%s'", t.ID, t.Level))
+	}
+	if t.Status == "" {
+		t.Status = evergreen.VersionSucceeded
+	}
+	if t.Status != evergreen.VersionSucceeded && t.Status != evergreen.VersionFailed {
+		catcher.Add(errors.Errorf("trigger '%s' has invalid status '%s'", t.ID, t.Status))
+	}
+	if t.Level == ProjectTriggerLevelVersion && (t.BuildVariantRegex != "" || t.TaskRegex != "") {
+		catcher.Add(errors.Errorf("version trigger '%s' cannot filter on variants or tasks", t.ID))
+	}
+	if t.Level == ProjectTriggerLevelBuild && t.TaskRegex != "" {
+		catcher.Add(errors.Errorf("build trigger '%s' cannot filter on tasks", t.ID))
+	}
+	if _, err := regexp.Compile(t.BuildVariantRegex); err != nil {
+		catcher.Add(errors.Wrapf(err, "trigger '%s' has an invalid variant regex", t.ID))
+	}
+	if _, err := regexp.Compile(t.TaskRegex); err != nil {
+		catcher.Add(errors.Wrapf(err, "trigger '%s' has an invalid task regex", t.ID))
+	}
+	return catcher.Resolve()
+}
+
+// ValidateTriggers validates each of the project's triggers, and checks that
+// their ids are unique.
+func (projectRef *ProjectRef) ValidateTriggers() error {
+	catcher := grip.NewBasicCatcher()
+	ids := map[string]bool{}
+	for i := range projectRef.Triggers {
+		catcher.Add(projectRef.Triggers[i].Validate(projectRef.Identifier))
+		if ids[projectRef.Triggers[i].ID] {
+			catcher.Add(errors.Errorf("duplicate trigger id '%s'", projectRef.Triggers[i].ID))
+		}
+		ids[projectRef.Triggers[i].ID] = true
+	}
+	return catcher.Resolve()
+}
+
+// ValidateGitHubSettings checks that the project's commit queue has a valid
+// merge method, defaulting it if it is unset, and that no other project
+// tracking the same branch already has the commit queue or pull request
+// testing enabled, since GitHub events for the branch can only go to one
+// project.
+func (projectRef *ProjectRef) ValidateGitHubSettings() error {
+	if projectRef.CommitQueue.Enabled {
+		if projectRef.CommitQueue.MergeMethod == "" {
+			projectRef.CommitQueue.MergeMethod = ValidMergeMethods[0]
+		}
+		if !util.StringSliceContains(ValidMergeMethods, projectRef.CommitQueue.MergeMethod) {
+			return errors.Errorf("invalid merge method '%s'", projectRef.CommitQueue.MergeMethod)
+		}
+	}
+	if !projectRef.CommitQueue.Enabled && !projectRef.PRTestingEnabled {
+		return nil
+	}
+
+	conflictingRefs, err := FindProjectRefsByRepoAndBranch(projectRef.Owner, projectRef.Repo, projectRef.Branch)
+	if err != nil {
+		return errors.Wrap(err, "problem finding projects tracking the same branch")
+	}
+	catcher := grip.NewBasicCatcher()
+	for _, ref := range conflictingRefs {
+		if ref.Identifier == projectRef.Identifier {
+			continue
+		}
+		if projectRef.CommitQueue.Enabled && ref.CommitQueue.Enabled {
+			catcher.Add(errors.Errorf("Cannot enable the commit queue in this repo, must disable in '%s' first", ref.Identifier))
+		}
+		if projectRef.PRTestingEnabled && ref.PRTestingEnabled {
+			catcher.Add(errors.Errorf("Cannot enable PR Testing in this repo, must disable in '%s' first", ref.Identifier))
+		}
+	}
+	return catcher.Resolve()
+}
+
+// ValidateSettings checks the project's settings before they are saved: that
+// it tracks a branch of a repository of a valid kind, and that its schedules,
+// triggers, and GitHub settings are valid.
+func (projectRef *ProjectRef) ValidateSettings() error {
+	catcher := grip.NewBasicCatcher()
+	if strings.TrimSpace(projectRef.Identifier) == "" {
+		catcher.Add(errors.New("project must have an identifier"))
+	}
+	if projectRef.Branch == "" {
+		catcher.Add(errors.New("no branch specified"))
+	}
+	if projectRef.RepoKind != "" && !util.StringSliceContains(ValidRepoTypes, projectRef.RepoKind) {
+		catcher.Add(errors.Errorf("invalid repo kind '%s'", projectRef.RepoKind))
+	}
+	if projectRef.RepoKind == GitRepoType && projectRef.RepoURL == "" {
+		catcher.Add(errors.New("git projects must specify a repo url"))
+	}
+	catcher.Add(ValidateRepoURL(projectRef.RepoURL))
+	catcher.Add(projectRef.ValidateSchedules())
+	catcher.Add(projectRef.ValidateTriggers())
+	for _, trigger := range projectRef.Triggers {
+		if trigger.Project == "" {
+			continue
+		}
+		upstream, err := FindOneProjectRef(trigger.Project)
+		if err != nil {
+			catcher.Add(errors.Wrapf(err, "problem finding upstream project '%s'", trigger.Project))
+		} else if upstream == nil {
+			catcher.Add(errors.Errorf("trigger '%s' has upstream project '%s', which does not exist", trigger.ID, trigger.Project))
+		}
+	}
+	catcher.Add(projectRef.ValidateGitHubSettings())
+	return catcher.Resolve()
+}
+
 // RepositoryErrorDetails indicates whether or not there is an invalid revision and if there is one,
 // what the guessed merge base revision is.
 type RepositoryErrorDetails struct {
@@ -85,6 +350,7 @@ var (
 	ProjectRefRepoKey               = bsonutil.MustHaveTag(ProjectRef{}, "Repo")
 	ProjectRefBranchKey             = bsonutil.MustHaveTag(ProjectRef{}, "Branch")
 	ProjectRefRepoKindKey           = bsonutil.MustHaveTag(ProjectRef{}, "RepoKind")
+	ProjectRefRepoURLKey            = bsonutil.MustHaveTag(ProjectRef{}, "RepoURL")
 	ProjectRefEnabledKey            = bsonutil.MustHaveTag(ProjectRef{}, "Enabled")
 	ProjectRefPrivateKey            = bsonutil.MustHaveTag(ProjectRef{}, "Private")
 	ProjectRefBatchTimeKey          = bsonutil.MustHaveTag(ProjectRef{}, "BatchTime")
@@ -98,6 +364,9 @@ var (
 	ProjectRefAdminsKey             = bsonutil.MustHaveTag(ProjectRef{}, "Admins")
 	projectRefTracksPushEventsKey   = bsonutil.MustHaveTag(ProjectRef{}, "TracksPushEvents")
 	projectRefPRTestingEnabledKey   = bsonutil.MustHaveTag(ProjectRef{}, "PRTestingEnabled")
+	projectRefCommitQueueKey        = bsonutil.MustHaveTag(ProjectRef{}, "CommitQueue")
+	projectRefSchedulesKey          = bsonutil.MustHaveTag(ProjectRef{}, "Schedules")
+	projectRefTriggersKey           = bsonutil.MustHaveTag(ProjectRef{}, "Triggers")
 	projectRefPatchingDisabledKey   = bsonutil.MustHaveTag(ProjectRef{}, "PatchingDisabled")
 	projectRefNotifyOnFailureKey    = bsonutil.MustHaveTag(ProjectRef{}, "NotifyOnBuildFailure")
 )
@@ -129,6 +398,15 @@ func FindOneProjectRef(identifier string) (*ProjectRef, error) {
 	return projectRef, err
 }
 
+// RemoveProjectRef removes the project ref with the given identifier.
+func RemoveProjectRef(identifier string) error {
+	err := db.Remove(ProjectRefCollection, bson.M{ProjectRefIdentifierKey: identifier})
+	if err != nil {
+		return errors.Wrapf(err, "problem removing project ref '%s'", identifier)
+	}
+	return nil
+}
+
 func FindFirstProjectRef() (*ProjectRef, error) {
 	projectRef := &ProjectRef{}
 	err := db.FindOne(
@@ -160,6 +438,25 @@ func FindAllTrackedProjectRefs() ([]ProjectRef, error) {
 	return projectRefs, err
 }
 
+// FindProjectsWithTriggers returns the enabled project refs that have a
+// trigger on any upstream project.
+func FindProjectsWithTriggers() ([]ProjectRef, error) {
+	projectRefs := []ProjectRef{}
+	err := db.FindAll(
+		ProjectRefCollection,
+		bson.M{
+			ProjectRefEnabledKey:         true,
+			projectRefTriggersKey + ".0": bson.M{"$exists": true},
+		},
+		db.NoProjection,
+		db.NoSort,
+		db.NoSkip,
+		db.NoLimit,
+		&projectRefs,
+	)
+	return projectRefs, err
+}
+
 // FindAllProjectRefs returns all project refs in the db
 func FindAllProjectRefs() ([]ProjectRef, error) {
 	projectRefs := []ProjectRef{}
@@ -237,6 +534,31 @@ func FindOneProjectRefByRepoAndBranchWithPRTesting(owner, repo, branch string) (
 	return &projectRefs[target], nil
 }
 
+// FindOneProjectRefWithCommitQueueByOwnerRepoAndBranch finds the ProjectRef
+// with matching repo/branch that has the commit queue enabled. If more than
+// one is found, an error is returned
+func FindOneProjectRefWithCommitQueueByOwnerRepoAndBranch(owner, repo, branch string) (*ProjectRef, error) {
+	projectRefs, err := FindProjectRefsByRepoAndBranch(owner, repo, branch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Could not fetch project ref for repo '%s/%s' with branch '%s'",
+			owner, repo, branch)
+	}
+
+	var target *ProjectRef
+	for i := range projectRefs {
+		if !projectRefs[i].CommitQueue.Enabled {
+			continue
+		}
+		if target != nil {
+			return nil, errors.Errorf("found more than one project ref for '%s/%s' on branch '%s' with the commit queue enabled",
+				owner, repo, branch)
+		}
+		target = &projectRefs[i]
+	}
+
+	return target, nil
+}
+
 // FindProjectRefs returns limit refs starting at project identifier key
 // in the sortDir direction
 func FindProjectRefs(key string, limit int, sortDir int, isAuthenticated bool) ([]ProjectRef, error) {
@@ -292,6 +614,7 @@ func (projectRef *ProjectRef) Upsert() error {
 		bson.M{
 			"$set": bson.M{
 				ProjectRefRepoKindKey:           projectRef.RepoKind,
+				ProjectRefRepoURLKey:            projectRef.RepoURL,
 				ProjectRefEnabledKey:            projectRef.Enabled,
 				ProjectRefPrivateKey:            projectRef.Private,
 				ProjectRefBatchTimeKey:          projectRef.BatchTime,
@@ -308,6 +631,9 @@ func (projectRef *ProjectRef) Upsert() error {
 				ProjectRefAdminsKey:             projectRef.Admins,
 				projectRefTracksPushEventsKey:   projectRef.TracksPushEvents,
 				projectRefPRTestingEnabledKey:   projectRef.PRTestingEnabled,
+				projectRefCommitQueueKey:        projectRef.CommitQueue,
+				projectRefSchedulesKey:          projectRef.Schedules,
+				projectRefTriggersKey:           projectRef.Triggers,
 				projectRefPatchingDisabledKey:   projectRef.PatchingDisabled,
 				projectRefNotifyOnFailureKey:    projectRef.NotifyOnBuildFailure,
 			},
@@ -4,6 +4,7 @@ import (
 	"math"
 	"testing"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/testutil"
 	"github.com/stretchr/testify/assert"
@@ -158,6 +159,25 @@ func TestFindProjectRefsByRepoAndBranch(t *testing.T) {
 	assert.Len(projectRefs, 2)
 }
 
+func TestFindProjectsWithTriggers(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(db.Clear(ProjectRefCollection))
+
+	for _, projectRef := range []ProjectRef{
+		{Identifier: "no-triggers", Enabled: true},
+		{Identifier: "disabled", Triggers: []TriggerDefinition{{ID: "t", Project: "upstream", Level: ProjectTriggerLevelVersion}}},
+		{Identifier: "downstream", Enabled: true, Triggers: []TriggerDefinition{{ID: "t", Project: "upstream", Level: ProjectTriggerLevelVersion}}},
+	} {
+		assert.NoError(projectRef.Insert())
+	}
+
+	projectRefs, err := FindProjectsWithTriggers()
+	assert.NoError(err)
+	assert.Len(projectRefs, 1)
+	assert.Equal("downstream", projectRefs[0].Identifier)
+}
+
 func TestFindOneProjectRefByRepoAndBranchWithPRTesting(t *testing.T) {
 	assert := assert.New(t)   //nolint
 	require := require.New(t) //nolint
@@ -211,3 +231,108 @@ func TestFindOneProjectRefByRepoAndBranchWithPRTesting(t *testing.T) {
 	assert.Contains(err.Error(), "found 2 project refs, when 1 was expected")
 	require.Nil(projectRef)
 }
+
+func TestFindOneProjectRefWithCommitQueueByOwnerRepoAndBranch(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	require.NoError(db.Clear(ProjectRefCollection))
+
+	projectRef, err := FindOneProjectRefWithCommitQueueByOwnerRepoAndBranch("mongodb", "mci", "master")
+	assert.NoError(err)
+	assert.Nil(projectRef)
+
+	doc := &ProjectRef{
+		Owner:      "mongodb",
+		Repo:       "mci",
+		Branch:     "master",
+		Enabled:    true,
+		Identifier: "ident0",
+	}
+	require.NoError(doc.Insert())
+
+	projectRef, err = FindOneProjectRefWithCommitQueueByOwnerRepoAndBranch("mongodb", "mci", "master")
+	assert.NoError(err)
+	assert.Nil(projectRef)
+
+	doc.Identifier = "ident1"
+	doc.CommitQueue.Enabled = true
+	require.NoError(doc.Insert())
+	projectRef, err = FindOneProjectRefWithCommitQueueByOwnerRepoAndBranch("mongodb", "mci", "master")
+	assert.NoError(err)
+	require.NotNil(projectRef)
+	assert.Equal("ident1", projectRef.Identifier)
+
+	doc.Identifier = "ident2"
+	require.NoError(doc.Insert())
+	_, err = FindOneProjectRefWithCommitQueueByOwnerRepoAndBranch("mongodb", "mci", "master")
+	assert.Error(err)
+}
+
+func TestProjectRefValidateSchedules(t *testing.T) {
+	assert := assert.New(t)
+
+	projectRef := &ProjectRef{
+		Identifier: "mci",
+		Schedules: []VersionSchedule{
+			{ID: "nightly", Cron: "@daily", Variants: []string{"ubuntu"}, Tasks: []string{"*"}},
+			{ID: "hourly", Cron: "0 * * * *", Alias: "hourly"},
+		},
+	}
+	assert.NoError(projectRef.ValidateSchedules())
+	assert.Equal("hourly", projectRef.GetSchedule("hourly").Alias)
+	assert.Nil(projectRef.GetSchedule("weekly"))
+
+	projectRef.Schedules = append(projectRef.Schedules, VersionSchedule{ID: "nightly", Cron: "0 0 * * *"})
+	assert.Error(projectRef.ValidateSchedules())
+
+	projectRef.Schedules = []VersionSchedule{{Cron: "@daily"}}
+	assert.Error(projectRef.ValidateSchedules())
+
+	projectRef.Schedules = []VersionSchedule{{ID: "nightly", Cron: "0 0 * *"}}
+	assert.Error(projectRef.ValidateSchedules())
+
+	projectRef.Schedules = []VersionSchedule{{ID: "nightly", Cron: "@daily", Alias: "nightly", Tasks: []string{"compile"}}}
+	assert.Error(projectRef.ValidateSchedules())
+}
+
+func TestValidateRepoURL(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, repoURL := range []string{"", "https://gitlab.com", "https://example.com/repo.git", "ssh://git@example.com/repo.git", "git@example.com:owner/repo.git"} {
+		assert.NoError(ValidateRepoURL(repoURL), repoURL)
+	}
+	for _, repoURL := range []string{"--upload-pack=touch /tmp/x", "-u", "ext::sh -c touch% /tmp/x", "file:///etc", "/var/repo.git", "http://example.com/repo.git", "https://"} {
+		assert.Error(ValidateRepoURL(repoURL), repoURL)
+	}
+}
+
+func TestProjectRefValidateTriggers(t *testing.T) {
+	assert := assert.New(t)
+
+	projectRef := &ProjectRef{
+		Identifier: "downstream",
+		Triggers: []TriggerDefinition{
+			{ID: "version", Project: "upstream", Level: ProjectTriggerLevelVersion},
+			{ID: "build", Project: "upstream", Level: ProjectTriggerLevelBuild, BuildVariantRegex: "^ubuntu"},
+			{ID: "task", Project: "upstream", Level: ProjectTriggerLevelTask, TaskRegex: "compile", Status: evergreen.TaskFailed},
+		},
+	}
+	assert.NoError(projectRef.ValidateTriggers())
+	assert.Equal(evergreen.VersionSucceeded, projectRef.Triggers[0].Status)
+
+	for _, def := range []TriggerDefinition{
+		{Project: "upstream", Level: ProjectTriggerLevelVersion},
+		{ID: "t", Level: ProjectTriggerLevelVersion},
+		{ID: "t", Project: "downstream", Level: ProjectTriggerLevelVersion},
+		{ID: "t", Project: "upstream", Level: "patch"},
+		{ID: "t", Project: "upstream", Level: ProjectTriggerLevelVersion, Status: "started"},
+		{ID: "t", Project: "upstream", Level: ProjectTriggerLevelVersion, TaskRegex: "compile"},
+		{ID: "t", Project: "upstream", Level: ProjectTriggerLevelBuild, TaskRegex: "compile"},
+		{ID: "t", Project: "upstream", Level: ProjectTriggerLevelTask, TaskRegex: "("},
+		{ID: "version", Project: "upstream", Level: ProjectTriggerLevelVersion},
+	} {
+		projectRef.Triggers = []TriggerDefinition{{ID: "version", Project: "upstream", Level: ProjectTriggerLevelVersion}, def}
+		assert.Error(projectRef.ValidateTriggers(), "%+v", def)
+	}
+}
@@ -443,6 +443,13 @@ func TestPopulateExpansions(t *testing.T) {
 	assert.Equal("octocat", expansions.Get("github_author"))
 	assert.Equal("42", expansions.Get("github_pr_number"))
 	assert.Equal("wut?", expansions.Get("github_org"))
+
+	v.Requester = evergreen.ManualVersionRequester
+	v.Parameters = []version.Parameter{{Key: "release", Value: "4.2.0"}, {Key: "cake", Value: "truth"}}
+	expansions = populateExpansions(d, v, bv, taskDoc, nil)
+	assert.Equal("4.2.0", expansions.Get("release"))
+	assert.Equal("truth", expansions.Get("cake"))
+	assert.False(expansions.Exists("is_patch"))
 }
 
 type projectSuite struct {
@@ -1,8 +1,17 @@
 package model
 
 import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
@@ -12,6 +21,8 @@ var (
 	projectVarIdKey   = bsonutil.MustHaveTag(ProjectVars{}, "Id")
 	projectVarsMapKey = bsonutil.MustHaveTag(ProjectVars{}, "Vars")
 	privateVarsMapKey = bsonutil.MustHaveTag(ProjectVars{}, "PrivateVars")
+	encryptedVarsKey  = bsonutil.MustHaveTag(ProjectVars{}, "EncryptedVars")
+	secretVarsKey     = bsonutil.MustHaveTag(ProjectVars{}, "SecretVars")
 )
 
 const (
@@ -35,6 +46,35 @@ type ProjectVars struct {
 	//PrivateVars keeps track of which variables are private and should therefore not
 	//be returned to the UI server.
 	PrivateVars map[string]bool `bson:"private_vars" json:"private_vars"`
+
+	//EncryptedVars holds the variables, encrypted, in place of Vars when an
+	//encryption key is configured. They are decrypted into Vars on load.
+	EncryptedVars map[string]string `bson:"encrypted_vars,omitempty" json:"-"`
+
+	//SecretVars maps variables to the secrets in the external secret store
+	//that their values are read from when a task runs, in the form
+	//"<path>#<key>". If the key is omitted it is the variable's name.
+	SecretVars map[string]string `bson:"secret_vars,omitempty" json:"secret_vars,omitempty"`
+}
+
+// SecretBackend reads secrets from an external secret store.
+type SecretBackend interface {
+	// ReadSecret returns the key/value pairs stored at a path.
+	ReadSecret(context.Context, string) (map[string]string, error)
+}
+
+// GetSecretBackend returns the secret store the configuration selects, or
+// nil if it selects none.
+func GetSecretBackend(conf *evergreen.SecretsConfig) (SecretBackend, error) {
+	switch conf.Backend {
+	case "":
+		return nil, nil
+	case evergreen.SecretBackendVault:
+		return thirdparty.NewVaultClient(conf.Vault.URL, conf.Vault.Token, conf.Vault.Mount,
+			time.Duration(conf.Vault.TimeoutSecs)*time.Second), nil
+	default:
+		return nil, errors.Errorf("invalid secret backend '%s'", conf.Backend)
+	}
 }
 
 type AWSSSHKey struct {
@@ -59,9 +99,185 @@ func FindOneProjectVars(projectId string) (*ProjectVars, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	if len(projectVars.EncryptedVars) > 0 {
+		key, err := getProjectVarsEncryptionKey()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if err = projectVars.decryptVars(key); err != nil {
+			return nil, errors.Wrapf(err, "problem decrypting vars for project '%s'", projectId)
+		}
+	}
 	return projectVars, nil
 }
 
+// FindExpansionVars finds a project's variables for a task's expansions,
+// with the variables kept in the external secret store resolved. Resolved
+// secrets are always private.
+func FindExpansionVars(ctx context.Context, projectId string) (*ProjectVars, error) {
+	projectVars, err := FindOneProjectVars(projectId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding vars for project '%s'", projectId)
+	}
+	if projectVars == nil || len(projectVars.SecretVars) == 0 {
+		return projectVars, nil
+	}
+
+	conf := &evergreen.SecretsConfig{}
+	if err = conf.Get(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	backend, err := GetSecretBackend(conf)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if backend == nil {
+		return nil, errors.Errorf("project '%s' has secret variables but no secret backend is configured", projectId)
+	}
+
+	if err = projectVars.resolveSecretVars(ctx, backend, conf.Vault.ProjectSecretPath(projectId)); err != nil {
+		return nil, errors.Wrapf(err, "problem resolving secret vars for project '%s'", projectId)
+	}
+	return projectVars, nil
+}
+
+// secretPathSegmentRegexp matches the segments that a secret reference's
+// path can have, which excludes anything that could escape the project's
+// secrets, such as "..".
+var secretPathSegmentRegexp = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9_.-]*$`)
+
+// ParseSecretReference splits a secret variable's reference into the path
+// and key of the secret in the secret store. The path is relative to the
+// project's secrets, so it cannot be absolute or contain "." or "..".
+func ParseSecretReference(name, ref string) (string, string, error) {
+	path, key := ref, name
+	if i := strings.LastIndex(ref, "#"); i >= 0 {
+		path, key = ref[:i], ref[i+1:]
+	}
+	if path == "" || key == "" {
+		return "", "", errors.Errorf("invalid secret reference '%s' for variable '%s'", ref, name)
+	}
+	for _, segment := range strings.Split(path, "/") {
+		if !secretPathSegmentRegexp.MatchString(segment) || segment == ".." {
+			return "", "", errors.Errorf("invalid secret path '%s' for variable '%s'", path, name)
+		}
+	}
+	return path, key, nil
+}
+
+// ValidateSecretVars checks that the secret variables' references are well
+// formed.
+func (projectVars *ProjectVars) ValidateSecretVars() error {
+	catcher := grip.NewBasicCatcher()
+	for name, ref := range projectVars.SecretVars {
+		_, _, err := ParseSecretReference(name, ref)
+		catcher.Add(err)
+	}
+	return catcher.Resolve()
+}
+
+// resolveSecretVars reads the secret variables from the backend, relative
+// to the project's path in the secret store.
+func (projectVars *ProjectVars) resolveSecretVars(ctx context.Context, backend SecretBackend, projectPath string) error {
+	if projectVars.Vars == nil {
+		projectVars.Vars = map[string]string{}
+	}
+	if projectVars.PrivateVars == nil {
+		projectVars.PrivateVars = map[string]bool{}
+	}
+
+	secrets := map[string]map[string]string{}
+	for name, ref := range projectVars.SecretVars {
+		path, key, err := ParseSecretReference(name, ref)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		path = projectPath + "/" + path
+		secret, ok := secrets[path]
+		if !ok {
+			secret, err = backend.ReadSecret(ctx, path)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			secrets[path] = secret
+		}
+		value, ok := secret[key]
+		if !ok {
+			return errors.Errorf("secret '%s' has no key '%s' for variable '%s'", path, key, name)
+		}
+		projectVars.Vars[name] = value
+		projectVars.PrivateVars[name] = true
+	}
+	return nil
+}
+
+// getProjectVarsEncryptionKey returns the key from the settings the
+// environment was configured with, which is never read from the database.
+func getProjectVarsEncryptionKey() ([]byte, error) {
+	conf := &evergreen.SecretsConfig{}
+	if settings := evergreen.GetEnvironment().Settings(); settings != nil {
+		conf = &settings.Secrets
+	}
+	key, err := conf.GetEncryptionKey()
+	return key, errors.Wrap(err, "invalid project vars encryption key")
+}
+
+// encryptVars moves the variables into EncryptedVars, encrypted with the
+// key. A nil key leaves them in plaintext.
+func (projectVars *ProjectVars) encryptVars(key []byte) error {
+	if key == nil {
+		projectVars.EncryptedVars = nil
+		return nil
+	}
+
+	encrypted := make(map[string]string, len(projectVars.Vars))
+	for name, value := range projectVars.Vars {
+		ciphertext, err := util.EncryptString(key, value, evergreen.ProjectVarAdditionalData(projectVars.Id, name))
+		if err != nil {
+			return errors.Wrapf(err, "problem encrypting variable '%s'", name)
+		}
+		encrypted[name] = ciphertext
+	}
+	projectVars.EncryptedVars = encrypted
+	projectVars.Vars = map[string]string{}
+	return nil
+}
+
+// decryptVars replaces the variables with the decrypted EncryptedVars.
+func (projectVars *ProjectVars) decryptVars(key []byte) error {
+	if key == nil {
+		return errors.New("project vars are encrypted but no encryption key is configured")
+	}
+
+	vars := make(map[string]string, len(projectVars.EncryptedVars))
+	for name, ciphertext := range projectVars.EncryptedVars {
+		value, err := util.DecryptString(key, ciphertext, evergreen.ProjectVarAdditionalData(projectVars.Id, name))
+		if err != nil {
+			return errors.Wrapf(err, "problem decrypting variable '%s'", name)
+		}
+		vars[name] = value
+	}
+	projectVars.Vars = vars
+	projectVars.EncryptedVars = nil
+	return nil
+}
+
+// forStorage returns a copy of the variables as they are stored, encrypted
+// if an encryption key is configured.
+func (projectVars *ProjectVars) forStorage() (*ProjectVars, error) {
+	key, err := getProjectVarsEncryptionKey()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	stored := *projectVars
+	if err = stored.encryptVars(key); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &stored, nil
+}
+
 func SetAWSKeyForProject(projectId string, ssh *AWSSSHKey) error {
 	vars, err := FindOneProjectVars(projectId)
 	if err != nil {
@@ -86,27 +302,50 @@ func GetAWSKeyForProject(projectId string) (*AWSSSHKey, error) {
 }
 
 func (projectVars *ProjectVars) Upsert() (*mgo.ChangeInfo, error) {
+	stored, err := projectVars.forStorage()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			projectVarsMapKey: stored.Vars,
+			privateVarsMapKey: stored.PrivateVars,
+			secretVarsKey:     stored.SecretVars,
+		},
+	}
+	if stored.EncryptedVars != nil {
+		update["$set"].(bson.M)[encryptedVarsKey] = stored.EncryptedVars
+	} else {
+		update["$unset"] = bson.M{encryptedVarsKey: 1}
+	}
+
 	return db.Upsert(
 		ProjectVarsCollection,
 		bson.M{
 			projectVarIdKey: projectVars.Id,
 		},
-		bson.M{
-			"$set": bson.M{
-				projectVarsMapKey: projectVars.Vars,
-				privateVarsMapKey: projectVars.PrivateVars,
-			},
-		},
+		update,
 	)
 }
 
 func (projectVars *ProjectVars) Insert() error {
+	stored, err := projectVars.forStorage()
+	if err != nil {
+		return errors.WithStack(err)
+	}
 	return db.Insert(
 		ProjectVarsCollection,
-		projectVars,
+		stored,
 	)
 }
 
+// RemoveProjectVars removes the variables of the project with the given id.
+func RemoveProjectVars(projectId string) error {
+	err := db.RemoveAll(ProjectVarsCollection, bson.M{projectVarIdKey: projectId})
+	return errors.Wrapf(err, "problem removing variables of project '%s'", projectId)
+}
+
 func (projectVars *ProjectVars) RedactPrivateVars() {
 	if projectVars != nil &&
 		projectVars.Vars != nil &&
@@ -1,12 +1,20 @@
 package model
 
 import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"testing"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
 )
 
 func TestFindOneProjectVar(t *testing.T) {
@@ -140,3 +148,190 @@ func TestAWSVars(t *testing.T) {
 	assert.Equal(false, found.PrivateVars[ProjectAWSSSHKeyName])
 	assert.Equal(true, found.PrivateVars[ProjectAWSSSHKeyValue])
 }
+
+type mockSecretBackend struct {
+	secrets map[string]map[string]string
+	reads   int
+}
+
+func (b *mockSecretBackend) ReadSecret(_ context.Context, path string) (map[string]string, error) {
+	b.reads++
+	secret, ok := b.secrets[path]
+	if !ok {
+		return nil, fmt.Errorf("secret '%s' not found", path)
+	}
+	return secret, nil
+}
+
+func TestParseSecretReference(t *testing.T) {
+	assert := assert.New(t)
+
+	path, key, err := ParseSecretReference("token", "ci/github#oauth")
+	assert.NoError(err)
+	assert.Equal("ci/github", path)
+	assert.Equal("oauth", key)
+
+	path, key, err = ParseSecretReference("token", "ci/github")
+	assert.NoError(err)
+	assert.Equal("ci/github", path)
+	assert.Equal("token", key)
+
+	_, _, err = ParseSecretReference("token", "#oauth")
+	assert.Error(err)
+	_, _, err = ParseSecretReference("token", "ci/github#")
+	assert.Error(err)
+
+	// references cannot leave the project's secrets
+	for _, ref := range []string{"../other/ci#key", "ci/../../other#key", "/ci/github#key", "ci//github#key", "./ci#key", "ci/.#key", "ci?x=1#key"} {
+		_, _, err = ParseSecretReference("token", ref)
+		assert.Error(err, ref)
+	}
+
+	vars := ProjectVars{SecretVars: map[string]string{"a": "ci/a", "b": ""}}
+	assert.Error(vars.ValidateSecretVars())
+	delete(vars.SecretVars, "b")
+	assert.NoError(vars.ValidateSecretVars())
+}
+
+func TestResolveSecretVars(t *testing.T) {
+	assert := assert.New(t)
+	backend := &mockSecretBackend{
+		secrets: map[string]map[string]string{
+			"projects/mci/ci/github":   {"oauth": "sekrit", "user": "bot"},
+			"projects/other/ci/github": {"oauth": "other"},
+		},
+	}
+
+	vars := ProjectVars{
+		Id:   "mci",
+		Vars: map[string]string{"plain": "value"},
+		SecretVars: map[string]string{
+			"token": "ci/github#oauth",
+			"user":  "ci/github",
+		},
+	}
+	assert.NoError(vars.resolveSecretVars(context.Background(), backend, "projects/mci"))
+	assert.Equal(1, backend.reads)
+	assert.Equal("value", vars.Vars["plain"])
+	assert.Equal("sekrit", vars.Vars["token"])
+	assert.Equal("bot", vars.Vars["user"])
+	assert.True(vars.PrivateVars["token"])
+	assert.True(vars.PrivateVars["user"])
+	assert.False(vars.PrivateVars["plain"])
+
+	vars.SecretVars = map[string]string{"missing": "ci/github"}
+	assert.Error(vars.resolveSecretVars(context.Background(), backend, "projects/mci"))
+	vars.SecretVars = map[string]string{"missing": "ci/nothing#key"}
+	assert.Error(vars.resolveSecretVars(context.Background(), backend, "projects/mci"))
+	vars.SecretVars = map[string]string{"other": "../other/ci/github#oauth"}
+	assert.Error(vars.resolveSecretVars(context.Background(), backend, "projects/mci"))
+}
+
+func TestEncryptProjectVars(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	key := []byte("0123456789abcdef")
+
+	vars := ProjectVars{
+		Id:          "mci",
+		Vars:        map[string]string{"a": "foo", "b": "bar"},
+		PrivateVars: map[string]bool{"a": true},
+	}
+	require.NoError(vars.encryptVars(key))
+	assert.Empty(vars.Vars)
+	require.Len(vars.EncryptedVars, 2)
+	assert.NotEqual("foo", vars.EncryptedVars["a"])
+
+	assert.Error(vars.decryptVars(nil))
+	assert.Error(vars.decryptVars([]byte("fedcba9876543210")))
+
+	// ciphertexts can't be moved between variables or projects
+	swapped := ProjectVars{Id: "mci", EncryptedVars: map[string]string{"a": vars.EncryptedVars["b"]}}
+	assert.Error(swapped.decryptVars(key))
+	moved := ProjectVars{Id: "other", EncryptedVars: vars.EncryptedVars}
+	assert.Error(moved.decryptVars(key))
+
+	require.NoError(vars.decryptVars(key))
+	assert.Equal(map[string]string{"a": "foo", "b": "bar"}, vars.Vars)
+	assert.Nil(vars.EncryptedVars)
+
+	require.NoError(vars.encryptVars(nil))
+	assert.Equal("foo", vars.Vars["a"])
+	assert.Nil(vars.EncryptedVars)
+}
+
+func TestProjectVarsEncryptedAtRest(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(ProjectVarsCollection, evergreen.ConfigCollection))
+
+	require.NoError(os.Setenv(evergreen.ProjectVarsEncryptionKeyEnv, base64.StdEncoding.EncodeToString([]byte("0123456789abcdef"))))
+	defer func() {
+		assert.NoError(os.Unsetenv(evergreen.ProjectVarsEncryptionKeyEnv))
+	}()
+
+	vars := &ProjectVars{
+		Id:          "mci",
+		Vars:        map[string]string{"a": "foo"},
+		PrivateVars: map[string]bool{"a": true},
+	}
+	_, err := vars.Upsert()
+	require.NoError(err)
+	assert.Equal("foo", vars.Vars["a"], "upserting should not modify the caller's vars")
+
+	stored := ProjectVars{}
+	require.NoError(db.FindOneQ(ProjectVarsCollection, db.Query(bson.M{projectVarIdKey: "mci"}), &stored))
+	assert.Empty(stored.Vars)
+	assert.Len(stored.EncryptedVars, 1)
+	assert.True(stored.PrivateVars["a"])
+
+	found, err := FindOneProjectVars("mci")
+	require.NoError(err)
+	require.NotNil(found)
+	assert.Equal("foo", found.Vars["a"])
+	assert.True(found.PrivateVars["a"])
+}
+
+func TestFindExpansionVarsWithVault(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(ProjectVarsCollection, evergreen.ConfigCollection))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" || r.URL.Path != "/v1/secret/data/evergreen/projects/mci/ci/github" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"data": {"oauth": "sekrit"}}}`))
+	}))
+	defer server.Close()
+
+	conf := evergreen.SecretsConfig{
+		Backend: evergreen.SecretBackendVault,
+		Vault: evergreen.VaultConfig{
+			URL:         server.URL,
+			Token:       "root",
+			Mount:       "secret",
+			TimeoutSecs: 5,
+		},
+	}
+	require.NoError(conf.Set())
+
+	vars := &ProjectVars{
+		Id:         "mci",
+		Vars:       map[string]string{"plain": "value"},
+		SecretVars: map[string]string{"token": "ci/github#oauth"},
+	}
+	require.NoError(vars.Insert())
+
+	found, err := FindExpansionVars(context.Background(), "mci")
+	require.NoError(err)
+	require.NotNil(found)
+	assert.Equal("value", found.Vars["plain"])
+	assert.Equal("sekrit", found.Vars["token"])
+	assert.True(found.PrivateVars["token"])
+
+	found, err = FindOneProjectVars("mci")
+	require.NoError(err)
+	assert.Empty(found.Vars["token"], "secrets should only be resolved for expansions")
+}
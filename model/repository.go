@@ -32,11 +32,14 @@ const (
 
 const (
 	GithubRepoType = "github"
+	GitlabRepoType = "gitlab"
+	// GitRepoType is any repository that can be cloned with git.
+	GitRepoType = "git"
 )
 
-// valid repositories - currently only github supported
+// valid repositories
 var (
-	ValidRepoTypes = []string{GithubRepoType}
+	ValidRepoTypes = []string{GithubRepoType, GitlabRepoType, GitRepoType}
 )
 
 type Revision struct {
@@ -0,0 +1,209 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	// RetryOnSystem retries tasks that failed because of the host they ran
+	// on, including tasks whose agent stopped sending heartbeats.
+	RetryOnSystem = "system"
+	// RetryOnSetup retries tasks that failed in a setup command.
+	RetryOnSetup = "setup"
+	// RetryOnTimeout retries tasks that timed out.
+	RetryOnTimeout = "timeout"
+	// RetryOnOOM retries tasks that were killed for exceeding their memory
+	// limit.
+	RetryOnOOM = "oom"
+
+	// maxRetryBackoff caps the delay before an automatic retry.
+	maxRetryBackoff = time.Hour
+)
+
+// ValidRetryFailureTypes are the failure types a retry policy may name.
+var ValidRetryFailureTypes = []string{RetryOnSystem, RetryOnSetup, RetryOnTimeout, RetryOnOOM}
+
+// RetryPolicy describes when Evergreen restarts a failed task automatically.
+// It can be set for a whole project and overridden for individual tasks.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times the task may run, counting the
+	// first run. A policy with fewer than two attempts never retries.
+	MaxAttempts int `yaml:"max_attempts,omitempty" bson:"max_attempts"`
+	// On lists the failure types that qualify for a retry. It defaults to
+	// system failures only.
+	On []string `yaml:"on,omitempty" bson:"on,omitempty"`
+	// BackoffSecs is the delay before the first retry. Each further retry
+	// doubles the delay.
+	BackoffSecs int `yaml:"backoff_secs,omitempty" bson:"backoff_secs"`
+}
+
+// FailureType returns the failure type of a task that finished with the
+// given details, or the empty string if the failure is not one that a retry
+// policy can name.
+func FailureType(detail *apimodels.TaskEndDetail) string {
+	if detail == nil || detail.Status != evergreen.TaskFailed {
+		return ""
+	}
+
+	switch {
+	case detail.Type == evergreen.CommandTypeSystem || detail.Description == task.AgentHeartbeat:
+		return RetryOnSystem
+	case detail.OOMKilled:
+		return RetryOnOOM
+	case detail.TimedOut:
+		return RetryOnTimeout
+	case detail.Type == evergreen.CommandTypeSetup:
+		return RetryOnSetup
+	}
+
+	return ""
+}
+
+// Qualifies returns true if the policy retries a task that finished with the
+// given details after the given number of automatic retries.
+func (r *RetryPolicy) Qualifies(detail *apimodels.TaskEndDetail, retries int) bool {
+	if r == nil || retries+1 >= r.MaxAttempts {
+		return false
+	}
+
+	failureType := FailureType(detail)
+	if failureType == "" {
+		return false
+	}
+	if len(r.On) == 0 {
+		return failureType == RetryOnSystem
+	}
+
+	return util.StringSliceContains(r.On, failureType)
+}
+
+// Backoff returns how long to wait before retrying a task that has already
+// been retried the given number of times.
+func (r *RetryPolicy) Backoff(retries int) time.Duration {
+	if r == nil || r.BackoffSecs <= 0 {
+		return 0
+	}
+
+	backoff := time.Duration(r.BackoffSecs) * time.Second
+	for i := 0; i < retries && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+
+	return backoff
+}
+
+// Validate returns an error if the policy is malformed.
+func (r *RetryPolicy) Validate() error {
+	catcher := grip.NewSimpleCatcher()
+	if r.MaxAttempts < 0 {
+		catcher.Add(errors.New("max_attempts cannot be negative"))
+	}
+	if r.MaxAttempts > evergreen.MaxTaskExecution+1 {
+		catcher.Add(errors.Errorf("max_attempts cannot be more than %d", evergreen.MaxTaskExecution+1))
+	}
+	if r.BackoffSecs < 0 {
+		catcher.Add(errors.New("backoff_secs cannot be negative"))
+	}
+	for _, failureType := range r.On {
+		if !util.StringSliceContains(ValidRetryFailureTypes, failureType) {
+			catcher.Add(errors.Errorf("'%s' is not a valid failure type to retry on, must be one of %v",
+				failureType, ValidRetryFailureTypes))
+		}
+	}
+
+	return catcher.Resolve()
+}
+
+// FindRetryPolicy returns the retry policy of a task, or nil if the task is
+// never retried automatically.
+func (p *Project) FindRetryPolicy(taskName string) *RetryPolicy {
+	if projectTask := p.FindProjectTask(taskName); projectTask != nil && projectTask.Retry != nil {
+		return projectTask.Retry
+	}
+
+	return p.Retry
+}
+
+// evalAutoRetry restarts a failed task, or schedules it to be restarted, if
+// the retry policy of its project covers the failure. It returns true if the
+// task will be retried.
+func evalAutoRetry(t *task.Task, detail *apimodels.TaskEndDetail) (bool, error) {
+	if t.IsPartOfDisplay() || t.DisplayOnly || t.Aborted || t.Execution >= evergreen.MaxTaskExecution {
+		return false, nil
+	}
+	if FailureType(detail) == "" {
+		return false, nil
+	}
+
+	project, err := FindProjectFromTask(t)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	policy := project.FindRetryPolicy(t.DisplayName)
+	if !policy.Qualifies(detail, t.AutoRetries) {
+		return false, nil
+	}
+
+	backoff := policy.Backoff(t.AutoRetries)
+	if backoff == 0 {
+		return true, errors.WithStack(AutoRetryTask(t.Id))
+	}
+
+	retryAt := time.Now().Add(backoff)
+	grip.Info(message.Fields{
+		"message":      "scheduling automatic retry",
+		"task_id":      t.Id,
+		"execution":    t.Execution,
+		"failure_type": FailureType(detail),
+		"auto_retries": t.AutoRetries,
+		"retry_at":     retryAt,
+	})
+
+	return true, errors.WithStack(t.SetAutoRetryAt(retryAt))
+}
+
+// AutoRetryTask restarts a task on behalf of its retry policy and records
+// the restart as an automatic one.
+func AutoRetryTask(taskId string) error {
+	t, err := task.FindOneNoMerge(task.ById(taskId))
+	if err != nil {
+		return errors.Wrapf(err, "problem finding task '%s'", taskId)
+	}
+	if t == nil {
+		return errors.Errorf("task '%s' not found", taskId)
+	}
+	if !t.IsFinished() {
+		return nil
+	}
+
+	failureType := FailureType(&t.Details)
+	if err = resetTask(t.Id, evergreen.AutoRetryTaskActivator); err != nil {
+		return errors.Wrapf(err, "problem restarting task '%s'", t.Id)
+	}
+	if err = t.IncAutoRetries(); err != nil {
+		return errors.WithStack(err)
+	}
+	event.LogTaskAutoRestarted(t.Id, t.Execution, failureType)
+
+	grip.Info(message.Fields{
+		"message":      "automatically retried task",
+		"task_id":      t.Id,
+		"execution":    t.Execution,
+		"failure_type": failureType,
+		"auto_retries": t.AutoRetries,
+	})
+
+	return nil
+}
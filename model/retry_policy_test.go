@@ -0,0 +1,103 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailureType(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("", FailureType(nil))
+	assert.Equal("", FailureType(&apimodels.TaskEndDetail{Status: evergreen.TaskSucceeded}))
+	assert.Equal("", FailureType(&apimodels.TaskEndDetail{Status: evergreen.TaskFailed, Type: evergreen.CommandTypeTest}))
+	assert.Equal(RetryOnSystem, FailureType(&apimodels.TaskEndDetail{Status: evergreen.TaskFailed, Type: evergreen.CommandTypeSystem}))
+	assert.Equal(RetryOnSystem, FailureType(&apimodels.TaskEndDetail{Status: evergreen.TaskFailed, Description: "heartbeat", TimedOut: true}))
+	assert.Equal(RetryOnTimeout, FailureType(&apimodels.TaskEndDetail{Status: evergreen.TaskFailed, Type: evergreen.CommandTypeTest, TimedOut: true}))
+	assert.Equal(RetryOnSetup, FailureType(&apimodels.TaskEndDetail{Status: evergreen.TaskFailed, Type: evergreen.CommandTypeSetup}))
+	assert.Equal(RetryOnOOM, FailureType(&apimodels.TaskEndDetail{Status: evergreen.TaskFailed, Type: evergreen.CommandTypeTest, OOMKilled: true}))
+}
+
+func TestRetryPolicyQualifies(t *testing.T) {
+	assert := assert.New(t)
+
+	system := &apimodels.TaskEndDetail{Status: evergreen.TaskFailed, Type: evergreen.CommandTypeSystem}
+	timeout := &apimodels.TaskEndDetail{Status: evergreen.TaskFailed, Type: evergreen.CommandTypeTest, TimedOut: true}
+	testFailure := &apimodels.TaskEndDetail{Status: evergreen.TaskFailed, Type: evergreen.CommandTypeTest}
+
+	var noPolicy *RetryPolicy
+	assert.False(noPolicy.Qualifies(system, 0))
+
+	policy := &RetryPolicy{MaxAttempts: 3}
+	assert.True(policy.Qualifies(system, 0))
+	assert.True(policy.Qualifies(system, 1))
+	assert.False(policy.Qualifies(system, 2), "the third attempt is the last")
+	assert.False(policy.Qualifies(timeout, 0), "only system failures are retried by default")
+	assert.False(policy.Qualifies(testFailure, 0))
+
+	policy.On = []string{RetryOnTimeout}
+	assert.True(policy.Qualifies(timeout, 0))
+	assert.False(policy.Qualifies(system, 0))
+
+	assert.False((&RetryPolicy{MaxAttempts: 1}).Qualifies(system, 0))
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Zero((&RetryPolicy{MaxAttempts: 3}).Backoff(1))
+
+	policy := &RetryPolicy{MaxAttempts: 3, BackoffSecs: 30}
+	assert.Equal(30*time.Second, policy.Backoff(0))
+	assert.Equal(60*time.Second, policy.Backoff(1))
+	assert.Equal(120*time.Second, policy.Backoff(2))
+	assert.Equal(maxRetryBackoff, policy.Backoff(100))
+}
+
+func TestRetryPolicyValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError((&RetryPolicy{MaxAttempts: 2, On: []string{RetryOnSystem, RetryOnSetup}, BackoffSecs: 10}).Validate())
+	assert.Error((&RetryPolicy{MaxAttempts: -1}).Validate())
+	assert.Error((&RetryPolicy{MaxAttempts: evergreen.MaxTaskExecution + 2}).Validate())
+	assert.Error((&RetryPolicy{MaxAttempts: 2, BackoffSecs: -1}).Validate())
+	assert.Error((&RetryPolicy{MaxAttempts: 2, On: []string{"test"}}).Validate())
+}
+
+func TestFindRetryPolicyFromYAML(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	yml := `
+retry:
+  max_attempts: 2
+tasks:
+- name: compile
+- name: integration
+  retry:
+    max_attempts: 3
+    on: [system, timeout]
+    backoff_secs: 60
+`
+	project := &Project{}
+	require.NoError(LoadProjectInto([]byte(yml), "retry", project))
+
+	compile := project.FindRetryPolicy("compile")
+	require.NotNil(compile)
+	assert.Equal(2, compile.MaxAttempts)
+	assert.Empty(compile.On)
+
+	integration := project.FindRetryPolicy("integration")
+	require.NotNil(integration)
+	assert.Equal(3, integration.MaxAttempts)
+	assert.Equal([]string{RetryOnSystem, RetryOnTimeout}, integration.On)
+	assert.Equal(60, integration.BackoffSecs)
+
+	project.Retry = nil
+	assert.Nil(project.FindRetryPolicy("compile"))
+}
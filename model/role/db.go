@@ -0,0 +1,279 @@
+package role
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// Collection is the name of the roles collection in the database.
+	Collection = "roles"
+	// AssignmentCollection is the name of the role assignments collection
+	// in the database.
+	AssignmentCollection = "role_assignments"
+)
+
+var (
+	// bson fields for the Role struct
+	IdKey          = bsonutil.MustHaveTag(Role{}, "Id")
+	DescriptionKey = bsonutil.MustHaveTag(Role{}, "Description")
+	PermissionsKey = bsonutil.MustHaveTag(Role{}, "Permissions")
+
+	// bson fields for the Assignment struct
+	AssignmentIdKey      = bsonutil.MustHaveTag(Assignment{}, "Id")
+	AssignmentUserKey    = bsonutil.MustHaveTag(Assignment{}, "User")
+	AssignmentRoleKey    = bsonutil.MustHaveTag(Assignment{}, "Role")
+	AssignmentProjectKey = bsonutil.MustHaveTag(Assignment{}, "Project")
+	AssignmentSourceKey  = bsonutil.MustHaveTag(Assignment{}, "Source")
+)
+
+// FindOne returns the role with the given id, which may be a built-in role,
+// or nil if there is none.
+func FindOne(roleId string) (*Role, error) {
+	r := &Role{}
+	err := db.FindOneQ(Collection, db.Query(bson.M{IdKey: roleId}), r)
+	if err == mgo.ErrNotFound {
+		for _, builtIn := range BuiltInRoles() {
+			if builtIn.Id == roleId {
+				return &builtIn, nil
+			}
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding role '%s'", roleId)
+	}
+
+	return r, nil
+}
+
+// FindStored returns the roles stored in the database.
+func FindStored() ([]Role, error) {
+	roles := []Role{}
+	if err := db.FindAllQ(Collection, db.Query(bson.M{}), &roles); err != nil {
+		return nil, errors.Wrap(err, "problem finding roles")
+	}
+	return roles, nil
+}
+
+// FindAll returns every role, including the built-in roles.
+func FindAll() ([]Role, error) {
+	stored, err := FindStored()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return MergeRoles(stored), nil
+}
+
+// Upsert stores the role on behalf of the given user, replacing any role
+// with the same id.
+func (r *Role) Upsert(user string) error {
+	if err := r.Validate(); err != nil {
+		return errors.Wrap(err, "invalid role")
+	}
+	existing, err := FindOne(r.Id)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = db.Upsert(Collection, bson.M{IdKey: r.Id}, bson.M{
+		"$set": bson.M{
+			DescriptionKey: r.Description,
+			PermissionsKey: r.Permissions,
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "problem saving role '%s'", r.Id)
+	}
+
+	change := event.RoleDataChange{
+		Action: event.RoleActionUpdate,
+		Role:   r.Id,
+		After:  r.Permissions,
+	}
+	if existing != nil {
+		change.Before = existing.Permissions
+	}
+	return errors.WithStack(event.LogRoleEvent(change, user))
+}
+
+// Remove deletes a stored role on behalf of the given user, along with its
+// assignments. Removing a built-in role restores its built-in definition
+// and keeps its assignments.
+func Remove(roleId, user string) error {
+	existing, err := FindOne(roleId)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if existing == nil {
+		return errors.Errorf("role '%s' not found", roleId)
+	}
+
+	err = db.Remove(Collection, bson.M{IdKey: roleId})
+	if err == mgo.ErrNotFound && IsBuiltIn(roleId) {
+		return errors.Errorf("built-in role '%s' cannot be removed", roleId)
+	}
+	if err != nil && err != mgo.ErrNotFound {
+		return errors.Wrapf(err, "problem removing role '%s'", roleId)
+	}
+	if !IsBuiltIn(roleId) {
+		if err = db.RemoveAll(AssignmentCollection, bson.M{AssignmentRoleKey: roleId}); err != nil {
+			return errors.Wrapf(err, "problem removing assignments of role '%s'", roleId)
+		}
+	}
+
+	return errors.WithStack(event.LogRoleEvent(event.RoleDataChange{
+		Action: event.RoleActionRemove,
+		Role:   roleId,
+		Before: existing.Permissions,
+	}, user))
+}
+
+// FindAssignments returns the roles assigned to a user.
+func FindAssignments(assignee string) ([]Assignment, error) {
+	assignments := []Assignment{}
+	err := db.FindAllQ(AssignmentCollection, db.Query(bson.M{AssignmentUserKey: assignee}), &assignments)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding roles of user '%s'", assignee)
+	}
+	return assignments, nil
+}
+
+// Assign grants a role to a user on a project, or globally if the project is
+// empty, on behalf of the given user.
+func Assign(assignee, roleId, project, user string) error {
+	r, err := FindOne(roleId)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if r == nil {
+		return errors.Errorf("role '%s' not found", roleId)
+	}
+
+	a := NewAssignment(assignee, roleId, project)
+	if _, err = db.Upsert(AssignmentCollection, bson.M{AssignmentIdKey: a.Id}, a); err != nil {
+		return errors.Wrapf(err, "problem assigning role '%s' to '%s'", roleId, assignee)
+	}
+
+	return errors.WithStack(event.LogRoleEvent(event.RoleDataChange{
+		Action:   event.RoleActionAssign,
+		Role:     roleId,
+		Assignee: assignee,
+		Project:  project,
+	}, user))
+}
+
+// Unassign revokes a role granted to a user on a project, or globally if the
+// project is empty, on behalf of the given user. It returns a not found error
+// response if the user does not hold the role.
+func Unassign(assignee, roleId, project, user string) error {
+	a := NewAssignment(assignee, roleId, project)
+	err := db.Remove(AssignmentCollection, bson.M{AssignmentIdKey: a.Id})
+	if err == mgo.ErrNotFound {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("user '%s' does not have role '%s'", assignee, roleId),
+		}
+	}
+	if err != nil {
+		return errors.Wrapf(err, "problem revoking role '%s' from '%s'", roleId, assignee)
+	}
+
+	return errors.WithStack(event.LogRoleEvent(event.RoleDataChange{
+		Action:   event.RoleActionUnassign,
+		Role:     roleId,
+		Assignee: assignee,
+		Project:  project,
+	}, user))
+}
+
+// SyncAssignments makes the assignments of the user that came from the source
+// match the given assignments. Assignments the user already holds are left
+// as they are, and assignments from the API or other sources are never
+// removed.
+func SyncAssignments(assignee, source string, assignments []Assignment) error {
+	existing, err := FindAssignments(assignee)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	held := map[string]Assignment{}
+	for _, a := range existing {
+		held[a.Id] = a
+	}
+	wanted := map[string]bool{}
+
+	for _, a := range assignments {
+		a = NewAssignment(assignee, a.Role, a.Project)
+		wanted[a.Id] = true
+		if _, ok := held[a.Id]; ok {
+			continue
+		}
+		a.Source = source
+		err = db.Insert(AssignmentCollection, a)
+		if db.IsDuplicateKey(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "problem assigning role '%s' to '%s'", a.Role, assignee)
+		}
+		if err = event.LogRoleEvent(event.RoleDataChange{
+			Action:   event.RoleActionAssign,
+			Role:     a.Role,
+			Assignee: assignee,
+			Project:  a.Project,
+		}, source); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	for _, a := range existing {
+		if a.Source != source || wanted[a.Id] {
+			continue
+		}
+		err = db.Remove(AssignmentCollection, bson.M{AssignmentIdKey: a.Id, AssignmentSourceKey: source})
+		if err == mgo.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "problem revoking role '%s' from '%s'", a.Role, assignee)
+		}
+		if err = event.LogRoleEvent(event.RoleDataChange{
+			Action:   event.RoleActionUnassign,
+			Role:     a.Role,
+			Assignee: assignee,
+			Project:  a.Project,
+		}, source); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return nil
+}
+
+// HasPermission returns true if a user holds the permission on the project,
+// or globally if the project is empty.
+func HasPermission(user, permission, project string) (bool, error) {
+	stored, err := FindStored()
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	assignments, err := FindAssignments(user)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	for _, held := range Permissions(stored, assignments, user, project) {
+		if held == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
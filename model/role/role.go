@@ -0,0 +1,194 @@
+package role
+
+import (
+	"sort"
+
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+// Permissions that roles can grant. Project permissions can be granted on a
+// single project or globally; the others are always global.
+const (
+	PermissionEditProjectSettings = "edit_project_settings"
+	PermissionRestartTasks        = "restart_tasks"
+	PermissionViewPrivateLogs     = "view_private_logs"
+	PermissionManageDistros       = "manage_distros"
+	PermissionManageSpawnHosts    = "manage_spawn_hosts"
+)
+
+// Built-in roles. They exist without being stored, but a stored role with the
+// same id replaces the built-in definition.
+const (
+	// AdminRole grants every permission.
+	AdminRole = "admin"
+	// ProjectAdminRole grants every project permission, and is implicitly
+	// held by the admins listed on a project.
+	ProjectAdminRole = "project_admin"
+	// DefaultRole is implicitly held by every user on every project.
+	DefaultRole = "default"
+)
+
+// GlobalScope is the project of an assignment that applies to all projects.
+const GlobalScope = ""
+
+var (
+	// ValidPermissions lists every permission a role may grant.
+	ValidPermissions = []string{
+		PermissionEditProjectSettings,
+		PermissionRestartTasks,
+		PermissionViewPrivateLogs,
+		PermissionManageDistros,
+		PermissionManageSpawnHosts,
+	}
+
+	// ProjectPermissions lists the permissions that can be granted on a
+	// single project.
+	ProjectPermissions = []string{
+		PermissionEditProjectSettings,
+		PermissionRestartTasks,
+		PermissionViewPrivateLogs,
+	}
+)
+
+// Role is a named set of permissions.
+type Role struct {
+	Id          string   `bson:"_id" json:"id"`
+	Description string   `bson:"description" json:"description"`
+	Permissions []string `bson:"permissions" json:"permissions"`
+}
+
+// Assignment grants a role to a user, either on one project or, if the
+// project is empty, globally. Source is empty for assignments made through
+// the API, and otherwise names what keeps the assignment in sync.
+type Assignment struct {
+	Id      string `bson:"_id" json:"-"`
+	User    string `bson:"user" json:"user"`
+	Role    string `bson:"role" json:"role"`
+	Project string `bson:"project" json:"project"`
+	Source  string `bson:"source,omitempty" json:"source,omitempty"`
+}
+
+// NewAssignment returns an assignment of the role to the user.
+func NewAssignment(user, roleId, project string) Assignment {
+	return Assignment{
+		Id:      user + "|" + roleId + "|" + project,
+		User:    user,
+		Role:    roleId,
+		Project: project,
+	}
+}
+
+// BuiltInRoles returns the definitions of the built-in roles. The default
+// role preserves the access every logged in user had before roles existed.
+func BuiltInRoles() []Role {
+	return []Role{
+		{
+			Id:          AdminRole,
+			Description: "all permissions",
+			Permissions: append([]string{}, ValidPermissions...),
+		},
+		{
+			Id:          ProjectAdminRole,
+			Description: "all project permissions",
+			Permissions: append([]string{}, ProjectPermissions...),
+		},
+		{
+			Id:          DefaultRole,
+			Description: "permissions held by every user",
+			Permissions: []string{PermissionRestartTasks, PermissionViewPrivateLogs, PermissionManageSpawnHosts},
+		},
+	}
+}
+
+// IsBuiltIn returns true if the id names a built-in role.
+func IsBuiltIn(roleId string) bool {
+	for _, r := range BuiltInRoles() {
+		if r.Id == roleId {
+			return true
+		}
+	}
+	return false
+}
+
+// IsProjectPermission returns true if the permission can be granted on a
+// single project.
+func IsProjectPermission(permission string) bool {
+	return util.StringSliceContains(ProjectPermissions, permission)
+}
+
+// Validate returns an error if the role is malformed.
+func (r *Role) Validate() error {
+	catcher := grip.NewSimpleCatcher()
+	if r.Id == "" {
+		catcher.Add(errors.New("role must have an id"))
+	}
+	for _, permission := range r.Permissions {
+		if !util.StringSliceContains(ValidPermissions, permission) {
+			catcher.Add(errors.Errorf("'%s' is not a valid permission, must be one of %v", permission, ValidPermissions))
+		}
+	}
+
+	return catcher.Resolve()
+}
+
+// MergeRoles returns the built-in roles replaced or extended by the stored
+// roles, sorted by id.
+func MergeRoles(stored []Role) []Role {
+	byId := map[string]Role{}
+	for _, r := range BuiltInRoles() {
+		byId[r.Id] = r
+	}
+	for _, r := range stored {
+		byId[r.Id] = r
+	}
+
+	out := make([]Role, 0, len(byId))
+	for _, r := range byId {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Id < out[j].Id })
+	return out
+}
+
+// Permissions returns the permissions a user with the given assignments
+// holds on a project, or globally if the project is empty. Assignments for
+// other users are ignored. Only global assignments grant permissions that
+// cannot be granted on a single project.
+func Permissions(roles []Role, assignments []Assignment, user, project string) []string {
+	byId := map[string]Role{}
+	for _, r := range MergeRoles(roles) {
+		byId[r.Id] = r
+	}
+
+	held := map[string]bool{}
+	grant := func(roleId string, onProject bool) {
+		for _, permission := range byId[roleId].Permissions {
+			if !onProject || IsProjectPermission(permission) {
+				held[permission] = true
+			}
+		}
+	}
+
+	grant(DefaultRole, false)
+	for _, a := range assignments {
+		if a.User != user {
+			continue
+		}
+		switch {
+		case a.Project == GlobalScope:
+			grant(a.Role, false)
+		case project != GlobalScope && a.Project == project:
+			grant(a.Role, true)
+		}
+	}
+
+	out := []string{}
+	for _, permission := range ValidPermissions {
+		if held[permission] {
+			out = append(out, permission)
+		}
+	}
+	return out
+}
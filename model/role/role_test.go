@@ -0,0 +1,135 @@
+package role
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	r := Role{Id: "release_manager", Permissions: []string{PermissionRestartTasks, PermissionManageDistros}}
+	assert.NoError(r.Validate())
+
+	r.Id = ""
+	assert.Error(r.Validate())
+
+	r = Role{Id: "bad", Permissions: []string{"launch_missiles"}}
+	assert.Error(r.Validate())
+}
+
+func TestMergeRoles(t *testing.T) {
+	assert := assert.New(t)
+
+	roles := MergeRoles(nil)
+	assert.Len(roles, 3)
+	assert.Equal(AdminRole, roles[0].Id)
+	assert.Equal(DefaultRole, roles[1].Id)
+	assert.Equal(ProjectAdminRole, roles[2].Id)
+
+	roles = MergeRoles([]Role{
+		{Id: DefaultRole},
+		{Id: "distro_admin", Permissions: []string{PermissionManageDistros}},
+	})
+	assert.Len(roles, 4)
+	assert.Equal(DefaultRole, roles[1].Id)
+	assert.Empty(roles[1].Permissions)
+	assert.Equal("distro_admin", roles[2].Id)
+}
+
+func TestPermissions(t *testing.T) {
+	assert := assert.New(t)
+
+	// every user holds the default role
+	assert.Equal([]string{PermissionRestartTasks, PermissionViewPrivateLogs, PermissionManageSpawnHosts},
+		Permissions(nil, nil, "alice", GlobalScope))
+
+	// stored roles replace built-in ones
+	locked := []Role{{Id: DefaultRole}}
+	assert.Empty(Permissions(locked, nil, "alice", "mci"))
+
+	assignments := []Assignment{
+		NewAssignment("alice", ProjectAdminRole, "mci"),
+		NewAssignment("alice", AdminRole, "other"),
+		NewAssignment("bob", AdminRole, GlobalScope),
+	}
+
+	// project assignments only apply to their project
+	assert.Equal([]string{PermissionEditProjectSettings, PermissionRestartTasks, PermissionViewPrivateLogs},
+		Permissions(locked, assignments, "alice", "mci"))
+	assert.Empty(Permissions(locked, assignments, "alice", GlobalScope))
+
+	// project assignments never grant global permissions
+	assert.NotContains(Permissions(locked, assignments, "alice", "other"), PermissionManageDistros)
+
+	// global assignments apply everywhere
+	assert.Equal(ValidPermissions, Permissions(locked, assignments, "bob", "mci"))
+	assert.Equal(ValidPermissions, Permissions(locked, assignments, "bob", GlobalScope))
+
+	// unknown roles grant nothing
+	assert.Empty(Permissions(locked, []Assignment{NewAssignment("carol", "missing", GlobalScope)}, "carol", GlobalScope))
+}
+
+func TestUnassign(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.ClearCollections(Collection, AssignmentCollection, event.AllLogCollection))
+
+	require.NoError(Assign("alice", ProjectAdminRole, "mci", "root"))
+	assert.NoError(Unassign("alice", ProjectAdminRole, "mci", "root"))
+
+	err := Unassign("alice", ProjectAdminRole, "mci", "root")
+	require.Error(err)
+	resp, ok := errors.Cause(err).(gimlet.ErrorResponse)
+	require.True(ok)
+	assert.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func TestSyncAssignments(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.ClearCollections(Collection, AssignmentCollection, event.AllLogCollection))
+
+	require.NoError(Assign("alice", DefaultRole, GlobalScope, "root"))
+	require.NoError(SyncAssignments("alice", "oidc", []Assignment{
+		{Role: AdminRole},
+		{Role: ProjectAdminRole, Project: "mci"},
+		{Role: DefaultRole},
+	}))
+	assignments, err := FindAssignments("alice")
+	require.NoError(err)
+	require.Len(assignments, 3)
+	for _, a := range assignments {
+		if a.Role == DefaultRole {
+			assert.Empty(a.Source)
+		} else {
+			assert.Equal("oidc", a.Source)
+		}
+	}
+
+	// assignments the source no longer grants are revoked, but not those
+	// made through the API
+	require.NoError(SyncAssignments("alice", "oidc", []Assignment{{Role: ProjectAdminRole, Project: "mci"}}))
+	assignments, err = FindAssignments("alice")
+	require.NoError(err)
+	require.Len(assignments, 2)
+	roles := []string{assignments[0].Role, assignments[1].Role}
+	assert.Contains(roles, DefaultRole)
+	assert.Contains(roles, ProjectAdminRole)
+
+	require.NoError(SyncAssignments("alice", "oidc", nil))
+	assignments, err = FindAssignments("alice")
+	require.NoError(err)
+	require.Len(assignments, 1)
+	assert.Equal(DefaultRole, assignments[0].Role)
+}
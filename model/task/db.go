@@ -65,6 +65,8 @@ var (
 	GenerateTaskKey         = bsonutil.MustHaveTag(Task{}, "GenerateTask")
 	GeneratedByKey          = bsonutil.MustHaveTag(Task{}, "GeneratedBy")
 	ResetWhenFinishedKey    = bsonutil.MustHaveTag(Task{}, "ResetWhenFinished")
+	AutoRetriesKey          = bsonutil.MustHaveTag(Task{}, "AutoRetries")
+	AutoRetryAtKey          = bsonutil.MustHaveTag(Task{}, "AutoRetryAt")
 
 	// BSON fields for the test result struct
 	TestResultStatusKey    = bsonutil.MustHaveTag(TestResult{}, "Status")
@@ -400,6 +402,26 @@ func ByRecentlyFinished(finishTime time.Time, project string, requester string)
 	return db.Query(query)
 }
 
+// ByRestartedInProject returns a query for the project's tasks that finished
+// after the given time and have been executed more than once.
+func ByRestartedInProject(project string, finishedAfter time.Time) db.Q {
+	return db.Query(bson.M{
+		ProjectKey:    project,
+		ExecutionKey:  bson.M{"$gt": 0},
+		FinishTimeKey: bson.M{"$gt": finishedAfter},
+		"$or":         FinishedOpts,
+	})
+}
+
+// ByAutoRetryDue returns a query for finished tasks whose scheduled automatic
+// retry is due.
+func ByAutoRetryDue(now time.Time) db.Q {
+	return db.Query(bson.M{
+		AutoRetryAtKey: bson.M{"$lte": now},
+		StatusKey:      evergreen.TaskFailed,
+	})
+}
+
 func ByDispatchedWithIdsVersionAndStatus(taskIds []string, versionId string, statuses []string) db.Q {
 	return db.Query(bson.M{
 		IdKey: bson.M{
@@ -533,6 +555,57 @@ func CostDataByDistroIdPipeline(distroId string, starttime time.Time, duration t
 	return pipeline
 }
 
+// ProjectUsageByDistroIdPipeline returns an aggregation pipeline for
+// fetching the total time taken by tasks of each project that finished on
+// the given distro since the given time.
+func ProjectUsageByDistroIdPipeline(distroId string, since time.Time) []bson.M {
+	return []bson.M{
+		{"$match": bson.M{
+			DistroIdKey:   distroId,
+			FinishTimeKey: bson.M{"$gte": since},
+		}},
+		{"$group": bson.M{
+			"_id":            "$" + ProjectKey,
+			"sum_time_taken": bson.M{"$sum": "$" + TimeTakenKey},
+			"num_tasks":      bson.M{"$sum": 1},
+		}},
+		{"$project": bson.M{
+			"_id":            0,
+			"project":        "$_id",
+			"sum_time_taken": 1,
+			"num_tasks":      1,
+		}},
+	}
+}
+
+// ArrivalsByDistroIdPipeline returns an aggregation pipeline for counting
+// the tasks activated on the given distro since the given time, and summing
+// their expected durations, by day of the week and hour of the day.
+func ArrivalsByDistroIdPipeline(distroId string, since time.Time) []bson.M {
+	return []bson.M{
+		{"$match": bson.M{
+			DistroIdKey:      distroId,
+			ActivatedTimeKey: bson.M{"$gte": since},
+		}},
+		{"$group": bson.M{
+			"_id": bson.M{
+				"day":  bson.M{"$dayOfWeek": "$" + ActivatedTimeKey},
+				"hour": bson.M{"$hour": "$" + ActivatedTimeKey},
+			},
+			"num_tasks":             bson.M{"$sum": 1},
+			"sum_expected_duration": bson.M{"$sum": "$" + ExpectedDurationKey},
+		}},
+		{"$project": bson.M{
+			"_id": 0,
+			// $dayOfWeek counts from 1 for Sunday
+			"day_of_week":           bson.M{"$subtract": []interface{}{"$_id.day", 1}},
+			"hour":                  "$_id.hour",
+			"num_tasks":             1,
+			"sum_expected_duration": 1,
+		}},
+	}
+}
+
 // FindCostTaskByProject fetches all tasks of a project matching the
 // given time range, starting at task's IdKey in sortDir direction.
 func FindCostTaskByProject(project, taskId string, starttime,
@@ -0,0 +1,305 @@
+package task
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DependencyGraph is the task dependency DAG of a version, annotated with
+// the duration of each task and the critical path through the graph.
+type DependencyGraph struct {
+	VersionId string       `json:"version_id"`
+	Nodes     []GraphNode  `json:"nodes"`
+	Edges     []GraphEdge  `json:"edges"`
+	Critical  CriticalPath `json:"critical_path"`
+	byId      map[string]int
+}
+
+// GraphNode is a task in a dependency graph. Times are offsets from the
+// moment the first task in the version could start, assuming every task
+// starts as soon as its dependencies finish.
+type GraphNode struct {
+	TaskId         string        `json:"task_id"`
+	DisplayName    string        `json:"display_name"`
+	BuildVariant   string        `json:"build_variant"`
+	Status         string        `json:"status"`
+	Duration       time.Duration `json:"duration"`
+	Estimated      bool          `json:"estimated"`
+	EarliestStart  time.Duration `json:"earliest_start"`
+	EarliestFinish time.Duration `json:"earliest_finish"`
+	Slack          time.Duration `json:"slack"`
+	OnCriticalPath bool          `json:"on_critical_path"`
+}
+
+// GraphEdge is a dependency of the To task on the From task.
+type GraphEdge struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Status string `json:"status,omitempty"`
+}
+
+// CriticalPath is the longest chain of dependent tasks in a graph, which
+// bounds how quickly the version can finish.
+type CriticalPath struct {
+	TaskIds  []string      `json:"task_ids"`
+	Duration time.Duration `json:"duration"`
+}
+
+// graphDuration returns how long the task took if it has finished, or how
+// long it is expected to take otherwise, and whether the value is an
+// estimate.
+func graphDuration(t *Task, now time.Time) (time.Duration, bool) {
+	if t.IsFinished() {
+		if t.TimeTaken > 0 {
+			return t.TimeTaken, false
+		}
+		if !t.StartTime.IsZero() && t.FinishTime.After(t.StartTime) {
+			return t.FinishTime.Sub(t.StartTime), false
+		}
+		return 0, false
+	}
+
+	expected := t.ExpectedDuration
+	if t.DurationPrediction.Value > 0 {
+		expected = t.DurationPrediction.Value
+	}
+	if !t.StartTime.IsZero() && now.After(t.StartTime) {
+		if elapsed := now.Sub(t.StartTime); elapsed > expected {
+			return elapsed, true
+		}
+	}
+	return expected, true
+}
+
+// NewDependencyGraph builds the dependency graph of a version's tasks.
+// Display tasks and dependencies on tasks outside the set are ignored. It
+// returns an error if the dependencies contain a cycle.
+func NewDependencyGraph(versionId string, tasks []Task) (*DependencyGraph, error) {
+	return newDependencyGraph(versionId, tasks, time.Now())
+}
+
+func newDependencyGraph(versionId string, tasks []Task, now time.Time) (*DependencyGraph, error) {
+	g := &DependencyGraph{
+		VersionId: versionId,
+		Nodes:     []GraphNode{},
+		Edges:     []GraphEdge{},
+		Critical:  CriticalPath{TaskIds: []string{}},
+		byId:      map[string]int{},
+	}
+
+	sorted := make([]Task, 0, len(tasks))
+	for _, t := range tasks {
+		if !t.DisplayOnly {
+			sorted = append(sorted, t)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Id < sorted[j].Id })
+
+	for i := range sorted {
+		t := &sorted[i]
+		duration, estimated := graphDuration(t, now)
+		g.byId[t.Id] = len(g.Nodes)
+		g.Nodes = append(g.Nodes, GraphNode{
+			TaskId:       t.Id,
+			DisplayName:  t.DisplayName,
+			BuildVariant: t.BuildVariant,
+			Status:       t.Status,
+			Duration:     duration,
+			Estimated:    estimated,
+		})
+	}
+
+	predecessors := make([][]int, len(g.Nodes))
+	successors := make([][]int, len(g.Nodes))
+	for i := range sorted {
+		for _, dep := range sorted[i].DependsOn {
+			from, ok := g.byId[dep.TaskId]
+			if !ok {
+				continue
+			}
+			g.Edges = append(g.Edges, GraphEdge{From: dep.TaskId, To: sorted[i].Id, Status: dep.Status})
+			predecessors[i] = append(predecessors[i], from)
+			successors[from] = append(successors[from], i)
+		}
+	}
+
+	order, err := topologicalOrder(successors, predecessors)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem building dependency graph for version '%s'", versionId)
+	}
+	g.schedule(order, predecessors, successors)
+
+	return g, nil
+}
+
+// topologicalOrder returns the node indexes ordered so that every node comes
+// after the nodes it depends on.
+func topologicalOrder(successors, predecessors [][]int) ([]int, error) {
+	remaining := make([]int, len(predecessors))
+	queue := []int{}
+	for i := range predecessors {
+		remaining[i] = len(predecessors[i])
+		if remaining[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+
+	order := make([]int, 0, len(predecessors))
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		order = append(order, next)
+		for _, s := range successors[next] {
+			remaining[s]--
+			if remaining[s] == 0 {
+				queue = append(queue, s)
+			}
+		}
+	}
+
+	if len(order) != len(predecessors) {
+		return nil, errors.Errorf("dependencies of %d tasks contain a cycle", len(predecessors)-len(order))
+	}
+	return order, nil
+}
+
+// schedule computes the earliest start and finish of every node, the slack
+// each node has before it delays the version, and the critical path.
+func (g *DependencyGraph) schedule(order []int, predecessors, successors [][]int) {
+	if len(order) == 0 {
+		return
+	}
+
+	for _, i := range order {
+		for _, p := range predecessors[i] {
+			if g.Nodes[p].EarliestFinish > g.Nodes[i].EarliestStart {
+				g.Nodes[i].EarliestStart = g.Nodes[p].EarliestFinish
+			}
+		}
+		g.Nodes[i].EarliestFinish = g.Nodes[i].EarliestStart + g.Nodes[i].Duration
+		if g.Nodes[i].EarliestFinish > g.Critical.Duration {
+			g.Critical.Duration = g.Nodes[i].EarliestFinish
+		}
+	}
+
+	latestFinish := make([]time.Duration, len(g.Nodes))
+	for k := len(order) - 1; k >= 0; k-- {
+		i := order[k]
+		latestFinish[i] = g.Critical.Duration
+		for _, s := range successors[i] {
+			if start := latestFinish[s] - g.Nodes[s].Duration; start < latestFinish[i] {
+				latestFinish[i] = start
+			}
+		}
+		g.Nodes[i].Slack = latestFinish[i] - g.Nodes[i].EarliestFinish
+	}
+
+	// walk back from the task that finishes last, always through the
+	// dependency that finishes last, breaking ties by task id
+	end := -1
+	for _, i := range order {
+		if end == -1 || g.Nodes[i].EarliestFinish > g.Nodes[end].EarliestFinish ||
+			(g.Nodes[i].EarliestFinish == g.Nodes[end].EarliestFinish && g.Nodes[i].TaskId < g.Nodes[end].TaskId) {
+			end = i
+		}
+	}
+	path := []string{}
+	for current := end; current != -1; {
+		g.Nodes[current].OnCriticalPath = true
+		path = append(path, g.Nodes[current].TaskId)
+
+		next := -1
+		for _, p := range predecessors[current] {
+			if g.Nodes[p].EarliestFinish != g.Nodes[current].EarliestStart {
+				continue
+			}
+			if next == -1 || g.Nodes[p].TaskId < g.Nodes[next].TaskId {
+				next = p
+			}
+		}
+		current = next
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	g.Critical.TaskIds = path
+}
+
+// Node returns the node for the task, or nil if it is not in the graph.
+func (g *DependencyGraph) Node(taskId string) *GraphNode {
+	i, ok := g.byId[taskId]
+	if !ok {
+		return nil
+	}
+	return &g.Nodes[i]
+}
+
+// DOT renders the graph in the Graphviz DOT language. Tasks are grouped by
+// build variant and the critical path is highlighted.
+func (g *DependencyGraph) DOT() string {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "digraph %q {\n", g.VersionId)
+	buf.WriteString("\trankdir=LR;\n")
+	buf.WriteString("\tnode [shape=box];\n")
+
+	variants := map[string][]GraphNode{}
+	variantNames := []string{}
+	for _, n := range g.Nodes {
+		if _, ok := variants[n.BuildVariant]; !ok {
+			variantNames = append(variantNames, n.BuildVariant)
+		}
+		variants[n.BuildVariant] = append(variants[n.BuildVariant], n)
+	}
+	sort.Strings(variantNames)
+
+	for i, bv := range variantNames {
+		fmt.Fprintf(buf, "\tsubgraph \"cluster_%d\" {\n", i)
+		fmt.Fprintf(buf, "\t\tlabel=%q;\n", bv)
+		for _, n := range variants[bv] {
+			duration := n.Duration.Round(time.Second).String()
+			if n.Estimated {
+				duration = "~" + duration
+			}
+			attrs := fmt.Sprintf("label=%q", fmt.Sprintf("%s\n%s (%s)", n.DisplayName, duration, n.Status))
+			if n.OnCriticalPath {
+				attrs += ", color=red, penwidth=2"
+			}
+			fmt.Fprintf(buf, "\t\t%q [%s];\n", n.TaskId, attrs)
+		}
+		buf.WriteString("\t}\n")
+	}
+
+	criticalEdges := map[GraphEdge]bool{}
+	for i := 1; i < len(g.Critical.TaskIds); i++ {
+		criticalEdges[GraphEdge{From: g.Critical.TaskIds[i-1], To: g.Critical.TaskIds[i]}] = true
+	}
+
+	for _, e := range g.Edges {
+		attrs := []string{}
+		if e.Status != "" {
+			attrs = append(attrs, fmt.Sprintf("label=%q", e.Status))
+		}
+		if criticalEdges[GraphEdge{From: e.From, To: e.To}] {
+			attrs = append(attrs, "color=red", "penwidth=2")
+		}
+		if len(attrs) == 0 {
+			fmt.Fprintf(buf, "\t%q -> %q;\n", e.From, e.To)
+			continue
+		}
+		fmt.Fprintf(buf, "\t%q -> %q [", e.From, e.To)
+		for i, attr := range attrs {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(attr)
+		}
+		buf.WriteString("];\n")
+	}
+
+	buf.WriteString("}\n")
+	return buf.String()
+}
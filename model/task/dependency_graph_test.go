@@ -0,0 +1,93 @@
+package task
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependencyGraph(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	now := time.Now()
+
+	//     compile (10m) --> unit (20m) --------> package (5m)
+	//             \                            /
+	//              +--> lint (2m) --> docs (1m)
+	tasks := []Task{
+		{Id: "compile", DisplayName: "compile", BuildVariant: "linux", Status: evergreen.TaskSucceeded, TimeTaken: 10 * time.Minute},
+		{Id: "unit", DisplayName: "unit", BuildVariant: "linux", Status: evergreen.TaskStarted,
+			StartTime: now.Add(-5 * time.Minute), ExpectedDuration: 20 * time.Minute,
+			DependsOn: []Dependency{{TaskId: "compile"}}},
+		{Id: "lint", DisplayName: "lint", BuildVariant: "linux", Status: evergreen.TaskSucceeded, TimeTaken: 2 * time.Minute,
+			DependsOn: []Dependency{{TaskId: "compile"}}},
+		{Id: "docs", DisplayName: "docs", BuildVariant: "docs", Status: evergreen.TaskUndispatched, ExpectedDuration: time.Minute,
+			DependsOn: []Dependency{{TaskId: "lint", Status: evergreen.TaskFailed}, {TaskId: "other_version_task"}}},
+		{Id: "package", DisplayName: "package", BuildVariant: "linux", Status: evergreen.TaskUndispatched, ExpectedDuration: 5 * time.Minute,
+			DependsOn: []Dependency{{TaskId: "unit"}, {TaskId: "docs"}}},
+		{Id: "display", DisplayName: "display", DisplayOnly: true, ExecutionTasks: []string{"unit"}},
+	}
+
+	g, err := newDependencyGraph("v1", tasks, now)
+	require.NoError(err)
+	assert.Equal("v1", g.VersionId)
+	require.Len(g.Nodes, 5)
+	assert.Nil(g.Node("display"))
+	assert.Len(g.Edges, 5)
+
+	compile := g.Node("compile")
+	assert.False(compile.Estimated)
+	assert.Equal(10*time.Minute, compile.Duration)
+
+	unit := g.Node("unit")
+	assert.True(unit.Estimated)
+	assert.Equal(10*time.Minute, unit.EarliestStart)
+	assert.Equal(30*time.Minute, unit.EarliestFinish)
+	assert.Equal(time.Duration(0), unit.Slack)
+
+	docs := g.Node("docs")
+	assert.Equal(12*time.Minute, docs.EarliestStart)
+	assert.Equal(17*time.Minute, docs.Slack)
+	assert.False(docs.OnCriticalPath)
+
+	assert.Equal([]string{"compile", "unit", "package"}, g.Critical.TaskIds)
+	assert.Equal(35*time.Minute, g.Critical.Duration)
+	for _, id := range g.Critical.TaskIds {
+		assert.True(g.Node(id).OnCriticalPath)
+	}
+
+	// a task running longer than expected extends the path
+	tasks[1].StartTime = now.Add(-40 * time.Minute)
+	g, err = newDependencyGraph("v1", tasks, now)
+	require.NoError(err)
+	assert.Equal(40*time.Minute, g.Node("unit").Duration)
+	assert.Equal(55*time.Minute, g.Critical.Duration)
+
+	dot := g.DOT()
+	assert.True(strings.HasPrefix(dot, `digraph "v1" {`))
+	assert.Contains(dot, `"compile" -> "unit" [color=red, penwidth=2];`)
+	assert.Contains(dot, `"lint" -> "docs" [label="failed"];`)
+	assert.Contains(dot, `"compile" -> "lint";`)
+	assert.Contains(dot, `label="docs";`)
+	assert.NotContains(dot, "display")
+}
+
+func TestDependencyGraphEmptyAndCycles(t *testing.T) {
+	assert := assert.New(t)
+
+	g, err := NewDependencyGraph("v1", nil)
+	assert.NoError(err)
+	assert.Empty(g.Nodes)
+	assert.Empty(g.Critical.TaskIds)
+
+	_, err = NewDependencyGraph("v1", []Task{
+		{Id: "a", DependsOn: []Dependency{{TaskId: "b"}}},
+		{Id: "b", DependsOn: []Dependency{{TaskId: "a"}}},
+		{Id: "c"},
+	})
+	assert.Error(err)
+}
@@ -145,6 +145,12 @@ type Task struct {
 	GenerateTask bool `bson:"generate_task,omitempty" json:"generate_task,omitempty"`
 	// GeneratedBy, if present, is the ID of the task that generated this task.
 	GeneratedBy string `bson:"generated_by,omitempty" json:"generated_by,omitempty"`
+
+	// AutoRetries is the number of times the task has been restarted by its
+	// retry policy, and AutoRetryAt is when the next of those restarts is
+	// due, if one is pending.
+	AutoRetries int       `bson:"auto_retries,omitempty" json:"auto_retries,omitempty"`
+	AutoRetryAt time.Time `bson:"auto_retry_at,omitempty" json:"auto_retry_at,omitempty"`
 }
 
 // Dependency represents a task that must be completed before the owning
@@ -173,6 +179,46 @@ type DistroCost struct {
 	NumTasks         int                    `bson:"num_tasks"`
 }
 
+// ProjectUsage is the aggregation of time taken by all tasks of a project
+// that recently finished on a distro.
+type ProjectUsage struct {
+	Project      string        `bson:"project"`
+	SumTimeTaken time.Duration `bson:"sum_time_taken"`
+	NumTasks     int           `bson:"num_tasks"`
+}
+
+// FindProjectUsageForDistro returns the time consumed by each project's tasks
+// that finished on the distro since the given time.
+func FindProjectUsageForDistro(distroId string, since time.Time) ([]ProjectUsage, error) {
+	usage := []ProjectUsage{}
+	if err := Aggregate(ProjectUsageByDistroIdPipeline(distroId, since), &usage); err != nil {
+		return nil, errors.Wrapf(err, "problem aggregating project usage for distro '%s'", distroId)
+	}
+
+	return usage, nil
+}
+
+// ArrivalStats is the aggregation of the tasks that were activated on a
+// distro during one hour of one day of the week, in UTC.
+type ArrivalStats struct {
+	DayOfWeek time.Weekday `bson:"day_of_week"`
+	Hour      int          `bson:"hour"`
+	NumTasks  int          `bson:"num_tasks"`
+	// SumExpectedDuration is the total expected duration of the tasks.
+	SumExpectedDuration time.Duration `bson:"sum_expected_duration"`
+}
+
+// FindArrivalStatsForDistro returns the number and expected duration of the
+// tasks activated on the distro since the given time, by hour of the week.
+func FindArrivalStatsForDistro(distroId string, since time.Time) ([]ArrivalStats, error) {
+	stats := []ArrivalStats{}
+	if err := Aggregate(ArrivalsByDistroIdPipeline(distroId, since), &stats); err != nil {
+		return nil, errors.Wrapf(err, "problem aggregating task arrivals for distro '%s'", distroId)
+	}
+
+	return stats, nil
+}
+
 // SetBSON allows us to use dependency representation of both
 // just task Ids and of true Dependency structs.
 //  TODO eventually drop all of this switching
@@ -801,6 +847,7 @@ func (t *Task) Reset() error {
 	t.ScheduledTime = util.ZeroTime
 	t.FinishTime = util.ZeroTime
 	t.ResetWhenFinished = false
+	t.AutoRetryAt = time.Time{}
 	reset := bson.M{
 		"$set": bson.M{
 			ActivatedKey:     true,
@@ -814,6 +861,7 @@ func (t *Task) Reset() error {
 		"$unset": bson.M{
 			DetailsKey:           "",
 			ResetWhenFinishedKey: "",
+			AutoRetryAtKey:       "",
 		},
 	}
 
@@ -849,7 +897,8 @@ func ResetTasks(taskIds []string) error {
 			FinishTimeKey:    util.ZeroTime,
 		},
 		"$unset": bson.M{
-			DetailsKey: "",
+			DetailsKey:     "",
+			AutoRetryAtKey: "",
 		},
 	}
 
@@ -1252,6 +1301,36 @@ func (t *Task) SetResetWhenFinished(detail *apimodels.TaskEndDetail) error {
 	)
 }
 
+// SetAutoRetryAt records when the task's retry policy is due to restart it.
+func (t *Task) SetAutoRetryAt(retryAt time.Time) error {
+	t.AutoRetryAt = retryAt
+	return UpdateOne(
+		bson.M{
+			IdKey: t.Id,
+		},
+		bson.M{
+			"$set": bson.M{
+				AutoRetryAtKey: retryAt,
+			},
+		},
+	)
+}
+
+// IncAutoRetries counts a restart of the task by its retry policy.
+func (t *Task) IncAutoRetries() error {
+	t.AutoRetries++
+	return UpdateOne(
+		bson.M{
+			IdKey: t.Id,
+		},
+		bson.M{
+			"$inc": bson.M{
+				AutoRetriesKey: 1,
+			},
+		},
+	)
+}
+
 // MergeTestResultsBulk takes a slice of task structs and returns the slice with
 // test results populated. Note that the order may change. The second parameter
 // can be used to use a specific test result filtering query, otherwise all test
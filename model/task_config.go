@@ -1,11 +1,13 @@
 package model
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"sync"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/task"
@@ -27,7 +29,8 @@ type TaskConfig struct {
 	GithubPatchData patch.GithubPatch
 	Timeout         *Timeout
 
-	mu sync.RWMutex
+	cacheStats apimodels.CacheStats
+	mu         sync.RWMutex
 }
 
 type Timeout struct {
@@ -59,6 +62,55 @@ func (t *TaskConfig) GetExecTimeout() int {
 	return t.Timeout.ExecTimeoutSecs
 }
 
+// RecordCacheRestore records a cache hit, and the size of the restored
+// cache, or a cache miss.
+func (t *TaskConfig) RecordCacheRestore(hit bool, size int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !hit {
+		t.cacheStats.Misses++
+		return
+	}
+	t.cacheStats.Hits++
+	t.cacheStats.BytesRestored += size
+}
+
+// RecordCacheSave records the saving of a cache of the given size.
+func (t *TaskConfig) RecordCacheSave(size int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cacheStats.Saves++
+	t.cacheStats.BytesSaved += size
+}
+
+// GetCacheStats returns the task's cache statistics, or nil if the task has
+// not used the cache.
+func (t *TaskConfig) GetCacheStats() *apimodels.CacheStats {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.cacheStats == (apimodels.CacheStats{}) {
+		return nil
+	}
+	stats := t.cacheStats
+	return &stats
+}
+
+// GetResourceLimits returns the limits on the resources the task may use,
+// which are the distro's limits restricted by any limits set on the task.
+func (t *TaskConfig) GetResourceLimits() distro.ResourceLimits {
+	limits := distro.ResourceLimits{}
+	if t.Distro != nil && t.Distro.ResourceLimits != nil {
+		limits = *t.Distro.ResourceLimits
+	}
+	if t.Project == nil || t.Task == nil {
+		return limits
+	}
+	if projectTask := t.Project.FindProjectTask(t.Task.DisplayName); projectTask != nil && projectTask.ResourceLimits != nil {
+		limits = limits.Restrict(*projectTask.ResourceLimits)
+	}
+	return limits
+}
+
 func NewTaskConfig(d *distro.Distro, v *version.Version, p *Project, t *task.Task, r *ProjectRef, patchDoc *patch.Patch) (*TaskConfig, error) {
 	// do a check on if the project is empty
 	if p == nil {
@@ -144,7 +196,7 @@ func MakeConfigFromTask(t *task.Task) (*TaskConfig, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "error making TaskConfig")
 	}
-	projVars, err := FindOneProjectVars(t.Project)
+	projVars, err := FindExpansionVars(context.TODO(), t.Project)
 	if err != nil {
 		return nil, errors.Wrap(err, "error finding project vars")
 	}
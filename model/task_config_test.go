@@ -4,8 +4,11 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTaskConfig(t *testing.T) {
@@ -37,3 +40,40 @@ func TestTaskConfig(t *testing.T) {
 	assert.Error(err)
 	assert.Equal("", out)
 }
+
+func TestTaskConfigResourceLimits(t *testing.T) {
+	assert := assert.New(t)
+
+	project := &Project{}
+	require.NoError(t, LoadProjectInto([]byte(`
+tasks:
+- name: compile
+  resource_limits:
+    memory_mb: 4096
+    processes: 200
+- name: lint
+- name: test
+  resource_limits:
+    memory_mb: 1024
+    cpus: 8
+`), "project", project))
+
+	conf := &TaskConfig{
+		Distro:  &distro.Distro{ResourceLimits: &distro.ResourceLimits{MemoryMB: 2048, CPUs: 2}},
+		Project: project,
+		Task:    &task.Task{DisplayName: "compile"},
+	}
+	assert.Equal(distro.ResourceLimits{MemoryMB: 2048, CPUs: 2, Processes: 200}, conf.GetResourceLimits(), "the project should not raise the distro's limits")
+
+	conf.Task.DisplayName = "test"
+	assert.Equal(distro.ResourceLimits{MemoryMB: 1024, CPUs: 2}, conf.GetResourceLimits())
+
+	conf.Task.DisplayName = "lint"
+	assert.Equal(distro.ResourceLimits{MemoryMB: 2048, CPUs: 2}, conf.GetResourceLimits())
+
+	conf.Distro = &distro.Distro{}
+	assert.True(conf.GetResourceLimits().IsZero())
+
+	conf.Task.DisplayName = "compile"
+	assert.Equal(distro.ResourceLimits{MemoryMB: 4096, Processes: 200}, conf.GetResourceLimits())
+}
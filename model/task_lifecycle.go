@@ -9,6 +9,7 @@ import (
 	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/flaky"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/task"
@@ -394,7 +395,7 @@ func MarkEnd(t *task.Task, caller string, finishTime time.Time, detail *apimodel
 	deactivatePrevious bool, updates *StatusChanges) error {
 
 	if t.HasFailedTests() {
-		detail.Status = evergreen.TaskFailed
+		applyTestQuarantine(t, detail)
 	}
 
 	t.Details = *detail
@@ -425,8 +426,17 @@ func MarkEnd(t *task.Task, caller string, finishTime time.Time, detail *apimodel
 		}
 	}
 
-	// activate/deactivate other task if this is not a patch request's task
-	if !evergreen.IsPatchRequester(t.Requester) {
+	retrying, err := evalAutoRetry(t, detail)
+	if err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"message": "problem evaluating retry policy",
+			"task_id": t.Id,
+		}))
+	}
+
+	// activate/deactivate other task if this is not a patch request's task,
+	// unless the task is about to run again
+	if !evergreen.IsPatchRequester(t.Requester) && !retrying {
 		if t.IsPartOfDisplay() {
 			err = evalStepback(t.DisplayTask, caller, t.DisplayTask.Status, deactivatePrevious)
 		} else {
@@ -452,6 +462,58 @@ func MarkEnd(t *task.Task, caller string, finishTime time.Time, detail *apimodel
 	return nil
 }
 
+// applyTestQuarantine sets the status of a task with failed tests. The task
+// fails if any failed test is not quarantined by the project. A failure
+// reported by a test command is turned into a success only if every failed
+// test is quarantined and nothing else explains the failure: the command did
+// not time out or run out of memory, and no test failed silently or exited
+// with an error while passing.
+func applyTestQuarantine(t *task.Task, detail *apimodels.TaskEndDetail) {
+	quarantined, err := flaky.FindQuarantinedTests(t.Project, t.DisplayName)
+	if err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"message": "could not find quarantined tests, failing task",
+			"task_id": t.Id,
+			"project": t.Project,
+		}))
+		detail.Status = evergreen.TaskFailed
+		return
+	}
+
+	numQuarantined := 0
+	otherFailures := false
+	for _, test := range t.LocalTestResults {
+		switch test.Status {
+		case evergreen.TestFailedStatus:
+			if !quarantined[test.TestFile] {
+				detail.Status = evergreen.TaskFailed
+				return
+			}
+			numQuarantined++
+		case evergreen.TestSilentlyFailedStatus:
+			otherFailures = true
+		default:
+			if test.ExitCode != 0 && test.Status != evergreen.TestSkippedStatus {
+				otherFailures = true
+			}
+		}
+	}
+
+	if detail.Status != evergreen.TaskFailed || detail.Type != evergreen.CommandTypeTest ||
+		detail.TimedOut || detail.OOMKilled || otherFailures {
+		return
+	}
+	detail.Status = evergreen.TaskSucceeded
+	detail.Description = fmt.Sprintf("%d quarantined test(s) failed", numQuarantined)
+
+	grip.Info(message.Fields{
+		"message":         "ignoring failures of quarantined tests",
+		"task_id":         t.Id,
+		"project":         t.Project,
+		"num_quarantined": numQuarantined,
+	})
+}
+
 func evalStepback(t *task.Task, caller, status string, deactivatePrevious bool) error {
 	if status == evergreen.TaskFailed {
 		var shouldStepBack bool
@@ -928,7 +990,68 @@ func ClearAndResetStrandedTask(h *host.Host) error {
 		return errors.Wrap(TryResetTask(t.Id, "mci", evergreen.MonitorPackage, detail), "problem resetting task")
 	}
 
-	return nil
+	_, err = evalAutoRetry(t, &t.Details)
+	return errors.Wrap(err, "problem evaluating retry policy")
+}
+
+// HandleSpotInterruption stops dispatching tasks to a host whose spot
+// instance will be reclaimed and resets the task running on it, since the
+// task cannot finish before the instance is reclaimed. The reset does not
+// count against the task's retry policy. The agent may report the same
+// notice more than once, so only the first report has an effect.
+func HandleSpotInterruption(h *host.Host, notice apimodels.SpotInterruptionNotice) error {
+	recorded, err := h.SetSpotInterrupted(time.Now())
+	if err != nil {
+		return errors.Wrapf(err, "problem recording spot interruption of host '%s'", h.Id)
+	}
+	if !recorded {
+		return nil
+	}
+
+	event.LogHostSpotInterrupted(h.Id, h.RunningTask, fmt.Sprintf("%s at %s", notice.Action, notice.Time.Format(time.RFC3339)))
+
+	if h.Status == evergreen.HostRunning {
+		if err = h.SetDecommissioned(evergreen.User, "spot instance interrupted"); err != nil {
+			return errors.Wrapf(err, "problem decommissioning host '%s'", h.Id)
+		}
+	}
+
+	return errors.Wrapf(resetSpotInterruptedTask(h), "problem resetting task running on host '%s'", h.Id)
+}
+
+func resetSpotInterruptedTask(h *host.Host) error {
+	if h.RunningTask == "" {
+		return nil
+	}
+
+	t, err := task.FindOne(task.ById(h.RunningTask))
+	if err != nil {
+		return errors.Wrapf(err, "database error finding task '%s'", h.RunningTask)
+	} else if t == nil {
+		return nil
+	}
+
+	if err = h.ClearRunningTask(); err != nil {
+		return errors.Wrapf(err, "problem clearing running task from host '%s'", h.Id)
+	}
+
+	if t.IsFinished() {
+		return nil
+	}
+
+	if err = t.MarkSystemFailed(); err != nil {
+		return errors.Wrap(err, "problem marking task failed")
+	}
+
+	detail := &apimodels.TaskEndDetail{
+		Status:      evergreen.TaskFailed,
+		Type:        evergreen.CommandTypeSystem,
+		Description: "spot instance interrupted",
+	}
+	if t.IsPartOfDisplay() {
+		return t.DisplayTask.SetResetWhenFinished(detail)
+	}
+	return errors.Wrap(TryResetTask(t.Id, evergreen.User, evergreen.MonitorPackage, detail), "problem resetting task")
 }
 
 func UpdateDisplayTask(t *task.Task) error {
@@ -9,7 +9,10 @@ import (
 	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/flaky"
+	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/testutil"
@@ -2195,3 +2198,116 @@ func TestDisplayTaskDelayedRestart(t *testing.T) {
 	assert.NoError(err)
 	assert.Equal(detail.Description, oldTask.Details.Description)
 }
+
+func TestApplyTestQuarantine(t *testing.T) {
+	assert := assert.New(t)
+	require.NoError(t, db.ClearCollections(flaky.Collection))
+	require.NoError(t, flaky.SetQuarantined("sample", "compile", "quarantined", true, "me"))
+
+	testTask := &task.Task{
+		Id:          "t1",
+		Project:     "sample",
+		DisplayName: "compile",
+		LocalTestResults: []task.TestResult{
+			{TestFile: "quarantined", Status: evergreen.TestFailedStatus},
+			{TestFile: "passing", Status: evergreen.TestSucceededStatus},
+		},
+	}
+
+	detail := &apimodels.TaskEndDetail{Status: evergreen.TaskSucceeded}
+	applyTestQuarantine(testTask, detail)
+	assert.Equal(evergreen.TaskSucceeded, detail.Status)
+
+	detail = &apimodels.TaskEndDetail{Status: evergreen.TaskFailed, Type: evergreen.CommandTypeTest}
+	applyTestQuarantine(testTask, detail)
+	assert.Equal(evergreen.TaskSucceeded, detail.Status)
+	assert.Equal("1 quarantined test(s) failed", detail.Description)
+
+	detail = &apimodels.TaskEndDetail{Status: evergreen.TaskFailed, Type: evergreen.CommandTypeSystem}
+	applyTestQuarantine(testTask, detail)
+	assert.Equal(evergreen.TaskFailed, detail.Status)
+
+	// a test command that failed for another reason still fails
+	detail = &apimodels.TaskEndDetail{Status: evergreen.TaskFailed, Type: evergreen.CommandTypeTest, TimedOut: true}
+	applyTestQuarantine(testTask, detail)
+	assert.Equal(evergreen.TaskFailed, detail.Status)
+
+	detail = &apimodels.TaskEndDetail{Status: evergreen.TaskFailed, Type: evergreen.CommandTypeTest, OOMKilled: true}
+	applyTestQuarantine(testTask, detail)
+	assert.Equal(evergreen.TaskFailed, detail.Status)
+
+	testTask.LocalTestResults = append(testTask.LocalTestResults, task.TestResult{TestFile: "crashed", Status: evergreen.TestSucceededStatus, ExitCode: 1})
+	detail = &apimodels.TaskEndDetail{Status: evergreen.TaskFailed, Type: evergreen.CommandTypeTest}
+	applyTestQuarantine(testTask, detail)
+	assert.Equal(evergreen.TaskFailed, detail.Status)
+
+	testTask.LocalTestResults[2] = task.TestResult{TestFile: "crashed", Status: evergreen.TestSilentlyFailedStatus}
+	detail = &apimodels.TaskEndDetail{Status: evergreen.TaskFailed, Type: evergreen.CommandTypeTest}
+	applyTestQuarantine(testTask, detail)
+	assert.Equal(evergreen.TaskFailed, detail.Status)
+	testTask.LocalTestResults = testTask.LocalTestResults[:2]
+
+	testTask.LocalTestResults = append(testTask.LocalTestResults, task.TestResult{TestFile: "other", Status: evergreen.TestFailedStatus})
+	detail = &apimodels.TaskEndDetail{Status: evergreen.TaskSucceeded}
+	applyTestQuarantine(testTask, detail)
+	assert.Equal(evergreen.TaskFailed, detail.Status)
+}
+
+func TestHandleSpotInterruption(t *testing.T) {
+	require.NoError(t, db.ClearCollections(task.Collection, task.OldCollection, build.Collection, host.Collection, event.AllLogCollection))
+	assert := assert.New(t)
+	require := require.New(t)
+
+	t1 := task.Task{
+		Id:        "t1",
+		BuildId:   "b",
+		Status:    evergreen.TaskStarted,
+		Activated: true,
+		HostId:    "h1",
+		StartTime: time.Now().Add(-time.Hour),
+	}
+	require.NoError(t1.Insert())
+	b := build.Build{
+		Id: "b",
+		Tasks: []build.TaskCache{
+			{Id: "t1", Status: evergreen.TaskStarted, Activated: true},
+		},
+	}
+	require.NoError(b.Insert())
+	h := host.Host{
+		Id:          "h1",
+		Distro:      distro.Distro{Id: "d1"},
+		Status:      evergreen.HostRunning,
+		RunningTask: "t1",
+	}
+	require.NoError(h.Insert())
+
+	notice := apimodels.SpotInterruptionNotice{Action: "terminate", Time: time.Now().Add(2 * time.Minute)}
+	require.NoError(HandleSpotInterruption(&h, notice))
+
+	dbHost, err := host.FindOneId("h1")
+	require.NoError(err)
+	assert.Equal(evergreen.HostDecommissioned, dbHost.Status)
+	assert.Empty(dbHost.RunningTask)
+	assert.False(dbHost.SpotInterruptionTime.IsZero())
+
+	// the task is reset even though it ran for longer than the
+	// unschedulable threshold
+	dbTask, err := task.FindOne(task.ById("t1"))
+	require.NoError(err)
+	assert.Equal(evergreen.TaskUndispatched, dbTask.Status)
+	assert.Equal(1, dbTask.Execution)
+
+	// a repeated notice has no effect
+	require.NoError(HandleSpotInterruption(dbHost, notice))
+	dbTask, err = task.FindOne(task.ById("t1"))
+	require.NoError(err)
+	assert.Equal(1, dbTask.Execution)
+
+	n, err := host.CountSpotInterruptions("d1", time.Now().Add(-time.Hour))
+	require.NoError(err)
+	assert.Equal(1, n)
+	n, err = host.CountSpotInterruptions("d2", time.Now().Add(-time.Hour))
+	require.NoError(err)
+	assert.Equal(0, n)
+}
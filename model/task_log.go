@@ -291,3 +291,83 @@ func FindMostRecentLogMessages(taskId string, execution int, numMsgs int,
 
 	return logMsgs, nil
 }
+
+// FindTaskLogMessagesFromOffset returns the messages of a task execution's
+// log starting at the given offset, along with the offset of the message that
+// follows the last one returned. Offsets count every message of the
+// execution, whatever its type, in the order the messages were logged, so a
+// caller can resume reading where it left off as the log grows. To ignore
+// type filtering, pass in an empty slice.
+func FindTaskLogMessagesFromOffset(taskId string, execution int, offset int,
+	msgTypes []string) ([]apimodels.LogMessage, int, error) {
+	session, db, err := getSessionAndDB()
+	if err != nil {
+		return nil, offset, err
+	}
+	defer session.Close()
+
+	oldMsgTypes := []string{}
+	for _, msgType := range msgTypes {
+		switch msgType {
+		case apimodels.SystemLogPrefix:
+			oldMsgTypes = append(oldMsgTypes, "system")
+		case apimodels.AgentLogPrefix:
+			oldMsgTypes = append(oldMsgTypes, "agent")
+		case apimodels.TaskLogPrefix:
+			oldMsgTypes = append(oldMsgTypes, "task")
+		}
+	}
+
+	query := bson.M{
+		TaskLogTaskIdKey:    taskId,
+		TaskLogExecutionKey: execution,
+	}
+
+	// count the messages of each document, without reading them, to skip
+	// the documents that end before the offset
+	counts := db.C(TaskLogCollection).Find(query).
+		Select(bson.M{TaskLogMessageCountKey: 1}).
+		Sort(TaskLogTimestampKey, TaskLogIdKey).Iter()
+	position := 0
+	numSkipped := 0
+	countObj := TaskLog{}
+	for counts.Next(&countObj) {
+		if position+countObj.MessageCount > offset {
+			break
+		}
+		position += countObj.MessageCount
+		numSkipped++
+	}
+	if err = counts.Close(); err != nil {
+		return nil, offset, err
+	}
+
+	iter := db.C(TaskLogCollection).Find(query).
+		Sort(TaskLogTimestampKey, TaskLogIdKey).Skip(numSkipped).Iter()
+
+	logMsgs := []apimodels.LogMessage{}
+	logObj := TaskLog{}
+	for iter.Next(&logObj) {
+		for _, logMsg := range logObj.Messages {
+			position++
+			if position <= offset {
+				continue
+			}
+			if len(msgTypes) != 0 {
+				if !(util.StringSliceContains(msgTypes, logMsg.Type) ||
+					util.StringSliceContains(oldMsgTypes, logMsg.Type)) {
+					continue
+				}
+			}
+			logMsgs = append(logMsgs, logMsg)
+		}
+	}
+	if err = iter.Close(); err != nil {
+		return nil, offset, err
+	}
+
+	if position < offset {
+		position = offset
+	}
+	return logMsgs, position, nil
+}
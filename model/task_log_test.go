@@ -294,3 +294,49 @@ func TestFindMostRecentLogMessages(t *testing.T) {
 	})
 
 }
+
+func TestFindTaskLogMessagesFromOffset(t *testing.T) {
+
+	Convey("When reading task log messages from an offset", t, func() {
+
+		testutil.HandleTestingErr(cleanUpLogDB(), t, "Error cleaning up task log"+
+			" database")
+
+		// insert 3 logs of 2 messages each, alternating task and agent messages
+		startTime := time.Now()
+		for i := 0; i < 3; i++ {
+			taskLog := &TaskLog{
+				TaskId:       "task_id",
+				Timestamp:    startTime.Add(time.Second * time.Duration(i)),
+				MessageCount: 2,
+				Messages: []apimodels.LogMessage{
+					{Type: apimodels.TaskLogPrefix, Message: "task"},
+					{Type: apimodels.AgentLogPrefix, Message: "agent"},
+				},
+			}
+			So(taskLog.Insert(), ShouldBeNil)
+		}
+
+		Convey("all messages after the offset should be returned", func() {
+			msgs, next, err := FindTaskLogMessagesFromOffset("task_id", 0, 3, []string{})
+			So(err, ShouldBeNil)
+			So(len(msgs), ShouldEqual, 3)
+			So(msgs[0].Message, ShouldEqual, "agent")
+			So(next, ShouldEqual, 6)
+		})
+
+		Convey("filtering by type should not change the offsets", func() {
+			msgs, next, err := FindTaskLogMessagesFromOffset("task_id", 0, 1, []string{apimodels.TaskLogPrefix})
+			So(err, ShouldBeNil)
+			So(len(msgs), ShouldEqual, 2)
+			So(next, ShouldEqual, 6)
+		})
+
+		Convey("an offset past the end should return no messages", func() {
+			msgs, next, err := FindTaskLogMessagesFromOffset("task_id", 0, 10, []string{})
+			So(err, ShouldBeNil)
+			So(len(msgs), ShouldEqual, 0)
+			So(next, ShouldEqual, 10)
+		})
+	})
+}
@@ -25,6 +25,22 @@ type TaskQueue struct {
 	Distro      string          `bson:"distro" json:"distro"`
 	GeneratedAt time.Time       `bson:"generated_at" json:"generated_at"`
 	Queue       []TaskQueueItem `bson:"queue" json:"queue"`
+	// ProjectShares are the shares of the distro's host time that the fair
+	// share prioritizer computed for each project when it ordered the
+	// queue.
+	ProjectShares []ProjectShare `bson:"project_shares,omitempty" json:"project_shares,omitempty"`
+}
+
+// ProjectShare describes the host time a project has consumed on a distro
+// and how much of the queue the fair share prioritizer assigned to it.
+type ProjectShare struct {
+	Project             string  `bson:"project" json:"project"`
+	ConsumedHostSeconds float64 `bson:"consumed_host_secs" json:"consumed_host_secs"`
+	QueuedHostSeconds   float64 `bson:"queued_host_secs" json:"queued_host_secs"`
+	QueuedTasks         int     `bson:"queued_tasks" json:"queued_tasks"`
+	// Share is the fraction of the distro's recently consumed host time
+	// that belongs to this project.
+	Share float64 `bson:"share" json:"share"`
 }
 
 type TaskDep struct {
@@ -53,6 +69,7 @@ var (
 	taskQueueDistroKey      = bsonutil.MustHaveTag(TaskQueue{}, "Distro")
 	taskQueueGeneratedAtKey = bsonutil.MustHaveTag(TaskQueue{}, "GeneratedAt")
 	taskQueueQueueKey       = bsonutil.MustHaveTag(TaskQueue{}, "Queue")
+	taskQueueSharesKey      = bsonutil.MustHaveTag(TaskQueue{}, "ProjectShares")
 
 	// bson fields for the individual task queue items
 	taskQueueItemIdKey           = bsonutil.MustHaveTag(TaskQueueItem{}, "Id")
@@ -144,7 +161,7 @@ func (self *TaskQueue) BlockTaskGroupTasks(spec TaskSpec, taskID string) error {
 }
 
 func (self *TaskQueue) Save() error {
-	return updateTaskQueue(self.Distro, self.Queue)
+	return updateTaskQueue(self.Distro, self.Queue, self.ProjectShares)
 }
 
 func (self *TaskQueue) FindNextTask(spec TaskSpec) *TaskQueueItem {
@@ -194,18 +211,24 @@ func (self *TaskQueue) FindNextTask(spec TaskSpec) *TaskQueueItem {
 	return nil
 }
 
-func updateTaskQueue(distro string, taskQueue []TaskQueueItem) error {
+func updateTaskQueue(distro string, taskQueue []TaskQueueItem, shares []ProjectShare) error {
+	update := bson.M{
+		"$set": bson.M{
+			taskQueueQueueKey:       taskQueue,
+			taskQueueGeneratedAtKey: time.Now(),
+		},
+	}
+	if len(shares) > 0 {
+		update["$set"].(bson.M)[taskQueueSharesKey] = shares
+	} else {
+		update["$unset"] = bson.M{taskQueueSharesKey: 1}
+	}
 	_, err := db.Upsert(
 		TaskQueuesCollection,
 		bson.M{
 			taskQueueDistroKey: distro,
 		},
-		bson.M{
-			"$set": bson.M{
-				taskQueueQueueKey:       taskQueue,
-				taskQueueGeneratedAtKey: time.Now(),
-			},
-		},
+		update,
 	)
 	return errors.WithStack(err)
 }
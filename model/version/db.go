@@ -2,6 +2,7 @@ package version
 
 import (
 	"fmt"
+	"regexp"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
@@ -40,6 +41,10 @@ var (
 	IdentifierKey          = bsonutil.MustHaveTag(Version{}, "Identifier")
 	RemoteKey              = bsonutil.MustHaveTag(Version{}, "Remote")
 	RemoteURLKey           = bsonutil.MustHaveTag(Version{}, "RemotePath")
+	TriggerIDKey           = bsonutil.MustHaveTag(Version{}, "TriggerID")
+	TriggerTypeKey         = bsonutil.MustHaveTag(Version{}, "TriggerType")
+	TriggerEventKey        = bsonutil.MustHaveTag(Version{}, "TriggerEvent")
+	ParametersKey          = bsonutil.MustHaveTag(Version{}, "Parameters")
 )
 
 // ById returns a db.Q object which will filter on {_id : <the id param>}
@@ -78,12 +83,17 @@ func ByProjectIdAndRevision(projectId, revision string) db.Q {
 		})
 }
 
+// ByProjectIdAndRevisionPrefix finds non-patch versions for the given project
+// whose revision starts with the given prefix.
 func ByProjectIdAndRevisionPrefix(projectId, revisionPrefix string) db.Q {
-	lengthHash := (40 - len(revisionPrefix))
+	lengthHash := 40 - len(revisionPrefix)
+	if lengthHash < 0 {
+		lengthHash = 0
+	}
 	return db.Query(
 		bson.M{
 			IdentifierKey: projectId,
-			RevisionKey:   bson.M{"$regex": fmt.Sprintf("^%s[0-9a-f]{%d}$", revisionPrefix, lengthHash)},
+			RevisionKey:   bson.M{"$regex": fmt.Sprintf("^%s[0-9a-f]{%d}$", regexp.QuoteMeta(revisionPrefix), lengthHash)},
 			RequesterKey:  evergreen.RepotrackerVersionRequester,
 		})
 }
@@ -127,6 +137,23 @@ func ByProjectId(projectId string) db.Q {
 		})
 }
 
+// ByProjectIdForWaterfall finds the versions shown on a project's waterfall:
+// those the repotracker created for commits, and those created by the
+// project's version schedules, downstream triggers, and users starting
+// manual versions.
+func ByProjectIdForWaterfall(projectId string) db.Q {
+	return db.Query(
+		bson.M{
+			IdentifierKey: projectId,
+			RequesterKey: bson.M{"$in": []string{
+				evergreen.RepotrackerVersionRequester,
+				evergreen.ScheduledVersionRequester,
+				evergreen.TriggerRequester,
+				evergreen.ManualVersionRequester,
+			}},
+		})
+}
+
 // ByProjectId finds all versions within a project, ordered by most recently created to oldest.
 // The requester controls if it should search patch or non-patch versions.
 func ByMostRecentForRequester(projectId, requester string) db.Q {
@@ -51,6 +51,24 @@ type Version struct {
 	// AuthorID is an optional reference to the Evergreen user that authored
 	// this comment, if they can be identified
 	AuthorID string `bson:"author_id,omitempty" json:"author_id,omitempty"`
+
+	// TriggerID is the id of the upstream version, build, or task whose
+	// outcome created this version, TriggerType is which of these it is, and
+	// TriggerEvent is the id of the event that fired the trigger
+	TriggerID    string `bson:"trigger_id,omitempty" json:"trigger_id,omitempty"`
+	TriggerType  string `bson:"trigger_type,omitempty" json:"trigger_type,omitempty"`
+	TriggerEvent string `bson:"trigger_event,omitempty" json:"trigger_event,omitempty"`
+
+	// Parameters are the values a user supplied for the project's
+	// parameters when starting a manual version. They become expansions
+	// for all of the version's tasks.
+	Parameters []Parameter `bson:"parameters,omitempty" json:"parameters,omitempty"`
+}
+
+// Parameter is the value of a project parameter for a version.
+type Parameter struct {
+	Key   string `bson:"key" json:"key"`
+	Value string `bson:"value" json:"value"`
 }
 
 func (v *Version) LastSuccessful() (*Version, error) {
@@ -6,6 +6,7 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/agent"
 	"github.com/evergreen-ci/evergreen/command"
 	"github.com/evergreen-ci/evergreen/rest/client"
@@ -25,6 +26,7 @@ func Agent() cli.Command {
 		logPrefixFlagName        = "log_prefix"
 		statusPortFlagName       = "status_port"
 		cleanupFlagName          = "cleanup"
+		spotInterruptionFlagName = "spot_interruption_url"
 	)
 
 	return cli.Command{
@@ -36,8 +38,9 @@ func Agent() cli.Command {
 				Usage: "id of machine agent is running on",
 			},
 			cli.StringFlag{
-				Name:  hostSecretFlagName,
-				Usage: "secret for the current host",
+				Name:   hostSecretFlagName,
+				Usage:  "secret for the current host",
+				EnvVar: evergreen.HostSecretEnv,
 			},
 			cli.StringFlag{
 				Name:  apiServerFlagName,
@@ -61,6 +64,10 @@ func Agent() cli.Command {
 				Name:  cleanupFlagName,
 				Usage: "clean up working directory and processes (do not set for smoke tests)",
 			},
+			cli.StringFlag{
+				Name:  spotInterruptionFlagName,
+				Usage: "URL of the instance metadata's spot interruption notice, if the host is a spot instance",
+			},
 		},
 		Before: mergeBeforeFuncs(
 			func(c *cli.Context) error {
@@ -80,6 +87,8 @@ func Agent() cli.Command {
 				LogPrefix:        c.String(logPrefixFlagName),
 				WorkingDirectory: c.String(workingDirectoryFlagName),
 				Cleanup:          c.Bool(cleanupFlagName),
+
+				SpotInterruptionURL: c.String(spotInterruptionFlagName),
 			}
 
 			if err := os.MkdirAll(opts.WorkingDirectory, 0777); err != nil {
@@ -0,0 +1,211 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+const (
+	commitQueueItemFlagName     = "item"
+	commitQueuePRFlagName       = "pr"
+	commitQueuePositionFlagName = "position"
+)
+
+func CommitQueue() cli.Command {
+	return cli.Command{
+		Name:   "commit-queue",
+		Usage:  "interact with a project's commit queue",
+		Before: setPlainLogger,
+		Subcommands: []cli.Command{
+			commitQueueList(),
+			commitQueueEnqueue(),
+			commitQueueDelete(),
+			commitQueueMove(),
+		},
+	}
+}
+
+func commitQueueList() cli.Command {
+	return cli.Command{
+		Name:   "list",
+		Usage:  "list the items in a project's commit queue",
+		Flags:  addProjectFlag(),
+		Before: requireStringFlag(projectFlagName),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+			project := c.String(projectFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			queue, err := client.GetCommitQueue(ctx, project)
+			if err != nil {
+				return errors.Wrap(err, "problem fetching commit queue")
+			}
+
+			if len(queue.Queue) == 0 {
+				grip.Infof("the commit queue for project '%s' is empty", project)
+				return nil
+			}
+
+			return printCommitQueue(queue)
+		},
+	}
+}
+
+func commitQueueEnqueue() cli.Command {
+	return cli.Command{
+		Name:  "enqueue",
+		Usage: "add a pull request to the back of a project's commit queue",
+		Flags: addProjectFlag(
+			cli.IntFlag{
+				Name:  commitQueuePRFlagName,
+				Usage: "number of the pull request to merge",
+			}),
+		Before: mergeBeforeFuncs(
+			requireStringFlag(projectFlagName),
+			requireIntValueBetween(commitQueuePRFlagName, 1, math.MaxInt32)),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+			project := c.String(projectFlagName)
+			item := strconv.Itoa(c.Int(commitQueuePRFlagName))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			position, err := client.EnqueueItem(ctx, project, item)
+			if err != nil {
+				return errors.Wrapf(err, "problem enqueueing pull request %s", item)
+			}
+
+			grip.Infof("pull request %s is at position %d in the commit queue for project '%s'", item, position, project)
+			return nil
+		},
+	}
+}
+
+func commitQueueDelete() cli.Command {
+	return cli.Command{
+		Name:  "delete",
+		Usage: "remove an item from a project's commit queue",
+		Flags: addProjectFlag(
+			cli.StringFlag{
+				Name:  commitQueueItemFlagName,
+				Usage: "item to remove from the queue",
+			}),
+		Before: mergeBeforeFuncs(
+			requireStringFlag(projectFlagName),
+			requireStringFlag(commitQueueItemFlagName)),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+			project := c.String(projectFlagName)
+			item := c.String(commitQueueItemFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			if err = client.DeleteCommitQueueItem(ctx, project, item); err != nil {
+				return errors.Wrapf(err, "problem deleting item '%s'", item)
+			}
+
+			grip.Infof("removed item '%s' from the commit queue for project '%s'", item, project)
+			return nil
+		},
+	}
+}
+
+func commitQueueMove() cli.Command {
+	return cli.Command{
+		Name:  "move",
+		Usage: "move an item to a new position in a project's commit queue (project admins only)",
+		Flags: addProjectFlag(
+			cli.StringFlag{
+				Name:  commitQueueItemFlagName,
+				Usage: "item to move",
+			},
+			cli.IntFlag{
+				Name:  commitQueuePositionFlagName,
+				Usage: "zero-based position to move the item to",
+			}),
+		Before: mergeBeforeFuncs(
+			requireStringFlag(projectFlagName),
+			requireStringFlag(commitQueueItemFlagName)),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+			project := c.String(projectFlagName)
+			item := c.String(commitQueueItemFlagName)
+			position := c.Int(commitQueuePositionFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			if err = client.MoveCommitQueueItem(ctx, project, item, position); err != nil {
+				return errors.Wrapf(err, "problem moving item '%s'", item)
+			}
+
+			grip.Infof("moved item '%s' to position %d in the commit queue for project '%s'", item, position, project)
+			return nil
+		},
+	}
+}
+
+func printCommitQueue(queue *model.APICommitQueue) error {
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 1, '\t', 0)
+	fmt.Fprintln(w, "Position\tItem\tEnqueued By\tEnqueued At\tPatch\t")
+	for i, item := range queue.Queue {
+		patchID := model.FromAPIString(item.PatchId)
+		if patchID == "" {
+			patchID = "-"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t\n",
+			i,
+			model.FromAPIString(item.Issue),
+			model.FromAPIString(item.Enqueuer),
+			item.EnqueueTime.String(),
+			patchID)
+	}
+
+	return w.Flush()
+}
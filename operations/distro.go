@@ -0,0 +1,452 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"text/tabwriter"
+
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	distroIDFlagName           = "distro"
+	distroNewFlagName          = "new-distro"
+	distroRevisionFlagName     = "revision"
+	distroAgainstFlagName      = "against"
+	distroDecommissionFlagName = "decommission"
+	distroDryRunFlagName       = "dry-run"
+)
+
+func Distro() cli.Command {
+	return cli.Command{
+		Name:   "distro",
+		Usage:  "manage distros and their revision history",
+		Before: setPlainLogger,
+		Subcommands: []cli.Command{
+			distroGet(),
+			distroApply(),
+			distroCopy(),
+			distroDelete(),
+			distroHistory(),
+			distroDiff(),
+			distroRollback(),
+		},
+	}
+}
+
+func addDistroIDFlag(flags ...cli.Flag) []cli.Flag {
+	return append(flags, cli.StringFlag{
+		Name:  joinFlagNames(distroIDFlagName, "d"),
+		Usage: "the ID of the distro",
+	})
+}
+
+func addDistroChangeFlags(flags ...cli.Flag) []cli.Flag {
+	return append(flags,
+		cli.BoolFlag{
+			Name:  distroDecommissionFlagName,
+			Usage: "decommission the distro's running hosts, so that they are replaced by hosts with the new settings",
+		},
+		cli.BoolFlag{
+			Name:  distroDryRunFlagName,
+			Usage: "validate and print the changes and the hosts to decommission without saving the distro",
+		})
+}
+
+func distroGet() cli.Command {
+	return cli.Command{
+		Name:   "get",
+		Usage:  "print a distro as a definition file",
+		Flags:  addDistroIDFlag(),
+		Before: requireStringFlag(distroIDFlagName),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+			distroID := c.String(distroIDFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			d, err := client.GetDistroByID(ctx, distroID)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			// the image ID is derived from the provider settings
+			d.ImageID = nil
+
+			out, err := json.Marshal(d)
+			if err != nil {
+				return errors.Wrap(err, "problem marshalling distro")
+			}
+			def := map[string]interface{}{}
+			if err = json.Unmarshal(out, &def); err != nil {
+				return errors.Wrap(err, "problem unmarshalling distro")
+			}
+			out, err = yaml.Marshal(def)
+			if err != nil {
+				return errors.Wrap(err, "problem marshalling distro definition")
+			}
+			fmt.Print(string(out))
+
+			return nil
+		},
+	}
+}
+
+func distroApply() cli.Command {
+	return cli.Command{
+		Name:  "apply",
+		Usage: "create or replace the distros in definition files",
+		Flags: addDistroChangeFlags(cli.StringSliceFlag{
+			Name:  joinFlagNames(pathFlagName, "filename", "file", "f"),
+			Usage: "path to a file with a distro or a list of distros; may specify more than once",
+		}),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+			paths := c.StringSlice(pathFlagName)
+			decommission := c.Bool(distroDecommissionFlagName)
+			dryRun := c.Bool(distroDryRunFlagName)
+			if len(paths) == 0 {
+				return errors.New("must specify at least one distro definition file")
+			}
+
+			distros := []model.APIDistro{}
+			for _, path := range paths {
+				fileDistros, err := readDistroDefinitions(path)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+				distros = append(distros, fileDistros...)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			catcher := grip.NewBasicCatcher()
+			for _, d := range distros {
+				distroID := model.FromAPIString(d.Name)
+				change, err := client.PutDistro(ctx, distroID, d, decommission, dryRun)
+				if err != nil {
+					catcher.Add(err)
+					continue
+				}
+				printDistroChange(distroID, change)
+			}
+
+			return catcher.Resolve()
+		},
+	}
+}
+
+func distroCopy() cli.Command {
+	return cli.Command{
+		Name:  "copy",
+		Usage: "create a distro with the settings of an existing one",
+		Flags: addDistroIDFlag(cli.StringFlag{
+			Name:  distroNewFlagName,
+			Usage: "the ID of the new distro",
+		}),
+		Before: mergeBeforeFuncs(
+			requireStringFlag(distroIDFlagName),
+			requireStringFlag(distroNewFlagName)),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+			distroID := c.String(distroIDFlagName)
+			newDistroID := c.String(distroNewFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			if _, err = client.CopyDistro(ctx, distroID, newDistroID); err != nil {
+				return errors.WithStack(err)
+			}
+
+			grip.Infof("copied distro '%s' to '%s'", distroID, newDistroID)
+			return nil
+		},
+	}
+}
+
+func distroDelete() cli.Command {
+	return cli.Command{
+		Name:   "delete",
+		Usage:  "delete a distro",
+		Flags:  addDistroIDFlag(addYesFlag()...),
+		Before: requireStringFlag(distroIDFlagName),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+			distroID := c.String(distroIDFlagName)
+			skipConfirm := c.Bool(yesFlagName)
+
+			if !skipConfirm && !confirm(fmt.Sprintf("Delete distro '%s'? (y/N)", distroID), false) {
+				return nil
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			if err = client.DeleteDistro(ctx, distroID); err != nil {
+				return errors.WithStack(err)
+			}
+
+			grip.Infof("deleted distro '%s'", distroID)
+			return nil
+		},
+	}
+}
+
+func distroHistory() cli.Command {
+	return cli.Command{
+		Name:   "history",
+		Usage:  "list the revisions of a distro",
+		Flags:  addDistroIDFlag(),
+		Before: requireStringFlag(distroIDFlagName),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+			distroID := c.String(distroIDFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			revisions, err := client.GetDistroRevisions(ctx, distroID)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+			fmt.Fprintln(w, "Revision\tTime\tUser\tEvent")
+			for _, r := range revisions {
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", r.Revision, r.Timestamp.Format("2006-01-02 15:04:05"),
+					model.FromAPIString(r.User), model.FromAPIString(r.EventType))
+			}
+
+			return errors.WithStack(w.Flush())
+		},
+	}
+}
+
+func distroDiff() cli.Command {
+	return cli.Command{
+		Name:  "diff",
+		Usage: "print the changes to a distro at a revision",
+		Flags: addDistroIDFlag(
+			cli.IntFlag{
+				Name:  joinFlagNames(distroRevisionFlagName, "r"),
+				Usage: "the revision to compare",
+			},
+			cli.IntFlag{
+				Name:  distroAgainstFlagName,
+				Value: -1,
+				Usage: "the revision to compare against, which defaults to the one before the revision",
+			}),
+		Before: mergeBeforeFuncs(
+			requireStringFlag(distroIDFlagName),
+			requireIntValueBetween(distroRevisionFlagName, 1, math.MaxInt32)),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+			distroID := c.String(distroIDFlagName)
+			revision := c.Int(distroRevisionFlagName)
+			against := c.Int(distroAgainstFlagName)
+			if against < 0 {
+				against = revision - 1
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			changes, err := client.DiffDistroRevisions(ctx, distroID, revision, against)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if len(changes) == 0 {
+				grip.Infof("revisions %d and %d of distro '%s' are the same", against, revision, distroID)
+				return nil
+			}
+			printDistroFieldChanges(changes)
+
+			return nil
+		},
+	}
+}
+
+func distroRollback() cli.Command {
+	return cli.Command{
+		Name:  "rollback",
+		Usage: "restore a distro as it was at a revision",
+		Flags: addDistroChangeFlags(addDistroIDFlag(cli.IntFlag{
+			Name:  joinFlagNames(distroRevisionFlagName, "r"),
+			Usage: "the revision to restore",
+		})...),
+		Before: mergeBeforeFuncs(
+			requireStringFlag(distroIDFlagName),
+			requireIntValueBetween(distroRevisionFlagName, 1, math.MaxInt32)),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+			distroID := c.String(distroIDFlagName)
+			revision := c.Int(distroRevisionFlagName)
+			decommission := c.Bool(distroDecommissionFlagName)
+			dryRun := c.Bool(distroDryRunFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			change, err := client.RollbackDistro(ctx, distroID, revision, decommission, dryRun)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			printDistroChange(distroID, change)
+
+			return nil
+		},
+	}
+}
+
+// readDistroDefinitions reads a file with either one distro or a list of
+// distros. Distros use the field names of the REST API, and must have a name.
+func readDistroDefinitions(path string) ([]model.APIDistro, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem reading distro definition file '%s'", path)
+	}
+
+	var def interface{}
+	if err = yaml.Unmarshal(data, &def); err != nil {
+		return nil, errors.Wrapf(err, "problem parsing distro definition file '%s'", path)
+	}
+	defs := []interface{}{}
+	switch v := stringKeys(def).(type) {
+	case []interface{}:
+		defs = v
+	case map[string]interface{}:
+		defs = append(defs, v)
+	default:
+		return nil, errors.Errorf("distro definition file '%s' must have a distro or a list of distros", path)
+	}
+
+	distros := []model.APIDistro{}
+	for i, def := range defs {
+		out, err := json.Marshal(def)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem reading distro #%d in '%s'", i+1, path)
+		}
+		d := model.APIDistro{}
+		if err = json.Unmarshal(out, &d); err != nil {
+			return nil, errors.Wrapf(err, "problem reading distro #%d in '%s'", i+1, path)
+		}
+		if model.FromAPIString(d.Name) == "" {
+			return nil, errors.Errorf("distro #%d in '%s' has no name", i+1, path)
+		}
+		distros = append(distros, d)
+	}
+
+	return distros, nil
+}
+
+func printDistroChange(distroID string, change *model.APIDistroChange) {
+	verb := "updated"
+	if change.DryRun {
+		verb = "would update"
+	}
+	if len(change.Changes) == 0 {
+		grip.Infof("distro '%s' is unchanged", distroID)
+	} else {
+		grip.Infof("%s distro '%s':", verb, distroID)
+		printDistroFieldChanges(change.Changes)
+	}
+
+	if len(change.HostsToDecommission) == 0 {
+		return
+	}
+	verb = "decommissioned"
+	if change.DryRun {
+		verb = "would decommission"
+	}
+	grip.Infof("%s %d hosts:", verb, len(change.HostsToDecommission))
+	for _, h := range change.HostsToDecommission {
+		fmt.Printf("  %s\n", h)
+	}
+}
+
+func printDistroFieldChanges(changes []distro.FieldChange) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "  Field\tOld\tNew")
+	for _, c := range changes {
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", c.Field, formatDistroFieldValue(c.Old), formatDistroFieldValue(c.New))
+	}
+	grip.Warning(w.Flush())
+}
+
+func formatDistroFieldValue(v interface{}) string {
+	if v == nil {
+		return "-"
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(out)
+}
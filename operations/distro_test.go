@@ -0,0 +1,79 @@
+package operations
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadDistroDefinitions(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "distro.yml")
+
+	content := []byte(`
+name: ubuntu1604-build
+arch: linux_amd64
+provider: ec2-ondemand
+pool_size: 10
+settings:
+  ami: ami-1
+  mount_points:
+    - device_name: /dev/xvdb
+      size: 100
+expansions:
+  - key: decompress
+    value: tar xzvf
+`)
+	require.NoError(ioutil.WriteFile(path, content, 0644))
+
+	distros, err := readDistroDefinitions(path)
+	require.NoError(err)
+	require.Len(distros, 1)
+	d := distros[0]
+	assert.Equal("ubuntu1604-build", model.FromAPIString(d.Name))
+	assert.Equal(10, d.PoolSize)
+	assert.Equal("ami-1", d.ProviderSettings["ami"])
+	mountPoints, ok := d.ProviderSettings["mount_points"].([]interface{})
+	require.True(ok)
+	require.Len(mountPoints, 1)
+	assert.Equal("/dev/xvdb", mountPoints[0].(map[string]interface{})["device_name"])
+	require.Len(d.Expansions, 1)
+	assert.Equal("decompress", d.Expansions[0].Key)
+
+	// a file can define several distros
+	content = []byte(`
+- name: a
+  arch: linux_amd64
+- name: b
+  arch: windows_amd64
+`)
+	require.NoError(ioutil.WriteFile(path, content, 0644))
+	distros, err = readDistroDefinitions(path)
+	require.NoError(err)
+	require.Len(distros, 2)
+	assert.Equal("a", model.FromAPIString(distros[0].Name))
+	assert.Equal("windows_amd64", model.FromAPIString(distros[1].Arch))
+
+	// every distro must have a name
+	content = []byte(`
+- name: a
+- arch: linux_amd64
+`)
+	require.NoError(ioutil.WriteFile(path, content, 0644))
+	_, err = readDistroDefinitions(path)
+	assert.Error(err)
+
+	require.NoError(ioutil.WriteFile(path, []byte(`just a string`), 0644))
+	_, err = readDistroDefinitions(path)
+	assert.Error(err)
+}
@@ -0,0 +1,139 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+const (
+	flakyTaskFlagName = "task"
+	flakyTestFlagName = "test"
+)
+
+func FlakyTests() cli.Command {
+	return cli.Command{
+		Name:   "flaky-tests",
+		Usage:  "list and quarantine a project's flaky tests",
+		Before: setPlainLogger,
+		Subcommands: []cli.Command{
+			flakyTestsList(),
+			flakyTestsQuarantine(true),
+			flakyTestsQuarantine(false),
+		},
+	}
+}
+
+func flakyTestsList() cli.Command {
+	return cli.Command{
+		Name:   "list",
+		Usage:  "list the tests of a project that flip between passing and failing",
+		Flags:  addProjectFlag(),
+		Before: requireStringFlag(projectFlagName),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+			project := c.String(projectFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			tests, err := client.GetFlakyTests(ctx, project)
+			if err != nil {
+				return errors.Wrap(err, "problem fetching flaky tests")
+			}
+
+			if len(tests) == 0 {
+				grip.Infof("no flaky tests found for project '%s'", project)
+				return nil
+			}
+
+			return printFlakyTests(tests)
+		},
+	}
+}
+
+func flakyTestsQuarantine(quarantine bool) cli.Command {
+	name := "quarantine"
+	usage := "quarantine a test so that its failures do not fail its task"
+	if !quarantine {
+		name = "unquarantine"
+		usage = "release a test from quarantine"
+	}
+
+	return cli.Command{
+		Name:  name,
+		Usage: usage,
+		Flags: addProjectFlag(
+			cli.StringFlag{
+				Name:  joinFlagNames(flakyTaskFlagName, "t"),
+				Usage: "name of the task that runs the test",
+			},
+			cli.StringFlag{
+				Name:  flakyTestFlagName,
+				Usage: "name of the test file",
+			}),
+		Before: mergeBeforeFuncs(
+			requireStringFlag(projectFlagName),
+			requireStringFlag(flakyTaskFlagName),
+			requireStringFlag(flakyTestFlagName)),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+			project := c.String(projectFlagName)
+			taskName := c.String(flakyTaskFlagName)
+			testFile := c.String(flakyTestFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			if err = client.SetTestQuarantined(ctx, project, taskName, testFile, quarantine); err != nil {
+				return errors.Wrapf(err, "problem updating test '%s'", testFile)
+			}
+
+			grip.Infof("%sd test '%s' of task '%s' in project '%s'", name, testFile, taskName, project)
+			return nil
+		},
+	}
+}
+
+func printFlakyTests(tests []model.APIFlakyTest) error {
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 1, '\t', 0)
+	fmt.Fprintln(w, "Task\tTest\tScore\tFlips\tRuns\tQuarantined\t")
+	for _, t := range tests {
+		quarantined := "no"
+		if t.Quarantined {
+			quarantined = "by " + model.FromAPIString(t.QuarantinedBy)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%.2f\t%d\t%d\t%s\t\n",
+			model.FromAPIString(t.TaskName),
+			model.FromAPIString(t.TestFile),
+			t.Score,
+			t.NumFlips,
+			t.NumRuns,
+			quarantined)
+	}
+
+	return w.Flush()
+}
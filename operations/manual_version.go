@@ -0,0 +1,91 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+const (
+	manualVersionRevisionFlagName  = "revision"
+	manualVersionParameterFlagName = "param"
+	manualVersionAliasFlagName     = "alias"
+	manualVersionMessageFlagName   = "message"
+)
+
+func ManualVersion() cli.Command {
+	return cli.Command{
+		Name:  "manual-version",
+		Usage: "start a version of a tracked revision of a project with parameters",
+		Flags: addProjectFlag(addVariantsFlag(addTasksFlag(
+			cli.StringFlag{
+				Name:  joinFlagNames(manualVersionRevisionFlagName, "r"),
+				Usage: "the revision to start the version at (defaults to the most recent tracked revision)",
+			},
+			cli.StringSliceFlag{
+				Name:  joinFlagNames(manualVersionParameterFlagName, "P"),
+				Usage: "a project parameter in the form key=value; may specify more than once",
+			},
+			cli.StringFlag{
+				Name:  joinFlagNames(manualVersionAliasFlagName, "a"),
+				Usage: "a project alias selecting the variants and tasks to run",
+			},
+			cli.StringFlag{
+				Name:  joinFlagNames(manualVersionMessageFlagName, "m"),
+				Usage: "a description of the version",
+			},
+		)...)...),
+		Before: mergeBeforeFuncs(
+			setPlainLogger,
+			requireStringFlag(projectFlagName),
+		),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().String(confFlagName)
+			project := c.String(projectFlagName)
+
+			params := map[string]string{}
+			for _, p := range c.StringSlice(manualVersionParameterFlagName) {
+				parts := strings.SplitN(p, "=", 2)
+				if len(parts) != 2 || parts[0] == "" {
+					return errors.Errorf("parameter '%s' is not in the form key=value", p)
+				}
+				params[parts[0]] = parts[1]
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			v, err := client.CreateManualVersion(ctx, project, model.APIManualVersionRequest{
+				Revision:   c.String(manualVersionRevisionFlagName),
+				Parameters: params,
+				Variants:   c.StringSlice(variantsFlagName),
+				Tasks:      c.StringSlice(tasksFlagName),
+				Alias:      c.String(manualVersionAliasFlagName),
+				Message:    c.String(manualVersionMessageFlagName),
+			})
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			id := model.FromAPIString(v.Id)
+			fmt.Printf("created version '%s' of revision '%s'\n", id, model.FromAPIString(v.Revision))
+			for _, param := range v.Parameters {
+				fmt.Printf("\t%s=%s\n", model.FromAPIString(param.Key), model.FromAPIString(param.Value))
+			}
+			fmt.Printf("%s/version/%s\n", conf.UIServerHost, id)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,535 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	projectNewFlagName     = "new-project"
+	projectKeyFlagName     = "key"
+	projectValueFlagName   = "value"
+	projectPrivateFlagName = "private"
+	projectPruneFlagName   = "prune"
+	projectUserFlagName    = "user"
+)
+
+// projectDefinition is the file format of the project command. Settings use
+// the names of the REST API's project fields, and settings missing from a
+// definition are left unchanged when it is applied.
+type projectDefinition struct {
+	Settings    map[string]interface{} `yaml:"settings,omitempty"`
+	Vars        map[string]string      `yaml:"vars,omitempty"`
+	PrivateVars []string               `yaml:"private_vars,omitempty"`
+	Aliases     []projectAlias         `yaml:"aliases,omitempty"`
+}
+
+type projectAlias struct {
+	Alias   string   `yaml:"alias"`
+	Variant string   `yaml:"variant,omitempty"`
+	Task    string   `yaml:"task,omitempty"`
+	Tags    []string `yaml:"tags,omitempty"`
+}
+
+func Project() cli.Command {
+	return cli.Command{
+		Name:   "project",
+		Usage:  "manage the settings, variables, aliases, and admins of projects",
+		Before: setPlainLogger,
+		Subcommands: []cli.Command{
+			projectGet(),
+			projectApply(),
+			projectCopy(),
+			projectDelete(),
+			projectVars(),
+			projectAdmins(),
+		},
+	}
+}
+
+func projectGet() cli.Command {
+	return cli.Command{
+		Name:   "get",
+		Usage:  "print a project's settings, variables, and aliases as a definition file",
+		Flags:  addProjectFlag(),
+		Before: requireStringFlag(projectFlagName),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+			projectID := c.String(projectFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			def, err := getProjectDefinition(ctx, client, projectID)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			out, err := yaml.Marshal(def)
+			if err != nil {
+				return errors.Wrap(err, "problem marshalling project definition")
+			}
+			fmt.Print(string(out))
+
+			return nil
+		},
+	}
+}
+
+func projectApply() cli.Command {
+	return cli.Command{
+		Name:  "apply",
+		Usage: "create or update a project from a definition file",
+		Flags: addPathFlag(addProjectFlag(
+			cli.BoolFlag{
+				Name:  projectPruneFlagName,
+				Usage: "delete variables that are not in the definition file",
+			})...),
+		Before: mergeBeforeFuncs(
+			requireStringFlag(projectFlagName),
+			requireStringFlag(pathFlagName)),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+			projectID := c.String(projectFlagName)
+			path := c.String(pathFlagName)
+			prune := c.Bool(projectPruneFlagName)
+
+			def, err := readProjectDefinition(path)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			return errors.WithStack(applyProjectDefinition(ctx, client, projectID, def, prune))
+		},
+	}
+}
+
+func projectCopy() cli.Command {
+	return cli.Command{
+		Name:  "copy",
+		Usage: "copy a project's settings, variables, and aliases to a new, disabled project",
+		Flags: addProjectFlag(cli.StringFlag{
+			Name:  projectNewFlagName,
+			Usage: "identifier of the new project",
+		}),
+		Before: mergeBeforeFuncs(
+			requireStringFlag(projectFlagName),
+			requireStringFlag(projectNewFlagName)),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+			projectID := c.String(projectFlagName)
+			newProjectID := c.String(projectNewFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			if _, err = client.CopyProject(ctx, projectID, newProjectID); err != nil {
+				return errors.WithStack(err)
+			}
+
+			grip.Infof("copied project '%s' to '%s'", projectID, newProjectID)
+			return nil
+		},
+	}
+}
+
+func projectDelete() cli.Command {
+	return cli.Command{
+		Name:   "delete",
+		Usage:  "delete a project and its variables and aliases",
+		Flags:  addProjectFlag(addYesFlag()...),
+		Before: requireStringFlag(projectFlagName),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+			projectID := c.String(projectFlagName)
+			skipConfirm := c.Bool(yesFlagName)
+
+			if !skipConfirm && !confirm(fmt.Sprintf("Delete project '%s'? (y/N)", projectID), false) {
+				return nil
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			if err = client.DeleteProject(ctx, projectID); err != nil {
+				return errors.WithStack(err)
+			}
+
+			grip.Infof("deleted project '%s'", projectID)
+			return nil
+		},
+	}
+}
+
+func projectVars() cli.Command {
+	return cli.Command{
+		Name:  "vars",
+		Usage: "set and delete a project's variables",
+		Subcommands: []cli.Command{
+			{
+				Name:  "set",
+				Usage: "set a variable",
+				Flags: addProjectFlag(
+					cli.StringFlag{
+						Name:  joinFlagNames(projectKeyFlagName, "k"),
+						Usage: "name of the variable",
+					},
+					cli.StringFlag{
+						Name:  joinFlagNames(projectValueFlagName, "v"),
+						Usage: "value of the variable",
+					},
+					cli.BoolFlag{
+						Name:  projectPrivateFlagName,
+						Usage: "make the variable private, so that its value cannot be read back",
+					}),
+				Before: mergeBeforeFuncs(
+					requireStringFlag(projectFlagName),
+					requireStringFlag(projectKeyFlagName)),
+				Action: func(c *cli.Context) error {
+					key := c.String(projectKeyFlagName)
+					vars := model.APIProjectVars{
+						Vars:        map[string]string{key: c.String(projectValueFlagName)},
+						PrivateVars: map[string]bool{key: c.Bool(projectPrivateFlagName)},
+					}
+					return updateProjectVars(c, vars)
+				},
+			},
+			{
+				Name:  "delete",
+				Usage: "delete a variable",
+				Flags: addProjectFlag(cli.StringFlag{
+					Name:  joinFlagNames(projectKeyFlagName, "k"),
+					Usage: "name of the variable",
+				}),
+				Before: mergeBeforeFuncs(
+					requireStringFlag(projectFlagName),
+					requireStringFlag(projectKeyFlagName)),
+				Action: func(c *cli.Context) error {
+					vars := model.APIProjectVars{
+						VarsToDelete: []string{c.String(projectKeyFlagName)},
+					}
+					return updateProjectVars(c, vars)
+				},
+			},
+		},
+	}
+}
+
+func updateProjectVars(c *cli.Context, vars model.APIProjectVars) error {
+	confPath := c.Parent().Parent().Parent().String(confFlagName)
+	projectID := c.String(projectFlagName)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conf, err := NewClientSettings(confPath)
+	if err != nil {
+		return errors.Wrap(err, "problem loading configuration")
+	}
+
+	client := conf.GetRestCommunicator(ctx)
+	defer client.Close()
+
+	if _, err = client.UpdateProjectVars(ctx, projectID, vars); err != nil {
+		return errors.WithStack(err)
+	}
+
+	grip.Infof("updated variables of project '%s'", projectID)
+	return nil
+}
+
+func projectAdmins() cli.Command {
+	return cli.Command{
+		Name:  "admins",
+		Usage: "add and remove a project's admins",
+		Subcommands: []cli.Command{
+			projectAdminsModify(true),
+			projectAdminsModify(false),
+		},
+	}
+}
+
+func projectAdminsModify(add bool) cli.Command {
+	name := "add"
+	usage := "make a user an admin of the project"
+	if !add {
+		name = "remove"
+		usage = "remove a user from the project's admins"
+	}
+
+	return cli.Command{
+		Name:  name,
+		Usage: usage,
+		Flags: addProjectFlag(cli.StringFlag{
+			Name:  joinFlagNames(projectUserFlagName, "u"),
+			Usage: "the user's ID",
+		}),
+		Before: mergeBeforeFuncs(
+			requireStringFlag(projectFlagName),
+			requireStringFlag(projectUserFlagName)),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().Parent().String(confFlagName)
+			projectID := c.String(projectFlagName)
+			userID := c.String(projectUserFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			project, err := client.GetProject(ctx, projectID)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			admins := []string{}
+			for _, admin := range project.Admins {
+				if model.FromAPIString(admin) != userID {
+					admins = append(admins, model.FromAPIString(admin))
+				}
+			}
+			if add {
+				admins = append(admins, userID)
+			}
+
+			if _, err = client.UpdateProject(ctx, projectID, map[string]interface{}{"admins": admins}); err != nil {
+				return errors.WithStack(err)
+			}
+
+			grip.Infof("admins of project '%s' are now %v", projectID, admins)
+			return nil
+		},
+	}
+}
+
+func getProjectDefinition(ctx context.Context, client client.Communicator, projectID string) (*projectDefinition, error) {
+	project, err := client.GetProject(ctx, projectID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	vars, err := client.GetProjectVars(ctx, projectID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	aliases, err := client.ListAliases(ctx, projectID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem fetching aliases of project '%s'", projectID)
+	}
+
+	def := &projectDefinition{
+		Vars: vars.Vars,
+	}
+	for k, private := range vars.PrivateVars {
+		if private {
+			def.PrivateVars = append(def.PrivateVars, k)
+		}
+	}
+	sort.Strings(def.PrivateVars)
+	for _, a := range aliases {
+		def.Aliases = append(def.Aliases, projectAlias{
+			Alias:   a.Alias,
+			Variant: a.Variant,
+			Task:    a.Task,
+			Tags:    a.Tags,
+		})
+	}
+
+	out, err := json.Marshal(project)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem marshalling project settings")
+	}
+	if err = json.Unmarshal(out, &def.Settings); err != nil {
+		return nil, errors.Wrap(err, "problem unmarshalling project settings")
+	}
+	delete(def.Settings, "identifier")
+
+	return def, nil
+}
+
+func readProjectDefinition(path string) (*projectDefinition, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem reading project definition file '%s'", path)
+	}
+
+	def := &projectDefinition{}
+	if err = yaml.Unmarshal(data, def); err != nil {
+		return nil, errors.Wrapf(err, "problem parsing project definition file '%s'", path)
+	}
+	if def.Settings != nil {
+		def.Settings = stringKeys(def.Settings).(map[string]interface{})
+	}
+
+	for _, k := range def.PrivateVars {
+		if _, ok := def.Vars[k]; !ok {
+			return nil, errors.Errorf("private variable '%s' is not one of the variables", k)
+		}
+	}
+
+	return def, nil
+}
+
+// stringKeys converts the maps that the YAML parser produces, which have
+// interface keys, to maps with string keys, which can be sent as JSON.
+func stringKeys(in interface{}) interface{} {
+	switch v := in.(type) {
+	case map[interface{}]interface{}:
+		out := map[string]interface{}{}
+		for k, val := range v {
+			out[fmt.Sprintf("%v", k)] = stringKeys(val)
+		}
+		return out
+	case map[string]interface{}:
+		out := map[string]interface{}{}
+		for k, val := range v {
+			out[k] = stringKeys(val)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, val := range v {
+			out[i] = stringKeys(val)
+		}
+		return out
+	default:
+		return in
+	}
+}
+
+// newProjectFromSettings returns the project to create from the settings of
+// a project definition. Since the whole project is sent on creation, settings
+// the definition omits get the same defaults the server gives them.
+func newProjectFromSettings(settings map[string]interface{}) (model.APIProject, error) {
+	project := model.APIProject{Tracked: true}
+	out, err := json.Marshal(settings)
+	if err != nil {
+		return project, errors.Wrap(err, "problem marshalling project settings")
+	}
+	if err = json.Unmarshal(out, &project); err != nil {
+		return project, errors.Wrap(err, "problem reading project settings")
+	}
+	return project, nil
+}
+
+// applyProjectDefinition creates the project if it does not exist and
+// updates it otherwise. Variables and aliases are only changed if the
+// definition has them.
+func applyProjectDefinition(ctx context.Context, client client.Communicator, projectID string, def *projectDefinition, prune bool) error {
+	_, err := client.GetProject(ctx, projectID)
+	if err != nil {
+		if errResp, ok := errors.Cause(err).(gimlet.ErrorResponse); !ok || errResp.StatusCode != http.StatusNotFound {
+			return errors.WithStack(err)
+		}
+
+		project, err := newProjectFromSettings(def.Settings)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if _, err = client.CreateProject(ctx, projectID, project); err != nil {
+			return errors.WithStack(err)
+		}
+		grip.Infof("created project '%s'", projectID)
+	} else if len(def.Settings) > 0 {
+		if _, err = client.UpdateProject(ctx, projectID, def.Settings); err != nil {
+			return errors.WithStack(err)
+		}
+		grip.Infof("updated settings of project '%s'", projectID)
+	}
+
+	if def.Vars != nil || prune {
+		vars := model.APIProjectVars{
+			Vars:        def.Vars,
+			PrivateVars: map[string]bool{},
+		}
+		for k := range def.Vars {
+			vars.PrivateVars[k] = util.StringSliceContains(def.PrivateVars, k)
+		}
+		if prune {
+			existing, err := client.GetProjectVars(ctx, projectID)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			for k := range existing.Vars {
+				if _, ok := def.Vars[k]; !ok {
+					vars.VarsToDelete = append(vars.VarsToDelete, k)
+				}
+			}
+		}
+		if _, err = client.UpdateProjectVars(ctx, projectID, vars); err != nil {
+			return errors.WithStack(err)
+		}
+		grip.Infof("updated variables of project '%s'", projectID)
+	}
+
+	if def.Aliases != nil {
+		aliases := []model.APIAlias{}
+		for _, a := range def.Aliases {
+			aliases = append(aliases, model.APIAlias{
+				Alias:   model.ToAPIString(a.Alias),
+				Variant: model.ToAPIString(a.Variant),
+				Task:    model.ToAPIString(a.Task),
+				Tags:    a.Tags,
+			})
+		}
+		if err = client.SetProjectAliases(ctx, projectID, aliases); err != nil {
+			return errors.WithStack(err)
+		}
+		grip.Infof("updated aliases of project '%s'", projectID)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package operations
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/rest/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadProjectDefinition(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "project.yml")
+
+	content := []byte(`
+settings:
+  display_name: Evergreen
+  commit_queue:
+    enabled: true
+    merge_method: squash
+  admins:
+    - admin
+vars:
+  a: "1"
+  secret: hunter2
+private_vars:
+  - secret
+aliases:
+  - alias: __github
+    variant: .*
+    task: .*
+`)
+	require.NoError(ioutil.WriteFile(path, content, 0644))
+
+	def, err := readProjectDefinition(path)
+	require.NoError(err)
+	assert.Equal("Evergreen", def.Settings["display_name"])
+	assert.Equal(map[string]string{"a": "1", "secret": "hunter2"}, def.Vars)
+	assert.Equal([]string{"secret"}, def.PrivateVars)
+	require.Len(def.Aliases, 1)
+	assert.Equal("__github", def.Aliases[0].Alias)
+
+	// nested settings must be sendable as JSON
+	out, err := json.Marshal(def.Settings)
+	require.NoError(err)
+	assert.Contains(string(out), `"merge_method":"squash"`)
+
+	// private variables must be defined
+	content = []byte(`
+vars:
+  a: "1"
+private_vars:
+  - secret
+`)
+	require.NoError(ioutil.WriteFile(path, content, 0644))
+	_, err = readProjectDefinition(path)
+	assert.Error(err)
+}
+
+func TestNewProjectFromSettings(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	project, err := newProjectFromSettings(map[string]interface{}{"display_name": "Evergreen"})
+	require.NoError(err)
+	assert.Equal("Evergreen", model.FromAPIString(project.DisplayName))
+	assert.True(project.Tracked, "projects should be tracked unless the definition says otherwise")
+
+	project, err = newProjectFromSettings(map[string]interface{}{"tracked": false})
+	require.NoError(err)
+	assert.False(project.Tracked)
+
+	project, err = newProjectFromSettings(nil)
+	require.NoError(err)
+	assert.True(project.Tracked)
+}
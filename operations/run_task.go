@@ -0,0 +1,109 @@
+package operations
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/agent"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+const (
+	runTaskVariantFlagName    = "variant"
+	runTaskTaskFlagName       = "task"
+	runTaskDirFlagName        = "dir"
+	runTaskOutputFlagName     = "output"
+	runTaskExpansionsFlagName = "expansion"
+)
+
+func RunTask() cli.Command {
+	return cli.Command{
+		Name:  "run-task",
+		Usage: "run a task from a project configuration file on this machine",
+		Flags: addPathFlag(
+			cli.StringFlag{
+				Name:  joinFlagNames(projectFlagName, "p"),
+				Usage: "the project identifier to use in expansions",
+				Value: "local",
+			},
+			cli.StringFlag{
+				Name:  joinFlagNames(runTaskVariantFlagName, "v"),
+				Usage: "the build variant to run the task on",
+			},
+			cli.StringFlag{
+				Name:  joinFlagNames(runTaskTaskFlagName, "t"),
+				Usage: "the name of the task to run",
+			},
+			cli.StringFlag{
+				Name:  joinFlagNames(runTaskDirFlagName, "d"),
+				Usage: "the working directory for the task's commands",
+				Value: ".",
+			},
+			cli.StringFlag{
+				Name:  joinFlagNames(runTaskOutputFlagName, "o"),
+				Usage: "the directory for artifacts, test results, and files put in s3",
+				Value: "evergreen-local",
+			},
+			cli.StringSliceFlag{
+				Name:  joinFlagNames(runTaskExpansionsFlagName, "e"),
+				Usage: "an expansion in the form key=value; may specify more than once",
+			},
+		),
+		Before: mergeBeforeFuncs(
+			setPlainLogger,
+			requirePathFlag,
+			requireStringFlag(runTaskVariantFlagName),
+			requireStringFlag(runTaskTaskFlagName),
+		),
+		Action: func(c *cli.Context) error {
+			yml, err := ioutil.ReadFile(c.String(pathFlagName))
+			if err != nil {
+				return errors.Wrap(err, "problem reading project configuration")
+			}
+
+			expansions := map[string]string{}
+			for _, e := range c.StringSlice(runTaskExpansionsFlagName) {
+				parts := strings.SplitN(e, "=", 2)
+				if len(parts) != 2 || parts[0] == "" {
+					return errors.Errorf("expansion '%s' is not in the form key=value", e)
+				}
+				expansions[parts[0]] = parts[1]
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				sigChan := make(chan os.Signal, 1)
+				signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+				select {
+				case <-sigChan:
+					cancel()
+				case <-ctx.Done():
+				}
+			}()
+
+			status, err := agent.RunLocalTask(ctx, agent.LocalOptions{
+				Project:           yml,
+				ProjectIdentifier: c.String(projectFlagName),
+				Variant:           c.String(runTaskVariantFlagName),
+				Task:              c.String(runTaskTaskFlagName),
+				WorkingDirectory:  c.String(runTaskDirFlagName),
+				OutputDirectory:   c.String(runTaskOutputFlagName),
+				Expansions:        expansions,
+			})
+			if err != nil {
+				return errors.Wrap(err, "problem running task")
+			}
+			if status != evergreen.TaskSucceeded {
+				return errors.Errorf("task finished with status '%s'", status)
+			}
+			return nil
+		},
+	}
+}
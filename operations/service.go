@@ -22,6 +22,7 @@ func Service() cli.Command {
 		Subcommands: []cli.Command{
 			deploy(),
 			startWebService(),
+			schedulerSimulator(),
 		},
 	}
 }
@@ -80,6 +81,7 @@ func startSystemCronJobs(ctx context.Context, env evergreen.Environment) {
 		units.PopulateHostTerminationJobs(env),
 		units.PopulateHostMonitoring(env),
 		units.PopulateTaskMonitoring(),
+		units.PopulateTaskAutoRetryJobs(),
 		units.PopulateEventAlertProcessing(1),
 		units.PopulateBackgroundStatsJobs(env, 0),
 		units.PopulateLastContainerFinishTimeJobs(),
@@ -87,7 +89,9 @@ func startSystemCronJobs(ctx context.Context, env evergreen.Environment) {
 		units.PopulatePeriodicNotificationJobs(1),
 		units.PopulateContainerStateJobs(env),
 		units.PopulateOldestImageRemovalJobs(),
-		units.PopulateSchedulerJobs(env)))
+		units.PopulateSchedulerJobs(env),
+		units.PopulateCommitQueueJobs(env),
+		units.PopulateScheduledVersionJobs(10*time.Minute)))
 
 	amboy.IntervalQueueOperation(ctx, env.RemoteQueue(), 15*time.Second, time.Now(), opts, amboy.GroupQueueOperationFactory(
 		units.PopulateHostSetupJobs(env, 0),
@@ -99,7 +103,8 @@ func startSystemCronJobs(ctx context.Context, env evergreen.Environment) {
 
 	amboy.IntervalQueueOperation(ctx, env.RemoteQueue(), 15*time.Minute, time.Now(), opts, amboy.GroupQueueOperationFactory(
 		units.PopulateCatchupJobs(30),
-		units.PopulateHostAlertJobs(20)))
+		units.PopulateHostAlertJobs(20),
+		units.PopulateFlakyTestDetectionJobs(30)))
 
 	////////////////////////////////////////////////////////////////////////
 	//
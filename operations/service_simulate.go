@@ -0,0 +1,253 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/scheduler"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/level"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+const (
+	simulateDistroFlagName           = "distro"
+	simulateStartFlagName            = "start"
+	simulateEndFlagName              = "end"
+	simulateAllocatorFlagName        = "allocator"
+	simulatePrioritizerFlagName      = "prioritizer"
+	simulateFreeHostFractionFlagName = "free-host-fraction"
+	simulateIntervalFlagName         = "interval"
+	simulateHostStartupFlagName      = "host-startup"
+	simulateIdleTimeoutFlagName      = "idle-timeout"
+	simulateJSONFlagName             = "json"
+)
+
+func schedulerSimulator() cli.Command {
+	return cli.Command{
+		Name:  "scheduler-simulator",
+		Usage: "replay a distro's recorded tasks to compare scheduler configurations",
+		Subcommands: []cli.Command{
+			exportSimulationInput(),
+			runSchedulerSimulation(),
+		},
+	}
+}
+
+func simulationWindowFlags(flags ...cli.Flag) []cli.Flag {
+	return append(flags,
+		cli.StringFlag{
+			Name:  joinFlagNames(simulateDistroFlagName, "d"),
+			Usage: "the distro whose tasks to record",
+		},
+		cli.StringFlag{
+			Name:  simulateStartFlagName,
+			Usage: "the start of the window of task activations to record (RFC3339)",
+		},
+		cli.StringFlag{
+			Name:  simulateEndFlagName,
+			Usage: "the end of the window of task activations to record (RFC3339), defaults to now",
+		})
+}
+
+// loadSimulationInputFromDB records the window of tasks described by the
+// command's flags from the database.
+func loadSimulationInputFromDB(ctx context.Context, c *cli.Context) (*scheduler.SimulationInput, error) {
+	distroID := c.String(simulateDistroFlagName)
+	if distroID == "" {
+		return nil, errors.Errorf("flag '--%s' was not specified", simulateDistroFlagName)
+	}
+	start, err := time.Parse(time.RFC3339, c.String(simulateStartFlagName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem parsing '--%s'", simulateStartFlagName)
+	}
+	end := time.Now()
+	if endString := c.String(simulateEndFlagName); endString != "" {
+		end, err = time.Parse(time.RFC3339, endString)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem parsing '--%s'", simulateEndFlagName)
+		}
+	}
+	if !start.Before(end) {
+		return nil, errors.New("the start of the window must be before its end")
+	}
+
+	env := evergreen.GetEnvironment()
+	if err = env.Configure(ctx, c.String(confFlagName), parseDB(c)); err != nil {
+		return nil, errors.Wrap(err, "problem configuring application environment")
+	}
+
+	input, err := scheduler.LoadSimulationInput(distroID, start, end)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem recording tasks")
+	}
+	return input, nil
+}
+
+func exportSimulationInput() cli.Command {
+	return cli.Command{
+		Name:   "export",
+		Usage:  "record a window of a distro's tasks to a file",
+		Flags:  mergeFlagSlices(serviceConfigFlags(), addDbSettingsFlags(), simulationWindowFlags(addOutputPath()...)),
+		Before: mergeBeforeFuncs(requireStringFlag(pathFlagName), requireStringFlag(simulateStartFlagName)),
+		Action: func(c *cli.Context) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			input, err := loadSimulationInputFromDB(ctx, c)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			if err = util.WriteJSONInto(c.String(pathFlagName), input); err != nil {
+				return errors.Wrap(err, "problem writing recorded tasks")
+			}
+
+			grip.Infof("recorded %d tasks of distro '%s' to '%s'", len(input.Tasks), input.Distro.Id, c.String(pathFlagName))
+			return nil
+		},
+	}
+}
+
+func runSchedulerSimulation() cli.Command {
+	allocators := []string{"deficit", "duration", "utilization", "predictive"}
+	return cli.Command{
+		Name:  "run",
+		Usage: "replay recorded tasks through one or more host allocators",
+		Flags: mergeFlagSlices(serviceConfigFlags(), addDbSettingsFlags(), simulationWindowFlags(
+			cli.StringFlag{
+				Name:  joinFlagNames(pathFlagName, "filename", "file", "f"),
+				Usage: "path to tasks recorded with export; if unset, tasks are recorded from the database",
+			},
+			cli.StringSliceFlag{
+				Name:  simulateAllocatorFlagName,
+				Usage: fmt.Sprintf("a host allocator to simulate, may be specified more than once %s", allocators),
+			},
+			cli.StringFlag{
+				Name:  simulatePrioritizerFlagName,
+				Usage: "the task prioritizer to simulate (comparator or fairshare)",
+				Value: "comparator",
+			},
+			cli.Float64Flag{
+				Name:  simulateFreeHostFractionFlagName,
+				Usage: "the fraction of soon to be free hosts that the utilization allocator counts as free",
+				Value: 0.5,
+			},
+			cli.DurationFlag{
+				Name:  simulateIntervalFlagName,
+				Usage: "how often the scheduler runs",
+				Value: time.Minute,
+			},
+			cli.DurationFlag{
+				Name:  simulateHostStartupFlagName,
+				Usage: "how long a new host takes to become ready",
+				Value: 5 * time.Minute,
+			},
+			cli.DurationFlag{
+				Name:  simulateIdleTimeoutFlagName,
+				Usage: "how long a host may be idle before it is terminated",
+				Value: 4 * time.Minute,
+			},
+			cli.BoolFlag{
+				Name:  simulateJSONFlagName,
+				Usage: "print the results as JSON",
+			})),
+		Before: mergeBeforeFuncs(
+			requireStringSliceValueChoices(simulateAllocatorFlagName, allocators),
+			requireStringValueChoices(simulatePrioritizerFlagName, []string{"comparator", "fairshare"})),
+		Action: func(c *cli.Context) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var input *scheduler.SimulationInput
+			var err error
+			if path := c.String(pathFlagName); path != "" {
+				input = &scheduler.SimulationInput{}
+				if err = readSimulationInput(path, input); err != nil {
+					return errors.WithStack(err)
+				}
+			} else if input, err = loadSimulationInputFromDB(ctx, c); err != nil {
+				return errors.WithStack(err)
+			}
+
+			// the scheduler logs every run, which would bury the results
+			sender := grip.GetSender()
+			info := sender.Level()
+			info.Threshold = level.Warning
+			grip.Warning(sender.SetLevel(info))
+
+			names := c.StringSlice(simulateAllocatorFlagName)
+			if len(names) == 0 {
+				names = allocators
+			}
+
+			results := make([]scheduler.SimulationResult, 0, len(names))
+			for _, name := range names {
+				res, err := scheduler.SimulateScheduler(ctx, input, scheduler.SimulationOptions{
+					HostAllocator:    name,
+					TaskPrioritizer:  c.String(simulatePrioritizerFlagName),
+					FreeHostFraction: c.Float64(simulateFreeHostFractionFlagName),
+					Interval:         c.Duration(simulateIntervalFlagName),
+					HostStartup:      c.Duration(simulateHostStartupFlagName),
+					HostIdleTimeout:  c.Duration(simulateIdleTimeoutFlagName),
+				})
+				if err != nil {
+					return errors.Wrapf(err, "problem simulating host allocator '%s'", name)
+				}
+				results = append(results, *res)
+			}
+
+			if c.Bool(simulateJSONFlagName) {
+				out, err := json.MarshalIndent(results, "", "  ")
+				if err != nil {
+					return errors.Wrap(err, "problem marshalling results")
+				}
+				fmt.Println(string(out))
+				return nil
+			}
+
+			return printSimulationResults(results)
+		},
+	}
+}
+
+func readSimulationInput(path string, input *scheduler.SimulationInput) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, "problem opening '%s'", path)
+	}
+	return errors.Wrapf(util.ReadJSONInto(f, input), "problem reading recorded tasks from '%s'", path)
+}
+
+func printSimulationResults(results []scheduler.SimulationResult) error {
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 1, '\t', 0)
+	fmt.Fprintln(w, "Distro\tAllocator\tPrioritizer\tTasks\tUnfinished\tMakespan\tMean Wait\tP50\tP90\tP99\tMax Wait\tHosts Spawned\tPeak Hosts\tHost Hours\tIdle Hours\t")
+	for _, res := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%d\t%d\t%.2f\t%.2f\t\n",
+			res.Distro,
+			res.HostAllocator,
+			res.TaskPrioritizer,
+			res.Tasks,
+			res.UnfinishedTasks,
+			res.Makespan,
+			res.MeanWait.Round(time.Second),
+			res.WaitP50,
+			res.WaitP90,
+			res.WaitP99,
+			res.MaxWait,
+			res.HostsSpawned,
+			res.PeakHosts,
+			res.HostHours,
+			res.IdleHostHours)
+	}
+
+	return w.Flush()
+}
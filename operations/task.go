@@ -0,0 +1,167 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+const (
+	taskIDFlagName        = "task"
+	taskLogTypeFlagName   = "type"
+	taskExecutionFlagName = "execution"
+	taskFollowFlagName    = "follow"
+
+	taskGraphVersionFlagName = "version"
+	taskGraphFormatFlagName  = "format"
+)
+
+func Task() cli.Command {
+	return cli.Command{
+		Name:   "task",
+		Usage:  "inspect tasks",
+		Before: setPlainLogger,
+		Subcommands: []cli.Command{
+			taskLogs(),
+			taskGraph(),
+		},
+	}
+}
+
+func taskLogs() cli.Command {
+	return cli.Command{
+		Name:  "logs",
+		Usage: "print a task's log, optionally following it until the task finishes",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  joinFlagNames(taskIDFlagName, "t"),
+				Usage: "the id of the task",
+			},
+			cli.StringFlag{
+				Name:  taskLogTypeFlagName,
+				Usage: "the log to print: task, agent, system, or all",
+				Value: "task",
+			},
+			cli.IntFlag{
+				Name:  taskExecutionFlagName,
+				Usage: "the execution of the task (defaults to the latest)",
+				Value: -1,
+			},
+			cli.BoolFlag{
+				Name:  joinFlagNames(taskFollowFlagName, "f"),
+				Usage: "keep printing messages as they are logged until the task finishes",
+			},
+		},
+		Before: requireStringFlag(taskIDFlagName),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+			taskID := c.String(taskIDFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			err = client.StreamTaskLogs(ctx, taskID, c.String(taskLogTypeFlagName), c.Int(taskExecutionFlagName),
+				c.Bool(taskFollowFlagName), printTaskLogMessage)
+			return errors.Wrapf(err, "problem fetching log for task '%s'", taskID)
+		},
+	}
+}
+
+func printTaskLogMessage(msg model.APILogMessage) error {
+	_, err := fmt.Printf("[%s] %s\n", msg.Timestamp.String(), model.FromAPIString(msg.Message))
+	return errors.WithStack(err)
+}
+
+func taskGraph() cli.Command {
+	return cli.Command{
+		Name:  "graph",
+		Usage: "print the task dependency graph and critical path of a version",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  joinFlagNames(taskGraphVersionFlagName, "v"),
+				Usage: "the id of the version",
+			},
+			cli.StringFlag{
+				Name:  taskGraphFormatFlagName,
+				Usage: "the output format: text (the critical path), json, or dot",
+				Value: "text",
+			},
+		},
+		Before: requireStringFlag(taskGraphVersionFlagName),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().Parent().String(confFlagName)
+			versionID := c.String(taskGraphVersionFlagName)
+			format := c.String(taskGraphFormatFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			switch format {
+			case "dot":
+				dot, err := client.GetVersionTaskGraphDOT(ctx, versionID)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+				fmt.Print(dot)
+				return nil
+			case "json", "text":
+				graph, err := client.GetVersionTaskGraph(ctx, versionID)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+				if format == "text" {
+					printCriticalPath(graph)
+					return nil
+				}
+				out, err := json.MarshalIndent(graph, "", "  ")
+				if err != nil {
+					return errors.Wrap(err, "problem rendering task graph")
+				}
+				fmt.Println(string(out))
+				return nil
+			default:
+				return errors.Errorf("invalid format '%s', must be one of text, json, or dot", format)
+			}
+		},
+	}
+}
+
+func printCriticalPath(graph *model.APITaskGraph) {
+	nodes := map[string]model.APITaskGraphNode{}
+	for _, n := range graph.Nodes {
+		nodes[model.FromAPIString(n.TaskId)] = n
+	}
+
+	fmt.Printf("Version %s: %d tasks, critical path takes %s\n", model.FromAPIString(graph.VersionId),
+		len(graph.Nodes), graph.CriticalPathDuration.ToDuration().Round(time.Second))
+	for i, id := range graph.CriticalPath {
+		n := nodes[model.FromAPIString(id)]
+		duration := n.Duration.ToDuration().Round(time.Second).String()
+		if n.Estimated {
+			duration = "~" + duration
+		}
+		fmt.Printf("%3d. %s/%s (%s) %s [%s]\n", i+1, model.FromAPIString(n.BuildVariant), model.FromAPIString(n.DisplayName),
+			model.FromAPIString(n.Status), duration, model.FromAPIString(n.TaskId))
+	}
+}
@@ -528,6 +528,9 @@ function ActiveVersionHeader({shortenCommitMessage, version, onLinkClick, userTz
   var commit = version.revisions[0].substring(0,5);
   var message = version.messages[0];
   var formatted_time = getFormattedTime(version.create_times[0], userTz, 'M/D/YY h:mm A' );
+  var scheduled = version.requesters && version.requesters[0] == "scheduled_request";
+  var triggered = version.requesters && version.requesters[0] == "trigger_request";
+  var manual = version.requesters && version.requesters[0] == "manual_request";
   const maxChars = 44
   var button;
   if (message.length > maxChars) {
@@ -547,6 +550,9 @@ function ActiveVersionHeader({shortenCommitMessage, version, onLinkClick, userTz
             <div className="row">
               <a className="githash" href={id_link}>{commit}</a>
               {formatted_time}
+              {scheduled && <span className="label label-default">scheduled</span>}
+              {triggered && <span className="label label-default">triggered</span>}
+              {manual && <span className="label label-default">manual</span>}
             </div>
           </div>
           <div className="col-xs-12">
@@ -592,6 +598,9 @@ function RolledUpVersionHeader({version, userTz, jiraHost}){
             author={version.authors[i]}
             commit={version.revisions[i]}
             message={version.messages[i]}
+            scheduled={version.requesters && version.requesters[i] == "scheduled_request"}
+            triggered={version.requesters && version.requesters[i] == "trigger_request"}
+            manual={version.requesters && version.requesters[i] == "manual_request"}
             versionId={version.ids[i]}
             key={id} userTz={userTz}
             createTime={version.create_times[i]}
@@ -609,7 +618,7 @@ function RolledUpVersionHeader({version, userTz, jiraHost}){
     </div>
   )
 };
-function RolledUpVersionSummary ({author, commit, message, versionId, createTime, userTz, jiraHost}) {
+function RolledUpVersionSummary ({author, commit, message, scheduled, triggered, manual, versionId, createTime, userTz, jiraHost}) {
   var formatted_time = getFormattedTime(new Date(createTime), userTz, 'M/D/YY h:mm A' );
   commit =  commit.substring(0,10);
 
@@ -618,6 +627,9 @@ function RolledUpVersionSummary ({author, commit, message, versionId, createTime
       <span className="version-header-time">{formatted_time}</span>
       <br />
       <a href={"/version/" + versionId}>{commit}</a> - <strong>{author}</strong>
+      {scheduled && <span className="label label-default">scheduled</span>}
+      {triggered && <span className="label label-default">triggered</span>}
+      {manual && <span className="label label-default">manual</span>}
       <br />
       <JiraLink jiraHost={jiraHost}>{message}</JiraLink>
       <br />
@@ -14,6 +14,8 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
   $scope.projectView = false;
 
   $scope.settingsFormData = {};
+  $scope.new_schedule = {};
+  $scope.new_trigger = {level: "version", status: "success"};
   $scope.saveMessage = "";
 
   $scope.modalTitle = 'New Project';
@@ -177,6 +179,45 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
   }
 
 
+  // addSchedule adds the new version schedule to the settingsFormData's list
+  // of schedules, splitting its comma separated variants and tasks
+  $scope.addSchedule = function(){
+    var split = function(s) {
+      return _.filter(_.map((s || "").split(","), function(v) { return v.trim(); }), function(v) { return v !== ""; });
+    };
+    $scope.settingsFormData.schedules.push({
+      id: $scope.new_schedule.id,
+      cron: $scope.new_schedule.cron,
+      variants: split($scope.new_schedule.variants),
+      tasks: split($scope.new_schedule.tasks),
+      alias: $scope.new_schedule.alias,
+    });
+    $scope.new_schedule = {};
+    $scope.isDirty = true;
+  }
+
+  // removeSchedule removes the version schedule located at index
+  $scope.removeSchedule = function(index){
+    $scope.settingsFormData.schedules.splice(index, 1);
+    $scope.isDirty = true;
+  }
+
+
+  // addTrigger adds the new downstream trigger to the settingsFormData's list
+  // of triggers
+  $scope.addTrigger = function(){
+    $scope.settingsFormData.triggers.push($scope.new_trigger);
+    $scope.new_trigger = {level: "version", status: "success"};
+    $scope.isDirty = true;
+  }
+
+  // removeTrigger removes the downstream trigger located at index
+  $scope.removeTrigger = function(index){
+    $scope.settingsFormData.triggers.splice(index, 1);
+    $scope.isDirty = true;
+  }
+
+
   $scope.addProject = function() {
     $scope.modalOpen = false;
     $('#admin-modal').modal('hide');
@@ -243,6 +284,7 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
         }
         $scope.projectVars = data.ProjectVars.vars || {};
         $scope.privateVars = data.ProjectVars.private_vars || {};
+        $scope.secretVars = data.ProjectVars.secret_vars || {};
         $scope.githubHookID = data.github_hook.hook_id || 0;
         $scope.prTestingConflicts = data.pr_testing_conflicting_refs || [];
         $scope.prTestingEnabled = data.ProjectRef.pr_testing_enabled || false;
@@ -269,6 +311,7 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
           identifier : $scope.projectRef.identifier,
           project_vars: $scope.projectVars,
           private_vars: $scope.privateVars,
+          secret_vars: $scope.secretVars,
           display_name : $scope.projectRef.display_name,
           remote_path:$scope.projectRef.remote_path,
           batch_time: parseInt($scope.projectRef.batch_time),
@@ -286,6 +329,9 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
           setup_github_hook: $scope.githubHookID != 0,
           tracks_push_events: data.ProjectRef.tracks_push_events || false,
           pr_testing_enabled: data.ProjectRef.pr_testing_enabled || false,
+          commit_queue: data.ProjectRef.commit_queue || {enabled: false, merge_method: "squash"},
+          schedules: data.ProjectRef.schedules || [],
+          triggers: data.ProjectRef.triggers || [],
           notify_on_failure: $scope.projectRef.notify_on_failure,
           force_repotracker_run: false,
           delete_aliases: [],
@@ -402,6 +448,14 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
     }
   };
 
+  $scope.addSecretVar = function() {
+    if ($scope.secret_var.name && $scope.secret_var.ref) {
+      $scope.settingsFormData.secret_vars[$scope.secret_var.name] = $scope.secret_var.ref;
+      $scope.secret_var.name="";
+      $scope.secret_var.ref="";
+    }
+  };
+
   $scope.addGithubAlias = function() {
     if ($scope.github_alias.variant && $scope.github_alias.task) {
       item = Object.assign({}, $scope.github_alias)
@@ -429,6 +483,11 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
     $scope.isDirty = true;
   };
 
+  $scope.removeSecretVar = function(name) {
+    delete $scope.settingsFormData.secret_vars[name];
+    $scope.isDirty = true;
+  };
+
   $scope.removeGithubAlias = function(i) {
     if ($scope.github_aliases[i]["_id"]) {
       $scope.settingsFormData.delete_aliases = $scope.settingsFormData.delete_aliases.concat([$scope.github_aliases[i]["_id"]])
@@ -61,7 +61,10 @@ mciModule.controller('SchedulerEventCtrl', function($scope, $window, $http) {
             var data = resp.data;
             $scope.events = data;
             $scope.fullEvents = _.filter($scope.events, function(event){
-              return event.data.task_queue_info.task_queue_length > 0;
+              // hosts may be spawned ahead of forecast tasks while
+              // the queue is empty
+              return event.data.task_queue_info.task_queue_length > 0 ||
+                (event.data.host_forecast && event.data.host_forecast.new_hosts > 0);
             });
             return
           },
@@ -0,0 +1,132 @@
+package repotracker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// TriggerDownstreamVersion creates the version in a downstream project for
+// one of its trigger definitions, recording the upstream version, build, or
+// task that fired it. The version is created at the tip of the downstream
+// project's branch, as of the most recent version the repotracker created,
+// with the configuration file the trigger names at that revision, or the
+// project's configuration if it names none. Creating the version for a
+// trigger and event is idempotent.
+func TriggerDownstreamVersion(ref *model.ProjectRef, def *model.TriggerDefinition, upstream *version.Version,
+	triggerType, triggerID, eventID string) (*version.Version, error) {
+
+	id := util.CleanName(fmt.Sprintf("%s_%s_%s", ref.Identifier, def.ID, eventID))
+	v, err := version.FindOne(version.ById(id))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding version '%s'", id)
+	}
+	if v != nil {
+		return v, nil
+	}
+
+	tip, project, err := branchTipVersion(ref)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	config := tip.Config
+	if def.ConfigFile != "" {
+		project, err = getTriggerConfig(ref, def.ConfigFile, tip.Revision)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		var configBytes []byte
+		configBytes, err = yaml.Marshal(project)
+		if err != nil {
+			return nil, errors.Wrap(err, "problem marshalling project config")
+		}
+		config = string(configBytes)
+	}
+
+	tvPairs, err := project.BuildVersionTVPairs(nil, nil, def.Alias)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(tvPairs.ExecTasks) == 0 {
+		return nil, errors.Errorf("trigger '%s' does not match any tasks in project '%s' at revision '%s'",
+			def.ID, ref.Identifier, tip.Revision)
+	}
+
+	number, err := model.GetNewRevisionOrderNumber(ref.Identifier)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	v = &version.Version{
+		Id:                  id,
+		CreateTime:          time.Now(),
+		Revision:            tip.Revision,
+		Author:              upstream.Author,
+		AuthorEmail:         upstream.AuthorEmail,
+		Message:             fmt.Sprintf("triggered by %s '%s' in project '%s'", triggerType, triggerID, upstream.Identifier),
+		Status:              evergreen.VersionCreated,
+		RevisionOrderNumber: number,
+		Config:              config,
+		Owner:               ref.Owner,
+		Repo:                ref.Repo,
+		Branch:              ref.Branch,
+		RepoKind:            ref.RepoKind,
+		Identifier:          ref.Identifier,
+		RemotePath:          ref.RemotePath,
+		Requester:           evergreen.TriggerRequester,
+		TriggerID:           triggerID,
+		TriggerType:         triggerType,
+		TriggerEvent:        eventID,
+	}
+
+	v, err = createActivatedVersion(project, v, tvPairs)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	grip.Info(message.Fields{
+		"message":      "created downstream version",
+		"runner":       RunnerName,
+		"project":      ref.Identifier,
+		"trigger":      def.ID,
+		"version":      id,
+		"revision":     tip.Revision,
+		"trigger_type": triggerType,
+		"trigger_id":   triggerID,
+		"event_id":     eventID,
+	})
+
+	return v, nil
+}
+
+func getTriggerConfig(ref *model.ProjectRef, configFile, revision string) (*model.Project, error) {
+	settings, err := evergreen.GetConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "problem getting evergreen settings")
+	}
+
+	configRef := *ref
+	configRef.RemotePath = configFile
+	poller, err := getRepoPoller(settings, &configRef)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	project, err := poller.GetRemoteConfig(ctx, revision)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem getting config file '%s' at revision '%s'", configFile, revision)
+	}
+	return project, nil
+}
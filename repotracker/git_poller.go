@@ -0,0 +1,277 @@
+package repotracker
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/pkg/errors"
+)
+
+const (
+	gitLogFieldSeparator  = "\x00"
+	gitLogCommitSeparator = "\x1e"
+	gitLogFormat          = "--format=%H%x00%an%x00%ae%x00%ct%x00%B%x1e"
+)
+
+// gitRevisionRegexp matches the full or abbreviated commit hashes that the
+// poller accepts as revisions.
+var gitRevisionRegexp = regexp.MustCompile("^[0-9a-f]{4,40}$")
+
+func validateGitRevision(revision string) error {
+	if !gitRevisionRegexp.MatchString(revision) {
+		return errors.Errorf("invalid revision '%s': must be a commit hash", revision)
+	}
+	return nil
+}
+
+// gitMirrorLocks serializes access to each local mirror, since the
+// repotracker may poll the same project from more than one job.
+var gitMirrorLocks = struct {
+	sync.Mutex
+	dirs map[string]*sync.Mutex
+}{dirs: map[string]*sync.Mutex{}}
+
+func lockGitMirror(dir string) func() {
+	gitMirrorLocks.Lock()
+	mu, ok := gitMirrorLocks.dirs[dir]
+	if !ok {
+		mu = &sync.Mutex{}
+		gitMirrorLocks.dirs[dir] = mu
+	}
+	gitMirrorLocks.Unlock()
+
+	mu.Lock()
+	return mu.Unlock
+}
+
+// GitRepositoryPoller is a RepoPoller for any repository that git can clone.
+// It keeps a mirror of the project ref's RepoURL in a local directory,
+// fetching from it before looking for new revisions, and reads history and
+// files from the mirror with git.
+type GitRepositoryPoller struct {
+	ProjectRef *model.ProjectRef
+	MirrorDir  string
+}
+
+// NewGitRepositoryPoller constructs a GitRepositoryPoller that keeps its
+// mirror of the project's repository within mirrorsDir, or within the
+// system's temporary directory if mirrorsDir is empty.
+func NewGitRepositoryPoller(projectRef *model.ProjectRef, mirrorsDir string) *GitRepositoryPoller {
+	if mirrorsDir == "" {
+		mirrorsDir = filepath.Join(os.TempDir(), "evergreen-repo-mirrors")
+	}
+	return &GitRepositoryPoller{
+		ProjectRef: projectRef,
+		MirrorDir:  filepath.Join(mirrorsDir, projectRef.Identifier+".git"),
+	}
+}
+
+func (p *GitRepositoryPoller) git(ctx context.Context, args ...string) (string, error) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, "git", append([]string{"--git-dir", p.MirrorDir}, args...)...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return "", errors.Wrapf(err, "problem running 'git %s': %s", strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// sync clones the mirror if it does not exist yet, and otherwise fetches
+// new commits into it if fetch is true.
+func (p *GitRepositoryPoller) sync(ctx context.Context, fetch bool) error {
+	if p.ProjectRef.RepoURL == "" {
+		return errors.Errorf("project '%s' has no repo url to clone", p.ProjectRef.Identifier)
+	}
+	if strings.HasPrefix(p.ProjectRef.RepoURL, "-") {
+		return errors.Errorf("project '%s' has invalid repo url '%s'", p.ProjectRef.Identifier, p.ProjectRef.RepoURL)
+	}
+
+	if _, err := os.Stat(filepath.Join(p.MirrorDir, "HEAD")); os.IsNotExist(err) {
+		if err = os.MkdirAll(filepath.Dir(p.MirrorDir), 0755); err != nil {
+			return errors.Wrapf(err, "problem creating directory for mirror '%s'", p.MirrorDir)
+		}
+		stderr := &bytes.Buffer{}
+		cmd := exec.CommandContext(ctx, "git", "clone", "--mirror", "--", p.ProjectRef.RepoURL, p.MirrorDir)
+		cmd.Stderr = stderr
+		if err = cmd.Run(); err != nil {
+			return errors.Wrapf(err, "problem cloning '%s': %s", p.ProjectRef.RepoURL, strings.TrimSpace(stderr.String()))
+		}
+		return nil
+	}
+
+	if !fetch {
+		return nil
+	}
+	_, err := p.git(ctx, "fetch", "--prune", "--", p.ProjectRef.RepoURL, "+refs/heads/*:refs/heads/*")
+	return errors.Wrapf(err, "problem fetching '%s'", p.ProjectRef.RepoURL)
+}
+
+func (p *GitRepositoryPoller) branchRef() string {
+	return "refs/heads/" + p.ProjectRef.Branch
+}
+
+// log returns the revisions in the range of commits, most recent first.
+func (p *GitRepositoryPoller) log(ctx context.Context, max int, revisionRange string) ([]model.Revision, error) {
+	args := []string{"log", gitLogFormat}
+	if max > 0 {
+		args = append(args, "--max-count="+strconv.Itoa(max))
+	}
+	out, err := p.git(ctx, append(args, revisionRange, "--")...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	revisions := []model.Revision{}
+	for _, commit := range strings.Split(out, gitLogCommitSeparator) {
+		commit = strings.TrimLeft(commit, "\n")
+		if commit == "" {
+			continue
+		}
+		fields := strings.SplitN(commit, gitLogFieldSeparator, 5)
+		if len(fields) != 5 {
+			return nil, errors.Errorf("git log returned commit history with missing information for project ref: %s",
+				p.ProjectRef.Identifier)
+		}
+		seconds, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid commit time '%s' for revision '%s'", fields[3], fields[0])
+		}
+		revisions = append(revisions, model.Revision{
+			Revision:        fields[0],
+			Author:          fields[1],
+			AuthorEmail:     fields[2],
+			CreateTime:      time.Unix(seconds, 0),
+			RevisionMessage: strings.TrimRight(fields[4], "\n"),
+		})
+	}
+	return revisions, nil
+}
+
+// GetRemoteConfig reads the project's configuration file at a revision.
+func (p *GitRepositoryPoller) GetRemoteConfig(ctx context.Context, revision string) (*model.Project, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	if err := validateGitRevision(revision); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer lockGitMirror(p.MirrorDir)()
+
+	if err := p.sync(ctx, false); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	data, err := p.git(ctx, "show", "--end-of-options", revision+":"+p.ProjectRef.RemotePath)
+	if err != nil {
+		if _, typeErr := p.git(ctx, "cat-file", "-e", "--end-of-options", revision+"^{commit}"); typeErr == nil {
+			return nil, thirdparty.NewFileNotFoundError(p.ProjectRef.RemotePath)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	projectConfig := &model.Project{}
+	if err = model.LoadProjectInto([]byte(data), p.ProjectRef.Identifier, projectConfig); err != nil {
+		return nil, thirdparty.YAMLFormatError{Message: err.Error()}
+	}
+	return projectConfig, nil
+}
+
+// GetChangedFiles returns the files a revision modified relative to its
+// first parent, or all of its files if it has no parents.
+func (p *GitRepositoryPoller) GetChangedFiles(ctx context.Context, revision string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	if err := validateGitRevision(revision); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer lockGitMirror(p.MirrorDir)()
+
+	if err := p.sync(ctx, false); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	parents, err := p.git(ctx, "rev-list", "--parents", "--max-count=1", "--end-of-options", revision)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error loading commit '%v'", revision)
+	}
+	var out string
+	if fields := strings.Fields(parents); len(fields) > 1 {
+		out, err = p.git(ctx, "diff", "--name-only", fields[1], revision, "--")
+	} else {
+		out, err = p.git(ctx, "diff-tree", "-r", "--root", "--no-commit-id", "--name-only", revision, "--")
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error loading changes in commit '%v'", revision)
+	}
+
+	files := []string{}
+	for _, f := range strings.Split(out, "\n") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// GetRevisionsSince fetches the repository and returns the commits on the
+// project's branch made after revision, most recent first.
+func (p *GitRepositoryPoller) GetRevisionsSince(revision string, maxRevisionsToSearch int) ([]model.Revision, error) {
+	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Minute)
+	defer cancel()
+	if err := validateGitRevision(revision); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer lockGitMirror(p.MirrorDir)()
+
+	if err := p.sync(ctx, true); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	// the revision must still be in the branch's history, and within the
+	// number of revisions we're willing to search
+	if _, err := p.git(ctx, "merge-base", "--is-ancestor", "--end-of-options", revision, p.branchRef()); err == nil {
+		max := 0
+		if maxRevisionsToSearch > 0 {
+			max = maxRevisionsToSearch + 1
+		}
+		revisions, err := p.log(ctx, max, revision+".."+p.branchRef())
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if max == 0 || len(revisions) < max {
+			return revisions, nil
+		}
+	}
+
+	var baseRevision string
+	mergeBase, err := p.git(ctx, "merge-base", "--end-of-options", revision, p.branchRef())
+	if err == nil {
+		baseRevision = strings.TrimSpace(mergeBase)
+	}
+	return []model.Revision{}, recordRevisionNotFound(p.ProjectRef, revision, baseRevision, err)
+}
+
+// GetRecentRevisions fetches the repository and returns the most recent
+// commits on the project's branch, most recent first.
+func (p *GitRepositoryPoller) GetRecentRevisions(maxRevisions int) ([]model.Revision, error) {
+	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Minute)
+	defer cancel()
+	defer lockGitMirror(p.MirrorDir)()
+
+	if err := p.sync(ctx, true); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	revisions, err := p.log(ctx, maxRevisions, p.branchRef())
+	return revisions, errors.WithStack(err)
+}
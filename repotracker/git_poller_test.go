@@ -0,0 +1,157 @@
+package repotracker
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/stretchr/testify/suite"
+)
+
+type GitPollerSuite struct {
+	dir      string
+	upstream string
+	work     string
+	poller   *GitRepositoryPoller
+	suite.Suite
+}
+
+func TestGitPollerSuite(t *testing.T) {
+	suite.Run(t, new(GitPollerSuite))
+}
+
+func (s *GitPollerSuite) SetupTest() {
+	var err error
+	s.dir, err = ioutil.TempDir("", "git-poller")
+	s.Require().NoError(err)
+	s.upstream = filepath.Join(s.dir, "upstream.git")
+	s.work = filepath.Join(s.dir, "work")
+
+	s.run(s.dir, "init", "--bare", "--initial-branch=master", s.upstream)
+	s.run(s.dir, "clone", s.upstream, s.work)
+
+	s.poller = NewGitRepositoryPoller(&model.ProjectRef{
+		Identifier: "proj",
+		RepoKind:   model.GitRepoType,
+		RepoURL:    s.upstream,
+		Branch:     "master",
+		RemotePath: "evergreen.yml",
+	}, filepath.Join(s.dir, "mirrors"))
+}
+
+func (s *GitPollerSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.dir))
+}
+
+func (s *GitPollerSuite) run(dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Evergreen", "GIT_AUTHOR_EMAIL=evergreen@example.com",
+		"GIT_COMMITTER_NAME=Evergreen", "GIT_COMMITTER_EMAIL=evergreen@example.com")
+	out, err := cmd.CombinedOutput()
+	s.Require().NoError(err, string(out))
+	return strings.TrimSpace(string(out))
+}
+
+// commit writes the files, commits them, and pushes the commit upstream,
+// returning its revision.
+func (s *GitPollerSuite) commit(message string, files map[string]string) string {
+	for name, contents := range files {
+		s.Require().NoError(ioutil.WriteFile(filepath.Join(s.work, name), []byte(contents), 0644))
+		s.run(s.work, "add", name)
+	}
+	s.run(s.work, "commit", "-m", message)
+	s.run(s.work, "push", "origin", "HEAD:master")
+	return s.run(s.work, "rev-parse", "HEAD")
+}
+
+func (s *GitPollerSuite) TestRevisions() {
+	first := s.commit("first", map[string]string{"evergreen.yml": "tasks:\n- name: compile\n"})
+	second := s.commit("second\n\nwith a body", map[string]string{"main.go": "package main\n"})
+	third := s.commit("third", map[string]string{"main.go": "package main\n\nfunc main() {}\n", "README": "hi\n"})
+
+	revisions, err := s.poller.GetRecentRevisions(2)
+	s.Require().NoError(err)
+	s.Require().Len(revisions, 2)
+	s.Equal(third, revisions[0].Revision)
+	s.Equal(second, revisions[1].Revision)
+	s.Equal("second\n\nwith a body", revisions[1].RevisionMessage)
+	s.Equal("Evergreen", revisions[1].Author)
+	s.Equal("evergreen@example.com", revisions[1].AuthorEmail)
+	s.False(revisions[1].CreateTime.IsZero())
+
+	revisions, err = s.poller.GetRevisionsSince(first, 10)
+	s.Require().NoError(err)
+	s.Require().Len(revisions, 2)
+	s.Equal(third, revisions[0].Revision)
+
+	// new commits are fetched into the existing mirror
+	fourth := s.commit("fourth", map[string]string{"README": "hello\n"})
+	revisions, err = s.poller.GetRevisionsSince(third, 10)
+	s.Require().NoError(err)
+	s.Require().Len(revisions, 1)
+	s.Equal(fourth, revisions[0].Revision)
+
+	revisions, err = s.poller.GetRevisionsSince(fourth, 10)
+	s.NoError(err)
+	s.Empty(revisions)
+}
+
+func (s *GitPollerSuite) TestChangedFilesAndConfig() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first := s.commit("first", map[string]string{"evergreen.yml": "tasks:\n- name: compile\n", "main.go": "package main\n"})
+	second := s.commit("second", map[string]string{"main.go": "package main\n\nfunc main() {}\n", "README": "hi\n"})
+	third := s.commit("third", map[string]string{"evergreen.yml": "tasks: [\n"})
+
+	files, err := s.poller.GetChangedFiles(ctx, first)
+	s.Require().NoError(err)
+	s.Equal([]string{"evergreen.yml", "main.go"}, files)
+	files, err = s.poller.GetChangedFiles(ctx, second)
+	s.Require().NoError(err)
+	s.Equal([]string{"README", "main.go"}, files)
+
+	project, err := s.poller.GetRemoteConfig(ctx, second)
+	s.Require().NoError(err)
+	s.Equal("proj", project.Identifier)
+	s.Require().Len(project.Tasks, 1)
+	s.Equal("compile", project.Tasks[0].Name)
+
+	_, err = s.poller.GetRemoteConfig(ctx, third)
+	s.IsType(thirdparty.YAMLFormatError{}, err)
+
+	s.poller.ProjectRef.RemotePath = "missing.yml"
+	_, err = s.poller.GetRemoteConfig(ctx, second)
+	s.True(thirdparty.IsFileNotFound(err))
+}
+
+func (s *GitPollerSuite) TestRejectsOptionsAsArguments() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	revision := s.commit("first", map[string]string{"evergreen.yml": "tasks:\n- name: compile\n"})
+
+	for _, bad := range []string{"--output=/tmp/x", "HEAD", revision + ":evergreen.yml", ""} {
+		_, err := s.poller.GetRemoteConfig(ctx, bad)
+		s.Error(err, bad)
+		_, err = s.poller.GetChangedFiles(ctx, bad)
+		s.Error(err, bad)
+		_, err = s.poller.GetRevisionsSince(bad, 10)
+		s.Error(err, bad)
+	}
+
+	s.poller.ProjectRef.RepoURL = "--upload-pack=touch " + filepath.Join(s.dir, "pwned")
+	s.poller.MirrorDir = filepath.Join(s.dir, "other-mirror.git")
+	_, err := s.poller.GetRecentRevisions(1)
+	s.Error(err)
+	_, err = os.Stat(filepath.Join(s.dir, "pwned"))
+	s.True(os.IsNotExist(err))
+}
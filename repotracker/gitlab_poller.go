@@ -0,0 +1,128 @@
+package repotracker
+
+import (
+	"context"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/pkg/errors"
+)
+
+// GitlabRepositoryPoller is a RepoPoller for projects hosted on GitLab. The
+// project ref's RepoURL is the GitLab instance, which defaults to gitlab.com.
+type GitlabRepositoryPoller struct {
+	ProjectRef *model.ProjectRef
+	Token      string
+}
+
+// NewGitlabRepositoryPoller constructs a GitlabRepositoryPoller that
+// authenticates with a GitLab personal access token, if one is given.
+func NewGitlabRepositoryPoller(projectRef *model.ProjectRef, token string) *GitlabRepositoryPoller {
+	return &GitlabRepositoryPoller{
+		ProjectRef: projectRef,
+		Token:      token,
+	}
+}
+
+func gitlabCommitToRevision(commit thirdparty.GitlabCommit) model.Revision {
+	return model.Revision{
+		Author:          commit.AuthorName,
+		AuthorEmail:     commit.AuthorEmail,
+		RevisionMessage: commit.Message,
+		Revision:        commit.ID,
+		CreateTime:      commit.CommittedDate,
+	}
+}
+
+// GetRemoteConfig fetches the project's configuration file at a revision.
+func (p *GitlabRepositoryPoller) GetRemoteConfig(ctx context.Context, revision string) (*model.Project, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	ref := p.ProjectRef
+	data, err := thirdparty.GetGitlabFile(ctx, ref.RepoURL, p.Token, ref.Owner, ref.Repo, ref.RemotePath, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	projectConfig := &model.Project{}
+	if err = model.LoadProjectInto(data, ref.Identifier, projectConfig); err != nil {
+		return nil, thirdparty.YAMLFormatError{Message: err.Error()}
+	}
+	return projectConfig, nil
+}
+
+// GetChangedFiles returns the files modified by a revision.
+func (p *GitlabRepositoryPoller) GetChangedFiles(ctx context.Context, revision string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	ref := p.ProjectRef
+	files, err := thirdparty.GetGitlabChangedFiles(ctx, ref.RepoURL, p.Token, ref.Owner, ref.Repo, revision)
+	return files, errors.Wrapf(err, "error loading commit '%v'", revision)
+}
+
+// GetRevisionsSince fetches the commits on the project's branch made after
+// revision, most recent first.
+func (p *GitlabRepositoryPoller) GetRevisionsSince(revision string, maxRevisionsToSearch int) ([]model.Revision, error) {
+	ctx, cancel := context.WithTimeout(context.TODO(), 30*time.Second)
+	defer cancel()
+
+	ref := p.ProjectRef
+	revisions := []model.Revision{}
+	var firstCommit string
+	for page := 1; page != 0; {
+		commits, next, err := thirdparty.GetGitlabCommits(ctx, ref.RepoURL, p.Token, ref.Owner, ref.Repo, ref.Branch, page)
+		if err != nil {
+			return nil, err
+		}
+		page = next
+
+		for _, commit := range commits {
+			if firstCommit == "" {
+				firstCommit = commit.ID
+			}
+			if commit.ID == revision {
+				return revisions, nil
+			}
+			if maxRevisionsToSearch > 0 && len(revisions) >= maxRevisionsToSearch {
+				page = 0
+				break
+			}
+			revisions = append(revisions, gitlabCommitToRevision(commit))
+		}
+	}
+
+	var baseRevision string
+	err := errors.New("no recent commit found")
+	if firstCommit != "" {
+		baseRevision, err = thirdparty.GetGitlabMergeBaseRevision(ctx, ref.RepoURL, p.Token, ref.Owner, ref.Repo, revision, firstCommit)
+	}
+	return []model.Revision{}, recordRevisionNotFound(ref, revision, baseRevision, err)
+}
+
+// GetRecentRevisions fetches the most recent commits on the project's
+// branch, most recent first.
+func (p *GitlabRepositoryPoller) GetRecentRevisions(maxRevisions int) ([]model.Revision, error) {
+	ctx, cancel := context.WithTimeout(context.TODO(), 30*time.Second)
+	defer cancel()
+
+	ref := p.ProjectRef
+	revisions := []model.Revision{}
+	for page := 1; page != 0 && len(revisions) < maxRevisions; {
+		commits, next, err := thirdparty.GetGitlabCommits(ctx, ref.RepoURL, p.Token, ref.Owner, ref.Repo, ref.Branch, page)
+		if err != nil {
+			return nil, err
+		}
+		page = next
+
+		for _, commit := range commits {
+			if len(revisions) == maxRevisions {
+				break
+			}
+			revisions = append(revisions, gitlabCommitToRevision(commit))
+		}
+	}
+	return revisions, nil
+}
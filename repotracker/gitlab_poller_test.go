@@ -0,0 +1,137 @@
+package repotracker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitlabPoller(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 5 commits, most recent first, served two to a page
+	commits := []thirdparty.GitlabCommit{}
+	for i := 5; i > 0; i-- {
+		commits = append(commits, thirdparty.GitlabCommit{
+			ID:            fmt.Sprintf("%040d", i),
+			Message:       fmt.Sprintf("commit %d", i),
+			AuthorName:    "Evergreen",
+			AuthorEmail:   "evergreen@example.com",
+			CommittedDate: time.Unix(int64(i), 0).UTC(),
+		})
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/projects/evergreen-ci%2Fevergreen/repository/commits", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("token", r.Header.Get("PRIVATE-TOKEN"))
+		assert.Equal("master", r.URL.Query().Get("ref_name"))
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		require.NoError(err)
+		start, end := (page-1)*2, page*2
+		if end >= len(commits) {
+			end = len(commits)
+		} else {
+			w.Header().Set("X-Next-Page", strconv.Itoa(page+1))
+		}
+		assert.NoError(json.NewEncoder(w).Encode(commits[start:end]))
+	})
+	mux.HandleFunc("/api/v4/projects/evergreen-ci%2Fevergreen/repository/files/self-tests.yml/raw", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("ref") != commits[0].ID {
+			http.Error(w, `{"message": "404 File Not Found"}`, http.StatusNotFound)
+			return
+		}
+		_, err := w.Write([]byte("tasks:\n- name: compile\n"))
+		assert.NoError(err)
+	})
+	mux.HandleFunc(fmt.Sprintf("/api/v4/projects/evergreen-ci%%2Fevergreen/repository/commits/%s/diff", commits[0].ID), func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte(`[{"old_path": "a.go", "new_path": "a.go"}, {"old_path": "b.go", "new_path": "c.go"}]`))
+		assert.NoError(err)
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the project path is encoded, so route on the raw path
+		r.URL.Path = r.URL.EscapedPath()
+		mux.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	poller := NewGitlabRepositoryPoller(&model.ProjectRef{
+		Identifier: "evergreen",
+		Owner:      "evergreen-ci",
+		Repo:       "evergreen",
+		Branch:     "master",
+		RepoKind:   model.GitlabRepoType,
+		RepoURL:    server.URL,
+		RemotePath: "self-tests.yml",
+	}, "token")
+
+	revisions, err := poller.GetRecentRevisions(3)
+	require.NoError(err)
+	require.Len(revisions, 3)
+	assert.Equal(commits[0].ID, revisions[0].Revision)
+	assert.Equal("commit 5", revisions[0].RevisionMessage)
+	assert.Equal("Evergreen", revisions[0].Author)
+	assert.Equal(commits[2].ID, revisions[2].Revision)
+
+	revisions, err = poller.GetRevisionsSince(commits[3].ID, 10)
+	require.NoError(err)
+	require.Len(revisions, 3)
+	assert.Equal(commits[2].ID, revisions[2].Revision)
+
+	revisions, err = poller.GetRevisionsSince(commits[0].ID, 10)
+	require.NoError(err)
+	assert.Empty(revisions)
+
+	files, err := poller.GetChangedFiles(ctx, commits[0].ID)
+	require.NoError(err)
+	assert.Equal([]string{"a.go", "c.go", "b.go"}, files)
+
+	project, err := poller.GetRemoteConfig(ctx, commits[0].ID)
+	require.NoError(err)
+	require.Len(project.Tasks, 1)
+	assert.Equal("compile", project.Tasks[0].Name)
+
+	_, err = poller.GetRemoteConfig(ctx, commits[1].ID)
+	assert.True(thirdparty.IsFileNotFound(err))
+}
+
+func TestGitlabPollerOnlySendsCredentialsToConfiguredInstance(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	conf := &evergreen.Settings{
+		Credentials: map[string]string{model.GitlabRepoType: "token"},
+		RepoTracker: evergreen.RepoTrackerConfig{GitlabURL: "https://gitlab.example.com/"},
+	}
+	for repoURL, expected := range map[string]string{
+		"https://gitlab.example.com":         "token",
+		"https://GITLAB.example.com/":        "token",
+		"https://gitlab.example.com.evil.io": "",
+		"https://evil.io":                    "",
+		"http://gitlab.example.com":          "",
+		"":                                   "",
+	} {
+		poller, err := getRepoPoller(conf, &model.ProjectRef{Identifier: "proj", RepoKind: model.GitlabRepoType, RepoURL: repoURL})
+		require.NoError(err)
+		assert.Equal(expected, poller.(*GitlabRepositoryPoller).Token, repoURL)
+	}
+
+	// the instance defaults to gitlab.com
+	conf.RepoTracker.GitlabURL = ""
+	poller, err := getRepoPoller(conf, &model.ProjectRef{Identifier: "proj", RepoKind: model.GitlabRepoType})
+	require.NoError(err)
+	assert.Equal("token", poller.(*GitlabRepositoryPoller).Token)
+}
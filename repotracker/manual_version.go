@@ -0,0 +1,137 @@
+package repotracker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ManualVersionOptions describes a version a user starts by hand.
+type ManualVersionOptions struct {
+	// Revision is the revision, or a prefix of it, to create the version
+	// at. It must be a revision the repotracker has tracked. If it is empty,
+	// the version is created at the tip of the project's branch.
+	Revision string
+	// Parameters are the values for the parameters the project declares.
+	Parameters map[string]string
+	// Variants, Tasks, and Alias select the tasks to run as they do for
+	// version schedules. They default to all tasks in all variants.
+	Variants []string
+	Tasks    []string
+	Alias    string
+	// Author is the user starting the version, and Message describes it.
+	Author  string
+	Message string
+}
+
+// CreateManualVersion creates a version of a tracked revision of a project
+// with user supplied parameters, which are validated against the parameters
+// the project configuration at that revision declares. The builds of the
+// version are activated immediately.
+func CreateManualVersion(ref *model.ProjectRef, opts ManualVersionOptions) (*version.Version, error) {
+	base, project, err := manualVersionBase(ref, opts.Revision)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	params, err := project.ValidateParameters(opts.Parameters)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid parameters")
+	}
+
+	tvPairs, err := project.BuildVersionTVPairs(opts.Variants, opts.Tasks, opts.Alias)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(tvPairs.ExecTasks) == 0 {
+		return nil, errors.Errorf("no tasks in project '%s' at revision '%s' match the requested variants and tasks",
+			ref.Identifier, base.Revision)
+	}
+
+	number, err := model.GetNewRevisionOrderNumber(ref.Identifier)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	msg := opts.Message
+	if msg == "" {
+		msg = fmt.Sprintf("manual version of '%s'", base.Revision)
+	}
+	v := &version.Version{
+		Id:                  fmt.Sprintf("%s_%s", ref.Identifier, bson.NewObjectId().Hex()),
+		CreateTime:          time.Now(),
+		Revision:            base.Revision,
+		Author:              opts.Author,
+		AuthorID:            opts.Author,
+		Message:             msg,
+		Status:              evergreen.VersionCreated,
+		RevisionOrderNumber: number,
+		Config:              base.Config,
+		Owner:               ref.Owner,
+		Repo:                ref.Repo,
+		Branch:              ref.Branch,
+		RepoKind:            ref.RepoKind,
+		Identifier:          ref.Identifier,
+		RemotePath:          ref.RemotePath,
+		Requester:           evergreen.ManualVersionRequester,
+		Parameters:          params,
+	}
+
+	v, err = createActivatedVersion(project, v, tvPairs)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	grip.Info(message.Fields{
+		"message":    "created manual version",
+		"runner":     RunnerName,
+		"project":    ref.Identifier,
+		"version":    v.Id,
+		"revision":   v.Revision,
+		"author":     opts.Author,
+		"parameters": len(params),
+		"builds":     len(v.BuildIds),
+	})
+
+	return v, nil
+}
+
+// manualVersionBase finds the version the repotracker created for a
+// revision, or for the tip of the project's branch if the revision is
+// empty, and loads its project configuration.
+func manualVersionBase(ref *model.ProjectRef, revision string) (*version.Version, *model.Project, error) {
+	if revision == "" {
+		return branchTipVersion(ref)
+	}
+
+	if err := validateGitRevision(revision); err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	var q db.Q
+	if len(revision) < 40 {
+		q = version.ByProjectIdAndRevisionPrefix(ref.Identifier, revision)
+	} else {
+		q = version.ByProjectIdAndRevision(ref.Identifier, revision)
+	}
+	base, err := version.FindOne(q)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "problem finding version for revision '%s'", revision)
+	}
+	if base == nil {
+		return nil, nil, errors.Errorf("revision '%s' has not been tracked in project '%s'", revision, ref.Identifier)
+	}
+
+	project := &model.Project{}
+	if err = model.LoadProjectInto([]byte(base.Config), ref.Identifier, project); err != nil {
+		return nil, nil, errors.Wrapf(err, "problem loading project config for version '%s'", base.Id)
+	}
+	return base, project, nil
+}
@@ -0,0 +1,16 @@
+package repotracker
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManualVersionBaseRejectsInvalidRevisions(t *testing.T) {
+	ref := &model.ProjectRef{Identifier: "proj"}
+	for _, revision := range []string{".*", "abc", "abcd.*", "ABCDEF", "--all", "0123456789012345678901234567890123456789a"} {
+		_, _, err := manualVersionBase(ref, revision)
+		assert.Error(t, err, revision)
+	}
+}
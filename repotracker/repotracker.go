@@ -657,3 +657,96 @@ func makeBuildBreakSubscriber(userID string) (*event.Subscriber, error) {
 
 	return subscriber, nil
 }
+
+// recordRevisionNotFound records on the project ref that the last revision
+// the repotracker saw is no longer in the branch's history, along with the
+// merge base of that revision and the branch if the poller could find one,
+// so that an admin can confirm where tracking should resume. It returns the
+// error the poller should report.
+func recordRevisionNotFound(projectRef *model.ProjectRef, revision, baseRevision string, mergeBaseErr error) error {
+	if len(revision) < 10 {
+		return errors.Errorf("invalid revision: %v", revision)
+	}
+
+	var revisionError error
+	if mergeBaseErr != nil {
+		// unable to get merge base commit so set projectRef revision details with a blank base revision
+		baseRevision = ""
+		revisionError = errors.Wrapf(mergeBaseErr,
+			"unable to find a suggested merge base commit for revision %v, must fix on projects settings page",
+			revision)
+	} else {
+		revisionError = errors.Errorf("base revision, %v not found, suggested base revision, %v found, must confirm on project settings page",
+			revision, baseRevision)
+	}
+
+	// update project ref to have an inconsistent status
+	projectRef.RepotrackerError = &model.RepositoryErrorDetails{
+		Exists:            true,
+		InvalidRevision:   revision[:10],
+		MergeBaseRevision: baseRevision,
+	}
+	if err := projectRef.Upsert(); err != nil {
+		return errors.Wrap(err, "unable to update projectRef revision details")
+	}
+
+	return revisionError
+}
+
+// branchTipVersion returns the most recent version the repotracker created
+// for the project, along with its project configuration.
+func branchTipVersion(ref *model.ProjectRef) (*version.Version, *model.Project, error) {
+	tip, err := version.FindOne(version.ByMostRecentForRequester(ref.Identifier, evergreen.RepotrackerVersionRequester))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "problem finding most recent version for project '%s'", ref.Identifier)
+	}
+	if tip == nil {
+		return nil, nil, errors.Errorf("project '%s' has no versions", ref.Identifier)
+	}
+
+	project := &model.Project{}
+	if err = model.LoadProjectInto([]byte(tip.Config), ref.Identifier, project); err != nil {
+		return nil, nil, errors.Wrapf(err, "problem loading project config for version '%s'", tip.Id)
+	}
+	return tip, project, nil
+}
+
+// createActivatedVersion creates activated builds for the tasks in a version
+// that was not created by tracking a commit, and inserts the version. If the
+// version already exists, the existing version is returned.
+func createActivatedVersion(project *model.Project, v *version.Version, tvPairs model.TaskVariantPairs) (*version.Version, error) {
+	variants := map[string]bool{}
+	for _, pair := range tvPairs.ExecTasks {
+		variants[pair.Variant] = true
+	}
+	for _, pair := range tvPairs.DisplayTasks {
+		variants[pair.Variant] = true
+	}
+
+	taskIds := model.NewPatchTaskIdTable(project, v, tvPairs)
+	for _, bv := range project.BuildVariants {
+		if !variants[bv.Name] {
+			continue
+		}
+		buildId, err := model.CreateBuildFromVersion(project, v, taskIds, bv.Name, true,
+			tvPairs.ExecTasks.TaskNames(bv.Name), tvPairs.DisplayTasks.TaskNames(bv.Name), "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem creating build '%s' for version '%s'", bv.Name, v.Id)
+		}
+		v.BuildIds = append(v.BuildIds, buildId)
+		v.BuildVariants = append(v.BuildVariants, version.BuildStatus{
+			BuildVariant: bv.Name,
+			Activated:    true,
+			ActivateAt:   v.CreateTime,
+			BuildId:      buildId,
+		})
+	}
+
+	if err := v.Insert(); err != nil {
+		if db.IsDuplicateKey(err) {
+			return version.FindOne(version.ById(v.Id))
+		}
+		return nil, errors.Wrapf(err, "problem inserting version '%s'", v.Id)
+	}
+	return v, nil
+}
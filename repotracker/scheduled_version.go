@@ -0,0 +1,101 @@
+package repotracker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// ScheduledVersionId returns the id of the version that a project's version
+// schedule creates when it fires at the given time.
+func ScheduledVersionId(projectId, scheduleId string, fireTime time.Time) string {
+	return util.CleanName(fmt.Sprintf("%s_%s_%s", projectId, scheduleId, fireTime.UTC().Format(build.IdTimeLayout)))
+}
+
+// CreateScheduledVersion creates the version for a project's version
+// schedule firing at the given time. The version is created at the tip of
+// the project's branch, as of the most recent version the repotracker
+// created, with the project configuration of that revision, and its builds
+// are activated immediately. Creating the version for a schedule and fire
+// time is idempotent: if the version already exists it is returned as is.
+func CreateScheduledVersion(ref *model.ProjectRef, scheduleId string, fireTime time.Time) (*version.Version, error) {
+	schedule := ref.GetSchedule(scheduleId)
+	if schedule == nil {
+		return nil, errors.Errorf("project '%s' has no schedule '%s'", ref.Identifier, scheduleId)
+	}
+
+	id := ScheduledVersionId(ref.Identifier, scheduleId, fireTime)
+	v, err := version.FindOne(version.ById(id))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding version '%s'", id)
+	}
+	if v != nil {
+		return v, nil
+	}
+
+	tip, project, err := branchTipVersion(ref)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	tvPairs, err := project.BuildScheduleTVPairs(schedule)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(tvPairs.ExecTasks) == 0 {
+		return nil, errors.Errorf("schedule '%s' does not match any tasks in project '%s' at revision '%s'",
+			scheduleId, ref.Identifier, tip.Revision)
+	}
+
+	number, err := model.GetNewRevisionOrderNumber(ref.Identifier)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	msg := schedule.Message
+	if msg == "" {
+		msg = fmt.Sprintf("scheduled version '%s' (%s)", scheduleId, schedule.Cron)
+	}
+	v = &version.Version{
+		Id:                  id,
+		CreateTime:          fireTime,
+		Revision:            tip.Revision,
+		Author:              evergreen.User,
+		Message:             msg,
+		Status:              evergreen.VersionCreated,
+		RevisionOrderNumber: number,
+		Config:              tip.Config,
+		Owner:               ref.Owner,
+		Repo:                ref.Repo,
+		Branch:              ref.Branch,
+		RepoKind:            ref.RepoKind,
+		Identifier:          ref.Identifier,
+		RemotePath:          ref.RemotePath,
+		Requester:           evergreen.ScheduledVersionRequester,
+	}
+
+	v, err = createActivatedVersion(project, v, tvPairs)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	grip.Info(message.Fields{
+		"message":  "created scheduled version",
+		"runner":   RunnerName,
+		"project":  ref.Identifier,
+		"schedule": scheduleId,
+		"version":  id,
+		"revision": tip.Revision,
+		"builds":   len(v.BuildIds),
+	})
+
+	return v, nil
+}
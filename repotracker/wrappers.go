@@ -12,7 +12,8 @@ import (
 )
 
 const (
-	// the repotracker polls version control (github) for new commits
+	// the repotracker polls version control (github, gitlab, or any git
+	// remote) for new commits
 	RunnerName = "repotracker"
 
 	// githubAPILimitCeiling is arbitrary but corresponds to when we start logging errors in
@@ -21,24 +22,49 @@ const (
 )
 
 func getTracker(conf *evergreen.Settings, project model.ProjectRef) (*RepoTracker, error) {
-	token, err := conf.GetGithubOauthToken()
+	poller, err := getRepoPoller(conf, &project)
 	if err != nil {
-		grip.Warning(message.Fields{
-			"runner":  RunnerName,
-			"message": "Github credentials not specified in Evergreen credentials file",
-		})
 		return nil, errors.WithStack(err)
 	}
 
 	tracker := &RepoTracker{
 		Settings:   conf,
 		ProjectRef: &project,
-		RepoPoller: NewGithubRepositoryPoller(&project, token),
+		RepoPoller: poller,
 	}
 
 	return tracker, nil
 }
 
+// getRepoPoller returns the poller for the kind of repository the project
+// is in.
+func getRepoPoller(conf *evergreen.Settings, project *model.ProjectRef) (RepoPoller, error) {
+	switch project.RepoKind {
+	case model.GithubRepoType, "":
+		token, err := conf.GetGithubOauthToken()
+		if err != nil {
+			grip.Warning(message.Fields{
+				"runner":  RunnerName,
+				"message": "Github credentials not specified in Evergreen credentials file",
+			})
+			return nil, errors.WithStack(err)
+		}
+		return NewGithubRepositoryPoller(project, token), nil
+	case model.GitlabRepoType:
+		// the credentials are only for the configured instance, so projects
+		// hosted elsewhere cannot collect them
+		token := ""
+		if thirdparty.IsSameGitlabInstance(project.RepoURL, conf.RepoTracker.GitlabURL) {
+			token = conf.Credentials[model.GitlabRepoType]
+		}
+		return NewGitlabRepositoryPoller(project, token), nil
+	case model.GitRepoType:
+		return NewGitRepositoryPoller(project, conf.RepoTracker.MirrorDirectory), nil
+	default:
+		return nil, errors.Errorf("unsupported repo kind '%s' for project '%s'", project.RepoKind, project.Identifier)
+	}
+}
+
 func CollectRevisionsForProject(ctx context.Context, conf *evergreen.Settings, project model.ProjectRef) error {
 	if !project.Enabled {
 		return errors.Errorf("project disabled: %s", project.Identifier)
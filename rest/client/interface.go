@@ -63,6 +63,9 @@ type Communicator interface {
 	GetDistro(context.Context, TaskData) (*distro.Distro, error)
 	// GetVersion loads the task's version.
 	GetVersion(context.Context, TaskData) (*version.Version, error)
+	// GetBucketsConfig returns the admin settings for the storage backends
+	// of the artifact commands.
+	GetBucketsConfig(context.Context, TaskData) (*evergreen.BucketsConfig, error)
 	// Heartbeat sends a heartbeat to the API server. The server can respond with
 	// an "abort" response. This function returns true if the agent should abort.
 	Heartbeat(context.Context, TaskData) (bool, error)
@@ -70,6 +73,9 @@ type Communicator interface {
 	FetchExpansionVars(context.Context, TaskData) (*apimodels.ExpansionVars, error)
 	// GetNextTask returns a next task response by getting the next task for a given host.
 	GetNextTask(context.Context, *apimodels.GetNextTaskDetails) (*apimodels.NextTaskResponse, error)
+	// ReportSpotInterruption tells the API server that the cloud provider
+	// will reclaim the host's spot instance.
+	ReportSpotInterruption(context.Context, apimodels.SpotInterruptionNotice) error
 
 	// Constructs a new LogProducer instance for use by tasks.
 	GetLoggerProducer(context.Context, TaskData) LoggerProducer
@@ -160,4 +166,72 @@ type Communicator interface {
 	// GetSubscriptions fetches the subscriptions for the user defined
 	// in the local evergreen yaml
 	GetSubscriptions(context.Context) ([]event.Subscription, error)
+
+	// GetFlakyTests fetches the flaky and quarantined tests of a project
+	GetFlakyTests(context.Context, string) ([]restmodel.APIFlakyTest, error)
+	// SetTestQuarantined quarantines or releases a test of a task in a project
+	SetTestQuarantined(context.Context, string, string, string, bool) error
+
+	// Commit Queue
+	// GetCommitQueue fetches the commit queue of a project
+	GetCommitQueue(context.Context, string) (*restmodel.APICommitQueue, error)
+	// EnqueueItem adds an item to a project's commit queue and returns its position
+	EnqueueItem(context.Context, string, string) (int, error)
+	// DeleteCommitQueueItem removes an item from a project's commit queue
+	DeleteCommitQueueItem(context.Context, string, string) error
+	// MoveCommitQueueItem moves an item to a position in a project's commit queue
+	MoveCommitQueueItem(context.Context, string, string, int) error
+
+	// StreamTaskLogs passes each message of a task's log to a handler,
+	// optionally following the log until the task finishes
+	StreamTaskLogs(context.Context, string, string, int, bool, func(restmodel.APILogMessage) error) error
+
+	// GetVersionTaskGraph fetches the task dependency graph and critical path
+	// of a version
+	GetVersionTaskGraph(context.Context, string) (*restmodel.APITaskGraph, error)
+	// GetVersionTaskGraphDOT fetches the task dependency graph of a version
+	// rendered in the DOT language
+	GetVersionTaskGraphDOT(context.Context, string) (string, error)
+
+	// CreateManualVersion starts a version of a revision of a project with
+	// the given parameters
+	CreateManualVersion(context.Context, string, restmodel.APIManualVersionRequest) (*restmodel.APIVersion, error)
+
+	// Project settings
+	// GetProject fetches the settings of a project
+	GetProject(context.Context, string) (*restmodel.APIProject, error)
+	// CreateProject creates a project with the given settings
+	CreateProject(context.Context, string, restmodel.APIProject) (*restmodel.APIProject, error)
+	// UpdateProject changes the given settings of a project
+	UpdateProject(context.Context, string, map[string]interface{}) (*restmodel.APIProject, error)
+	// CopyProject copies a project's settings, variables, and aliases to a
+	// new, disabled project
+	CopyProject(context.Context, string, string) (*restmodel.APIProject, error)
+	// DeleteProject removes a project and its variables and aliases
+	DeleteProject(context.Context, string) error
+	// GetProjectVars fetches the variables of a project, without the values
+	// of private variables
+	GetProjectVars(context.Context, string) (*restmodel.APIProjectVars, error)
+	// UpdateProjectVars sets and deletes variables of a project
+	UpdateProjectVars(context.Context, string, restmodel.APIProjectVars) (*restmodel.APIProjectVars, error)
+	// SetProjectAliases replaces the aliases of a project
+	SetProjectAliases(context.Context, string, []restmodel.APIAlias) error
+
+	// Distros
+	// GetDistroByID fetches a distro
+	GetDistroByID(context.Context, string) (*restmodel.APIDistro, error)
+	// PutDistro creates or replaces a distro, optionally decommissioning
+	// its hosts or only reporting what would change
+	PutDistro(context.Context, string, restmodel.APIDistro, bool, bool) (*restmodel.APIDistroChange, error)
+	// DeleteDistro removes a distro
+	DeleteDistro(context.Context, string) error
+	// CopyDistro creates a distro with the settings of an existing one
+	CopyDistro(context.Context, string, string) (*restmodel.APIDistro, error)
+	// GetDistroRevisions fetches the revisions of a distro, oldest first
+	GetDistroRevisions(context.Context, string) ([]restmodel.APIDistroRevision, error)
+	// DiffDistroRevisions fetches the changes to a distro between two
+	// revisions
+	DiffDistroRevisions(context.Context, string, int, int) ([]distro.FieldChange, error)
+	// RollbackDistro restores a distro as it was at a revision
+	RollbackDistro(context.Context, string, int, bool, bool) (*restmodel.APIDistroChange, error)
 }
@@ -0,0 +1,211 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/send"
+	"github.com/pkg/errors"
+)
+
+// LocalStorageDirectory is the directory, within a local communicator's
+// output directory, that stands in for remote storage buckets.
+const LocalStorageDirectory = "s3"
+
+var unsafeFileNameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// localCommunicator is a Communicator for running a task without an API
+// server. Calls that would report data to the server write it to files in
+// an output directory instead, and the task's logs are written to standard
+// output.
+type localCommunicator struct {
+	outputDir string
+	mu        sync.Mutex
+	*Mock
+}
+
+// NewLocalCommunicator returns a Communicator that stores the data a task
+// reports in outputDir rather than sending it to an API server.
+func NewLocalCommunicator(outputDir string) Communicator {
+	return &localCommunicator{
+		outputDir: outputDir,
+		Mock:      NewMock(""),
+	}
+}
+
+// GetLoggerProducer returns a logger that writes every channel to standard
+// output.
+func (c *localCommunicator) GetLoggerProducer(ctx context.Context, td TaskData) LoggerProducer {
+	return NewSingleChannelLogHarness(td.ID, send.MakePlainLogger())
+}
+
+func (c *localCommunicator) path(parts ...string) (string, error) {
+	path := filepath.Join(append([]string{c.outputDir}, parts...)...)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", errors.Wrapf(err, "problem creating directory for '%s'", path)
+	}
+	return path, nil
+}
+
+// updateJSON reads the named JSON file into v, if it exists, calls update,
+// and writes v back to the file.
+func (c *localCommunicator) updateJSON(name string, v interface{}, update func()) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path, err := c.path(name)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err == nil {
+		if err = json.Unmarshal(data, v); err != nil {
+			return errors.Wrapf(err, "problem reading '%s'", path)
+		}
+	} else if !os.IsNotExist(err) {
+		return errors.Wrapf(err, "problem reading '%s'", path)
+	}
+
+	update()
+
+	data, err = json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.Wrapf(ioutil.WriteFile(path, data, 0644), "problem writing '%s'", path)
+}
+
+// AttachFiles records the task's artifacts in artifacts.json.
+func (c *localCommunicator) AttachFiles(ctx context.Context, td TaskData, taskFiles []*artifact.File) error {
+	for _, f := range taskFiles {
+		grip.Infof("attached file '%s' at '%s'", f.Name, f.Link)
+	}
+
+	files := []*artifact.File{}
+	return errors.WithStack(c.updateJSON("artifacts.json", &files, func() {
+		files = append(files, taskFiles...)
+	}))
+}
+
+// SendTestResults records the task's test results in results.json.
+func (c *localCommunicator) SendTestResults(ctx context.Context, td TaskData, results *task.LocalTestResults) error {
+	if results == nil || len(results.Results) == 0 {
+		return nil
+	}
+
+	failed := 0
+	for _, r := range results.Results {
+		if r.Status == evergreen.TestFailedStatus {
+			failed++
+		}
+	}
+	grip.Infof("attached %d test results (%d failed)", len(results.Results), failed)
+
+	existing := task.LocalTestResults{}
+	return errors.WithStack(c.updateJSON("results.json", &existing, func() {
+		existing.Results = append(existing.Results, results.Results...)
+	}))
+}
+
+// SendTestLog writes a test log to the test_logs directory and returns its
+// path as the log's id.
+func (c *localCommunicator) SendTestLog(ctx context.Context, td TaskData, log *serviceModel.TestLog) (string, error) {
+	if log == nil {
+		return "", nil
+	}
+
+	path, err := c.path("test_logs", unsafeFileNameChars.ReplaceAllString(log.Name, "_")+".log")
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	if err = ioutil.WriteFile(path, []byte(strings.Join(log.Lines, "\n")), 0644); err != nil {
+		return "", errors.Wrapf(err, "problem writing test log '%s'", log.Name)
+	}
+
+	return path, nil
+}
+
+// GetBucketsConfig returns settings for filesystem buckets in the local
+// storage directory.
+func (c *localCommunicator) GetBucketsConfig(ctx context.Context, td TaskData) (*evergreen.BucketsConfig, error) {
+	return &evergreen.BucketsConfig{
+		Filesystem: evergreen.FilesystemBucketConfig{
+			Root: filepath.Join(c.outputDir, LocalStorageDirectory),
+		},
+	}, nil
+}
+
+// S3Copy copies a file between buckets in the local storage directory.
+func (c *localCommunicator) S3Copy(ctx context.Context, td TaskData, req *apimodels.S3CopyRequest) error {
+	src := filepath.Join(c.outputDir, LocalStorageDirectory, req.S3SourceBucket, filepath.FromSlash(req.S3SourcePath))
+	dest, err := c.path(LocalStorageDirectory, req.S3DestinationBucket, filepath.FromSlash(req.S3DestinationPath))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return errors.Wrapf(err, "problem opening '%s/%s'", req.S3SourceBucket, req.S3SourcePath)
+	}
+	defer in.Close()
+	out, err := os.Create(dest)
+	if err != nil {
+		return errors.Wrapf(err, "problem creating '%s/%s'", req.S3DestinationBucket, req.S3DestinationPath)
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return errors.Wrapf(err, "problem copying '%s/%s' to '%s/%s'", req.S3SourceBucket, req.S3SourcePath,
+		req.S3DestinationBucket, req.S3DestinationPath)
+}
+
+// PostJSONData writes the data to the json directory.
+func (c *localCommunicator) PostJSONData(ctx context.Context, td TaskData, path string, data interface{}) error {
+	out, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	dest, err := c.path("json", unsafeFileNameChars.ReplaceAllString(path, "_")+".json")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.Wrapf(ioutil.WriteFile(dest, out, 0644), "problem writing json data for '%s'", path)
+}
+
+// GenerateTasks writes the generated project configurations to
+// generated_tasks.json rather than adding them to a version.
+func (c *localCommunicator) GenerateTasks(ctx context.Context, td TaskData, jsonBytes []json.RawMessage) error {
+	dest, err := c.path("generated_tasks.json")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	out, err := json.MarshalIndent(jsonBytes, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	grip.Infof("generated tasks are not run locally, wrote their configuration to '%s'", dest)
+	return errors.Wrap(ioutil.WriteFile(dest, out, 0644), "problem writing generated tasks")
+}
+
+// CreateHost is not supported without an API server.
+func (c *localCommunicator) CreateHost(ctx context.Context, td TaskData, options apimodels.CreateHost) error {
+	return errors.New("hosts cannot be created when running a task locally")
+}
+
+// GetPatchFile is not supported without an API server.
+func (c *localCommunicator) GetPatchFile(ctx context.Context, td TaskData, patchFileID string) (string, error) {
+	return "", errors.Errorf("patch file '%s' is not available when running a task locally", patchFileID)
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/artifact"
@@ -162,6 +163,30 @@ func (c *communicatorImpl) GetDistro(ctx context.Context, taskData TaskData) (*d
 	return d, nil
 }
 
+// GetBucketsConfig returns the admin settings for the storage backends of
+// the artifact commands.
+func (c *communicatorImpl) GetBucketsConfig(ctx context.Context, taskData TaskData) (*evergreen.BucketsConfig, error) {
+	conf := &evergreen.BucketsConfig{}
+	info := requestInfo{
+		method:   get,
+		taskData: &taskData,
+		version:  apiVersion1,
+	}
+	info.setTaskPathSuffix("buckets")
+	resp, err := c.retryRequest(ctx, info, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get bucket settings for task %s", taskData.ID)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusConflict {
+		return nil, errors.New("conflict; wrong secret")
+	}
+	if err = util.ReadJSONInto(resp.Body, conf); err != nil {
+		return nil, errors.Wrapf(err, "unable to read bucket settings response for task %s", taskData.ID)
+	}
+	return conf, nil
+}
+
 // GetVersion loads the task's version.
 func (c *communicatorImpl) GetVersion(ctx context.Context, taskData TaskData) (*version.Version, error) {
 	v := &version.Version{}
@@ -273,6 +298,22 @@ func (c *communicatorImpl) GetNextTask(ctx context.Context, details *apimodels.G
 
 }
 
+// ReportSpotInterruption tells the API server that the cloud provider will
+// reclaim the host's spot instance.
+func (c *communicatorImpl) ReportSpotInterruption(ctx context.Context, notice apimodels.SpotInterruptionNotice) error {
+	info := requestInfo{
+		method:  post,
+		version: apiVersion1,
+		path:    "agent/spot_interruption",
+	}
+	resp, err := c.retryRequest(ctx, info, notice)
+	if err != nil {
+		return errors.Wrap(err, "failed to report spot interruption")
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
 // SendLogMessages posts a group of log messages for a task.
 func (c *communicatorImpl) SendLogMessages(ctx context.Context, taskData TaskData, msgs []apimodels.LogMessage) error {
 	if len(msgs) == 0 {
@@ -58,6 +58,7 @@ type Mock struct {
 	GetSubscriptionsFail        bool
 
 	AttachedFiles    map[string][]*artifact.File
+	BucketsConfig    evergreen.BucketsConfig
 	LogID            string
 	LocalTestResults *task.LocalTestResults
 	TestLogs         []*serviceModel.TestLog
@@ -68,10 +69,11 @@ type Mock struct {
 	SysInfo  map[string]*message.SystemInfo
 
 	// data collected by mocked methods
-	logMessages     map[string][]apimodels.LogMessage
-	PatchFiles      map[string]string
-	keyVal          map[string]*serviceModel.KeyVal
-	LastMessageSent time.Time
+	logMessages       map[string][]apimodels.LogMessage
+	PatchFiles        map[string]string
+	keyVal            map[string]*serviceModel.KeyVal
+	LastMessageSent   time.Time
+	SpotInterruptions []apimodels.SpotInterruptionNotice
 
 	mu sync.RWMutex
 }
@@ -176,6 +178,12 @@ func (c *Mock) GetDistro(ctx context.Context, td TaskData) (*distro.Distro, erro
 	}, nil
 }
 
+// GetBucketsConfig returns the mock's BucketsConfig.
+func (c *Mock) GetBucketsConfig(ctx context.Context, td TaskData) (*evergreen.BucketsConfig, error) {
+	conf := c.BucketsConfig
+	return &conf, nil
+}
+
 // GetVersion return a mock Version.
 func (c *Mock) GetVersion(ctx context.Context, td TaskData) (*version.Version, error) {
 	var err error
@@ -221,6 +229,14 @@ func (c *Mock) FetchExpansionVars(ctx context.Context, td TaskData) (*apimodels.
 	}, nil
 }
 
+// ReportSpotInterruption records the notice.
+func (c *Mock) ReportSpotInterruption(ctx context.Context, notice apimodels.SpotInterruptionNotice) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.SpotInterruptions = append(c.SpotInterruptions, notice)
+	return nil
+}
+
 // GetNextTask returns a mock NextTaskResponse.
 func (c *Mock) GetNextTask(ctx context.Context, details *apimodels.GetNextTaskDetails) (*apimodels.NextTaskResponse, error) {
 	if c.NextTaskIsNil {
@@ -554,3 +570,103 @@ func (c *Mock) GetSubscriptions(_ context.Context) ([]event.Subscription, error)
 		},
 	}, nil
 }
+
+func (c *Mock) GetFlakyTests(ctx context.Context, project string) ([]model.APIFlakyTest, error) {
+	return nil, errors.New("(c *Mock) GetFlakyTests not implemented")
+}
+
+func (c *Mock) SetTestQuarantined(ctx context.Context, project, taskName, testFile string, quarantined bool) error {
+	return errors.New("(c *Mock) SetTestQuarantined not implemented")
+}
+
+func (c *Mock) GetCommitQueue(ctx context.Context, projectID string) (*model.APICommitQueue, error) {
+	return nil, errors.New("(c *Mock) GetCommitQueue not implemented")
+}
+
+func (c *Mock) EnqueueItem(ctx context.Context, projectID, item string) (int, error) {
+	return -1, errors.New("(c *Mock) EnqueueItem not implemented")
+}
+
+func (c *Mock) DeleteCommitQueueItem(ctx context.Context, projectID, item string) error {
+	return errors.New("(c *Mock) DeleteCommitQueueItem not implemented")
+}
+
+func (c *Mock) MoveCommitQueueItem(ctx context.Context, projectID, item string, position int) error {
+	return errors.New("(c *Mock) MoveCommitQueueItem not implemented")
+}
+
+func (c *Mock) StreamTaskLogs(ctx context.Context, taskID, logType string, execution int, follow bool, handle func(model.APILogMessage) error) error {
+	return errors.New("(c *Mock) StreamTaskLogs not implemented")
+}
+
+func (c *Mock) GetVersionTaskGraph(ctx context.Context, versionID string) (*model.APITaskGraph, error) {
+	return nil, errors.New("(c *Mock) GetVersionTaskGraph not implemented")
+}
+
+func (c *Mock) GetVersionTaskGraphDOT(ctx context.Context, versionID string) (string, error) {
+	return "", errors.New("(c *Mock) GetVersionTaskGraphDOT not implemented")
+}
+
+func (c *Mock) CreateManualVersion(ctx context.Context, projectID string, req model.APIManualVersionRequest) (*model.APIVersion, error) {
+	return nil, errors.New("(c *Mock) CreateManualVersion not implemented")
+}
+
+func (c *Mock) GetProject(ctx context.Context, projectID string) (*model.APIProject, error) {
+	return nil, errors.New("(c *Mock) GetProject not implemented")
+}
+
+func (c *Mock) CreateProject(ctx context.Context, projectID string, project model.APIProject) (*model.APIProject, error) {
+	return nil, errors.New("(c *Mock) CreateProject not implemented")
+}
+
+func (c *Mock) UpdateProject(ctx context.Context, projectID string, update map[string]interface{}) (*model.APIProject, error) {
+	return nil, errors.New("(c *Mock) UpdateProject not implemented")
+}
+
+func (c *Mock) CopyProject(ctx context.Context, projectID, newProjectID string) (*model.APIProject, error) {
+	return nil, errors.New("(c *Mock) CopyProject not implemented")
+}
+
+func (c *Mock) DeleteProject(ctx context.Context, projectID string) error {
+	return errors.New("(c *Mock) DeleteProject not implemented")
+}
+
+func (c *Mock) GetProjectVars(ctx context.Context, projectID string) (*model.APIProjectVars, error) {
+	return nil, errors.New("(c *Mock) GetProjectVars not implemented")
+}
+
+func (c *Mock) UpdateProjectVars(ctx context.Context, projectID string, vars model.APIProjectVars) (*model.APIProjectVars, error) {
+	return nil, errors.New("(c *Mock) UpdateProjectVars not implemented")
+}
+
+func (c *Mock) SetProjectAliases(ctx context.Context, projectID string, aliases []model.APIAlias) error {
+	return errors.New("(c *Mock) SetProjectAliases not implemented")
+}
+
+func (c *Mock) GetDistroByID(ctx context.Context, distroID string) (*model.APIDistro, error) {
+	return nil, errors.New("(c *Mock) GetDistroByID not implemented")
+}
+
+func (c *Mock) PutDistro(ctx context.Context, distroID string, d model.APIDistro, decommission, dryRun bool) (*model.APIDistroChange, error) {
+	return nil, errors.New("(c *Mock) PutDistro not implemented")
+}
+
+func (c *Mock) DeleteDistro(ctx context.Context, distroID string) error {
+	return errors.New("(c *Mock) DeleteDistro not implemented")
+}
+
+func (c *Mock) CopyDistro(ctx context.Context, distroID, newDistroID string) (*model.APIDistro, error) {
+	return nil, errors.New("(c *Mock) CopyDistro not implemented")
+}
+
+func (c *Mock) GetDistroRevisions(ctx context.Context, distroID string) ([]model.APIDistroRevision, error) {
+	return nil, errors.New("(c *Mock) GetDistroRevisions not implemented")
+}
+
+func (c *Mock) DiffDistroRevisions(ctx context.Context, distroID string, revision, against int) ([]distro.FieldChange, error) {
+	return nil, errors.New("(c *Mock) DiffDistroRevisions not implemented")
+}
+
+func (c *Mock) RollbackDistro(ctx context.Context, distroID string, revision int, decommission, dryRun bool) (*model.APIDistroChange, error) {
+	return nil, errors.New("(c *Mock) RollbackDistro not implemented")
+}
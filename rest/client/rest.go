@@ -4,12 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/util"
@@ -611,3 +614,759 @@ func (c *communicatorImpl) GetSubscriptions(ctx context.Context) ([]event.Subscr
 
 	return subs, nil
 }
+
+// GetFlakyTests returns the flaky and quarantined tests of a project.
+func (c *communicatorImpl) GetFlakyTests(ctx context.Context, project string) ([]model.APIFlakyTest, error) {
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    fmt.Sprintf("projects/%s/flaky_tests", project),
+	}
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "problem querying api server")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return nil, errors.Wrap(errMsg, "problem fetching flaky tests")
+	}
+
+	bytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading JSON")
+	}
+	tests := []model.APIFlakyTest{}
+	if err = json.Unmarshal(bytes, &tests); err != nil {
+		test := model.APIFlakyTest{}
+		if err = json.Unmarshal(bytes, &test); err != nil {
+			return nil, errors.Wrap(err, "error reading json")
+		}
+		tests = []model.APIFlakyTest{test}
+	}
+
+	return tests, nil
+}
+
+// SetTestQuarantined quarantines or releases a test of a task in a project.
+func (c *communicatorImpl) SetTestQuarantined(ctx context.Context, project, taskName, testFile string, quarantined bool) error {
+	info := requestInfo{
+		method:  post,
+		version: apiVersion2,
+		path:    fmt.Sprintf("projects/%s/flaky_tests/quarantine", project),
+	}
+	body := model.APIQuarantineRequest{
+		TaskName:    taskName,
+		TestFile:    testFile,
+		Quarantined: quarantined,
+	}
+
+	resp, err := c.request(ctx, info, body)
+	if err != nil {
+		return errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return errors.Wrap(err, "problem updating quarantine state and parsing error message")
+		}
+		return errors.Wrap(errMsg, "problem updating quarantine state")
+	}
+
+	return nil
+}
+
+// GetCommitQueue fetches the commit queue of a project.
+func (c *communicatorImpl) GetCommitQueue(ctx context.Context, projectID string) (*model.APICommitQueue, error) {
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    fmt.Sprintf("commit_queue/%s", projectID),
+	}
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "problem querying api server")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return nil, errors.Wrap(errMsg, "problem fetching commit queue")
+	}
+
+	queue := &model.APICommitQueue{}
+	if err = util.ReadJSONInto(resp.Body, queue); err != nil {
+		return nil, errors.Wrap(err, "error reading json")
+	}
+
+	return queue, nil
+}
+
+// EnqueueItem adds an item to the commit queue of a project and returns the
+// item's position in the queue.
+func (c *communicatorImpl) EnqueueItem(ctx context.Context, projectID, item string) (int, error) {
+	info := requestInfo{
+		method:  put,
+		version: apiVersion2,
+		path:    fmt.Sprintf("commit_queue/%s/%s", projectID, item),
+	}
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return -1, errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return -1, errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return -1, errors.Wrap(errMsg, "problem enqueueing item")
+	}
+
+	position := model.APICommitQueuePosition{}
+	if err = util.ReadJSONInto(resp.Body, &position); err != nil {
+		return -1, errors.Wrap(err, "error reading json")
+	}
+
+	return position.Position, nil
+}
+
+// DeleteCommitQueueItem removes an item from the commit queue of a project.
+func (c *communicatorImpl) DeleteCommitQueueItem(ctx context.Context, projectID, item string) error {
+	info := requestInfo{
+		method:  delete,
+		version: apiVersion2,
+		path:    fmt.Sprintf("commit_queue/%s/%s", projectID, item),
+	}
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return errors.Wrap(errMsg, "problem deleting item")
+	}
+
+	return nil
+}
+
+// MoveCommitQueueItem moves an item to a new position in the commit queue of
+// a project.
+func (c *communicatorImpl) MoveCommitQueueItem(ctx context.Context, projectID, item string, position int) error {
+	info := requestInfo{
+		method:  patch,
+		version: apiVersion2,
+		path:    fmt.Sprintf("commit_queue/%s/%s", projectID, item),
+	}
+	resp, err := c.request(ctx, info, model.APICommitQueuePosition{Position: position})
+	if err != nil {
+		return errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return errors.Wrap(errMsg, "problem moving item")
+	}
+
+	return nil
+}
+
+// StreamTaskLogs calls handle with each message of a task's log of the given
+// type, for the given execution or, if execution is negative, the task's
+// latest one. When follow is true it keeps reading messages as they are
+// logged, reconnecting as needed, until the task finishes or the context is
+// canceled. Reconnecting resumes the execution the stream started with, even
+// if the task has been restarted since.
+func (c *communicatorImpl) StreamTaskLogs(ctx context.Context, taskID, logType string, execution int, follow bool, handle func(model.APILogMessage) error) error {
+	offset := 0
+	for {
+		chunk, err := c.streamTaskLogChunks(ctx, taskID, logType, execution, offset, follow, handle)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if chunk == nil {
+			return errors.New("task log stream ended without a final chunk")
+		}
+		if !follow || chunk.Finished {
+			return nil
+		}
+		execution = chunk.Execution
+		offset = chunk.NextOffset
+	}
+}
+
+// streamTaskLogChunks reads one task log stream, passing its messages to
+// handle, and returns the last chunk read.
+func (c *communicatorImpl) streamTaskLogChunks(ctx context.Context, taskID, logType string, execution, offset int, follow bool, handle func(model.APILogMessage) error) (*model.APITaskLogChunk, error) {
+	path := fmt.Sprintf("tasks/%s/log/stream?type=%s&offset=%d&follow=%t", taskID, logType, offset, follow)
+	if execution >= 0 {
+		path = fmt.Sprintf("%s&execution=%d", path, execution)
+	}
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    path,
+	}
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return nil, errors.Wrap(errMsg, "problem streaming task log")
+	}
+
+	var last *model.APITaskLogChunk
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		chunk := &model.APITaskLogChunk{}
+		if err = decoder.Decode(chunk); err == io.EOF {
+			return last, nil
+		} else if err != nil {
+			return nil, errors.Wrap(err, "problem reading task log stream")
+		}
+		for _, msg := range chunk.Messages {
+			if err = handle(msg); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+		last = chunk
+	}
+}
+
+func (c *communicatorImpl) getVersionTaskGraph(ctx context.Context, versionID, format string) (*http.Response, error) {
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    fmt.Sprintf("versions/%s/graph?format=%s", versionID, format),
+	}
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "problem querying api server")
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return nil, errors.Wrapf(errMsg, "problem fetching task graph of version '%s'", versionID)
+	}
+
+	return resp, nil
+}
+
+// GetVersionTaskGraph fetches the task dependency graph and critical path of
+// a version.
+func (c *communicatorImpl) GetVersionTaskGraph(ctx context.Context, versionID string) (*model.APITaskGraph, error) {
+	resp, err := c.getVersionTaskGraph(ctx, versionID, "json")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	graph := &model.APITaskGraph{}
+	if err = util.ReadJSONInto(resp.Body, graph); err != nil {
+		return nil, errors.Wrap(err, "error reading json")
+	}
+
+	return graph, nil
+}
+
+// GetVersionTaskGraphDOT fetches the task dependency graph of a version
+// rendered in the DOT language.
+func (c *communicatorImpl) GetVersionTaskGraphDOT(ctx context.Context, versionID string) (string, error) {
+	resp, err := c.getVersionTaskGraph(ctx, versionID, "dot")
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	out, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Wrap(err, "error reading response")
+	}
+
+	return string(out), nil
+}
+
+// CreateManualVersion starts a version of a revision of a project with the
+// parameters, variants, and tasks in the request.
+func (c *communicatorImpl) CreateManualVersion(ctx context.Context, projectID string, req model.APIManualVersionRequest) (*model.APIVersion, error) {
+	info := requestInfo{
+		method:  post,
+		version: apiVersion2,
+		path:    fmt.Sprintf("projects/%s/versions", projectID),
+	}
+	resp, err := c.request(ctx, info, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return nil, errors.Wrapf(errMsg, "problem creating version of project '%s'", projectID)
+	}
+
+	v := &model.APIVersion{}
+	if err = util.ReadJSONInto(resp.Body, v); err != nil {
+		return nil, errors.Wrap(err, "error reading json")
+	}
+
+	return v, nil
+}
+
+// GetProject fetches the settings of a project.
+func (c *communicatorImpl) GetProject(ctx context.Context, projectID string) (*model.APIProject, error) {
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    fmt.Sprintf("projects/%s", projectID),
+	}
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "problem querying api server")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return nil, errors.Wrapf(errMsg, "problem fetching project '%s'", projectID)
+	}
+
+	project := &model.APIProject{}
+	if err = util.ReadJSONInto(resp.Body, project); err != nil {
+		return nil, errors.Wrap(err, "error reading json")
+	}
+
+	return project, nil
+}
+
+// CreateProject creates a project with the given settings.
+func (c *communicatorImpl) CreateProject(ctx context.Context, projectID string, project model.APIProject) (*model.APIProject, error) {
+	info := requestInfo{
+		method:  put,
+		version: apiVersion2,
+		path:    fmt.Sprintf("projects/%s", projectID),
+	}
+	resp, err := c.request(ctx, info, project)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return nil, errors.Wrapf(errMsg, "problem creating project '%s'", projectID)
+	}
+
+	created := &model.APIProject{}
+	if err = util.ReadJSONInto(resp.Body, created); err != nil {
+		return nil, errors.Wrap(err, "error reading json")
+	}
+
+	return created, nil
+}
+
+// UpdateProject changes the settings of a project. The update is a map so
+// that settings missing from it are left unchanged.
+func (c *communicatorImpl) UpdateProject(ctx context.Context, projectID string, update map[string]interface{}) (*model.APIProject, error) {
+	info := requestInfo{
+		method:  patch,
+		version: apiVersion2,
+		path:    fmt.Sprintf("projects/%s", projectID),
+	}
+	resp, err := c.request(ctx, info, update)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return nil, errors.Wrapf(errMsg, "problem updating project '%s'", projectID)
+	}
+
+	updated := &model.APIProject{}
+	if err = util.ReadJSONInto(resp.Body, updated); err != nil {
+		return nil, errors.Wrap(err, "error reading json")
+	}
+
+	return updated, nil
+}
+
+// CopyProject creates a disabled project with the settings, variables, and
+// aliases of an existing one.
+func (c *communicatorImpl) CopyProject(ctx context.Context, projectID, newProjectID string) (*model.APIProject, error) {
+	info := requestInfo{
+		method:  post,
+		version: apiVersion2,
+		path:    fmt.Sprintf("projects/%s/copy?new_project=%s", projectID, url.QueryEscape(newProjectID)),
+	}
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return nil, errors.Wrapf(errMsg, "problem copying project '%s'", projectID)
+	}
+
+	copied := &model.APIProject{}
+	if err = util.ReadJSONInto(resp.Body, copied); err != nil {
+		return nil, errors.Wrap(err, "error reading json")
+	}
+
+	return copied, nil
+}
+
+// DeleteProject removes a project and its variables and aliases.
+func (c *communicatorImpl) DeleteProject(ctx context.Context, projectID string) error {
+	info := requestInfo{
+		method:  delete,
+		version: apiVersion2,
+		path:    fmt.Sprintf("projects/%s", projectID),
+	}
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return errors.Wrapf(errMsg, "problem deleting project '%s'", projectID)
+	}
+
+	return nil
+}
+
+// GetProjectVars fetches the variables of a project. The values of private
+// variables are empty.
+func (c *communicatorImpl) GetProjectVars(ctx context.Context, projectID string) (*model.APIProjectVars, error) {
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    fmt.Sprintf("projects/%s/variables", projectID),
+	}
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "problem querying api server")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return nil, errors.Wrapf(errMsg, "problem fetching variables of project '%s'", projectID)
+	}
+
+	vars := &model.APIProjectVars{}
+	if err = util.ReadJSONInto(resp.Body, vars); err != nil {
+		return nil, errors.Wrap(err, "error reading json")
+	}
+
+	return vars, nil
+}
+
+// UpdateProjectVars sets and deletes variables of a project.
+func (c *communicatorImpl) UpdateProjectVars(ctx context.Context, projectID string, vars model.APIProjectVars) (*model.APIProjectVars, error) {
+	info := requestInfo{
+		method:  patch,
+		version: apiVersion2,
+		path:    fmt.Sprintf("projects/%s/variables", projectID),
+	}
+	resp, err := c.request(ctx, info, vars)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return nil, errors.Wrapf(errMsg, "problem updating variables of project '%s'", projectID)
+	}
+
+	updated := &model.APIProjectVars{}
+	if err = util.ReadJSONInto(resp.Body, updated); err != nil {
+		return nil, errors.Wrap(err, "error reading json")
+	}
+
+	return updated, nil
+}
+
+// SetProjectAliases replaces the aliases of a project.
+func (c *communicatorImpl) SetProjectAliases(ctx context.Context, projectID string, aliases []model.APIAlias) error {
+	info := requestInfo{
+		method:  put,
+		version: apiVersion2,
+		path:    fmt.Sprintf("projects/%s/aliases", projectID),
+	}
+	resp, err := c.request(ctx, info, aliases)
+	if err != nil {
+		return errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return errors.Wrapf(errMsg, "problem setting aliases of project '%s'", projectID)
+	}
+
+	return nil
+}
+
+// GetDistroByID fetches a distro.
+func (c *communicatorImpl) GetDistroByID(ctx context.Context, distroID string) (*model.APIDistro, error) {
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    fmt.Sprintf("distros/%s", distroID),
+	}
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "problem querying api server")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return nil, errors.Wrapf(errMsg, "problem fetching distro '%s'", distroID)
+	}
+
+	d := &model.APIDistro{}
+	if err = util.ReadJSONInto(resp.Body, d); err != nil {
+		return nil, errors.Wrap(err, "error reading json")
+	}
+
+	return d, nil
+}
+
+// PutDistro creates a distro, or replaces it if it exists. If decommission is
+// set, the hosts of an existing distro are decommissioned. A dry run only
+// validates the distro and reports the changes and hosts.
+func (c *communicatorImpl) PutDistro(ctx context.Context, distroID string, d model.APIDistro, decommission, dryRun bool) (*model.APIDistroChange, error) {
+	info := requestInfo{
+		method:  put,
+		version: apiVersion2,
+		path:    fmt.Sprintf("distros/%s?decommission=%t&dry_run=%t", distroID, decommission, dryRun),
+	}
+	resp, err := c.request(ctx, info, d)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return nil, errors.Wrapf(errMsg, "problem saving distro '%s'", distroID)
+	}
+
+	change := &model.APIDistroChange{}
+	if err = util.ReadJSONInto(resp.Body, change); err != nil {
+		return nil, errors.Wrap(err, "error reading json")
+	}
+
+	return change, nil
+}
+
+// DeleteDistro removes a distro.
+func (c *communicatorImpl) DeleteDistro(ctx context.Context, distroID string) error {
+	info := requestInfo{
+		method:  delete,
+		version: apiVersion2,
+		path:    fmt.Sprintf("distros/%s", distroID),
+	}
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return errors.Wrapf(errMsg, "problem deleting distro '%s'", distroID)
+	}
+
+	return nil
+}
+
+// CopyDistro creates a distro with the settings of an existing one.
+func (c *communicatorImpl) CopyDistro(ctx context.Context, distroID, newDistroID string) (*model.APIDistro, error) {
+	info := requestInfo{
+		method:  post,
+		version: apiVersion2,
+		path:    fmt.Sprintf("distros/%s/copy?new_distro=%s", distroID, url.QueryEscape(newDistroID)),
+	}
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return nil, errors.Wrapf(errMsg, "problem copying distro '%s'", distroID)
+	}
+
+	d := &model.APIDistro{}
+	if err = util.ReadJSONInto(resp.Body, d); err != nil {
+		return nil, errors.Wrap(err, "error reading json")
+	}
+
+	return d, nil
+}
+
+// GetDistroRevisions fetches the revisions of a distro, oldest first.
+func (c *communicatorImpl) GetDistroRevisions(ctx context.Context, distroID string) ([]model.APIDistroRevision, error) {
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    fmt.Sprintf("distros/%s/revisions", distroID),
+	}
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "problem querying api server")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return nil, errors.Wrapf(errMsg, "problem fetching revisions of distro '%s'", distroID)
+	}
+
+	bytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading JSON")
+	}
+	revisions := []model.APIDistroRevision{}
+	if err = json.Unmarshal(bytes, &revisions); err != nil {
+		revision := model.APIDistroRevision{}
+		if err = json.Unmarshal(bytes, &revision); err != nil {
+			return nil, errors.Wrap(err, "error reading json")
+		}
+		revisions = []model.APIDistroRevision{revision}
+	}
+
+	return revisions, nil
+}
+
+// DiffDistroRevisions fetches the changes to a distro from one revision to
+// another. Revision 0 is an empty distro.
+func (c *communicatorImpl) DiffDistroRevisions(ctx context.Context, distroID string, revision, against int) ([]distro.FieldChange, error) {
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    fmt.Sprintf("distros/%s/revisions/%d/diff?against=%d", distroID, revision, against),
+	}
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "problem querying api server")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return nil, errors.Wrapf(errMsg, "problem comparing revisions of distro '%s'", distroID)
+	}
+
+	changes := []distro.FieldChange{}
+	if err = util.ReadJSONInto(resp.Body, &changes); err != nil {
+		return nil, errors.Wrap(err, "error reading json")
+	}
+
+	return changes, nil
+}
+
+// RollbackDistro restores a distro as it was at a revision.
+func (c *communicatorImpl) RollbackDistro(ctx context.Context, distroID string, revision int, decommission, dryRun bool) (*model.APIDistroChange, error) {
+	info := requestInfo{
+		method:  post,
+		version: apiVersion2,
+		path:    fmt.Sprintf("distros/%s/revisions/%d/rollback?decommission=%t&dry_run=%t", distroID, revision, decommission, dryRun),
+	}
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reaching evergreen API server")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Errorf("bad status from api server: %v", resp.StatusCode)
+		}
+		return nil, errors.Wrapf(errMsg, "problem rolling back distro '%s'", distroID)
+	}
+
+	change := &model.APIDistroChange{}
+	if err = util.ReadJSONInto(resp.Body, change); err != nil {
+		return nil, errors.Wrap(err, "error reading json")
+	}
+
+	return change, nil
+}
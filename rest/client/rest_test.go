@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamTaskLogsResumesTheSameExecution(t *testing.T) {
+	assert := assert.New(t)
+
+	queries := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		chunk := model.APITaskLogChunk{
+			Messages:   []model.APILogMessage{{Message: model.ToAPIString("msg")}},
+			Execution:  3,
+			NextOffset: len(queries),
+			Finished:   len(queries) == 2,
+		}
+		assert.NoError(json.NewEncoder(w).Encode(chunk))
+	}))
+	defer server.Close()
+
+	client := NewCommunicator(server.URL)
+	defer client.Close()
+
+	numMsgs := 0
+	require.NoError(t, client.StreamTaskLogs(context.Background(), "t1", "task", -1, true, func(model.APILogMessage) error {
+		numMsgs++
+		return nil
+	}))
+	assert.Equal(2, numMsgs)
+	assert.Equal([]string{
+		"type=task&offset=0&follow=true",
+		"type=task&offset=1&follow=true&execution=3",
+	}, queries)
+}
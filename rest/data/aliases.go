@@ -1,7 +1,14 @@
 package data
 
 import (
+	"net/http"
+	"strings"
+
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
 )
 
 // DBAliasConnector is a struct that implements the Alias related methods
@@ -20,11 +27,64 @@ func (d *DBAliasConnector) FindProjectAliases(projectId string) ([]model.Project
 	return aliases, nil
 }
 
+// UpdateProjectAliases validates a project's aliases and replaces its
+// existing aliases with them.
+func (d *DBAliasConnector) UpdateProjectAliases(projectId string, aliases []model.ProjectAlias) error {
+	if errs := model.ValidateProjectAliases(aliases); len(errs) > 0 {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    strings.Join(errs, ", "),
+		}
+	}
+
+	if err := model.RemoveProjectAliasesForProject(projectId); err != nil {
+		return errors.WithStack(err)
+	}
+	catcher := grip.NewBasicCatcher()
+	for i := range aliases {
+		aliases[i].ID = bson.ObjectId("")
+		aliases[i].ProjectID = projectId
+		catcher.Add(aliases[i].Upsert())
+	}
+	return catcher.Resolve()
+}
+
 // MockAliasConnector is a struct that implements mock versions of
 // Alias-related methods for testing.
-type MockAliasConnector struct{}
+type MockAliasConnector struct {
+	CachedAliases []model.ProjectAlias
+}
 
 // FindAllAliases is a mock implementation for testing.
 func (d *MockAliasConnector) FindProjectAliases(projectId string) ([]model.ProjectAlias, error) {
-	return nil, nil
+	var aliases []model.ProjectAlias
+	for _, alias := range d.CachedAliases {
+		if alias.ProjectID == projectId {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases, nil
+}
+
+// UpdateProjectAliases replaces the cached aliases of a project.
+func (d *MockAliasConnector) UpdateProjectAliases(projectId string, aliases []model.ProjectAlias) error {
+	if errs := model.ValidateProjectAliases(aliases); len(errs) > 0 {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    strings.Join(errs, ", "),
+		}
+	}
+
+	cached := []model.ProjectAlias{}
+	for _, alias := range d.CachedAliases {
+		if alias.ProjectID != projectId {
+			cached = append(cached, alias)
+		}
+	}
+	for _, alias := range aliases {
+		alias.ProjectID = projectId
+		cached = append(cached, alias)
+	}
+	d.CachedAliases = cached
+	return nil
 }
@@ -0,0 +1,243 @@
+package data
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/commitqueue"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/google/go-github/github"
+	"github.com/pkg/errors"
+)
+
+// DBCommitQueueConnector is a struct that implements the commit queue related
+// methods from the Connector through interactions with the backing database.
+type DBCommitQueueConnector struct{}
+
+// GetGitHubPR fetches a pull request from GitHub.
+func (cq *DBCommitQueueConnector) GetGitHubPR(ctx context.Context, owner, repo string, prNumber int) (*github.PullRequest, error) {
+	githubToken, err := evergreen.GetEnvironment().Settings().GetGithubOauthToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "can't get github oauth token")
+	}
+
+	pr, err := thirdparty.GetGithubPullRequest(ctx, githubToken, owner, repo, prNumber)
+	return pr, errors.WithStack(err)
+}
+
+// IsGitHubCommitter returns true if the GitHub user can push to the
+// repository.
+func (cq *DBCommitQueueConnector) IsGitHubCommitter(ctx context.Context, owner, repo, login string) (bool, error) {
+	githubToken, err := evergreen.GetEnvironment().Settings().GetGithubOauthToken()
+	if err != nil {
+		return false, errors.Wrap(err, "can't get github oauth token")
+	}
+
+	isCommitter, err := thirdparty.GithubUserHasWriteAccess(ctx, githubToken, owner, repo, login)
+	return isCommitter, errors.WithStack(err)
+}
+
+// GetProjectWithCommitQueueByOwnerRepoAndBranch returns the project tracking
+// the branch that has the commit queue enabled, or nil if there is none.
+func (cq *DBCommitQueueConnector) GetProjectWithCommitQueueByOwnerRepoAndBranch(owner, repo, branch string) (*model.ProjectRef, error) {
+	ref, err := model.FindOneProjectRefWithCommitQueueByOwnerRepoAndBranch(owner, repo, branch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't query for project with commit queue for %s/%s:%s", owner, repo, branch)
+	}
+	return ref, nil
+}
+
+// FindCommitQueueByID returns the commit queue of a project. A project whose
+// queue has never been used has an empty queue.
+func (cq *DBCommitQueueConnector) FindCommitQueueByID(projectID string) (*commitqueue.CommitQueue, error) {
+	queue, err := commitqueue.FindOneId(projectID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't query for commit queue '%s'", projectID)
+	}
+	if queue == nil {
+		return &commitqueue.CommitQueue{ProjectID: projectID}, nil
+	}
+	return queue, nil
+}
+
+// EnqueueItem adds an item to the commit queue of a project, creating the
+// queue if necessary, and returns the item's position.
+func (cq *DBCommitQueueConnector) EnqueueItem(projectID string, item commitqueue.CommitQueueItem) (int, error) {
+	ref, err := model.FindOneProjectRef(projectID)
+	if err != nil {
+		return -1, errors.Wrapf(err, "can't query for project '%s'", projectID)
+	}
+	if ref == nil {
+		return -1, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "project not found",
+		}
+	}
+	if !ref.CommitQueue.Enabled {
+		return -1, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "commit queue is not enabled for project",
+		}
+	}
+
+	queue, err := commitqueue.FindOneId(projectID)
+	if err != nil {
+		return -1, errors.Wrapf(err, "can't query for commit queue '%s'", projectID)
+	}
+	if queue == nil {
+		queue = &commitqueue.CommitQueue{ProjectID: projectID}
+		if err = commitqueue.InsertQueue(queue); err != nil {
+			return -1, errors.Wrapf(err, "can't create commit queue '%s'", projectID)
+		}
+	}
+
+	position, err := queue.Enqueue(item)
+	if err != nil {
+		return -1, errors.WithStack(err)
+	}
+	return position, nil
+}
+
+// CommitQueueRemoveItem removes an item from the commit queue of a project.
+// It returns false if the item was not in the queue.
+func (cq *DBCommitQueueConnector) CommitQueueRemoveItem(projectID, item string) (bool, error) {
+	queue, err := cq.FindCommitQueueByID(projectID)
+	if err != nil {
+		return false, err
+	}
+
+	removed, err := queue.Remove(item)
+	return removed, errors.WithStack(err)
+}
+
+// CommitQueueMoveItem moves an item to a new position in the commit queue of
+// a project.
+func (cq *DBCommitQueueConnector) CommitQueueMoveItem(projectID, item string, position int) error {
+	queue, err := cq.FindCommitQueueByID(projectID)
+	if err != nil {
+		return err
+	}
+	if queue.FindItem(item) < 0 {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "item not in commit queue",
+		}
+	}
+
+	if err = queue.Move(item, position); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	return nil
+}
+
+// MockCommitQueueConnector is a struct that implements mock versions of the
+// commit queue related methods for testing.
+type MockCommitQueueConnector struct {
+	CachedCommitQueues    map[string]*commitqueue.CommitQueue
+	CachedPullRequests    map[int]*github.PullRequest
+	CachedCommitQueueRefs []model.ProjectRef
+	CachedCommitters      []string
+}
+
+// GetGitHubPR returns the cached pull request with the given number.
+func (cq *MockCommitQueueConnector) GetGitHubPR(ctx context.Context, owner, repo string, prNumber int) (*github.PullRequest, error) {
+	pr, ok := cq.CachedPullRequests[prNumber]
+	if !ok {
+		return nil, errors.Errorf("pull request %d not found", prNumber)
+	}
+	return pr, nil
+}
+
+// IsGitHubCommitter returns true if the GitHub user is one of the cached
+// committers.
+func (cq *MockCommitQueueConnector) IsGitHubCommitter(ctx context.Context, owner, repo, login string) (bool, error) {
+	return util.StringSliceContains(cq.CachedCommitters, login), nil
+}
+
+// GetProjectWithCommitQueueByOwnerRepoAndBranch returns the cached project
+// ref tracking the branch that has the commit queue enabled.
+func (cq *MockCommitQueueConnector) GetProjectWithCommitQueueByOwnerRepoAndBranch(owner, repo, branch string) (*model.ProjectRef, error) {
+	for i := range cq.CachedCommitQueueRefs {
+		ref := cq.CachedCommitQueueRefs[i]
+		if ref.Owner == owner && ref.Repo == repo && ref.Branch == branch && ref.CommitQueue.Enabled {
+			return &ref, nil
+		}
+	}
+	return nil, nil
+}
+
+// FindCommitQueueByID returns the cached commit queue of a project.
+func (cq *MockCommitQueueConnector) FindCommitQueueByID(projectID string) (*commitqueue.CommitQueue, error) {
+	queue, ok := cq.CachedCommitQueues[projectID]
+	if !ok {
+		return &commitqueue.CommitQueue{ProjectID: projectID}, nil
+	}
+	return queue, nil
+}
+
+// EnqueueItem adds an item to the cached commit queue of a project.
+func (cq *MockCommitQueueConnector) EnqueueItem(projectID string, item commitqueue.CommitQueueItem) (int, error) {
+	if cq.CachedCommitQueues == nil {
+		cq.CachedCommitQueues = map[string]*commitqueue.CommitQueue{}
+	}
+	queue, ok := cq.CachedCommitQueues[projectID]
+	if !ok {
+		queue = &commitqueue.CommitQueue{ProjectID: projectID}
+		cq.CachedCommitQueues[projectID] = queue
+	}
+
+	if position := queue.FindItem(item.Issue); position >= 0 {
+		return position, nil
+	}
+	queue.Queue = append(queue.Queue, item)
+	return len(queue.Queue) - 1, nil
+}
+
+// CommitQueueRemoveItem removes an item from the cached commit queue of a
+// project.
+func (cq *MockCommitQueueConnector) CommitQueueRemoveItem(projectID, item string) (bool, error) {
+	queue, ok := cq.CachedCommitQueues[projectID]
+	if !ok {
+		return false, nil
+	}
+
+	position := queue.FindItem(item)
+	if position < 0 {
+		return false, nil
+	}
+	queue.Queue = append(queue.Queue[:position], queue.Queue[position+1:]...)
+	return true, nil
+}
+
+// CommitQueueMoveItem moves an item in the cached commit queue of a project.
+func (cq *MockCommitQueueConnector) CommitQueueMoveItem(projectID, item string, position int) error {
+	queue, ok := cq.CachedCommitQueues[projectID]
+	if !ok || queue.FindItem(item) < 0 {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "item not in commit queue",
+		}
+	}
+	if position < 0 || position >= len(queue.Queue) {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "position out of range",
+		}
+	}
+
+	current := queue.FindItem(item)
+	moved := queue.Queue[current]
+	reordered := append([]commitqueue.CommitQueueItem{}, queue.Queue[:current]...)
+	reordered = append(reordered, queue.Queue[current+1:]...)
+	reordered = append(reordered[:position], append([]commitqueue.CommitQueueItem{moved}, reordered[position:]...)...)
+	queue.Queue = reordered
+
+	return nil
+}
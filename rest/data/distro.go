@@ -1,15 +1,22 @@
 package data
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/validator"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
 )
 
 // DBDistroConnector is a struct that implements the Distro related methods
@@ -73,11 +80,163 @@ func (tc *DBDistroConnector) ClearTaskQueue(distroId string) error {
 	return model.ClearTaskQueue(distroId)
 }
 
+// FindDistroById returns the distro with the given ID.
+func (dc *DBDistroConnector) FindDistroById(id string) (*distro.Distro, error) {
+	d, err := distro.FindOne(distro.ById(id))
+	if err != nil {
+		if db.ResultsNotFound(err) {
+			return nil, distroNotFoundError(id)
+		}
+		return nil, errors.Wrapf(err, "problem finding distro '%s'", id)
+	}
+	return &d, nil
+}
+
+// ValidateDistro checks a distro with the distro validator. New distros must
+// also have a unique ID. Only errors, not warnings, make a distro invalid.
+func (dc *DBDistroConnector) ValidateDistro(ctx context.Context, d *distro.Distro, isNew bool) error {
+	vErrs, err := validator.CheckDistro(ctx, d, evergreen.GetEnvironment().Settings(), isNew)
+	if err != nil {
+		return errors.Wrapf(err, "problem validating distro '%s'", d.Id)
+	}
+	return distroValidationError(vErrs)
+}
+
+// CreateDistro validates a new distro, saves it, and logs its creation.
+func (dc *DBDistroConnector) CreateDistro(ctx context.Context, d *distro.Distro, userID string) error {
+	if err := dc.ValidateDistro(ctx, d, true); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := d.Insert(); err != nil {
+		return errors.Wrapf(err, "problem inserting distro '%s'", d.Id)
+	}
+
+	event.LogDistroAdded(d.Id, userID, *d)
+	return nil
+}
+
+// UpdateDistro validates an existing distro, saves it, and logs the change.
+// If decommission is set, the distro's hosts are decommissioned, so that
+// they are replaced by hosts with the new settings.
+func (dc *DBDistroConnector) UpdateDistro(ctx context.Context, d *distro.Distro, userID string, decommission bool) error {
+	if err := dc.ValidateDistro(ctx, d, false); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := d.Update(); err != nil {
+		return errors.Wrapf(err, "problem updating distro '%s'", d.Id)
+	}
+	event.LogDistroModified(d.Id, userID, *d)
+
+	if !decommission {
+		return nil
+	}
+	hosts, err := dc.FindDistroHosts(d.Id)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err = host.DecommissionHostsWithDistroId(d.Id); err != nil {
+		return errors.Wrapf(err, "problem decommissioning hosts of distro '%s'", d.Id)
+	}
+	for _, h := range hosts {
+		event.LogHostStatusChanged(h.Id, h.Status, evergreen.HostDecommissioned, userID, evergreen.RESTV2Package)
+	}
+
+	return nil
+}
+
+// DeleteDistro removes a distro and logs its removal.
+func (dc *DBDistroConnector) DeleteDistro(id, userID string) error {
+	d, err := dc.FindDistroById(id)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err = distro.Remove(id); err != nil {
+		return errors.Wrapf(err, "problem removing distro '%s'", id)
+	}
+
+	event.LogDistroRemoved(id, userID, *d)
+	return nil
+}
+
+// FindDistroHosts returns the running hosts of a distro, which are the hosts
+// that are decommissioned when the distro is updated.
+func (dc *DBDistroConnector) FindDistroHosts(id string) ([]host.Host, error) {
+	hosts, err := host.Find(host.ByDistroId(id))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding hosts of distro '%s'", id)
+	}
+	return hosts, nil
+}
+
+// FindDistroRevisions returns the revisions of a distro from its events, in
+// the order that they happened.
+func (dc *DBDistroConnector) FindDistroRevisions(id string) ([]distro.Revision, error) {
+	events, err := event.Find(event.AllLogCollection, event.DistroEventsInOrder(id))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding events of distro '%s'", id)
+	}
+
+	revisions := []distro.Revision{}
+	for _, e := range events {
+		data, ok := e.Data.(*event.DistroEventData)
+		if !ok {
+			return nil, errors.Errorf("event '%s' of distro '%s' has data of type %T", e.ID, id, e.Data)
+		}
+
+		// the distro is stored as a generic document, so it is converted
+		// back through BSON
+		var d distro.Distro
+		out, err := bson.Marshal(data.Data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem reading event '%s' of distro '%s'", e.ID, id)
+		}
+		if err = bson.Unmarshal(out, &d); err != nil {
+			return nil, errors.Wrapf(err, "problem reading event '%s' of distro '%s'", e.ID, id)
+		}
+
+		revisions = append(revisions, distro.Revision{
+			Number:    len(revisions) + 1,
+			EventType: e.EventType,
+			User:      data.UserId,
+			Timestamp: e.Timestamp,
+			Distro:    d,
+		})
+	}
+
+	return revisions, nil
+}
+
+func distroNotFoundError(id string) error {
+	return gimlet.ErrorResponse{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("distro '%s' not found", id),
+	}
+}
+
+func distroValidationError(vErrs []validator.ValidationError) error {
+	errs := []validator.ValidationError{}
+	for _, e := range vErrs {
+		if e.Level == validator.Error {
+			errs = append(errs, e)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return gimlet.ErrorResponse{
+		StatusCode: http.StatusBadRequest,
+		Message:    fmt.Sprintf("distro is invalid: %s", validator.ValidationErrorsToString(errs)),
+	}
+}
+
 // MockDistroConnector is a struct that implements mock versions of
 // Distro-related methods for testing.
 type MockDistroConnector struct {
-	CachedDistros []distro.Distro
-	CachedTasks   []task.Task
+	CachedDistros     []distro.Distro
+	CachedTasks       []task.Task
+	CachedDistroHosts []host.Host
+	CachedRevisions   []distro.Revision
 }
 
 // FindAllDistros is a mock implementation for testing.
@@ -126,3 +285,110 @@ func (mdc *MockDistroConnector) FindCostByDistroId(distroId string,
 func (mdc *MockDistroConnector) ClearTaskQueue(distroId string) error {
 	return errors.New("ClearTaskQueue unimplemented for mock")
 }
+
+// FindDistroById returns the cached distro with the given ID.
+func (mdc *MockDistroConnector) FindDistroById(id string) (*distro.Distro, error) {
+	for _, d := range mdc.CachedDistros {
+		if d.Id == id {
+			return &d, nil
+		}
+	}
+	return nil, distroNotFoundError(id)
+}
+
+// ValidateDistro only checks that a distro has an ID, and that new distros
+// have a unique one.
+func (mdc *MockDistroConnector) ValidateDistro(ctx context.Context, d *distro.Distro, isNew bool) error {
+	if d.Id == "" {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "distro is invalid: distro '_id' cannot be blank",
+		}
+	}
+	if _, err := mdc.FindDistroById(d.Id); isNew && err == nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("distro is invalid: distro '%s' already exists", d.Id),
+		}
+	}
+	return nil
+}
+
+// CreateDistro adds a distro to the cached distros.
+func (mdc *MockDistroConnector) CreateDistro(ctx context.Context, d *distro.Distro, userID string) error {
+	if err := mdc.ValidateDistro(ctx, d, true); err != nil {
+		return errors.WithStack(err)
+	}
+	mdc.CachedDistros = append(mdc.CachedDistros, *d)
+	mdc.addRevision(event.EventDistroAdded, userID, *d)
+	return nil
+}
+
+// UpdateDistro replaces a cached distro. Decommissioned hosts are removed
+// from the cached hosts.
+func (mdc *MockDistroConnector) UpdateDistro(ctx context.Context, d *distro.Distro, userID string, decommission bool) error {
+	if err := mdc.ValidateDistro(ctx, d, false); err != nil {
+		return errors.WithStack(err)
+	}
+	for i := range mdc.CachedDistros {
+		if mdc.CachedDistros[i].Id == d.Id {
+			mdc.CachedDistros[i] = *d
+			mdc.addRevision(event.EventDistroModified, userID, *d)
+			if decommission {
+				hosts := []host.Host{}
+				for _, h := range mdc.CachedDistroHosts {
+					if h.Distro.Id != d.Id {
+						hosts = append(hosts, h)
+					}
+				}
+				mdc.CachedDistroHosts = hosts
+			}
+			return nil
+		}
+	}
+	return distroNotFoundError(d.Id)
+}
+
+// DeleteDistro removes a cached distro.
+func (mdc *MockDistroConnector) DeleteDistro(id, userID string) error {
+	for i := range mdc.CachedDistros {
+		if mdc.CachedDistros[i].Id == id {
+			mdc.addRevision(event.EventDistroRemoved, userID, mdc.CachedDistros[i])
+			mdc.CachedDistros = append(mdc.CachedDistros[:i], mdc.CachedDistros[i+1:]...)
+			return nil
+		}
+	}
+	return distroNotFoundError(id)
+}
+
+// FindDistroHosts returns the cached hosts of a distro.
+func (mdc *MockDistroConnector) FindDistroHosts(id string) ([]host.Host, error) {
+	hosts := []host.Host{}
+	for _, h := range mdc.CachedDistroHosts {
+		if h.Distro.Id == id {
+			hosts = append(hosts, h)
+		}
+	}
+	return hosts, nil
+}
+
+// FindDistroRevisions returns the cached revisions of a distro.
+func (mdc *MockDistroConnector) FindDistroRevisions(id string) ([]distro.Revision, error) {
+	revisions := []distro.Revision{}
+	for _, r := range mdc.CachedRevisions {
+		if r.Distro.Id == id {
+			r.Number = len(revisions) + 1
+			revisions = append(revisions, r)
+		}
+	}
+	return revisions, nil
+}
+
+func (mdc *MockDistroConnector) addRevision(eventType, userID string, d distro.Distro) {
+	mdc.CachedRevisions = append(mdc.CachedRevisions, distro.Revision{
+		EventType: eventType,
+		User:      userID,
+		Timestamp: time.Now(),
+		Distro:    d,
+	})
+}
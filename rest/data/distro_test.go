@@ -8,9 +8,11 @@ import (
 
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -36,6 +38,40 @@ func TestFindAllDistros(t *testing.T) {
 	assert.Len(found, numDistros)
 }
 
+func TestFindDistroRevisions(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	testutil.ConfigureIntegrationTest(t, testConfig, "TestFindDistroRevisions")
+	db.SetGlobalSessionProvider(testConfig.SessionFactory())
+	require.NoError(db.Clear(event.AllLogCollection))
+
+	sc := &DBConnector{}
+
+	d := distro.Distro{Id: "d", Arch: "linux_amd64", PoolSize: 1}
+	event.LogDistroAdded(d.Id, "me", d)
+	d.PoolSize = 2
+	event.LogDistroModified(d.Id, "you", d)
+	event.LogDistroRemoved(d.Id, "me", d)
+	event.LogDistroAdded("other", "me", distro.Distro{Id: "other"})
+
+	revisions, err := sc.FindDistroRevisions("d")
+	require.NoError(err)
+	require.Len(revisions, 3)
+	for i, revision := range revisions {
+		assert.Equal(i+1, revision.Number)
+		assert.Equal("d", revision.Distro.Id)
+	}
+	assert.Equal(event.EventDistroAdded, revisions[0].EventType)
+	assert.Equal(1, revisions[0].Distro.PoolSize)
+	assert.Equal("you", revisions[1].User)
+	assert.Equal(2, revisions[1].Distro.PoolSize)
+	assert.Equal(event.EventDistroRemoved, revisions[2].EventType)
+
+	revisions, err = sc.FindDistroRevisions("nonexistent")
+	assert.NoError(err)
+	assert.Empty(revisions)
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 type DistroCostConnectorSuite struct {
 	ctx       Connector
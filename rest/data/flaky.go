@@ -0,0 +1,67 @@
+package data
+
+import (
+	"github.com/evergreen-ci/evergreen/model/flaky"
+	"github.com/pkg/errors"
+)
+
+// DBFlakyTestConnector is a struct that implements the flaky test related
+// methods from the Connector through interactions with the backing database.
+type DBFlakyTestConnector struct{}
+
+// FindFlakyTests returns the flaky and quarantined tests of a project.
+func (fc *DBFlakyTestConnector) FindFlakyTests(projectId string) ([]flaky.FlakyTest, error) {
+	tests, err := flaky.Find(flaky.ByProject(projectId))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding flaky tests for project '%s'", projectId)
+	}
+	return tests, nil
+}
+
+// SetTestQuarantined quarantines or releases a test of a task in a project.
+func (fc *DBFlakyTestConnector) SetTestQuarantined(projectId, taskName, testFile string, quarantined bool, user string) error {
+	return errors.WithStack(flaky.SetQuarantined(projectId, taskName, testFile, quarantined, user))
+}
+
+// MockFlakyTestConnector is a struct that implements mock versions of the
+// flaky test related methods for testing.
+type MockFlakyTestConnector struct {
+	CachedFlakyTests []flaky.FlakyTest
+}
+
+// FindFlakyTests returns the cached tests that belong to the project.
+func (fc *MockFlakyTestConnector) FindFlakyTests(projectId string) ([]flaky.FlakyTest, error) {
+	tests := []flaky.FlakyTest{}
+	for _, test := range fc.CachedFlakyTests {
+		if test.Project == projectId {
+			tests = append(tests, test)
+		}
+	}
+	return tests, nil
+}
+
+// SetTestQuarantined updates the cached test, adding it if it is not cached.
+func (fc *MockFlakyTestConnector) SetTestQuarantined(projectId, taskName, testFile string, quarantined bool, user string) error {
+	if projectId == "" || taskName == "" || testFile == "" {
+		return errors.New("project, task name, and test file must all be specified")
+	}
+
+	id := flaky.MakeId(projectId, taskName, testFile)
+	for i := range fc.CachedFlakyTests {
+		if fc.CachedFlakyTests[i].Id == id {
+			fc.CachedFlakyTests[i].Quarantined = quarantined
+			fc.CachedFlakyTests[i].QuarantinedBy = user
+			return nil
+		}
+	}
+
+	fc.CachedFlakyTests = append(fc.CachedFlakyTests, flaky.FlakyTest{
+		Id:            id,
+		Project:       projectId,
+		TaskName:      taskName,
+		TestFile:      testFile,
+		Quarantined:   quarantined,
+		QuarantinedBy: user,
+	})
+	return nil
+}
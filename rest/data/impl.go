@@ -30,6 +30,10 @@ type DBConnector struct {
 	DBSubscriptionConnector
 	NotificationConnector
 	DBCreateHostConnector
+	DBFlakyTestConnector
+	DBCommitQueueConnector
+	DBTaskLogConnector
+	DBRoleConnector
 }
 
 func (ctx *DBConnector) GetSuperUsers() []string   { return ctx.superUsers }
@@ -65,6 +69,10 @@ type MockConnector struct {
 	MockSubscriptionConnector
 	MockNotificationConnector
 	MockCreateHostConnector
+	MockFlakyTestConnector
+	MockCommitQueueConnector
+	MockTaskLogConnector
+	MockRoleConnector
 }
 
 func (ctx *MockConnector) GetSuperUsers() []string   { return ctx.superUsers }
@@ -9,14 +9,18 @@ import (
 	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/commitqueue"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/flaky"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/role"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/testresult"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/repotracker"
 	restModel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/google/go-github/github"
@@ -70,6 +74,22 @@ type Connector interface {
 
 	// FindProjects is a method to find projects as ordered by name
 	FindProjects(string, int, int, bool) ([]model.ProjectRef, error)
+	// FindProjectById returns the project with the given identifier.
+	FindProjectById(string) (*model.ProjectRef, error)
+	// CreateProject validates and saves a new project.
+	CreateProject(*model.ProjectRef) error
+	// UpdateProject validates and saves the settings of an existing project.
+	UpdateProject(*model.ProjectRef) error
+	// CopyProject creates a project with the settings, variables, and
+	// aliases of an existing one.
+	CopyProject(string, string) (*model.ProjectRef, error)
+	// DeleteProject removes a project and its variables and aliases.
+	DeleteProject(string) error
+	// FindProjectVars returns the variables of a project.
+	FindProjectVars(string) (*model.ProjectVars, error)
+	// UpdateProjectVars sets the given variables of a project and deletes
+	// the named ones.
+	UpdateProjectVars(string, *model.ProjectVars, []string) error
 	// FindProjectByBranch is a method to find the projectref given a branch name.
 	FindProjectByBranch(string) (*model.ProjectRef, error)
 	// GetVersionsAndVariants returns recent versions for a project
@@ -87,6 +107,9 @@ type Connector interface {
 
 	// FindUserById is a method to find a specific user given its ID.
 	FindUserById(string) (gimlet.User, error)
+	// FindUserByGithubUID returns the user who linked the GitHub account
+	// with the given ID, or nil if there is none.
+	FindUserByGithubUID(int) (*user.DBUser, error)
 
 	// FindHostsById is a method to find a sorted list of hosts given an ID to
 	// start from.
@@ -107,6 +130,21 @@ type Connector interface {
 
 	// FindAllDistros is a method to find a sorted list of all distros.
 	FindAllDistros() ([]distro.Distro, error)
+	// FindDistroById returns the distro with the given ID.
+	FindDistroById(string) (*distro.Distro, error)
+	// ValidateDistro checks that a distro, which may be new, is valid.
+	ValidateDistro(context.Context, *distro.Distro, bool) error
+	// CreateDistro validates and saves a new distro.
+	CreateDistro(context.Context, *distro.Distro, string) error
+	// UpdateDistro validates and saves an existing distro, optionally
+	// decommissioning its hosts.
+	UpdateDistro(context.Context, *distro.Distro, string, bool) error
+	// DeleteDistro removes a distro.
+	DeleteDistro(string, string) error
+	// FindDistroHosts returns the running hosts of a distro.
+	FindDistroHosts(string) ([]host.Host, error)
+	// FindDistroRevisions returns the revisions of a distro, oldest first.
+	FindDistroRevisions(string) ([]distro.Revision, error)
 
 	// FindTaskSystemMetrics and FindTaskProcessMetrics provide
 	// access to the metrics data collected by agents during task execution
@@ -146,6 +184,12 @@ type Connector interface {
 
 	// RestartVersion restarts all completed tasks of a version given its ID and the caller.
 	RestartVersion(string, string) error
+	// FindVersionTaskGraph returns the task dependency graph of a version
+	// given its ID.
+	FindVersionTaskGraph(string) (*task.DependencyGraph, error)
+	// CreateManualVersion starts a version of a revision of the given
+	// project with user supplied parameters.
+	CreateManualVersion(string, repotracker.ManualVersionOptions) (*version.Version, error)
 	// SetPatchPriority and SetPatchActivated change the status of the input patch
 	SetPatchPriority(string, int64) error
 	SetPatchActivated(string, string, bool) error
@@ -185,6 +229,8 @@ type Connector interface {
 
 	// FindProjectAliases queries the database to find all aliases.
 	FindProjectAliases(string) ([]model.ProjectAlias, error)
+	// UpdateProjectAliases replaces the aliases of a project.
+	UpdateProjectAliases(string, []model.ProjectAlias) error
 
 	// TriggerRepotracker creates an amboy job to get the commits from a
 	// Github Push Event
@@ -209,4 +255,52 @@ type Connector interface {
 	ListHostsForTask(string) ([]host.Host, error)
 	MakeIntentHost(string, string, string, apimodels.CreateHost) (*host.Host, error)
 	CreateHostsFromTask(*task.Task, user.DBUser, string) error
+
+	// FindFlakyTests returns the flaky and quarantined tests of a project.
+	FindFlakyTests(string) ([]flaky.FlakyTest, error)
+	// SetTestQuarantined quarantines or releases a test of a task in a
+	// project on behalf of a user.
+	SetTestQuarantined(string, string, string, bool, string) error
+
+	// GetGitHubPR fetches a pull request from GitHub.
+	GetGitHubPR(context.Context, string, string, int) (*github.PullRequest, error)
+	// IsGitHubCommitter returns true if a GitHub user can push to a
+	// repository.
+	IsGitHubCommitter(context.Context, string, string, string) (bool, error)
+	// GetProjectWithCommitQueueByOwnerRepoAndBranch returns the project
+	// tracking a branch that has the commit queue enabled.
+	GetProjectWithCommitQueueByOwnerRepoAndBranch(string, string, string) (*model.ProjectRef, error)
+	// FindCommitQueueByID returns the commit queue of a project.
+	FindCommitQueueByID(string) (*commitqueue.CommitQueue, error)
+	// EnqueueItem adds an item to a project's commit queue and returns its
+	// position.
+	EnqueueItem(string, commitqueue.CommitQueueItem) (int, error)
+	// CommitQueueRemoveItem removes an item from a project's commit queue.
+	CommitQueueRemoveItem(string, string) (bool, error)
+	// CommitQueueMoveItem moves an item to a position in a project's commit
+	// queue.
+	CommitQueueMoveItem(string, string, int) error
+
+	// GetTaskLogMessagesFromOffset returns the messages of a task
+	// execution's log of the given types, starting at an offset, and the
+	// offset that follows them.
+	GetTaskLogMessagesFromOffset(string, int, int, []string) ([]apimodels.LogMessage, int, error)
+
+	// FindRoles returns every role, including the built-in roles.
+	FindRoles() ([]role.Role, error)
+	// UpdateRole creates or replaces a role on behalf of a user.
+	UpdateRole(*role.Role, string) error
+	// RemoveRole removes a role on behalf of a user.
+	RemoveRole(string, string) error
+	// FindRoleAssignments returns the roles assigned to a user.
+	FindRoleAssignments(string) ([]role.Assignment, error)
+	// AssignRole grants a role to a user on a project, or globally if the
+	// project is empty, on behalf of another user.
+	AssignRole(string, string, string, string) error
+	// UnassignRole revokes a role granted to a user on a project, or
+	// globally if the project is empty, on behalf of another user.
+	UnassignRole(string, string, string, string) error
+	// UserHasPermission returns true if a user holds a permission on a
+	// project, or globally if the project is empty.
+	UserHasPermission(string, string, string) (bool, error)
 }
@@ -1,8 +1,14 @@
 package data
 
 import (
+	"fmt"
+	"net/http"
+
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
 )
 
 // DBPatchConnector is a struct that implements the Patch related methods
@@ -19,6 +25,219 @@ func (pc *DBProjectConnector) FindProjects(key string, limit int, sortDir int, i
 	return projects, nil
 }
 
+// FindProjectById returns the project with the given identifier.
+func (pc *DBProjectConnector) FindProjectById(id string) (*model.ProjectRef, error) {
+	projectRef, err := model.FindOneProjectRef(id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding project '%s'", id)
+	}
+	if projectRef == nil {
+		return nil, projectNotFoundError(id)
+	}
+	return projectRef, nil
+}
+
+// CreateProject validates the settings of a new project and saves it, with
+// no variables.
+func (pc *DBProjectConnector) CreateProject(projectRef *model.ProjectRef) error {
+	existing, err := model.FindOneProjectRef(projectRef.Identifier)
+	if err != nil {
+		return errors.Wrapf(err, "problem finding project '%s'", projectRef.Identifier)
+	}
+	if existing != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("project '%s' already exists", projectRef.Identifier),
+		}
+	}
+	if err = projectRef.ValidateSettings(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	if err = projectRef.Insert(); err != nil {
+		return errors.Wrapf(err, "problem inserting project '%s'", projectRef.Identifier)
+	}
+	vars := model.ProjectVars{Id: projectRef.Identifier}
+	return errors.Wrapf(vars.Insert(), "problem inserting variables of project '%s'", projectRef.Identifier)
+}
+
+// UpdateProject validates the settings of an existing project and saves
+// them.
+func (pc *DBProjectConnector) UpdateProject(projectRef *model.ProjectRef) error {
+	existing, err := pc.FindProjectById(projectRef.Identifier)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err = projectRef.ValidateSettings(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	// the repotracker's state is not a setting
+	projectRef.LocalConfig = existing.LocalConfig
+	projectRef.RepotrackerError = existing.RepotrackerError
+
+	return errors.Wrapf(projectRef.Upsert(), "problem updating project '%s'", projectRef.Identifier)
+}
+
+// CopyProject creates a project with the settings, variables, and aliases of
+// an existing one. The copy is disabled, and does not test pull requests or
+// have a commit queue, since only one project tracking a branch can.
+func (pc *DBProjectConnector) CopyProject(oldId, newId string) (*model.ProjectRef, error) {
+	projectRef, err := pc.FindProjectById(oldId)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	projectRef.Identifier = newId
+	projectRef.Enabled = false
+	projectRef.PRTestingEnabled = false
+	projectRef.CommitQueue.Enabled = false
+	projectRef.RepotrackerError = nil
+	existing, err := model.FindOneProjectRef(newId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding project '%s'", newId)
+	}
+	if existing != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("project '%s' already exists", newId),
+		}
+	}
+	if err = projectRef.Insert(); err != nil {
+		return nil, errors.Wrapf(err, "problem inserting project '%s'", newId)
+	}
+
+	vars, err := model.FindOneProjectVars(oldId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding variables of project '%s'", oldId)
+	}
+	if vars == nil {
+		vars = &model.ProjectVars{}
+	}
+	vars.Id = newId
+	if err = vars.Insert(); err != nil {
+		return nil, errors.Wrapf(err, "problem inserting variables of project '%s'", newId)
+	}
+
+	aliases, err := model.FindAliasesForProject(oldId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding aliases of project '%s'", oldId)
+	}
+	catcher := grip.NewBasicCatcher()
+	for i := range aliases {
+		aliases[i].ID = bson.ObjectId("")
+		aliases[i].ProjectID = newId
+		catcher.Add(aliases[i].Upsert())
+	}
+	if catcher.HasErrors() {
+		return nil, errors.Wrapf(catcher.Resolve(), "problem copying aliases to project '%s'", newId)
+	}
+
+	return projectRef, nil
+}
+
+// DeleteProject removes a project and its variables and aliases.
+func (pc *DBProjectConnector) DeleteProject(id string) error {
+	if _, err := pc.FindProjectById(id); err != nil {
+		return errors.WithStack(err)
+	}
+
+	catcher := grip.NewBasicCatcher()
+	catcher.Add(model.RemoveProjectVars(id))
+	catcher.Add(model.RemoveProjectAliasesForProject(id))
+	catcher.Add(model.RemoveProjectRef(id))
+	return catcher.Resolve()
+}
+
+// FindProjectVars returns the variables of a project.
+func (pc *DBProjectConnector) FindProjectVars(id string) (*model.ProjectVars, error) {
+	vars, err := model.FindOneProjectVars(id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding variables of project '%s'", id)
+	}
+	if vars == nil {
+		vars = &model.ProjectVars{Id: id}
+	}
+	return vars, nil
+}
+
+// UpdateProjectVars sets and deletes variables of a project, leaving the
+// others unchanged.
+func (pc *DBProjectConnector) UpdateProjectVars(id string, update *model.ProjectVars, toDelete []string) error {
+	vars, err := pc.FindProjectVars(id)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err = mergeProjectVars(vars, update, toDelete); err != nil {
+		return errors.WithStack(err)
+	}
+	if err = vars.ValidateSecretVars(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	_, err = vars.Upsert()
+	return errors.Wrapf(err, "problem updating variables of project '%s'", id)
+}
+
+// mergeProjectVars applies an update to a project's variables. Private
+// variables are redacted when read, so a private variable that is set to the
+// empty string keeps its value, and a private variable can only be made
+// public along with a new value. Secret variables are not exposed through
+// the API, so they are left unchanged.
+func mergeProjectVars(vars, update *model.ProjectVars, toDelete []string) error {
+	if vars.Vars == nil {
+		vars.Vars = map[string]string{}
+	}
+	if vars.PrivateVars == nil {
+		vars.PrivateVars = map[string]bool{}
+	}
+
+	for k, private := range update.PrivateVars {
+		if _, ok := vars.Vars[k]; ok && !private && vars.PrivateVars[k] && update.Vars[k] == "" {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("private variable '%s' cannot be made public without a new value", k),
+			}
+		}
+	}
+
+	for k, v := range update.Vars {
+		if _, ok := vars.Vars[k]; ok && v == "" && vars.PrivateVars[k] {
+			continue
+		}
+		vars.Vars[k] = v
+	}
+	for k, private := range update.PrivateVars {
+		if private {
+			vars.PrivateVars[k] = true
+		} else {
+			delete(vars.PrivateVars, k)
+		}
+	}
+	for _, k := range toDelete {
+		delete(vars.Vars, k)
+		delete(vars.PrivateVars, k)
+	}
+
+	return nil
+}
+
+func projectNotFoundError(id string) error {
+	return gimlet.ErrorResponse{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("project '%s' not found", id),
+	}
+}
+
 // MockPatchConnector is a struct that implements the Patch related methods
 // from the Connector through interactions with he backing database.
 type MockProjectConnector struct {
@@ -55,3 +274,106 @@ func (pc *MockProjectConnector) FindProjects(key string, limit int, sortDir int,
 	}
 	return projects, nil
 }
+
+// FindProjectById returns the cached project with the given identifier.
+func (pc *MockProjectConnector) FindProjectById(id string) (*model.ProjectRef, error) {
+	for i := range pc.CachedProjects {
+		if pc.CachedProjects[i].Identifier == id {
+			projectRef := pc.CachedProjects[i]
+			return &projectRef, nil
+		}
+	}
+	return nil, projectNotFoundError(id)
+}
+
+// CreateProject adds a project to the cached projects.
+func (pc *MockProjectConnector) CreateProject(projectRef *model.ProjectRef) error {
+	if _, err := pc.FindProjectById(projectRef.Identifier); err == nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("project '%s' already exists", projectRef.Identifier),
+		}
+	}
+	pc.CachedProjects = append(pc.CachedProjects, *projectRef)
+	pc.CachedVars = append(pc.CachedVars, &model.ProjectVars{Id: projectRef.Identifier})
+	return nil
+}
+
+// UpdateProject replaces a cached project.
+func (pc *MockProjectConnector) UpdateProject(projectRef *model.ProjectRef) error {
+	for i := range pc.CachedProjects {
+		if pc.CachedProjects[i].Identifier == projectRef.Identifier {
+			pc.CachedProjects[i] = *projectRef
+			return nil
+		}
+	}
+	return projectNotFoundError(projectRef.Identifier)
+}
+
+// CopyProject adds a disabled copy of a cached project and its variables.
+func (pc *MockProjectConnector) CopyProject(oldId, newId string) (*model.ProjectRef, error) {
+	projectRef, err := pc.FindProjectById(oldId)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	projectRef.Identifier = newId
+	projectRef.Enabled = false
+	projectRef.PRTestingEnabled = false
+	projectRef.CommitQueue.Enabled = false
+	if err = pc.CreateProject(projectRef); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	vars, err := pc.FindProjectVars(oldId)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	pc.CachedVars[len(pc.CachedVars)-1] = &model.ProjectVars{
+		Id:          newId,
+		Vars:        vars.Vars,
+		PrivateVars: vars.PrivateVars,
+	}
+	return projectRef, nil
+}
+
+// DeleteProject removes a cached project and its variables.
+func (pc *MockProjectConnector) DeleteProject(id string) error {
+	for i := range pc.CachedProjects {
+		if pc.CachedProjects[i].Identifier == id {
+			pc.CachedProjects = append(pc.CachedProjects[:i], pc.CachedProjects[i+1:]...)
+			for j := range pc.CachedVars {
+				if pc.CachedVars[j].Id == id {
+					pc.CachedVars = append(pc.CachedVars[:j], pc.CachedVars[j+1:]...)
+					break
+				}
+			}
+			return nil
+		}
+	}
+	return projectNotFoundError(id)
+}
+
+// FindProjectVars returns the cached variables of a project.
+func (pc *MockProjectConnector) FindProjectVars(id string) (*model.ProjectVars, error) {
+	for _, vars := range pc.CachedVars {
+		if vars.Id == id {
+			return vars, nil
+		}
+	}
+	return &model.ProjectVars{Id: id}, nil
+}
+
+// UpdateProjectVars sets and deletes cached variables of a project.
+func (pc *MockProjectConnector) UpdateProjectVars(id string, update *model.ProjectVars, toDelete []string) error {
+	for _, vars := range pc.CachedVars {
+		if vars.Id == id {
+			return mergeProjectVars(vars, update, toDelete)
+		}
+	}
+	vars := &model.ProjectVars{Id: id}
+	if err := mergeProjectVars(vars, update, toDelete); err != nil {
+		return err
+	}
+	pc.CachedVars = append(pc.CachedVars, vars)
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -283,3 +285,68 @@ func (s *ProjectConnectorGetSuite) TestFetchKeyOutOfBoundDesc() {
 	s.NoError(err)
 	s.Len(projects, 0)
 }
+
+func TestProjectConnectorSettings(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	testutil.ConfigureIntegrationTest(t, testConfig, "TestProjectConnectorSettings")
+	db.SetGlobalSessionProvider(testConfig.SessionFactory())
+	require.NoError(db.ClearCollections(model.ProjectRefCollection, model.ProjectVarsCollection, model.ProjectAliasCollection))
+
+	sc := &DBConnector{}
+	projectRef := &model.ProjectRef{
+		Identifier:       "mci",
+		Owner:            "evergreen-ci",
+		Repo:             "evergreen",
+		Branch:           "master",
+		RepoKind:         model.GithubRepoType,
+		Enabled:          true,
+		PRTestingEnabled: true,
+	}
+	require.NoError(sc.CreateProject(projectRef))
+	assert.Error(sc.CreateProject(projectRef))
+	assert.Error(sc.CreateProject(&model.ProjectRef{Identifier: "no-branch"}))
+
+	require.NoError(sc.UpdateProjectVars("mci", &model.ProjectVars{
+		Vars:        map[string]string{"a": "1", "secret": "hunter2"},
+		PrivateVars: map[string]bool{"secret": true},
+	}, nil))
+	require.NoError(sc.UpdateProjectAliases("mci", []model.ProjectAlias{
+		{Alias: "__github", Variant: ".*", Task: ".*"},
+	}))
+	assert.Error(sc.UpdateProjectAliases("mci", []model.ProjectAlias{{Alias: "bad"}}))
+
+	// an empty value does not overwrite a private variable
+	require.NoError(sc.UpdateProjectVars("mci", &model.ProjectVars{
+		Vars: map[string]string{"secret": ""},
+	}, []string{"a"}))
+	vars, err := sc.FindProjectVars("mci")
+	require.NoError(err)
+	assert.Equal(map[string]string{"secret": "hunter2"}, vars.Vars)
+
+	projectRef.DisplayName = "Evergreen"
+	require.NoError(sc.UpdateProject(projectRef))
+
+	copied, err := sc.CopyProject("mci", "mci-copy")
+	require.NoError(err)
+	assert.Equal("Evergreen", copied.DisplayName)
+	assert.False(copied.Enabled)
+	assert.False(copied.PRTestingEnabled)
+	vars, err = sc.FindProjectVars("mci-copy")
+	require.NoError(err)
+	assert.Equal("hunter2", vars.Vars["secret"])
+	aliases, err := sc.FindProjectAliases("mci-copy")
+	require.NoError(err)
+	assert.Len(aliases, 1)
+	_, err = sc.CopyProject("mci", "mci-copy")
+	assert.Error(err)
+
+	require.NoError(sc.DeleteProject("mci"))
+	_, err = sc.FindProjectById("mci")
+	assert.Error(err)
+	aliases, err = sc.FindProjectAliases("mci")
+	require.NoError(err)
+	assert.Empty(aliases)
+	assert.Error(sc.DeleteProject("mci"))
+}
@@ -0,0 +1,218 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+// DBRoleConnector is a struct that implements the role related methods from
+// the Connector through interactions with the backing database.
+type DBRoleConnector struct{}
+
+// FindRoles returns every role, including the built-in roles.
+func (rc *DBRoleConnector) FindRoles() ([]role.Role, error) {
+	return role.FindAll()
+}
+
+// UpdateRole creates or replaces a role on behalf of the user.
+func (rc *DBRoleConnector) UpdateRole(r *role.Role, user string) error {
+	if err := r.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	return errors.WithStack(r.Upsert(user))
+}
+
+// RemoveRole removes a role on behalf of the user.
+func (rc *DBRoleConnector) RemoveRole(roleId, user string) error {
+	r, err := role.FindOne(roleId)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if r == nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "role not found",
+		}
+	}
+	return errors.WithStack(role.Remove(roleId, user))
+}
+
+// FindRoleAssignments returns the roles assigned to a user.
+func (rc *DBRoleConnector) FindRoleAssignments(assignee string) ([]role.Assignment, error) {
+	return role.FindAssignments(assignee)
+}
+
+// AssignRole grants a role to a user on a project, or globally if the project
+// is empty.
+func (rc *DBRoleConnector) AssignRole(assignee, roleId, project, user string) error {
+	r, err := role.FindOne(roleId)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if r == nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "role not found",
+		}
+	}
+	return errors.WithStack(role.Assign(assignee, roleId, project, user))
+}
+
+// UnassignRole revokes a role from a user.
+func (rc *DBRoleConnector) UnassignRole(assignee, roleId, project, user string) error {
+	return errors.WithStack(role.Unassign(assignee, roleId, project, user))
+}
+
+// UserHasPermission returns true if the user holds the permission on the
+// project, or globally if the project is empty.
+func (rc *DBRoleConnector) UserHasPermission(user, permission, project string) (bool, error) {
+	return role.HasPermission(user, permission, project)
+}
+
+// MockRoleConnector is a struct that implements mock versions of the role
+// related methods for testing. Changes are recorded in Events rather than
+// in the event log.
+type MockRoleConnector struct {
+	mu                sync.Mutex
+	CachedRoles       []role.Role
+	CachedAssignments []role.Assignment
+	Events            []event.RoleDataChange
+}
+
+func (rc *MockRoleConnector) FindRoles() ([]role.Role, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return role.MergeRoles(rc.CachedRoles), nil
+}
+
+func (rc *MockRoleConnector) findRole(roleId string) *role.Role {
+	for _, r := range role.MergeRoles(rc.CachedRoles) {
+		if r.Id == roleId {
+			return &r
+		}
+	}
+	return nil
+}
+
+func (rc *MockRoleConnector) UpdateRole(r *role.Role, user string) error {
+	if err := r.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	change := event.RoleDataChange{Action: event.RoleActionUpdate, Role: r.Id, After: r.Permissions}
+	if existing := rc.findRole(r.Id); existing != nil {
+		change.Before = existing.Permissions
+	}
+	roles := []role.Role{*r}
+	for _, stored := range rc.CachedRoles {
+		if stored.Id != r.Id {
+			roles = append(roles, stored)
+		}
+	}
+	rc.CachedRoles = roles
+	rc.Events = append(rc.Events, change)
+	return nil
+}
+
+func (rc *MockRoleConnector) RemoveRole(roleId, user string) error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	existing := rc.findRole(roleId)
+	if existing == nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "role not found",
+		}
+	}
+	roles := []role.Role{}
+	for _, stored := range rc.CachedRoles {
+		if stored.Id != roleId {
+			roles = append(roles, stored)
+		}
+	}
+	if len(roles) == len(rc.CachedRoles) && role.IsBuiltIn(roleId) {
+		return errors.Errorf("built-in role '%s' cannot be removed", roleId)
+	}
+	rc.CachedRoles = roles
+	rc.Events = append(rc.Events, event.RoleDataChange{Action: event.RoleActionRemove, Role: roleId, Before: existing.Permissions})
+	return nil
+}
+
+func (rc *MockRoleConnector) FindRoleAssignments(assignee string) ([]role.Assignment, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	out := []role.Assignment{}
+	for _, a := range rc.CachedAssignments {
+		if a.User == assignee {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func (rc *MockRoleConnector) AssignRole(assignee, roleId, project, user string) error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.findRole(roleId) == nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "role not found",
+		}
+	}
+	a := role.NewAssignment(assignee, roleId, project)
+	for _, existing := range rc.CachedAssignments {
+		if existing.Id == a.Id {
+			return nil
+		}
+	}
+	rc.CachedAssignments = append(rc.CachedAssignments, a)
+	rc.Events = append(rc.Events, event.RoleDataChange{Action: event.RoleActionAssign, Role: roleId, Assignee: assignee, Project: project})
+	return nil
+}
+
+func (rc *MockRoleConnector) UnassignRole(assignee, roleId, project, user string) error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	id := role.NewAssignment(assignee, roleId, project).Id
+	for i, a := range rc.CachedAssignments {
+		if a.Id == id {
+			rc.CachedAssignments = append(rc.CachedAssignments[:i], rc.CachedAssignments[i+1:]...)
+			rc.Events = append(rc.Events, event.RoleDataChange{Action: event.RoleActionUnassign, Role: roleId, Assignee: assignee, Project: project})
+			return nil
+		}
+	}
+	return gimlet.ErrorResponse{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("user '%s' does not have role '%s'", assignee, roleId),
+	}
+}
+
+func (rc *MockRoleConnector) UserHasPermission(user, permission, project string) (bool, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	for _, held := range role.Permissions(rc.CachedRoles, rc.CachedAssignments, user, project) {
+		if held == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
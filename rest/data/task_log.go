@@ -0,0 +1,45 @@
+package data
+
+import (
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+// DBTaskLogConnector is a struct that implements the task log related methods
+// from the Connector through interactions with the backing database.
+type DBTaskLogConnector struct{}
+
+// GetTaskLogMessagesFromOffset returns the messages of a task execution's log
+// starting at an offset, and the offset that follows them.
+func (tlc *DBTaskLogConnector) GetTaskLogMessagesFromOffset(taskId string, execution, offset int, types []string) ([]apimodels.LogMessage, int, error) {
+	msgs, next, err := model.FindTaskLogMessagesFromOffset(taskId, execution, offset, types)
+	if err != nil {
+		return nil, offset, errors.Wrapf(err, "problem reading log of task '%s'", taskId)
+	}
+	return msgs, next, nil
+}
+
+// MockTaskLogConnector is a struct that implements mock versions of the task
+// log related methods for testing.
+type MockTaskLogConnector struct {
+	CachedLogMessages map[string][]apimodels.LogMessage
+}
+
+// GetTaskLogMessagesFromOffset returns the cached messages of the task
+// starting at the offset. The execution is ignored.
+func (tlc *MockTaskLogConnector) GetTaskLogMessagesFromOffset(taskId string, execution, offset int, types []string) ([]apimodels.LogMessage, int, error) {
+	cached := tlc.CachedLogMessages[taskId]
+	if offset >= len(cached) {
+		return []apimodels.LogMessage{}, offset, nil
+	}
+
+	msgs := []apimodels.LogMessage{}
+	for _, msg := range cached[offset:] {
+		if len(types) == 0 || util.StringSliceContains(types, msg.Type) {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs, len(cached), nil
+}
@@ -27,6 +27,13 @@ func (tc *DBUserConnector) FindUserById(userId string) (gimlet.User, error) {
 	return t, nil
 }
 
+// FindUserByGithubUID returns the user who linked the GitHub account with
+// the given ID, or nil if there is none.
+func (u *DBUserConnector) FindUserByGithubUID(uid int) (*user.DBUser, error) {
+	dbUser, err := user.FindByGithubUID(uid)
+	return dbUser, errors.WithStack(err)
+}
+
 func (u *DBUserConnector) AddPublicKey(user *user.DBUser, keyName, keyValue string) error {
 	return user.AddPublicKey(keyName, keyValue)
 }
@@ -136,6 +143,17 @@ func (muc *MockUserConnector) FindUserById(userId string) (gimlet.User, error) {
 	return u, nil
 }
 
+// FindUserByGithubUID returns the cached user who linked the GitHub account
+// with the given ID.
+func (muc *MockUserConnector) FindUserByGithubUID(uid int) (*user.DBUser, error) {
+	for _, u := range muc.CachedUsers {
+		if u.Settings.GithubUser.UID == uid {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
 func (muc *MockUserConnector) AddPublicKey(dbuser *user.DBUser, keyName, keyValue string) error {
 	u, ok := muc.CachedUsers[dbuser.Id]
 	if !ok {
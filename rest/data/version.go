@@ -10,6 +10,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/repotracker"
 	restModel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/pkg/errors"
@@ -87,6 +88,46 @@ func (vc *DBVersionConnector) RestartVersion(versionId string, caller string) er
 	return model.RestartVersion(versionId, taskIds, true, caller)
 }
 
+// FindVersionTaskGraph builds the task dependency graph of the version with
+// the given versionId.
+func (vc *DBVersionConnector) FindVersionTaskGraph(versionId string) (*task.DependencyGraph, error) {
+	tasks, err := task.Find(task.ByVersion(versionId))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding tasks for version '%s'", versionId)
+	}
+	if len(tasks) == 0 {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("version with id %s not found", versionId),
+		}
+	}
+	return task.NewDependencyGraph(versionId, tasks)
+}
+
+// CreateManualVersion starts a version of a revision of the project with the
+// given id, with the parameters and tasks in the options.
+func (vc *DBVersionConnector) CreateManualVersion(projectId string, opts repotracker.ManualVersionOptions) (*version.Version, error) {
+	ref, err := model.FindOneProjectRef(projectId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding project '%s'", projectId)
+	}
+	if ref == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project with id %s not found", projectId),
+		}
+	}
+
+	v, err := repotracker.CreateManualVersion(ref, opts)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	return v, nil
+}
+
 // Fetch versions until 'numVersionElements' elements are created, including
 // elements consisting of multiple versions rolled-up into one.
 // The skip value indicates how many versions back in time should be skipped
@@ -373,6 +414,46 @@ func (mvc *MockVersionConnector) RestartVersion(versionId string, caller string)
 	return nil
 }
 
+// FindVersionTaskGraph is the mock implementation of the function for the
+// Connector interface. It builds the graph from the cached tasks.
+func (mvc *MockVersionConnector) FindVersionTaskGraph(versionId string) (*task.DependencyGraph, error) {
+	tasks := []task.Task{}
+	for _, t := range mvc.CachedTasks {
+		if t.Version == versionId {
+			tasks = append(tasks, t)
+		}
+	}
+	if len(tasks) == 0 {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("version with id %s not found", versionId),
+		}
+	}
+	return task.NewDependencyGraph(versionId, tasks)
+}
+
+// CreateManualVersion is the mock implementation of the function for the
+// Connector interface. It caches a version with the options' parameters.
+func (mvc *MockVersionConnector) CreateManualVersion(projectId string, opts repotracker.ManualVersionOptions) (*version.Version, error) {
+	params := []version.Parameter{}
+	for key, value := range opts.Parameters {
+		params = append(params, version.Parameter{Key: key, Value: value})
+	}
+	sort.Slice(params, func(i, j int) bool { return params[i].Key < params[j].Key })
+
+	v := version.Version{
+		Id:         fmt.Sprintf("%s_manual_%d", projectId, len(mvc.CachedVersions)),
+		Identifier: projectId,
+		Revision:   opts.Revision,
+		Author:     opts.Author,
+		Message:    opts.Message,
+		Requester:  evergreen.ManualVersionRequester,
+		Parameters: params,
+	}
+	mvc.CachedVersions = append(mvc.CachedVersions, v)
+	return &v, nil
+}
+
 func (mvc *MockVersionConnector) GetVersionsAndVariants(skip, numVersionElements int, project *model.Project) (*restModel.VersionVariantData, error) {
 	return nil, nil
 }
@@ -16,6 +16,7 @@ func NewConfigModel() *APIAdminSettings {
 		Amboy:             &APIAmboyConfig{},
 		Api:               &APIapiConfig{},
 		AuthConfig:        &APIAuthConfig{},
+		Buckets:           &APIBucketsConfig{},
 		ContainerPools:    &APIContainerPoolsConfig{},
 		Credentials:       map[string]string{},
 		Expansions:        map[string]string{},
@@ -29,6 +30,7 @@ func NewConfigModel() *APIAdminSettings {
 		Providers:         &APICloudProviders{},
 		RepoTracker:       &APIRepoTrackerConfig{},
 		Scheduler:         &APISchedulerConfig{},
+		Secrets:           &APISecretsConfig{},
 		ServiceFlags:      &APIServiceFlags{},
 		Slack:             &APISlackConfig{},
 		Splunk:            &APISplunkConnectionInfo{},
@@ -45,6 +47,7 @@ type APIAdminSettings struct {
 	AuthConfig         *APIAuthConfig                    `json:"auth,omitempty"`
 	Banner             APIString                         `json:"banner,omitempty"`
 	BannerTheme        APIString                         `json:"banner_theme,omitempty"`
+	Buckets            *APIBucketsConfig                 `json:"buckets,omitempty"`
 	ClientBinariesDir  APIString                         `json:"client_binaries_dir,omitempty"`
 	ConfigDir          APIString                         `json:"configdir,omitempty"`
 	Credentials        map[string]string                 `json:"credentials,omitempty"`
@@ -62,6 +65,7 @@ type APIAdminSettings struct {
 	Providers          *APICloudProviders                `json:"providers,omitempty"`
 	RepoTracker        *APIRepoTrackerConfig             `json:"repotracker,omitempty"`
 	Scheduler          *APISchedulerConfig               `json:"scheduler,omitempty"`
+	Secrets            *APISecretsConfig                 `json:"secrets,omitempty"`
 	ServiceFlags       *APIServiceFlags                  `json:"service_flags,omitempty"`
 	Slack              *APISlackConfig                   `json:"slack,omitempty"`
 	Splunk             *APISplunkConnectionInfo          `json:"splunk,omitempty"`
@@ -322,6 +326,7 @@ type APIAuthConfig struct {
 	Crowd  *APICrowdConfig      `json:"crowd"`
 	Naive  *APINaiveAuthConfig  `json:"naive"`
 	Github *APIGithubAuthConfig `json:"github"`
+	OIDC   *APIOIDCAuthConfig   `json:"oidc"`
 }
 
 func (a *APIAuthConfig) BuildFromService(h interface{}) error {
@@ -345,6 +350,12 @@ func (a *APIAuthConfig) BuildFromService(h interface{}) error {
 				return err
 			}
 		}
+		if v.OIDC != nil {
+			a.OIDC = &APIOIDCAuthConfig{}
+			if err := a.OIDC.BuildFromService(v.OIDC); err != nil {
+				return err
+			}
+		}
 	default:
 		return errors.Errorf("%T is not a supported type", h)
 	}
@@ -355,6 +366,7 @@ func (a *APIAuthConfig) ToService() (interface{}, error) {
 	var crowd *evergreen.CrowdConfig
 	var naive *evergreen.NaiveAuthConfig
 	var github *evergreen.GithubAuthConfig
+	var oidc *evergreen.OIDCAuthConfig
 	i, err := a.Crowd.ToService()
 	if err != nil {
 		return nil, err
@@ -376,10 +388,18 @@ func (a *APIAuthConfig) ToService() (interface{}, error) {
 	if i != nil {
 		github = i.(*evergreen.GithubAuthConfig)
 	}
+	i, err = a.OIDC.ToService()
+	if err != nil {
+		return nil, err
+	}
+	if i != nil {
+		oidc = i.(*evergreen.OIDCAuthConfig)
+	}
 	return evergreen.AuthConfig{
 		Crowd:  crowd,
 		Naive:  naive,
 		Github: github,
+		OIDC:   oidc,
 	}, nil
 }
 
@@ -529,12 +549,137 @@ func (a *APIGithubAuthConfig) ToService() (interface{}, error) {
 	return &config, nil
 }
 
+type APIOIDCAuthConfig struct {
+	Issuer           APIString          `json:"issuer"`
+	ClientId         APIString          `json:"client_id"`
+	ClientSecret     APIString          `json:"client_secret"`
+	Scopes           []APIString        `json:"scopes"`
+	UsernameClaim    APIString          `json:"username_claim"`
+	DisplayNameClaim APIString          `json:"display_name_claim"`
+	EmailClaim       APIString          `json:"email_claim"`
+	GroupsClaim      APIString          `json:"groups_claim"`
+	Groups           []APIString        `json:"groups"`
+	GroupRoles       []APIOIDCGroupRole `json:"group_roles"`
+}
+
+type APIOIDCGroupRole struct {
+	Group   APIString `json:"group"`
+	Role    APIString `json:"role"`
+	Project APIString `json:"project"`
+}
+
+func (a *APIOIDCAuthConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case *evergreen.OIDCAuthConfig:
+		if v == nil {
+			return nil
+		}
+		a.Issuer = ToAPIString(v.Issuer)
+		a.ClientId = ToAPIString(v.ClientId)
+		a.ClientSecret = ToAPIString(v.ClientSecret)
+		a.UsernameClaim = ToAPIString(v.UsernameClaim)
+		a.DisplayNameClaim = ToAPIString(v.DisplayNameClaim)
+		a.EmailClaim = ToAPIString(v.EmailClaim)
+		a.GroupsClaim = ToAPIString(v.GroupsClaim)
+		for _, scope := range v.Scopes {
+			a.Scopes = append(a.Scopes, ToAPIString(scope))
+		}
+		for _, group := range v.Groups {
+			a.Groups = append(a.Groups, ToAPIString(group))
+		}
+		for _, groupRole := range v.GroupRoles {
+			a.GroupRoles = append(a.GroupRoles, APIOIDCGroupRole{
+				Group:   ToAPIString(groupRole.Group),
+				Role:    ToAPIString(groupRole.Role),
+				Project: ToAPIString(groupRole.Project),
+			})
+		}
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+	return nil
+}
+
+func (a *APIOIDCAuthConfig) ToService() (interface{}, error) {
+	if a == nil {
+		return nil, nil
+	}
+	config := evergreen.OIDCAuthConfig{
+		Issuer:           FromAPIString(a.Issuer),
+		ClientId:         FromAPIString(a.ClientId),
+		ClientSecret:     FromAPIString(a.ClientSecret),
+		UsernameClaim:    FromAPIString(a.UsernameClaim),
+		DisplayNameClaim: FromAPIString(a.DisplayNameClaim),
+		EmailClaim:       FromAPIString(a.EmailClaim),
+		GroupsClaim:      FromAPIString(a.GroupsClaim),
+	}
+	for _, scope := range a.Scopes {
+		config.Scopes = append(config.Scopes, FromAPIString(scope))
+	}
+	for _, group := range a.Groups {
+		config.Groups = append(config.Groups, FromAPIString(group))
+	}
+	for _, groupRole := range a.GroupRoles {
+		config.GroupRoles = append(config.GroupRoles, evergreen.OIDCGroupRole{
+			Group:   FromAPIString(groupRole.Group),
+			Role:    FromAPIString(groupRole.Role),
+			Project: FromAPIString(groupRole.Project),
+		})
+	}
+	return &config, nil
+}
+
 // APIBanner is a public structure representing the banner part of the admin settings
 type APIBanner struct {
 	Text  APIString `json:"banner"`
 	Theme APIString `json:"theme"`
 }
 
+type APIBucketsConfig struct {
+	Filesystem APIFilesystemBucketConfig `json:"filesystem"`
+	GCS        APIGCSBucketConfig        `json:"gcs"`
+}
+
+type APIFilesystemBucketConfig struct {
+	Root        APIString `json:"root"`
+	LinkBaseURL APIString `json:"link_base_url"`
+}
+
+type APIGCSBucketConfig struct {
+	Endpoint    APIString `json:"endpoint"`
+	LinkBaseURL APIString `json:"link_base_url"`
+}
+
+func (a *APIBucketsConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case evergreen.BucketsConfig:
+		a.Filesystem = APIFilesystemBucketConfig{
+			Root:        ToAPIString(v.Filesystem.Root),
+			LinkBaseURL: ToAPIString(v.Filesystem.LinkBaseURL),
+		}
+		a.GCS = APIGCSBucketConfig{
+			Endpoint:    ToAPIString(v.GCS.Endpoint),
+			LinkBaseURL: ToAPIString(v.GCS.LinkBaseURL),
+		}
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+	return nil
+}
+
+func (a *APIBucketsConfig) ToService() (interface{}, error) {
+	return evergreen.BucketsConfig{
+		Filesystem: evergreen.FilesystemBucketConfig{
+			Root:        FromAPIString(a.Filesystem.Root),
+			LinkBaseURL: FromAPIString(a.Filesystem.LinkBaseURL),
+		},
+		GCS: evergreen.GCSBucketConfig{
+			Endpoint:    FromAPIString(a.GCS.Endpoint),
+			LinkBaseURL: FromAPIString(a.GCS.LinkBaseURL),
+		},
+	}, nil
+}
+
 type APIHostInitConfig struct {
 	SSHTimeoutSeconds int64 `json:"ssh_timeout_secs"`
 }
@@ -671,16 +816,17 @@ func (a *APINotifyConfig) ToService() (interface{}, error) {
 	return evergreen.NotifyConfig{
 		BufferTargetPerInterval: a.BufferTargetPerInterval,
 		BufferIntervalSeconds:   a.BufferIntervalSeconds,
-		SMTP: smtp.(evergreen.SMTPConfig),
+		SMTP:                    smtp.(evergreen.SMTPConfig),
 	}, nil
 }
 
 type APICloudProviders struct {
-	AWS       *APIAWSConfig       `json:"aws"`
-	Docker    *APIDockerConfig    `json:"docker"`
-	GCE       *APIGCEConfig       `json:"gce"`
-	OpenStack *APIOpenStackConfig `json:"openstack"`
-	VSphere   *APIVSphereConfig   `json:"vsphere"`
+	AWS        *APIAWSConfig        `json:"aws"`
+	Docker     *APIDockerConfig     `json:"docker"`
+	GCE        *APIGCEConfig        `json:"gce"`
+	Kubernetes *APIKubernetesConfig `json:"kubernetes"`
+	OpenStack  *APIOpenStackConfig  `json:"openstack"`
+	VSphere    *APIVSphereConfig    `json:"vsphere"`
 }
 
 func (a *APICloudProviders) BuildFromService(h interface{}) error {
@@ -689,6 +835,7 @@ func (a *APICloudProviders) BuildFromService(h interface{}) error {
 		a.AWS = &APIAWSConfig{}
 		a.Docker = &APIDockerConfig{}
 		a.GCE = &APIGCEConfig{}
+		a.Kubernetes = &APIKubernetesConfig{}
 		a.OpenStack = &APIOpenStackConfig{}
 		a.VSphere = &APIVSphereConfig{}
 		if err := a.AWS.BuildFromService(v.AWS); err != nil {
@@ -700,6 +847,9 @@ func (a *APICloudProviders) BuildFromService(h interface{}) error {
 		if err := a.GCE.BuildFromService(v.GCE); err != nil {
 			return err
 		}
+		if err := a.Kubernetes.BuildFromService(v.Kubernetes); err != nil {
+			return err
+		}
 		if err := a.OpenStack.BuildFromService(v.OpenStack); err != nil {
 			return err
 		}
@@ -725,6 +875,10 @@ func (a *APICloudProviders) ToService() (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	kubernetes, err := a.Kubernetes.ToService()
+	if err != nil {
+		return nil, err
+	}
 	openstack, err := a.OpenStack.ToService()
 	if err != nil {
 		return nil, err
@@ -734,11 +888,12 @@ func (a *APICloudProviders) ToService() (interface{}, error) {
 		return nil, err
 	}
 	return evergreen.CloudProviders{
-		AWS:       aws.(evergreen.AWSConfig),
-		Docker:    docker.(evergreen.DockerConfig),
-		GCE:       gce.(evergreen.GCEConfig),
-		OpenStack: openstack.(evergreen.OpenStackConfig),
-		VSphere:   vsphere.(evergreen.VSphereConfig),
+		AWS:        aws.(evergreen.AWSConfig),
+		Docker:     docker.(evergreen.DockerConfig),
+		GCE:        gce.(evergreen.GCEConfig),
+		Kubernetes: kubernetes.(evergreen.KubernetesConfig),
+		OpenStack:  openstack.(evergreen.OpenStackConfig),
+		VSphere:    vsphere.(evergreen.VSphereConfig),
 	}, nil
 }
 
@@ -783,6 +938,7 @@ type APIContainerPool struct {
 	Id            APIString `json:"id"`
 	MaxContainers int       `json:"max_containers"`
 	Port          uint16    `json:"port"`
+	Namespace     APIString `json:"namespace"`
 }
 
 func (a *APIContainerPool) BuildFromService(h interface{}) error {
@@ -792,6 +948,7 @@ func (a *APIContainerPool) BuildFromService(h interface{}) error {
 		a.Id = ToAPIString(v.Id)
 		a.MaxContainers = v.MaxContainers
 		a.Port = v.Port
+		a.Namespace = ToAPIString(v.Namespace)
 	default:
 		return errors.Errorf("%T is not a supported type", h)
 	}
@@ -804,6 +961,7 @@ func (a *APIContainerPool) ToService() (interface{}, error) {
 		Id:            FromAPIString(a.Id),
 		MaxContainers: a.MaxContainers,
 		Port:          a.Port,
+		Namespace:     FromAPIString(a.Namespace),
 	}, nil
 }
 
@@ -850,6 +1008,35 @@ func (a *APIDockerConfig) ToService() (interface{}, error) {
 	}, nil
 }
 
+type APIKubernetesConfig struct {
+	APIServer APIString `json:"api_server"`
+	Token     APIString `json:"token"`
+	Namespace APIString `json:"namespace"`
+	Insecure  bool      `json:"insecure"`
+}
+
+func (a *APIKubernetesConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case evergreen.KubernetesConfig:
+		a.APIServer = ToAPIString(v.APIServer)
+		a.Token = ToAPIString(v.Token)
+		a.Namespace = ToAPIString(v.Namespace)
+		a.Insecure = v.Insecure
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+	return nil
+}
+
+func (a *APIKubernetesConfig) ToService() (interface{}, error) {
+	return evergreen.KubernetesConfig{
+		APIServer: FromAPIString(a.APIServer),
+		Token:     FromAPIString(a.Token),
+		Namespace: FromAPIString(a.Namespace),
+		Insecure:  a.Insecure,
+	}, nil
+}
+
 type APIGCEConfig struct {
 	ClientEmail  APIString `json:"client_email"`
 	PrivateKey   APIString `json:"private_key"`
@@ -947,9 +1134,11 @@ func (a *APIVSphereConfig) ToService() (interface{}, error) {
 }
 
 type APIRepoTrackerConfig struct {
-	NumNewRepoRevisionsToFetch int `json:"revs_to_fetch"`
-	MaxRepoRevisionsToSearch   int `json:"max_revs_to_search"`
-	MaxConcurrentRequests      int `json:"max_con_requests"`
+	NumNewRepoRevisionsToFetch int       `json:"revs_to_fetch"`
+	MaxRepoRevisionsToSearch   int       `json:"max_revs_to_search"`
+	MaxConcurrentRequests      int       `json:"max_con_requests"`
+	MirrorDirectory            APIString `json:"mirror_dir"`
+	GitlabURL                  APIString `json:"gitlab_url"`
 }
 
 func (a *APIRepoTrackerConfig) BuildFromService(h interface{}) error {
@@ -958,6 +1147,8 @@ func (a *APIRepoTrackerConfig) BuildFromService(h interface{}) error {
 		a.NumNewRepoRevisionsToFetch = v.NumNewRepoRevisionsToFetch
 		a.MaxConcurrentRequests = v.MaxConcurrentRequests
 		a.MaxRepoRevisionsToSearch = v.MaxRepoRevisionsToSearch
+		a.MirrorDirectory = ToAPIString(v.MirrorDirectory)
+		a.GitlabURL = ToAPIString(v.GitlabURL)
 	default:
 		return errors.Errorf("%T is not a supported type", h)
 	}
@@ -969,12 +1160,15 @@ func (a *APIRepoTrackerConfig) ToService() (interface{}, error) {
 		NumNewRepoRevisionsToFetch: a.NumNewRepoRevisionsToFetch,
 		MaxConcurrentRequests:      a.MaxConcurrentRequests,
 		MaxRepoRevisionsToSearch:   a.MaxRepoRevisionsToSearch,
+		MirrorDirectory:            FromAPIString(a.MirrorDirectory),
+		GitlabURL:                  FromAPIString(a.GitlabURL),
 	}, nil
 }
 
 type APISchedulerConfig struct {
 	TaskFinder       APIString `json:"task_finder"`
 	HostAllocator    APIString `json:"host_allocator"`
+	TaskPrioritizer  APIString `json:"task_prioritizer"`
 	FreeHostFraction float64   `json:"free_host_fraction"`
 }
 
@@ -983,6 +1177,7 @@ func (a *APISchedulerConfig) BuildFromService(h interface{}) error {
 	case evergreen.SchedulerConfig:
 		a.TaskFinder = ToAPIString(v.TaskFinder)
 		a.HostAllocator = ToAPIString(v.HostAllocator)
+		a.TaskPrioritizer = ToAPIString(v.TaskPrioritizer)
 		a.FreeHostFraction = v.FreeHostFraction
 	default:
 		return errors.Errorf("%T is not a supported type", h)
@@ -994,10 +1189,56 @@ func (a *APISchedulerConfig) ToService() (interface{}, error) {
 	return evergreen.SchedulerConfig{
 		TaskFinder:       FromAPIString(a.TaskFinder),
 		HostAllocator:    FromAPIString(a.HostAllocator),
+		TaskPrioritizer:  FromAPIString(a.TaskPrioritizer),
 		FreeHostFraction: a.FreeHostFraction,
 	}, nil
 }
 
+// APISecretsConfig leaves out the project variables encryption key, which
+// is only set in the configuration file or the environment.
+type APISecretsConfig struct {
+	Backend APIString      `json:"backend"`
+	Vault   APIVaultConfig `json:"vault"`
+}
+
+func (a *APISecretsConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case evergreen.SecretsConfig:
+		a.Backend = ToAPIString(v.Backend)
+		a.Vault = APIVaultConfig{
+			URL:           ToAPIString(v.Vault.URL),
+			Token:         ToAPIString(v.Vault.Token),
+			Mount:         ToAPIString(v.Vault.Mount),
+			ProjectPrefix: ToAPIString(v.Vault.ProjectPrefix),
+			TimeoutSecs:   v.Vault.TimeoutSecs,
+		}
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+	return nil
+}
+
+func (a *APISecretsConfig) ToService() (interface{}, error) {
+	return evergreen.SecretsConfig{
+		Backend: FromAPIString(a.Backend),
+		Vault: evergreen.VaultConfig{
+			URL:           FromAPIString(a.Vault.URL),
+			Token:         FromAPIString(a.Vault.Token),
+			Mount:         FromAPIString(a.Vault.Mount),
+			ProjectPrefix: FromAPIString(a.Vault.ProjectPrefix),
+			TimeoutSecs:   a.Vault.TimeoutSecs,
+		},
+	}, nil
+}
+
+type APIVaultConfig struct {
+	URL           APIString `json:"url"`
+	Token         APIString `json:"token"`
+	Mount         APIString `json:"mount"`
+	ProjectPrefix APIString `json:"project_prefix"`
+	TimeoutSecs   int       `json:"timeout_secs"`
+}
+
 // APIServiceFlags is a public structure representing the admin service flags
 type APIServiceFlags struct {
 	TaskDispatchDisabled         bool `json:"task_dispatch_disabled"`
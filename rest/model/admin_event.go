@@ -9,12 +9,23 @@ import (
 )
 
 type APIAdminEvent struct {
-	Timestamp time.Time `json:"ts"`
-	User      string    `json:"user"`
-	Section   string    `json:"section"`
-	Before    Model     `json:"before"`
-	After     Model     `json:"after"`
-	Guid      string    `json:"guid"`
+	Timestamp  time.Time      `json:"ts"`
+	User       string         `json:"user"`
+	Section    string         `json:"section"`
+	Before     Model          `json:"before"`
+	After      Model          `json:"after"`
+	RoleChange *APIRoleChange `json:"role_change,omitempty"`
+	Guid       string         `json:"guid"`
+}
+
+// APIRoleChange describes a change to a role or role assignment.
+type APIRoleChange struct {
+	Action   string   `json:"action"`
+	Role     string   `json:"role"`
+	Assignee string   `json:"assignee,omitempty"`
+	Project  string   `json:"project,omitempty"`
+	Before   []string `json:"before,omitempty"`
+	After    []string `json:"after,omitempty"`
 }
 
 func (e *APIAdminEvent) BuildFromService(h interface{}) error {
@@ -28,6 +39,17 @@ func (e *APIAdminEvent) BuildFromService(h interface{}) error {
 		e.User = data.User
 		e.Section = data.Section
 		e.Guid = data.GUID
+		if data.RoleChange != nil {
+			e.RoleChange = &APIRoleChange{
+				Action:   data.RoleChange.Action,
+				Role:     data.RoleChange.Role,
+				Assignee: data.RoleChange.Assignee,
+				Project:  data.RoleChange.Project,
+				Before:   data.RoleChange.Before,
+				After:    data.RoleChange.After,
+			}
+			return nil
+		}
 		before, err := AdminDbToRestModel(data.Changes.Before)
 		if err != nil {
 			return errors.Wrap(err, "unable to convert 'before' changes")
@@ -109,6 +109,9 @@ func TestModelConversion(t *testing.T) {
 	assert.EqualValues(testSettings.Providers.VSphere.Host, FromAPIString(apiSettings.Providers.VSphere.Host))
 	assert.EqualValues(testSettings.RepoTracker.MaxConcurrentRequests, apiSettings.RepoTracker.MaxConcurrentRequests)
 	assert.EqualValues(testSettings.Scheduler.TaskFinder, FromAPIString(apiSettings.Scheduler.TaskFinder))
+	assert.EqualValues(testSettings.Secrets.Vault.URL, FromAPIString(apiSettings.Secrets.Vault.URL))
+	assert.EqualValues(testSettings.Buckets.Filesystem.Root, FromAPIString(apiSettings.Buckets.Filesystem.Root))
+	assert.EqualValues(testSettings.Buckets.GCS.Endpoint, FromAPIString(apiSettings.Buckets.GCS.Endpoint))
 	assert.EqualValues(testSettings.ServiceFlags.HostinitDisabled, apiSettings.ServiceFlags.HostinitDisabled)
 	assert.EqualValues(testSettings.Slack.Level, FromAPIString(apiSettings.Slack.Level))
 	assert.EqualValues(testSettings.Slack.Options.Channel, FromAPIString(apiSettings.Slack.Options.Channel))
@@ -147,6 +150,9 @@ func TestModelConversion(t *testing.T) {
 	assert.EqualValues(testSettings.Providers.VSphere.Host, dbSettings.Providers.VSphere.Host)
 	assert.EqualValues(testSettings.RepoTracker.MaxConcurrentRequests, dbSettings.RepoTracker.MaxConcurrentRequests)
 	assert.EqualValues(testSettings.Scheduler.TaskFinder, dbSettings.Scheduler.TaskFinder)
+	assert.EqualValues(testSettings.Secrets.Vault.URL, dbSettings.Secrets.Vault.URL)
+	assert.EqualValues(testSettings.Buckets.Filesystem.Root, dbSettings.Buckets.Filesystem.Root)
+	assert.EqualValues(testSettings.Buckets.GCS.Endpoint, dbSettings.Buckets.GCS.Endpoint)
 	assert.EqualValues(testSettings.ServiceFlags.HostinitDisabled, dbSettings.ServiceFlags.HostinitDisabled)
 	assert.EqualValues(testSettings.Slack.Level, dbSettings.Slack.Level)
 	assert.EqualValues(testSettings.Slack.Options.Channel, dbSettings.Slack.Options.Channel)
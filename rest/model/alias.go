@@ -10,6 +10,7 @@ type APIAlias struct {
 	Alias   APIString `json:"alias"`
 	Variant APIString `json:"variant"`
 	Task    APIString `json:"task"`
+	Tags    []string  `json:"tags,omitempty"`
 }
 
 // BuildFromService converts from service level structs to an APIAlias.
@@ -19,6 +20,7 @@ func (apiAlias *APIAlias) BuildFromService(h interface{}) error {
 		apiAlias.Alias = ToAPIString(v.Alias)
 		apiAlias.Variant = ToAPIString(v.Variant)
 		apiAlias.Task = ToAPIString(v.Task)
+		apiAlias.Tags = v.Tags
 	default:
 		return errors.Errorf("incorrect type when fetching converting alias type")
 	}
@@ -27,5 +29,10 @@ func (apiAlias *APIAlias) BuildFromService(h interface{}) error {
 
 // ToService returns a service layer alias using the data from APIAlias.
 func (apiAlias *APIAlias) ToService() (interface{}, error) {
-	return nil, errors.Errorf("ToService() is not impelemented for APIAlias")
+	return model.ProjectAlias{
+		Alias:   FromAPIString(apiAlias.Alias),
+		Variant: FromAPIString(apiAlias.Variant),
+		Task:    FromAPIString(apiAlias.Task),
+		Tags:    apiAlias.Tags,
+	}, nil
 }
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model/commitqueue"
+	"github.com/pkg/errors"
+)
+
+// APICommitQueue is the model to be returned by the API whenever a project's
+// commit queue is fetched.
+type APICommitQueue struct {
+	ProjectID APIString            `json:"queue_id"`
+	Queue     []APICommitQueueItem `json:"queue"`
+}
+
+// APICommitQueueItem is a single entry of an APICommitQueue.
+type APICommitQueueItem struct {
+	Issue       APIString `json:"issue"`
+	PatchId     APIString `json:"patch_id"`
+	EnqueueTime APITime   `json:"enqueue_time"`
+	Enqueuer    APIString `json:"enqueuer"`
+}
+
+// BuildFromService converts from service level structs to an APICommitQueue.
+func (cq *APICommitQueue) BuildFromService(h interface{}) error {
+	var queue commitqueue.CommitQueue
+	switch v := h.(type) {
+	case commitqueue.CommitQueue:
+		queue = v
+	case *commitqueue.CommitQueue:
+		queue = *v
+	default:
+		return errors.Errorf("incorrect type '%T' when converting commit queue", h)
+	}
+
+	cq.ProjectID = ToAPIString(queue.ProjectID)
+	cq.Queue = []APICommitQueueItem{}
+	for _, item := range queue.Queue {
+		apiItem := APICommitQueueItem{}
+		if err := apiItem.BuildFromService(item); err != nil {
+			return errors.WithStack(err)
+		}
+		cq.Queue = append(cq.Queue, apiItem)
+	}
+
+	return nil
+}
+
+// ToService is not implemented for APICommitQueue.
+func (cq *APICommitQueue) ToService() (interface{}, error) {
+	return nil, errors.New("ToService() is not implemented for APICommitQueue")
+}
+
+// BuildFromService converts from service level structs to an
+// APICommitQueueItem.
+func (item *APICommitQueueItem) BuildFromService(h interface{}) error {
+	v, ok := h.(commitqueue.CommitQueueItem)
+	if !ok {
+		return errors.Errorf("incorrect type '%T' when converting commit queue item", h)
+	}
+
+	item.Issue = ToAPIString(v.Issue)
+	item.PatchId = ToAPIString(v.PatchId)
+	item.EnqueueTime = NewTime(v.EnqueueTime)
+	item.Enqueuer = ToAPIString(v.Enqueuer)
+
+	return nil
+}
+
+// ToService converts an APICommitQueueItem to a service level struct.
+func (item *APICommitQueueItem) ToService() (interface{}, error) {
+	return commitqueue.CommitQueueItem{
+		Issue:    FromAPIString(item.Issue),
+		PatchId:  FromAPIString(item.PatchId),
+		Enqueuer: FromAPIString(item.Enqueuer),
+	}, nil
+}
+
+// APICommitQueuePosition is returned by the API when an item is enqueued, and
+// is the body of a request to move an item within the queue.
+type APICommitQueuePosition struct {
+	Position int `json:"position"`
+}
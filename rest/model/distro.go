@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/cloud"
 	"github.com/evergreen-ci/evergreen/model/distro"
@@ -9,12 +11,26 @@ import (
 )
 
 // APIDistro is the model to be returned by the API whenever distros are fetched.
-// EVG-1717 will implement the remainder of the distro model.
 type APIDistro struct {
-	Name             APIString `json:"name"`
-	UserSpawnAllowed bool      `json:"user_spawn_allowed"`
-	Provider         APIString `json:"provider"`
-	ImageID          APIString `json:"image_id,omitempty"`
+	Name             APIString              `json:"name"`
+	UserSpawnAllowed bool                   `json:"user_spawn_allowed"`
+	Provider         APIString              `json:"provider"`
+	ImageID          APIString              `json:"image_id,omitempty"`
+	Arch             APIString              `json:"arch"`
+	WorkDir          APIString              `json:"work_dir"`
+	PoolSize         int                    `json:"pool_size"`
+	ProviderSettings map[string]interface{} `json:"settings"`
+	SetupAsSudo      bool                   `json:"setup_as_sudo"`
+	Setup            APIString              `json:"setup"`
+	Teardown         APIString              `json:"teardown"`
+	User             APIString              `json:"user"`
+	SSHKey           APIString              `json:"ssh_key"`
+	SSHOptions       []string               `json:"ssh_options"`
+	Expansions       []distro.Expansion     `json:"expansions"`
+	Disabled         bool                   `json:"disabled"`
+	ContainerPool    APIString              `json:"container_pool"`
+	TaskPrioritizer  APIString              `json:"task_prioritizer"`
+	ResourceLimits   *distro.ResourceLimits `json:"resource_limits,omitempty"`
 }
 
 // BuildFromService converts from service level structs to an APIDistro.
@@ -34,6 +50,24 @@ func (apiDistro *APIDistro) BuildFromService(h interface{}) error {
 
 			apiDistro.ImageID = ToAPIString(ec2Settings.AMI)
 		}
+
+		apiDistro.Arch = ToAPIString(v.Arch)
+		apiDistro.WorkDir = ToAPIString(v.WorkDir)
+		apiDistro.PoolSize = v.PoolSize
+		if v.ProviderSettings != nil {
+			apiDistro.ProviderSettings = *v.ProviderSettings
+		}
+		apiDistro.SetupAsSudo = v.SetupAsSudo
+		apiDistro.Setup = ToAPIString(v.Setup)
+		apiDistro.Teardown = ToAPIString(v.Teardown)
+		apiDistro.User = ToAPIString(v.User)
+		apiDistro.SSHKey = ToAPIString(v.SSHKey)
+		apiDistro.SSHOptions = v.SSHOptions
+		apiDistro.Expansions = v.Expansions
+		apiDistro.Disabled = v.Disabled
+		apiDistro.ContainerPool = ToAPIString(v.ContainerPool)
+		apiDistro.TaskPrioritizer = ToAPIString(v.TaskPrioritizer)
+		apiDistro.ResourceLimits = v.ResourceLimits
 	default:
 		return errors.Errorf("incorrect type when fetching converting distro type")
 	}
@@ -41,6 +75,72 @@ func (apiDistro *APIDistro) BuildFromService(h interface{}) error {
 }
 
 // ToService returns a service layer distro using the data from APIDistro.
+// The image ID is only informational; an EC2 distro's AMI is set in its
+// provider settings.
 func (apiDistro *APIDistro) ToService() (interface{}, error) {
-	return nil, errors.Errorf("ToService() is not impelemented for APIDistro")
+	d := distro.Distro{
+		Id:              FromAPIString(apiDistro.Name),
+		Arch:            FromAPIString(apiDistro.Arch),
+		WorkDir:         FromAPIString(apiDistro.WorkDir),
+		PoolSize:        apiDistro.PoolSize,
+		Provider:        FromAPIString(apiDistro.Provider),
+		SetupAsSudo:     apiDistro.SetupAsSudo,
+		Setup:           FromAPIString(apiDistro.Setup),
+		Teardown:        FromAPIString(apiDistro.Teardown),
+		User:            FromAPIString(apiDistro.User),
+		SSHKey:          FromAPIString(apiDistro.SSHKey),
+		SSHOptions:      apiDistro.SSHOptions,
+		SpawnAllowed:    apiDistro.UserSpawnAllowed,
+		Expansions:      apiDistro.Expansions,
+		Disabled:        apiDistro.Disabled,
+		ContainerPool:   FromAPIString(apiDistro.ContainerPool),
+		TaskPrioritizer: FromAPIString(apiDistro.TaskPrioritizer),
+		ResourceLimits:  apiDistro.ResourceLimits,
+	}
+	if apiDistro.ProviderSettings != nil {
+		settings := apiDistro.ProviderSettings
+		d.ProviderSettings = &settings
+	}
+
+	return d, nil
+}
+
+// APIDistroRevision is a distro as it was after one of its changes.
+type APIDistroRevision struct {
+	Revision  int        `json:"revision"`
+	EventType APIString  `json:"event_type"`
+	User      APIString  `json:"user"`
+	Timestamp time.Time  `json:"timestamp"`
+	Distro    *APIDistro `json:"distro"`
+}
+
+// BuildFromService converts a distro revision to an APIDistroRevision.
+func (apiRevision *APIDistroRevision) BuildFromService(h interface{}) error {
+	v, ok := h.(distro.Revision)
+	if !ok {
+		return errors.Errorf("incorrect type when converting distro revision")
+	}
+
+	apiRevision.Revision = v.Number
+	apiRevision.EventType = ToAPIString(v.EventType)
+	apiRevision.User = ToAPIString(v.User)
+	apiRevision.Timestamp = v.Timestamp
+	apiRevision.Distro = &APIDistro{}
+
+	return errors.WithStack(apiRevision.Distro.BuildFromService(v.Distro))
+}
+
+// ToService is not implemented for APIDistroRevision.
+func (apiRevision *APIDistroRevision) ToService() (interface{}, error) {
+	return nil, errors.Errorf("ToService() is not implemented for APIDistroRevision")
+}
+
+// APIDistroChange is the result of creating, updating, or rolling back a
+// distro, or of a dry run of one. It has the fields of the distro that
+// change and the hosts that are, or would be, decommissioned.
+type APIDistroChange struct {
+	Distro              *APIDistro           `json:"distro"`
+	Changes             []distro.FieldChange `json:"changes"`
+	HostsToDecommission []string             `json:"hosts_to_decommission"`
+	DryRun              bool                 `json:"dry_run"`
 }
@@ -45,3 +45,35 @@ func TestDistroAMIForEC2(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, "ami-000000", FromAPIString(apiDistro.ImageID))
 }
+
+func TestDistroToService(t *testing.T) {
+	assert := assert.New(t)
+	d := distro.Distro{
+		Id:       "testId",
+		Arch:     "linux_amd64",
+		Provider: evergreen.ProviderNameEc2Auto,
+		ProviderSettings: &map[string]interface{}{
+			"ami": "ami-000000",
+		},
+		PoolSize:   10,
+		Setup:      "echo setup",
+		User:       "admin",
+		SSHOptions: []string{"StrictHostKeyChecking=no"},
+		Expansions: []distro.Expansion{{Key: "k", Value: "v"}},
+		Disabled:   true,
+	}
+
+	apiDistro := &APIDistro{}
+	assert.NoError(apiDistro.BuildFromService(d))
+	assert.Equal("ami-000000", FromAPIString(apiDistro.ImageID))
+
+	i, err := apiDistro.ToService()
+	assert.NoError(err)
+	assert.Equal(d, i.(distro.Distro))
+
+	// distros without settings have no settings pointer
+	apiDistro = &APIDistro{Name: ToAPIString("static"), Provider: ToAPIString(evergreen.ProviderNameStatic)}
+	i, err = apiDistro.ToService()
+	assert.NoError(err)
+	assert.Nil(i.(distro.Distro).ProviderSettings)
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model/flaky"
+	"github.com/pkg/errors"
+)
+
+// APIFlakyTest is the model to be returned by the API whenever flaky tests
+// are fetched.
+type APIFlakyTest struct {
+	Project        APIString `json:"project"`
+	TaskName       APIString `json:"task_name"`
+	TestFile       APIString `json:"test_file"`
+	Score          float64   `json:"score"`
+	NumFlips       int       `json:"num_flips"`
+	NumRuns        int       `json:"num_runs"`
+	LastFlipTaskId APIString `json:"last_flip_task_id"`
+	LastUpdated    APITime   `json:"last_updated"`
+	Quarantined    bool      `json:"quarantined"`
+	QuarantinedBy  APIString `json:"quarantined_by"`
+	QuarantinedAt  APITime   `json:"quarantined_at"`
+}
+
+// BuildFromService converts from service level structs to an APIFlakyTest.
+func (t *APIFlakyTest) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case flaky.FlakyTest:
+		t.Project = ToAPIString(v.Project)
+		t.TaskName = ToAPIString(v.TaskName)
+		t.TestFile = ToAPIString(v.TestFile)
+		t.Score = v.Score
+		t.NumFlips = v.NumFlips
+		t.NumRuns = v.NumRuns
+		t.LastFlipTaskId = ToAPIString(v.LastFlipTaskId)
+		t.LastUpdated = NewTime(v.LastUpdated)
+		t.Quarantined = v.Quarantined
+		t.QuarantinedBy = ToAPIString(v.QuarantinedBy)
+		t.QuarantinedAt = NewTime(v.QuarantinedAt)
+	default:
+		return errors.Errorf("incorrect type '%T' when converting flaky test", h)
+	}
+	return nil
+}
+
+// ToService is not implemented for APIFlakyTest.
+func (t *APIFlakyTest) ToService() (interface{}, error) {
+	return nil, errors.New("ToService() is not implemented for APIFlakyTest")
+}
+
+// APIQuarantineRequest is the body of a request to quarantine or release a
+// test.
+type APIQuarantineRequest struct {
+	TaskName    string `json:"task_name"`
+	TestFile    string `json:"test_file"`
+	Quarantined bool   `json:"quarantined"`
+}
@@ -8,20 +8,32 @@ import (
 )
 
 type APIProject struct {
-	BatchTime          int         `json:"batch_time"`
-	Branch             APIString   `json:"branch_name"`
-	DisplayName        APIString   `json:"display_name"`
-	Enabled            bool        `json:"enabled"`
-	Identifier         APIString   `json:"identifier"`
-	Owner              APIString   `json:"owner_name"`
-	Private            bool        `json:"private"`
-	RemotePath         APIString   `json:"remote_path"`
-	Repo               APIString   `json:"repo_name"`
-	Tracked            bool        `json:"tracked"`
-	DeactivatePrevious bool        `json:"deactivate_previous"`
-	Admins             []APIString `json:"admins"`
-	TracksPushEvents   bool        `json:"tracks_push_events"`
-	PRTestingEnabled   bool        `json:"pr_testing_enabled"`
+	BatchTime            int                       `json:"batch_time"`
+	Branch               APIString                 `json:"branch_name"`
+	DisplayName          APIString                 `json:"display_name"`
+	Enabled              bool                      `json:"enabled"`
+	Identifier           APIString                 `json:"identifier"`
+	Owner                APIString                 `json:"owner_name"`
+	Private              bool                      `json:"private"`
+	RemotePath           APIString                 `json:"remote_path"`
+	Repo                 APIString                 `json:"repo_name"`
+	RepoKind             APIString                 `json:"repo_kind"`
+	RepoURL              APIString                 `json:"repo_url"`
+	Tracked              bool                      `json:"tracked"`
+	DeactivatePrevious   bool                      `json:"deactivate_previous"`
+	Admins               []APIString               `json:"admins"`
+	TracksPushEvents     bool                      `json:"tracks_push_events"`
+	PRTestingEnabled     bool                      `json:"pr_testing_enabled"`
+	CommitQueue          APICommitQueueParams      `json:"commit_queue"`
+	PatchingDisabled     bool                      `json:"patching_disabled"`
+	NotifyOnBuildFailure bool                      `json:"notify_on_failure"`
+	Schedules            []model.VersionSchedule   `json:"schedules"`
+	Triggers             []model.TriggerDefinition `json:"triggers"`
+}
+
+type APICommitQueueParams struct {
+	Enabled     bool      `json:"enabled"`
+	MergeMethod APIString `json:"merge_method"`
 }
 
 func (apiProject *APIProject) BuildFromService(p interface{}) error {
@@ -38,10 +50,20 @@ func (apiProject *APIProject) BuildFromService(p interface{}) error {
 	apiProject.Private = v.Private
 	apiProject.RemotePath = ToAPIString(v.RemotePath)
 	apiProject.Repo = ToAPIString(v.Repo)
+	apiProject.RepoKind = ToAPIString(v.RepoKind)
+	apiProject.RepoURL = ToAPIString(v.RepoURL)
 	apiProject.Tracked = v.Tracked
 	apiProject.TracksPushEvents = v.TracksPushEvents
 	apiProject.PRTestingEnabled = v.PRTestingEnabled
 	apiProject.DeactivatePrevious = v.DeactivatePrevious
+	apiProject.CommitQueue = APICommitQueueParams{
+		Enabled:     v.CommitQueue.Enabled,
+		MergeMethod: ToAPIString(v.CommitQueue.MergeMethod),
+	}
+	apiProject.PatchingDisabled = v.PatchingDisabled
+	apiProject.NotifyOnBuildFailure = v.NotifyOnBuildFailure
+	apiProject.Schedules = v.Schedules
+	apiProject.Triggers = v.Triggers
 
 	admins := []APIString{}
 	for _, a := range v.Admins {
@@ -52,6 +74,89 @@ func (apiProject *APIProject) BuildFromService(p interface{}) error {
 	return nil
 }
 
+// ToService returns the project ref with the project's settings. Fields that
+// are not settings, such as the repotracker's state, are left empty.
 func (apiProject *APIProject) ToService() (interface{}, error) {
-	return nil, errors.New("not implemented for read-only route")
+	projectRef := model.ProjectRef{
+		BatchTime:          apiProject.BatchTime,
+		Branch:             FromAPIString(apiProject.Branch),
+		DisplayName:        FromAPIString(apiProject.DisplayName),
+		Enabled:            apiProject.Enabled,
+		Identifier:         FromAPIString(apiProject.Identifier),
+		Owner:              FromAPIString(apiProject.Owner),
+		Private:            apiProject.Private,
+		RemotePath:         FromAPIString(apiProject.RemotePath),
+		Repo:               FromAPIString(apiProject.Repo),
+		RepoKind:           FromAPIString(apiProject.RepoKind),
+		RepoURL:            FromAPIString(apiProject.RepoURL),
+		Tracked:            apiProject.Tracked,
+		DeactivatePrevious: apiProject.DeactivatePrevious,
+		TracksPushEvents:   apiProject.TracksPushEvents,
+		PRTestingEnabled:   apiProject.PRTestingEnabled,
+		CommitQueue: model.CommitQueueParams{
+			Enabled:     apiProject.CommitQueue.Enabled,
+			MergeMethod: FromAPIString(apiProject.CommitQueue.MergeMethod),
+		},
+		PatchingDisabled:     apiProject.PatchingDisabled,
+		NotifyOnBuildFailure: apiProject.NotifyOnBuildFailure,
+		Schedules:            apiProject.Schedules,
+		Triggers:             apiProject.Triggers,
+	}
+
+	for _, a := range apiProject.Admins {
+		projectRef.Admins = append(projectRef.Admins, FromAPIString(a))
+	}
+
+	return projectRef, nil
+}
+
+// APIProjectVars is a project's variables. The values of private variables
+// are never returned, so they can be set but not read.
+type APIProjectVars struct {
+	Vars        map[string]string `json:"vars"`
+	PrivateVars map[string]bool   `json:"private_vars"`
+	// VarsToDelete are the variables that an update removes.
+	VarsToDelete []string `json:"vars_to_delete,omitempty"`
+}
+
+// BuildFromService converts project variables to an APIProjectVars,
+// redacting the values of private variables.
+func (apiVars *APIProjectVars) BuildFromService(v interface{}) error {
+	var vars model.ProjectVars
+	switch t := v.(type) {
+	case model.ProjectVars:
+		vars = t
+	case *model.ProjectVars:
+		if t == nil {
+			return errors.New("project vars cannot be nil")
+		}
+		vars = *t
+	default:
+		return fmt.Errorf("incorrect type when converting project vars")
+	}
+
+	apiVars.Vars = map[string]string{}
+	for k, val := range vars.Vars {
+		if vars.PrivateVars[k] {
+			val = ""
+		}
+		apiVars.Vars[k] = val
+	}
+	apiVars.PrivateVars = map[string]bool{}
+	for k, private := range vars.PrivateVars {
+		if private {
+			apiVars.PrivateVars[k] = true
+		}
+	}
+
+	return nil
+}
+
+// ToService returns the variables to set. The variables to delete are not
+// part of the service model.
+func (apiVars *APIProjectVars) ToService() (interface{}, error) {
+	return model.ProjectVars{
+		Vars:        apiVars.Vars,
+		PrivateVars: apiVars.PrivateVars,
+	}, nil
 }
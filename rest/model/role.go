@@ -0,0 +1,64 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/pkg/errors"
+)
+
+// APIRole is a named set of permissions.
+type APIRole struct {
+	Id          APIString `json:"id"`
+	Description APIString `json:"description"`
+	Permissions []string  `json:"permissions"`
+	BuiltIn     bool      `json:"built_in"`
+}
+
+// BuildFromService converts from service level structs to an APIRole.
+func (r *APIRole) BuildFromService(h interface{}) error {
+	v, ok := h.(role.Role)
+	if !ok {
+		return errors.Errorf("incorrect type '%T' when converting role", h)
+	}
+
+	r.Id = ToAPIString(v.Id)
+	r.Description = ToAPIString(v.Description)
+	r.Permissions = append([]string{}, v.Permissions...)
+	r.BuiltIn = role.IsBuiltIn(v.Id)
+
+	return nil
+}
+
+// ToService returns a service layer role using the data from the APIRole.
+func (r *APIRole) ToService() (interface{}, error) {
+	return role.Role{
+		Id:          FromAPIString(r.Id),
+		Description: FromAPIString(r.Description),
+		Permissions: append([]string{}, r.Permissions...),
+	}, nil
+}
+
+// APIRoleAssignment grants a role to a user on a project or, if the project
+// is empty, globally.
+type APIRoleAssignment struct {
+	Role    APIString `json:"role"`
+	Project APIString `json:"project"`
+}
+
+// BuildFromService converts from service level structs to an
+// APIRoleAssignment.
+func (a *APIRoleAssignment) BuildFromService(h interface{}) error {
+	v, ok := h.(role.Assignment)
+	if !ok {
+		return errors.Errorf("incorrect type '%T' when converting role assignment", h)
+	}
+
+	a.Role = ToAPIString(v.Role)
+	a.Project = ToAPIString(v.Project)
+
+	return nil
+}
+
+// ToService is not implemented for APIRoleAssignment.
+func (a *APIRoleAssignment) ToService() (interface{}, error) {
+	return nil, errors.New("ToService() is not implemented for APIRoleAssignment")
+}
@@ -59,10 +59,30 @@ type logLinks struct {
 }
 
 type apiTaskEndDetail struct {
-	Status      APIString `json:"status"`
-	Type        APIString `json:"type"`
-	Description APIString `json:"desc"`
-	TimedOut    bool      `json:"timed_out"`
+	Status        APIString                 `json:"status"`
+	Type          APIString                 `json:"type"`
+	Description   APIString                 `json:"desc"`
+	TimedOut      bool                      `json:"timed_out"`
+	Redactions    int                       `json:"redactions"`
+	Cache         *apiCacheStats            `json:"cache,omitempty"`
+	OOMKilled     bool                      `json:"oom_killed"`
+	ResourceUsage []apiCommandResourceUsage `json:"resource_usage,omitempty"`
+}
+
+type apiCacheStats struct {
+	Hits          int   `json:"hits"`
+	Misses        int   `json:"misses"`
+	Saves         int   `json:"saves"`
+	BytesRestored int64 `json:"bytes_restored"`
+	BytesSaved    int64 `json:"bytes_saved"`
+}
+
+type apiCommandResourceUsage struct {
+	Command         APIString `json:"command"`
+	PeakMemoryBytes int64     `json:"peak_memory_bytes"`
+	CPUSeconds      float64   `json:"cpu_seconds"`
+	PeakProcesses   int       `json:"peak_processes"`
+	OOMKills        int       `json:"oom_kills"`
 }
 
 func (at *APITask) BuildPreviousExecutions(tasks []task.Task) error {
@@ -108,6 +128,8 @@ func (at *APITask) BuildFromService(t interface{}) error {
 				Type:        ToAPIString(v.Details.Type),
 				Description: ToAPIString(v.Details.Description),
 				TimedOut:    v.Details.TimedOut,
+				Redactions:  v.Details.Redactions,
+				OOMKilled:   v.Details.OOMKilled,
 			},
 			Status:           ToAPIString(v.Status),
 			TimeTaken:        NewAPIDuration(v.TimeTaken),
@@ -117,6 +139,25 @@ func (at *APITask) BuildFromService(t interface{}) error {
 			GeneratedBy:      v.GeneratedBy,
 		}
 
+		if v.Details.Cache != nil {
+			at.Details.Cache = &apiCacheStats{
+				Hits:          v.Details.Cache.Hits,
+				Misses:        v.Details.Cache.Misses,
+				Saves:         v.Details.Cache.Saves,
+				BytesRestored: v.Details.Cache.BytesRestored,
+				BytesSaved:    v.Details.Cache.BytesSaved,
+			}
+		}
+		for _, usage := range v.Details.ResourceUsage {
+			at.Details.ResourceUsage = append(at.Details.ResourceUsage, apiCommandResourceUsage{
+				Command:         ToAPIString(usage.Command),
+				PeakMemoryBytes: usage.PeakMemoryBytes,
+				CPUSeconds:      usage.CPUSeconds,
+				PeakProcesses:   usage.PeakProcesses,
+				OOMKills:        usage.OOMKills,
+			})
+		}
+
 		if len(v.DependsOn) > 0 {
 			dependsOn := make([]string, len(v.DependsOn))
 			for i, dep := range v.DependsOn {
@@ -168,6 +209,8 @@ func (ad *APITask) ToService() (interface{}, error) {
 			Type:        FromAPIString(ad.Details.Type),
 			Description: FromAPIString(ad.Details.Description),
 			TimedOut:    ad.Details.TimedOut,
+			Redactions:  ad.Details.Redactions,
+			OOMKilled:   ad.Details.OOMKilled,
 		},
 		Status:           FromAPIString(ad.Status),
 		TimeTaken:        ad.TimeTaken.ToDuration(),
@@ -176,6 +219,24 @@ func (ad *APITask) ToService() (interface{}, error) {
 		GenerateTask:     ad.GenerateTask,
 		GeneratedBy:      ad.GeneratedBy,
 	}
+	if ad.Details.Cache != nil {
+		st.Details.Cache = &apimodels.CacheStats{
+			Hits:          ad.Details.Cache.Hits,
+			Misses:        ad.Details.Cache.Misses,
+			Saves:         ad.Details.Cache.Saves,
+			BytesRestored: ad.Details.Cache.BytesRestored,
+			BytesSaved:    ad.Details.Cache.BytesSaved,
+		}
+	}
+	for _, usage := range ad.Details.ResourceUsage {
+		st.Details.ResourceUsage = append(st.Details.ResourceUsage, apimodels.CommandResourceUsage{
+			Command:         FromAPIString(usage.Command),
+			PeakMemoryBytes: usage.PeakMemoryBytes,
+			CPUSeconds:      usage.CPUSeconds,
+			PeakProcesses:   usage.PeakProcesses,
+			OOMKills:        usage.OOMKills,
+		})
+	}
 	dependsOn := make([]task.Dependency, len(ad.DependsOn))
 
 	for i, depId := range ad.DependsOn {
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/pkg/errors"
+)
+
+// APITaskGraph is the model to be returned by the API whenever the task
+// dependency graph of a version is fetched.
+type APITaskGraph struct {
+	VersionId            APIString          `json:"version_id"`
+	Nodes                []APITaskGraphNode `json:"nodes"`
+	Edges                []APITaskGraphEdge `json:"edges"`
+	CriticalPath         []APIString        `json:"critical_path"`
+	CriticalPathDuration APIDuration        `json:"critical_path_duration_ms"`
+}
+
+// APITaskGraphNode is a task in a dependency graph.
+type APITaskGraphNode struct {
+	TaskId         APIString   `json:"task_id"`
+	DisplayName    APIString   `json:"display_name"`
+	BuildVariant   APIString   `json:"build_variant"`
+	Status         APIString   `json:"status"`
+	Duration       APIDuration `json:"duration_ms"`
+	Estimated      bool        `json:"estimated"`
+	EarliestStart  APIDuration `json:"earliest_start_ms"`
+	EarliestFinish APIDuration `json:"earliest_finish_ms"`
+	Slack          APIDuration `json:"slack_ms"`
+	OnCriticalPath bool        `json:"on_critical_path"`
+}
+
+// APITaskGraphEdge is a dependency of the "to" task on the "from" task.
+type APITaskGraphEdge struct {
+	From   APIString `json:"from"`
+	To     APIString `json:"to"`
+	Status APIString `json:"status"`
+}
+
+// BuildFromService converts from a service level dependency graph by loading
+// the data into the appropriate fields of the APITaskGraph.
+func (g *APITaskGraph) BuildFromService(h interface{}) error {
+	v, ok := h.(*task.DependencyGraph)
+	if !ok {
+		return errors.Errorf("incorrect type %T when converting task graph", h)
+	}
+
+	g.VersionId = ToAPIString(v.VersionId)
+	g.Nodes = make([]APITaskGraphNode, 0, len(v.Nodes))
+	for _, n := range v.Nodes {
+		g.Nodes = append(g.Nodes, APITaskGraphNode{
+			TaskId:         ToAPIString(n.TaskId),
+			DisplayName:    ToAPIString(n.DisplayName),
+			BuildVariant:   ToAPIString(n.BuildVariant),
+			Status:         ToAPIString(n.Status),
+			Duration:       NewAPIDuration(n.Duration),
+			Estimated:      n.Estimated,
+			EarliestStart:  NewAPIDuration(n.EarliestStart),
+			EarliestFinish: NewAPIDuration(n.EarliestFinish),
+			Slack:          NewAPIDuration(n.Slack),
+			OnCriticalPath: n.OnCriticalPath,
+		})
+	}
+	g.Edges = make([]APITaskGraphEdge, 0, len(v.Edges))
+	for _, e := range v.Edges {
+		g.Edges = append(g.Edges, APITaskGraphEdge{
+			From:   ToAPIString(e.From),
+			To:     ToAPIString(e.To),
+			Status: ToAPIString(e.Status),
+		})
+	}
+	g.CriticalPath = make([]APIString, 0, len(v.Critical.TaskIds))
+	for _, id := range v.Critical.TaskIds {
+		g.CriticalPath = append(g.CriticalPath, ToAPIString(id))
+	}
+	g.CriticalPathDuration = NewAPIDuration(v.Critical.Duration)
+
+	return nil
+}
+
+// ToService is not implemented for APITaskGraph.
+func (g *APITaskGraph) ToService() (interface{}, error) {
+	return nil, errors.New("ToService() is not implemented for APITaskGraph")
+}
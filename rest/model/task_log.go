@@ -0,0 +1,45 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/pkg/errors"
+)
+
+// APILogMessage is a single message of a task log.
+type APILogMessage struct {
+	Type      APIString `json:"type"`
+	Severity  APIString `json:"severity"`
+	Message   APIString `json:"message"`
+	Timestamp APITime   `json:"timestamp"`
+}
+
+// BuildFromService converts from service level structs to an APILogMessage.
+func (m *APILogMessage) BuildFromService(h interface{}) error {
+	v, ok := h.(apimodels.LogMessage)
+	if !ok {
+		return errors.Errorf("incorrect type '%T' when converting log message", h)
+	}
+
+	m.Type = ToAPIString(v.Type)
+	m.Severity = ToAPIString(v.Severity)
+	m.Message = ToAPIString(v.Message)
+	m.Timestamp = NewTime(v.Timestamp)
+
+	return nil
+}
+
+// ToService is not implemented for APILogMessage.
+func (m *APILogMessage) ToService() (interface{}, error) {
+	return nil, errors.New("ToService() is not implemented for APILogMessage")
+}
+
+// APITaskLogChunk is a group of consecutive messages of a streamed task log.
+// Execution is the task execution the log belongs to, NextOffset is the
+// offset to resume the stream from after the chunk, and Finished is true once
+// the task has finished and the log is complete.
+type APITaskLogChunk struct {
+	Messages   []APILogMessage `json:"messages"`
+	Execution  int             `json:"execution"`
+	NextOffset int             `json:"next_offset"`
+	Finished   bool            `json:"finished"`
+}
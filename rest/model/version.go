@@ -25,6 +25,31 @@ type APIVersion struct {
 	Errors   []APIString `json:"errors"`
 	Warnings []APIString `json:"warnings"`
 	Ignored  bool        `json:"ignored"`
+
+	Requester   APIString `json:"requester"`
+	TriggerID   APIString `json:"trigger_id"`
+	TriggerType APIString `json:"trigger_type"`
+
+	Parameters []APIParameter `json:"parameters,omitempty"`
+}
+
+// APIParameter is the value of a project parameter for a version.
+type APIParameter struct {
+	Key   APIString `json:"key"`
+	Value APIString `json:"value"`
+}
+
+// APIManualVersionRequest is the body of a request to start a manual
+// version of a project. Parameters map the names of the parameters the
+// project declares to their values; Variants, Tasks, and Alias select the
+// tasks to run, and default to all of them.
+type APIManualVersionRequest struct {
+	Revision   string            `json:"revision"`
+	Parameters map[string]string `json:"parameters"`
+	Variants   []string          `json:"variants"`
+	Tasks      []string          `json:"tasks"`
+	Alias      string            `json:"alias"`
+	Message    string            `json:"message"`
 }
 
 type buildDetail struct {
@@ -51,6 +76,15 @@ func (apiVersion *APIVersion) BuildFromService(h interface{}) error {
 	apiVersion.Repo = ToAPIString(v.Repo)
 	apiVersion.Branch = ToAPIString(v.Branch)
 	apiVersion.Order = v.RevisionOrderNumber
+	apiVersion.Requester = ToAPIString(v.Requester)
+	apiVersion.TriggerID = ToAPIString(v.TriggerID)
+	apiVersion.TriggerType = ToAPIString(v.TriggerType)
+	for _, param := range v.Parameters {
+		apiVersion.Parameters = append(apiVersion.Parameters, APIParameter{
+			Key:   ToAPIString(param.Key),
+			Value: ToAPIString(param.Value),
+		})
+	}
 
 	var bd buildDetail
 	for _, t := range v.BuildVariants {
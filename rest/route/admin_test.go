@@ -118,6 +118,7 @@ func (s *AdminRouteSuite) TestAdminRoute() {
 	s.EqualValues(testSettings.Providers.AWS.Id, settings.Providers.AWS.Id)
 	s.EqualValues(testSettings.Providers.Docker.APIVersion, settings.Providers.Docker.APIVersion)
 	s.EqualValues(testSettings.Providers.GCE.ClientEmail, settings.Providers.GCE.ClientEmail)
+	s.EqualValues(testSettings.Providers.Kubernetes.APIServer, settings.Providers.Kubernetes.APIServer)
 	s.EqualValues(testSettings.Providers.OpenStack.IdentityEndpoint, settings.Providers.OpenStack.IdentityEndpoint)
 	s.EqualValues(testSettings.Providers.VSphere.Host, settings.Providers.VSphere.Host)
 	s.EqualValues(testSettings.RepoTracker.MaxConcurrentRequests, settings.RepoTracker.MaxConcurrentRequests)
@@ -0,0 +1,298 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/evergreen-ci/evergreen/model/commitqueue"
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/commit_queue/{project_id}
+
+type commitQueueGetHandler struct {
+	projectId string
+	sc        data.Connector
+}
+
+func makeGetCommitQueue(sc data.Connector) gimlet.RouteHandler {
+	return &commitQueueGetHandler{
+		sc: sc,
+	}
+}
+
+func (h *commitQueueGetHandler) Factory() gimlet.RouteHandler {
+	return &commitQueueGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *commitQueueGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	projectId, err := commitQueueProjectId(ctx)
+	h.projectId = projectId
+	return err
+}
+
+func (h *commitQueueGetHandler) Run(ctx context.Context) gimlet.Responder {
+	queue, err := h.sc.FindCommitQueueByID(h.projectId)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	queueModel := &model.APICommitQueue{}
+	if err = queueModel.BuildFromService(queue); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(queueModel)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PUT /rest/v2/commit_queue/{project_id}/{item}
+
+type commitQueueEnqueueItemHandler struct {
+	projectId string
+	item      string
+	sc        data.Connector
+}
+
+func makeCommitQueueEnqueueItem(sc data.Connector) gimlet.RouteHandler {
+	return &commitQueueEnqueueItemHandler{
+		sc: sc,
+	}
+}
+
+func (h *commitQueueEnqueueItemHandler) Factory() gimlet.RouteHandler {
+	return &commitQueueEnqueueItemHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *commitQueueEnqueueItemHandler) Parse(ctx context.Context, r *http.Request) error {
+	projectId, err := commitQueueProjectId(ctx)
+	if err != nil {
+		return err
+	}
+	h.projectId = projectId
+	h.item = gimlet.GetVars(r)["item"]
+
+	return nil
+}
+
+// Run adds the pull request to the commit queue. As with enqueueing by
+// commenting on the pull request, the user must be able to edit the project,
+// and the pull request must be theirs or a committer's.
+func (h *commitQueueEnqueueItemHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+	projectRef := MustHaveProjectContext(ctx).ProjectRef
+
+	allowed, err := hasPermission(h.sc, u, role.PermissionEditProjectSettings, projectRef)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+	if !allowed {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    fmt.Sprintf("user '%s' cannot add to the commit queue of project '%s'", u.Username(), h.projectId),
+		})
+	}
+
+	prNumber, err := strconv.Atoi(h.item)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("'%s' is not a pull request number", h.item),
+		})
+	}
+	pr, err := h.sc.GetGitHubPR(ctx, projectRef.Owner, projectRef.Repo, prNumber)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "can't get pull request %d", prNumber))
+	}
+	if base := pr.GetBase().GetRef(); base != projectRef.Branch {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("pull request %d is against branch '%s', not '%s'", prNumber, base, projectRef.Branch),
+		})
+	}
+
+	githubLogin := u.Settings.GithubUser.LastKnownAs
+	if author := pr.GetUser().GetLogin(); githubLogin == "" || author != githubLogin {
+		isCommitter, err := h.sc.IsGitHubCommitter(ctx, projectRef.Owner, projectRef.Repo, author)
+		if err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "can't check whether '%s' is a committer", author))
+		}
+		if !isCommitter {
+			return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+				StatusCode: http.StatusUnauthorized,
+				Message:    fmt.Sprintf("pull request %d is by '%s', who is not a committer to %s/%s", prNumber, author, projectRef.Owner, projectRef.Repo),
+			})
+		}
+	}
+
+	position, err := h.sc.EnqueueItem(h.projectId, commitqueue.CommitQueueItem{
+		Issue:    h.item,
+		Enqueuer: u.Username(),
+	})
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "can't enqueue item"))
+	}
+
+	return gimlet.NewJSONResponse(model.APICommitQueuePosition{Position: position})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// DELETE /rest/v2/commit_queue/{project_id}/{item}
+
+type commitQueueDeleteItemHandler struct {
+	projectId string
+	item      string
+	sc        data.Connector
+}
+
+func makeCommitQueueDeleteItem(sc data.Connector) gimlet.RouteHandler {
+	return &commitQueueDeleteItemHandler{
+		sc: sc,
+	}
+}
+
+func (h *commitQueueDeleteItemHandler) Factory() gimlet.RouteHandler {
+	return &commitQueueDeleteItemHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *commitQueueDeleteItemHandler) Parse(ctx context.Context, r *http.Request) error {
+	projectId, err := commitQueueProjectId(ctx)
+	if err != nil {
+		return err
+	}
+	h.projectId = projectId
+	h.item = gimlet.GetVars(r)["item"]
+
+	return nil
+}
+
+func (h *commitQueueDeleteItemHandler) Run(ctx context.Context) gimlet.Responder {
+	queue, err := h.sc.FindCommitQueueByID(h.projectId)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	position := queue.FindItem(h.item)
+	if position < 0 {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "item not in commit queue",
+		})
+	}
+
+	// users may remove their own items; anything else requires a project admin
+	u := MustHaveUser(ctx)
+	if queue.Queue[position].Enqueuer != u.Username() {
+		admin, err := isProjectAdmin(ctx, h.sc)
+		if err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+		if !admin {
+			return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+				StatusCode: http.StatusUnauthorized,
+				Message:    "only project admins can remove items enqueued by other users",
+			})
+		}
+	}
+
+	removed, err := h.sc.CommitQueueRemoveItem(h.projectId, h.item)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "can't remove item"))
+	}
+	if !removed {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "item not in commit queue",
+		})
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PATCH /rest/v2/commit_queue/{project_id}/{item}
+
+type commitQueueMoveItemHandler struct {
+	projectId string
+	item      string
+	position  model.APICommitQueuePosition
+	sc        data.Connector
+}
+
+func makeCommitQueueMoveItem(sc data.Connector) gimlet.RouteHandler {
+	return &commitQueueMoveItemHandler{
+		sc: sc,
+	}
+}
+
+func (h *commitQueueMoveItemHandler) Factory() gimlet.RouteHandler {
+	return &commitQueueMoveItemHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *commitQueueMoveItemHandler) Parse(ctx context.Context, r *http.Request) error {
+	projectId, err := commitQueueProjectId(ctx)
+	if err != nil {
+		return err
+	}
+	admin, err := isProjectAdmin(ctx, h.sc)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if !admin {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "only project admins can reorder the commit queue",
+		}
+	}
+	h.projectId = projectId
+	h.item = gimlet.GetVars(r)["item"]
+
+	if err = gimlet.GetJSON(r.Body, &h.position); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+
+	return nil
+}
+
+func (h *commitQueueMoveItemHandler) Run(ctx context.Context) gimlet.Responder {
+	if err := h.sc.CommitQueueMoveItem(h.projectId, h.item, h.position.Position); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "can't move item"))
+	}
+
+	return gimlet.NewJSONResponse(h.position)
+}
+
+func commitQueueProjectId(ctx context.Context) (string, error) {
+	projCtx := MustHaveProjectContext(ctx)
+	if projCtx.ProjectRef == nil {
+		return "", gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "Project not found",
+		}
+	}
+	return projCtx.ProjectRef.Identifier, nil
+}
+
+// isProjectAdmin returns true if the user can edit the settings of the
+// project in the context.
+func isProjectAdmin(ctx context.Context, sc data.Connector) (bool, error) {
+	return hasPermission(sc, MustHaveUser(ctx), role.PermissionEditProjectSettings, MustHaveProjectContext(ctx).ProjectRef)
+}
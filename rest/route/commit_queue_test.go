@@ -0,0 +1,257 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/commitqueue"
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/google/go-github/github"
+	"github.com/stretchr/testify/suite"
+)
+
+type CommitQueueSuite struct {
+	sc  *data.MockConnector
+	ctx context.Context
+	suite.Suite
+}
+
+func TestCommitQueueSuite(t *testing.T) {
+	suite.Run(t, new(CommitQueueSuite))
+}
+
+func (s *CommitQueueSuite) SetupTest() {
+	s.sc = &data.MockConnector{MockCommitQueueConnector: data.MockCommitQueueConnector{
+		CachedCommitQueues: map[string]*commitqueue.CommitQueue{
+			"mci": {
+				ProjectID: "mci",
+				Queue: []commitqueue.CommitQueueItem{
+					{Issue: "1", Enqueuer: "octocat"},
+					{Issue: "2", Enqueuer: "someone"},
+					{Issue: "3", Enqueuer: "admin"},
+				},
+			},
+		},
+	}}
+	s.sc.SetSuperUsers([]string{"root"})
+
+	s.ctx = gimlet.AttachUser(context.Background(), &user.DBUser{Id: "admin"})
+	s.ctx = context.WithValue(s.ctx, RequestContext, &model.Context{
+		ProjectRef: &model.ProjectRef{Identifier: "mci", Owner: "evergreen-ci", Repo: "evergreen", Branch: "master", Admins: []string{"admin"}},
+	})
+}
+
+func (s *CommitQueueSuite) issues() []string {
+	queue, err := s.sc.FindCommitQueueByID("mci")
+	s.Require().NoError(err)
+	issues := []string{}
+	for _, item := range queue.Queue {
+		issues = append(issues, item.Issue)
+	}
+	return issues
+}
+
+func (s *CommitQueueSuite) TestGetCommitQueue() {
+	h := makeGetCommitQueue(s.sc).(*commitQueueGetHandler)
+	s.NoError(h.Parse(s.ctx, nil))
+
+	resp := h.Run(s.ctx)
+	s.Equal(http.StatusOK, resp.Status())
+	queue, ok := resp.Data().(*restModel.APICommitQueue)
+	s.Require().True(ok)
+	s.Equal("mci", restModel.FromAPIString(queue.ProjectID))
+	s.Require().Len(queue.Queue, 3)
+	s.Equal("1", restModel.FromAPIString(queue.Queue[0].Issue))
+}
+
+func (s *CommitQueueSuite) TestEnqueueItem() {
+	s.sc.CachedPullRequests = map[int]*github.PullRequest{
+		4: {Base: &github.PullRequestBranch{Ref: github.String("master")}, User: &github.User{Login: github.String("octocat")}},
+		5: {Base: &github.PullRequestBranch{Ref: github.String("master")}, User: &github.User{Login: github.String("stranger")}},
+		6: {Base: &github.PullRequestBranch{Ref: github.String("master")}, User: &github.User{Login: github.String("committer")}},
+		7: {Base: &github.PullRequestBranch{Ref: github.String("release")}, User: &github.User{Login: github.String("committer")}},
+	}
+	s.sc.CachedCommitters = []string{"committer"}
+	ctx := gimlet.AttachUser(s.ctx, &user.DBUser{Id: "admin", Settings: user.UserSettings{GithubUser: user.GithubUser{UID: 1, LastKnownAs: "octocat"}}})
+
+	h := makeCommitQueueEnqueueItem(s.sc).(*commitQueueEnqueueItemHandler)
+	h.projectId = "mci"
+	h.item = "4"
+
+	resp := h.Run(ctx)
+	s.Equal(http.StatusOK, resp.Status())
+	s.Equal(restModel.APICommitQueuePosition{Position: 3}, resp.Data())
+	s.Equal([]string{"1", "2", "3", "4"}, s.issues())
+
+	// admins can enqueue committers' pull requests
+	h.item = "6"
+	s.Equal(http.StatusOK, h.Run(ctx).Status())
+
+	// but not pull requests by anyone else, or against other branches
+	h.item = "5"
+	s.Equal(http.StatusUnauthorized, h.Run(ctx).Status())
+	h.item = "7"
+	s.Equal(http.StatusBadRequest, h.Run(ctx).Status())
+	h.item = "not-a-number"
+	s.Equal(http.StatusBadRequest, h.Run(ctx).Status())
+
+	// users who can't edit the project can't enqueue anything
+	h.item = "6"
+	s.Equal(http.StatusUnauthorized, h.Run(gimlet.AttachUser(s.ctx, &user.DBUser{Id: "someone"})).Status())
+
+	s.Equal([]string{"1", "2", "3", "4", "6"}, s.issues())
+}
+
+func (s *CommitQueueSuite) TestDeleteItem() {
+	h := makeCommitQueueDeleteItem(s.sc).(*commitQueueDeleteItemHandler)
+	h.projectId = "mci"
+	h.item = "2"
+
+	resp := h.Run(s.ctx)
+	s.Equal(http.StatusOK, resp.Status())
+	s.Equal([]string{"1", "3"}, s.issues())
+
+	h.item = "2"
+	resp = h.Run(s.ctx)
+	s.Equal(http.StatusNotFound, resp.Status())
+}
+
+func (s *CommitQueueSuite) TestDeleteItemRequiresEnqueuerOrAdmin() {
+	ctx := gimlet.AttachUser(s.ctx, &user.DBUser{Id: "someone"})
+	h := makeCommitQueueDeleteItem(s.sc).(*commitQueueDeleteItemHandler)
+	h.projectId = "mci"
+
+	h.item = "1"
+	resp := h.Run(ctx)
+	s.Equal(http.StatusUnauthorized, resp.Status())
+
+	h.item = "2"
+	resp = h.Run(ctx)
+	s.Equal(http.StatusOK, resp.Status())
+	s.Equal([]string{"1", "3"}, s.issues())
+}
+
+func (s *CommitQueueSuite) TestMoveItem() {
+	h := makeCommitQueueMoveItem(s.sc).(*commitQueueMoveItemHandler)
+	h.projectId = "mci"
+	h.item = "3"
+	h.position = restModel.APICommitQueuePosition{Position: 0}
+
+	resp := h.Run(s.ctx)
+	s.Equal(http.StatusOK, resp.Status())
+	s.Equal([]string{"3", "1", "2"}, s.issues())
+
+	h.position = restModel.APICommitQueuePosition{Position: 5}
+	resp = h.Run(s.ctx)
+	s.Equal(http.StatusBadRequest, resp.Status())
+}
+
+func (s *CommitQueueSuite) TestMoveItemRequiresAdmin() {
+	ctx := gimlet.AttachUser(s.ctx, &user.DBUser{Id: "someone"})
+	h := makeCommitQueueMoveItem(s.sc).(*commitQueueMoveItemHandler)
+
+	req, err := http.NewRequest(http.MethodPatch, "/commit_queue/mci/3", bytes.NewBufferString(`{"position": 0}`))
+	s.Require().NoError(err)
+	err = h.Parse(ctx, req)
+	s.Require().Error(err)
+	resp, ok := err.(gimlet.ErrorResponse)
+	s.Require().True(ok)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (s *CommitQueueSuite) TestProjectAdminRoleCanManageQueue() {
+	s.sc.MockRoleConnector.CachedAssignments = []role.Assignment{role.NewAssignment("someone", role.ProjectAdminRole, "mci")}
+	ctx := gimlet.AttachUser(s.ctx, &user.DBUser{Id: "someone"})
+
+	move := makeCommitQueueMoveItem(s.sc).(*commitQueueMoveItemHandler)
+	req, err := http.NewRequest(http.MethodPatch, "/commit_queue/mci/3", bytes.NewBufferString(`{"position": 0}`))
+	s.Require().NoError(err)
+	s.NoError(move.Parse(ctx, req))
+
+	remove := makeCommitQueueDeleteItem(s.sc).(*commitQueueDeleteItemHandler)
+	remove.projectId = "mci"
+	remove.item = "1"
+	s.Equal(http.StatusOK, remove.Run(ctx).Status())
+	s.Equal([]string{"2", "3"}, s.issues())
+}
+
+func (s *CommitQueueSuite) TestCommentTriggersEnqueue() {
+	s.sc.CachedPullRequests = map[int]*github.PullRequest{
+		5: {Base: &github.PullRequestBranch{Ref: github.String("master")}, User: &github.User{Login: github.String("octocat")}},
+		6: {Base: &github.PullRequestBranch{Ref: github.String("master")}, User: &github.User{Login: github.String("stranger")}},
+		7: {Base: &github.PullRequestBranch{Ref: github.String("master")}, User: &github.User{Login: github.String("committer")}},
+	}
+	s.sc.CachedCommitQueueRefs = []model.ProjectRef{
+		{Identifier: "mci", Owner: "evergreen-ci", Repo: "evergreen", Branch: "master", CommitQueue: model.CommitQueueParams{Enabled: true}, Admins: []string{"admin"}},
+	}
+	s.sc.CachedCommitters = []string{"committer"}
+	s.sc.MockUserConnector.CachedUsers = map[string]*user.DBUser{
+		"admin":  {Id: "admin", Settings: user.UserSettings{GithubUser: user.GithubUser{UID: 1, LastKnownAs: "octocat"}}},
+		"nobody": {Id: "nobody", Settings: user.UserSettings{GithubUser: user.GithubUser{UID: 2, LastKnownAs: "stranger"}}},
+	}
+
+	event := &github.IssueCommentEvent{
+		Action: github.String("created"),
+		Issue: &github.Issue{
+			Number:           github.Int(5),
+			PullRequestLinks: &github.PullRequestLinks{},
+		},
+		Comment: &github.IssueComment{
+			Body: github.String("  evergreen merge\n"),
+			User: &github.User{ID: github.Int(1), Login: github.String("octocat")},
+		},
+		Repo: &github.Repository{
+			Name:  github.String("evergreen"),
+			Owner: &github.User{Login: github.String("evergreen-ci")},
+		},
+	}
+
+	gh := &githubHookApi{sc: s.sc, event: event}
+	resp := gh.Run(context.Background())
+	s.Equal(http.StatusOK, resp.Status())
+	s.Equal([]string{"1", "2", "3", "5"}, s.issues())
+
+	queue, err := s.sc.FindCommitQueueByID("mci")
+	s.NoError(err)
+	s.Equal("octocat", queue.Queue[3].Enqueuer)
+
+	// admins can enqueue committers' pull requests
+	event.Issue.Number = github.Int(7)
+	resp = gh.Run(context.Background())
+	s.Equal(http.StatusOK, resp.Status())
+	s.Equal([]string{"1", "2", "3", "5", "7"}, s.issues())
+
+	// but not pull requests by anyone else
+	event.Issue.Number = github.Int(6)
+	resp = gh.Run(context.Background())
+	s.Equal(http.StatusUnauthorized, resp.Status())
+	s.Len(s.issues(), 5)
+
+	// users who can't edit the project can't enqueue, even their own pull
+	// requests
+	event.Comment.User = &github.User{ID: github.Int(2), Login: github.String("stranger")}
+	resp = gh.Run(context.Background())
+	s.Equal(http.StatusUnauthorized, resp.Status())
+	s.Len(s.issues(), 5)
+
+	// nor can GitHub users without an Evergreen user
+	event.Comment.User = &github.User{ID: github.Int(3), Login: github.String("committer")}
+	event.Issue.Number = github.Int(7)
+	resp = gh.Run(context.Background())
+	s.Equal(http.StatusUnauthorized, resp.Status())
+
+	// other comments are ignored
+	event.Comment.User = &github.User{ID: github.Int(1), Login: github.String("octocat")}
+	event.Issue.Number = github.Int(6)
+	event.Comment.Body = github.String("looks good")
+	resp = gh.Run(context.Background())
+	s.Equal(http.StatusOK, resp.Status())
+	s.Len(s.issues(), 5)
+}
@@ -2,10 +2,16 @@ package route
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strconv"
 
+	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/pkg/errors"
 )
@@ -55,3 +61,520 @@ func (dgh *distroGetHandler) Run(ctx context.Context) gimlet.Responder {
 
 	return resp
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/distros/{distro_id}
+
+type distroIDGetHandler struct {
+	distroID string
+	sc       data.Connector
+}
+
+func makeGetDistroByID(sc data.Connector) gimlet.RouteHandler {
+	return &distroIDGetHandler{
+		sc: sc,
+	}
+}
+
+func (h *distroIDGetHandler) Factory() gimlet.RouteHandler {
+	return &distroIDGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *distroIDGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.distroID = gimlet.GetVars(r)["distro_id"]
+	return nil
+}
+
+func (h *distroIDGetHandler) Run(ctx context.Context) gimlet.Responder {
+	d, err := h.sc.FindDistroById(h.distroID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	distroModel := &model.APIDistro{}
+	if err = distroModel.BuildFromService(*d); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem converting distro"))
+	}
+
+	return gimlet.NewJSONResponse(distroModel)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PUT /rest/v2/distros/{distro_id}?decommission={bool}&dry_run={bool}
+
+type distroIDPutHandler struct {
+	distroID     string
+	distro       model.APIDistro
+	userID       string
+	decommission bool
+	dryRun       bool
+	sc           data.Connector
+}
+
+func makePutDistro(sc data.Connector) gimlet.RouteHandler {
+	return &distroIDPutHandler{
+		sc: sc,
+	}
+}
+
+func (h *distroIDPutHandler) Factory() gimlet.RouteHandler {
+	return &distroIDPutHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *distroIDPutHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.userID = MustHaveUser(ctx).Username()
+	h.distroID = gimlet.GetVars(r)["distro_id"]
+	h.decommission, h.dryRun = parseDistroChangeOptions(r)
+	if err := gimlet.GetJSON(r.Body, &h.distro); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+	h.distro.Name = model.ToAPIString(h.distroID)
+
+	return nil
+}
+
+// Run creates the distro if it does not exist, and replaces it otherwise.
+func (h *distroIDPutHandler) Run(ctx context.Context) gimlet.Responder {
+	i, err := h.distro.ToService()
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem converting distro"))
+	}
+	d := i.(distro.Distro)
+
+	return changeDistro(ctx, h.sc, &d, h.userID, h.decommission, h.dryRun)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PATCH /rest/v2/distros/{distro_id}?decommission={bool}&dry_run={bool}
+
+type distroIDPatchHandler struct {
+	distroID     string
+	body         []byte
+	userID       string
+	decommission bool
+	dryRun       bool
+	sc           data.Connector
+}
+
+func makePatchDistro(sc data.Connector) gimlet.RouteHandler {
+	return &distroIDPatchHandler{
+		sc: sc,
+	}
+}
+
+func (h *distroIDPatchHandler) Factory() gimlet.RouteHandler {
+	return &distroIDPatchHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *distroIDPatchHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.userID = MustHaveUser(ctx).Username()
+	h.distroID = gimlet.GetVars(r)["distro_id"]
+	h.decommission, h.dryRun = parseDistroChangeOptions(r)
+	body := util.NewRequestReader(r)
+	defer body.Close()
+
+	var err error
+	h.body, err = ioutil.ReadAll(body)
+	return errors.Wrap(err, "problem reading request body")
+}
+
+// Run applies the request to the distro, so fields missing from the request
+// are unchanged.
+func (h *distroIDPatchHandler) Run(ctx context.Context) gimlet.Responder {
+	oldDistro, err := h.sc.FindDistroById(h.distroID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	distroModel := &model.APIDistro{}
+	if err = distroModel.BuildFromService(*oldDistro); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem converting distro"))
+	}
+	if err = json.Unmarshal(h.body, distroModel); err != nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("problem parsing request body: %s", err.Error()),
+		})
+	}
+	distroModel.Name = model.ToAPIString(h.distroID)
+
+	i, err := distroModel.ToService()
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem converting distro"))
+	}
+	d := i.(distro.Distro)
+
+	return changeDistro(ctx, h.sc, &d, h.userID, h.decommission, h.dryRun)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// DELETE /rest/v2/distros/{distro_id}
+
+type distroIDDeleteHandler struct {
+	distroID string
+	userID   string
+	sc       data.Connector
+}
+
+func makeDeleteDistro(sc data.Connector) gimlet.RouteHandler {
+	return &distroIDDeleteHandler{
+		sc: sc,
+	}
+}
+
+func (h *distroIDDeleteHandler) Factory() gimlet.RouteHandler {
+	return &distroIDDeleteHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *distroIDDeleteHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.userID = MustHaveUser(ctx).Username()
+	h.distroID = gimlet.GetVars(r)["distro_id"]
+	return nil
+}
+
+func (h *distroIDDeleteHandler) Run(ctx context.Context) gimlet.Responder {
+	if err := h.sc.DeleteDistro(h.distroID, h.userID); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem deleting distro '%s'", h.distroID))
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/distros/{distro_id}/copy?new_distro={new_distro_id}
+
+type distroCopyHandler struct {
+	oldDistroID string
+	newDistroID string
+	userID      string
+	sc          data.Connector
+}
+
+func makeCopyDistro(sc data.Connector) gimlet.RouteHandler {
+	return &distroCopyHandler{
+		sc: sc,
+	}
+}
+
+func (h *distroCopyHandler) Factory() gimlet.RouteHandler {
+	return &distroCopyHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *distroCopyHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.userID = MustHaveUser(ctx).Username()
+	h.oldDistroID = gimlet.GetVars(r)["distro_id"]
+	h.newDistroID = r.URL.Query().Get("new_distro")
+	if h.newDistroID == "" {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "must provide new distro ID",
+		}
+	}
+
+	return nil
+}
+
+func (h *distroCopyHandler) Run(ctx context.Context) gimlet.Responder {
+	d, err := h.sc.FindDistroById(h.oldDistroID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	d.Id = h.newDistroID
+	if err = h.sc.CreateDistro(ctx, d, h.userID); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem copying distro '%s'", h.oldDistroID))
+	}
+
+	distroModel := &model.APIDistro{}
+	if err = distroModel.BuildFromService(*d); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem converting distro"))
+	}
+	resp := gimlet.NewJSONResponse(distroModel)
+	if err = resp.SetStatus(http.StatusCreated); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem setting response status"))
+	}
+
+	return resp
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/distros/{distro_id}/revisions
+
+type distroRevisionsGetHandler struct {
+	distroID string
+	sc       data.Connector
+}
+
+func makeGetDistroRevisions(sc data.Connector) gimlet.RouteHandler {
+	return &distroRevisionsGetHandler{
+		sc: sc,
+	}
+}
+
+func (h *distroRevisionsGetHandler) Factory() gimlet.RouteHandler {
+	return &distroRevisionsGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *distroRevisionsGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.distroID = gimlet.GetVars(r)["distro_id"]
+	return nil
+}
+
+func (h *distroRevisionsGetHandler) Run(ctx context.Context) gimlet.Responder {
+	revisions, err := h.sc.FindDistroRevisions(h.distroID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	if len(revisions) == 0 {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("no revisions found for distro '%s'", h.distroID),
+		})
+	}
+
+	resp := gimlet.NewResponseBuilder()
+	if err = resp.SetFormat(gimlet.JSON); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	for _, revision := range revisions {
+		revisionModel := &model.APIDistroRevision{}
+		if err = revisionModel.BuildFromService(revision); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem converting distro revision"))
+		}
+		if err = resp.AddData(revisionModel); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+	}
+
+	return resp
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/distros/{distro_id}/revisions/{revision}/diff?against={revision}
+
+type distroRevisionDiffHandler struct {
+	distroID string
+	revision int
+	against  int
+	sc       data.Connector
+}
+
+func makeDiffDistroRevision(sc data.Connector) gimlet.RouteHandler {
+	return &distroRevisionDiffHandler{
+		sc: sc,
+	}
+}
+
+func (h *distroRevisionDiffHandler) Factory() gimlet.RouteHandler {
+	return &distroRevisionDiffHandler{
+		sc: h.sc,
+	}
+}
+
+// Parse reads the revisions to compare. By default, a revision is compared
+// against the one before it; revision 0 is an empty distro.
+func (h *distroRevisionDiffHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	h.distroID = gimlet.GetVars(r)["distro_id"]
+	h.revision, err = parseDistroRevision(gimlet.GetVars(r)["revision"])
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	h.against = h.revision - 1
+	if against := r.URL.Query().Get("against"); against != "" {
+		h.against, err = parseDistroRevision(against)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return nil
+}
+
+func (h *distroRevisionDiffHandler) Run(ctx context.Context) gimlet.Responder {
+	revisions, err := h.sc.FindDistroRevisions(h.distroID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	newRevision, err := findDistroRevision(h.distroID, revisions, h.revision)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	oldRevision, err := findDistroRevision(h.distroID, revisions, h.against)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	changes, err := distro.Diff(oldRevision.Distro, newRevision.Distro)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem comparing distro revisions"))
+	}
+
+	return gimlet.NewJSONResponse(changes)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/distros/{distro_id}/revisions/{revision}/rollback?decommission={bool}&dry_run={bool}
+
+type distroRollbackHandler struct {
+	distroID     string
+	revision     int
+	userID       string
+	decommission bool
+	dryRun       bool
+	sc           data.Connector
+}
+
+func makeRollbackDistro(sc data.Connector) gimlet.RouteHandler {
+	return &distroRollbackHandler{
+		sc: sc,
+	}
+}
+
+func (h *distroRollbackHandler) Factory() gimlet.RouteHandler {
+	return &distroRollbackHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *distroRollbackHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	h.userID = MustHaveUser(ctx).Username()
+	h.distroID = gimlet.GetVars(r)["distro_id"]
+	h.decommission, h.dryRun = parseDistroChangeOptions(r)
+	h.revision, err = parseDistroRevision(gimlet.GetVars(r)["revision"])
+	return errors.WithStack(err)
+}
+
+// Run restores the distro as it was at the revision, which recreates the
+// distro if it has since been deleted.
+func (h *distroRollbackHandler) Run(ctx context.Context) gimlet.Responder {
+	revisions, err := h.sc.FindDistroRevisions(h.distroID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	revision, err := findDistroRevision(h.distroID, revisions, h.revision)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	if revision.Number == 0 {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "cannot roll back to before the distro was created",
+		})
+	}
+
+	d := revision.Distro
+	return changeDistro(ctx, h.sc, &d, h.userID, h.decommission, h.dryRun)
+}
+
+// changeDistro creates the distro if it does not exist and updates it
+// otherwise, or, for a dry run, only validates it. The response has the
+// fields that change and the hosts that are, or would be, decommissioned.
+func changeDistro(ctx context.Context, sc data.Connector, d *distro.Distro, userID string, decommission, dryRun bool) gimlet.Responder {
+	isNew := false
+	oldDistro, err := sc.FindDistroById(d.Id)
+	if err != nil {
+		if errResp, ok := errors.Cause(err).(gimlet.ErrorResponse); !ok || errResp.StatusCode != http.StatusNotFound {
+			return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+		}
+		isNew = true
+		oldDistro = &distro.Distro{}
+	}
+
+	result := &model.APIDistroChange{
+		Distro:              &model.APIDistro{},
+		HostsToDecommission: []string{},
+		DryRun:              dryRun,
+	}
+	if result.Changes, err = distro.Diff(*oldDistro, *d); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem comparing distros"))
+	}
+	if decommission && !isNew {
+		hosts, err := sc.FindDistroHosts(d.Id)
+		if err != nil {
+			return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+		}
+		for _, h := range hosts {
+			result.HostsToDecommission = append(result.HostsToDecommission, h.Id)
+		}
+	}
+
+	switch {
+	case dryRun:
+		err = sc.ValidateDistro(ctx, d, isNew)
+	case isNew:
+		err = sc.CreateDistro(ctx, d, userID)
+	default:
+		err = sc.UpdateDistro(ctx, d, userID, decommission)
+	}
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem changing distro '%s'", d.Id))
+	}
+
+	if err = result.Distro.BuildFromService(*d); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem converting distro"))
+	}
+	resp := gimlet.NewJSONResponse(result)
+	if isNew && !dryRun {
+		if err = resp.SetStatus(http.StatusCreated); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem setting response status"))
+		}
+	}
+
+	return resp
+}
+
+func parseDistroChangeOptions(r *http.Request) (decommission, dryRun bool) {
+	vals := r.URL.Query()
+	return vals.Get("decommission") == "true", vals.Get("dry_run") == "true"
+}
+
+func parseDistroRevision(revision string) (int, error) {
+	n, err := strconv.Atoi(revision)
+	if err != nil || n < 0 {
+		return 0, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("invalid revision '%s'", revision),
+		}
+	}
+	return n, nil
+}
+
+// findDistroRevision returns the numbered revision. Revision 0 is an empty
+// distro, from before the distro was created.
+func findDistroRevision(distroID string, revisions []distro.Revision, n int) (distro.Revision, error) {
+	if n == 0 {
+		return distro.Revision{}, nil
+	}
+	for _, revision := range revisions {
+		if revision.Number == n {
+			return revision, nil
+		}
+	}
+	return distro.Revision{}, gimlet.ErrorResponse{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("revision %d of distro '%s' not found", n, distroID),
+	}
+}
@@ -0,0 +1,207 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/suite"
+)
+
+type DistroSuite struct {
+	sc  *data.MockConnector
+	ctx context.Context
+
+	suite.Suite
+}
+
+func TestDistroSuite(t *testing.T) {
+	suite.Run(t, new(DistroSuite))
+}
+
+func (s *DistroSuite) SetupTest() {
+	s.sc = &data.MockConnector{
+		MockDistroConnector: data.MockDistroConnector{
+			CachedDistros: []distro.Distro{
+				{
+					Id:               "d1",
+					Arch:             "linux_amd64",
+					Provider:         evergreen.ProviderNameStatic,
+					ProviderSettings: &map[string]interface{}{"hosts": []interface{}{}},
+					User:             "admin",
+				},
+			},
+			CachedDistroHosts: []host.Host{
+				{Id: "h1", Distro: distro.Distro{Id: "d1"}},
+				{Id: "h2", Distro: distro.Distro{Id: "d2"}},
+			},
+		},
+	}
+	s.ctx = gimlet.AttachUser(context.Background(), &user.DBUser{Id: "root"})
+}
+
+func (s *DistroSuite) TestGet() {
+	h := &distroIDGetHandler{sc: s.sc, distroID: "d1"}
+	resp := h.Run(s.ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	d := resp.Data().(*model.APIDistro)
+	s.Equal("linux_amd64", model.FromAPIString(d.Arch))
+	s.Equal("admin", model.FromAPIString(d.User))
+
+	h.distroID = "nonexistent"
+	resp = h.Run(s.ctx)
+	s.Equal(http.StatusNotFound, resp.Status())
+}
+
+func (s *DistroSuite) TestPutCreatesThenReplaces() {
+	h := &distroIDPutHandler{
+		sc:       s.sc,
+		distroID: "d2",
+		userID:   "root",
+		distro: model.APIDistro{
+			Name:     model.ToAPIString("d2"),
+			Arch:     model.ToAPIString("linux_amd64"),
+			Provider: model.ToAPIString(evergreen.ProviderNameStatic),
+		},
+	}
+	resp := h.Run(s.ctx)
+	s.Require().Equal(http.StatusCreated, resp.Status())
+	d, err := s.sc.FindDistroById("d2")
+	s.Require().NoError(err)
+	s.Equal("linux_amd64", d.Arch)
+
+	h.distro.Arch = model.ToAPIString("windows_amd64")
+	resp = h.Run(s.ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	change := resp.Data().(*model.APIDistroChange)
+	s.Equal([]distro.FieldChange{{Field: "arch", Old: "linux_amd64", New: "windows_amd64"}}, change.Changes)
+	d, err = s.sc.FindDistroById("d2")
+	s.Require().NoError(err)
+	s.Equal("windows_amd64", d.Arch)
+}
+
+func (s *DistroSuite) TestDryRunDoesNotChangeDistroOrHosts() {
+	h := &distroIDPutHandler{
+		sc:           s.sc,
+		distroID:     "d1",
+		userID:       "root",
+		decommission: true,
+		dryRun:       true,
+		distro: model.APIDistro{
+			Name:     model.ToAPIString("d1"),
+			Arch:     model.ToAPIString("windows_amd64"),
+			Provider: model.ToAPIString(evergreen.ProviderNameStatic),
+		},
+	}
+	resp := h.Run(s.ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	change := resp.Data().(*model.APIDistroChange)
+	s.True(change.DryRun)
+	s.Equal([]string{"h1"}, change.HostsToDecommission)
+
+	d, err := s.sc.FindDistroById("d1")
+	s.Require().NoError(err)
+	s.Equal("linux_amd64", d.Arch)
+	s.Len(s.sc.CachedDistroHosts, 2)
+
+	h.dryRun = false
+	resp = h.Run(s.ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	s.Len(s.sc.CachedDistroHosts, 1)
+}
+
+func (s *DistroSuite) TestPatchLeavesMissingFieldsUnchanged() {
+	h := &distroIDPatchHandler{
+		sc:       s.sc,
+		distroID: "d1",
+		userID:   "root",
+		body:     []byte(`{"pool_size": 5}`),
+	}
+	resp := h.Run(s.ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	d, err := s.sc.FindDistroById("d1")
+	s.Require().NoError(err)
+	s.Equal(5, d.PoolSize)
+	s.Equal("linux_amd64", d.Arch)
+	s.Equal("admin", d.User)
+
+	h.distroID = "nonexistent"
+	resp = h.Run(s.ctx)
+	s.Equal(http.StatusNotFound, resp.Status())
+}
+
+func (s *DistroSuite) TestCopyAndDelete() {
+	copyHandler := &distroCopyHandler{sc: s.sc, oldDistroID: "d1", newDistroID: "d1-copy", userID: "root"}
+	resp := copyHandler.Run(s.ctx)
+	s.Require().Equal(http.StatusCreated, resp.Status())
+	d, err := s.sc.FindDistroById("d1-copy")
+	s.Require().NoError(err)
+	s.Equal("linux_amd64", d.Arch)
+
+	resp = copyHandler.Run(s.ctx)
+	s.Equal(http.StatusBadRequest, resp.Status())
+
+	deleteHandler := &distroIDDeleteHandler{sc: s.sc, distroID: "d1-copy", userID: "root"}
+	resp = deleteHandler.Run(s.ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	_, err = s.sc.FindDistroById("d1-copy")
+	s.Error(err)
+
+	resp = deleteHandler.Run(s.ctx)
+	s.Equal(http.StatusNotFound, resp.Status())
+}
+
+func (s *DistroSuite) TestRevisionsDiffAndRollback() {
+	put := &distroIDPutHandler{
+		sc:       s.sc,
+		distroID: "d2",
+		userID:   "root",
+		distro: model.APIDistro{
+			Name:     model.ToAPIString("d2"),
+			Arch:     model.ToAPIString("linux_amd64"),
+			Provider: model.ToAPIString(evergreen.ProviderNameStatic),
+			PoolSize: 1,
+		},
+	}
+	s.Require().Equal(http.StatusCreated, put.Run(s.ctx).Status())
+	put.distro.PoolSize = 2
+	s.Require().Equal(http.StatusOK, put.Run(s.ctx).Status())
+
+	revisionsHandler := &distroRevisionsGetHandler{sc: s.sc, distroID: "d2"}
+	resp := revisionsHandler.Run(s.ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	revisions := resp.Data().([]interface{})
+	s.Require().Len(revisions, 2)
+	s.Equal(2, revisions[1].(*model.APIDistroRevision).Revision)
+	s.Equal("root", model.FromAPIString(revisions[1].(*model.APIDistroRevision).User))
+
+	diffHandler := &distroRevisionDiffHandler{sc: s.sc, distroID: "d2", revision: 2, against: 1}
+	resp = diffHandler.Run(s.ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	s.Equal([]distro.FieldChange{{Field: "pool_size", Old: float64(1), New: float64(2)}}, resp.Data())
+
+	diffHandler.revision = 3
+	resp = diffHandler.Run(s.ctx)
+	s.Equal(http.StatusNotFound, resp.Status())
+
+	rollbackHandler := &distroRollbackHandler{sc: s.sc, distroID: "d2", revision: 1, userID: "root"}
+	resp = rollbackHandler.Run(s.ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	d, err := s.sc.FindDistroById("d2")
+	s.Require().NoError(err)
+	s.Equal(1, d.PoolSize)
+
+	rollbackHandler.revision = 0
+	resp = rollbackHandler.Run(s.ctx)
+	s.Equal(http.StatusBadRequest, resp.Status())
+
+	resp = (&distroRevisionsGetHandler{sc: s.sc, distroID: "nonexistent"}).Run(s.ctx)
+	s.Equal(http.StatusNotFound, resp.Status())
+}
@@ -0,0 +1,120 @@
+package route
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/flaky_tests
+
+type flakyTestsGetHandler struct {
+	projectId string
+	sc        data.Connector
+}
+
+func makeFetchFlakyTests(sc data.Connector) gimlet.RouteHandler {
+	return &flakyTestsGetHandler{
+		sc: sc,
+	}
+}
+
+func (h *flakyTestsGetHandler) Factory() gimlet.RouteHandler {
+	return &flakyTestsGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *flakyTestsGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	projCtx := MustHaveProjectContext(ctx)
+	if projCtx.ProjectRef == nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "Project not found",
+		}
+	}
+	h.projectId = projCtx.ProjectRef.Identifier
+
+	return nil
+}
+
+func (h *flakyTestsGetHandler) Run(ctx context.Context) gimlet.Responder {
+	tests, err := h.sc.FindFlakyTests(h.projectId)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	resp := gimlet.NewResponseBuilder()
+	for _, t := range tests {
+		testModel := &model.APIFlakyTest{}
+		if err = testModel.BuildFromService(t); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+		if err = resp.AddData(testModel); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+	}
+
+	return resp
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/projects/{project_id}/flaky_tests/quarantine
+
+type flakyTestQuarantineHandler struct {
+	projectId string
+	request   model.APIQuarantineRequest
+	sc        data.Connector
+}
+
+func makeQuarantineFlakyTest(sc data.Connector) gimlet.RouteHandler {
+	return &flakyTestQuarantineHandler{
+		sc: sc,
+	}
+}
+
+func (h *flakyTestQuarantineHandler) Factory() gimlet.RouteHandler {
+	return &flakyTestQuarantineHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *flakyTestQuarantineHandler) Parse(ctx context.Context, r *http.Request) error {
+	projCtx := MustHaveProjectContext(ctx)
+	if projCtx.ProjectRef == nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    "Project not found",
+		}
+	}
+	h.projectId = projCtx.ProjectRef.Identifier
+
+	if err := gimlet.GetJSON(r.Body, &h.request); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+	if h.request.TaskName == "" || h.request.TestFile == "" {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "task_name and test_file must be specified",
+		}
+	}
+
+	return nil
+}
+
+func (h *flakyTestQuarantineHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+
+	if err := h.sc.SetTestQuarantined(h.projectId, h.request.TaskName, h.request.TestFile, h.request.Quarantined, u.Username()); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem updating quarantine state"))
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
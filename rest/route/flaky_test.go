@@ -0,0 +1,89 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/flaky"
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/suite"
+)
+
+type FlakyTestsRouteSuite struct {
+	sc  *data.MockConnector
+	ctx context.Context
+	suite.Suite
+}
+
+func TestFlakyTestsRouteSuite(t *testing.T) {
+	suite.Run(t, new(FlakyTestsRouteSuite))
+}
+
+func (s *FlakyTestsRouteSuite) SetupTest() {
+	s.sc = &data.MockConnector{MockFlakyTestConnector: data.MockFlakyTestConnector{
+		CachedFlakyTests: []flaky.FlakyTest{
+			{Id: flaky.MakeId("proj", "unit", "a"), Project: "proj", TaskName: "unit", TestFile: "a", Score: 0.5},
+			{Id: flaky.MakeId("other", "unit", "a"), Project: "other", TaskName: "unit", TestFile: "a", Score: 1},
+		},
+	}}
+	s.sc.SetSuperUsers([]string{"root"})
+
+	s.ctx = gimlet.AttachUser(context.Background(), &user.DBUser{Id: "admin"})
+	s.ctx = context.WithValue(s.ctx, RequestContext, &model.Context{
+		ProjectRef: &model.ProjectRef{Identifier: "proj", Admins: []string{"admin"}},
+	})
+}
+
+func (s *FlakyTestsRouteSuite) TestGetFlakyTests() {
+	h := makeFetchFlakyTests(s.sc).(*flakyTestsGetHandler)
+	s.NoError(h.Parse(s.ctx, nil))
+	s.Equal("proj", h.projectId)
+
+	resp := h.Run(s.ctx)
+	s.Equal(http.StatusOK, resp.Status())
+	payload := resp.Data().([]interface{})
+	s.Require().Len(payload, 1)
+	test, ok := payload[0].(*restModel.APIFlakyTest)
+	s.Require().True(ok)
+	s.Equal("a", restModel.FromAPIString(test.TestFile))
+	s.Equal(0.5, test.Score)
+}
+
+func (s *FlakyTestsRouteSuite) TestQuarantine() {
+	h := makeQuarantineFlakyTest(s.sc).(*flakyTestQuarantineHandler)
+	h.projectId = "proj"
+	h.request = restModel.APIQuarantineRequest{TaskName: "unit", TestFile: "b", Quarantined: true}
+
+	resp := h.Run(s.ctx)
+	s.Equal(http.StatusOK, resp.Status())
+
+	tests, err := s.sc.FindFlakyTests("proj")
+	s.NoError(err)
+	s.Require().Len(tests, 2)
+	s.True(tests[1].Quarantined)
+	s.Equal("admin", tests[1].QuarantinedBy)
+}
+
+func (s *FlakyTestsRouteSuite) TestQuarantineRequiresProjectEditPermission() {
+	canEditProject := NewRequirePermissionMiddleware(s.sc, role.PermissionEditProjectSettings)
+	check := func(u gimlet.User) int {
+		r, err := http.NewRequest(http.MethodPost, "/", nil)
+		s.Require().NoError(err)
+		rw := httptest.NewRecorder()
+		canEditProject.ServeHTTP(rw, r.WithContext(gimlet.AttachUser(s.ctx, u)), func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusOK)
+		})
+		return rw.Code
+	}
+
+	s.Equal(http.StatusOK, check(&user.DBUser{Id: "admin"}))
+	s.Equal(http.StatusOK, check(&user.DBUser{Id: "root"}))
+	s.Equal(http.StatusUnauthorized, check(&user.DBUser{Id: "someone"}))
+}
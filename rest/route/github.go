@@ -2,15 +2,22 @@ package route
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/evergreen-ci/evergreen/model/commitqueue"
 	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/google/go-github/github"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
 )
 
 const (
@@ -18,6 +25,11 @@ const (
 	githubActionOpened      = "opened"
 	githubActionSynchronize = "synchronize"
 	githubActionReopened    = "reopened"
+	githubActionCreated     = "created"
+
+	// commitQueueCommentTrigger is the pull request comment that adds the
+	// pull request to its project's commit queue.
+	commitQueueCommentTrigger = "evergreen merge"
 )
 
 type githubHookApi struct {
@@ -180,6 +192,35 @@ func (gh *githubHookApi) Run(ctx context.Context) gimlet.Responder {
 			return gimlet.NewJSONResponse(struct{}{})
 		}
 
+	case *github.IssueCommentEvent:
+		if !isCommitQueueTrigger(event) {
+			return gimlet.NewJSONResponse(struct{}{})
+		}
+
+		grip.Info(message.Fields{
+			"source":    "github hook",
+			"msg_id":    gh.msgID,
+			"event":     gh.eventType,
+			"message":   "commit queue triggered",
+			"repo":      event.Repo.GetFullName(),
+			"pr_number": event.Issue.GetNumber(),
+			"user":      event.Comment.User.GetLogin(),
+		})
+
+		if err := gh.enqueuePullRequest(ctx, event); err != nil {
+			grip.Error(message.WrapError(err, message.Fields{
+				"source":    "github hook",
+				"msg_id":    gh.msgID,
+				"event":     gh.eventType,
+				"repo":      event.Repo.GetFullName(),
+				"pr_number": event.Issue.GetNumber(),
+				"message":   "can't enqueue pull request",
+			}))
+			return gimlet.MakeJSONErrorResponder(err)
+		}
+
+		return gimlet.NewJSONResponse(struct{}{})
+
 	case *github.PushEvent:
 		if err := gh.sc.TriggerRepotracker(gh.queue, gh.msgID, event); err != nil {
 			return gimlet.MakeJSONErrorResponder(err)
@@ -189,3 +230,85 @@ func (gh *githubHookApi) Run(ctx context.Context) gimlet.Responder {
 
 	return gimlet.NewJSONResponse(struct{}{})
 }
+
+func isCommitQueueTrigger(event *github.IssueCommentEvent) bool {
+	if event.GetAction() != githubActionCreated {
+		return false
+	}
+	if event.Issue == nil || !event.Issue.IsPullRequest() || event.Comment == nil {
+		return false
+	}
+
+	return strings.TrimSpace(event.Comment.GetBody()) == commitQueueCommentTrigger
+}
+
+// enqueuePullRequest adds the pull request commented on to the commit queue
+// of the project that tracks the pull request's base branch. The commenter
+// must have linked their GitHub account to an Evergreen user who can edit
+// the project, and the pull request must be theirs or a committer's.
+func (gh *githubHookApi) enqueuePullRequest(ctx context.Context, event *github.IssueCommentEvent) error {
+	owner := event.Repo.Owner.GetLogin()
+	repo := event.Repo.GetName()
+	prNumber := event.Issue.GetNumber()
+	commenter := event.Comment.User.GetLogin()
+
+	var u *user.DBUser
+	var err error
+	if uid := event.Comment.User.GetID(); uid != 0 {
+		if u, err = gh.sc.FindUserByGithubUID(uid); err != nil {
+			return errors.Wrapf(err, "can't find user for GitHub user '%s'", commenter)
+		}
+	}
+	if u == nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    fmt.Sprintf("GitHub user '%s' is not linked to an Evergreen user", commenter),
+		}
+	}
+
+	pr, err := gh.sc.GetGitHubPR(ctx, owner, repo, prNumber)
+	if err != nil {
+		return errors.Wrapf(err, "can't get pull request %d", prNumber)
+	}
+	if pr.Base == nil || pr.Base.Ref == nil {
+		return errors.Errorf("pull request %d has no base branch", prNumber)
+	}
+
+	projectRef, err := gh.sc.GetProjectWithCommitQueueByOwnerRepoAndBranch(owner, repo, *pr.Base.Ref)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if projectRef == nil {
+		return errors.Errorf("no project with a commit queue tracks %s/%s:%s", owner, repo, *pr.Base.Ref)
+	}
+
+	allowed, err := hasPermission(gh.sc, u, role.PermissionEditProjectSettings, projectRef)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if !allowed {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    fmt.Sprintf("user '%s' cannot add to the commit queue of project '%s'", u.Id, projectRef.Identifier),
+		}
+	}
+
+	if author := pr.GetUser().GetLogin(); author != commenter {
+		isCommitter, err := gh.sc.IsGitHubCommitter(ctx, owner, repo, author)
+		if err != nil {
+			return errors.Wrapf(err, "can't check whether '%s' is a committer", author)
+		}
+		if !isCommitter {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusUnauthorized,
+				Message:    fmt.Sprintf("pull request %d is by '%s', who is not a committer to %s/%s", prNumber, author, owner, repo),
+			}
+		}
+	}
+
+	_, err = gh.sc.EnqueueItem(projectRef.Identifier, commitqueue.CommitQueueItem{
+		Issue:    strconv.Itoa(prNumber),
+		Enqueuer: event.Comment.User.GetLogin(),
+	})
+	return errors.WithStack(err)
+}
@@ -2,14 +2,18 @@ package route
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/auth"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/role"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
 )
 
 type (
@@ -104,6 +108,79 @@ func MustHaveUser(ctx context.Context) *user.DBUser {
 	return usr
 }
 
+type permissionMiddleware struct {
+	sc         data.Connector
+	permission string
+}
+
+// NewRequirePermissionMiddleware returns a middleware that rejects requests
+// from users who do not hold the permission. Project permissions are checked
+// against the project in the request's project context, so the project
+// context middleware must run first; without a project they must be held
+// globally. Super users hold every permission and project admins hold every
+// project permission on their project. Private project logs are only
+// restricted if the project is private.
+func NewRequirePermissionMiddleware(sc data.Connector, permission string) gimlet.Middleware {
+	return &permissionMiddleware{
+		sc:         sc,
+		permission: permission,
+	}
+}
+
+func (m *permissionMiddleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	ctx := r.Context()
+	u := gimlet.GetUser(ctx)
+	if u == nil {
+		gimlet.WriteResponse(rw, gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "not authorized",
+		}))
+		return
+	}
+
+	var projectRef *model.ProjectRef
+	if opCtx := GetProjectContext(ctx); opCtx != nil && role.IsProjectPermission(m.permission) {
+		projectRef = opCtx.ProjectRef
+	}
+
+	allowed, err := hasPermission(m.sc, u, m.permission, projectRef)
+	if err != nil {
+		gimlet.WriteResponse(rw, gimlet.MakeJSONInternalErrorResponder(err))
+		return
+	}
+	if !allowed {
+		gimlet.WriteResponse(rw, gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    fmt.Sprintf("user '%s' does not have permission '%s'", u.Username(), m.permission),
+		}))
+		return
+	}
+
+	next(rw, r)
+}
+
+// hasPermission returns true if the user holds the permission on the
+// project, or globally if the project is nil.
+func hasPermission(sc data.Connector, u gimlet.User, permission string, projectRef *model.ProjectRef) (bool, error) {
+	if auth.IsSuperUser(sc.GetSuperUsers(), u) {
+		return true, nil
+	}
+
+	project := role.GlobalScope
+	if projectRef != nil {
+		if permission == role.PermissionViewPrivateLogs && !projectRef.Private {
+			return true, nil
+		}
+		if util.StringSliceContains(projectRef.Admins, u.Username()) {
+			return true, nil
+		}
+		project = projectRef.Identifier
+	}
+
+	allowed, err := sc.UserHasPermission(u.Username(), permission, project)
+	return allowed, errors.Wrap(err, "problem checking permissions")
+}
+
 func validPriority(priority int64, user gimlet.User, sc data.Connector) bool {
 	if priority > evergreen.MaxTaskPriority {
 		return auth.IsSuperUser(sc.GetSuperUsers(), user)
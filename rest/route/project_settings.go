@@ -0,0 +1,441 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}
+
+type projectIDGetHandler struct {
+	projectID string
+	sc        data.Connector
+}
+
+func makeGetProjectByID(sc data.Connector) gimlet.RouteHandler {
+	return &projectIDGetHandler{
+		sc: sc,
+	}
+}
+
+func (h *projectIDGetHandler) Factory() gimlet.RouteHandler {
+	return &projectIDGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *projectIDGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectID = gimlet.GetVars(r)["project_id"]
+	return nil
+}
+
+func (h *projectIDGetHandler) Run(ctx context.Context) gimlet.Responder {
+	projectRef, err := h.sc.FindProjectById(h.projectID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	projectModel := &model.APIProject{}
+	if err = projectModel.BuildFromService(*projectRef); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem converting project document"))
+	}
+
+	return gimlet.NewJSONResponse(projectModel)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PUT /rest/v2/projects/{project_id}
+
+type projectIDPutHandler struct {
+	projectID string
+	project   model.APIProject
+	sc        data.Connector
+}
+
+func makePutProjectByID(sc data.Connector) gimlet.RouteHandler {
+	return &projectIDPutHandler{
+		sc: sc,
+	}
+}
+
+func (h *projectIDPutHandler) Factory() gimlet.RouteHandler {
+	return &projectIDPutHandler{
+		sc: h.sc,
+	}
+}
+
+// Parse reads the settings of a new project. Projects are tracked and use
+// GitHub unless the request says otherwise.
+func (h *projectIDPutHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectID = gimlet.GetVars(r)["project_id"]
+	h.project = model.APIProject{Tracked: true}
+	if err := gimlet.GetJSON(r.Body, &h.project); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+	h.project.Identifier = model.ToAPIString(h.projectID)
+	if model.FromAPIString(h.project.RepoKind) == "" {
+		h.project.RepoKind = model.ToAPIString(dbModel.GithubRepoType)
+	}
+
+	return nil
+}
+
+func (h *projectIDPutHandler) Run(ctx context.Context) gimlet.Responder {
+	i, err := h.project.ToService()
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem converting project"))
+	}
+	projectRef := i.(dbModel.ProjectRef)
+
+	if err = h.sc.CreateProject(&projectRef); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem creating project '%s'", h.projectID))
+	}
+
+	return projectResponse(projectRef, http.StatusCreated)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PATCH /rest/v2/projects/{project_id}
+
+type projectIDPatchHandler struct {
+	projectID string
+	body      []byte
+	sc        data.Connector
+}
+
+func makePatchProjectByID(sc data.Connector) gimlet.RouteHandler {
+	return &projectIDPatchHandler{
+		sc: sc,
+	}
+}
+
+func (h *projectIDPatchHandler) Factory() gimlet.RouteHandler {
+	return &projectIDPatchHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *projectIDPatchHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectID = gimlet.GetVars(r)["project_id"]
+	body := util.NewRequestReader(r)
+	defer body.Close()
+
+	var err error
+	h.body, err = ioutil.ReadAll(body)
+	return errors.Wrap(err, "problem reading request body")
+}
+
+// Run applies the request to the project's current settings, so settings
+// missing from the request are unchanged.
+func (h *projectIDPatchHandler) Run(ctx context.Context) gimlet.Responder {
+	oldProject, err := h.sc.FindProjectById(h.projectID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	projectModel := &model.APIProject{}
+	if err = projectModel.BuildFromService(*oldProject); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem converting project document"))
+	}
+	if err = json.Unmarshal(h.body, projectModel); err != nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("problem parsing request body: %s", err.Error()),
+		})
+	}
+	projectModel.Identifier = model.ToAPIString(h.projectID)
+
+	i, err := projectModel.ToService()
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem converting project"))
+	}
+	projectRef := i.(dbModel.ProjectRef)
+
+	if err = h.sc.UpdateProject(&projectRef); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem updating project '%s'", h.projectID))
+	}
+
+	return projectResponse(projectRef, http.StatusOK)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// DELETE /rest/v2/projects/{project_id}
+
+type projectDeleteHandler struct {
+	projectID string
+	user      *user.DBUser
+	sc        data.Connector
+}
+
+func makeDeleteProject(sc data.Connector) gimlet.RouteHandler {
+	return &projectDeleteHandler{
+		sc: sc,
+	}
+}
+
+func (h *projectDeleteHandler) Factory() gimlet.RouteHandler {
+	return &projectDeleteHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *projectDeleteHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.user = MustHaveUser(ctx)
+	h.projectID = gimlet.GetVars(r)["project_id"]
+	return nil
+}
+
+// Run deletes the project. Project admins can edit a project but not delete
+// it, so the user must be able to edit the settings of every project.
+func (h *projectDeleteHandler) Run(ctx context.Context) gimlet.Responder {
+	allowed, err := hasPermission(h.sc, h.user, role.PermissionEditProjectSettings, nil)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+	if !allowed {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "not authorized to delete projects",
+		})
+	}
+
+	if err := h.sc.DeleteProject(h.projectID); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem deleting project '%s'", h.projectID))
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/projects/{project_id}/copy?new_project={new_project_id}
+
+type projectCopyHandler struct {
+	oldProjectID string
+	newProjectID string
+	user         *user.DBUser
+	sc           data.Connector
+}
+
+func makeCopyProject(sc data.Connector) gimlet.RouteHandler {
+	return &projectCopyHandler{
+		sc: sc,
+	}
+}
+
+func (h *projectCopyHandler) Factory() gimlet.RouteHandler {
+	return &projectCopyHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *projectCopyHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.user = MustHaveUser(ctx)
+	h.oldProjectID = gimlet.GetVars(r)["project_id"]
+	h.newProjectID = r.URL.Query().Get("new_project")
+	if h.newProjectID == "" {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "must provide new project ID",
+		}
+	}
+
+	return nil
+}
+
+// Run copies the project. The copy is a new project, so the user must also be
+// able to create projects.
+func (h *projectCopyHandler) Run(ctx context.Context) gimlet.Responder {
+	allowed, err := hasPermission(h.sc, h.user, role.PermissionEditProjectSettings, nil)
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+	if !allowed {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "not authorized to create projects",
+		})
+	}
+
+	projectRef, err := h.sc.CopyProject(h.oldProjectID, h.newProjectID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem copying project '%s'", h.oldProjectID))
+	}
+
+	return projectResponse(*projectRef, http.StatusCreated)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/variables
+
+type projectVarsGetHandler struct {
+	projectID string
+	sc        data.Connector
+}
+
+func makeGetProjectVars(sc data.Connector) gimlet.RouteHandler {
+	return &projectVarsGetHandler{
+		sc: sc,
+	}
+}
+
+func (h *projectVarsGetHandler) Factory() gimlet.RouteHandler {
+	return &projectVarsGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *projectVarsGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectID = gimlet.GetVars(r)["project_id"]
+	return nil
+}
+
+func (h *projectVarsGetHandler) Run(ctx context.Context) gimlet.Responder {
+	if _, err := h.sc.FindProjectById(h.projectID); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	vars, err := h.sc.FindProjectVars(h.projectID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	varsModel := &model.APIProjectVars{}
+	if err = varsModel.BuildFromService(vars); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem converting project variables"))
+	}
+
+	return gimlet.NewJSONResponse(varsModel)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PATCH /rest/v2/projects/{project_id}/variables
+
+type projectVarsPatchHandler struct {
+	projectID string
+	vars      model.APIProjectVars
+	sc        data.Connector
+}
+
+func makePatchProjectVars(sc data.Connector) gimlet.RouteHandler {
+	return &projectVarsPatchHandler{
+		sc: sc,
+	}
+}
+
+func (h *projectVarsPatchHandler) Factory() gimlet.RouteHandler {
+	return &projectVarsPatchHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *projectVarsPatchHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectID = gimlet.GetVars(r)["project_id"]
+	return errors.Wrap(gimlet.GetJSON(r.Body, &h.vars), "problem parsing request body")
+}
+
+func (h *projectVarsPatchHandler) Run(ctx context.Context) gimlet.Responder {
+	if _, err := h.sc.FindProjectById(h.projectID); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	i, err := h.vars.ToService()
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem converting project variables"))
+	}
+	vars := i.(dbModel.ProjectVars)
+	if err = h.sc.UpdateProjectVars(h.projectID, &vars, h.vars.VarsToDelete); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem updating variables of project '%s'", h.projectID))
+	}
+
+	updated, err := h.sc.FindProjectVars(h.projectID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	varsModel := &model.APIProjectVars{}
+	if err = varsModel.BuildFromService(updated); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem converting project variables"))
+	}
+
+	return gimlet.NewJSONResponse(varsModel)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PUT /rest/v2/projects/{project_id}/aliases
+
+type projectAliasesPutHandler struct {
+	projectID string
+	aliases   []model.APIAlias
+	sc        data.Connector
+}
+
+func makePutProjectAliases(sc data.Connector) gimlet.RouteHandler {
+	return &projectAliasesPutHandler{
+		sc: sc,
+	}
+}
+
+func (h *projectAliasesPutHandler) Factory() gimlet.RouteHandler {
+	return &projectAliasesPutHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *projectAliasesPutHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectID = gimlet.GetVars(r)["project_id"]
+	return errors.Wrap(gimlet.GetJSON(r.Body, &h.aliases), "problem parsing request body")
+}
+
+// Run replaces all of the project's aliases with the aliases in the request.
+func (h *projectAliasesPutHandler) Run(ctx context.Context) gimlet.Responder {
+	if _, err := h.sc.FindProjectById(h.projectID); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	aliases := []dbModel.ProjectAlias{}
+	for _, apiAlias := range h.aliases {
+		i, err := apiAlias.ToService()
+		if err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem converting alias"))
+		}
+		aliases = append(aliases, i.(dbModel.ProjectAlias))
+	}
+
+	if err := h.sc.UpdateProjectAliases(h.projectID, aliases); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem updating aliases of project '%s'", h.projectID))
+	}
+
+	return gimlet.NewJSONResponse(h.aliases)
+}
+
+func projectResponse(projectRef dbModel.ProjectRef, status int) gimlet.Responder {
+	projectModel := &model.APIProject{}
+	if err := projectModel.BuildFromService(projectRef); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem converting project document"))
+	}
+
+	resp := gimlet.NewJSONResponse(projectModel)
+	if err := resp.SetStatus(status); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "problem setting response status %d", status))
+	}
+	return resp
+}
@@ -0,0 +1,241 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProjectSettingsSuite struct {
+	sc  *data.MockConnector
+	ctx context.Context
+
+	suite.Suite
+}
+
+func TestProjectSettingsSuite(t *testing.T) {
+	suite.Run(t, new(ProjectSettingsSuite))
+}
+
+func (s *ProjectSettingsSuite) SetupTest() {
+	s.sc = &data.MockConnector{
+		MockProjectConnector: data.MockProjectConnector{
+			CachedProjects: []serviceModel.ProjectRef{
+				{
+					Identifier:  "mci",
+					DisplayName: "Evergreen",
+					Owner:       "evergreen-ci",
+					Repo:        "evergreen",
+					Branch:      "master",
+					RepoKind:    serviceModel.GithubRepoType,
+					Enabled:     true,
+					Admins:      []string{"admin"},
+				},
+			},
+			CachedVars: []*serviceModel.ProjectVars{
+				{
+					Id:          "mci",
+					Vars:        map[string]string{"a": "1", "secret": "hunter2"},
+					PrivateVars: map[string]bool{"secret": true},
+					SecretVars:  map[string]string{"token": "ci/token"},
+				},
+			},
+		},
+	}
+	s.sc.SetSuperUsers([]string{"root"})
+	s.ctx = gimlet.AttachUser(context.Background(), &user.DBUser{Id: "root"})
+}
+
+func (s *ProjectSettingsSuite) request(method, url string, body interface{}) *http.Request {
+	out, err := json.Marshal(body)
+	s.Require().NoError(err)
+	req, err := http.NewRequest(method, url, bytes.NewBuffer(out))
+	s.Require().NoError(err)
+	return req
+}
+
+func (s *ProjectSettingsSuite) TestGet() {
+	h := &projectIDGetHandler{sc: s.sc, projectID: "mci"}
+	resp := h.Run(s.ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	project := resp.Data().(*model.APIProject)
+	s.Equal("Evergreen", model.FromAPIString(project.DisplayName))
+
+	h.projectID = "nonexistent"
+	resp = h.Run(s.ctx)
+	s.Equal(http.StatusNotFound, resp.Status())
+}
+
+func (s *ProjectSettingsSuite) TestCreate() {
+	h := makePutProjectByID(s.sc).(*projectIDPutHandler)
+	req := s.request(http.MethodPut, "/projects/new", map[string]interface{}{
+		"display_name": "New",
+		"owner_name":   "evergreen-ci",
+		"repo_name":    "new",
+		"branch_name":  "master",
+	})
+	s.Require().NoError(h.Parse(s.ctx, req))
+	h.projectID = "new"
+	h.project.Identifier = model.ToAPIString("new")
+	s.Equal(serviceModel.GithubRepoType, model.FromAPIString(h.project.RepoKind))
+	s.True(h.project.Tracked)
+
+	resp := h.Run(s.ctx)
+	s.Require().Equal(http.StatusCreated, resp.Status())
+	projectRef, err := s.sc.FindProjectById("new")
+	s.Require().NoError(err)
+	s.Equal("New", projectRef.DisplayName)
+	s.Equal("new", projectRef.Identifier)
+
+	resp = h.Run(s.ctx)
+	s.Equal(http.StatusBadRequest, resp.Status())
+}
+
+func (s *ProjectSettingsSuite) TestUpdateLeavesMissingSettingsUnchanged() {
+	h := makePatchProjectByID(s.sc).(*projectIDPatchHandler)
+	req := s.request(http.MethodPatch, "/projects/mci", map[string]interface{}{
+		"display_name": "Renamed",
+		"admins":       []string{"admin", "other"},
+	})
+	s.Require().NoError(h.Parse(s.ctx, req))
+	h.projectID = "mci"
+
+	resp := h.Run(s.ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	projectRef, err := s.sc.FindProjectById("mci")
+	s.Require().NoError(err)
+	s.Equal("Renamed", projectRef.DisplayName)
+	s.Equal("master", projectRef.Branch)
+	s.True(projectRef.Enabled)
+	s.Equal([]string{"admin", "other"}, projectRef.Admins)
+
+	h.projectID = "nonexistent"
+	resp = h.Run(s.ctx)
+	s.Equal(http.StatusNotFound, resp.Status())
+}
+
+func (s *ProjectSettingsSuite) TestCopy() {
+	h := &projectCopyHandler{sc: s.sc, user: &user.DBUser{Id: "root"}, oldProjectID: "mci", newProjectID: "mci-copy"}
+	resp := h.Run(s.ctx)
+	s.Require().Equal(http.StatusCreated, resp.Status())
+
+	projectRef, err := s.sc.FindProjectById("mci-copy")
+	s.Require().NoError(err)
+	s.False(projectRef.Enabled)
+	s.Equal("Evergreen", projectRef.DisplayName)
+	vars, err := s.sc.FindProjectVars("mci-copy")
+	s.Require().NoError(err)
+	s.Equal("hunter2", vars.Vars["secret"])
+
+	h.user = &user.DBUser{Id: "admin"}
+	h.newProjectID = "another-copy"
+	resp = h.Run(s.ctx)
+	s.Equal(http.StatusUnauthorized, resp.Status())
+}
+
+func (s *ProjectSettingsSuite) TestDelete() {
+	h := &projectDeleteHandler{sc: s.sc, user: &user.DBUser{Id: "admin"}, projectID: "mci"}
+	resp := h.Run(s.ctx)
+	s.Equal(http.StatusUnauthorized, resp.Status())
+	_, err := s.sc.FindProjectById("mci")
+	s.NoError(err)
+
+	h.user = &user.DBUser{Id: "root"}
+	resp = h.Run(s.ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	_, err = s.sc.FindProjectById("mci")
+	s.Error(err)
+
+	resp = h.Run(s.ctx)
+	s.Equal(http.StatusNotFound, resp.Status())
+}
+
+func (s *ProjectSettingsSuite) TestGetVarsRedactsPrivateVars() {
+	h := &projectVarsGetHandler{sc: s.sc, projectID: "mci"}
+	resp := h.Run(s.ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	vars := resp.Data().(*model.APIProjectVars)
+	s.Equal("1", vars.Vars["a"])
+	s.Equal("", vars.Vars["secret"])
+	s.True(vars.PrivateVars["secret"])
+}
+
+func (s *ProjectSettingsSuite) TestUpdateVars() {
+	h := makePatchProjectVars(s.sc).(*projectVarsPatchHandler)
+	req := s.request(http.MethodPatch, "/projects/mci/variables", model.APIProjectVars{
+		Vars:         map[string]string{"b": "2", "secret": ""},
+		PrivateVars:  map[string]bool{"b": true},
+		VarsToDelete: []string{"a"},
+	})
+	s.Require().NoError(h.Parse(s.ctx, req))
+	h.projectID = "mci"
+
+	resp := h.Run(s.ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	s.Equal("", resp.Data().(*model.APIProjectVars).Vars["b"])
+
+	vars, err := s.sc.FindProjectVars("mci")
+	s.Require().NoError(err)
+	s.Equal(map[string]string{"b": "2", "secret": "hunter2"}, vars.Vars)
+	s.Equal(map[string]bool{"b": true, "secret": true}, vars.PrivateVars)
+}
+
+func (s *ProjectSettingsSuite) TestUpdateVarsKeepsPrivateVarsPrivate() {
+	h := &projectVarsPatchHandler{sc: s.sc, projectID: "mci"}
+
+	// a private variable can't be made public without a new value
+	h.vars = model.APIProjectVars{PrivateVars: map[string]bool{"secret": false}}
+	resp := h.Run(s.ctx)
+	s.Equal(http.StatusBadRequest, resp.Status())
+	vars, err := s.sc.FindProjectVars("mci")
+	s.Require().NoError(err)
+	s.True(vars.PrivateVars["secret"])
+
+	h.vars = model.APIProjectVars{
+		Vars:        map[string]string{"secret": "public"},
+		PrivateVars: map[string]bool{"secret": false},
+	}
+	resp = h.Run(s.ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	s.Equal("public", resp.Data().(*model.APIProjectVars).Vars["secret"])
+
+	// secret variables aren't exposed, so they aren't deleted
+	h.vars = model.APIProjectVars{VarsToDelete: []string{"token"}}
+	resp = h.Run(s.ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	vars, err = s.sc.FindProjectVars("mci")
+	s.Require().NoError(err)
+	s.Equal(map[string]string{"token": "ci/token"}, vars.SecretVars)
+}
+
+func (s *ProjectSettingsSuite) TestPutAliases() {
+	h := &projectAliasesPutHandler{
+		sc:        s.sc,
+		projectID: "mci",
+		aliases: []model.APIAlias{
+			{Alias: model.ToAPIString("__github"), Variant: model.ToAPIString(".*"), Task: model.ToAPIString(".*")},
+		},
+	}
+	resp := h.Run(s.ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	aliases, err := s.sc.FindProjectAliases("mci")
+	s.Require().NoError(err)
+	s.Require().Len(aliases, 1)
+	s.Equal("__github", aliases[0].Alias)
+
+	h.aliases = []model.APIAlias{{Alias: model.ToAPIString("bad")}}
+	resp = h.Run(s.ctx)
+	s.Equal(http.StatusBadRequest, resp.Status())
+	aliases, err = s.sc.FindProjectAliases("mci")
+	s.Require().NoError(err)
+	s.Len(aliases, 1)
+}
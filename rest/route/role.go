@@ -0,0 +1,251 @@
+package route
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/roles
+
+type rolesGetHandler struct {
+	sc data.Connector
+}
+
+func makeFetchRoles(sc data.Connector) gimlet.RouteHandler {
+	return &rolesGetHandler{
+		sc: sc,
+	}
+}
+
+func (h *rolesGetHandler) Factory() gimlet.RouteHandler {
+	return &rolesGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *rolesGetHandler) Parse(ctx context.Context, r *http.Request) error { return nil }
+
+func (h *rolesGetHandler) Run(ctx context.Context) gimlet.Responder {
+	roles, err := h.sc.FindRoles()
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	resp := gimlet.NewResponseBuilder()
+	for _, r := range roles {
+		apiRole := &model.APIRole{}
+		if err = apiRole.BuildFromService(r); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+		if err = resp.AddData(apiRole); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+	}
+
+	return resp
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PUT /rest/v2/roles/{role_id}
+
+type roleUpdateHandler struct {
+	role role.Role
+	sc   data.Connector
+}
+
+func makeUpdateRole(sc data.Connector) gimlet.RouteHandler {
+	return &roleUpdateHandler{
+		sc: sc,
+	}
+}
+
+func (h *roleUpdateHandler) Factory() gimlet.RouteHandler {
+	return &roleUpdateHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *roleUpdateHandler) Parse(ctx context.Context, r *http.Request) error {
+	apiRole := model.APIRole{}
+	if err := util.ReadJSONInto(r.Body, &apiRole); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+	apiRole.Id = model.ToAPIString(gimlet.GetVars(r)["role_id"])
+
+	i, err := apiRole.ToService()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	h.role = i.(role.Role)
+
+	return nil
+}
+
+func (h *roleUpdateHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+	if err := h.sc.UpdateRole(&h.role, u.Username()); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "can't update role '%s'", h.role.Id))
+	}
+
+	apiRole := &model.APIRole{}
+	if err := apiRole.BuildFromService(h.role); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+	return gimlet.NewJSONResponse(apiRole)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// DELETE /rest/v2/roles/{role_id}
+
+type roleDeleteHandler struct {
+	roleId string
+	sc     data.Connector
+}
+
+func makeDeleteRole(sc data.Connector) gimlet.RouteHandler {
+	return &roleDeleteHandler{
+		sc: sc,
+	}
+}
+
+func (h *roleDeleteHandler) Factory() gimlet.RouteHandler {
+	return &roleDeleteHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *roleDeleteHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.roleId = gimlet.GetVars(r)["role_id"]
+	return nil
+}
+
+func (h *roleDeleteHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+	if err := h.sc.RemoveRole(h.roleId, u.Username()); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "can't remove role '%s'", h.roleId))
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/users/{user_id}/roles
+
+type userRolesGetHandler struct {
+	userId string
+	sc     data.Connector
+}
+
+func makeFetchUserRoles(sc data.Connector) gimlet.RouteHandler {
+	return &userRolesGetHandler{
+		sc: sc,
+	}
+}
+
+func (h *userRolesGetHandler) Factory() gimlet.RouteHandler {
+	return &userRolesGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *userRolesGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.userId = gimlet.GetVars(r)["user_id"]
+	return nil
+}
+
+func (h *userRolesGetHandler) Run(ctx context.Context) gimlet.Responder {
+	assignments, err := h.sc.FindRoleAssignments(h.userId)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	resp := gimlet.NewResponseBuilder()
+	for _, a := range assignments {
+		apiAssignment := &model.APIRoleAssignment{}
+		if err = apiAssignment.BuildFromService(a); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+		if err = resp.AddData(apiAssignment); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+	}
+
+	return resp
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/users/{user_id}/roles
+// DELETE /rest/v2/users/{user_id}/roles
+
+type userRoleModifyHandler struct {
+	userId     string
+	assignment model.APIRoleAssignment
+	revoke     bool
+	sc         data.Connector
+}
+
+func makeAssignUserRole(sc data.Connector) gimlet.RouteHandler {
+	return &userRoleModifyHandler{
+		sc: sc,
+	}
+}
+
+func makeRevokeUserRole(sc data.Connector) gimlet.RouteHandler {
+	return &userRoleModifyHandler{
+		revoke: true,
+		sc:     sc,
+	}
+}
+
+func (h *userRoleModifyHandler) Factory() gimlet.RouteHandler {
+	return &userRoleModifyHandler{
+		revoke: h.revoke,
+		sc:     h.sc,
+	}
+}
+
+func (h *userRoleModifyHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.userId = gimlet.GetVars(r)["user_id"]
+	if err := util.ReadJSONInto(r.Body, &h.assignment); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+	if model.FromAPIString(h.assignment.Role) == "" {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "must specify a role",
+		}
+	}
+
+	return nil
+}
+
+func (h *userRoleModifyHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+	roleId := model.FromAPIString(h.assignment.Role)
+	project := model.FromAPIString(h.assignment.Project)
+
+	var err error
+	if h.revoke {
+		err = h.sc.UnassignRole(h.userId, roleId, project, u.Username())
+	} else {
+		err = h.sc.AssignRole(h.userId, roleId, project, u.Username())
+	}
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "can't change roles of user '%s'", h.userId))
+	}
+
+	return gimlet.NewJSONResponse(h.assignment)
+}
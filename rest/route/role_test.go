@@ -0,0 +1,157 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/role"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	restmodel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/suite"
+)
+
+type RoleRouteSuite struct {
+	sc  *data.MockConnector
+	ctx context.Context
+	suite.Suite
+}
+
+func TestRoleRouteSuite(t *testing.T) {
+	suite.Run(t, new(RoleRouteSuite))
+}
+
+func (s *RoleRouteSuite) SetupTest() {
+	s.sc = &data.MockConnector{}
+	s.sc.SetSuperUsers([]string{"root"})
+	s.ctx = gimlet.AttachUser(context.Background(), &user.DBUser{Id: "root"})
+}
+
+func (s *RoleRouteSuite) TestFetchRoles() {
+	resp := makeFetchRoles(s.sc).Run(s.ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	roles, ok := resp.Data().([]interface{})
+	s.Require().True(ok)
+	s.Len(roles, len(role.BuiltInRoles()))
+	for _, r := range roles {
+		s.True(r.(*restmodel.APIRole).BuiltIn)
+	}
+}
+
+func (s *RoleRouteSuite) TestUpdateRole() {
+	h := makeUpdateRole(s.sc).(*roleUpdateHandler)
+	r, err := http.NewRequest(http.MethodPut, "/roles/distro_admin", bytes.NewBufferString(`{"description": "distros", "permissions": ["manage_distros"]}`))
+	s.Require().NoError(err)
+	s.Require().NoError(h.Parse(s.ctx, r))
+	s.Equal([]string{role.PermissionManageDistros}, h.role.Permissions)
+
+	h.role = role.Role{Id: "distro_admin", Permissions: []string{role.PermissionManageDistros}}
+	resp := h.Run(s.ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	s.Equal("distro_admin", restmodel.FromAPIString(resp.Data().(*restmodel.APIRole).Id))
+	s.Require().Len(s.sc.MockRoleConnector.Events, 1)
+	s.Equal(event.RoleActionUpdate, s.sc.MockRoleConnector.Events[0].Action)
+
+	h.role = role.Role{Id: "bad", Permissions: []string{"launch_missiles"}}
+	resp = h.Run(s.ctx)
+	s.Equal(http.StatusBadRequest, resp.Status())
+}
+
+func (s *RoleRouteSuite) TestDeleteRole() {
+	s.sc.MockRoleConnector.CachedRoles = []role.Role{{Id: "distro_admin"}}
+	h := makeDeleteRole(s.sc).(*roleDeleteHandler)
+
+	h.roleId = "distro_admin"
+	s.Equal(http.StatusOK, h.Run(s.ctx).Status())
+	s.Empty(s.sc.MockRoleConnector.CachedRoles)
+
+	s.Equal(http.StatusNotFound, h.Run(s.ctx).Status())
+
+	h.roleId = role.AdminRole
+	s.Equal(http.StatusBadRequest, h.Run(s.ctx).Status())
+}
+
+func (s *RoleRouteSuite) TestAssignAndRevoke() {
+	assign := makeAssignUserRole(s.sc).(*userRoleModifyHandler)
+	assign.userId = "alice"
+	assign.assignment = restmodel.APIRoleAssignment{
+		Role:    restmodel.ToAPIString(role.ProjectAdminRole),
+		Project: restmodel.ToAPIString("mci"),
+	}
+	s.Require().Equal(http.StatusOK, assign.Run(s.ctx).Status())
+
+	fetch := makeFetchUserRoles(s.sc).(*userRolesGetHandler)
+	fetch.userId = "alice"
+	resp := fetch.Run(s.ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	assignments := resp.Data().([]interface{})
+	s.Require().Len(assignments, 1)
+	s.Equal("mci", restmodel.FromAPIString(assignments[0].(*restmodel.APIRoleAssignment).Project))
+
+	revoke := makeRevokeUserRole(s.sc).Factory().(*userRoleModifyHandler)
+	s.True(revoke.revoke)
+	revoke.userId = "alice"
+	revoke.assignment = assign.assignment
+	s.Require().Equal(http.StatusOK, revoke.Run(s.ctx).Status())
+	s.Empty(s.sc.MockRoleConnector.CachedAssignments)
+	s.Equal(http.StatusNotFound, revoke.Run(s.ctx).Status())
+
+	assign.assignment.Role = restmodel.ToAPIString("missing")
+	s.Equal(http.StatusNotFound, assign.Run(s.ctx).Status())
+
+	s.Len(s.sc.MockRoleConnector.Events, 2)
+}
+
+func (s *RoleRouteSuite) checkPermission(permission string, u gimlet.User, projectRef *model.ProjectRef) int {
+	ctx := context.Background()
+	if u != nil {
+		ctx = gimlet.AttachUser(ctx, u)
+	}
+	if projectRef != nil {
+		ctx = context.WithValue(ctx, RequestContext, &model.Context{ProjectRef: projectRef})
+	}
+	r, err := http.NewRequest(http.MethodPost, "/", nil)
+	s.Require().NoError(err)
+	r = r.WithContext(ctx)
+
+	rw := httptest.NewRecorder()
+	NewRequirePermissionMiddleware(s.sc, permission).ServeHTTP(rw, r, func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+	return rw.Code
+}
+
+func (s *RoleRouteSuite) TestPermissionMiddleware() {
+	root := &user.DBUser{Id: "root"}
+	alice := &user.DBUser{Id: "alice"}
+	bob := &user.DBUser{Id: "bob"}
+	mci := &model.ProjectRef{Identifier: "mci", Private: true, Admins: []string{"bob"}}
+	other := &model.ProjectRef{Identifier: "other", Private: true}
+	public := &model.ProjectRef{Identifier: "public"}
+
+	s.Equal(http.StatusUnauthorized, s.checkPermission(role.PermissionRestartTasks, nil, mci))
+
+	// the default role keeps the access users had before roles existed
+	s.Equal(http.StatusOK, s.checkPermission(role.PermissionRestartTasks, alice, mci))
+	s.Equal(http.StatusUnauthorized, s.checkPermission(role.PermissionManageDistros, alice, nil))
+	s.Equal(http.StatusUnauthorized, s.checkPermission(role.PermissionEditProjectSettings, alice, mci))
+	s.Equal(http.StatusOK, s.checkPermission(role.PermissionManageDistros, root, nil))
+	s.Equal(http.StatusOK, s.checkPermission(role.PermissionEditProjectSettings, bob, mci))
+
+	// narrowing the default role restricts everyone but admins
+	s.sc.MockRoleConnector.CachedRoles = []role.Role{{Id: role.DefaultRole}}
+	s.Equal(http.StatusUnauthorized, s.checkPermission(role.PermissionViewPrivateLogs, alice, mci))
+	s.Equal(http.StatusOK, s.checkPermission(role.PermissionViewPrivateLogs, alice, public))
+	s.Equal(http.StatusOK, s.checkPermission(role.PermissionViewPrivateLogs, bob, mci))
+
+	s.sc.MockRoleConnector.CachedAssignments = []role.Assignment{role.NewAssignment("alice", role.ProjectAdminRole, "mci")}
+	s.Equal(http.StatusOK, s.checkPermission(role.PermissionViewPrivateLogs, alice, mci))
+	s.Equal(http.StatusUnauthorized, s.checkPermission(role.PermissionViewPrivateLogs, alice, other))
+	s.Equal(http.StatusUnauthorized, s.checkPermission(role.PermissionManageSpawnHosts, alice, mci))
+}
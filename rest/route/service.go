@@ -1,6 +1,7 @@
 package route
 
 import (
+	"github.com/evergreen-ci/evergreen/model/role"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/mongodb/amboy"
@@ -21,6 +22,11 @@ func AttachHandler(app *gimlet.APIApp, queue amboy.Queue, URL string, superUsers
 	superUser := gimlet.NewRestrictAccessToUsers(sc.GetSuperUsers())
 	checkUser := gimlet.NewRequireAuthHandler()
 	addProject := NewProjectContextMiddleware(sc)
+	canRestartTasks := NewRequirePermissionMiddleware(sc, role.PermissionRestartTasks)
+	canViewLogs := NewRequirePermissionMiddleware(sc, role.PermissionViewPrivateLogs)
+	canSpawnHosts := NewRequirePermissionMiddleware(sc, role.PermissionManageSpawnHosts)
+	canEditProject := NewRequirePermissionMiddleware(sc, role.PermissionEditProjectSettings)
+	canManageDistros := NewRequirePermissionMiddleware(sc, role.PermissionManageDistros)
 
 	// Routes
 	app.AddRoute("/").Version(2).Get().RouteHandler(makePlaceHolderManger(sc))
@@ -38,19 +44,27 @@ func AttachHandler(app *gimlet.APIApp, queue amboy.Queue, URL string, superUsers
 	app.AddRoute("/builds/{build_id}").Version(2).Get().RouteHandler(makeGetBuildByID(sc))
 	app.AddRoute("/builds/{build_id}").Version(2).Patch().Wrap(checkUser).RouteHandler(makeChangeStatusForBuild(sc))
 	app.AddRoute("/builds/{build_id}/abort").Version(2).Post().Wrap(checkUser).RouteHandler(makeAbortBuild(sc))
-	app.AddRoute("/builds/{build_id}/restart").Version(2).Post().Wrap(checkUser).RouteHandler(makeRestartBuild(sc))
+	app.AddRoute("/builds/{build_id}/restart").Version(2).Post().Wrap(checkUser, addProject, canRestartTasks).RouteHandler(makeRestartBuild(sc))
 	app.AddRoute("/builds/{build_id}/tasks").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchTasksByBuild(sc))
 	app.AddRoute("/cost/distro/{distro_id}").Version(2).Get().Wrap(checkUser).RouteHandler(makeCostByDistroHandler(sc))
 	app.AddRoute("/cost/project/{project_id}/tasks").Version(2).Get().Wrap(checkUser).RouteHandler(makeTaskCostByProjectRoute(sc))
 	app.AddRoute("/cost/version/{version_id}").Version(2).Get().Wrap(checkUser).RouteHandler(makeCostByVersionHandler(sc))
 	app.AddRoute("/distros").Version(2).Get().Wrap(checkUser).RouteHandler(makeDistroRoute(sc))
+	app.AddRoute("/distros/{distro_id}").Version(2).Get().Wrap(checkUser).RouteHandler(makeGetDistroByID(sc))
+	app.AddRoute("/distros/{distro_id}").Version(2).Put().Wrap(checkUser, canManageDistros).RouteHandler(makePutDistro(sc))
+	app.AddRoute("/distros/{distro_id}").Version(2).Patch().Wrap(checkUser, canManageDistros).RouteHandler(makePatchDistro(sc))
+	app.AddRoute("/distros/{distro_id}").Version(2).Delete().Wrap(checkUser, canManageDistros).RouteHandler(makeDeleteDistro(sc))
+	app.AddRoute("/distros/{distro_id}/copy").Version(2).Post().Wrap(checkUser, canManageDistros).RouteHandler(makeCopyDistro(sc))
+	app.AddRoute("/distros/{distro_id}/revisions").Version(2).Get().Wrap(checkUser).RouteHandler(makeGetDistroRevisions(sc))
+	app.AddRoute("/distros/{distro_id}/revisions/{revision}/diff").Version(2).Get().Wrap(checkUser).RouteHandler(makeDiffDistroRevision(sc))
+	app.AddRoute("/distros/{distro_id}/revisions/{revision}/rollback").Version(2).Post().Wrap(checkUser, canManageDistros).RouteHandler(makeRollbackDistro(sc))
 	app.AddRoute("/hooks/github").Version(2).Post().RouteHandler(makeGithubHooksRoute(sc, queue, githubSecret))
 	app.AddRoute("/hosts").Version(2).Get().RouteHandler(makeFetchHosts(sc))
-	app.AddRoute("/hosts").Version(2).Post().Wrap(checkUser).RouteHandler(makeSpawnHostCreateRoute(sc))
+	app.AddRoute("/hosts").Version(2).Post().Wrap(checkUser, canSpawnHosts).RouteHandler(makeSpawnHostCreateRoute(sc))
 	app.AddRoute("/hosts/{host_id}").Version(2).Get().RouteHandler(makeGetHostByID(sc))
-	app.AddRoute("/hosts/{host_id}/change_password").Version(2).Post().Wrap(checkUser).RouteHandler(makeHostChangePassword(sc))
-	app.AddRoute("/hosts/{host_id}/extend_expiration").Version(2).Post().Wrap(checkUser).RouteHandler(makeExtendHostExpiration(sc))
-	app.AddRoute("/hosts/{host_id}/terminate").Version(2).Post().Wrap(checkUser).RouteHandler(makeTerminateHostRoute(sc))
+	app.AddRoute("/hosts/{host_id}/change_password").Version(2).Post().Wrap(checkUser, canSpawnHosts).RouteHandler(makeHostChangePassword(sc))
+	app.AddRoute("/hosts/{host_id}/extend_expiration").Version(2).Post().Wrap(checkUser, canSpawnHosts).RouteHandler(makeExtendHostExpiration(sc))
+	app.AddRoute("/hosts/{host_id}/terminate").Version(2).Post().Wrap(checkUser, canSpawnHosts).RouteHandler(makeTerminateHostRoute(sc))
 	app.AddRoute("/hosts/{task_id}/create").Version(2).Post().RouteHandler(makeHostCreateRouteManager(sc))
 	app.AddRoute("/hosts/{task_id}/list").Version(2).Get().RouteHandler(makeHostListRouteManager(sc))
 	app.AddRoute("/keys").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchKeys(sc))
@@ -59,11 +73,29 @@ func AttachHandler(app *gimlet.APIApp, queue amboy.Queue, URL string, superUsers
 	app.AddRoute("/patches/{patch_id}").Version(2).Get().RouteHandler(makeFetchPatchByID(sc))
 	app.AddRoute("/patches/{patch_id}").Version(2).Patch().Wrap(checkUser).RouteHandler(makeChangePatchStatus(sc))
 	app.AddRoute("/patches/{patch_id}/abort").Version(2).Post().Wrap(checkUser).RouteHandler(makeAbortPatch(sc))
-	app.AddRoute("/patches/{patch_id}/restart").Version(2).Post().Wrap(checkUser).RouteHandler(makeRestartPatch(sc))
+	app.AddRoute("/patches/{patch_id}/restart").Version(2).Post().Wrap(checkUser, addProject, canRestartTasks).RouteHandler(makeRestartPatch(sc))
 	app.AddRoute("/projects").Version(2).Get().RouteHandler(makeFetchProjectsRoute(sc))
+	app.AddRoute("/projects/{project_id}").Version(2).Get().Wrap(checkUser, addProject).RouteHandler(makeGetProjectByID(sc))
+	app.AddRoute("/projects/{project_id}").Version(2).Put().Wrap(checkUser, addProject, canEditProject).RouteHandler(makePutProjectByID(sc))
+	app.AddRoute("/projects/{project_id}").Version(2).Patch().Wrap(checkUser, addProject, canEditProject).RouteHandler(makePatchProjectByID(sc))
+	app.AddRoute("/projects/{project_id}").Version(2).Delete().Wrap(checkUser, addProject, canEditProject).RouteHandler(makeDeleteProject(sc))
+	app.AddRoute("/projects/{project_id}/aliases").Version(2).Put().Wrap(checkUser, addProject, canEditProject).RouteHandler(makePutProjectAliases(sc))
+	app.AddRoute("/projects/{project_id}/copy").Version(2).Post().Wrap(checkUser, addProject, canEditProject).RouteHandler(makeCopyProject(sc))
+	app.AddRoute("/projects/{project_id}/flaky_tests").Version(2).Get().Wrap(checkUser, addProject).RouteHandler(makeFetchFlakyTests(sc))
+	app.AddRoute("/projects/{project_id}/flaky_tests/quarantine").Version(2).Post().Wrap(checkUser, addProject, canEditProject).RouteHandler(makeQuarantineFlakyTest(sc))
+	app.AddRoute("/commit_queue/{project_id}").Version(2).Get().Wrap(checkUser, addProject).RouteHandler(makeGetCommitQueue(sc))
+	app.AddRoute("/commit_queue/{project_id}/{item}").Version(2).Put().Wrap(checkUser, addProject).RouteHandler(makeCommitQueueEnqueueItem(sc))
+	app.AddRoute("/commit_queue/{project_id}/{item}").Version(2).Delete().Wrap(checkUser, addProject).RouteHandler(makeCommitQueueDeleteItem(sc))
+	app.AddRoute("/commit_queue/{project_id}/{item}").Version(2).Patch().Wrap(checkUser, addProject).RouteHandler(makeCommitQueueMoveItem(sc))
 	app.AddRoute("/projects/{project_id}/patches").Version(2).Get().Wrap(checkUser).RouteHandler(makePatchesByProjectRoute(sc))
 	app.AddRoute("/projects/{project_id}/recent_versions").Version(2).Get().RouteHandler(makeFetchProjectVersions(sc))
+	app.AddRoute("/projects/{project_id}/versions").Version(2).Post().Wrap(checkUser, addProject, canEditProject).RouteHandler(makeCreateManualVersion(sc))
 	app.AddRoute("/projects/{project_id}/revisions/{commit_hash}/tasks").Version(2).Get().Wrap(checkUser).RouteHandler(makeTasksByProjectAndCommitHandler(sc))
+	app.AddRoute("/projects/{project_id}/variables").Version(2).Get().Wrap(checkUser, addProject, canEditProject).RouteHandler(makeGetProjectVars(sc))
+	app.AddRoute("/projects/{project_id}/variables").Version(2).Patch().Wrap(checkUser, addProject, canEditProject).RouteHandler(makePatchProjectVars(sc))
+	app.AddRoute("/roles").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchRoles(sc))
+	app.AddRoute("/roles/{role_id}").Version(2).Put().Wrap(superUser).RouteHandler(makeUpdateRole(sc))
+	app.AddRoute("/roles/{role_id}").Version(2).Delete().Wrap(superUser).RouteHandler(makeDeleteRole(sc))
 	app.AddRoute("/status/cli_version").Version(2).Get().RouteHandler(makeFetchCLIVersionRoute(sc))
 	app.AddRoute("/status/hosts/distros").Version(2).Get().Wrap(checkUser).RouteHandler(makeHostStatusByDistroRoute(sc))
 	app.AddRoute("/status/notifications").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchNotifcationStatusRoute(sc))
@@ -75,16 +107,21 @@ func AttachHandler(app *gimlet.APIApp, queue amboy.Queue, URL string, superUsers
 	app.AddRoute("/tasks/{task_id}").Version(2).Patch().Wrap(checkUser, addProject).RouteHandler(makeModifyTaskRoute(sc))
 	app.AddRoute("/tasks/{task_id}/abort").Version(2).Post().Wrap(checkUser).RouteHandler(makeTaskAbortHandler(sc))
 	app.AddRoute("/tasks/{task_id}/generate").Version(2).Post().RouteHandler(makeGenerateTasksHandler(sc))
+	app.AddRoute("/tasks/{task_id}/log/stream").Version(2).Get().Wrap(checkUser, addProject, canViewLogs).Handler(makeTaskLogStreamHandler(sc))
 	app.AddRoute("/tasks/{task_id}/metrics/process").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchTaskProcessMetrics(sc))
 	app.AddRoute("/tasks/{task_id}/metrics/system").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchTaskSystmMetrics(sc))
-	app.AddRoute("/tasks/{task_id}/restart").Version(2).Post().Wrap(addProject, checkUser).RouteHandler(makeTaskRestartHandler(sc))
+	app.AddRoute("/tasks/{task_id}/restart").Version(2).Post().Wrap(addProject, checkUser, canRestartTasks).RouteHandler(makeTaskRestartHandler(sc))
 	app.AddRoute("/tasks/{task_id}/tests").Version(2).Get().Wrap(addProject).RouteHandler(makeFetchTestsForTask(sc))
 	app.AddRoute("/user/settings").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchUserConfig())
 	app.AddRoute("/user/settings").Version(2).Post().Wrap(checkUser).RouteHandler(makeSetUserConfig(sc))
 	app.AddRoute("/users/{user_id}/hosts").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchHosts(sc))
+	app.AddRoute("/users/{user_id}/roles").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchUserRoles(sc))
+	app.AddRoute("/users/{user_id}/roles").Version(2).Post().Wrap(superUser).RouteHandler(makeAssignUserRole(sc))
+	app.AddRoute("/users/{user_id}/roles").Version(2).Delete().Wrap(superUser).RouteHandler(makeRevokeUserRole(sc))
 	app.AddRoute("/users/{user_id}/patches").Version(2).Get().Wrap(checkUser).RouteHandler(makeUserPatchHandler(sc))
 	app.AddRoute("/versions/{version_id}").Version(2).Get().RouteHandler(makeGetVersionByID(sc))
 	app.AddRoute("/versions/{version_id}/abort").Version(2).Post().Wrap(checkUser).RouteHandler(makeAbortVersion(sc))
+	app.AddRoute("/versions/{version_id}/graph").Version(2).Get().RouteHandler(makeGetVersionTaskGraph(sc))
 	app.AddRoute("/versions/{version_id}/builds").Version(2).Get().RouteHandler(makeGetVersionBuilds(sc))
-	app.AddRoute("/versions/{version_id}/restart").Version(2).Post().Wrap(checkUser).RouteHandler(makeRestartVersion(sc))
+	app.AddRoute("/versions/{version_id}/restart").Version(2).Post().Wrap(checkUser, addProject, canRestartTasks).RouteHandler(makeRestartVersion(sc))
 }
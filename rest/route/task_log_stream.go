@@ -0,0 +1,216 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	taskLogStreamPollInterval = time.Second
+	// taskLogStreamWindow bounds how long one request follows a log, so
+	// that it ends before the server's write timeout. Clients resume from
+	// the last chunk's next offset.
+	taskLogStreamWindow = 45 * time.Second
+)
+
+// taskLogTypes maps the log names accepted by the stream route to the
+// message types they select.
+var taskLogTypes = map[string][]string{
+	"task":   {apimodels.TaskLogPrefix},
+	"agent":  {apimodels.AgentLogPrefix},
+	"system": {apimodels.SystemLogPrefix},
+	"all":    {},
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/tasks/{task_id}/log/stream
+//
+// Streams the task's log as a sequence of JSON encoded APITaskLogChunks,
+// starting at the "offset" query parameter. When "follow" is true, the
+// stream continues as messages are appended, until the task finishes or the
+// stream window closes; the last chunk's next_offset resumes the stream.
+
+type taskLogStreamHandler struct {
+	taskId    string
+	execution int
+	offset    int
+	types     []string
+	follow    bool
+
+	pollInterval time.Duration
+	window       time.Duration
+	sc           data.Connector
+}
+
+func makeTaskLogStreamHandler(sc data.Connector) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h := &taskLogStreamHandler{
+			pollInterval: taskLogStreamPollInterval,
+			window:       taskLogStreamWindow,
+			sc:           sc,
+		}
+		if err := h.parse(r); err != nil {
+			resp, ok := err.(gimlet.ErrorResponse)
+			if !ok {
+				resp = gimlet.ErrorResponse{StatusCode: http.StatusBadRequest, Message: err.Error()}
+			}
+			gimlet.WriteJSONResponse(w, resp.StatusCode, resp)
+			return
+		}
+
+		h.stream(r.Context(), w)
+	}
+}
+
+func (h *taskLogStreamHandler) parse(r *http.Request) error {
+	h.taskId = gimlet.GetVars(r)["task_id"]
+	vals := r.URL.Query()
+
+	logType := vals.Get("type")
+	if logType == "" {
+		logType = "task"
+	}
+	types, ok := taskLogTypes[logType]
+	if !ok {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "type must be one of task, agent, system, or all",
+		}
+	}
+	h.types = types
+
+	var err error
+	h.execution = -1
+	if execution := vals.Get("execution"); execution != "" {
+		if h.execution, err = strconv.Atoi(execution); err != nil || h.execution < 0 {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    "execution must be a non-negative integer",
+			}
+		}
+	}
+	if offset := vals.Get("offset"); offset != "" {
+		if h.offset, err = strconv.Atoi(offset); err != nil || h.offset < 0 {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    "offset must be a non-negative integer",
+			}
+		}
+	}
+	h.follow = vals.Get("follow") == "true"
+
+	return nil
+}
+
+func (h *taskLogStreamHandler) stream(ctx context.Context, w http.ResponseWriter) {
+	finished, err := h.taskFinished()
+	if err != nil {
+		gimlet.WriteJSONResponse(w, http.StatusNotFound, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    err.Error(),
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(w)
+	flusher, _ := w.(http.Flusher)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	deadline := time.Now().Add(h.window)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		// check whether the task is done before reading, so that the
+		// last chunk of a finished task includes everything it logged
+		if h.follow && !finished {
+			if finished, err = h.taskFinished(); err != nil {
+				return
+			}
+		}
+
+		chunk, err := h.readChunk()
+		if err != nil {
+			grip.Warning(message.WrapError(err, message.Fields{
+				"message": "problem reading task log",
+				"task_id": h.taskId,
+				"offset":  h.offset,
+			}))
+			return
+		}
+		done := !h.follow || finished || time.Now().After(deadline)
+		chunk.Finished = finished
+
+		if len(chunk.Messages) > 0 || done {
+			if err = encoder.Encode(chunk); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if done {
+			return
+		}
+
+		timer.Reset(h.pollInterval)
+	}
+}
+
+func (h *taskLogStreamHandler) readChunk() (*model.APITaskLogChunk, error) {
+	msgs, next, err := h.sc.GetTaskLogMessagesFromOffset(h.taskId, h.execution, h.offset, h.types)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	chunk := &model.APITaskLogChunk{
+		Messages:   []model.APILogMessage{},
+		Execution:  h.execution,
+		NextOffset: next,
+	}
+	for _, msg := range msgs {
+		apiMsg := model.APILogMessage{}
+		if err = apiMsg.BuildFromService(msg); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		chunk.Messages = append(chunk.Messages, apiMsg)
+	}
+	h.offset = next
+
+	return chunk, nil
+}
+
+// taskFinished returns true if the streamed execution of the task has
+// finished. It defaults the execution to the task's latest one.
+func (h *taskLogStreamHandler) taskFinished() (bool, error) {
+	t, err := h.sc.FindTaskById(h.taskId)
+	if err != nil {
+		return false, errors.Wrapf(err, "problem finding task '%s'", h.taskId)
+	}
+	if t == nil {
+		return false, errors.Errorf("task '%s' not found", h.taskId)
+	}
+
+	if h.execution < 0 {
+		h.execution = t.Execution
+	}
+	return h.execution < t.Execution || t.IsFinished(), nil
+}
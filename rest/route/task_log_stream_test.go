@@ -0,0 +1,134 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type TaskLogStreamSuite struct {
+	sc *data.MockConnector
+	suite.Suite
+}
+
+func TestTaskLogStreamSuite(t *testing.T) {
+	suite.Run(t, new(TaskLogStreamSuite))
+}
+
+func (s *TaskLogStreamSuite) SetupTest() {
+	s.sc = &data.MockConnector{
+		MockTaskConnector: data.MockTaskConnector{
+			CachedTasks: []task.Task{
+				{Id: "running", Status: evergreen.TaskStarted},
+				{Id: "done", Status: evergreen.TaskSucceeded, Execution: 2},
+			},
+		},
+		MockTaskLogConnector: data.MockTaskLogConnector{
+			CachedLogMessages: map[string][]apimodels.LogMessage{
+				"running": {
+					{Type: apimodels.TaskLogPrefix, Message: "one"},
+					{Type: apimodels.AgentLogPrefix, Message: "two"},
+				},
+				"done": {
+					{Type: apimodels.TaskLogPrefix, Message: "one"},
+					{Type: apimodels.TaskLogPrefix, Message: "two"},
+					{Type: apimodels.SystemLogPrefix, Message: "three"},
+				},
+			},
+		},
+	}
+}
+
+func (s *TaskLogStreamSuite) handler(taskId string, follow bool) *taskLogStreamHandler {
+	return &taskLogStreamHandler{
+		taskId:       taskId,
+		execution:    -1,
+		types:        []string{apimodels.TaskLogPrefix},
+		follow:       follow,
+		pollInterval: time.Millisecond,
+		window:       50 * time.Millisecond,
+		sc:           s.sc,
+	}
+}
+
+func (s *TaskLogStreamSuite) readChunks(w *httptest.ResponseRecorder) []model.APITaskLogChunk {
+	chunks := []model.APITaskLogChunk{}
+	decoder := json.NewDecoder(w.Body)
+	for decoder.More() {
+		chunk := model.APITaskLogChunk{}
+		s.Require().NoError(decoder.Decode(&chunk))
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+func (s *TaskLogStreamSuite) TestParse() {
+	h := &taskLogStreamHandler{}
+	r, err := http.NewRequest(http.MethodGet, "/tasks/t1/log/stream?type=all&offset=4&follow=true", nil)
+	s.Require().NoError(err)
+	s.NoError(h.parse(r))
+	s.Equal(-1, h.execution)
+	s.Equal(4, h.offset)
+	s.Empty(h.types)
+	s.True(h.follow)
+
+	for _, query := range []string{"type=foo", "offset=-1", "execution=x"} {
+		r, err = http.NewRequest(http.MethodGet, "/tasks/t1/log/stream?"+query, nil)
+		s.Require().NoError(err)
+		s.Error(h.parse(r), query)
+	}
+}
+
+func (s *TaskLogStreamSuite) TestFinishedTask() {
+	w := httptest.NewRecorder()
+	s.handler("done", true).stream(context.Background(), w)
+	s.Equal(http.StatusOK, w.Code)
+
+	chunks := s.readChunks(w)
+	s.Require().Len(chunks, 1)
+	s.True(chunks[0].Finished)
+	s.Equal(2, chunks[0].Execution)
+	s.Equal(3, chunks[0].NextOffset)
+	s.Require().Len(chunks[0].Messages, 2)
+	s.Equal("two", model.FromAPIString(chunks[0].Messages[1].Message))
+}
+
+func (s *TaskLogStreamSuite) TestFollowRunningTaskUntilWindowCloses() {
+	h := s.handler("running", true)
+	h.offset = 1
+	w := httptest.NewRecorder()
+	h.stream(context.Background(), w)
+
+	chunks := s.readChunks(w)
+	s.Require().Len(chunks, 1)
+	s.False(chunks[0].Finished)
+	s.Empty(chunks[0].Messages)
+	s.Equal(2, chunks[0].NextOffset)
+}
+
+func (s *TaskLogStreamSuite) TestNoFollowReturnsOneChunk() {
+	w := httptest.NewRecorder()
+	s.handler("running", false).stream(context.Background(), w)
+
+	chunks := s.readChunks(w)
+	s.Require().Len(chunks, 1)
+	s.False(chunks[0].Finished)
+	s.Require().Len(chunks[0].Messages, 1)
+	s.Equal("one", model.FromAPIString(chunks[0].Messages[0].Message))
+}
+
+func (s *TaskLogStreamSuite) TestMissingTask() {
+	w := httptest.NewRecorder()
+	s.handler("nonexistent", false).stream(context.Background(), w)
+	s.Equal(http.StatusNotFound, w.Code)
+}
@@ -2,10 +2,13 @@ package route
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
+	"github.com/evergreen-ci/evergreen/repotracker"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/pkg/errors"
 )
@@ -225,3 +228,132 @@ func (h *versionRestartHandler) Run(ctx context.Context) gimlet.Responder {
 
 	return gimlet.NewJSONResponse(versionModel)
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/versions/{version_id}/graph
+
+const (
+	taskGraphFormatJSON = "json"
+	taskGraphFormatDOT  = "dot"
+)
+
+// versionTaskGraphHandler is a RequestHandler for fetching the task
+// dependency graph and critical path of a version.
+type versionTaskGraphHandler struct {
+	versionId string
+	format    string
+	sc        data.Connector
+}
+
+func makeGetVersionTaskGraph(sc data.Connector) gimlet.RouteHandler {
+	return &versionTaskGraphHandler{
+		sc: sc,
+	}
+}
+
+func (h *versionTaskGraphHandler) Factory() gimlet.RouteHandler {
+	return &versionTaskGraphHandler{sc: h.sc}
+}
+
+// Parse fetches the versionId and the output format from the http request.
+func (h *versionTaskGraphHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.versionId = gimlet.GetVars(r)["version_id"]
+	if h.versionId == "" {
+		return errors.New("request data incomplete")
+	}
+
+	h.format = r.URL.Query().Get("format")
+	if h.format == "" {
+		h.format = taskGraphFormatJSON
+	}
+	if h.format != taskGraphFormatJSON && h.format != taskGraphFormatDOT {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("invalid format '%s', must be '%s' or '%s'", h.format, taskGraphFormatJSON, taskGraphFormatDOT),
+		}
+	}
+
+	return nil
+}
+
+// Run builds the task graph of the version and returns it in the requested format.
+func (h *versionTaskGraphHandler) Run(ctx context.Context) gimlet.Responder {
+	graph, err := h.sc.FindVersionTaskGraph(h.versionId)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error in building task graph"))
+	}
+
+	if h.format == taskGraphFormatDOT {
+		return gimlet.NewTextResponse(graph.DOT())
+	}
+
+	graphModel := &model.APITaskGraph{}
+	if err = graphModel.BuildFromService(graph); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+
+	return gimlet.NewJSONResponse(graphModel)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/projects/{project_id}/versions
+
+// manualVersionHandler is a RequestHandler for starting a version of a
+// revision of a project with user supplied parameters.
+type manualVersionHandler struct {
+	projectId string
+	request   model.APIManualVersionRequest
+	sc        data.Connector
+}
+
+func makeCreateManualVersion(sc data.Connector) gimlet.RouteHandler {
+	return &manualVersionHandler{
+		sc: sc,
+	}
+}
+
+func (h *manualVersionHandler) Factory() gimlet.RouteHandler {
+	return &manualVersionHandler{sc: h.sc}
+}
+
+// Parse fetches the projectId and the version request from the http request.
+func (h *manualVersionHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectId = gimlet.GetVars(r)["project_id"]
+	if h.projectId == "" {
+		return errors.New("request data incomplete")
+	}
+
+	if err := util.ReadJSONInto(util.NewRequestReader(r), &h.request); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("problem parsing request body: %s", err.Error()),
+		}
+	}
+
+	return nil
+}
+
+// Run creates the version and returns it.
+func (h *manualVersionHandler) Run(ctx context.Context) gimlet.Responder {
+	v, err := h.sc.CreateManualVersion(h.projectId, repotracker.ManualVersionOptions{
+		Revision:   h.request.Revision,
+		Parameters: h.request.Parameters,
+		Variants:   h.request.Variants,
+		Tasks:      h.request.Tasks,
+		Alias:      h.request.Alias,
+		Author:     MustHaveUser(ctx).Id,
+		Message:    h.request.Message,
+	})
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem creating version"))
+	}
+
+	versionModel := &model.APIVersion{}
+	if err = versionModel.BuildFromService(v); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+
+	return gimlet.NewJSONResponse(versionModel)
+}
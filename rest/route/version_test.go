@@ -1,6 +1,7 @@
 package route
 
 import (
+	"bytes"
 	"context"
 	"net/http"
 	"testing"
@@ -198,3 +199,68 @@ func (s *VersionSuite) TestRestartVersion() {
 	s.Equal(model.ToAPIString(versionId), h.Id)
 	s.Equal("caller1", s.versionData.CachedRestartedVersions["versionId"])
 }
+
+// TestVersionTaskGraph tests the route for fetching the task graph of a version.
+func (s *VersionSuite) TestVersionTaskGraph() {
+	sc := &data.MockConnector{
+		MockVersionConnector: data.MockVersionConnector{
+			CachedTasks: []task.Task{
+				{Id: "compile", Version: versionId, Status: evergreen.TaskSucceeded, TimeTaken: time.Minute},
+				{Id: "test", Version: versionId, Status: evergreen.TaskUndispatched, ExpectedDuration: 2 * time.Minute,
+					DependsOn: []task.Dependency{{TaskId: "compile"}}},
+			},
+		},
+	}
+
+	r, err := http.NewRequest(http.MethodGet, "/versions/versionId/graph?format=svg", nil)
+	s.Require().NoError(err)
+	handler := makeGetVersionTaskGraph(sc).(*versionTaskGraphHandler)
+	s.Error(handler.Parse(context.TODO(), r))
+
+	handler.versionId = versionId
+	handler.format = taskGraphFormatJSON
+	res := handler.Run(context.TODO())
+	s.Require().Equal(http.StatusOK, res.Status())
+	graph, ok := res.Data().(*model.APITaskGraph)
+	s.Require().True(ok)
+	s.Len(graph.Nodes, 2)
+	s.Len(graph.Edges, 1)
+	s.Equal([]model.APIString{model.ToAPIString("compile"), model.ToAPIString("test")}, graph.CriticalPath)
+	s.Equal(model.NewAPIDuration(3*time.Minute), graph.CriticalPathDuration)
+
+	handler.format = taskGraphFormatDOT
+	res = handler.Run(context.TODO())
+	s.Require().Equal(http.StatusOK, res.Status())
+	s.Contains(res.Data(), `"compile" -> "test"`)
+
+	handler.versionId = "missing"
+	s.Equal(http.StatusNotFound, handler.Run(context.TODO()).Status())
+}
+
+// TestCreateManualVersion tests the route for starting a manual version.
+func (s *VersionSuite) TestCreateManualVersion() {
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "release-engineer"})
+	sc := &data.MockConnector{}
+
+	body := bytes.NewBufferString(`{"revision": "abcdef", "parameters": {"release": "4.2.0"}}`)
+	r, err := http.NewRequest(http.MethodPost, "/projects/proj/versions", body)
+	s.Require().NoError(err)
+	s.Error(makeCreateManualVersion(sc).Parse(ctx, r))
+
+	handler := &manualVersionHandler{
+		projectId: "proj",
+		request: model.APIManualVersionRequest{
+			Revision:   "abcdef",
+			Parameters: map[string]string{"release": "4.2.0"},
+		},
+		sc: sc,
+	}
+	res := handler.Run(ctx)
+	s.Require().Equal(http.StatusOK, res.Status())
+	v, ok := res.Data().(*model.APIVersion)
+	s.Require().True(ok)
+	s.Equal(model.ToAPIString(evergreen.ManualVersionRequester), v.Requester)
+	s.Equal(model.ToAPIString("release-engineer"), v.Author)
+	s.Equal(model.ToAPIString("abcdef"), v.Revision)
+	s.Equal([]model.APIParameter{{Key: model.ToAPIString("release"), Value: model.ToAPIString("4.2.0")}}, v.Parameters)
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/host"
-	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
@@ -146,7 +145,7 @@ func computeScheduledTasksDuration(scheduledDistroTasksData *ScheduledDistroTask
 
 // computeRunningTasksDuration returns the estimated time to completion of all
 // currently running tasks for a given distro given its hosts
-func computeRunningTasksDuration(existingDistroHosts []host.Host) (runningTasksDuration float64, err error) {
+func computeRunningTasksDuration(existingDistroHosts []host.Host, estimateRunningTasks runningTaskEstimator) (runningTasksDuration float64, err error) {
 	runningTaskIds := []string{}
 
 	for _, existingDistroHost := range existingDistroHosts {
@@ -161,15 +160,15 @@ func computeRunningTasksDuration(existingDistroHosts []host.Host) (runningTasksD
 		return
 	}
 
-	runningTasksMap := make(map[string]task.Task)
-	runningTasks, err := task.Find(task.ByIds(runningTaskIds))
+	runningTasksMap := make(map[string]runningTaskEstimate)
+	runningTasks, err := estimateRunningTasks(runningTaskIds)
 	if err != nil {
 		return runningTasksDuration, err
 	}
 
-	// build a map of task id => task
+	// build a map of task id => estimate
 	for _, runningTask := range runningTasks {
-		runningTasksMap[runningTask.Id] = runningTask
+		runningTasksMap[runningTask.id] = runningTask
 	}
 
 	// compute the total time to completion for running tasks
@@ -179,14 +178,12 @@ func computeRunningTasksDuration(existingDistroHosts []host.Host) (runningTasksD
 			return runningTasksDuration, errors.Errorf(
 				"Unable to find running task with _id %v", runningTaskId)
 		}
-		expectedDuration := runningTask.FetchExpectedDuration()
-		elapsedTime := time.Since(runningTask.StartTime)
-		if elapsedTime > expectedDuration {
+		if runningTask.elapsed > runningTask.expected {
 			// probably an outlier; or an unknown data point
 			continue
 		}
 
-		runningTasksDuration += (expectedDuration - elapsedTime).Seconds()
+		runningTasksDuration += (runningTask.expected - runningTask.elapsed).Seconds()
 	}
 	return
 }
@@ -391,7 +388,7 @@ func durationNumNewHostsForDistro(ctx context.Context, hostAllocatorData *HostAl
 
 	// determine the total remaining running time of all
 	// tasks currently running on the hosts for this distro
-	runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts)
+	runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts, hostAllocatorData.runningTaskEstimator())
 
 	if err != nil {
 		return numNewHosts, err
@@ -512,7 +512,7 @@ func TestComputeRunningTasksDuration(t *testing.T) {
 				{Id: hostIds[4], RunningTask: runningTaskIds[2]},
 			}
 
-			runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts)
+			runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts, estimateRunningTasksFromDB)
 
 			So(err, ShouldBeNil)
 
@@ -550,7 +550,7 @@ func TestComputeRunningTasksDuration(t *testing.T) {
 				So(runningTask.Insert(), ShouldBeNil)
 			}
 
-			runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts)
+			runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts, estimateRunningTasksFromDB)
 			So(err, ShouldBeNil)
 			// the running task duration should be a total of the remaining
 			// duration of running tasks - 6 in this case
@@ -582,7 +582,7 @@ func TestComputeRunningTasksDuration(t *testing.T) {
 				So(runningTask.Insert(), ShouldBeNil)
 			}
 
-			runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts)
+			runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts, estimateRunningTasksFromDB)
 			So(err, ShouldBeNil)
 			// only task 1's duration is known, so the others should use the default.
 			expectedDur := remainingDurationTwoSecs + float64((2*10*time.Minute)/time.Second)
@@ -610,7 +610,7 @@ func TestComputeRunningTasksDuration(t *testing.T) {
 				So(runningTask.Insert(), ShouldBeNil)
 			}
 
-			runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts)
+			runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts, estimateRunningTasksFromDB)
 			So(err, ShouldBeNil)
 			// task 2's duration should be ignored
 			// due to scheduling variables, we allow a 5 second tolerance
@@ -629,7 +629,7 @@ func TestComputeRunningTasksDuration(t *testing.T) {
 				{Id: hostIds[3]},
 			}
 
-			runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts)
+			runningTasksDuration, err := computeRunningTasksDuration(existingDistroHosts, estimateRunningTasksFromDB)
 			So(err, ShouldBeNil)
 			// the running task duration should be a total of the remaining
 			// duration of running tasks
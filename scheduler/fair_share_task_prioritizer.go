@@ -0,0 +1,168 @@
+package scheduler
+
+import (
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	// fairShareUsageWindow is how far back the fair share prioritizer looks
+	// when computing the host time each project has recently consumed.
+	fairShareUsageWindow = 24 * time.Hour
+
+	// fairShareDefaultTaskDuration is charged to a project for a queued task
+	// that has no expected duration yet.
+	fairShareDefaultTaskDuration = 10 * time.Minute
+)
+
+// FairShareTaskPrioritizer orders a distro's queue so that projects that
+// have recently consumed less host time on the distro are served before
+// projects that have consumed more. Within a project, tasks keep the
+// relative order assigned by the comparator-based prioritizer, and tasks
+// with a priority above evergreen.MaxTaskPriority stay at the front.
+type FairShareTaskPrioritizer struct {
+	runtimeID string
+	window    time.Duration
+	base      TaskPrioritizer
+	findUsage func(string, time.Time) ([]task.ProjectUsage, error)
+
+	shares map[string]model.ProjectShare
+}
+
+// NewFairShareTaskPrioritizer returns a fair share prioritizer that
+// considers the host time consumed during the last day.
+func NewFairShareTaskPrioritizer(runtimeID string) *FairShareTaskPrioritizer {
+	return &FairShareTaskPrioritizer{
+		runtimeID: runtimeID,
+		window:    fairShareUsageWindow,
+		base:      &CmpBasedTaskPrioritizer{runtimeID: runtimeID},
+		findUsage: task.FindProjectUsageForDistro,
+	}
+}
+
+// ProjectShares returns the per-project shares computed by the most recent
+// call to PrioritizeTasks, ordered by project.
+func (p *FairShareTaskPrioritizer) ProjectShares() []model.ProjectShare {
+	shares := make([]model.ProjectShare, 0, len(p.shares))
+	for _, share := range p.shares {
+		shares = append(shares, share)
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		return shares[i].Project < shares[j].Project
+	})
+	return shares
+}
+
+func (p *FairShareTaskPrioritizer) PrioritizeTasks(distroId string, tasks []task.Task, versions map[string]version.Version) ([]task.Task, error) {
+	ordered, err := p.base.PrioritizeTasks(distroId, tasks, versions)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem running base prioritizer")
+	}
+
+	usage, err := p.findUsage(distroId, time.Now().Add(-p.window))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding project usage for distro '%s'", distroId)
+	}
+
+	consumed := make(map[string]time.Duration, len(usage))
+	for _, u := range usage {
+		consumed[u.Project] += u.SumTimeTaken
+	}
+
+	ordered, p.shares = fairShareOrder(ordered, consumed)
+
+	grip.Info(message.Fields{
+		"message":   "computed fair share project usage",
+		"distro":    distroId,
+		"runner":    RunnerName,
+		"instance":  p.runtimeID,
+		"operation": "prioritize tasks",
+		"window":    p.window.String(),
+		"shares":    p.shares,
+	})
+
+	return ordered, nil
+}
+
+// fairShareOrder interleaves the prioritized tasks by project, repeatedly
+// taking the next task of the project that has been charged the least host
+// time so far. Each project starts with the time it already consumed, and
+// is charged the expected duration of every task it is given a slot for.
+func fairShareOrder(prioritized []task.Task, consumed map[string]time.Duration) ([]task.Task, map[string]model.ProjectShare) {
+	ordered := make([]task.Task, 0, len(prioritized))
+	queues := map[string][]task.Task{}
+	projects := []string{}
+
+	for _, t := range prioritized {
+		if t.Priority > evergreen.MaxTaskPriority {
+			ordered = append(ordered, t)
+			continue
+		}
+		if _, ok := queues[t.Project]; !ok {
+			projects = append(projects, t.Project)
+		}
+		queues[t.Project] = append(queues[t.Project], t)
+	}
+
+	charged := make(map[string]time.Duration, len(projects))
+	var total time.Duration
+	for project, used := range consumed {
+		charged[project] = used
+		total += used
+	}
+
+	shares := make(map[string]model.ProjectShare, len(charged))
+	for project, used := range charged {
+		share := model.ProjectShare{
+			Project:             project,
+			ConsumedHostSeconds: used.Seconds(),
+		}
+		if total > 0 {
+			share.Share = float64(used) / float64(total)
+		}
+		shares[project] = share
+	}
+
+	for len(projects) > 0 {
+		// projects is kept in order of first appearance in the
+		// prioritized queue, so ties go to the project that the base
+		// prioritizer ranked higher.
+		idx := 0
+		for i := range projects {
+			if charged[projects[i]] < charged[projects[idx]] {
+				idx = i
+			}
+		}
+
+		project := projects[idx]
+		next := queues[project][0]
+		queues[project] = queues[project][1:]
+		if len(queues[project]) == 0 {
+			projects = append(projects[:idx], projects[idx+1:]...)
+		}
+
+		duration := next.ExpectedDuration
+		if duration <= 0 {
+			duration = fairShareDefaultTaskDuration
+		}
+		charged[project] += duration
+
+		share := shares[project]
+		share.Project = project
+		share.QueuedHostSeconds += duration.Seconds()
+		share.QueuedTasks++
+		shares[project] = share
+
+		ordered = append(ordered, next)
+	}
+
+	return ordered, shares
+}
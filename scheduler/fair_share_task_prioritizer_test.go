@@ -0,0 +1,131 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type passthroughPrioritizer struct{}
+
+func (p *passthroughPrioritizer) PrioritizeTasks(_ string, tasks []task.Task, _ map[string]version.Version) ([]task.Task, error) {
+	return tasks, nil
+}
+
+func taskIDs(tasks []task.Task) []string {
+	ids := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.Id)
+	}
+	return ids
+}
+
+func TestFairShareOrder(t *testing.T) {
+	assert := assert.New(t)
+
+	tasks := []task.Task{
+		{Id: "noisy1", Project: "noisy", ExpectedDuration: time.Hour},
+		{Id: "noisy2", Project: "noisy", ExpectedDuration: time.Hour},
+		{Id: "noisy3", Project: "noisy", ExpectedDuration: time.Hour},
+		{Id: "quiet1", Project: "quiet", ExpectedDuration: time.Hour},
+		{Id: "quiet2", Project: "quiet", ExpectedDuration: time.Hour},
+	}
+
+	// without any recorded usage, projects alternate starting with the
+	// project the base prioritizer ranked first
+	ordered, shares := fairShareOrder(tasks, map[string]time.Duration{})
+	assert.Equal([]string{"noisy1", "quiet1", "noisy2", "quiet2", "noisy3"}, taskIDs(ordered))
+	assert.Equal(3, shares["noisy"].QueuedTasks)
+	assert.Equal(2, shares["quiet"].QueuedTasks)
+	assert.Zero(shares["noisy"].Share)
+
+	// a project that consumed more host time yields to the others
+	ordered, shares = fairShareOrder(tasks, map[string]time.Duration{
+		"noisy": 90 * time.Minute,
+		"quiet": 10 * time.Minute,
+	})
+	assert.Equal([]string{"quiet1", "quiet2", "noisy1", "noisy2", "noisy3"}, taskIDs(ordered))
+	assert.InDelta(0.9, shares["noisy"].Share, 0.0001)
+	assert.InDelta(0.1, shares["quiet"].Share, 0.0001)
+	assert.Equal(5400.0, shares["noisy"].ConsumedHostSeconds)
+	assert.Equal(3*time.Hour.Seconds(), shares["noisy"].QueuedHostSeconds)
+
+	// tasks without an expected duration are charged the default
+	ordered, _ = fairShareOrder([]task.Task{
+		{Id: "a1", Project: "a"},
+		{Id: "a2", Project: "a"},
+		{Id: "b1", Project: "b", ExpectedDuration: time.Minute},
+		{Id: "b2", Project: "b", ExpectedDuration: time.Minute},
+	}, nil)
+	assert.Equal([]string{"a1", "b1", "b2", "a2"}, taskIDs(ordered))
+
+	// high priority tasks stay at the front regardless of usage
+	ordered, _ = fairShareOrder([]task.Task{
+		{Id: "urgent", Project: "noisy", Priority: evergreen.MaxTaskPriority + 1},
+		{Id: "noisy1", Project: "noisy"},
+		{Id: "quiet1", Project: "quiet"},
+	}, map[string]time.Duration{"noisy": time.Hour})
+	assert.Equal([]string{"urgent", "quiet1", "noisy1"}, taskIDs(ordered))
+}
+
+func TestFairShareTaskPrioritizer(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var queriedDistro string
+	prioritizer := &FairShareTaskPrioritizer{
+		runtimeID: "test",
+		window:    time.Hour,
+		base:      &passthroughPrioritizer{},
+		findUsage: func(distroID string, _ time.Time) ([]task.ProjectUsage, error) {
+			queriedDistro = distroID
+			return []task.ProjectUsage{
+				{Project: "noisy", SumTimeTaken: time.Hour, NumTasks: 4},
+			}, nil
+		},
+	}
+
+	ordered, err := prioritizer.PrioritizeTasks("d1", []task.Task{
+		{Id: "noisy1", Project: "noisy"},
+		{Id: "quiet1", Project: "quiet"},
+	}, nil)
+	require.NoError(err)
+	assert.Equal("d1", queriedDistro)
+	assert.Equal([]string{"quiet1", "noisy1"}, taskIDs(ordered))
+
+	shares := prioritizer.ProjectShares()
+	require.Len(shares, 2)
+	assert.Equal("noisy", shares[0].Project)
+	assert.Equal(1.0, shares[0].Share)
+	assert.Equal("quiet", shares[1].Project)
+	assert.Equal(1, shares[1].QueuedTasks)
+
+	prioritizer.findUsage = func(string, time.Time) ([]task.ProjectUsage, error) {
+		return nil, errors.New("usage unavailable")
+	}
+	_, err = prioritizer.PrioritizeTasks("d1", []task.Task{{Id: "t1"}}, nil)
+	assert.Error(err)
+}
+
+func TestGetTaskPrioritizer(t *testing.T) {
+	assert := assert.New(t)
+
+	for name, expected := range map[string]TaskPrioritizer{
+		evergreen.TaskPrioritizerFairShare:  &FairShareTaskPrioritizer{},
+		evergreen.TaskPrioritizerComparator: &CmpBasedTaskPrioritizer{},
+		"":                                  &CmpBasedTaskPrioritizer{},
+	} {
+		prioritizer, err := GetTaskPrioritizer(name, "id")
+		assert.NoError(err)
+		assert.IsType(expected, prioritizer, name)
+	}
+
+	_, err := GetTaskPrioritizer("fifo", "id")
+	assert.Error(err)
+}
@@ -2,20 +2,25 @@ package scheduler
 
 import (
 	"context"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
 )
 
 // HostAllocator is responsible for determining how many new hosts should be spun up.
 // Parameters:
-//  taskQueueItems: a map of distro name -> task queue items for that distro (a TaskQueue object)
-//  distros: a map of distro name -> information on that distro (a model.Distro object)
-//  existingDistroHosts: a map of distro name -> currently running hosts on that distro
-//  projectTaskDurations: the expected duration of tasks by project and variant
-//  taskRunDistros: a map of task id -> distros the task is allowed to run on
+//
+//	taskQueueItems: a map of distro name -> task queue items for that distro (a TaskQueue object)
+//	distros: a map of distro name -> information on that distro (a model.Distro object)
+//	existingDistroHosts: a map of distro name -> currently running hosts on that distro
+//	projectTaskDurations: the expected duration of tasks by project and variant
+//	taskRunDistros: a map of task id -> distros the task is allowed to run on
+//
 // Returns a map of distro name -> how many hosts need to be spun up for that distro.
 type HostAllocator func(context.Context, HostAllocatorData) (int, error)
 
@@ -28,6 +33,57 @@ type HostAllocatorData struct {
 	freeHostFraction float64
 	usesContainers   bool
 	containerPool    *evergreen.ContainerPool
+
+	// estimateRunningTasks estimates how long the tasks running on the
+	// existing hosts have left, defaulting to the database.
+	estimateRunningTasks runningTaskEstimator
+
+	// findArrivalStats looks up the distro's history of task arrivals for
+	// the predictive allocator, defaulting to the database.
+	findArrivalStats arrivalStatsFinder
+	// recordForecast, if set, is passed the demand that the predictive
+	// allocator forecast.
+	recordForecast func(event.HostForecast)
+	// now and forecastLookahead, if set, replace the wall clock and the
+	// default lookahead of the predictive allocator.
+	now               time.Time
+	forecastLookahead time.Duration
+}
+
+// runningTaskEstimate is how long a running task is expected to take, and
+// how long it has run.
+type runningTaskEstimate struct {
+	id       string
+	expected time.Duration
+	elapsed  time.Duration
+}
+
+// runningTaskEstimator returns estimates for the running tasks with the given
+// ids.
+type runningTaskEstimator func([]string) ([]runningTaskEstimate, error)
+
+func estimateRunningTasksFromDB(ids []string) ([]runningTaskEstimate, error) {
+	tasks, err := task.Find(task.ByIds(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	estimates := make([]runningTaskEstimate, 0, len(tasks))
+	for _, t := range tasks {
+		estimates = append(estimates, runningTaskEstimate{
+			id:       t.Id,
+			expected: t.FetchExpectedDuration(),
+			elapsed:  time.Since(t.StartTime),
+		})
+	}
+	return estimates, nil
+}
+
+func (d *HostAllocatorData) runningTaskEstimator() runningTaskEstimator {
+	if d.estimateRunningTasks != nil {
+		return d.estimateRunningTasks
+	}
+	return estimateRunningTasksFromDB
 }
 
 func GetHostAllocator(name string) HostAllocator {
@@ -38,6 +94,8 @@ func GetHostAllocator(name string) HostAllocator {
 		return DurationBasedHostAllocator
 	case "utilization":
 		return UtilizationBasedHostAllocator
+	case "predictive":
+		return PredictiveHostAllocator
 	default:
 		return UtilizationBasedHostAllocator
 	}
@@ -0,0 +1,199 @@
+package scheduler
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	// predictiveHistoryWeeks is how many weeks of task arrivals the
+	// predictive allocator averages when forecasting demand.
+	predictiveHistoryWeeks = 4
+	// typicalHostStartup is about how long a new host takes to be ready
+	// to run tasks.
+	typicalHostStartup = 5 * time.Minute
+	// predictiveLookahead is how far ahead the predictive allocator
+	// forecasts demand. A host that is ready and idle for idleHostTimeout
+	// is terminated, so a host spawned for tasks forecast any further
+	// ahead would be gone before they arrive.
+	predictiveLookahead = typicalHostStartup + idleHostTimeout
+	// arrivalStatsTTL is how long the arrival history of a distro is cached
+	// between scheduler runs.
+	arrivalStatsTTL = time.Hour
+)
+
+// arrivalStatsFinder returns the tasks activated on a distro since the given
+// time, by hour of the week.
+type arrivalStatsFinder func(string, time.Time) ([]task.ArrivalStats, error)
+
+type cachedArrivalStats struct {
+	stats       []task.ArrivalStats
+	collectedAt time.Time
+}
+
+var (
+	arrivalStatsCache      = map[string]cachedArrivalStats{}
+	arrivalStatsCacheMutex sync.Mutex
+)
+
+// findArrivalStatsCached returns the arrival history of the distro, which
+// changes slowly, from the database at most once per arrivalStatsTTL.
+func findArrivalStatsCached(distroID string, since time.Time) ([]task.ArrivalStats, error) {
+	arrivalStatsCacheMutex.Lock()
+	defer arrivalStatsCacheMutex.Unlock()
+
+	if cached, ok := arrivalStatsCache[distroID]; ok && time.Since(cached.collectedAt) < arrivalStatsTTL {
+		return cached.stats, nil
+	}
+
+	stats, err := task.FindArrivalStatsForDistro(distroID, since)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	arrivalStatsCache[distroID] = cachedArrivalStats{stats: stats, collectedAt: time.Now()}
+
+	return stats, nil
+}
+
+func (d *HostAllocatorData) arrivalStatsFinder() arrivalStatsFinder {
+	if d.findArrivalStats != nil {
+		return d.findArrivalStats
+	}
+	return findArrivalStatsCached
+}
+
+func (d *HostAllocatorData) currentTime() time.Time {
+	if !d.now.IsZero() {
+		return d.now
+	}
+	return time.Now()
+}
+
+func (d *HostAllocatorData) lookahead() time.Duration {
+	if d.forecastLookahead > 0 {
+		return d.forecastLookahead
+	}
+	return predictiveLookahead
+}
+
+// PredictiveHostAllocator spawns the hosts that the utilization based
+// allocator calls for, and also pre-spawns hosts ahead of bursts of tasks
+// that historically arrive at the same hour of the same day of the week, so
+// that the hosts are ready when the tasks arrive.
+func PredictiveHostAllocator(ctx context.Context, hostAllocatorData HostAllocatorData) (int, error) {
+	reactive, err := UtilizationBasedHostAllocator(ctx, hostAllocatorData)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	distro := hostAllocatorData.distro
+	if !distro.IsEphemeral() || hostAllocatorData.usesContainers {
+		return reactive, nil
+	}
+
+	now := hostAllocatorData.currentTime()
+	stats, err := hostAllocatorData.arrivalStatsFinder()(distro.Id, now.Add(-predictiveHistoryWeeks*7*24*time.Hour))
+	if err != nil {
+		// fall back to the queue rather than stop spawning hosts
+		grip.Warning(message.WrapError(err, message.Fields{
+			"message":   "problem finding task arrival history, not forecasting demand",
+			"runner":    RunnerName,
+			"distro":    distro.Id,
+			"operation": "predictive host allocation",
+		}))
+		return reactive, nil
+	}
+
+	forecast := forecastHostDemand(stats, now, hostAllocatorData.lookahead(), predictiveHistoryWeeks)
+	forecast.ReactiveHosts = reactive
+	forecast.NewHosts = predictiveNumNewHosts(forecast.PredictedHosts, reactive, len(hostAllocatorData.existingHosts), distro.PoolSize)
+
+	if hostAllocatorData.recordForecast != nil {
+		hostAllocatorData.recordForecast(forecast)
+	}
+
+	grip.Info(message.Fields{
+		"message":            "forecast host demand",
+		"runner":             RunnerName,
+		"distro":             distro.Id,
+		"operation":          "predictive host allocation",
+		"lookahead":          forecast.Lookahead.String(),
+		"predicted_tasks":    forecast.PredictedTasks,
+		"predicted_duration": forecast.PredictedDuration.String(),
+		"predicted_hosts":    forecast.PredictedHosts,
+		"reactive_hosts":     forecast.ReactiveHosts,
+		"existing_hosts":     len(hostAllocatorData.existingHosts),
+		"new_hosts":          forecast.NewHosts,
+	})
+
+	return forecast.NewHosts, nil
+}
+
+// forecastHostDemand predicts the tasks that will arrive during the
+// lookahead after now, as the average over the weeks of history of the tasks
+// that arrived during the same hours of the week, and the number of hosts
+// needed to run them within the lookahead.
+func forecastHostDemand(stats []task.ArrivalStats, now time.Time, lookahead time.Duration, weeks int) event.HostForecast {
+	forecast := event.HostForecast{Lookahead: lookahead}
+	if weeks <= 0 || lookahead <= 0 {
+		return forecast
+	}
+
+	type hourOfWeek struct {
+		day  time.Weekday
+		hour int
+	}
+	byHour := make(map[hourOfWeek]task.ArrivalStats, len(stats))
+	for _, s := range stats {
+		byHour[hourOfWeek{day: s.DayOfWeek, hour: s.Hour}] = s
+	}
+
+	// weight each hour that the lookahead overlaps by how much of it
+	// falls within the lookahead
+	var predictedDuration float64
+	start := now.UTC()
+	end := start.Add(lookahead)
+	for hourStart := start.Truncate(time.Hour); hourStart.Before(end); hourStart = hourStart.Add(time.Hour) {
+		overlapStart, overlapEnd := hourStart, hourStart.Add(time.Hour)
+		if start.After(overlapStart) {
+			overlapStart = start
+		}
+		if end.Before(overlapEnd) {
+			overlapEnd = end
+		}
+		fraction := overlapEnd.Sub(overlapStart).Hours() / float64(weeks)
+
+		s := byHour[hourOfWeek{day: hourStart.Weekday(), hour: hourStart.Hour()}]
+		forecast.PredictedTasks += float64(s.NumTasks) * fraction
+		predictedDuration += float64(s.SumExpectedDuration) * fraction
+	}
+
+	forecast.PredictedDuration = time.Duration(predictedDuration)
+	forecast.PredictedHosts = int(math.Ceil(predictedDuration / float64(lookahead)))
+
+	return forecast
+}
+
+// predictiveNumNewHosts returns the number of hosts to spawn so that the
+// distro has enough hosts for both the queue and the forecast, without
+// exceeding the distro's pool size.
+func predictiveNumNewHosts(predictedHosts, reactiveHosts, existingHosts, poolSize int) int {
+	target := existingHosts + reactiveHosts
+	if predictedHosts > target {
+		target = util.Min(predictedHosts, poolSize)
+	}
+
+	if target-existingHosts < reactiveHosts {
+		return reactiveHosts
+	}
+	return target - existingHosts
+}
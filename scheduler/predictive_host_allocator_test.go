@@ -0,0 +1,129 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForecastHostDemand(t *testing.T) {
+	assert := assert.New(t)
+
+	// a Monday
+	now := time.Date(2019, time.January, 7, 8, 45, 0, 0, time.UTC)
+	stats := []task.ArrivalStats{
+		{DayOfWeek: time.Monday, Hour: 8, NumTasks: 40, SumExpectedDuration: 40 * time.Hour},
+		{DayOfWeek: time.Monday, Hour: 9, NumTasks: 400, SumExpectedDuration: 200 * time.Hour},
+		{DayOfWeek: time.Tuesday, Hour: 9, NumTasks: 1000, SumExpectedDuration: 1000 * time.Hour},
+	}
+
+	// the lookahead covers the last quarter of the 8:00 hour and the first
+	// quarter of the 9:00 hour, averaged over four weeks
+	forecast := forecastHostDemand(stats, now, 30*time.Minute, 4)
+	assert.Equal(30*time.Minute, forecast.Lookahead)
+	assert.InDelta(2.5+25, forecast.PredictedTasks, 0.001)
+	assert.Equal(2*time.Hour+30*time.Minute+12*time.Hour+30*time.Minute, forecast.PredictedDuration)
+	assert.Equal(30, forecast.PredictedHosts)
+
+	// nothing historically arrives on Sunday
+	forecast = forecastHostDemand(stats, now.Add(-24*time.Hour), 30*time.Minute, 4)
+	assert.Zero(forecast.PredictedTasks)
+	assert.Zero(forecast.PredictedHosts)
+
+	assert.Zero(forecastHostDemand(stats, now, 30*time.Minute, 0).PredictedHosts)
+}
+
+func TestPredictiveNumNewHosts(t *testing.T) {
+	assert := assert.New(t)
+
+	// the forecast calls for more hosts than the queue
+	assert.Equal(6, predictiveNumNewHosts(10, 2, 4, 20))
+	// within the pool size
+	assert.Equal(4, predictiveNumNewHosts(10, 2, 4, 8))
+	// the queue calls for more hosts than the forecast
+	assert.Equal(3, predictiveNumNewHosts(5, 3, 4, 20))
+	// there are already enough hosts for the forecast
+	assert.Equal(0, predictiveNumNewHosts(5, 0, 10, 20))
+	// the pool is already full
+	assert.Equal(0, predictiveNumNewHosts(10, 0, 8, 8))
+}
+
+func TestPredictiveHostAllocator(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := distro.Distro{Id: "d", Provider: evergreen.ProviderNameMock, PoolSize: 50}
+	// the same demand arrives during every hour of the week
+	stats := []task.ArrivalStats{}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		for hour := 0; hour < 24; hour++ {
+			stats = append(stats, task.ArrivalStats{DayOfWeek: day, Hour: hour, NumTasks: 80, SumExpectedDuration: 40 * time.Hour})
+		}
+	}
+
+	var recorded *event.HostForecast
+	data := HostAllocatorData{
+		distro:           d,
+		existingHosts:    []host.Host{{Id: "h1"}, {Id: "h2"}},
+		findArrivalStats: func(string, time.Time) ([]task.ArrivalStats, error) { return stats, nil },
+		recordForecast:   func(f event.HostForecast) { recorded = &f },
+	}
+
+	// with an empty queue, hosts are spawned for the forecast
+	newHosts, err := PredictiveHostAllocator(ctx, data)
+	require.NoError(err)
+	require.NotNil(recorded)
+	assert.Equal(10, recorded.PredictedHosts)
+	assert.Equal(0, recorded.ReactiveHosts)
+	assert.Equal(8, recorded.NewHosts)
+	assert.Equal(8, newHosts)
+	// hosts spawned for the forecast must not be terminated as idle
+	// before the forecast tasks arrive
+	assert.Equal(predictiveLookahead, recorded.Lookahead)
+	assert.True(recorded.Lookahead <= typicalHostStartup+idleHostTimeout)
+
+	// the clock and lookahead can be replaced
+	var since time.Time
+	now := time.Date(2019, time.January, 7, 8, 0, 0, 0, time.UTC)
+	data.now = now
+	data.forecastLookahead = 20 * time.Minute
+	data.findArrivalStats = func(_ string, t time.Time) ([]task.ArrivalStats, error) {
+		since = t
+		return stats, nil
+	}
+	_, err = PredictiveHostAllocator(ctx, data)
+	require.NoError(err)
+	assert.Equal(now.Add(-predictiveHistoryWeeks*7*24*time.Hour), since)
+	assert.Equal(20*time.Minute, recorded.Lookahead)
+	data.now = time.Time{}
+	data.forecastLookahead = 0
+
+	// the history cannot be found, so only the queue is considered
+	recorded = nil
+	data.findArrivalStats = func(string, time.Time) ([]task.ArrivalStats, error) { return nil, errors.New("no history") }
+	data.taskQueueItems = []model.TaskQueueItem{{Id: "t1", ExpectedDuration: time.Minute}}
+	newHosts, err = PredictiveHostAllocator(ctx, data)
+	require.NoError(err)
+	assert.Nil(recorded)
+	assert.Equal(0, newHosts)
+
+	// hosts are not spawned ahead of demand on static distros
+	data.distro.Provider = evergreen.ProviderNameStatic
+	data.findArrivalStats = func(string, time.Time) ([]task.ArrivalStats, error) { return stats, nil }
+	newHosts, err = PredictiveHostAllocator(ctx, data)
+	require.NoError(err)
+	assert.Nil(recorded)
+	assert.Equal(0, newHosts)
+}
@@ -82,7 +82,12 @@ func (s *distroSchedueler) scheduleDistro(distroId string, runnableTasksForDistr
 		"operation": "saving task queue for distro",
 	})
 
-	queuedTasks, err := s.PersistTaskQueue(distroId, prioritizedTasks)
+	var shares []model.ProjectShare
+	if sharer, ok := s.TaskPrioritizer.(projectSharer); ok {
+		shares = sharer.ProjectShares()
+	}
+
+	queuedTasks, err := s.PersistTaskQueue(distroId, prioritizedTasks, shares)
 	if err != nil {
 		res.err = errors.Wrapf(err, "Error processing distro %s saving task queue", distroId)
 		return res
@@ -158,7 +163,7 @@ func spawnHosts(ctx context.Context, d distro.Distro, newHostsNeeded int, pool *
 
 	// if distro is container distro, check if there are enough parent hosts to
 	// support new containers
-	if pool != nil {
+	if pool != nil && !pool.UsesNamespace() {
 		// find all running parents with the specified container pool
 		currentParents, err := host.FindAllRunningParentsByContainerPool(pool.Id)
 		if err != nil {
@@ -217,8 +222,22 @@ func spawnHosts(ctx context.Context, d distro.Distro, newHostsNeeded int, pool *
 		newHostsNeeded = containerCapacity(len(currentParents), len(existingContainers), newHostsNeeded, pool.MaxContainers)
 	}
 
-	// create intent documents for container hosts
-	if d.ContainerPool != "" {
+	// create intent documents for containers scheduled into a namespace,
+	// which have no parent hosts
+	if pool != nil && pool.UsesNamespace() {
+		numContainers, err := host.CountContainersByContainerPool(pool.Id)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not count containers in pool")
+		}
+		newHostsNeeded = containerCapacity(1, numContainers, newHostsNeeded, pool.MaxContainers)
+		for i := 0; i < newHostsNeeded; i++ {
+			hostOptions := cloud.HostOptions{
+				UserName:              evergreen.User,
+				ContainerPoolSettings: pool,
+			}
+			hostsSpawned = append(hostsSpawned, *cloud.NewIntent(d, d.GenerateName(), d.Provider, hostOptions))
+		}
+	} else if d.ContainerPool != "" { // create intent documents for container hosts
 		containerIntents, err := generateContainerHostIntents(d, newHostsNeeded)
 		if err != nil {
 			return nil, errors.Wrap(err, "error generating container intent hosts")
@@ -0,0 +1,787 @@
+package scheduler
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// This file contains an offline simulator of the scheduler. It replays a
+// recorded window of a distro's tasks through a host allocator and a task
+// prioritizer in virtual time, so that scheduler configurations can be
+// compared before they are deployed.
+
+const (
+	// defaultSimulationInterval is how often the simulated scheduler runs,
+	// unless the options say otherwise.
+	defaultSimulationInterval = time.Minute
+	// defaultSimulationHostStartup is how long a simulated host takes to
+	// become ready to run tasks, unless the options say otherwise.
+	defaultSimulationHostStartup = 5 * time.Minute
+	// simulationDrainLimit bounds how long a simulation runs after the
+	// last task arrived, so that configurations that never run some tasks
+	// still finish.
+	simulationDrainLimit = 7 * 24 * time.Hour
+
+	simulationRuntimeID = "simulator"
+)
+
+// SimulatedTask is a task recorded for replay by the scheduler simulator.
+type SimulatedTask struct {
+	Id                  string   `json:"id" yaml:"id"`
+	DisplayName         string   `json:"display_name" yaml:"display_name"`
+	Project             string   `json:"project" yaml:"project"`
+	BuildVariant        string   `json:"build_variant" yaml:"build_variant"`
+	BuildId             string   `json:"build_id" yaml:"build_id"`
+	Version             string   `json:"version" yaml:"version"`
+	Revision            string   `json:"revision" yaml:"revision"`
+	RevisionOrderNumber int      `json:"order" yaml:"order"`
+	Requester           string   `json:"requester" yaml:"requester"`
+	Priority            int64    `json:"priority" yaml:"priority"`
+	TaskGroup           string   `json:"task_group,omitempty" yaml:"task_group,omitempty"`
+	TaskGroupMaxHosts   int      `json:"task_group_max_hosts,omitempty" yaml:"task_group_max_hosts,omitempty"`
+	GenerateTask        bool     `json:"generate_task,omitempty" yaml:"generate_task,omitempty"`
+	DependsOn           []string `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+	Status              string   `json:"status" yaml:"status"`
+
+	CreateTime time.Time `json:"create_time" yaml:"create_time"`
+	// ActivatedTime is when the task became ready to be scheduled.
+	ActivatedTime time.Time `json:"activated_time" yaml:"activated_time"`
+	// Duration is how long the task ran.
+	Duration time.Duration `json:"duration" yaml:"duration"`
+	// ExpectedDuration is the scheduler's estimate of how long the task
+	// would run. The task's actual duration is used if it is unset.
+	ExpectedDuration time.Duration `json:"expected_duration,omitempty" yaml:"expected_duration,omitempty"`
+}
+
+// SimulationInput is a recorded window of a distro's tasks and hosts.
+type SimulationInput struct {
+	Distro distro.Distro `json:"distro" yaml:"distro"`
+	Start  time.Time     `json:"start" yaml:"start"`
+	End    time.Time     `json:"end" yaml:"end"`
+	// InitialHosts is the number of the distro's hosts that were up at
+	// the start of the window.
+	InitialHosts int             `json:"initial_hosts" yaml:"initial_hosts"`
+	Tasks        []SimulatedTask `json:"tasks" yaml:"tasks"`
+}
+
+// SimulationOptions configure a run of the scheduler simulator.
+type SimulationOptions struct {
+	// HostAllocator and TaskPrioritizer name the allocator and
+	// prioritizer to simulate, as accepted by GetHostAllocator and
+	// GetTaskPrioritizer.
+	HostAllocator    string
+	TaskPrioritizer  string
+	FreeHostFraction float64
+	// Interval is how often the scheduler runs.
+	Interval time.Duration
+	// HostStartup is how long a new host takes to become ready.
+	HostStartup time.Duration
+	// HostIdleTimeout is how long a host may be idle before it is
+	// terminated.
+	HostIdleTimeout time.Duration
+}
+
+// SimulationResult summarizes a run of the scheduler simulator.
+type SimulationResult struct {
+	Distro          string `json:"distro"`
+	HostAllocator   string `json:"host_allocator"`
+	TaskPrioritizer string `json:"task_prioritizer"`
+	Tasks           int    `json:"tasks"`
+	UnfinishedTasks int    `json:"unfinished_tasks"`
+
+	// Makespan is the time from the first task's arrival until the last
+	// task finished.
+	Makespan time.Duration `json:"makespan"`
+	// The wait of a task is the time from its arrival until it started.
+	MeanWait time.Duration `json:"mean_wait"`
+	WaitP50  time.Duration `json:"wait_p50"`
+	WaitP90  time.Duration `json:"wait_p90"`
+	WaitP99  time.Duration `json:"wait_p99"`
+	MaxWait  time.Duration `json:"max_wait"`
+
+	HostsSpawned  int     `json:"hosts_spawned"`
+	PeakHosts     int     `json:"peak_hosts"`
+	HostHours     float64 `json:"host_hours"`
+	IdleHostHours float64 `json:"idle_host_hours"`
+}
+
+// LoadSimulationInput records the finished tasks that were activated on a
+// distro between the given times, and the number of the distro's hosts that
+// were up at the start, for replay by SimulateScheduler.
+func LoadSimulationInput(distroID string, start, end time.Time) (*SimulationInput, error) {
+	d, err := distro.FindOne(distro.ById(distroID))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding distro '%s'", distroID)
+	}
+
+	tasks, err := task.Find(db.Query(bson.M{
+		task.DistroIdKey:      distroID,
+		task.ActivatedTimeKey: bson.M{"$gte": start, "$lt": end},
+		task.StatusKey:        bson.M{"$in": task.CompletedStatuses},
+	}))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding tasks for distro '%s'", distroID)
+	}
+
+	hosts, err := host.Find(host.ByDynamicWithinTime(start, start))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding hosts for distro '%s'", distroID)
+	}
+
+	input := &SimulationInput{
+		Distro: d,
+		Start:  start,
+		End:    end,
+		Tasks:  make([]SimulatedTask, 0, len(tasks)),
+	}
+	for _, h := range hosts {
+		if h.Distro.Id == distroID {
+			input.InitialHosts++
+		}
+	}
+	for _, t := range tasks {
+		simulated := SimulatedTask{
+			Id:                  t.Id,
+			DisplayName:         t.DisplayName,
+			Project:             t.Project,
+			BuildVariant:        t.BuildVariant,
+			BuildId:             t.BuildId,
+			Version:             t.Version,
+			Revision:            t.Revision,
+			RevisionOrderNumber: t.RevisionOrderNumber,
+			Requester:           t.Requester,
+			Priority:            t.Priority,
+			TaskGroup:           t.TaskGroup,
+			TaskGroupMaxHosts:   t.TaskGroupMaxHosts,
+			GenerateTask:        t.GenerateTask,
+			Status:              t.Status,
+			CreateTime:          t.CreateTime,
+			ActivatedTime:       t.ActivatedTime,
+			Duration:            t.TimeTaken,
+			ExpectedDuration:    t.ExpectedDuration,
+		}
+		for _, dep := range t.DependsOn {
+			simulated.DependsOn = append(simulated.DependsOn, dep.TaskId)
+		}
+		input.Tasks = append(input.Tasks, simulated)
+	}
+
+	return input, nil
+}
+
+// SimulateScheduler replays the input's tasks through the host allocator and
+// task prioritizer named in the options. Every interval, the simulated
+// scheduler prioritizes the tasks whose dependencies have finished and asks
+// the allocator for new hosts; between runs, free hosts take tasks from the
+// front of the most recent queue, as agents do. The simulation ends when all
+// tasks have finished and every idle host has been terminated.
+func SimulateScheduler(ctx context.Context, input *SimulationInput, opts SimulationOptions) (*SimulationResult, error) {
+	if input == nil || len(input.Tasks) == 0 {
+		return nil, errors.New("cannot simulate the scheduler without tasks")
+	}
+	if input.Distro.ContainerPool != "" {
+		return nil, errors.Errorf("cannot simulate distro '%s', which runs containers", input.Distro.Id)
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = defaultSimulationInterval
+	}
+	if opts.HostStartup < 0 {
+		return nil, errors.New("host startup time cannot be negative")
+	}
+	if opts.HostStartup == 0 {
+		opts.HostStartup = defaultSimulationHostStartup
+	}
+	if opts.HostIdleTimeout <= 0 {
+		opts.HostIdleTimeout = idleHostTimeout
+	}
+	if opts.TaskPrioritizer != "" && !util.StringSliceContains(evergreen.ValidTaskPrioritizers, opts.TaskPrioritizer) {
+		return nil, errors.Errorf("unknown task prioritizer '%s'", opts.TaskPrioritizer)
+	}
+
+	return newSchedulerSimulation(input, opts).run(ctx)
+}
+
+// idleHostTimeout matches how long the idle host job waits before it
+// terminates a host.
+const idleHostTimeout = 4 * time.Minute
+
+type simulatedTaskState struct {
+	SimulatedTask
+	numDependents int
+	arrived       bool
+	started       bool
+	finished      bool
+	startedAt     time.Time
+	finishedAt    time.Time
+}
+
+type simulatedHost struct {
+	host.Host
+	requestedAt  time.Time
+	readyAt      time.Time
+	idleSince    time.Time
+	terminatedAt time.Time
+	running      *simulatedTaskState
+	busy         time.Duration
+}
+
+func (h *simulatedHost) isUp() bool { return h.terminatedAt.IsZero() }
+
+type schedulerSimulation struct {
+	input       *SimulationInput
+	opts        SimulationOptions
+	allocator   HostAllocator
+	prioritizer TaskPrioritizer
+	ephemeral   bool
+
+	now      time.Time
+	tasks    map[string]*simulatedTaskState
+	arrivals []*simulatedTaskState
+	next     int
+	queue    []*simulatedTaskState
+	hosts    []*simulatedHost
+	finished []*simulatedTaskState
+	spawned  int
+	peak     int
+
+	// lastFinished and failing summarize the finished tasks in the way
+	// that the comparator-based prioritizer looks up task history.
+	lastFinished map[string]*simulatedTaskState
+	failing      map[string]int
+}
+
+func newSchedulerSimulation(input *SimulationInput, opts SimulationOptions) *schedulerSimulation {
+	s := &schedulerSimulation{
+		input:        input,
+		opts:         opts,
+		allocator:    GetHostAllocator(opts.HostAllocator),
+		ephemeral:    input.Distro.IsEphemeral(),
+		tasks:        make(map[string]*simulatedTaskState, len(input.Tasks)),
+		lastFinished: map[string]*simulatedTaskState{},
+		failing:      map[string]int{},
+	}
+	s.prioritizer = s.taskPrioritizer(opts.TaskPrioritizer)
+
+	for i := range input.Tasks {
+		t := &simulatedTaskState{SimulatedTask: input.Tasks[i]}
+		if t.ExpectedDuration == 0 {
+			t.ExpectedDuration = t.Duration
+		}
+		s.tasks[t.Id] = t
+		s.arrivals = append(s.arrivals, t)
+	}
+	for _, t := range s.arrivals {
+		for _, dep := range t.DependsOn {
+			if parent, ok := s.tasks[dep]; ok {
+				parent.numDependents++
+			}
+		}
+	}
+	sort.SliceStable(s.arrivals, func(i, j int) bool {
+		return s.arrivals[i].ActivatedTime.Before(s.arrivals[j].ActivatedTime)
+	})
+
+	s.now = input.Start
+	if s.now.IsZero() || s.arrivals[0].ActivatedTime.Before(s.now) {
+		s.now = s.arrivals[0].ActivatedTime
+	}
+	for i := 0; i < input.InitialHosts; i++ {
+		s.addHost(s.now)
+	}
+
+	return s
+}
+
+// taskPrioritizer returns the named prioritizer, set up to look up task
+// history and project usage in the simulation instead of the database.
+func (s *schedulerSimulation) taskPrioritizer(name string) TaskPrioritizer {
+	base := &CmpBasedTaskPrioritizer{
+		runtimeID:  simulationRuntimeID,
+		setupFuncs: []sortSetupFunc{s.cacheHistory, cacheTaskGroups, groupTaskGroups},
+	}
+	if name == evergreen.TaskPrioritizerFairShare {
+		return &FairShareTaskPrioritizer{
+			runtimeID: simulationRuntimeID,
+			window:    fairShareUsageWindow,
+			base:      base,
+			findUsage: s.findProjectUsage,
+		}
+	}
+	return base
+}
+
+func (s *schedulerSimulation) run(ctx context.Context) (*SimulationResult, error) {
+	deadline := s.arrivals[len(s.arrivals)-1].ActivatedTime.Add(simulationDrainLimit)
+	nextRun := s.now
+
+	for !s.done() {
+		if ctx.Err() != nil {
+			return nil, errors.New("simulation canceled")
+		}
+
+		s.now = s.nextEvent(nextRun)
+		if s.now.After(deadline) {
+			break
+		}
+
+		s.finishTasks()
+		if !s.now.Before(nextRun) {
+			if err := s.schedule(ctx); err != nil {
+				return nil, errors.WithStack(err)
+			}
+			nextRun = nextRun.Add(s.opts.Interval)
+		}
+		s.dispatch()
+	}
+
+	return s.result(), nil
+}
+
+// done returns true once every task has finished and, on distros whose
+// hosts come and go, every host has been terminated.
+func (s *schedulerSimulation) done() bool {
+	if len(s.finished) < len(s.tasks) {
+		return false
+	}
+	if !s.ephemeral {
+		return true
+	}
+	for _, h := range s.hosts {
+		if h.isUp() {
+			return false
+		}
+	}
+	return true
+}
+
+// nextEvent returns the time of the next scheduler run, task finish or host
+// becoming ready, whichever comes first.
+func (s *schedulerSimulation) nextEvent(nextRun time.Time) time.Time {
+	next := nextRun
+	for _, h := range s.hosts {
+		if !h.isUp() {
+			continue
+		}
+		if h.running != nil {
+			if finish := h.running.startedAt.Add(h.running.Duration); finish.Before(next) {
+				next = finish
+			}
+		} else if h.readyAt.After(s.now) && h.readyAt.Before(next) && len(s.queue) > 0 {
+			next = h.readyAt
+		}
+	}
+	if next.Before(s.now) {
+		return s.now
+	}
+	return next
+}
+
+func (s *schedulerSimulation) addHost(readyAt time.Time) {
+	s.spawned++
+	s.hosts = append(s.hosts, &simulatedHost{
+		Host: host.Host{
+			Id:     util.RandomString(),
+			Distro: s.input.Distro,
+			Status: evergreen.HostRunning,
+		},
+		requestedAt: s.now,
+		readyAt:     readyAt,
+		idleSince:   readyAt,
+	})
+}
+
+func (s *schedulerSimulation) finishTasks() {
+	for _, h := range s.hosts {
+		t := h.running
+		if t == nil || t.startedAt.Add(t.Duration).After(s.now) {
+			continue
+		}
+
+		t.finished = true
+		t.finishedAt = t.startedAt.Add(t.Duration)
+		s.finished = append(s.finished, t)
+		s.recordHistory(t)
+
+		h.running = nil
+		h.busy += t.Duration
+		h.idleSince = t.finishedAt
+		h.RunningTask = ""
+		h.RunningTaskGroup = ""
+		h.RunningTaskBuildVariant = ""
+		h.RunningTaskProject = ""
+		h.RunningTaskVersion = ""
+	}
+}
+
+// schedule runs the simulated scheduler: it terminates idle hosts, queues
+// the tasks that are ready to run, and spawns the hosts the allocator asks
+// for.
+func (s *schedulerSimulation) schedule(ctx context.Context) error {
+	for s.next < len(s.arrivals) && !s.arrivals[s.next].ActivatedTime.After(s.now) {
+		s.arrivals[s.next].arrived = true
+		s.next++
+	}
+
+	if s.ephemeral {
+		for _, h := range s.hosts {
+			if h.isUp() && h.running == nil && !h.readyAt.After(s.now) && s.now.Sub(h.idleSince) >= s.opts.HostIdleTimeout {
+				h.terminatedAt = s.now
+			}
+		}
+	}
+
+	ready := []task.Task{}
+	versions := map[string]version.Version{}
+	for _, t := range s.arrivals[:s.next] {
+		if t.started || !s.dependenciesFinished(t) {
+			continue
+		}
+		ready = append(ready, s.toTask(t))
+		versions[t.Version] = version.Version{Id: t.Version}
+	}
+
+	prioritized, err := s.prioritizer.PrioritizeTasks(s.input.Distro.Id, ready, versions)
+	if err != nil {
+		return errors.Wrap(err, "problem prioritizing tasks")
+	}
+
+	s.queue = make([]*simulatedTaskState, 0, len(prioritized))
+	queueItems := make([]model.TaskQueueItem, 0, len(prioritized))
+	for _, t := range prioritized {
+		s.queue = append(s.queue, s.tasks[t.Id])
+		queueItems = append(queueItems, model.TaskQueueItem{
+			Id:                  t.Id,
+			DisplayName:         t.DisplayName,
+			BuildVariant:        t.BuildVariant,
+			RevisionOrderNumber: t.RevisionOrderNumber,
+			Requester:           t.Requester,
+			Revision:            t.Revision,
+			Project:             t.Project,
+			ExpectedDuration:    t.ExpectedDuration,
+			Priority:            t.Priority,
+			Group:               t.TaskGroup,
+			GroupMaxHosts:       t.TaskGroupMaxHosts,
+			Version:             t.Version,
+		})
+	}
+
+	existingHosts := []host.Host{}
+	for _, h := range s.hosts {
+		if h.isUp() {
+			existingHosts = append(existingHosts, h.Host)
+		}
+	}
+
+	newHosts, err := s.allocator(ctx, HostAllocatorData{
+		taskQueueItems:       queueItems,
+		existingHosts:        existingHosts,
+		distro:               s.input.Distro,
+		freeHostFraction:     s.opts.FreeHostFraction,
+		estimateRunningTasks: s.estimateRunningTasks,
+		findArrivalStats:     s.findArrivalStats,
+		now:                  s.now,
+		forecastLookahead:    s.opts.HostStartup + s.opts.HostIdleTimeout,
+	})
+	if err != nil {
+		return errors.Wrap(err, "problem allocating hosts")
+	}
+	if !s.ephemeral {
+		newHosts = 0
+	}
+	for i := 0; i < newHosts; i++ {
+		s.addHost(s.now.Add(s.opts.HostStartup))
+	}
+	if up := len(existingHosts) + newHosts; up > s.peak {
+		s.peak = up
+	}
+
+	return nil
+}
+
+// dispatch gives each free host the first task in the queue that it may
+// run, respecting the maximum number of hosts of task groups.
+func (s *schedulerSimulation) dispatch() {
+	for _, h := range s.hosts {
+		if !h.isUp() || h.running != nil || h.readyAt.After(s.now) {
+			continue
+		}
+
+		for i, t := range s.queue {
+			if t.started || !s.canRunGroup(t) {
+				continue
+			}
+
+			t.started = true
+			t.startedAt = s.now
+			h.running = t
+			h.RunningTask = t.Id
+			h.RunningTaskGroup = t.TaskGroup
+			h.RunningTaskBuildVariant = t.BuildVariant
+			h.RunningTaskProject = t.Project
+			h.RunningTaskVersion = t.Version
+			s.queue = append(s.queue[:i], s.queue[i+1:]...)
+			break
+		}
+	}
+}
+
+func (s *schedulerSimulation) canRunGroup(t *simulatedTaskState) bool {
+	if t.TaskGroup == "" || t.TaskGroupMaxHosts <= 0 {
+		return true
+	}
+
+	running := 0
+	for _, h := range s.hosts {
+		if h.running != nil && h.RunningTaskGroup == t.TaskGroup && h.RunningTaskBuildVariant == t.BuildVariant &&
+			h.RunningTaskProject == t.Project && h.RunningTaskVersion == t.Version {
+			running++
+		}
+	}
+	return running < t.TaskGroupMaxHosts
+}
+
+func (s *schedulerSimulation) dependenciesFinished(t *simulatedTaskState) bool {
+	for _, dep := range t.DependsOn {
+		// dependencies outside of the window finished before it
+		if parent, ok := s.tasks[dep]; ok && !parent.finished {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *schedulerSimulation) toTask(t *simulatedTaskState) task.Task {
+	return task.Task{
+		Id:                  t.Id,
+		DisplayName:         t.DisplayName,
+		Project:             t.Project,
+		BuildVariant:        t.BuildVariant,
+		BuildId:             t.BuildId,
+		Version:             t.Version,
+		Revision:            t.Revision,
+		RevisionOrderNumber: t.RevisionOrderNumber,
+		Requester:           t.Requester,
+		Priority:            t.Priority,
+		TaskGroup:           t.TaskGroup,
+		TaskGroupMaxHosts:   t.TaskGroupMaxHosts,
+		GenerateTask:        t.GenerateTask,
+		NumDependents:       t.numDependents,
+		CreateTime:          t.CreateTime,
+		ActivatedTime:       t.ActivatedTime,
+		DistroId:            s.input.Distro.Id,
+		ExpectedDuration:    t.ExpectedDuration,
+	}
+}
+
+// estimateRunningTasks returns estimates for the running tasks with the
+// given ids, measured on the simulation's clock.
+func (s *schedulerSimulation) estimateRunningTasks(ids []string) ([]runningTaskEstimate, error) {
+	estimates := make([]runningTaskEstimate, 0, len(ids))
+	for _, id := range ids {
+		t, ok := s.tasks[id]
+		if !ok {
+			return nil, errors.Errorf("task '%s' is not part of the simulation", id)
+		}
+
+		estimates = append(estimates, runningTaskEstimate{
+			id:       t.Id,
+			expected: t.ExpectedDuration,
+			elapsed:  s.now.Sub(t.startedAt),
+		})
+	}
+	return estimates, nil
+}
+
+// findArrivalStats summarizes the tasks that have arrived in the simulation
+// since the given time by hour of the week, as the predictive allocator looks
+// up its history. Only tasks that have already arrived are counted, so the
+// forecast never sees the future of the recording.
+func (s *schedulerSimulation) findArrivalStats(distroID string, since time.Time) ([]task.ArrivalStats, error) {
+	type hourOfWeek struct {
+		day  time.Weekday
+		hour int
+	}
+	byHour := map[hourOfWeek]*task.ArrivalStats{}
+	hours := []hourOfWeek{}
+	for _, t := range s.arrivals[:s.next] {
+		if t.ActivatedTime.Before(since) {
+			continue
+		}
+		activated := t.ActivatedTime.UTC()
+		key := hourOfWeek{day: activated.Weekday(), hour: activated.Hour()}
+		if _, ok := byHour[key]; !ok {
+			byHour[key] = &task.ArrivalStats{DayOfWeek: key.day, Hour: key.hour}
+			hours = append(hours, key)
+		}
+		byHour[key].NumTasks++
+		byHour[key].SumExpectedDuration += t.ExpectedDuration
+	}
+
+	out := make([]task.ArrivalStats, 0, len(hours))
+	for _, key := range hours {
+		out = append(out, *byHour[key])
+	}
+	return out, nil
+}
+
+// findProjectUsage sums the time taken by the tasks of each project that
+// finished during the fair share window. The prioritizer computes the start
+// of its window from the wall clock, so the window is computed from the
+// simulation's clock instead.
+func (s *schedulerSimulation) findProjectUsage(distroID string, _ time.Time) ([]task.ProjectUsage, error) {
+	since := s.now.Add(-fairShareUsageWindow)
+	usage := map[string]*task.ProjectUsage{}
+	projects := []string{}
+	for _, t := range s.finished {
+		if t.finishedAt.Before(since) {
+			continue
+		}
+		if _, ok := usage[t.Project]; !ok {
+			usage[t.Project] = &task.ProjectUsage{Project: t.Project}
+			projects = append(projects, t.Project)
+		}
+		usage[t.Project].SumTimeTaken += t.Duration
+		usage[t.Project].NumTasks++
+	}
+
+	out := make([]task.ProjectUsage, 0, len(projects))
+	for _, project := range projects {
+		out = append(out, *usage[project])
+	}
+	return out, nil
+}
+
+func simulatedHistoryKey(t task.Task) string {
+	return t.Project + "/" + t.BuildVariant + "/" + t.DisplayName + "/" + t.Requester
+}
+
+func simulatedFailingKey(t task.Task) string {
+	return t.Revision + "/" + t.Project + "/" + t.DisplayName + "/" + t.Requester
+}
+
+func (s *schedulerSimulation) recordHistory(t *simulatedTaskState) {
+	finished := s.toTask(t)
+	s.lastFinished[simulatedHistoryKey(finished)] = t
+	if t.Status == evergreen.TaskFailed {
+		s.failing[simulatedFailingKey(finished)]++
+		s.failing[simulatedFailingKey(finished)+"/"+t.BuildVariant]++
+	}
+}
+
+// cacheHistory is a sort setup function that caches the previous runs of
+// the tasks and the number of similar failing tasks, in place of
+// cachePreviousTasks and cacheSimilarFailing, from the tasks that finished
+// during the simulation.
+func (s *schedulerSimulation) cacheHistory(comparator *CmpBasedTaskComparator) error {
+	comparator.previousTasksCache = make(map[string]task.Task, len(comparator.tasks))
+	comparator.similarFailingCount = make(map[string]int, len(comparator.tasks))
+	for _, t := range comparator.tasks {
+		comparator.previousTasksCache[t.Id] = task.Task{}
+		comparator.similarFailingCount[t.Id] = 0
+
+		// only relevant for repotracker tasks
+		if t.Requester != evergreen.RepotrackerVersionRequester {
+			continue
+		}
+		if prev, ok := s.lastFinished[simulatedHistoryKey(t)]; ok {
+			comparator.previousTasksCache[t.Id] = task.Task{
+				Id:        prev.Id,
+				Status:    prev.Status,
+				TimeTaken: prev.Duration,
+			}
+		}
+		key := simulatedFailingKey(t)
+		comparator.similarFailingCount[t.Id] = s.failing[key] - s.failing[key+"/"+t.BuildVariant]
+	}
+	return nil
+}
+
+func (s *schedulerSimulation) result() *SimulationResult {
+	res := &SimulationResult{
+		Distro:          s.input.Distro.Id,
+		HostAllocator:   s.opts.HostAllocator,
+		TaskPrioritizer: s.opts.TaskPrioritizer,
+		Tasks:           len(s.tasks),
+		UnfinishedTasks: len(s.tasks) - len(s.finished),
+		HostsSpawned:    s.spawned - s.input.InitialHosts,
+		PeakHosts:       s.peak,
+	}
+	if res.PeakHosts < s.input.InitialHosts {
+		res.PeakHosts = s.input.InitialHosts
+	}
+
+	waits := make([]time.Duration, 0, len(s.tasks))
+	var totalWait time.Duration
+	var lastFinish time.Time
+	for _, t := range s.arrivals {
+		if !t.started {
+			continue
+		}
+		wait := t.startedAt.Sub(t.ActivatedTime)
+		if wait < 0 {
+			wait = 0
+		}
+		waits = append(waits, wait)
+		totalWait += wait
+		if t.finished && t.finishedAt.After(lastFinish) {
+			lastFinish = t.finishedAt
+		}
+	}
+	if !lastFinish.IsZero() {
+		res.Makespan = lastFinish.Sub(s.arrivals[0].ActivatedTime)
+	}
+	if len(waits) > 0 {
+		sort.Slice(waits, func(i, j int) bool { return waits[i] < waits[j] })
+		res.MeanWait = totalWait / time.Duration(len(waits))
+		res.WaitP50 = waitPercentile(waits, 0.5)
+		res.WaitP90 = waitPercentile(waits, 0.9)
+		res.WaitP99 = waitPercentile(waits, 0.99)
+		res.MaxWait = waits[len(waits)-1]
+	}
+
+	// hosts of distros whose hosts are never terminated are counted until
+	// the last task finished
+	end := s.now
+	if !s.ephemeral && !lastFinish.IsZero() {
+		end = lastFinish
+	}
+	for _, h := range s.hosts {
+		terminated := h.terminatedAt
+		if terminated.IsZero() {
+			terminated = end
+		}
+		res.HostHours += terminated.Sub(h.requestedAt).Hours()
+
+		busy := h.busy
+		if h.running != nil {
+			busy += terminated.Sub(h.running.startedAt)
+		}
+		if up := terminated.Sub(h.readyAt); up > busy {
+			res.IdleHostHours += (up - busy).Hours()
+		}
+	}
+
+	return res
+}
+
+// waitPercentile returns the nearest-rank percentile of the sorted waits.
+func waitPercentile(sorted []time.Duration, percentile float64) time.Duration {
+	rank := int(math.Ceil(percentile*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
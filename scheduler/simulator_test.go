@@ -0,0 +1,140 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func simulationInputForTest() *SimulationInput {
+	start := time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)
+	input := &SimulationInput{
+		Distro: distro.Distro{Id: "d", Provider: evergreen.ProviderNameMock, PoolSize: 10},
+		Start:  start,
+		End:    start.Add(time.Hour),
+	}
+
+	// a burst of ten ten-minute tasks, and a task that depends on the first
+	for i := 0; i < 10; i++ {
+		input.Tasks = append(input.Tasks, SimulatedTask{
+			Id:            fmt.Sprintf("t%d", i),
+			DisplayName:   fmt.Sprintf("t%d", i),
+			Project:       "p",
+			BuildVariant:  "bv",
+			Version:       "v",
+			Requester:     evergreen.RepotrackerVersionRequester,
+			Status:        evergreen.TaskSucceeded,
+			ActivatedTime: start,
+			Duration:      10 * time.Minute,
+		})
+	}
+	input.Tasks = append(input.Tasks, SimulatedTask{
+		Id:            "dependent",
+		DisplayName:   "dependent",
+		Project:       "p",
+		BuildVariant:  "bv",
+		Version:       "v",
+		Requester:     evergreen.RepotrackerVersionRequester,
+		Status:        evergreen.TaskSucceeded,
+		ActivatedTime: start,
+		Duration:      time.Minute,
+		DependsOn:     []string{"t0", "outside-of-window"},
+	})
+
+	return input
+}
+
+func TestSimulateScheduler(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	opts := SimulationOptions{
+		HostAllocator:   "deficit",
+		Interval:        time.Minute,
+		HostStartup:     5 * time.Minute,
+		HostIdleTimeout: 4 * time.Minute,
+	}
+	deficit, err := SimulateScheduler(ctx, simulationInputForTest(), opts)
+	require.NoError(err)
+	assert.Equal("d", deficit.Distro)
+	assert.Equal(11, deficit.Tasks)
+	assert.Equal(0, deficit.UnfinishedTasks)
+	assert.Equal(10, deficit.HostsSpawned)
+	assert.Equal(10, deficit.PeakHosts)
+	// every burst task starts once the hosts are up, and the dependent
+	// task runs on a host freed by the burst
+	assert.Equal(5*time.Minute, deficit.WaitP50)
+	assert.Equal(15*time.Minute, deficit.MaxWait)
+	assert.Equal(16*time.Minute, deficit.Makespan)
+	assert.True(deficit.HostHours > 0)
+	assert.True(deficit.IdleHostHours > 0)
+	assert.True(deficit.IdleHostHours < deficit.HostHours)
+
+	// allocators that account for the tasks' durations spawn fewer hosts,
+	// at the cost of longer waits
+	opts.HostAllocator = "utilization"
+	utilization, err := SimulateScheduler(ctx, simulationInputForTest(), opts)
+	require.NoError(err)
+	assert.Equal(0, utilization.UnfinishedTasks)
+	assert.True(utilization.HostsSpawned <= deficit.HostsSpawned)
+	assert.True(utilization.MaxWait >= deficit.MaxWait)
+
+	// the predictive allocator spawns at least the hosts the queue calls
+	// for, plus any its forecast from the arrivals so far calls for
+	opts.HostAllocator = "predictive"
+	predictive, err := SimulateScheduler(ctx, simulationInputForTest(), opts)
+	require.NoError(err)
+	assert.Equal(0, predictive.UnfinishedTasks)
+	assert.True(predictive.HostsSpawned >= utilization.HostsSpawned)
+
+	opts.HostAllocator = "utilization"
+	opts.TaskPrioritizer = "fairshare"
+	fairShare, err := SimulateScheduler(ctx, simulationInputForTest(), opts)
+	require.NoError(err)
+	assert.Equal(0, fairShare.UnfinishedTasks)
+}
+
+func TestSimulateSchedulerStaticDistro(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	input := simulationInputForTest()
+	input.Tasks = input.Tasks[:10]
+	input.Distro.Provider = evergreen.ProviderNameStatic
+	input.InitialHosts = 2
+
+	res, err := SimulateScheduler(ctx, input, SimulationOptions{HostAllocator: "deficit"})
+	require.NoError(err)
+	assert.Equal(0, res.UnfinishedTasks)
+	assert.Equal(0, res.HostsSpawned)
+	assert.Equal(2, res.PeakHosts)
+	// two hosts run the ten ten-minute tasks in five rounds
+	assert.Equal(40*time.Minute, res.WaitP99)
+	assert.Equal(50*time.Minute, res.Makespan)
+	assert.InDelta(2*res.Makespan.Hours(), res.HostHours, 0.001)
+}
+
+func TestSimulateSchedulerInvalidInput(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := SimulateScheduler(ctx, nil, SimulationOptions{})
+	assert.Error(t, err)
+	_, err = SimulateScheduler(ctx, &SimulationInput{}, SimulationOptions{})
+	assert.Error(t, err)
+
+	input := simulationInputForTest()
+	input.Distro.ContainerPool = "pool"
+	_, err = SimulateScheduler(ctx, input, SimulationOptions{})
+	assert.Error(t, err)
+}
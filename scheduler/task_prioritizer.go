@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/mongodb/grip"
@@ -22,6 +23,25 @@ type TaskPrioritizer interface {
 	PrioritizeTasks(distroId string, tasks []task.Task, versions map[string]version.Version) ([]task.Task, error)
 }
 
+// projectSharer is implemented by prioritizers that divide a distro's queue
+// between projects, so that the shares they computed can be saved with it.
+type projectSharer interface {
+	ProjectShares() []model.ProjectShare
+}
+
+// GetTaskPrioritizer returns the TaskPrioritizer with the given name, or the
+// comparator-based prioritizer if the name is empty.
+func GetTaskPrioritizer(name, runtimeID string) (TaskPrioritizer, error) {
+	switch name {
+	case "", evergreen.TaskPrioritizerComparator:
+		return &CmpBasedTaskPrioritizer{runtimeID: runtimeID}, nil
+	case evergreen.TaskPrioritizerFairShare:
+		return NewFairShareTaskPrioritizer(runtimeID), nil
+	default:
+		return nil, errors.Errorf("unknown task prioritizer '%s'", name)
+	}
+}
+
 // CmpBasedTaskComparator runs the tasks through a slice of comparator functions
 // determining which is more important.
 type CmpBasedTaskComparator struct {
@@ -76,6 +96,10 @@ func NewCmpBasedTaskComparator(id string) *CmpBasedTaskComparator {
 
 type CmpBasedTaskPrioritizer struct {
 	runtimeID string
+
+	// setupFuncs replace the comparator's default setup functions, such as
+	// to prioritize tasks without looking up their history in the database.
+	setupFuncs []sortSetupFunc
 }
 
 // PrioritizeTask prioritizes the tasks to run. First splits the tasks into slices based on
@@ -86,6 +110,9 @@ func (prioritizer *CmpBasedTaskPrioritizer) PrioritizeTasks(distroId string, tas
 
 	comparator := NewCmpBasedTaskComparator(prioritizer.runtimeID)
 	comparator.versions = versions
+	if prioritizer.setupFuncs != nil {
+		comparator.setupFuncs = prioritizer.setupFuncs
+	}
 	// split the tasks into repotracker tasks and patch tasks, then prioritize
 	// individually and merge
 	taskQueues := comparator.splitTasksByRequester(tasks)
@@ -9,16 +9,17 @@ import (
 // TaskQueuePersister is responsible for taking a task queue for a particular distro
 // and saving it.
 type TaskQueuePersister interface {
-	// distro, tasks, duration cache
-	PersistTaskQueue(string, []task.Task) ([]model.TaskQueueItem, error)
+	// distro, tasks, project shares
+	PersistTaskQueue(string, []task.Task, []model.ProjectShare) ([]model.TaskQueueItem, error)
 }
 
 // DBTaskQueuePersister saves a queue to the database.
 type DBTaskQueuePersister struct{}
 
-// PersistTaskQueue saves the task queue to the database.
+// PersistTaskQueue saves the task queue, along with the project shares the
+// prioritizer computed for it, if any, to the database.
 // Returns an error if the db call returns an error.
-func (self *DBTaskQueuePersister) PersistTaskQueue(distro string, tasks []task.Task) ([]model.TaskQueueItem, error) {
+func (self *DBTaskQueuePersister) PersistTaskQueue(distro string, tasks []task.Task, shares []model.ProjectShare) ([]model.TaskQueueItem, error) {
 	taskQueue := make([]model.TaskQueueItem, 0, len(tasks))
 	for _, t := range tasks {
 		taskQueue = append(taskQueue, model.TaskQueueItem{
@@ -39,6 +40,7 @@ func (self *DBTaskQueuePersister) PersistTaskQueue(distro string, tasks []task.T
 	}
 
 	queue := model.NewTaskQueue(distro, taskQueue)
+	queue.ProjectShares = shares
 	err := queue.Save()
 
 	return taskQueue, errors.WithStack(err)
@@ -102,15 +102,18 @@ func TestDBTaskQueuePersister(t *testing.T) {
 			"correct ordering of tasks along with the relevant average task "+
 			"completion times", func() {
 			_, err := taskQueuePersister.PersistTaskQueue(distroIds[0],
-				[]task.Task{tasks[0], tasks[1], tasks[2]})
+				[]task.Task{tasks[0], tasks[1], tasks[2]},
+				[]model.ProjectShare{{Project: "p1", QueuedTasks: 3, Share: 1}})
 			So(err, ShouldBeNil)
 			_, err = taskQueuePersister.PersistTaskQueue(distroIds[1],
-				[]task.Task{tasks[3], tasks[4]})
+				[]task.Task{tasks[3], tasks[4]}, nil)
 			So(err, ShouldBeNil)
 
 			taskQueue, err := model.LoadTaskQueue(distroIds[0])
 			So(err, ShouldBeNil)
 			So(taskQueue.Length(), ShouldEqual, 3)
+			So(len(taskQueue.ProjectShares), ShouldEqual, 1)
+			So(taskQueue.ProjectShares[0].QueuedTasks, ShouldEqual, 3)
 
 			So(taskQueue.Queue[0].Id, ShouldEqual, taskIds[0])
 			So(taskQueue.Queue[0].DisplayName, ShouldEqual,
@@ -12,7 +12,6 @@ import (
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/host"
-	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 )
@@ -44,7 +43,8 @@ func UtilizationBasedHostAllocator(ctx context.Context, hostAllocatorData HostAl
 			hostAllocatorData.freeHostFraction,
 			hostAllocatorData.usesContainers,
 			hostAllocatorData.containerPool,
-			maxHosts)
+			maxHosts,
+			hostAllocatorData.runningTaskEstimator())
 
 		if err != nil {
 			return 0, errors.Wrapf(err, "error calculating hosts for distro %s", hostAllocatorData.distro.Id)
@@ -121,7 +121,7 @@ func makeTaskGroupString(group, bv, project, version string) string {
 // and dividing it by the target duration. Request however many hosts are needed to
 // achieve that minus the number of free hosts
 func evalHostUtilization(ctx context.Context, d distro.Distro, taskQueue []model.TaskQueueItem, existingHosts []host.Host,
-	freeHostFraction float64, usesContainers bool, containerPool *evergreen.ContainerPool, maxHosts int) (int, error) {
+	freeHostFraction float64, usesContainers bool, containerPool *evergreen.ContainerPool, maxHosts int, estimateRunningTasks runningTaskEstimator) (int, error) {
 
 	if !d.IsEphemeral() {
 		return 0, nil
@@ -149,7 +149,7 @@ func evalHostUtilization(ctx context.Context, d distro.Distro, taskQueue []model
 	scheduledTasksDuration := calcScheduledTasksDuration(newTaskQueue)
 
 	// determine how many free hosts we have that are already up
-	numFreeHosts, err := calcExistingFreeHosts(existingHosts, freeHostFraction, maxDuration)
+	numFreeHosts, err := calcExistingFreeHosts(existingHosts, estimateRunningTasks, freeHostFraction, maxDuration)
 	if err != nil {
 		return numNewHosts, err
 	}
@@ -158,11 +158,11 @@ func evalHostUtilization(ctx context.Context, d distro.Distro, taskQueue []model
 	numNewHosts = calcNewHostsNeeded(scheduledTasksDuration, maxDuration, numFreeHosts, hostsForLongTasks)
 
 	// calculate the same values for 0 and 1 values of the fraction (just for reporting purposes)
-	freeHostsIfZero, err := calcExistingFreeHosts(existingHosts, 0, maxDuration)
+	freeHostsIfZero, err := calcExistingFreeHosts(existingHosts, estimateRunningTasks, 0, maxDuration)
 	if err != nil {
 		return numNewHosts, err
 	}
-	freeHostsIfOne, err := calcExistingFreeHosts(existingHosts, 1, maxDuration)
+	freeHostsIfOne, err := calcExistingFreeHosts(existingHosts, estimateRunningTasks, 1, maxDuration)
 	if err != nil {
 		return numNewHosts, err
 	}
@@ -268,7 +268,7 @@ func calcNewHostsNeeded(scheduledDuration, maxDurationPerHost time.Duration, num
 
 // calcExistingFreeHosts returns the number of hosts that are not running a task,
 // plus hosts that will soon be free scaled by some fraction
-func calcExistingFreeHosts(existingHosts []host.Host, freeHostFactor float64, maxDurationPerHost time.Duration) (int, error) {
+func calcExistingFreeHosts(existingHosts []host.Host, estimateRunningTasks runningTaskEstimator, freeHostFactor float64, maxDurationPerHost time.Duration) (int, error) {
 	numFreeHosts := 0
 	if freeHostFactor > 1 {
 		return numFreeHosts, errors.New("free host factor cannot be greater than 1")
@@ -280,7 +280,7 @@ func calcExistingFreeHosts(existingHosts []host.Host, freeHostFactor float64, ma
 		}
 	}
 
-	soonToBeFree, err := getSoonToBeFreeHosts(existingHosts, freeHostFactor, maxDurationPerHost)
+	soonToBeFree, err := getSoonToBeFreeHosts(existingHosts, estimateRunningTasks, freeHostFactor, maxDurationPerHost)
 	if err != nil {
 		return 0, err
 	}
@@ -292,7 +292,7 @@ func calcExistingFreeHosts(existingHosts []host.Host, freeHostFactor float64, ma
 // to be free for some fraction of the next maxDurationPerHost interval
 // the final value is scaled by some fraction representing how confident we are that
 // the hosts will actually be free in the expected amount of time
-func getSoonToBeFreeHosts(existingHosts []host.Host, freeHostFactor float64, maxDurationPerHost time.Duration) (float64, error) {
+func getSoonToBeFreeHosts(existingHosts []host.Host, estimateRunningTasks runningTaskEstimator, freeHostFactor float64, maxDurationPerHost time.Duration) (float64, error) {
 	var freeHosts float64
 	runningTaskIds := []string{}
 
@@ -306,15 +306,13 @@ func getSoonToBeFreeHosts(existingHosts []host.Host, freeHostFactor float64, max
 		return freeHosts, nil
 	}
 
-	runningTasks, err := task.Find(task.ByIds(runningTaskIds))
+	runningTasks, err := estimateRunningTasks(runningTaskIds)
 	if err != nil {
 		return freeHosts, err
 	}
 
 	for _, t := range runningTasks {
-		expectedDuration := t.FetchExpectedDuration()
-		elapsedTime := time.Since(t.StartTime)
-		timeLeft := expectedDuration - elapsedTime
+		timeLeft := t.expected - t.elapsed
 
 		// calculate what fraction of the host will be free within the max duration.
 		// for example if we estimate 20 minutes left on the task and the target duration
@@ -172,7 +172,7 @@ func (s *UtilizationAllocatorSuite) TestCalcExistingFreeHosts() {
 	}
 	s.NoError(t3.Insert())
 
-	freeHosts, err := calcExistingFreeHosts([]host.Host{h1, h2, h3, h4, h5}, 1, 30*time.Minute)
+	freeHosts, err := calcExistingFreeHosts([]host.Host{h1, h2, h3, h4, h5}, estimateRunningTasksFromDB, 1, 30*time.Minute)
 	s.NoError(err)
 	s.Equal(3, freeHosts)
 }
@@ -20,6 +20,7 @@ type Configuration struct {
 	DistroID         string
 	TaskFinder       string
 	HostAllocator    string
+	TaskPrioritizer  string
 	FreeHostFraction float64
 }
 
@@ -59,10 +60,18 @@ func PlanDistro(ctx context.Context, conf Configuration, s *evergreen.Settings)
 		return errors.Wrap(err, "error getting runnable tasks")
 	}
 
+	prioritizerName := conf.TaskPrioritizer
+	if distroSpec.TaskPrioritizer != "" {
+		prioritizerName = distroSpec.TaskPrioritizer
+	}
+
+	prioritizer, err := GetTaskPrioritizer(prioritizerName, schedulerInstance)
+	if err != nil {
+		return errors.Wrapf(err, "problem getting task prioritizer for distro '%s'", conf.DistroID)
+	}
+
 	ds := &distroSchedueler{
-		TaskPrioritizer: &CmpBasedTaskPrioritizer{
-			runtimeID: schedulerInstance,
-		},
+		TaskPrioritizer:    prioritizer,
 		TaskQueuePersister: &DBTaskQueuePersister{},
 		runtimeID:          schedulerInstance,
 	}
@@ -107,6 +116,9 @@ func PlanDistro(ctx context.Context, conf Configuration, s *evergreen.Settings)
 		freeHostFraction: conf.FreeHostFraction,
 	}
 
+	var forecast *event.HostForecast
+	allocatorArgs.recordForecast = func(f event.HostForecast) { forecast = &f }
+
 	// retrieve container pool information for container distros
 	var pool *evergreen.ContainerPool
 	if distroSpec.ContainerPool != "" {
@@ -141,6 +153,7 @@ func PlanDistro(ctx context.Context, conf Configuration, s *evergreen.Settings)
 	event.LogSchedulerEvent(event.SchedulerEventData{
 		TaskQueueInfo: res.schedulerEvent,
 		DistroId:      conf.DistroID,
+		HostForecast:  forecast,
 	})
 
 	grip.Info(message.Fields{
@@ -203,6 +203,14 @@ func (as *APIServer) GetProjectRef(w http.ResponseWriter, r *http.Request) {
 	gimlet.WriteJSON(w, p)
 }
 
+// GetBucketsConfig returns the admin settings for the storage backends of
+// the artifact commands.
+func (as *APIServer) GetBucketsConfig(w http.ResponseWriter, r *http.Request) {
+	_ = MustHaveTask(r)
+
+	gimlet.WriteJSON(w, as.GetSettings().Buckets)
+}
+
 // AttachTestLog is the API Server hook for getting
 // the test logs and storing them in the test_logs collection.
 func (as *APIServer) AttachTestLog(w http.ResponseWriter, r *http.Request) {
@@ -250,10 +258,11 @@ func (as *APIServer) AttachResults(w http.ResponseWriter, r *http.Request) {
 }
 
 // FetchProjectVars is an API hook for returning the project variables
-// associated with a task's project.
+// associated with a task's project, including those resolved from the
+// external secret store.
 func (as *APIServer) FetchProjectVars(w http.ResponseWriter, r *http.Request) {
 	t := MustHaveTask(r)
-	projectVars, err := model.FindOneProjectVars(t.Project)
+	projectVars, err := model.FindExpansionVars(r.Context(), t.Project)
 	if err != nil {
 		as.LoggedError(w, r, http.StatusInternalServerError, err)
 		return
@@ -263,7 +272,10 @@ func (as *APIServer) FetchProjectVars(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	gimlet.WriteJSON(w, projectVars)
+	gimlet.WriteJSON(w, apimodels.ExpansionVars{
+		Vars:        projectVars.Vars,
+		PrivateVars: projectVars.PrivateVars,
+	})
 }
 
 // AttachFiles updates file mappings for a task or build
@@ -571,6 +583,7 @@ func (as *APIServer) GetServiceApp() *gimlet.APIApp {
 
 	// Agent routes
 	app.Route().Version(2).Route("/agent/next_task").Wrap(checkHost).Handler(as.NextTask).Get()
+	app.Route().Version(2).Route("/agent/spot_interruption").Wrap(checkHost).Handler(as.SpotInterruption).Post()
 
 	app.Route().Version(2).Route("/task/{taskId}/end").Wrap(checkTaskSecret, checkHost).Handler(as.EndTask).Post()
 	app.Route().Version(2).Route("/task/{taskId}/start").Wrap(checkTaskSecret, checkHost).Handler(as.StartTask).Post()
@@ -586,6 +599,7 @@ func (as *APIServer) GetServiceApp() *gimlet.APIApp {
 	app.Route().Version(2).Route("/task/{taskId}/distro").Wrap(checkTask).Handler(as.GetDistro).Get()
 	app.Route().Version(2).Route("/task/{taskId}/version").Wrap(checkTask).Handler(as.GetVersion).Get()
 	app.Route().Version(2).Route("/task/{taskId}/project_ref").Wrap(checkTask).Handler(as.GetProjectRef).Get()
+	app.Route().Version(2).Route("/task/{taskId}/buckets").Wrap(checkTask).Handler(as.GetBucketsConfig).Get()
 
 	// plugins
 
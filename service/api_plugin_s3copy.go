@@ -11,7 +11,6 @@ import (
 	"github.com/evergreen-ci/evergreen/thirdparty"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
-	"github.com/goamz/goamz/aws"
 	"github.com/goamz/goamz/s3"
 	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
@@ -35,6 +34,38 @@ func (as *APIServer) s3copyPlugin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// filesystem buckets are directories on this server, so the names must
+	// not lead outside of the buckets' root
+	for _, name := range []string{s3CopyReq.S3SourceBucket, s3CopyReq.S3DestinationBucket} {
+		if err = thirdparty.ValidateBucketName(name); err != nil {
+			as.LoggedError(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	buckets := as.GetSettings().Buckets
+	src, err := thirdparty.NewBucket(thirdparty.BucketOptions{
+		Provider: s3CopyReq.Provider,
+		Name:     s3CopyReq.S3SourceBucket,
+		Key:      s3CopyReq.AwsKey,
+		Secret:   s3CopyReq.AwsSecret,
+	}, &buckets)
+	if err != nil {
+		as.LoggedError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	dest, err := thirdparty.NewBucket(thirdparty.BucketOptions{
+		Provider:    s3CopyReq.Provider,
+		Name:        s3CopyReq.S3DestinationBucket,
+		Key:         s3CopyReq.AwsKey,
+		Secret:      s3CopyReq.AwsSecret,
+		Permissions: string(s3.PublicRead),
+	}, &buckets)
+	if err != nil {
+		as.LoggedError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
 	// Get the version for this task, so we can check if it has
 	// any already-done pushes
 	v, err := version.FindOne(version.ById(task.Version))
@@ -77,20 +108,13 @@ func (as *APIServer) s3copyPlugin(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Now copy the file into the permanent location
-	auth := &aws.Auth{
-		AccessKey: s3CopyReq.AwsKey,
-		SecretKey: s3CopyReq.AwsSecret,
-	}
-
-	grip.Infof("performing S3 copy: '%s' => '%s'", copyFromLocation, copyToLocation)
+	grip.Infof("performing %s copy: '%s' => '%s'", src.Name(), copyFromLocation, copyToLocation)
 
 	_, err = util.Retry(func() (bool, error) {
-		err = errors.WithStack(thirdparty.S3CopyFile(auth,
-			s3CopyReq.S3SourceBucket,
+		err = errors.WithStack(src.Copy(r.Context(),
 			s3CopyReq.S3SourcePath,
-			s3CopyReq.S3DestinationBucket,
+			dest,
 			s3CopyReq.S3DestinationPath,
-			string(s3.PublicRead),
 		))
 		if err != nil {
 			grip.Errorf("S3 copy failed for task %s, retrying: %+v", task.Id, err)
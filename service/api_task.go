@@ -542,6 +542,33 @@ func (as *APIServer) NextTask(w http.ResponseWriter, r *http.Request) {
 	gimlet.WriteJSON(w, response)
 }
 
+// SpotInterruption stops dispatching tasks to a host whose spot instance the
+// cloud provider will reclaim, and resets the task running on it.
+func (as *APIServer) SpotInterruption(w http.ResponseWriter, r *http.Request) {
+	h := MustHaveHost(r)
+	notice := apimodels.SpotInterruptionNotice{}
+	if err := util.ReadJSONInto(util.NewRequestReader(r), &notice); err != nil {
+		as.LoggedError(w, r, http.StatusBadRequest, errors.Wrap(err, "problem reading spot interruption notice"))
+		return
+	}
+
+	grip.Info(message.Fields{
+		"message":      "agent reported spot instance interruption",
+		"host":         h.Id,
+		"distro":       h.Distro.Id,
+		"running_task": h.RunningTask,
+		"action":       notice.Action,
+		"time":         notice.Time,
+	})
+
+	if err := model.HandleSpotInterruption(h, notice); err != nil {
+		as.LoggedError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	gimlet.WriteJSON(w, struct{}{})
+}
+
 func setNextTask(t *task.Task, response *apimodels.NextTaskResponse) {
 	response.TaskId = t.Id
 	response.TaskSecret = t.Secret
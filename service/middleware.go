@@ -9,6 +9,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/auth"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/role"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/plugin"
 	"github.com/evergreen-ci/evergreen/util"
@@ -113,6 +114,10 @@ func (uis *UIServer) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
 				next(w, r)
 				return
 			}
+			if projCtx.ProjectRef != nil && uis.hasPermission(dbUser, role.PermissionEditProjectSettings, projCtx.ProjectRef) {
+				next(w, r)
+				return
+			}
 		}
 
 		uis.RedirectToLogin(w, r)
@@ -160,6 +165,65 @@ func (uis *UIServer) requireLogin(next http.HandlerFunc) http.HandlerFunc {
 	return requireUser(next, uis.RedirectToLogin)
 }
 
+// requirePermission returns a middleware function which verifies that the user
+// holds the permission, on the project context's project if it is a project
+// permission. Anonymous users may only view the logs of public projects.
+func (uis *UIServer) requirePermission(permission string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			var projectRef *model.ProjectRef
+			if role.IsProjectPermission(permission) {
+				projectRef = MustHaveProjectContext(r).ProjectRef
+			}
+
+			usr := gimlet.GetUser(r.Context())
+			if usr == nil {
+				if permission == role.PermissionViewPrivateLogs && (projectRef == nil || !projectRef.Private) {
+					next(w, r)
+					return
+				}
+				uis.RedirectToLogin(w, r)
+				return
+			}
+
+			if !uis.hasPermission(usr, permission, projectRef) {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next(w, r)
+		}
+	}
+}
+
+// hasPermission returns true if the user holds the permission on the project,
+// or globally if the project is nil. Super users hold every permission and
+// project admins hold every permission on their project.
+func (uis *UIServer) hasPermission(u gimlet.User, permission string, projectRef *model.ProjectRef) bool {
+	if uis.isSuperUser(u) {
+		return true
+	}
+
+	project := role.GlobalScope
+	if projectRef != nil {
+		if permission == role.PermissionViewPrivateLogs && !projectRef.Private {
+			return true
+		}
+		if isAdmin(u, projectRef) {
+			return true
+		}
+		project = projectRef.Identifier
+	}
+
+	allowed, err := role.HasPermission(u.Username(), permission, project)
+	grip.Error(message.WrapError(err, message.Fields{
+		"message":    "problem checking permissions",
+		"user":       u.Username(),
+		"permission": permission,
+		"project":    project,
+	}))
+	return allowed
+}
+
 // isSuperUser verifies that a given user has super user permissions.
 // A user has these permission if they are in the super users list or if the list is empty,
 // in which case all users are super users.
@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"regexp"
-	"strings"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
@@ -174,24 +172,30 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 	}
 
 	responseRef := struct {
-		Identifier         string               `json:"id"`
-		DisplayName        string               `json:"display_name"`
-		RemotePath         string               `json:"remote_path"`
-		BatchTime          int                  `json:"batch_time"`
-		DeactivatePrevious bool                 `json:"deactivate_previous"`
-		Branch             string               `json:"branch_name"`
-		ProjVarsMap        map[string]string    `json:"project_vars"`
-		ProjectAliases     []model.ProjectAlias `json:"project_aliases"`
-		DeleteAliases      []string             `json:"delete_aliases"`
-		PrivateVars        map[string]bool      `json:"private_vars"`
-		Enabled            bool                 `json:"enabled"`
-		Private            bool                 `json:"private"`
-		Owner              string               `json:"owner_name"`
-		Repo               string               `json:"repo_name"`
-		Admins             []string             `json:"admins"`
-		TracksPushEvents   bool                 `json:"tracks_push_events"`
-		PRTestingEnabled   bool                 `json:"pr_testing_enabled"`
-		PatchingDisabled   bool                 `json:"patching_disabled"`
+		Identifier         string                    `json:"id"`
+		DisplayName        string                    `json:"display_name"`
+		RemotePath         string                    `json:"remote_path"`
+		RepoKind           string                    `json:"repo_kind"`
+		RepoURL            string                    `json:"repo_url"`
+		BatchTime          int                       `json:"batch_time"`
+		DeactivatePrevious bool                      `json:"deactivate_previous"`
+		Branch             string                    `json:"branch_name"`
+		ProjVarsMap        map[string]string         `json:"project_vars"`
+		ProjectAliases     []model.ProjectAlias      `json:"project_aliases"`
+		DeleteAliases      []string                  `json:"delete_aliases"`
+		PrivateVars        map[string]bool           `json:"private_vars"`
+		SecretVars         map[string]string         `json:"secret_vars"`
+		Enabled            bool                      `json:"enabled"`
+		Private            bool                      `json:"private"`
+		Owner              string                    `json:"owner_name"`
+		Repo               string                    `json:"repo_name"`
+		Admins             []string                  `json:"admins"`
+		TracksPushEvents   bool                      `json:"tracks_push_events"`
+		PRTestingEnabled   bool                      `json:"pr_testing_enabled"`
+		CommitQueue        model.CommitQueueParams   `json:"commit_queue"`
+		Schedules          []model.VersionSchedule   `json:"schedules"`
+		Triggers           []model.TriggerDefinition `json:"triggers"`
+		PatchingDisabled   bool                      `json:"patching_disabled"`
 		AlertConfig        map[string][]struct {
 			Provider string                 `json:"provider"`
 			Settings map[string]interface{} `json:"settings"`
@@ -213,25 +217,7 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	errs := []string{}
-	for i, pd := range responseRef.ProjectAliases {
-		if strings.TrimSpace(pd.Alias) == "" {
-			errs = append(errs, fmt.Sprintf("alias name #%d can't be empty string", i+1))
-		}
-		if strings.TrimSpace(pd.Variant) == "" {
-			errs = append(errs, fmt.Sprintf("variant regex #%d can't be empty string", i+1))
-		}
-		if strings.TrimSpace(pd.Task) == "" && len(pd.Tags) == 0 {
-			errs = append(errs, fmt.Sprintf("must specify either task regex or tags on line #%d ", i+1))
-		}
-
-		if _, err = regexp.Compile(pd.Variant); err != nil {
-			errs = append(errs, fmt.Sprintf("variant regex #%d is invalid", i+1))
-		}
-		if _, err = regexp.Compile(pd.Task); err != nil {
-			errs = append(errs, fmt.Sprintf("task regex #%d is invalid", i+1))
-		}
-	}
+	errs := model.ValidateProjectAliases(responseRef.ProjectAliases)
 	if len(errs) > 0 {
 		errMsg := ""
 		for _, err := range errs {
@@ -242,23 +228,62 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if responseRef.PRTestingEnabled {
-		var conflictingRefs []model.ProjectRef
-		conflictingRefs, err = model.FindProjectRefsByRepoAndBranch(responseRef.Owner, responseRef.Repo, responseRef.Branch)
+	if responseRef.RepoKind != "" && !util.StringSliceContains(model.ValidRepoTypes, responseRef.RepoKind) {
+		uis.LoggedError(w, r, http.StatusBadRequest, errors.Errorf("invalid repo kind '%s'", responseRef.RepoKind))
+		return
+	}
+	if responseRef.RepoKind == model.GitRepoType && responseRef.RepoURL == "" {
+		uis.LoggedError(w, r, http.StatusBadRequest, errors.New("git projects must specify a repo url"))
+		return
+	}
+	if err = model.ValidateRepoURL(responseRef.RepoURL); err != nil {
+		uis.LoggedError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	validationRef := model.ProjectRef{Identifier: id, Schedules: responseRef.Schedules, Triggers: responseRef.Triggers}
+	if err = validationRef.ValidateSchedules(); err != nil {
+		uis.LoggedError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err = validationRef.ValidateTriggers(); err != nil {
+		uis.LoggedError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	for i := range validationRef.Triggers {
+		var upstream *model.ProjectRef
+		upstream, err = model.FindOneProjectRef(validationRef.Triggers[i].Project)
 		if err != nil {
 			uis.LoggedError(w, r, http.StatusInternalServerError, err)
 			return
 		}
-		for _, ref := range conflictingRefs {
-			if ref.PRTestingEnabled && ref.Identifier != id {
-				uis.LoggedError(w, r, http.StatusBadRequest, errors.Errorf("Cannot enable PR Testing in this repo, must disable in '%s' first", ref.Identifier))
-				return
-			}
+		if upstream == nil {
+			uis.LoggedError(w, r, http.StatusBadRequest, errors.Errorf("trigger '%s' has upstream project '%s', which does not exist",
+				validationRef.Triggers[i].ID, validationRef.Triggers[i].Project))
+			return
 		}
 	}
 
+	githubRef := model.ProjectRef{
+		Identifier:       id,
+		Owner:            responseRef.Owner,
+		Repo:             responseRef.Repo,
+		Branch:           responseRef.Branch,
+		PRTestingEnabled: responseRef.PRTestingEnabled,
+		CommitQueue:      responseRef.CommitQueue,
+	}
+	if err = githubRef.ValidateGitHubSettings(); err != nil {
+		uis.LoggedError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	responseRef.CommitQueue = githubRef.CommitQueue
+
 	projectRef.DisplayName = responseRef.DisplayName
 	projectRef.RemotePath = responseRef.RemotePath
+	if responseRef.RepoKind != "" {
+		projectRef.RepoKind = responseRef.RepoKind
+	}
+	projectRef.RepoURL = responseRef.RepoURL
 	projectRef.BatchTime = responseRef.BatchTime
 	projectRef.Branch = responseRef.Branch
 	projectRef.Enabled = responseRef.Enabled
@@ -270,6 +295,9 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 	projectRef.Identifier = id
 	projectRef.TracksPushEvents = responseRef.TracksPushEvents
 	projectRef.PRTestingEnabled = responseRef.PRTestingEnabled
+	projectRef.CommitQueue = responseRef.CommitQueue
+	projectRef.Schedules = responseRef.Schedules
+	projectRef.Triggers = validationRef.Triggers
 	projectRef.PatchingDisabled = responseRef.PatchingDisabled
 	projectRef.NotifyOnBuildFailure = responseRef.NotifyOnBuildFailure
 
@@ -393,6 +421,11 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 	}
 	projectVars.Vars = responseRef.ProjVarsMap
 	projectVars.PrivateVars = responseRef.PrivateVars
+	projectVars.SecretVars = responseRef.SecretVars
+	if err = projectVars.ValidateSecretVars(); err != nil {
+		uis.LoggedError(w, r, http.StatusBadRequest, err)
+		return
+	}
 
 	_, err = projectVars.Upsert()
 	if err != nil {
@@ -37,8 +37,9 @@ type uiTaskQueueItem struct {
 // ui version of a task queue, for wrapping the ui versions of task queue
 // items
 type uiTaskQueue struct {
-	Distro string            `json:"distro"`
-	Queue  []uiTaskQueueItem `json:"queue"`
+	Distro        string               `json:"distro"`
+	Queue         []uiTaskQueueItem    `json:"queue"`
+	ProjectShares []model.ProjectShare `json:"project_shares,omitempty"`
 }
 
 // top-level ui struct for holding information on task
@@ -87,8 +88,9 @@ func (uis *UIServer) allTaskQueues(w http.ResponseWriter, r *http.Request) {
 
 	for _, tQ := range taskQueues {
 		asUI := uiTaskQueue{
-			Distro: tQ.Distro,
-			Queue:  []uiTaskQueueItem{},
+			Distro:        tQ.Distro,
+			Queue:         []uiTaskQueueItem{},
+			ProjectShares: tQ.ProjectShares,
 		}
 
 		if len(tQ.Queue) == 0 {
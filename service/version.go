@@ -8,6 +8,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/role"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/plugin"
@@ -188,6 +189,10 @@ func (uis *UIServer) modifyVersion(w http.ResponseWriter, r *http.Request) {
 	// determine what action needs to be taken
 	switch jsonMap.Action {
 	case "restart":
+		if !uis.hasPermission(user, role.PermissionRestartTasks, projCtx.ProjectRef) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
 		if err = model.RestartVersion(projCtx.Version.Id, jsonMap.TaskIds, jsonMap.Abort, user.Id); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
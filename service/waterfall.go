@@ -123,6 +123,7 @@ type waterfallVersion struct {
 	Authors             []string    `json:"authors"`
 	CreateTimes         []time.Time `json:"create_times"`
 	Revisions           []string    `json:"revisions"`
+	Requesters          []string    `json:"requesters"`
 	RevisionOrderNumber int         `json:"revision_order"`
 
 	// used to hold any errors that were found in creating the version
@@ -320,6 +321,8 @@ func getVersionsAndVariants(skip, numVersionElements int, project *model.Project
 					lastRolledUpVersion.CreateTimes, versionFromDB.CreateTime)
 				lastRolledUpVersion.Revisions = append(
 					lastRolledUpVersion.Revisions, versionFromDB.Revision)
+				lastRolledUpVersion.Requesters = append(
+					lastRolledUpVersion.Requesters, versionFromDB.Requester)
 
 				// move on to the next version
 				continue
@@ -344,6 +347,7 @@ func getVersionsAndVariants(skip, numVersionElements int, project *model.Project
 				Authors:             []string{versionFromDB.Author},
 				CreateTimes:         []time.Time{versionFromDB.CreateTime},
 				Revisions:           []string{versionFromDB.Revision},
+				Requesters:          []string{versionFromDB.Requester},
 				Errors:              []waterfallVersionError{{versionFromDB.Errors}},
 				Warnings:            []waterfallVersionError{{versionFromDB.Warnings}},
 				Ignoreds:            []bool{versionFromDB.Ignored},
@@ -594,7 +598,7 @@ func waterfallDataAdaptor(vvData versionVariantData, project *model.Project, ski
 	finalData.Rows = rows
 
 	// compute the total number of versions that exist
-	finalData.TotalVersions, err = version.Count(version.ByProjectId(project.Identifier))
+	finalData.TotalVersions, err = version.Count(version.ByProjectIdForWaterfall(project.Identifier))
 	if err != nil {
 		return waterfallData{}, err
 	}
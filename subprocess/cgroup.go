@@ -0,0 +1,38 @@
+package subprocess
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrCgroupsUnsupported is returned when the system has no cgroup hierarchy
+// that the agent can place tasks in.
+var ErrCgroupsUnsupported = errors.New("cgroups are not supported on this system")
+
+// CgroupLimits are the limits on the resources used by the processes in a
+// task's cgroup. Zero values are unlimited.
+type CgroupLimits struct {
+	MemoryBytes int64
+	CPUs        float64
+	Processes   int
+}
+
+// IsZero returns true if none of the limits are set.
+func (l CgroupLimits) IsZero() bool {
+	return l.MemoryBytes == 0 && l.CPUs == 0 && l.Processes == 0
+}
+
+// CgroupUsage is a snapshot of the resources used by the processes in a
+// task's cgroup.
+type CgroupUsage struct {
+	// MemoryBytes is the memory currently in use by the cgroup.
+	MemoryBytes int64
+	// CPUTime is the CPU time used by the cgroup since it was created.
+	CPUTime time.Duration
+	// Processes is the number of processes currently in the cgroup.
+	Processes int
+	// OOMKills is the number of processes in the cgroup that the kernel
+	// has killed for exceeding the cgroup's memory limit.
+	OOMKills int
+}
@@ -0,0 +1,467 @@
+package subprocess
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+const (
+	// cgroupParent is the cgroup, at the top of each hierarchy, that holds
+	// the cgroups of all tasks.
+	cgroupParent = "evergreen"
+	// cgroupCPUPeriod is the period, in microseconds, over which the CPU
+	// limit of a cgroup is enforced.
+	cgroupCPUPeriod = 100000
+)
+
+var (
+	// cgroupRoot is where the cgroup filesystem is mounted.
+	cgroupRoot = "/sys/fs/cgroup"
+
+	cgroupNameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+	cgroupMapping = newCgroupRegistry()
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// Registry of the cgroups of the tasks running on this host
+//
+////////////////////////////////////////////////////////////////////////
+
+type cgroupRegistry struct {
+	groups map[string]*cgroup
+	mu     sync.Mutex
+}
+
+func newCgroupRegistry() *cgroupRegistry {
+	return &cgroupRegistry{
+		groups: make(map[string]*cgroup),
+	}
+}
+
+func (r *cgroupRegistry) create(key string, limits CgroupLimits) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.groups[key]; ok {
+		return nil
+	}
+
+	group, err := newCgroup(key)
+	if err != nil {
+		return err
+	}
+	if err = group.create(limits); err != nil {
+		grip.Warning(errors.Wrapf(group.remove(), "problem removing partially created cgroup for %s", key))
+		return errors.Wrapf(err, "problem creating cgroup for %s", key)
+	}
+	r.groups[key] = group
+
+	return nil
+}
+
+func (r *cgroupRegistry) get(key string) *cgroup {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.groups[key]
+}
+
+func (r *cgroupRegistry) remove(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	group, ok := r.groups[key]
+	if !ok {
+		return nil
+	}
+	delete(r.groups, key)
+
+	return errors.Wrapf(group.remove(), "problem removing cgroup for %s", key)
+}
+
+func (r *cgroupRegistry) release(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	group, ok := r.groups[key]
+	if !ok {
+		return nil
+	}
+	delete(r.groups, key)
+
+	return errors.Wrapf(group.release(), "problem releasing cgroup for %s", key)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// Functions used to manage the cgroups of tasks
+//
+////////////////////////////////////////////////////////////////////////
+
+// CreateCgroup creates a cgroup with the given limits for the task with the
+// given key. Processes passed to TrackProcess for the task, and the
+// processes they start, are placed in the cgroup.
+func CreateCgroup(key string, limits CgroupLimits) error {
+	return cgroupMapping.create(key, limits)
+}
+
+// GetCgroupUsage returns the resources currently used by the cgroup of the
+// task with the given key.
+func GetCgroupUsage(key string) (CgroupUsage, error) {
+	group := cgroupMapping.get(key)
+	if group == nil {
+		return CgroupUsage{}, errors.Errorf("no cgroup exists for %s", key)
+	}
+
+	return group.usage()
+}
+
+// RemoveCgroup kills any processes left in the cgroup of the task with the
+// given key and removes the cgroup. It is a noop if the task has no cgroup.
+func RemoveCgroup(key string) error {
+	return cgroupMapping.remove(key)
+}
+
+// ReleaseCgroup moves any processes left in the cgroup of the task with the
+// given key back to the root cgroup, where they keep running without
+// limits, and removes the cgroup. It is a noop if the task has no cgroup.
+func ReleaseCgroup(key string) error {
+	return cgroupMapping.release(key)
+}
+
+// addToCgroup moves the process with the given pid into the cgroup of the
+// task with the given key, if the task has one.
+func addToCgroup(key string, pid int, logger grip.Journaler) {
+	group := cgroupMapping.get(key)
+	if group == nil {
+		return
+	}
+
+	if err := group.addProcess(pid); err != nil {
+		logger.Errorf("failed adding process %d to cgroup: %v", pid, err)
+		return
+	}
+	logger.Infof("added process with pid %d to cgroup", pid)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// Implementation of cgroups for both the unified (v2) hierarchy and the
+// legacy (v1) hierarchies, which have a separate directory per controller.
+//
+////////////////////////////////////////////////////////////////////////
+
+type cgroup struct {
+	unified bool
+	memory  string
+	cpu     string
+	pids    string
+}
+
+func newCgroup(key string) (*cgroup, error) {
+	name := cgroupNameInvalidChars.ReplaceAllString(key, "_")
+
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err == nil {
+		path := filepath.Join(cgroupRoot, cgroupParent, name)
+		return &cgroup{unified: true, memory: path, cpu: path, pids: path}, nil
+	}
+
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "memory")); err != nil {
+		return nil, ErrCgroupsUnsupported
+	}
+	group := &cgroup{
+		memory: filepath.Join(cgroupRoot, "memory", cgroupParent, name),
+		cpu:    filepath.Join(cgroupRoot, "cpu", cgroupParent, name),
+	}
+	// the pids controller is missing from older kernels
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "pids")); err == nil {
+		group.pids = filepath.Join(cgroupRoot, "pids", cgroupParent, name)
+	}
+
+	return group, nil
+}
+
+// dirs returns the directories of the cgroup in each hierarchy.
+func (c *cgroup) dirs() []string {
+	if c.unified {
+		return []string{c.memory}
+	}
+
+	dirs := []string{c.memory, c.cpu}
+	if c.pids != "" {
+		dirs = append(dirs, c.pids)
+	}
+	return dirs
+}
+
+func (c *cgroup) create(limits CgroupLimits) error {
+	if c.unified {
+		// controllers must be enabled for the children of every cgroup
+		// between the root and the task's cgroup
+		controllers := "+memory +cpu +pids"
+		if err := writeCgroupFile(filepath.Join(cgroupRoot, "cgroup.subtree_control"), controllers); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := os.MkdirAll(filepath.Join(cgroupRoot, cgroupParent), 0755); err != nil {
+			return errors.Wrap(err, "problem creating parent cgroup")
+		}
+		if err := writeCgroupFile(filepath.Join(cgroupRoot, cgroupParent, "cgroup.subtree_control"), controllers); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	for _, dir := range c.dirs() {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return errors.Wrapf(err, "problem creating cgroup directory '%s'", dir)
+		}
+	}
+
+	if limits.MemoryBytes > 0 {
+		if err := c.setMemoryLimit(limits.MemoryBytes); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if limits.CPUs > 0 {
+		if err := c.setCPULimit(limits.CPUs); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if limits.Processes > 0 {
+		if c.pids == "" {
+			return errors.New("cannot limit processes without the pids controller")
+		}
+		if err := writeCgroupFile(filepath.Join(c.pids, "pids.max"), strconv.Itoa(limits.Processes)); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return nil
+}
+
+func (c *cgroup) setMemoryLimit(bytes int64) error {
+	limit := strconv.FormatInt(bytes, 10)
+	if !c.unified {
+		return writeCgroupFile(filepath.Join(c.memory, "memory.limit_in_bytes"), limit)
+	}
+
+	if err := writeCgroupFile(filepath.Join(c.memory, "memory.max"), limit); err != nil {
+		return err
+	}
+	// without a swap limit, a task that exceeds its memory limit swaps
+	// instead of being killed; the file is missing if swap accounting is
+	// disabled, in which case there's nothing to limit
+	swap := filepath.Join(c.memory, "memory.swap.max")
+	if _, err := os.Stat(swap); err == nil {
+		return writeCgroupFile(swap, "0")
+	}
+	return nil
+}
+
+func (c *cgroup) setCPULimit(cpus float64) error {
+	quota := strconv.Itoa(int(cpus * cgroupCPUPeriod))
+	period := strconv.Itoa(cgroupCPUPeriod)
+	if c.unified {
+		return writeCgroupFile(filepath.Join(c.cpu, "cpu.max"), quota+" "+period)
+	}
+
+	if err := writeCgroupFile(filepath.Join(c.cpu, "cpu.cfs_period_us"), period); err != nil {
+		return err
+	}
+	return writeCgroupFile(filepath.Join(c.cpu, "cpu.cfs_quota_us"), quota)
+}
+
+func (c *cgroup) addProcess(pid int) error {
+	catcher := grip.NewSimpleCatcher()
+	for _, dir := range c.dirs() {
+		catcher.Add(writeCgroupFile(filepath.Join(dir, "cgroup.procs"), strconv.Itoa(pid)))
+	}
+	return catcher.Resolve()
+}
+
+func (c *cgroup) usage() (CgroupUsage, error) {
+	usage := CgroupUsage{}
+	var err error
+
+	if c.unified {
+		if usage.MemoryBytes, err = readCgroupInt(filepath.Join(c.memory, "memory.current")); err != nil {
+			return usage, errors.WithStack(err)
+		}
+		var usec int64
+		if usec, err = readCgroupKeyedInt(filepath.Join(c.cpu, "cpu.stat"), "usage_usec"); err != nil {
+			return usage, errors.WithStack(err)
+		}
+		usage.CPUTime = time.Duration(usec) * time.Microsecond
+	} else {
+		if usage.MemoryBytes, err = readCgroupInt(filepath.Join(c.memory, "memory.usage_in_bytes")); err != nil {
+			return usage, errors.WithStack(err)
+		}
+		var nsec int64
+		if nsec, err = readCgroupInt(filepath.Join(c.cpu, "cpuacct.usage")); err != nil {
+			return usage, errors.WithStack(err)
+		}
+		usage.CPUTime = time.Duration(nsec)
+	}
+
+	if c.pids != "" {
+		var pids int64
+		if pids, err = readCgroupInt(filepath.Join(c.pids, "pids.current")); err != nil {
+			return usage, errors.WithStack(err)
+		}
+		usage.Processes = int(pids)
+	}
+
+	events := "memory.oom_control"
+	if c.unified {
+		events = "memory.events"
+	}
+	// older kernels do not count OOM kills
+	oomKills, err := readCgroupKeyedInt(filepath.Join(c.memory, events), "oom_kill")
+	if err == nil {
+		usage.OOMKills = int(oomKills)
+	}
+
+	return usage, nil
+}
+
+// remove kills the processes in the cgroup and removes its directories.
+func (c *cgroup) remove() error {
+	if err := c.killProcesses(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	catcher := grip.NewSimpleCatcher()
+	for _, dir := range c.dirs() {
+		catcher.Add(removeCgroupDir(dir))
+	}
+	return catcher.Resolve()
+}
+
+// release moves the processes in the cgroup to the root of each hierarchy
+// and removes its directories.
+func (c *cgroup) release() error {
+	catcher := grip.NewSimpleCatcher()
+	for _, dir := range c.dirs() {
+		data, err := ioutil.ReadFile(filepath.Join(dir, "cgroup.procs"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			catcher.Add(errors.Wrap(err, "problem listing processes in cgroup"))
+			continue
+		}
+
+		// each task's cgroup is a child of the parent cgroup, which is at
+		// the root of the hierarchy
+		root := filepath.Dir(filepath.Dir(dir))
+		for _, field := range strings.Fields(string(data)) {
+			// the process may have exited since the cgroup was read
+			_ = writeCgroupFile(filepath.Join(root, "cgroup.procs"), field)
+		}
+	}
+	if catcher.HasErrors() {
+		return catcher.Resolve()
+	}
+
+	for _, dir := range c.dirs() {
+		catcher.Add(removeCgroupDir(dir))
+	}
+	return catcher.Resolve()
+}
+
+func (c *cgroup) killProcesses() error {
+	// newer kernels kill every process in a unified cgroup at once
+	kill := filepath.Join(c.memory, "cgroup.kill")
+	if _, err := os.Stat(kill); c.unified && err == nil {
+		return writeCgroupFile(kill, "1")
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(c.memory, "cgroup.procs"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "problem listing processes in cgroup")
+	}
+
+	for _, field := range strings.Fields(string(data)) {
+		pid, err := strconv.Atoi(field)
+		if err != nil {
+			continue
+		}
+		// the process may have exited since the cgroup was read
+		_ = syscall.Kill(pid, syscall.SIGKILL)
+	}
+	return nil
+}
+
+// removeCgroupDir removes the directory of a cgroup, which the kernel
+// refuses until the processes in it have exited.
+func removeCgroupDir(dir string) error {
+	var err error
+	for i := 0; i < 10; i++ {
+		if err = os.RemoveAll(dir); err == nil {
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return errors.Wrapf(err, "problem removing cgroup directory '%s'", dir)
+}
+
+func writeCgroupFile(path, value string) error {
+	return errors.Wrapf(ioutil.WriteFile(path, []byte(value), 0644),
+		"problem writing '%s' to '%s'", value, path)
+}
+
+// readCgroupInt reads a file holding a single number, where "max" means
+// there is no limit.
+func readCgroupInt(path string) (int64, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, errors.Wrapf(err, "problem reading '%s'", path)
+	}
+
+	value := strings.TrimSpace(string(data))
+	if value == "max" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	return n, errors.Wrapf(err, "problem parsing '%s'", path)
+}
+
+// readCgroupKeyedInt reads the number with the given key from a file of
+// "key value" lines, such as memory.events.
+func readCgroupKeyedInt(path, key string) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, errors.Wrapf(err, "problem reading '%s'", path)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || fields[0] != key {
+			continue
+		}
+		n, err := strconv.ParseInt(fields[1], 10, 64)
+		return n, errors.Wrapf(err, "problem parsing '%s' in '%s'", key, path)
+	}
+	if err = scanner.Err(); err != nil {
+		return 0, errors.Wrapf(err, "problem reading '%s'", path)
+	}
+
+	return 0, errors.Errorf("'%s' has no '%s'", path, key)
+}
@@ -0,0 +1,131 @@
+package subprocess
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withFakeCgroupRoot points the cgroup functions at a temporary directory
+// laid out like a cgroup filesystem, which is removed when the test ends.
+func withFakeCgroupRoot(t *testing.T, layout ...string) func() {
+	root, err := ioutil.TempDir("", "cgroup")
+	require.NoError(t, err)
+	for _, path := range layout {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, path)), 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(root, path), nil, 0644))
+	}
+
+	oldRoot, oldMapping := cgroupRoot, cgroupMapping
+	cgroupRoot, cgroupMapping = root, newCgroupRegistry()
+	return func() {
+		cgroupRoot, cgroupMapping = oldRoot, oldMapping
+		assert.NoError(t, os.RemoveAll(root))
+	}
+}
+
+func readCgroupFile(t *testing.T, path ...string) string {
+	data, err := ioutil.ReadFile(filepath.Join(append([]string{cgroupRoot}, path...)...))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func writeFakeCgroupFile(t *testing.T, value string, path ...string) {
+	require.NoError(t, ioutil.WriteFile(filepath.Join(append([]string{cgroupRoot}, path...)...), []byte(value), 0644))
+}
+
+func TestUnifiedCgroup(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	defer withFakeCgroupRoot(t, "cgroup.controllers")()
+
+	require.NoError(CreateCgroup("task/1", CgroupLimits{MemoryBytes: 1024, CPUs: 1.5, Processes: 10}))
+	assert.Equal("+memory +cpu +pids", readCgroupFile(t, "evergreen", "cgroup.subtree_control"))
+	assert.Equal("1024", readCgroupFile(t, "evergreen", "task_1", "memory.max"))
+	assert.Equal("150000 100000", readCgroupFile(t, "evergreen", "task_1", "cpu.max"))
+	assert.Equal("10", readCgroupFile(t, "evergreen", "task_1", "pids.max"))
+
+	writeFakeCgroupFile(t, "512\n", "evergreen", "task_1", "memory.current")
+	writeFakeCgroupFile(t, "usage_usec 2500000\nuser_usec 2000000\n", "evergreen", "task_1", "cpu.stat")
+	writeFakeCgroupFile(t, "3\n", "evergreen", "task_1", "pids.current")
+	writeFakeCgroupFile(t, "low 0\nhigh 0\nmax 4\noom 1\noom_kill 1\n", "evergreen", "task_1", "memory.events")
+
+	usage, err := GetCgroupUsage("task/1")
+	require.NoError(err)
+	assert.Equal(CgroupUsage{MemoryBytes: 512, CPUTime: 2500 * time.Millisecond, Processes: 3, OOMKills: 1}, usage)
+
+	_, err = GetCgroupUsage("task/2")
+	assert.Error(err)
+
+	// processes that are tracked for the task are moved into its cgroup,
+	// and killed when the cgroup is removed
+	cmd := exec.Command("sleep", "30")
+	require.NoError(cmd.Start())
+	TrackProcess("task/1", cmd.Process.Pid, logging.MakeGrip(grip.GetSender()))
+	assert.Equal(strconv.Itoa(cmd.Process.Pid), readCgroupFile(t, "evergreen", "task_1", "cgroup.procs"))
+
+	require.NoError(RemoveCgroup("task/1"))
+	assert.Error(cmd.Wait())
+	_, err = os.Stat(filepath.Join(cgroupRoot, "evergreen", "task_1"))
+	assert.True(os.IsNotExist(err))
+	assert.NoError(RemoveCgroup("task/1"))
+
+	// processes in a released cgroup are moved to the root cgroup and
+	// keep running
+	require.NoError(CreateCgroup("task/2", CgroupLimits{MemoryBytes: 1024}))
+	cmd = exec.Command("sleep", "30")
+	require.NoError(cmd.Start())
+	defer func() { _ = cmd.Process.Kill() }()
+	TrackProcess("task/2", cmd.Process.Pid, logging.MakeGrip(grip.GetSender()))
+
+	require.NoError(ReleaseCgroup("task/2"))
+	assert.Equal(strconv.Itoa(cmd.Process.Pid), readCgroupFile(t, "cgroup.procs"))
+	assert.NoError(cmd.Process.Signal(syscall.Signal(0)), "released processes should not be killed")
+	_, err = os.Stat(filepath.Join(cgroupRoot, "evergreen", "task_2"))
+	assert.True(os.IsNotExist(err))
+	assert.NoError(ReleaseCgroup("task/2"))
+}
+
+func TestLegacyCgroup(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	defer withFakeCgroupRoot(t, "memory/tasks", "cpu/tasks")()
+
+	assert.Error(CreateCgroup("task", CgroupLimits{Processes: 10}), "limiting processes needs the pids controller")
+	_, err := os.Stat(filepath.Join(cgroupRoot, "memory", "evergreen", "task"))
+	assert.True(os.IsNotExist(err), "a cgroup that could not be created should be removed")
+
+	require.NoError(CreateCgroup("task", CgroupLimits{MemoryBytes: 1024, CPUs: 0.5}))
+	assert.Equal("1024", readCgroupFile(t, "memory", "evergreen", "task", "memory.limit_in_bytes"))
+	assert.Equal("100000", readCgroupFile(t, "cpu", "evergreen", "task", "cpu.cfs_period_us"))
+	assert.Equal("50000", readCgroupFile(t, "cpu", "evergreen", "task", "cpu.cfs_quota_us"))
+
+	writeFakeCgroupFile(t, "2048\n", "memory", "evergreen", "task", "memory.usage_in_bytes")
+	writeFakeCgroupFile(t, "3000000000\n", "cpu", "evergreen", "task", "cpuacct.usage")
+
+	usage, err := GetCgroupUsage("task")
+	require.NoError(err)
+	assert.Equal(CgroupUsage{MemoryBytes: 2048, CPUTime: 3 * time.Second}, usage)
+
+	require.NoError(RemoveCgroup("task"))
+	for _, controller := range []string{"memory", "cpu"} {
+		_, err = os.Stat(filepath.Join(cgroupRoot, controller, "evergreen", "task"))
+		assert.True(os.IsNotExist(err))
+	}
+}
+
+func TestCgroupsUnsupported(t *testing.T) {
+	defer withFakeCgroupRoot(t)()
+
+	assert.Equal(t, ErrCgroupsUnsupported, CreateCgroup("task", CgroupLimits{MemoryBytes: 1024}))
+}
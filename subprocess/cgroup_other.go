@@ -0,0 +1,25 @@
+// +build !linux
+
+package subprocess
+
+// CreateCgroup returns ErrCgroupsUnsupported, because cgroups only exist on
+// Linux.
+func CreateCgroup(key string, limits CgroupLimits) error {
+	return ErrCgroupsUnsupported
+}
+
+// GetCgroupUsage returns ErrCgroupsUnsupported, because cgroups only exist
+// on Linux.
+func GetCgroupUsage(key string) (CgroupUsage, error) {
+	return CgroupUsage{}, ErrCgroupsUnsupported
+}
+
+// RemoveCgroup is a noop, because cgroups only exist on Linux.
+func RemoveCgroup(key string) error {
+	return nil
+}
+
+// ReleaseCgroup is a noop, because cgroups only exist on Linux.
+func ReleaseCgroup(key string) error {
+	return nil
+}
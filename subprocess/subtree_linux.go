@@ -10,8 +10,9 @@ import (
 )
 
 func TrackProcess(key string, pid int, logger grip.Journaler) {
-	// trackProcess is a noop on linux, because we detect all the processes to be killed in
-	// cleanup() and we don't need to do any special bookkeeping up-front.
+	// we detect all the processes to be killed in cleanup(), so the only bookkeeping
+	// trackProcess does on linux is to place the process in the task's cgroup, if any.
+	addToCgroup(key, pid, logger)
 }
 
 // getEnv returns a slice of environment variables for the given pid, in the form
@@ -78,8 +78,16 @@ func MockConfig() *evergreen.Settings {
 				Organization: "ghorg",
 			},
 		},
-		Banner:            "banner",
-		BannerTheme:       "important",
+		Banner:      "banner",
+		BannerTheme: "important",
+		Buckets: evergreen.BucketsConfig{
+			Filesystem: evergreen.FilesystemBucketConfig{
+				Root: "/srv/artifacts",
+			},
+			GCS: evergreen.GCSBucketConfig{
+				Endpoint: "http://localhost:4443",
+			},
+		},
 		ClientBinariesDir: "bin_dir",
 		ConfigDir:         "cfg_dir",
 		ContainerPools: evergreen.ContainerPoolsConfig{
@@ -133,6 +141,11 @@ func MockConfig() *evergreen.Settings {
 				PrivateKeyID: "gce_key_id",
 				TokenURI:     "gce_token",
 			},
+			Kubernetes: evergreen.KubernetesConfig{
+				APIServer: "https://kubernetes.example.com",
+				Token:     "k8s_token",
+				Namespace: "evergreen",
+			},
 			OpenStack: evergreen.OpenStackConfig{
 				IdentityEndpoint: "endpoint",
 				Username:         "username",
@@ -156,6 +169,12 @@ func MockConfig() *evergreen.Settings {
 		Scheduler: evergreen.SchedulerConfig{
 			TaskFinder: "legacy",
 		},
+		Secrets: evergreen.SecretsConfig{
+			Vault: evergreen.VaultConfig{
+				URL:   "http://localhost:8200",
+				Mount: "secret",
+			},
+		},
 		ServiceFlags: evergreen.ServiceFlags{
 			TaskDispatchDisabled:         true,
 			HostinitDisabled:             true,
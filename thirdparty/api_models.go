@@ -41,6 +41,12 @@ func (nfe FileNotFoundError) Error() string {
 	return fmt.Sprintf("Requested file at %v not found", nfe.filepath)
 }
 
+// NewFileNotFoundError returns a FileNotFoundError for a file that is
+// missing from a repository.
+func NewFileNotFoundError(filepath string) FileNotFoundError {
+	return FileNotFoundError{filepath: filepath}
+}
+
 func IsFileNotFound(err error) bool {
 	_, ok := err.(FileNotFoundError)
 	return ok
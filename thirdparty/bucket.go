@@ -0,0 +1,541 @@
+package thirdparty
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/goamz/goamz/aws"
+	"github.com/pkg/errors"
+)
+
+const (
+	s3LinkBaseURL      = "https://s3.amazonaws.com"
+	defaultContentType = "application/octet-stream"
+)
+
+// Bucket is a container of files in a storage backend that artifact
+// commands upload files to and download files from.
+type Bucket interface {
+	// Name returns the name of the bucket.
+	Name() string
+	// Upload writes a local file to the remote path in the bucket.
+	Upload(ctx context.Context, localPath, remotePath, contentType string) error
+	// Get returns a reader for the file at the remote path in the bucket,
+	// which the caller must close.
+	Get(ctx context.Context, remotePath string) (io.ReadCloser, error)
+	// Copy copies the file at the remote path to a path in another bucket,
+	// which may belong to a different backend.
+	Copy(ctx context.Context, remotePath string, dest Bucket, destPath string) error
+	// Link returns the URL users can download the file at the remote path
+	// from.
+	Link(remotePath string) string
+	// List returns the files in the bucket whose paths start with the
+	// prefix.
+	List(ctx context.Context, prefix string) ([]BucketItem, error)
+}
+
+// BucketItem describes a file in a bucket.
+type BucketItem struct {
+	Path         string
+	Size         int64
+	LastModified time.Time
+}
+
+// BucketOptions describe a bucket.
+type BucketOptions struct {
+	// Provider is the storage backend of the bucket. It defaults to S3.
+	Provider string
+	Name     string
+	// Key and Secret are credentials for the backend. S3 uses both, while
+	// GCS uses Secret as an OAuth2 access token. The filesystem backend
+	// does not use credentials.
+	Key    string
+	Secret string
+	// Permissions is the S3 canned ACL to apply to uploaded files.
+	Permissions string
+}
+
+// ValidateBucketName checks that a bucket name cannot refer to a location
+// outside of its backend, since filesystem buckets are directories named
+// after their buckets.
+func ValidateBucketName(name string) error {
+	if name == "" {
+		return errors.New("bucket must have a name")
+	}
+	if name == "." || strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return errors.Errorf("invalid bucket name '%s'", name)
+	}
+	return nil
+}
+
+// NewBucket returns the bucket the options describe, with its backend
+// configured by the admin settings.
+func NewBucket(opts BucketOptions, conf *evergreen.BucketsConfig) (Bucket, error) {
+	if conf == nil {
+		conf = &evergreen.BucketsConfig{}
+	}
+	if err := ValidateBucketName(opts.Name); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	switch opts.Provider {
+	case "", evergreen.BucketProviderS3:
+		return &s3Bucket{
+			name:        opts.Name,
+			auth:        &aws.Auth{AccessKey: opts.Key, SecretKey: opts.Secret},
+			permissions: opts.Permissions,
+		}, nil
+	case evergreen.BucketProviderFilesystem:
+		if conf.Filesystem.Root == "" {
+			return nil, errors.New("no root directory is configured for filesystem buckets")
+		}
+		return &filesystemBucket{
+			name:    opts.Name,
+			dir:     filepath.Join(conf.Filesystem.Root, opts.Name),
+			linkURL: strings.TrimRight(conf.Filesystem.LinkBaseURL, "/"),
+		}, nil
+	case evergreen.BucketProviderGCS:
+		endpoint := strings.TrimRight(conf.GCS.Endpoint, "/")
+		if endpoint == "" {
+			return nil, errors.New("no endpoint is configured for gcs buckets")
+		}
+		linkURL := strings.TrimRight(conf.GCS.LinkBaseURL, "/")
+		if linkURL == "" {
+			linkURL = endpoint
+		}
+		return &gcsBucket{
+			name:        opts.Name,
+			endpoint:    endpoint,
+			linkURL:     linkURL,
+			token:       opts.Secret,
+			permissions: opts.Permissions,
+		}, nil
+	default:
+		return nil, errors.Errorf("invalid bucket provider '%s'", opts.Provider)
+	}
+}
+
+// copyBetweenBuckets copies a file between buckets of different backends by
+// downloading it to a temporary file and uploading that.
+func copyBetweenBuckets(ctx context.Context, src Bucket, remotePath string, dest Bucket, destPath string) error {
+	reader, err := src.Get(ctx, remotePath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer reader.Close()
+
+	tmp, err := ioutil.TempFile("", "evergreen-bucket-copy")
+	if err != nil {
+		return errors.Wrap(err, "problem creating temporary file")
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, reader)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrapf(err, "problem downloading '%s' from bucket '%s'", remotePath, src.Name())
+	}
+
+	return errors.WithStack(dest.Upload(ctx, tmp.Name(), destPath, defaultContentType))
+}
+
+// joinURL joins a base URL, a bucket name, and a slash separated path,
+// escaping each segment of the path.
+func joinURL(base, bucket, remotePath string) string {
+	segments := strings.Split(strings.TrimLeft(remotePath, "/"), "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return fmt.Sprintf("%s/%s/%s", base, bucket, strings.Join(segments, "/"))
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// S3
+
+type s3Bucket struct {
+	name        string
+	auth        *aws.Auth
+	permissions string
+}
+
+func (b *s3Bucket) Name() string { return b.name }
+
+func (b *s3Bucket) Upload(_ context.Context, localPath, remotePath, contentType string) error {
+	s3URL := url.URL{
+		Scheme: "s3",
+		Host:   b.name,
+		Path:   remotePath,
+	}
+	return errors.WithStack(PutS3File(b.auth, localPath, s3URL.String(), contentType, b.permissions))
+}
+
+func (b *s3Bucket) Get(_ context.Context, remotePath string) (io.ReadCloser, error) {
+	client := util.GetHTTPClient()
+
+	session := NewS3Session(b.auth, aws.USEast, client)
+	reader, err := session.Bucket(b.name).GetReader(remotePath)
+	if err != nil {
+		util.PutHTTPClient(client)
+		return nil, errors.Wrapf(err, "error getting bucket reader for file %s", remotePath)
+	}
+	return &pooledClientReadCloser{ReadCloser: reader, client: client}, nil
+}
+
+func (b *s3Bucket) Copy(ctx context.Context, remotePath string, dest Bucket, destPath string) error {
+	s3Dest, ok := dest.(*s3Bucket)
+	if !ok {
+		return copyBetweenBuckets(ctx, b, remotePath, dest, destPath)
+	}
+	return errors.WithStack(S3CopyFile(b.auth, b.name, remotePath, s3Dest.name, destPath, s3Dest.permissions))
+}
+
+func (b *s3Bucket) Link(remotePath string) string {
+	return s3LinkBaseURL + "/" + b.name + "/" + filepath.ToSlash(remotePath)
+}
+
+func (b *s3Bucket) List(ctx context.Context, prefix string) ([]BucketItem, error) {
+	client := util.GetHTTPClient()
+	defer util.PutHTTPClient(client)
+
+	bucket := NewS3Session(b.auth, aws.USEast, client).Bucket(b.name)
+	items := []BucketItem{}
+	marker := ""
+	for {
+		if ctx.Err() != nil {
+			return nil, errors.New("listing bucket canceled")
+		}
+
+		resp, err := bucket.List(prefix, "", marker, 1000)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem listing bucket %s", b.name)
+		}
+		for _, key := range resp.Contents {
+			modified, err := time.Parse(time.RFC3339Nano, key.LastModified)
+			if err != nil {
+				return nil, errors.Wrapf(err, "problem parsing modification time of %s", key.Key)
+			}
+			items = append(items, BucketItem{Path: key.Key, Size: key.Size, LastModified: modified})
+			marker = key.Key
+		}
+		if !resp.IsTruncated || len(resp.Contents) == 0 {
+			return items, nil
+		}
+	}
+}
+
+// pooledClientReadCloser returns an HTTP client to the pool once the
+// response body read with it is closed.
+type pooledClientReadCloser struct {
+	io.ReadCloser
+	client *http.Client
+}
+
+func (r *pooledClientReadCloser) Close() error {
+	defer util.PutHTTPClient(r.client)
+	return r.ReadCloser.Close()
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// Filesystem
+
+type filesystemBucket struct {
+	name    string
+	dir     string
+	linkURL string
+}
+
+func (b *filesystemBucket) Name() string { return b.name }
+
+// localPath returns the path of a file in the bucket, which must not be
+// outside of the bucket's directory.
+func (b *filesystemBucket) localPath(remotePath string) (string, error) {
+	cleaned := path.Clean("/" + filepath.ToSlash(remotePath))
+	if cleaned == "/" {
+		return "", errors.Errorf("invalid path '%s' in bucket '%s'", remotePath, b.name)
+	}
+	return filepath.Join(b.dir, filepath.FromSlash(cleaned)), nil
+}
+
+func (b *filesystemBucket) Upload(_ context.Context, localPath, remotePath, _ string) error {
+	dest, err := b.localPath(remotePath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	src, err := os.Open(localPath)
+	if err != nil {
+		return errors.Wrapf(err, "problem opening file %s", localPath)
+	}
+	defer src.Close()
+
+	if err = os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return errors.Wrapf(err, "problem creating directory for %s", dest)
+	}
+
+	// write to a temporary file and rename it so that readers never see a
+	// partially written file
+	tmp, err := ioutil.TempFile(filepath.Dir(dest), "."+filepath.Base(dest))
+	if err != nil {
+		return errors.Wrapf(err, "problem creating temporary file for %s", dest)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, src)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrapf(err, "problem writing %s", dest)
+	}
+	if err = os.Chmod(tmp.Name(), 0644); err != nil {
+		return errors.Wrapf(err, "problem setting permissions of %s", dest)
+	}
+
+	return errors.Wrapf(os.Rename(tmp.Name(), dest), "problem writing %s", dest)
+}
+
+func (b *filesystemBucket) Get(_ context.Context, remotePath string) (io.ReadCloser, error) {
+	src, err := b.localPath(remotePath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	file, err := os.Open(src)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem opening file %s in bucket %s", remotePath, b.name)
+	}
+	return file, nil
+}
+
+func (b *filesystemBucket) Copy(ctx context.Context, remotePath string, dest Bucket, destPath string) error {
+	src, err := b.localPath(remotePath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(dest.Upload(ctx, src, destPath, defaultContentType))
+}
+
+func (b *filesystemBucket) Link(remotePath string) string {
+	if b.linkURL == "" {
+		local, err := b.localPath(remotePath)
+		if err != nil {
+			return ""
+		}
+		return (&url.URL{Scheme: "file", Path: filepath.ToSlash(local)}).String()
+	}
+	return joinURL(b.linkURL, b.name, remotePath)
+}
+
+func (b *filesystemBucket) List(ctx context.Context, prefix string) ([]BucketItem, error) {
+	items := []BucketItem{}
+	err := filepath.Walk(b.dir, func(local string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && local == b.dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if ctx.Err() != nil {
+			return errors.New("listing bucket canceled")
+		}
+		// skip directories and the temporary files of uploads in progress
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(b.dir, local)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if strings.HasPrefix(rel, strings.TrimLeft(prefix, "/")) {
+			items = append(items, BucketItem{Path: rel, Size: info.Size(), LastModified: info.ModTime()})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem listing bucket %s", b.name)
+	}
+	return items, nil
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GCS
+
+// gcsPredefinedACLs maps S3 canned ACLs to the equivalent GCS predefined
+// ACLs.
+var gcsPredefinedACLs = map[string]string{
+	"private":                   "private",
+	"public-read":               "publicRead",
+	"public-read-write":         "publicReadWrite",
+	"authenticated-read":        "authenticatedRead",
+	"bucket-owner-read":         "bucketOwnerRead",
+	"bucket-owner-full-control": "bucketOwnerFullControl",
+}
+
+type gcsBucket struct {
+	name        string
+	endpoint    string
+	linkURL     string
+	token       string
+	permissions string
+}
+
+func (b *gcsBucket) Name() string { return b.name }
+
+func (b *gcsBucket) objectName(remotePath string) string {
+	return strings.TrimLeft(filepath.ToSlash(remotePath), "/")
+}
+
+func (b *gcsBucket) do(ctx context.Context, method, target string, body io.Reader, contentType string) (*http.Response, error) {
+	req, err := http.NewRequest(method, target, body)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem creating request")
+	}
+	req = req.WithContext(ctx)
+	if b.token != "" {
+		req.Header.Add("Authorization", "Bearer "+b.token)
+	}
+	if contentType != "" {
+		req.Header.Add("Content-Type", contentType)
+	}
+
+	client := util.GetHTTPClient()
+	resp, err := client.Do(req)
+	if err != nil {
+		util.PutHTTPClient(client)
+		return nil, errors.Wrapf(err, "problem making %s request to %s", method, target)
+	}
+	resp.Body = &pooledClientReadCloser{ReadCloser: resp.Body, client: client}
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		_ = resp.Body.Close()
+		return nil, errors.Errorf("%s request to %s failed (status %d): %s",
+			method, target, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+func (b *gcsBucket) Upload(ctx context.Context, localPath, remotePath, contentType string) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return errors.Wrapf(err, "problem opening file %s", localPath)
+	}
+	defer file.Close()
+
+	query := url.Values{}
+	query.Set("uploadType", "media")
+	query.Set("name", b.objectName(remotePath))
+	if acl, ok := gcsPredefinedACLs[b.permissions]; ok {
+		query.Set("predefinedAcl", acl)
+	}
+	if contentType == "" {
+		contentType = defaultContentType
+	}
+
+	resp, err := b.do(ctx, http.MethodPost,
+		fmt.Sprintf("%s/upload/storage/v1/b/%s/o?%s", b.endpoint, url.PathEscape(b.name), query.Encode()),
+		file, contentType)
+	if err != nil {
+		return errors.Wrapf(err, "problem uploading %s to bucket %s", localPath, b.name)
+	}
+	return errors.WithStack(resp.Body.Close())
+}
+
+func (b *gcsBucket) Get(ctx context.Context, remotePath string) (io.ReadCloser, error) {
+	resp, err := b.do(ctx, http.MethodGet,
+		fmt.Sprintf("%s/storage/v1/b/%s/o/%s?alt=media", b.endpoint,
+			url.PathEscape(b.name), url.PathEscape(b.objectName(remotePath))),
+		nil, "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem getting file %s from bucket %s", remotePath, b.name)
+	}
+	return resp.Body, nil
+}
+
+func (b *gcsBucket) Copy(ctx context.Context, remotePath string, dest Bucket, destPath string) error {
+	gcsDest, ok := dest.(*gcsBucket)
+	if !ok || gcsDest.endpoint != b.endpoint {
+		return copyBetweenBuckets(ctx, b, remotePath, dest, destPath)
+	}
+
+	query := url.Values{}
+	if acl, ok := gcsPredefinedACLs[gcsDest.permissions]; ok {
+		query.Set("destinationPredefinedAcl", acl)
+	}
+	resp, err := b.do(ctx, http.MethodPost,
+		fmt.Sprintf("%s/storage/v1/b/%s/o/%s/copyTo/b/%s/o/%s?%s", b.endpoint,
+			url.PathEscape(b.name), url.PathEscape(b.objectName(remotePath)),
+			url.PathEscape(gcsDest.name), url.PathEscape(gcsDest.objectName(destPath)),
+			query.Encode()),
+		nil, "")
+	if err != nil {
+		return errors.Wrapf(err, "problem copying %s/%s to %s/%s", b.name, remotePath, gcsDest.name, destPath)
+	}
+	return errors.WithStack(resp.Body.Close())
+}
+
+func (b *gcsBucket) Link(remotePath string) string {
+	return joinURL(b.linkURL, b.name, b.objectName(remotePath))
+}
+
+type gcsObjectList struct {
+	Items []struct {
+		Name    string    `json:"name"`
+		Size    string    `json:"size"`
+		Updated time.Time `json:"updated"`
+	} `json:"items"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+func (b *gcsBucket) List(ctx context.Context, prefix string) ([]BucketItem, error) {
+	items := []BucketItem{}
+	query := url.Values{}
+	query.Set("prefix", b.objectName(prefix))
+	for {
+		resp, err := b.do(ctx, http.MethodGet,
+			fmt.Sprintf("%s/storage/v1/b/%s/o?%s", b.endpoint, url.PathEscape(b.name), query.Encode()),
+			nil, "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem listing bucket %s", b.name)
+		}
+
+		list := gcsObjectList{}
+		err = json.NewDecoder(resp.Body).Decode(&list)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem reading listing of bucket %s", b.name)
+		}
+		for _, object := range list.Items {
+			size, err := strconv.ParseInt(object.Size, 10, 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "problem parsing size of %s", object.Name)
+			}
+			items = append(items, BucketItem{Path: object.Name, Size: size, LastModified: object.Updated})
+		}
+
+		if list.NextPageToken == "" {
+			return items, nil
+		}
+		query.Set("pageToken", list.NextPageToken)
+	}
+}
@@ -0,0 +1,245 @@
+package thirdparty
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBucket(t *testing.T) {
+	assert := assert.New(t)
+
+	b, err := NewBucket(BucketOptions{Name: "bucket"}, nil)
+	assert.NoError(err)
+	assert.IsType(&s3Bucket{}, b)
+	assert.Equal("https://s3.amazonaws.com/bucket/dir/file.txt", b.Link("dir/file.txt"))
+
+	_, err = NewBucket(BucketOptions{Provider: evergreen.BucketProviderFilesystem, Name: "bucket"}, nil)
+	assert.Error(err, "filesystem buckets need a root directory")
+	_, err = NewBucket(BucketOptions{Provider: evergreen.BucketProviderGCS, Name: "bucket"}, nil)
+	assert.Error(err, "gcs buckets need an endpoint")
+	_, err = NewBucket(BucketOptions{Provider: "floppy", Name: "bucket"}, nil)
+	assert.Error(err)
+	_, err = NewBucket(BucketOptions{}, nil)
+	assert.Error(err)
+
+	conf := &evergreen.BucketsConfig{
+		Filesystem: evergreen.FilesystemBucketConfig{Root: "/srv", LinkBaseURL: "https://files.example.com/"},
+		GCS:        evergreen.GCSBucketConfig{Endpoint: "https://storage.example.com"},
+	}
+	b, err = NewBucket(BucketOptions{Provider: evergreen.BucketProviderFilesystem, Name: "bucket"}, conf)
+	assert.NoError(err)
+	assert.Equal("https://files.example.com/bucket/dir/a%20file.txt", b.Link("dir/a file.txt"))
+
+	for _, name := range []string{"..", "../../etc", "a/b", `a\b`, ".", "a..b"} {
+		for _, provider := range []string{evergreen.BucketProviderFilesystem, evergreen.BucketProviderS3, evergreen.BucketProviderGCS} {
+			_, err = NewBucket(BucketOptions{Provider: provider, Name: name}, conf)
+			assert.Error(err, "%s bucket '%s'", provider, name)
+		}
+	}
+
+	b, err = NewBucket(BucketOptions{Provider: evergreen.BucketProviderGCS, Name: "bucket"}, conf)
+	assert.NoError(err)
+	assert.Equal("https://storage.example.com/bucket/dir/file.txt", b.Link("/dir/file.txt"))
+}
+
+func TestFilesystemBucket(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	root, err := ioutil.TempDir("", "filesystem-bucket")
+	require.NoError(err)
+	defer os.RemoveAll(root)
+
+	local := filepath.Join(root, "local.txt")
+	require.NoError(ioutil.WriteFile(local, []byte("artifact"), 0644))
+
+	conf := &evergreen.BucketsConfig{Filesystem: evergreen.FilesystemBucketConfig{Root: filepath.Join(root, "store")}}
+	src, err := NewBucket(BucketOptions{Provider: evergreen.BucketProviderFilesystem, Name: "staging"}, conf)
+	require.NoError(err)
+	dest, err := NewBucket(BucketOptions{Provider: evergreen.BucketProviderFilesystem, Name: "release"}, conf)
+	require.NoError(err)
+
+	require.NoError(src.Upload(ctx, local, "builds/1/artifact.txt", "text/plain"))
+	data, err := ioutil.ReadFile(filepath.Join(root, "store", "staging", "builds", "1", "artifact.txt"))
+	require.NoError(err)
+	assert.Equal("artifact", string(data))
+	assert.True(strings.HasPrefix(src.Link("builds/1/artifact.txt"), "file://"))
+
+	reader, err := src.Get(ctx, "builds/1/artifact.txt")
+	require.NoError(err)
+	data, err = ioutil.ReadAll(reader)
+	assert.NoError(err)
+	assert.NoError(reader.Close())
+	assert.Equal("artifact", string(data))
+
+	require.NoError(src.Copy(ctx, "builds/1/artifact.txt", dest, "artifact.txt"))
+	data, err = ioutil.ReadFile(filepath.Join(root, "store", "release", "artifact.txt"))
+	require.NoError(err)
+	assert.Equal("artifact", string(data))
+
+	items, err := src.List(ctx, "builds/")
+	require.NoError(err)
+	require.Len(items, 1)
+	assert.Equal("builds/1/artifact.txt", items[0].Path)
+	assert.EqualValues(len("artifact"), items[0].Size)
+	items, err = dest.List(ctx, "builds/")
+	require.NoError(err)
+	assert.Len(items, 0)
+	empty, err := NewBucket(BucketOptions{Provider: evergreen.BucketProviderFilesystem, Name: "empty"}, conf)
+	require.NoError(err)
+	items, err = empty.List(ctx, "")
+	assert.NoError(err, "listing a bucket with no files should not fail")
+	assert.Len(items, 0)
+
+	_, err = src.Get(ctx, "missing.txt")
+	assert.Error(err)
+	err = src.Upload(ctx, filepath.Join(root, "missing.txt"), "missing.txt", "text/plain")
+	assert.True(os.IsNotExist(errors.Cause(err)))
+
+	// paths cannot escape the bucket's directory
+	require.NoError(src.Upload(ctx, local, "../../escape.txt", "text/plain"))
+	_, err = os.Stat(filepath.Join(root, "escape.txt"))
+	assert.True(os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(root, "store", "staging", "escape.txt"))
+	assert.NoError(err)
+}
+
+// gcsStandIn is a minimal in-memory implementation of the parts of the GCS
+// JSON API that buckets use.
+type gcsStandIn struct {
+	mu      sync.Mutex
+	objects map[string]string
+	acls    map[string]string
+}
+
+func (s *gcsStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.EscapedPath(), "/"), "/")
+	switch {
+	case r.Method == http.MethodPost && len(parts) == 6 && parts[0] == "upload":
+		data, _ := ioutil.ReadAll(r.Body)
+		key := parts[4] + "/" + r.URL.Query().Get("name")
+		s.objects[key] = string(data)
+		s.acls[key] = r.URL.Query().Get("predefinedAcl")
+	case r.Method == http.MethodGet && len(parts) == 6 && r.URL.Query().Get("alt") == "media":
+		object, _ := url.PathUnescape(parts[5])
+		data, ok := s.objects[parts[3]+"/"+object]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(data))
+		return
+	case r.Method == http.MethodGet && len(parts) == 5:
+		prefix := parts[3] + "/" + r.URL.Query().Get("prefix")
+		list := []string{}
+		for key, data := range s.objects {
+			if strings.HasPrefix(key, prefix) {
+				list = append(list, fmt.Sprintf(`{"name": %q, "size": "%d", "updated": "2018-06-01T12:00:00.000Z"}`,
+					strings.TrimPrefix(key, parts[3]+"/"), len(data)))
+			}
+		}
+		_, _ = w.Write([]byte(`{"items": [` + strings.Join(list, ",") + `]}`))
+		return
+	case r.Method == http.MethodPost && len(parts) == 11 && parts[6] == "copyTo":
+		srcObject, _ := url.PathUnescape(parts[5])
+		destObject, _ := url.PathUnescape(parts[10])
+		data, ok := s.objects[parts[3]+"/"+srcObject]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		s.objects[parts[8]+"/"+destObject] = data
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	_, _ = w.Write([]byte("{}"))
+}
+
+func TestGCSBucket(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	standIn := &gcsStandIn{objects: map[string]string{}, acls: map[string]string{}}
+	server := httptest.NewServer(standIn)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "gcs-bucket")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	local := filepath.Join(dir, "local.txt")
+	require.NoError(ioutil.WriteFile(local, []byte("artifact"), 0644))
+
+	conf := &evergreen.BucketsConfig{GCS: evergreen.GCSBucketConfig{Endpoint: server.URL}}
+	src, err := NewBucket(BucketOptions{
+		Provider:    evergreen.BucketProviderGCS,
+		Name:        "staging",
+		Secret:      "token",
+		Permissions: "public-read",
+	}, conf)
+	require.NoError(err)
+	dest, err := NewBucket(BucketOptions{Provider: evergreen.BucketProviderGCS, Name: "release", Secret: "token"}, conf)
+	require.NoError(err)
+
+	require.NoError(src.Upload(ctx, local, "builds/1/artifact.txt", "text/plain"))
+	assert.Equal("artifact", standIn.objects["staging/builds/1/artifact.txt"])
+	assert.Equal("publicRead", standIn.acls["staging/builds/1/artifact.txt"])
+	assert.Equal(server.URL+"/staging/builds/1/artifact.txt", src.Link("builds/1/artifact.txt"))
+
+	reader, err := src.Get(ctx, "builds/1/artifact.txt")
+	require.NoError(err)
+	data, err := ioutil.ReadAll(reader)
+	assert.NoError(err)
+	assert.NoError(reader.Close())
+	assert.Equal("artifact", string(data))
+
+	require.NoError(src.Copy(ctx, "builds/1/artifact.txt", dest, "artifact.txt"))
+	assert.Equal("artifact", standIn.objects["release/artifact.txt"])
+
+	items, err := src.List(ctx, "builds/")
+	require.NoError(err)
+	require.Len(items, 1)
+	assert.Equal("builds/1/artifact.txt", items[0].Path)
+	assert.EqualValues(len("artifact"), items[0].Size)
+	assert.Equal(2018, items[0].LastModified.Year())
+
+	_, err = src.Get(ctx, "missing.txt")
+	assert.Error(err)
+
+	unauthorized, err := NewBucket(BucketOptions{Provider: evergreen.BucketProviderGCS, Name: "staging"}, conf)
+	require.NoError(err)
+	assert.Error(unauthorized.Upload(ctx, local, "artifact.txt", "text/plain"))
+
+	// copying between backends goes through a temporary file
+	fsConf := &evergreen.BucketsConfig{Filesystem: evergreen.FilesystemBucketConfig{Root: dir}}
+	fs, err := NewBucket(BucketOptions{Provider: evergreen.BucketProviderFilesystem, Name: "local"}, fsConf)
+	require.NoError(err)
+	require.NoError(src.Copy(ctx, "builds/1/artifact.txt", fs, "copied.txt"))
+	data, err = ioutil.ReadFile(filepath.Join(dir, "local", "copied.txt"))
+	require.NoError(err)
+	assert.Equal("artifact", string(data))
+}
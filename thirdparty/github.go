@@ -614,6 +614,28 @@ func GithubUserInOrganization(ctx context.Context, token, requiredOrganization,
 	return isMember, err
 }
 
+// GithubUserHasWriteAccess returns true if the given github user can push
+// to the repository, as an admin or with write access.
+func GithubUserHasWriteAccess(ctx context.Context, token, owner, repo, username string) (bool, error) {
+	httpClient, err := getGithubClient(token)
+	if err != nil {
+		return false, errors.Wrap(err, "can't fetch data from github")
+	}
+	defer util.PutHTTPClient(httpClient)
+
+	client := github.NewClient(httpClient)
+	level, _, err := client.Repositories.GetPermissionLevel(ctx, owner, repo, username)
+	if err != nil {
+		return false, errors.Wrapf(err, "can't get permission level of '%s' on %s/%s", username, owner, repo)
+	}
+	if level == nil {
+		return false, errors.New("empty data received from github")
+	}
+
+	permission := level.GetPermission()
+	return permission == "admin" || permission == "write", nil
+}
+
 // GetPullRequestMergeBase returns the merge base hash for the given PR.
 // This function will retry up to 5 times, regardless of error response (unless
 // error is the result of hitting an api limit)
@@ -722,3 +744,63 @@ func buildPatchURL(gp *patch.GithubPatch) string {
 
 	return url.String()
 }
+
+// GetGithubPullRequest fetches the pull request with the given number.
+func GetGithubPullRequest(ctx context.Context, token, owner, repo string, prNumber int) (*github.PullRequest, error) {
+	httpClient, err := getGithubClient(token)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't fetch data from github")
+	}
+	defer util.PutHTTPClient(httpClient)
+	client := github.NewClient(httpClient)
+
+	pr, _, err := client.PullRequests.Get(ctx, owner, repo, prNumber)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't get pull request %d in '%s/%s'", prNumber, owner, repo)
+	}
+	if pr == nil || pr.Head == nil || pr.Base == nil || pr.User == nil {
+		return nil, errors.New("empty data received from github")
+	}
+
+	return pr, nil
+}
+
+// MergePullRequest merges a pull request with the given merge method,
+// provided the head of the pull request is still at sha.
+func MergePullRequest(ctx context.Context, token, owner, repo string, prNumber int, sha, mergeMethod, commitMessage string) error {
+	httpClient, err := getGithubClient(token)
+	if err != nil {
+		return errors.Wrap(err, "can't fetch data from github")
+	}
+	defer util.PutHTTPClient(httpClient)
+	client := github.NewClient(httpClient)
+
+	res, _, err := client.PullRequests.Merge(ctx, owner, repo, prNumber, commitMessage, &github.PullRequestOptions{
+		SHA:         sha,
+		MergeMethod: mergeMethod,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "can't merge pull request %d in '%s/%s'", prNumber, owner, repo)
+	}
+	if res == nil || res.Merged == nil || !*res.Merged {
+		return errors.Errorf("github did not merge pull request %d in '%s/%s'", prNumber, owner, repo)
+	}
+
+	return nil
+}
+
+// PostCommentToPullRequest adds a comment to a pull request.
+func PostCommentToPullRequest(ctx context.Context, token, owner, repo string, prNumber int, comment string) error {
+	httpClient, err := getGithubClient(token)
+	if err != nil {
+		return errors.Wrap(err, "can't fetch data from github")
+	}
+	defer util.PutHTTPClient(httpClient)
+	client := github.NewClient(httpClient)
+
+	_, _, err = client.Issues.CreateComment(ctx, owner, repo, prNumber, &github.IssueComment{
+		Body: github.String(comment),
+	})
+
+	return errors.Wrapf(err, "can't comment on pull request %d in '%s/%s'", prNumber, owner, repo)
+}
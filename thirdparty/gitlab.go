@@ -0,0 +1,201 @@
+package thirdparty
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultGitlabURL is the GitLab instance used for projects that do
+	// not specify one.
+	DefaultGitlabURL = "https://gitlab.com"
+
+	gitlabPageSize = 100
+)
+
+// GitlabCommit is a commit returned by the GitLab commits API.
+type GitlabCommit struct {
+	ID             string    `json:"id"`
+	Title          string    `json:"title"`
+	Message        string    `json:"message"`
+	AuthorName     string    `json:"author_name"`
+	AuthorEmail    string    `json:"author_email"`
+	CommitterName  string    `json:"committer_name"`
+	CommitterEmail string    `json:"committer_email"`
+	CommittedDate  time.Time `json:"committed_date"`
+	ParentIDs      []string  `json:"parent_ids"`
+}
+
+type gitlabDiff struct {
+	OldPath string `json:"old_path"`
+	NewPath string `json:"new_path"`
+}
+
+// gitlabProjectURL returns the API URL of a project's resource. GitLab
+// identifies projects by their URL-encoded "owner/repo" path.
+func gitlabProjectURL(baseURL, owner, repo, resource string) string {
+	if baseURL == "" {
+		baseURL = DefaultGitlabURL
+	}
+	return fmt.Sprintf("%s/api/v4/projects/%s/%s", strings.TrimRight(baseURL, "/"),
+		url.PathEscape(owner+"/"+repo), resource)
+}
+
+// IsSameGitlabInstance returns whether two GitLab base URLs, where empty
+// means gitlab.com, refer to the same https instance. Credentials for one
+// instance must not be sent to any other.
+func IsSameGitlabInstance(baseURL, otherURL string) bool {
+	normalize := func(s string) (*url.URL, bool) {
+		if s == "" {
+			s = DefaultGitlabURL
+		}
+		u, err := url.Parse(s)
+		if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil {
+			return nil, false
+		}
+		return u, true
+	}
+	base, ok := normalize(baseURL)
+	if !ok {
+		return false
+	}
+	other, ok := normalize(otherURL)
+	if !ok {
+		return false
+	}
+	return strings.EqualFold(base.Host, other.Host) &&
+		strings.TrimRight(base.Path, "/") == strings.TrimRight(other.Path, "/")
+}
+
+// gitlabGet performs an authenticated GET request against the GitLab API,
+// decoding a JSON response into out, or returning the raw body if out is
+// nil. It returns the next page of results, or 0 if there are no more.
+func gitlabGet(ctx context.Context, token, path string, out interface{}) ([]byte, int, error) {
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "problem creating gitlab request")
+	}
+	req = req.WithContext(ctx)
+	if token != "" {
+		req.Header.Add("PRIVATE-TOKEN", token)
+	}
+
+	client := util.GetHTTPClient()
+	defer util.PutHTTPClient(client)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, APIResponseError{fmt.Sprintf("error querying gitlab at '%s': %v", path, err)}
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, ResponseReadError{err.Error()}
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, 0, FileNotFoundError{filepath: path}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, APIRequestError{Message: fmt.Sprintf("gitlab returned %d: %s", resp.StatusCode, string(body))}
+	}
+
+	nextPage := 0
+	if next := resp.Header.Get("X-Next-Page"); next != "" {
+		if nextPage, err = strconv.Atoi(next); err != nil {
+			return nil, 0, APIResponseError{fmt.Sprintf("invalid next page '%s' from gitlab", next)}
+		}
+	}
+
+	if out != nil {
+		if err = json.Unmarshal(body, out); err != nil {
+			return nil, 0, APIUnmarshalError{string(body), err.Error()}
+		}
+	}
+	return body, nextPage, nil
+}
+
+// GetGitlabCommits returns a page of the commits on a branch of a GitLab
+// project, most recent first, and the next page, or 0 if there are no more.
+func GetGitlabCommits(ctx context.Context, baseURL, token, owner, repo, branch string, page int) ([]GitlabCommit, int, error) {
+	if page == 0 {
+		page = 1
+	}
+	query := url.Values{}
+	query.Set("ref_name", branch)
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(gitlabPageSize))
+
+	commits := []GitlabCommit{}
+	_, next, err := gitlabGet(ctx, token, gitlabProjectURL(baseURL, owner, repo, "repository/commits?"+query.Encode()), &commits)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "problem fetching commits for '%s/%s'", owner, repo)
+	}
+	return commits, next, nil
+}
+
+// GetGitlabFile returns the contents of a file in a GitLab project at a
+// revision.
+func GetGitlabFile(ctx context.Context, baseURL, token, owner, repo, path, revision string) ([]byte, error) {
+	resource := fmt.Sprintf("repository/files/%s/raw?ref=%s", url.PathEscape(path), url.QueryEscape(revision))
+	file, _, err := gitlabGet(ctx, token, gitlabProjectURL(baseURL, owner, repo, resource), nil)
+	if err != nil {
+		if IsFileNotFound(err) {
+			return nil, FileNotFoundError{filepath: path}
+		}
+		return nil, errors.Wrapf(err, "problem fetching '%s' from '%s/%s'", path, owner, repo)
+	}
+	return file, nil
+}
+
+// GetGitlabChangedFiles returns the paths of the files a commit in a GitLab
+// project modified.
+func GetGitlabChangedFiles(ctx context.Context, baseURL, token, owner, repo, revision string) ([]string, error) {
+	files := []string{}
+	for page := 1; page != 0; {
+		query := url.Values{}
+		query.Set("page", strconv.Itoa(page))
+		query.Set("per_page", strconv.Itoa(gitlabPageSize))
+
+		diffs := []gitlabDiff{}
+		var err error
+		resource := fmt.Sprintf("repository/commits/%s/diff?%s", url.PathEscape(revision), query.Encode())
+		if _, page, err = gitlabGet(ctx, token, gitlabProjectURL(baseURL, owner, repo, resource), &diffs); err != nil {
+			return nil, errors.Wrapf(err, "problem fetching diff of '%s' in '%s/%s'", revision, owner, repo)
+		}
+		for _, d := range diffs {
+			files = append(files, d.NewPath)
+			if d.OldPath != "" && d.OldPath != d.NewPath {
+				files = append(files, d.OldPath)
+			}
+		}
+	}
+	return files, nil
+}
+
+// GetGitlabMergeBaseRevision returns the merge base of two revisions in a
+// GitLab project.
+func GetGitlabMergeBaseRevision(ctx context.Context, baseURL, token, owner, repo, baseRevision, currentRevision string) (string, error) {
+	query := url.Values{}
+	query.Add("refs[]", baseRevision)
+	query.Add("refs[]", currentRevision)
+
+	commit := GitlabCommit{}
+	if _, _, err := gitlabGet(ctx, token, gitlabProjectURL(baseURL, owner, repo, "repository/merge_base?"+query.Encode()), &commit); err != nil {
+		return "", errors.Wrapf(err, "problem finding merge base of '%s' and '%s'", baseRevision, currentRevision)
+	}
+	if commit.ID == "" {
+		return "", APIRequestError{Message: "missing data from gitlab merge base response"}
+	}
+	return commit.ID, nil
+}
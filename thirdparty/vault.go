@@ -0,0 +1,102 @@
+package thirdparty
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+// VaultClient reads secrets from the version 2 key/value secrets engine of
+// a Vault-compatible secret store over its HTTP API.
+type VaultClient struct {
+	url     string
+	token   string
+	mount   string
+	timeout time.Duration
+}
+
+// NewVaultClient returns a client for the secret store at the given URL,
+// which authenticates with the token and reads from the secrets engine
+// mounted at the given path.
+func NewVaultClient(url, token, mount string, timeout time.Duration) *VaultClient {
+	return &VaultClient{
+		url:     strings.TrimRight(url, "/"),
+		token:   token,
+		mount:   strings.Trim(mount, "/"),
+		timeout: timeout,
+	}
+}
+
+type vaultSecretResponse struct {
+	Data struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+// ReadSecret returns the latest version of the key/value pairs stored at
+// the given path.
+func (c *VaultClient) ReadSecret(ctx context.Context, path string) (map[string]string, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	path = strings.Trim(path, "/")
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return nil, errors.Errorf("invalid secret path '%s'", path)
+		}
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/data/%s", c.url, c.mount, path)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem creating request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Add("X-Vault-Token", c.token)
+
+	client := util.GetHTTPClient()
+	defer util.PutHTTPClient(client)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem reading secret '%s'", path)
+	}
+	defer resp.Body.Close()
+
+	out := vaultSecretResponse{}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.Errorf("secret '%s' not found", path)
+	}
+	if err = util.ReadJSONInto(resp.Body, &out); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, errors.Wrapf(err, "problem parsing secret '%s'", path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("problem reading secret '%s' (status %d): %s",
+			path, resp.StatusCode, strings.Join(out.Errors, "; "))
+	}
+
+	secret := make(map[string]string, len(out.Data.Data))
+	for key, value := range out.Data.Data {
+		switch v := value.(type) {
+		case string:
+			secret[key] = v
+		default:
+			raw, err := json.Marshal(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "problem reading key '%s' of secret '%s'", key, path)
+			}
+			secret[key] = string(raw)
+		}
+	}
+	return secret, nil
+}
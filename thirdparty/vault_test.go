@@ -0,0 +1,53 @@
+package thirdparty
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultClientReadSecret(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{"permission denied"}})
+			return
+		}
+		if r.URL.Path != "/v1/secret/data/evergreen/proj" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"data":     map[string]interface{}{"password": "hunter2", "port": 5432},
+				"metadata": map[string]interface{}{"version": 3},
+			},
+		})
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := NewVaultClient(server.URL+"/", "token", "/secret/", time.Second)
+	secret, err := client.ReadSecret(ctx, "evergreen/proj")
+	require.NoError(err)
+	assert.Equal(map[string]string{"password": "hunter2", "port": "5432"}, secret)
+
+	_, err = client.ReadSecret(ctx, "evergreen/missing")
+	assert.Error(err)
+	_, err = client.ReadSecret(ctx, "evergreen/other/../proj")
+	assert.Error(err)
+
+	client = NewVaultClient(server.URL, "wrong", "secret", time.Second)
+	_, err = client.ReadSecret(ctx, "evergreen/proj")
+	require.Error(err)
+	assert.Contains(err.Error(), "permission denied")
+}
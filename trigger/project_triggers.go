@@ -0,0 +1,233 @@
+package trigger
+
+import (
+	"regexp"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+// ProjectProcessor handles one of a downstream project's trigger
+// definitions that an event fired, given the upstream version, the level and
+// id of the upstream version, build, or task that fired the trigger, and the
+// id of the event.
+type ProjectProcessor func(ref *model.ProjectRef, def *model.TriggerDefinition, upstream *version.Version,
+	triggerType, triggerID, eventID string) error
+
+// upstreamOutcome is the version, build, or task that an event reports has
+// finished.
+type upstreamOutcome struct {
+	level   string
+	id      string
+	status  string
+	project string
+	variant string
+	task    string
+	version string
+}
+
+// EvalProjectTriggers finds the triggers of the given downstream projects
+// that the outcome an event reports matches, and passes them to the
+// processor. Callers find the projects with triggers once for many events,
+// so that events no trigger can match are skipped without reading the
+// upstream version, build, or task. Only versions the repotracker or a
+// version schedule created can fire triggers, so that patches and triggered
+// versions never do.
+func EvalProjectTriggers(e *event.EventLogEntry, refs []model.ProjectRef, processor ProjectProcessor) error {
+	if !anyTriggerAtLevel(refs, eventLevel(e)) {
+		return nil
+	}
+
+	outcome, err := getUpstreamOutcome(e)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if outcome == nil {
+		return nil
+	}
+
+	type match struct {
+		ref *model.ProjectRef
+		def *model.TriggerDefinition
+	}
+	matches := []match{}
+	catcher := grip.NewBasicCatcher()
+	for i := range refs {
+		for j := range refs[i].Triggers {
+			def := &refs[i].Triggers[j]
+			ok, err := triggerMatches(def, outcome)
+			if err != nil {
+				catcher.Add(errors.Wrapf(err, "problem evaluating trigger '%s' in project '%s'", def.ID, refs[i].Identifier))
+				continue
+			}
+			if ok {
+				matches = append(matches, match{ref: &refs[i], def: def})
+			}
+		}
+	}
+	if len(matches) == 0 {
+		return catcher.Resolve()
+	}
+
+	upstream, err := version.FindOne(version.ById(outcome.version))
+	if err != nil {
+		return errors.Wrapf(err, "problem finding version '%s'", outcome.version)
+	}
+	if upstream == nil {
+		return errors.Errorf("version '%s' not found", outcome.version)
+	}
+	if upstream.Requester != evergreen.RepotrackerVersionRequester && upstream.Requester != evergreen.ScheduledVersionRequester {
+		return catcher.Resolve()
+	}
+
+	for _, m := range matches {
+		if err = processor(m.ref, m.def, upstream, outcome.level, outcome.id, e.ID); err != nil {
+			catcher.Add(errors.Wrapf(err, "problem processing trigger '%s' in project '%s'", m.def.ID, m.ref.Identifier))
+		}
+	}
+
+	return catcher.Resolve()
+}
+
+// eventLevel returns the level of the triggers that an event can fire, or
+// the empty string if it can fire none.
+func eventLevel(e *event.EventLogEntry) string {
+	switch {
+	case e.ResourceType == event.ResourceTypeVersion && e.EventType == event.VersionStateChange:
+		return model.ProjectTriggerLevelVersion
+	case e.ResourceType == event.ResourceTypeBuild && e.EventType == event.BuildStateChange:
+		return model.ProjectTriggerLevelBuild
+	case e.ResourceType == event.ResourceTypeTask && e.EventType == event.TaskFinished:
+		return model.ProjectTriggerLevelTask
+	}
+	return ""
+}
+
+func anyTriggerAtLevel(refs []model.ProjectRef, level string) bool {
+	if level == "" {
+		return false
+	}
+	for _, ref := range refs {
+		for _, def := range ref.Triggers {
+			if def.Level == level {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func getUpstreamOutcome(e *event.EventLogEntry) (*upstreamOutcome, error) {
+	switch {
+	case e.ResourceType == event.ResourceTypeVersion && e.EventType == event.VersionStateChange:
+		data, ok := e.Data.(*event.VersionEventData)
+		if !ok {
+			return nil, errors.Errorf("version '%s' contains unexpected data with type '%T'", e.ResourceId, e.Data)
+		}
+		v, err := version.FindOne(version.ById(e.ResourceId).WithFields(version.IdentifierKey))
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem finding version '%s'", e.ResourceId)
+		}
+		if v == nil {
+			return nil, errors.Errorf("version '%s' not found", e.ResourceId)
+		}
+		return &upstreamOutcome{
+			level:   model.ProjectTriggerLevelVersion,
+			id:      v.Id,
+			status:  data.Status,
+			project: v.Identifier,
+			version: v.Id,
+		}, nil
+
+	case e.ResourceType == event.ResourceTypeBuild && e.EventType == event.BuildStateChange:
+		data, ok := e.Data.(*event.BuildEventData)
+		if !ok {
+			return nil, errors.Errorf("build '%s' contains unexpected data with type '%T'", e.ResourceId, e.Data)
+		}
+		b, err := build.FindOneId(e.ResourceId)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem finding build '%s'", e.ResourceId)
+		}
+		if b == nil {
+			return nil, errors.Errorf("build '%s' not found", e.ResourceId)
+		}
+		return &upstreamOutcome{
+			level:   model.ProjectTriggerLevelBuild,
+			id:      b.Id,
+			status:  data.Status,
+			project: b.Project,
+			variant: b.BuildVariant,
+			version: b.Version,
+		}, nil
+
+	case e.ResourceType == event.ResourceTypeTask && e.EventType == event.TaskFinished:
+		data, ok := e.Data.(*event.TaskEventData)
+		if !ok {
+			return nil, errors.Errorf("task '%s' contains unexpected data with type '%T'", e.ResourceId, e.Data)
+		}
+		t, err := task.FindOneIdOldOrNew(e.ResourceId, data.Execution)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem finding task '%s'", e.ResourceId)
+		}
+		if t == nil {
+			return nil, errors.Errorf("task '%s' not found", e.ResourceId)
+		}
+		// the display task, rather than its execution tasks, is the task
+		// users see finish
+		if t.IsPartOfDisplay() {
+			return nil, nil
+		}
+		return &upstreamOutcome{
+			level:   model.ProjectTriggerLevelTask,
+			id:      t.Id,
+			status:  data.Status,
+			project: t.Project,
+			variant: t.BuildVariant,
+			task:    t.DisplayName,
+			version: t.Version,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+func triggerMatches(def *model.TriggerDefinition, outcome *upstreamOutcome) (bool, error) {
+	if def.Project != outcome.project || def.Level != outcome.level {
+		return false, nil
+	}
+
+	status := def.Status
+	if status == "" {
+		status = evergreen.VersionSucceeded
+	}
+	if status != outcome.status {
+		return false, nil
+	}
+
+	if def.BuildVariantRegex != "" {
+		matched, err := regexp.MatchString(def.BuildVariantRegex, outcome.variant)
+		if err != nil {
+			return false, errors.Wrap(err, "invalid variant regex")
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	if def.TaskRegex != "" {
+		matched, err := regexp.MatchString(def.TaskRegex, outcome.task)
+		if err != nil {
+			return false, errors.Wrap(err, "invalid task regex")
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,133 @@
+package trigger
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriggerMatches(t *testing.T) {
+	assert := assert.New(t)
+
+	outcome := &upstreamOutcome{
+		level:   model.ProjectTriggerLevelTask,
+		id:      "t1",
+		status:  evergreen.TaskSucceeded,
+		project: "upstream",
+		variant: "ubuntu1604",
+		task:    "compile",
+		version: "v1",
+	}
+
+	for _, test := range []struct {
+		def     model.TriggerDefinition
+		matches bool
+	}{
+		{def: model.TriggerDefinition{Project: "upstream", Level: model.ProjectTriggerLevelTask}, matches: true},
+		{def: model.TriggerDefinition{Project: "upstream", Level: model.ProjectTriggerLevelTask, Status: evergreen.TaskSucceeded}, matches: true},
+		{def: model.TriggerDefinition{Project: "upstream", Level: model.ProjectTriggerLevelTask, Status: evergreen.TaskFailed}, matches: false},
+		{def: model.TriggerDefinition{Project: "upstream", Level: model.ProjectTriggerLevelVersion}, matches: false},
+		{def: model.TriggerDefinition{Project: "other", Level: model.ProjectTriggerLevelTask}, matches: false},
+		{def: model.TriggerDefinition{Project: "upstream", Level: model.ProjectTriggerLevelTask, TaskRegex: "^comp"}, matches: true},
+		{def: model.TriggerDefinition{Project: "upstream", Level: model.ProjectTriggerLevelTask, TaskRegex: "^lint$"}, matches: false},
+		{def: model.TriggerDefinition{Project: "upstream", Level: model.ProjectTriggerLevelTask, BuildVariantRegex: "ubuntu.*", TaskRegex: "compile"}, matches: true},
+		{def: model.TriggerDefinition{Project: "upstream", Level: model.ProjectTriggerLevelTask, BuildVariantRegex: "windows"}, matches: false},
+	} {
+		matches, err := triggerMatches(&test.def, outcome)
+		assert.NoError(err)
+		assert.Equal(test.matches, matches, "%+v", test.def)
+	}
+
+	_, err := triggerMatches(&model.TriggerDefinition{Project: "upstream", Level: model.ProjectTriggerLevelTask, TaskRegex: "("}, outcome)
+	assert.Error(err)
+}
+
+func TestEvalProjectTriggers(t *testing.T) {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(model.ProjectRefCollection, version.Collection, build.Collection, task.Collection))
+
+	downstream := model.ProjectRef{
+		Identifier: "downstream",
+		Enabled:    true,
+		Triggers: []model.TriggerDefinition{
+			{ID: "on-version", Project: "upstream", Level: model.ProjectTriggerLevelVersion},
+			{ID: "on-build", Project: "upstream", Level: model.ProjectTriggerLevelBuild, BuildVariantRegex: "^ubuntu"},
+			{ID: "on-task", Project: "upstream", Level: model.ProjectTriggerLevelTask, TaskRegex: "^dist$"},
+		},
+	}
+
+	upstream := version.Version{Id: "v1", Identifier: "upstream", Requester: evergreen.RepotrackerVersionRequester}
+	require.NoError(upstream.Insert())
+	patchVersion := version.Version{Id: "v2", Identifier: "upstream", Requester: evergreen.PatchVersionRequester}
+	require.NoError(patchVersion.Insert())
+	b := build.Build{Id: "b1", Project: "upstream", BuildVariant: "ubuntu1604", Version: "v1"}
+	require.NoError(b.Insert())
+	tsk := task.Task{Id: "t1", Project: "upstream", BuildVariant: "ubuntu1604", DisplayName: "dist", Version: "v1"}
+	require.NoError(tsk.Insert())
+
+	fired := []string{}
+	processor := func(ref *model.ProjectRef, def *model.TriggerDefinition, v *version.Version, triggerType, triggerID, eventID string) error {
+		assert.Equal("downstream", ref.Identifier)
+		assert.Equal("v1", v.Id)
+		fired = append(fired, def.ID+":"+triggerType+":"+triggerID)
+		return nil
+	}
+	refs := []model.ProjectRef{downstream}
+
+	for _, test := range []struct {
+		e        event.EventLogEntry
+		expected []string
+	}{
+		{
+			e: event.EventLogEntry{ID: "e1", ResourceType: event.ResourceTypeVersion, EventType: event.VersionStateChange, ResourceId: "v1",
+				Data: &event.VersionEventData{Status: evergreen.VersionSucceeded}},
+			expected: []string{"on-version:version:v1"},
+		},
+		{
+			e: event.EventLogEntry{ID: "e2", ResourceType: event.ResourceTypeVersion, EventType: event.VersionStateChange, ResourceId: "v1",
+				Data: &event.VersionEventData{Status: evergreen.VersionFailed}},
+			expected: []string{},
+		},
+		{
+			e: event.EventLogEntry{ID: "e3", ResourceType: event.ResourceTypeVersion, EventType: event.VersionStateChange, ResourceId: "v2",
+				Data: &event.VersionEventData{Status: evergreen.VersionSucceeded}},
+			expected: []string{},
+		},
+		{
+			e: event.EventLogEntry{ID: "e4", ResourceType: event.ResourceTypeBuild, EventType: event.BuildStateChange, ResourceId: "b1",
+				Data: &event.BuildEventData{Status: evergreen.BuildSucceeded}},
+			expected: []string{"on-build:build:b1"},
+		},
+		{
+			e: event.EventLogEntry{ID: "e5", ResourceType: event.ResourceTypeTask, EventType: event.TaskFinished, ResourceId: "t1",
+				Data: &event.TaskEventData{Status: evergreen.TaskSucceeded}},
+			expected: []string{"on-task:task:t1"},
+		},
+	} {
+		fired = []string{}
+		assert.NoError(EvalProjectTriggers(&test.e, refs, processor))
+		assert.Equal(test.expected, fired, test.e.ID)
+	}
+
+	// events that no trigger can match are skipped without reading the
+	// resource they're about
+	fired = []string{}
+	e := event.EventLogEntry{ID: "e6", ResourceType: event.ResourceTypeTask, EventType: event.TaskFinished, ResourceId: "nonexistent",
+		Data: &event.TaskEventData{Status: evergreen.TaskSucceeded}}
+	assert.Error(EvalProjectTriggers(&e, refs, processor))
+	refs[0].Triggers = refs[0].Triggers[:2]
+	assert.NoError(EvalProjectTriggers(&e, refs, processor))
+	assert.NoError(EvalProjectTriggers(&e, nil, processor))
+	assert.Empty(fired)
+}
@@ -95,9 +95,13 @@ func (t *versionTriggers) makeData(sub *event.Subscription, pastTenseOverride st
 		data.PastTenseStatus = "succeeded"
 		slackColor = evergreenSuccessColor
 	}
+	slackTitle := "Evergreen Version"
+	if t.version.Requester == evergreen.ScheduledVersionRequester {
+		slackTitle = "Evergreen Scheduled Version"
+	}
 	data.slack = []message.SlackAttachment{
 		{
-			Title:     "Evergreen Version",
+			Title:     slackTitle,
 			TitleLink: data.URL,
 			Color:     slackColor,
 			Text:      t.version.Message,
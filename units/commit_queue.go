@@ -0,0 +1,420 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/commitqueue"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/evergreen/validator"
+	"github.com/google/go-github/github"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/level"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	commitQueueJobName = "commit-queue"
+
+	// commitQueueStatusContext is the context of the GitHub statuses the
+	// commit queue sets on pull requests.
+	commitQueueStatusContext = "evergreen/commitqueue"
+
+	// commitQueueFinalizeTimeout is how long a patch can go without being
+	// finalized before the commit queue gives up on it.
+	commitQueueFinalizeTimeout = 10 * time.Minute
+)
+
+func init() {
+	registry.AddJobType(commitQueueJobName, func() amboy.Job { return makeCommitQueueJob() })
+}
+
+type commitQueueJob struct {
+	job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+	QueueID  string `bson:"queue_id" json:"queue_id" yaml:"queue_id"`
+
+	env evergreen.Environment
+}
+
+func makeCommitQueueJob() *commitQueueJob {
+	j := &commitQueueJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    commitQueueJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+
+	return j
+}
+
+// NewCommitQueueJob creates a job that advances a project's commit queue: it
+// starts testing the item at the front of the queue, and merges or dequeues
+// the item once its tests finish.
+func NewCommitQueueJob(env evergreen.Environment, queueID string, id string) amboy.Job {
+	j := makeCommitQueueJob()
+	j.QueueID = queueID
+	j.env = env
+	j.SetID(fmt.Sprintf("%s.%s.%s", commitQueueJobName, queueID, id))
+
+	return j
+}
+
+func (j *commitQueueJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+
+	cq, err := commitqueue.FindOneId(j.QueueID)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "can't find commit queue for '%s'", j.QueueID))
+		return
+	}
+	if cq == nil {
+		return
+	}
+	next := cq.Next()
+	if next == nil {
+		return
+	}
+
+	projectRef, err := model.FindOneProjectRef(j.QueueID)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "can't find project ref for '%s'", j.QueueID))
+		return
+	}
+	if projectRef == nil || !projectRef.CommitQueue.Enabled {
+		return
+	}
+
+	githubToken, err := j.env.Settings().GetGithubOauthToken()
+	if err != nil {
+		j.AddError(errors.Wrap(err, "can't get github token"))
+		return
+	}
+
+	prNumber, err := strconv.Atoi(next.Issue)
+	if err != nil {
+		j.dequeue(ctx, cq, projectRef, githubToken, *next, "", fmt.Sprintf("'%s' is not a pull request number", next.Issue))
+		return
+	}
+
+	if next.PatchId == "" {
+		j.startTesting(ctx, cq, projectRef, githubToken, *next, prNumber)
+		return
+	}
+
+	if !bson.IsObjectIdHex(next.PatchId) {
+		j.dequeue(ctx, cq, projectRef, githubToken, *next, "", fmt.Sprintf("patch '%s' is not a valid patch id", next.PatchId))
+		return
+	}
+	patchDoc, err := patch.FindOne(patch.ById(bson.ObjectIdHex(next.PatchId)))
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "can't find patch '%s'", next.PatchId))
+		return
+	}
+	if patchDoc == nil {
+		j.dequeue(ctx, cq, projectRef, githubToken, *next, "", fmt.Sprintf("patch '%s' no longer exists", next.PatchId))
+		return
+	}
+
+	if !patchDoc.Activated {
+		// the job that created the patch may still be finalizing it
+		if time.Since(patchDoc.CreateTime) > commitQueueFinalizeTimeout {
+			j.AddError(model.CancelPatch(patchDoc, evergreen.MergeTestRequester))
+			j.dequeue(ctx, cq, projectRef, githubToken, *next, patchDoc.GithubPatchData.HeadHash,
+				fmt.Sprintf("patch '%s' was never finalized", next.PatchId))
+		}
+		return
+	}
+
+	switch patchDoc.Status {
+	case evergreen.PatchSucceeded:
+		j.merge(ctx, cq, projectRef, githubToken, *next, patchDoc)
+	case evergreen.PatchFailed:
+		j.dequeue(ctx, cq, projectRef, githubToken, *next, patchDoc.GithubPatchData.HeadHash,
+			fmt.Sprintf("tasks failed in %s", j.patchURL(patchDoc)))
+	}
+}
+
+// startTesting creates and finalizes a patch that applies the pull request to
+// the tip of the project's branch.
+func (j *commitQueueJob) startTesting(ctx context.Context, cq *commitqueue.CommitQueue, projectRef *model.ProjectRef, githubToken string, item commitqueue.CommitQueueItem, prNumber int) {
+	pr, err := thirdparty.GetGithubPullRequest(ctx, githubToken, projectRef.Owner, projectRef.Repo, prNumber)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+	if pr.GetState() != "open" {
+		j.dequeue(ctx, cq, projectRef, githubToken, item, pr.Head.GetSHA(), "the pull request is not open")
+		return
+	}
+	if pr.Base.GetRef() != projectRef.Branch {
+		j.dequeue(ctx, cq, projectRef, githubToken, item, pr.Head.GetSHA(),
+			fmt.Sprintf("the pull request targets '%s', not '%s'", pr.Base.GetRef(), projectRef.Branch))
+		return
+	}
+
+	branch, err := thirdparty.GetBranchEvent(ctx, githubToken, projectRef.Owner, projectRef.Repo, projectRef.Branch)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "can't get tip of branch '%s'", projectRef.Branch))
+		return
+	}
+	if branch.Commit == nil || branch.Commit.SHA == nil {
+		j.AddError(errors.Errorf("github returned no commit for branch '%s'", projectRef.Branch))
+		return
+	}
+
+	patchDoc, reason, err := makeCommitQueuePatch(ctx, projectRef, githubToken, pr, *branch.Commit.SHA)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+	if reason != "" {
+		j.dequeue(ctx, cq, projectRef, githubToken, item, pr.Head.GetSHA(), reason)
+		return
+	}
+
+	// the item refers to its patch before the patch is finalized, so that a
+	// failure can't leave the item waiting for a new patch on every run
+	claimed, err := cq.SetPatchId(item.Issue, patchDoc.Id.Hex())
+	if err != nil {
+		j.AddError(err)
+		j.AddError(model.CancelPatch(patchDoc, evergreen.MergeTestRequester))
+		return
+	}
+	if !claimed {
+		// an overlapping job started testing the item first
+		grip.Info(message.Fields{
+			"message":  "another job is already testing commit queue item",
+			"source":   "commit queue",
+			"job":      j.ID(),
+			"project":  projectRef.Identifier,
+			"item":     item.Issue,
+			"patch_id": patchDoc.Id.Hex(),
+		})
+		j.AddError(model.CancelPatch(patchDoc, evergreen.MergeTestRequester))
+		return
+	}
+	if _, err = model.FinalizePatch(ctx, patchDoc, evergreen.MergeTestRequester, githubToken); err != nil {
+		j.AddError(errors.Wrapf(err, "can't finalize patch for pull request %d", prNumber))
+		j.AddError(model.CancelPatch(patchDoc, evergreen.MergeTestRequester))
+		item.PatchId = patchDoc.Id.Hex()
+		j.dequeue(ctx, cq, projectRef, githubToken, item, pr.Head.GetSHA(),
+			fmt.Sprintf("Evergreen could not create a version to test it: %s", err.Error()))
+		return
+	}
+
+	grip.Info(message.Fields{
+		"message":  "commit queue started testing item",
+		"source":   "commit queue",
+		"job":      j.ID(),
+		"project":  projectRef.Identifier,
+		"item":     item.Issue,
+		"patch_id": patchDoc.Id.Hex(),
+		"base":     patchDoc.Githash,
+	})
+
+	j.AddError(thirdparty.PostCommentToPullRequest(ctx, githubToken, projectRef.Owner, projectRef.Repo, prNumber,
+		fmt.Sprintf("Evergreen is testing this pull request on top of %s@%s: %s",
+			projectRef.Branch, patchDoc.Githash, j.patchURL(patchDoc))))
+}
+
+// makeCommitQueuePatch builds and inserts a patch for the pull request based
+// on the given revision. If the pull request cannot be tested, it returns
+// the reason to dequeue it.
+func makeCommitQueuePatch(ctx context.Context, projectRef *model.ProjectRef, githubToken string, pr *github.PullRequest, baseHash string) (*patch.Patch, string, error) {
+	u, err := findEvergreenUserForPR(int(pr.User.GetID()))
+	if err != nil {
+		return nil, "", errors.Wrap(err, "can't find user for pull request")
+	}
+
+	patchDoc := &patch.Patch{
+		Id:      bson.NewObjectId(),
+		Project: projectRef.Identifier,
+		Githash: baseHash,
+		Author:  u.Id,
+		Description: fmt.Sprintf("Commit Queue Merge: '%s/%s' pull request #%d by %s: %s",
+			projectRef.Owner, projectRef.Repo, pr.GetNumber(), pr.User.GetLogin(), pr.GetTitle()),
+		Status:     evergreen.PatchCreated,
+		CreateTime: time.Now(),
+		Alias:      patch.CommitQueueAlias,
+		GithubPatchData: patch.GithubPatch{
+			PRNumber:   pr.GetNumber(),
+			BaseOwner:  projectRef.Owner,
+			BaseRepo:   projectRef.Repo,
+			BaseBranch: projectRef.Branch,
+			HeadHash:   pr.Head.GetSHA(),
+			Author:     pr.User.GetLogin(),
+			AuthorUID:  int(pr.User.GetID()),
+		},
+	}
+	if pr.Head.Repo != nil {
+		patchDoc.GithubPatchData.HeadOwner = pr.Head.Repo.Owner.GetLogin()
+		patchDoc.GithubPatchData.HeadRepo = pr.Head.Repo.GetName()
+	}
+
+	diff, summaries, err := thirdparty.GetGithubPullRequestDiff(ctx, githubToken, &patchDoc.GithubPatchData)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "can't get pull request diff")
+	}
+	patchFileID := fmt.Sprintf("%s_%s", patchDoc.Id.Hex(), patchDoc.Githash)
+	if err = db.WriteGridFile(patch.GridFSPrefix, patchFileID, strings.NewReader(diff)); err != nil {
+		return nil, "", errors.Wrap(err, "failed to write patch file to db")
+	}
+	patchDoc.Patches = []patch.ModulePatch{
+		{
+			Githash: baseHash,
+			PatchSet: patch.PatchSet{
+				PatchFileId: patchFileID,
+				Summary:     summaries,
+			},
+		},
+	}
+
+	project, err := validator.GetPatchedProject(ctx, patchDoc, githubToken)
+	if err != nil {
+		return nil, fmt.Sprintf("the patched project configuration is invalid: %s", err.Error()), nil
+	}
+	projectYamlBytes, err := yaml.Marshal(project)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "error marshaling patched config")
+	}
+	patchDoc.PatchedConfig = string(projectYamlBytes)
+
+	project.BuildProjectTVPairs(patchDoc, patch.CommitQueueAlias)
+	if len(patchDoc.Tasks) == 0 && len(patchDoc.BuildVariants) == 0 {
+		return nil, fmt.Sprintf("the '%s' alias selects no tasks", patch.CommitQueueAlias), nil
+	}
+
+	patchDoc.PatchNumber, err = u.IncPatchNumber()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "error computing patch num")
+	}
+	if err = patchDoc.Insert(); err != nil {
+		return nil, "", errors.Wrap(err, "can't insert patch")
+	}
+	event.LogPatchStateChangeEvent(patchDoc.Id.Hex(), patchDoc.Status)
+
+	return patchDoc, "", nil
+}
+
+// merge merges the tested pull request, provided it has not changed since
+// its patch was created, and removes it from the queue.
+func (j *commitQueueJob) merge(ctx context.Context, cq *commitqueue.CommitQueue, projectRef *model.ProjectRef, githubToken string, item commitqueue.CommitQueueItem, patchDoc *patch.Patch) {
+	data := patchDoc.GithubPatchData
+	pr, err := thirdparty.GetGithubPullRequest(ctx, githubToken, data.BaseOwner, data.BaseRepo, data.PRNumber)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+	if pr.Head.GetSHA() != data.HeadHash {
+		j.dequeue(ctx, cq, projectRef, githubToken, item, pr.Head.GetSHA(), "the pull request changed while it was being tested")
+		return
+	}
+
+	mergeMethod := projectRef.CommitQueue.MergeMethod
+	if mergeMethod == "" {
+		mergeMethod = model.ValidMergeMethods[0]
+	}
+	if err = thirdparty.MergePullRequest(ctx, githubToken, data.BaseOwner, data.BaseRepo, data.PRNumber, data.HeadHash, mergeMethod, ""); err != nil {
+		j.dequeue(ctx, cq, projectRef, githubToken, item, data.HeadHash, fmt.Sprintf("merging failed: %s", err.Error()))
+		return
+	}
+
+	grip.Info(message.Fields{
+		"message":  "commit queue merged item",
+		"source":   "commit queue",
+		"job":      j.ID(),
+		"project":  projectRef.Identifier,
+		"item":     item.Issue,
+		"patch_id": item.PatchId,
+	})
+
+	_, err = cq.Remove(item.Issue)
+	j.AddError(err)
+}
+
+// dequeue removes the item from the queue and tells the pull request why,
+// failing the commit queue status of the pull request's head, if given.
+func (j *commitQueueJob) dequeue(ctx context.Context, cq *commitqueue.CommitQueue, projectRef *model.ProjectRef, githubToken string, item commitqueue.CommitQueueItem, headSHA, reason string) {
+	grip.Info(message.Fields{
+		"message":  "commit queue removed item",
+		"source":   "commit queue",
+		"job":      j.ID(),
+		"project":  projectRef.Identifier,
+		"item":     item.Issue,
+		"patch_id": item.PatchId,
+		"reason":   reason,
+	})
+
+	if _, err := cq.Remove(item.Issue); err != nil {
+		j.AddError(err)
+		return
+	}
+
+	if headSHA != "" {
+		j.AddError(j.sendFailureStatus(projectRef, headSHA, reason))
+	}
+
+	prNumber, err := strconv.Atoi(item.Issue)
+	if err != nil {
+		return
+	}
+	j.AddError(thirdparty.PostCommentToPullRequest(ctx, githubToken, projectRef.Owner, projectRef.Repo, prNumber,
+		fmt.Sprintf("Evergreen removed this pull request from the commit queue: %s", reason)))
+}
+
+// sendFailureStatus marks the commit queue status of a pull request's head
+// as failed.
+func (j *commitQueueJob) sendFailureStatus(projectRef *model.ProjectRef, headSHA, reason string) error {
+	sender, err := j.env.GetSender(evergreen.SenderGithubStatus)
+	if err != nil {
+		return errors.Wrap(err, "can't get github status sender")
+	}
+
+	// github limits status descriptions to 140 characters
+	if len(reason) > 140 {
+		reason = reason[:137] + "..."
+	}
+	c := message.MakeGithubStatusMessageWithRepo(message.GithubStatus{
+		Owner:       projectRef.Owner,
+		Repo:        projectRef.Repo,
+		Ref:         headSHA,
+		Context:     commitQueueStatusContext,
+		State:       message.GithubStateFailure,
+		URL:         fmt.Sprintf("%s/waterfall/%s", j.env.Settings().Ui.Url, projectRef.Identifier),
+		Description: reason,
+	})
+	if !c.Loggable() {
+		return errors.Errorf("commit queue status for '%s' is invalid", headSHA)
+	}
+	if err = c.SetPriority(level.Notice); err != nil {
+		return errors.WithStack(err)
+	}
+	sender.Send(c)
+	return nil
+}
+
+func (j *commitQueueJob) patchURL(p *patch.Patch) string {
+	return fmt.Sprintf("%s/version/%s", j.env.Settings().Ui.Url, p.Id.Hex())
+}
@@ -0,0 +1,93 @@
+package units
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/commitqueue"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/mongodb/amboy/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestCommitQueueJob(t *testing.T) {
+	assert := assert.New(t)
+	env := evergreen.GetEnvironment()
+
+	factory, err := registry.GetJobFactory(commitQueueJobName)
+	assert.NoError(err)
+	assert.NotNil(factory)
+
+	j, ok := factory().(*commitQueueJob)
+	assert.True(ok)
+	assert.NotNil(j)
+
+	jOne := NewCommitQueueJob(env, "mci", "id")
+	jTwo := NewCommitQueueJob(env, "mci", "id")
+	jThree := NewCommitQueueJob(env, "evergreen", "id")
+	assert.Equal(jOne.ID(), jTwo.ID())
+	assert.NotEqual(jThree.ID(), jOne.ID())
+	assert.Equal("mci", jOne.(*commitQueueJob).QueueID)
+}
+
+func TestCommitQueueJobDequeuesUnfinalizedPatches(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.ClearCollections(commitqueue.Collection, patch.Collection, model.ProjectRefCollection))
+	env := evergreen.GetEnvironment()
+	require.NoError(env.Configure(ctx, filepath.Join(evergreen.FindEvergreenHome(), testutil.TestDir, testutil.TestSettings), nil))
+
+	projectRef := &model.ProjectRef{
+		Identifier:  "mci",
+		Owner:       "evergreen-ci",
+		Repo:        "evergreen",
+		Branch:      "master",
+		CommitQueue: model.CommitQueueParams{Enabled: true},
+	}
+	require.NoError(projectRef.Insert())
+
+	recent := &patch.Patch{Id: bson.NewObjectId(), Project: "mci", CreateTime: time.Now()}
+	stale := &patch.Patch{Id: bson.NewObjectId(), Project: "mci", CreateTime: time.Now().Add(-time.Hour)}
+	require.NoError(recent.Insert())
+	require.NoError(stale.Insert())
+	require.NoError(commitqueue.InsertQueue(&commitqueue.CommitQueue{
+		ProjectID: "mci",
+		Queue: []commitqueue.CommitQueueItem{
+			{Issue: "1", PatchId: recent.Id.Hex()},
+		},
+	}))
+
+	// a patch that is still being finalized is left alone
+	NewCommitQueueJob(env, "mci", "1").Run(ctx)
+	cq, err := commitqueue.FindOneId("mci")
+	require.NoError(err)
+	require.Len(cq.Queue, 1)
+
+	// but one that was never finalized is removed along with its item
+	require.NoError(db.Clear(commitqueue.Collection))
+	require.NoError(commitqueue.InsertQueue(&commitqueue.CommitQueue{
+		ProjectID: "mci",
+		Queue: []commitqueue.CommitQueueItem{
+			{Issue: "1", PatchId: stale.Id.Hex()},
+		},
+	}))
+	NewCommitQueueJob(env, "mci", "2").Run(ctx)
+	cq, err = commitqueue.FindOneId("mci")
+	require.NoError(err)
+	assert.Empty(cq.Queue)
+	p, err := patch.FindOne(patch.ById(stale.Id))
+	require.NoError(err)
+	assert.Nil(p)
+}
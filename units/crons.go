@@ -72,6 +72,83 @@ func PopulateCatchupJobs(part int) amboy.QueueOperation {
 	}
 }
 
+// putNewJob adds the job to the queue unless a job with its ID is already
+// in the queue. Jobs that an operation creates on several runs are expected
+// to be in the queue already, so only other errors are returned.
+func putNewJob(queue amboy.Queue, j amboy.Job) error {
+	if _, ok := queue.Get(j.ID()); ok {
+		return nil
+	}
+	if err := queue.Put(j); err != nil {
+		// another operation may have added the job since it was checked
+		if _, ok := queue.Get(j.ID()); ok {
+			return nil
+		}
+		return errors.Wrapf(err, "problem adding job '%s'", j.ID())
+	}
+	return nil
+}
+
+// PopulateScheduledVersionJobs enqueues a job for each time in the last
+// window that one of an enabled project's version schedules fired. The
+// window is longer than the interval of the operation so that times are not
+// missed when a run is late. Since jobs are identified by their schedule and
+// fire time, jobs for times that an earlier run saw are skipped, and each
+// time is only handled once.
+func PopulateScheduledVersionJobs(window time.Duration) amboy.QueueOperation {
+	return func(queue amboy.Queue) error {
+		flags, err := evergreen.GetServiceFlags()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if flags.RepotrackerDisabled {
+			grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+				"message": "repotracker is disabled",
+				"impact":  "scheduled versions disabled",
+				"mode":    "degraded",
+			})
+			return nil
+		}
+
+		projects, err := model.FindAllTrackedProjectRefs()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		now := time.Now().UTC()
+		catcher := grip.NewBasicCatcher()
+		for _, proj := range projects {
+			if !proj.Enabled {
+				continue
+			}
+
+			for _, schedule := range proj.Schedules {
+				if schedule.Disabled {
+					continue
+				}
+				cron, err := util.ParseCron(schedule.Cron)
+				if err != nil {
+					catcher.Add(errors.Wrapf(err, "invalid schedule '%s' for project '%s'", schedule.ID, proj.Identifier))
+					continue
+				}
+
+				for fireTime := cron.Next(now.Add(-window)); !fireTime.IsZero() && !fireTime.After(now); fireTime = cron.Next(fireTime) {
+					catcher.Add(putNewJob(queue, NewScheduledVersionJob(proj.Identifier, schedule.ID, fireTime)))
+				}
+			}
+		}
+
+		// the operation's errors are not logged where it runs
+		err = catcher.Resolve()
+		grip.Error(message.WrapError(err, message.Fields{
+			"message":   "problem adding scheduled version jobs",
+			"operation": "scheduled versions",
+		}))
+		return err
+	}
+}
+
 func PopulateRepotrackerPollingJobs(part int) amboy.QueueOperation {
 	return func(queue amboy.Queue) error {
 		flags, err := evergreen.GetServiceFlags()
@@ -146,6 +223,50 @@ func PopulateActivationJobs(part int) amboy.QueueOperation {
 	}
 }
 
+func PopulateFlakyTestDetectionJobs(part int) amboy.QueueOperation {
+	return func(queue amboy.Queue) error {
+		projects, err := model.FindAllTrackedProjectRefs()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		ts := util.RoundPartOfHour(part).Format(tsFormat)
+
+		catcher := grip.NewBasicCatcher()
+		for _, proj := range projects {
+			if !proj.Enabled {
+				continue
+			}
+
+			catcher.Add(queue.Put(NewFlakyTestDetectionJob(proj.Identifier, ts)))
+		}
+
+		return catcher.Resolve()
+	}
+}
+
+func PopulateCommitQueueJobs(env evergreen.Environment) amboy.QueueOperation {
+	return func(queue amboy.Queue) error {
+		projects, err := model.FindAllTrackedProjectRefs()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		ts := util.RoundPartOfMinute(0).Format(tsFormat)
+
+		catcher := grip.NewBasicCatcher()
+		for _, proj := range projects {
+			if !proj.Enabled || !proj.CommitQueue.Enabled {
+				continue
+			}
+
+			catcher.Add(queue.Put(NewCommitQueueJob(env, proj.Identifier, ts)))
+		}
+
+		return catcher.Resolve()
+	}
+}
+
 func PopulateHostMonitoring(env evergreen.Environment) amboy.QueueOperation {
 	const reachabilityCheckInterval = 10 * time.Minute
 
@@ -234,6 +355,28 @@ func PopulateTaskMonitoring() amboy.QueueOperation {
 	}
 }
 
+func PopulateTaskAutoRetryJobs() amboy.QueueOperation {
+	return func(queue amboy.Queue) error {
+		flags, err := evergreen.GetServiceFlags()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if flags.MonitorDisabled {
+			grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+				"message": "monitor is disabled",
+				"impact":  "not retrying tasks automatically",
+				"mode":    "degraded",
+			})
+			return nil
+		}
+
+		ts := util.RoundPartOfMinute(0).Format(tsFormat)
+
+		return queue.Put(NewTaskAutoRetryJob(ts))
+	}
+}
+
 func PopulateHostTerminationJobs(env evergreen.Environment) amboy.QueueOperation {
 	return func(queue amboy.Queue) error {
 		flags, err := evergreen.GetServiceFlags()
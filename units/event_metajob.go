@@ -6,8 +6,10 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/trigger"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/dependency"
@@ -124,6 +126,24 @@ func tryProcessOneEvent(e *event.EventLogEntry) (n []notification.Notification,
 	return n, err
 }
 
+// evalProjectTriggers enqueues a job for each trigger of the given projects
+// that the event fires, which creates the trigger's downstream version.
+func (j *eventMetaJob) evalProjectTriggers(e *event.EventLogEntry, refs []model.ProjectRef) error {
+	err := trigger.EvalProjectTriggers(e, refs, func(ref *model.ProjectRef, def *model.TriggerDefinition, upstream *version.Version,
+		triggerType, triggerID, eventID string) error {
+		return j.q.Put(NewProjectTriggerJob(ref, def, upstream, triggerType, triggerID, eventID))
+	})
+	grip.Error(message.WrapError(err, message.Fields{
+		"job":        eventMetaJobName,
+		"source":     "events-processing",
+		"message":    "errors processing project triggers for event",
+		"event_id":   e.ID,
+		"event_type": e.ResourceType,
+	}))
+
+	return err
+}
+
 func (j *eventMetaJob) dispatchLoop(ctx context.Context) error {
 	// TODO: if this is a perf problem, it could be multithreaded. For now,
 	// we just log time
@@ -132,11 +152,22 @@ func (j *eventMetaJob) dispatchLoop(ctx context.Context) error {
 	catcher := grip.NewSimpleCatcher()
 	notifications := make([][]notification.Notification, len(j.events))
 
+	// finding the projects with triggers once means that events are only
+	// evaluated against triggers if some project has them
+	var triggerRefs []model.ProjectRef
 	var err error
+	if !j.flags.RepotrackerDisabled {
+		triggerRefs, err = model.FindProjectsWithTriggers()
+		catcher.Add(errors.Wrap(err, "problem finding projects with triggers"))
+	}
+
 	for i := range j.events {
 		notifications[i], err = tryProcessOneEvent(&j.events[i])
 		catcher.Add(err)
 		catcher.Add(notification.InsertMany(notifications[i]...))
+		if len(triggerRefs) > 0 {
+			catcher.Add(j.evalProjectTriggers(&j.events[i], triggerRefs))
+		}
 	}
 
 	for idx := range notifications {
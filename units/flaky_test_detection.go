@@ -0,0 +1,57 @@
+package units
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evergreen-ci/evergreen/model/flaky"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+const flakyTestDetectionJobName = "flaky-test-detection"
+
+func init() {
+	registry.AddJobType(flakyTestDetectionJobName, func() amboy.Job {
+		return makeFlakyTestDetectionJob()
+	})
+}
+
+type flakyTestDetectionJob struct {
+	Project  string `bson:"project" json:"project" yaml:"project"`
+	job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+}
+
+func makeFlakyTestDetectionJob() *flakyTestDetectionJob {
+	j := &flakyTestDetectionJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    flakyTestDetectionJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewFlakyTestDetectionJob creates a job that rescores the flakiness of the
+// tests in a project.
+func NewFlakyTestDetectionJob(project string, id string) amboy.Job {
+	j := makeFlakyTestDetectionJob()
+	j.Project = project
+
+	j.SetID(fmt.Sprintf("%s.%s.%s", flakyTestDetectionJobName, project, id))
+	j.SetPriority(-1)
+	return j
+}
+
+func (j *flakyTestDetectionJob) Run(_ context.Context) {
+	defer j.MarkComplete()
+
+	j.AddError(errors.Wrapf(flaky.UpdateProjectScores(j.Project, flaky.DetectionWindow),
+		"problem detecting flaky tests for project %s", j.Project))
+}
@@ -0,0 +1,27 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/mongodb/amboy/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlakyTestDetectionJob(t *testing.T) {
+	assert := assert.New(t)
+
+	factory, err := registry.GetJobFactory(flakyTestDetectionJobName)
+	assert.NoError(err)
+	assert.NotNil(factory)
+
+	j, ok := factory().(*flakyTestDetectionJob)
+	assert.True(ok)
+	assert.NotNil(j)
+
+	jOne := NewFlakyTestDetectionJob("foo", "id")
+	jTwo := NewFlakyTestDetectionJob("foo", "id")
+	jThree := NewFlakyTestDetectionJob("bar", "id")
+	assert.Equal(jOne.ID(), jTwo.ID())
+	assert.Equal(jOne, jTwo)
+	assert.NotEqual(jThree.ID(), jOne.ID())
+}
@@ -0,0 +1,121 @@
+package units
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/repotracker"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const projectTriggerJobName = "project-trigger"
+
+func init() {
+	registry.AddJobType(projectTriggerJobName, func() amboy.Job {
+		return makeProjectTriggerJob()
+	})
+}
+
+type projectTriggerJob struct {
+	DownstreamProject string `bson:"downstream_project" json:"downstream_project" yaml:"downstream_project"`
+	DefinitionID      string `bson:"definition_id" json:"definition_id" yaml:"definition_id"`
+	UpstreamVersion   string `bson:"upstream_version" json:"upstream_version" yaml:"upstream_version"`
+	TriggerType       string `bson:"trigger_type" json:"trigger_type" yaml:"trigger_type"`
+	TriggerID         string `bson:"trigger_id" json:"trigger_id" yaml:"trigger_id"`
+	EventID           string `bson:"event_id" json:"event_id" yaml:"event_id"`
+	job.Base          `bson:"metadata" json:"metadata" yaml:"metadata"`
+}
+
+func makeProjectTriggerJob() *projectTriggerJob {
+	j := &projectTriggerJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    projectTriggerJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewProjectTriggerJob creates a job that creates the version in a
+// downstream project for one of its trigger definitions, which the upstream
+// version, build, or task with the given level and id fired.
+func NewProjectTriggerJob(ref *model.ProjectRef, def *model.TriggerDefinition, upstream *version.Version,
+	triggerType, triggerID, eventID string) amboy.Job {
+
+	j := makeProjectTriggerJob()
+	j.DownstreamProject = ref.Identifier
+	j.DefinitionID = def.ID
+	j.UpstreamVersion = upstream.Id
+	j.TriggerType = triggerType
+	j.TriggerID = triggerID
+	j.EventID = eventID
+
+	j.SetID(fmt.Sprintf("%s.%s.%s.%s", projectTriggerJobName, ref.Identifier, def.ID, eventID))
+	return j
+}
+
+func (j *projectTriggerJob) Run(_ context.Context) {
+	defer j.MarkComplete()
+
+	ref, err := model.FindOneProjectRef(j.DownstreamProject)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding project '%s'", j.DownstreamProject))
+		return
+	}
+	if ref == nil {
+		j.AddError(errors.Errorf("project '%s' not found", j.DownstreamProject))
+		return
+	}
+	var def *model.TriggerDefinition
+	for i := range ref.Triggers {
+		if ref.Triggers[i].ID == j.DefinitionID {
+			def = &ref.Triggers[i]
+			break
+		}
+	}
+	if def == nil {
+		// the trigger was removed since the event fired it
+		return
+	}
+
+	upstream, err := version.FindOne(version.ById(j.UpstreamVersion))
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding version '%s'", j.UpstreamVersion))
+		return
+	}
+	if upstream == nil {
+		j.AddError(errors.Errorf("version '%s' not found", j.UpstreamVersion))
+		return
+	}
+
+	v, err := repotracker.TriggerDownstreamVersion(ref, def, upstream, j.TriggerType, j.TriggerID, j.EventID)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "problem creating version for trigger '%s' in project '%s'", def.ID, ref.Identifier))
+		return
+	}
+	if v == nil {
+		return
+	}
+
+	grip.Info(message.Fields{
+		"job":          j.ID(),
+		"operation":    projectTriggerJobName,
+		"message":      "triggered downstream version",
+		"event_id":     j.EventID,
+		"trigger_type": j.TriggerType,
+		"trigger_id":   j.TriggerID,
+		"project":      v.Identifier,
+		"version":      v.Id,
+	})
+}
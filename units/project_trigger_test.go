@@ -0,0 +1,44 @@
+package units
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/mongodb/amboy/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectTriggerJob(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(model.ProjectRefCollection, version.Collection))
+
+	factory, err := registry.GetJobFactory(projectTriggerJobName)
+	assert.NoError(err)
+	_, ok := factory().(*projectTriggerJob)
+	assert.True(ok)
+
+	ref := &model.ProjectRef{
+		Identifier: "downstream",
+		Enabled:    true,
+		Triggers:   []model.TriggerDefinition{{ID: "on-version", Project: "upstream", Level: model.ProjectTriggerLevelVersion}},
+	}
+	upstream := &version.Version{Id: "v1", Identifier: "upstream"}
+
+	// a trigger only creates one version for each event
+	j := NewProjectTriggerJob(ref, &ref.Triggers[0], upstream, model.ProjectTriggerLevelVersion, "v1", "e1")
+	assert.Equal(j.ID(), NewProjectTriggerJob(ref, &ref.Triggers[0], upstream, model.ProjectTriggerLevelVersion, "v1", "e1").ID())
+
+	j.Run(context.Background())
+	assert.Error(j.Error(), "the downstream project doesn't exist")
+
+	// a trigger that was removed since the event fired it does nothing
+	require.NoError(ref.Insert())
+	j = NewProjectTriggerJob(ref, &model.TriggerDefinition{ID: "removed"}, upstream, model.ProjectTriggerLevelVersion, "v1", "e1")
+	j.Run(context.Background())
+	assert.NoError(j.Error())
+}
@@ -300,6 +300,9 @@ func (j *agentDeployJob) startAgentOnRemote(ctx context.Context, settings *everg
 		fmt.Sprintf("--working_directory='%s'", hostObj.Distro.WorkDir),
 		"--cleanup",
 	}
+	if hostObj.Distro.Provider == evergreen.ProviderNameEc2Spot {
+		agentCmdParts = append(agentCmdParts, fmt.Sprintf("--spot_interruption_url='%s'", cloud.EC2SpotInterruptionURL))
+	}
 
 	// build the command to run on the remote machine
 	remoteCmd := strings.Join(agentCmdParts, " ")
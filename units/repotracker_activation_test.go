@@ -1,10 +1,15 @@
 package units
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/queue"
 	"github.com/mongodb/amboy/registry"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestVersionActivationJob(t *testing.T) {
@@ -29,3 +34,46 @@ func TestVersionActivationJob(t *testing.T) {
 	assert.Equal(jOne, jTwo)
 	assert.NotEqual(jThree.ID(), jOne.ID())
 }
+
+func TestScheduledVersionJob(t *testing.T) {
+	assert := assert.New(t)
+
+	factory, err := registry.GetJobFactory(scheduledVersionJobName)
+	assert.NoError(err)
+	assert.NotNil(factory)
+
+	j, ok := factory().(*scheduledVersionJob)
+	assert.True(ok)
+	assert.NotNil(j)
+
+	fireTime := time.Date(2019, time.January, 2, 0, 0, 0, 0, time.UTC)
+	jOne := NewScheduledVersionJob("foo", "nightly", fireTime)
+	jTwo := NewScheduledVersionJob("foo", "nightly", fireTime)
+	jThree := NewScheduledVersionJob("foo", "nightly", fireTime.AddDate(0, 0, 1))
+	jFour := NewScheduledVersionJob("foo", "weekly", fireTime)
+	assert.Equal(jOne.ID(), jTwo.ID())
+	assert.NotEqual(jThree.ID(), jOne.ID())
+	assert.NotEqual(jFour.ID(), jOne.ID())
+}
+
+func TestPutNewJob(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	q := queue.NewLocalUnordered(1)
+
+	// errors other than duplicate IDs are returned
+	j := job.NewShellJob("true", "")
+	assert.Error(putNewJob(q, j))
+
+	require.NoError(q.Start(ctx))
+	require.NoError(putNewJob(q, j))
+	_, ok := q.Get(j.ID())
+	assert.True(ok)
+
+	// jobs that are already queued are skipped
+	assert.NoError(putNewJob(q, j))
+	assert.Equal(1, q.Stats().Total)
+}
@@ -0,0 +1,72 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/repotracker"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+const scheduledVersionJobName = "scheduled-version"
+
+func init() {
+	registry.AddJobType(scheduledVersionJobName, func() amboy.Job {
+		return makeScheduledVersionJob()
+	})
+}
+
+type scheduledVersionJob struct {
+	Project  string    `bson:"project" json:"project" yaml:"project"`
+	Schedule string    `bson:"schedule" json:"schedule" yaml:"schedule"`
+	FireTime time.Time `bson:"fire_time" json:"fire_time" yaml:"fire_time"`
+	job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+}
+
+func makeScheduledVersionJob() *scheduledVersionJob {
+	j := &scheduledVersionJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    scheduledVersionJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewScheduledVersionJob creates a job that creates the version for a
+// project's version schedule firing at the given time.
+func NewScheduledVersionJob(project, schedule string, fireTime time.Time) amboy.Job {
+	j := makeScheduledVersionJob()
+	j.Project = project
+	j.Schedule = schedule
+	j.FireTime = fireTime
+
+	j.SetID(fmt.Sprintf("%s.%s.%s.%s", scheduledVersionJobName, project, schedule, fireTime.UTC().Format(tsFormat)))
+	return j
+}
+
+func (j *scheduledVersionJob) Run(_ context.Context) {
+	defer j.MarkComplete()
+
+	ref, err := model.FindOneProjectRef(j.Project)
+	if err != nil {
+		j.AddError(errors.WithStack(err))
+		return
+	}
+	if ref == nil {
+		j.AddError(errors.Errorf("project '%s' not found", j.Project))
+		return
+	}
+
+	_, err = repotracker.CreateScheduledVersion(ref, j.Schedule, j.FireTime)
+	j.AddError(errors.Wrapf(err, "problem creating version for schedule '%s' in project '%s'", j.Schedule, j.Project))
+}
@@ -70,6 +70,7 @@ func (j *distroSchedulerJob) Run(ctx context.Context) {
 		DistroID:         j.DistroID,
 		TaskFinder:       settings.Scheduler.TaskFinder,
 		HostAllocator:    settings.Scheduler.HostAllocator,
+		TaskPrioritizer:  settings.Scheduler.TaskPrioritizer,
 		FreeHostFraction: settings.Scheduler.FreeHostFraction,
 	}
 
@@ -0,0 +1,70 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const taskAutoRetryJobName = "task-auto-retry"
+
+func init() {
+	registry.AddJobType(taskAutoRetryJobName, func() amboy.Job {
+		return makeTaskAutoRetryJob()
+	})
+}
+
+type taskAutoRetryJob struct {
+	job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+}
+
+func makeTaskAutoRetryJob() *taskAutoRetryJob {
+	j := &taskAutoRetryJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    taskAutoRetryJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewTaskAutoRetryJob creates a job that restarts the tasks whose retry
+// policy scheduled a restart that is now due.
+func NewTaskAutoRetryJob(id string) amboy.Job {
+	j := makeTaskAutoRetryJob()
+	j.SetID(fmt.Sprintf("%s.%s", taskAutoRetryJobName, id))
+	return j
+}
+
+func (j *taskAutoRetryJob) Run(_ context.Context) {
+	defer j.MarkComplete()
+
+	tasks, err := task.Find(task.ByAutoRetryDue(time.Now()).WithFields(task.IdKey))
+	if err != nil {
+		j.AddError(errors.Wrap(err, "problem finding tasks due for automatic retry"))
+		return
+	}
+
+	for _, t := range tasks {
+		j.AddError(errors.Wrapf(model.AutoRetryTask(t.Id), "problem retrying task '%s'", t.Id))
+	}
+
+	grip.InfoWhen(len(tasks) > 0, message.Fields{
+		"job":       j.ID(),
+		"operation": taskAutoRetryJobName,
+		"num_tasks": len(tasks),
+	})
+}
@@ -0,0 +1,25 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/mongodb/amboy/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskAutoRetryJob(t *testing.T) {
+	assert := assert.New(t)
+
+	factory, err := registry.GetJobFactory(taskAutoRetryJobName)
+	assert.NoError(err)
+	assert.NotNil(factory)
+
+	j, ok := factory().(*taskAutoRetryJob)
+	assert.True(ok)
+	assert.NotNil(j)
+
+	jOne := NewTaskAutoRetryJob("id")
+	jTwo := NewTaskAutoRetryJob("id")
+	assert.Equal(jOne.ID(), jTwo.ID())
+	assert.Equal(jOne, jTwo)
+}
@@ -0,0 +1,204 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CronSchedule is a parsed five field cron specification: minute, hour, day
+// of month, month, and day of week. Each field may be "*", a number, a range
+// ("a-b"), or a comma separated list of these, and numbers and ranges may
+// have a step ("*/15", "0-30/10"). Months and days of the week may also be
+// given by their three letter names. The descriptors @yearly, @annually,
+// @monthly, @weekly, @daily, @midnight and @hourly are also supported.
+type CronSchedule struct {
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+
+	// as in cron, if both the day of the month and the day of the week are
+	// restricted, a day matching either of them matches the schedule
+	domRestricted bool
+	dowRestricted bool
+}
+
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	cronMinute = cronField{name: "minute", min: 0, max: 59}
+	cronHour   = cronField{name: "hour", min: 0, max: 23}
+	cronDOM    = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// both 0 and 7 are Sunday
+	cronDOW = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+
+	cronDescriptors = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+// ParseCron parses a cron specification.
+func ParseCron(spec string) (*CronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@") {
+		expanded, ok := cronDescriptors[strings.ToLower(spec)]
+		if !ok {
+			return nil, errors.Errorf("unrecognized cron descriptor '%s'", spec)
+		}
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, errors.Errorf("cron specification '%s' must have 5 fields, found %d", spec, len(fields))
+	}
+
+	s := &CronSchedule{}
+	var err error
+	if s.minute, _, err = cronMinute.parse(fields[0]); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if s.hour, _, err = cronHour.parse(fields[1]); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if s.dom, s.domRestricted, err = cronDOM.parse(fields[2]); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if s.month, _, err = cronMonth.parse(fields[3]); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if s.dow, s.dowRestricted, err = cronDOW.parse(fields[4]); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+
+	return s, nil
+}
+
+// parse returns the values the field matches as a bitset, and whether the
+// field restricts the values at all.
+func (f cronField) parse(field string) (uint64, bool, error) {
+	var bits uint64
+	restricted := !strings.HasPrefix(field, "*")
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			var err error
+			rangePart = part[:idx]
+			step, err = strconv.Atoi(part[idx+1:])
+			if err != nil || step <= 0 {
+				return 0, false, errors.Errorf("invalid step in %s field '%s'", f.name, part)
+			}
+		}
+
+		var start, end int
+		switch {
+		case rangePart == "*":
+			start, end = f.min, f.max
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if start, err = f.value(bounds[0]); err != nil {
+				return 0, false, errors.WithStack(err)
+			}
+			if end, err = f.value(bounds[1]); err != nil {
+				return 0, false, errors.WithStack(err)
+			}
+			if start > end {
+				return 0, false, errors.Errorf("invalid range in %s field '%s'", f.name, part)
+			}
+		default:
+			var err error
+			if start, err = f.value(rangePart); err != nil {
+				return 0, false, errors.WithStack(err)
+			}
+			end = start
+			if step > 1 {
+				end = f.max
+			}
+		}
+
+		for i := start; i <= end; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+
+	return bits, restricted, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errors.Errorf("invalid value '%s' in %s field", s, f.name)
+	}
+	if v < f.min || v > f.max {
+		return 0, errors.Errorf("%s value %d is not between %d and %d", f.name, v, f.min, f.max)
+	}
+	return v, nil
+}
+
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domRestricted && s.dowRestricted {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+// Next returns the first time after t that matches the schedule, or the zero
+// time if the schedule does not match any time in the next five years (for
+// instance, "0 0 30 2 *").
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
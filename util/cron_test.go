@@ -0,0 +1,77 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, spec := range []string{
+		"* * * * *",
+		"*/15 * * * *",
+		"0 9-17 * * mon-fri",
+		"30 2 1,15 * *",
+		"0 0 * JAN,jul 0",
+		"5/20 * * * 7",
+		"@daily",
+		"@Weekly",
+	} {
+		_, err := ParseCron(spec)
+		assert.NoError(err, spec)
+	}
+
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"* * * foo *",
+		"@sometimes",
+	} {
+		_, err := ParseCron(spec)
+		assert.Error(err, spec)
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	assert := assert.New(t)
+	// a Wednesday
+	start := time.Date(2019, time.January, 2, 10, 7, 30, 0, time.UTC)
+
+	for spec, expected := range map[string]time.Time{
+		"* * * * *":          time.Date(2019, time.January, 2, 10, 8, 0, 0, time.UTC),
+		"*/15 * * * *":       time.Date(2019, time.January, 2, 10, 15, 0, 0, time.UTC),
+		"5/20 * * * *":       time.Date(2019, time.January, 2, 10, 25, 0, 0, time.UTC),
+		"0 * * * *":          time.Date(2019, time.January, 2, 11, 0, 0, 0, time.UTC),
+		"@daily":             time.Date(2019, time.January, 3, 0, 0, 0, 0, time.UTC),
+		"0 9 * * sat,sun":    time.Date(2019, time.January, 5, 9, 0, 0, 0, time.UTC),
+		"@weekly":            time.Date(2019, time.January, 6, 0, 0, 0, 0, time.UTC),
+		"0 0 * * 7":          time.Date(2019, time.January, 6, 0, 0, 0, 0, time.UTC),
+		"@monthly":           time.Date(2019, time.February, 1, 0, 0, 0, 0, time.UTC),
+		"0 0 29 2 *":         time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC),
+		"0 12 15 * fri":      time.Date(2019, time.January, 4, 12, 0, 0, 0, time.UTC),
+		"30 23 31 dec *":     time.Date(2019, time.December, 31, 23, 30, 0, 0, time.UTC),
+		"0 8-10 2 jan *":     time.Date(2020, time.January, 2, 8, 0, 0, 0, time.UTC),
+		"10-20/5 10 2 1 wed": time.Date(2019, time.January, 2, 10, 10, 0, 0, time.UTC),
+	} {
+		schedule, err := ParseCron(spec)
+		require.NoError(t, err, spec)
+		assert.Equal(expected, schedule.Next(start), spec)
+	}
+
+	schedule, err := ParseCron("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(schedule.Next(start).IsZero())
+}
@@ -0,0 +1,65 @@
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// EncryptString encrypts the plaintext with AES-GCM using the given key,
+// which must be 16, 24, or 32 bytes long. The result is the base64 encoding
+// of a random nonce followed by the ciphertext, so encrypting the same
+// plaintext twice gives different results. The additional data is
+// authenticated but not encrypted, and must be given again to decrypt.
+func EncryptString(key []byte, plaintext string, additionalData []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errors.Wrap(err, "problem generating nonce")
+	}
+
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(plaintext), additionalData)), nil
+}
+
+// DecryptString decrypts a value produced by EncryptString with the same key
+// and additional data.
+func DecryptString(key []byte, ciphertext string, additionalData []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", errors.Wrap(err, "ciphertext is not valid base64")
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("ciphertext is too short")
+	}
+
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], additionalData)
+	if err != nil {
+		return "", errors.Wrap(err, "problem decrypting ciphertext")
+	}
+	return string(plaintext), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid encryption key")
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem creating cipher")
+	}
+	return gcm, nil
+}
@@ -0,0 +1,40 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptString(t *testing.T) {
+	assert := assert.New(t)
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	aad := []byte("mci:password")
+
+	first, err := EncryptString(key, "hunter2", aad)
+	assert.NoError(err)
+	second, err := EncryptString(key, "hunter2", aad)
+	assert.NoError(err)
+	assert.NotEqual(first, second)
+	assert.NotContains(first, "hunter2")
+
+	plaintext, err := DecryptString(key, first, aad)
+	assert.NoError(err)
+	assert.Equal("hunter2", plaintext)
+
+	// the ciphertext is bound to its additional data
+	_, err = DecryptString(key, first, []byte("other:password"))
+	assert.Error(err)
+	_, err = DecryptString(key, first, nil)
+	assert.Error(err)
+
+	_, err = DecryptString([]byte("fedcba9876543210fedcba9876543210"), first, aad)
+	assert.Error(err)
+	_, err = DecryptString(key, "not base64!", aad)
+	assert.Error(err)
+	_, err = DecryptString(key, "c2hvcnQ=", aad)
+	assert.Error(err)
+	_, err = EncryptString([]byte("short"), "hunter2", aad)
+	assert.Error(err)
+}
@@ -21,6 +21,8 @@ var distroSyntaxValidators = []distroValidator{
 	ensureValidExpansions,
 	ensureStaticHostsAreNotSpawnable,
 	ensureValidContainerPool,
+	ensureValidResourceLimits,
+	ensureValidTaskPrioritizer,
 }
 
 // CheckDistro checks if the distro configuration syntax is valid. Returns
@@ -190,3 +192,30 @@ func ensureValidContainerPool(ctx context.Context, d *distro.Distro, s *evergree
 	}
 	return nil
 }
+
+// ensureValidResourceLimits checks that none of the distro's resource limits
+// are negative.
+func ensureValidResourceLimits(ctx context.Context, d *distro.Distro, s *evergreen.Settings) []ValidationError {
+	if d.ResourceLimits == nil {
+		return nil
+	}
+	if err := d.ResourceLimits.Validate(); err != nil {
+		return []ValidationError{{Error, "invalid resource limits: " + err.Error()}}
+	}
+	return nil
+}
+
+// ensureValidTaskPrioritizer checks that the distro's task prioritizer, if it
+// overrides the scheduler's, is one the scheduler supports.
+func ensureValidTaskPrioritizer(ctx context.Context, d *distro.Distro, s *evergreen.Settings) []ValidationError {
+	if d.TaskPrioritizer == "" || util.StringSliceContains(evergreen.ValidTaskPrioritizers, d.TaskPrioritizer) {
+		return nil
+	}
+	return []ValidationError{
+		{
+			Message: fmt.Sprintf("invalid task prioritizer '%s' for distro '%s': must be one of %s",
+				d.TaskPrioritizer, d.Id, evergreen.ValidTaskPrioritizers),
+			Level: Error,
+		},
+	}
+}
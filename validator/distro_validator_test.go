@@ -264,6 +264,28 @@ func TestEnsureNonZeroID(t *testing.T) {
 	assert.Nil(ensureHasNonZeroID(ctx, &distro.Distro{Id: " "}, conf))
 }
 
+func TestEnsureValidResourceLimits(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	assert.Nil(ensureValidResourceLimits(ctx, &distro.Distro{Id: "foo"}, conf))
+	assert.Nil(ensureValidResourceLimits(ctx, &distro.Distro{Id: "foo", ResourceLimits: &distro.ResourceLimits{MemoryMB: 512}}, conf))
+	assert.NotNil(ensureValidResourceLimits(ctx, &distro.Distro{Id: "foo", ResourceLimits: &distro.ResourceLimits{CPUs: -1}}, conf))
+}
+
+func TestEnsureValidTaskPrioritizer(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	assert.Nil(ensureValidTaskPrioritizer(ctx, &distro.Distro{Id: "foo"}, conf))
+	assert.Nil(ensureValidTaskPrioritizer(ctx, &distro.Distro{Id: "foo", TaskPrioritizer: evergreen.TaskPrioritizerFairShare}, conf))
+	assert.NotNil(ensureValidTaskPrioritizer(ctx, &distro.Distro{Id: "foo", TaskPrioritizer: "fifo"}, conf))
+}
+
 func TestEnsureValidContainerPool(t *testing.T) {
 	assert := assert.New(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -58,6 +58,9 @@ var projectSyntaxValidators = []projectValidator{
 	validateTaskGroups,
 	validateGenerateTasks,
 	validateCreateHosts,
+	validateRetryPolicies,
+	validateResourceLimits,
+	validateParameters,
 }
 
 // Functions used to validate the semantics of a project configuration file.
@@ -693,6 +696,68 @@ func validateProjectTaskNames(project *model.Project) []ValidationError {
 	return errs
 }
 
+// validateRetryPolicies ensures that the retry policies of the project and
+// its tasks are well formed.
+func validateRetryPolicies(project *model.Project) []ValidationError {
+	errs := []ValidationError{}
+	if project.Retry != nil {
+		if err := project.Retry.Validate(); err != nil {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("invalid retry policy for project '%s': %s", project.Identifier, err.Error()),
+			})
+		}
+	}
+	for _, task := range project.Tasks {
+		if task.Retry == nil {
+			continue
+		}
+		if err := task.Retry.Validate(); err != nil {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("invalid retry policy for task '%s': %s", task.Name, err.Error()),
+			})
+		}
+	}
+	return errs
+}
+
+// validateResourceLimits ensures that the resource limits of the project's
+// tasks are not negative.
+func validateResourceLimits(project *model.Project) []ValidationError {
+	errs := []ValidationError{}
+	for _, task := range project.Tasks {
+		if task.ResourceLimits == nil {
+			continue
+		}
+		if err := task.ResourceLimits.Validate(); err != nil {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("invalid resource limits for task '%s': %s", task.Name, err.Error()),
+			})
+		}
+	}
+	return errs
+}
+
+// validateParameters ensures that the project's parameter definitions are
+// well formed and have unique names.
+func validateParameters(project *model.Project) []ValidationError {
+	errs := []ValidationError{}
+	names := map[string]bool{}
+	for _, def := range project.Parameters {
+		if err := def.Validate(); err != nil {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("invalid parameter definition: %s", err.Error()),
+			})
+		}
+		if names[def.Name] {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("parameter '%s' is defined more than once", def.Name),
+			})
+		}
+		names[def.Name] = true
+	}
+	return errs
+}
+
 // validateProjectTaskIdsAndTags ensures that task tags and ids only contain valid characters
 func validateProjectTaskIdsAndTags(project *model.Project) []ValidationError {
 	errs := []ValidationError{}
@@ -1947,3 +1947,54 @@ func TestValidateCreateHosts(t *testing.T) {
 	errs = validateCreateHosts(&p)
 	assert.Len(errs, 1)
 }
+
+func TestValidateRetryPolicies(t *testing.T) {
+	assert := assert.New(t)
+
+	p := &model.Project{
+		Identifier: "proj",
+		Retry:      &model.RetryPolicy{MaxAttempts: 2},
+		Tasks: []model.ProjectTask{
+			{Name: "compile"},
+			{Name: "test", Retry: &model.RetryPolicy{MaxAttempts: 3, On: []string{model.RetryOnTimeout}}},
+		},
+	}
+	assert.Len(validateRetryPolicies(p), 0)
+
+	p.Retry.BackoffSecs = -1
+	p.Tasks[1].Retry.On = []string{"flaky"}
+	assert.Len(validateRetryPolicies(p), 2)
+}
+
+func TestValidateResourceLimits(t *testing.T) {
+	assert := assert.New(t)
+
+	p := &model.Project{
+		Tasks: []model.ProjectTask{
+			{Name: "compile"},
+			{Name: "test", ResourceLimits: &distro.ResourceLimits{MemoryMB: 1024, CPUs: 2}},
+		},
+	}
+	assert.Len(validateResourceLimits(p), 0)
+
+	p.Tasks[1].ResourceLimits.Processes = -1
+	assert.Len(validateResourceLimits(p), 1)
+}
+
+func TestValidateParameters(t *testing.T) {
+	assert := assert.New(t)
+
+	p := &model.Project{
+		Parameters: []model.ParameterDefinition{
+			{Name: "release"},
+			{Name: "channel", Default: "beta", AllowedValues: []string{"beta", "stable"}},
+		},
+	}
+	assert.Len(validateParameters(p), 0)
+
+	p.Parameters = append(p.Parameters,
+		model.ParameterDefinition{Name: "release"},
+		model.ParameterDefinition{Name: "mode", Default: "fast", AllowedValues: []string{"slow"}},
+	)
+	assert.Len(validateParameters(p), 2)
+}